@@ -0,0 +1,338 @@
+package apple
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const (
+	AppAttestEnvironmentDevelopment = "development"
+	AppAttestEnvironmentProduction  = "production"
+
+	appAttestFormat = "apple-appattest"
+)
+
+var (
+	appAttestAAGUIDDevelopment = []byte("appattestdevelop")
+	appAttestAAGUIDProduction  = append([]byte("appattest"), 0, 0, 0, 0, 0, 0, 0)
+
+	appAttestNonceExtensionOID = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 8, 2}
+)
+
+// AppAttest verifies App Attest attestations of new keys and assertions signed by previously attested keys. See
+// https://developer.apple.com/documentation/devicecheck/validating_apps_that_connect_to_your_server
+type AppAttest interface {
+	VerifyAttestation(keyID string, attestation []byte, challenge []byte) (*AppAttestKey, error)
+	VerifyAssertion(publicKey []byte, assertion []byte, clientData []byte, previousCounter uint32) (uint32, error)
+}
+
+// AppAttestConfig requires the app id, which is the team id and bundle id separated by a period, and the Apple App
+// Attestation Root CA certificate in PEM format. Attestations are verified against the production environment unless
+// the development environment is explicitly enabled.
+type AppAttestConfig struct {
+	AppID                     string `envconfig:"TIDEPOOL_APPLE_APP_ATTEST_APP_ID"`
+	RootCertificate           string `envconfig:"TIDEPOOL_APPLE_APP_ATTEST_ROOT_CERTIFICATE"`
+	UseDevelopmentEnvironment bool   `envconfig:"TIDEPOOL_APPLE_APP_ATTEST_USE_DEVELOPMENT" default:"false"`
+}
+
+func NewAppAttestConfig() *AppAttestConfig {
+	return &AppAttestConfig{}
+}
+
+func (c *AppAttestConfig) Load() error {
+	return envconfig.Process("", c)
+}
+
+func (c *AppAttestConfig) IsConfigured() bool {
+	return c.AppID != ""
+}
+
+// AppAttestKey is the result of a verified attestation. The public key is PKIX DER encoded.
+type AppAttestKey struct {
+	ID          string
+	PublicKey   []byte
+	Receipt     []byte
+	Environment string
+}
+
+type appAttest struct {
+	appIDHash   []byte
+	roots       *x509.CertPool
+	environment string
+	now         func() time.Time
+}
+
+func NewAppAttest(cfg *AppAttestConfig) (AppAttest, error) {
+	return NewAppAttestWithTime(cfg, time.Now)
+}
+
+// NewAppAttestWithTime verifies certificates at the time returned by now, for example, to verify recorded fixtures
+func NewAppAttestWithTime(cfg *AppAttestConfig, now func() time.Time) (AppAttest, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	}
+	if cfg.AppID == "" {
+		return nil, errors.New("app id is missing")
+	}
+	if now == nil {
+		return nil, errors.New("now is missing")
+	}
+
+	block, _ := pem.Decode([]byte(cfg.RootCertificate))
+	if block == nil {
+		return nil, errors.New("root certificate is invalid")
+	}
+	root, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.New("root certificate is invalid")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	environment := AppAttestEnvironmentProduction
+	if cfg.UseDevelopmentEnvironment {
+		environment = AppAttestEnvironmentDevelopment
+	}
+
+	appIDHash := sha256.Sum256([]byte(cfg.AppID))
+	return &appAttest{
+		appIDHash:   appIDHash[:],
+		roots:       roots,
+		environment: environment,
+		now:         now,
+	}, nil
+}
+
+func (a *appAttest) VerifyAttestation(keyID string, attestation []byte, challenge []byte) (*AppAttestKey, error) {
+	keyIDBytes, err := base64.StdEncoding.DecodeString(keyID)
+	if err != nil || len(keyIDBytes) != sha256.Size {
+		return nil, errors.New("key id is invalid")
+	}
+	if len(challenge) == 0 {
+		return nil, errors.New("challenge is missing")
+	}
+
+	decoded, err := decodeCBOR(attestation)
+	if err != nil {
+		return nil, errors.New("attestation is invalid")
+	}
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("attestation is invalid")
+	}
+	if format, _ := object["fmt"].(string); format != appAttestFormat {
+		return nil, errors.New("attestation format is invalid")
+	}
+	statement, ok := object["attStmt"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("attestation statement is invalid")
+	}
+	authData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("attestation authenticator data is invalid")
+	}
+	receipt, _ := statement["receipt"].([]byte)
+
+	certificates, err := parseAppAttestCertificates(statement["x5c"])
+	if err != nil {
+		return nil, err
+	}
+	credential := certificates[0]
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	if _, err = credential.Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		CurrentTime:   a.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, errors.New("attestation certificate is not trusted")
+	}
+
+	clientDataHash := sha256.Sum256(challenge)
+	nonce := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	credentialNonce, err := appAttestCertificateNonce(credential)
+	if err != nil {
+		return nil, err
+	} else if !bytes.Equal(credentialNonce, nonce[:]) {
+		return nil, errors.New("attestation nonce does not match")
+	}
+
+	publicKey, ok := credential.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P256() {
+		return nil, errors.New("attestation public key is invalid")
+	}
+	publicKeyHash := sha256.Sum256(elliptic.Marshal(publicKey.Curve, publicKey.X, publicKey.Y))
+	if !bytes.Equal(publicKeyHash[:], keyIDBytes) {
+		return nil, errors.New("attestation public key does not match key id")
+	}
+
+	data, err := parseAppAttestAuthenticatorData(authData, true)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(data.rpIDHash, a.appIDHash) {
+		return nil, errors.New("attestation app id does not match")
+	}
+	if data.counter != 0 {
+		return nil, errors.New("attestation counter is invalid")
+	}
+	if !bytes.Equal(data.aaguid, a.aaguid()) {
+		return nil, errors.New("attestation environment does not match")
+	}
+	if !bytes.Equal(data.credentialID, keyIDBytes) {
+		return nil, errors.New("attestation credential id does not match key id")
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, errors.New("attestation public key is invalid")
+	}
+
+	return &AppAttestKey{
+		ID:          keyID,
+		PublicKey:   publicKeyBytes,
+		Receipt:     receipt,
+		Environment: a.environment,
+	}, nil
+}
+
+// VerifyAssertion returns the counter of the assertion, which must be greater than the previous counter
+func (a *appAttest) VerifyAssertion(publicKey []byte, assertion []byte, clientData []byte, previousCounter uint32) (uint32, error) {
+	parsedPublicKey, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return 0, errors.New("public key is invalid")
+	}
+	ecdsaPublicKey, ok := parsedPublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return 0, errors.New("public key is invalid")
+	}
+	if len(clientData) == 0 {
+		return 0, errors.New("client data is missing")
+	}
+
+	decoded, err := decodeCBOR(assertion)
+	if err != nil {
+		return 0, errors.New("assertion is invalid")
+	}
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return 0, errors.New("assertion is invalid")
+	}
+	signature, ok := object["signature"].([]byte)
+	if !ok {
+		return 0, errors.New("assertion signature is invalid")
+	}
+	authData, ok := object["authenticatorData"].([]byte)
+	if !ok {
+		return 0, errors.New("assertion authenticator data is invalid")
+	}
+
+	clientDataHash := sha256.Sum256(clientData)
+	nonce := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	digest := sha256.Sum256(nonce[:])
+	if !ecdsa.VerifyASN1(ecdsaPublicKey, digest[:], signature) {
+		return 0, errors.New("assertion signature does not match")
+	}
+
+	data, err := parseAppAttestAuthenticatorData(authData, false)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(data.rpIDHash, a.appIDHash) {
+		return 0, errors.New("assertion app id does not match")
+	}
+	if data.counter <= previousCounter {
+		return 0, errors.New("assertion counter is invalid")
+	}
+
+	return data.counter, nil
+}
+
+func (a *appAttest) aaguid() []byte {
+	if a.environment == AppAttestEnvironmentDevelopment {
+		return appAttestAAGUIDDevelopment
+	}
+	return appAttestAAGUIDProduction
+}
+
+func parseAppAttestCertificates(value interface{}) ([]*x509.Certificate, error) {
+	array, ok := value.([]interface{})
+	if !ok || len(array) == 0 {
+		return nil, errors.New("attestation certificates are invalid")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(array))
+	for _, element := range array {
+		bites, ok := element.([]byte)
+		if !ok {
+			return nil, errors.New("attestation certificates are invalid")
+		}
+		certificate, err := x509.ParseCertificate(bites)
+		if err != nil {
+			return nil, errors.New("attestation certificates are invalid")
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, nil
+}
+
+func appAttestCertificateNonce(certificate *x509.Certificate) ([]byte, error) {
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(appAttestNonceExtensionOID) {
+			var value struct {
+				Nonce []byte `asn1:"tag:1,explicit"`
+			}
+			if _, err := asn1.Unmarshal(extension.Value, &value); err != nil {
+				return nil, errors.New("attestation nonce is invalid")
+			}
+			return value.Nonce, nil
+		}
+	}
+	return nil, errors.New("attestation nonce is missing")
+}
+
+type appAttestAuthenticatorData struct {
+	rpIDHash     []byte
+	counter      uint32
+	aaguid       []byte
+	credentialID []byte
+}
+
+// parseAppAttestAuthenticatorData parses the WebAuthn authenticator data; the attested credential data is only
+// present in an attestation
+func parseAppAttestAuthenticatorData(authData []byte, attested bool) (*appAttestAuthenticatorData, error) {
+	if len(authData) < 37 {
+		return nil, errors.New("authenticator data is invalid")
+	}
+
+	data := &appAttestAuthenticatorData{
+		rpIDHash: authData[0:32],
+		counter:  binary.BigEndian.Uint32(authData[33:37]),
+	}
+	if attested {
+		if len(authData) < 55 {
+			return nil, errors.New("authenticator data is invalid")
+		}
+		credentialIDLength := int(binary.BigEndian.Uint16(authData[53:55]))
+		if len(authData) < 55+credentialIDLength {
+			return nil, errors.New("authenticator data is invalid")
+		}
+		data.aaguid = authData[37:53]
+		data.credentialID = authData[55 : 55+credentialIDLength]
+	}
+	return data, nil
+}
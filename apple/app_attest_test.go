@@ -0,0 +1,209 @@
+package apple_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/apple"
+	"github.com/tidepool-org/platform/apple/test"
+)
+
+var _ = Describe("AppAttest", func() {
+	var fixture *test.AppAttest
+	var challenge []byte
+
+	BeforeEach(func() {
+		fixture = test.NewAppAttest()
+		challenge = []byte("challenge")
+	})
+
+	Context("AppAttestConfig", func() {
+		It("is not configured without an app id", func() {
+			Expect(apple.NewAppAttestConfig().IsConfigured()).To(BeFalse())
+		})
+
+		It("is configured with an app id", func() {
+			Expect(fixture.Config().IsConfigured()).To(BeTrue())
+		})
+
+		It("uses the production environment by default", func() {
+			cfg := apple.NewAppAttestConfig()
+			Expect(cfg.Load()).To(Succeed())
+			Expect(cfg.UseDevelopmentEnvironment).To(BeFalse())
+		})
+	})
+
+	Context("NewAppAttest", func() {
+		It("returns an error if the config is missing", func() {
+			appAttest, err := apple.NewAppAttest(nil)
+			Expect(err).To(MatchError("config is missing"))
+			Expect(appAttest).To(BeNil())
+		})
+
+		It("returns an error if the app id is missing", func() {
+			cfg := fixture.Config()
+			cfg.AppID = ""
+			appAttest, err := apple.NewAppAttest(cfg)
+			Expect(err).To(MatchError("app id is missing"))
+			Expect(appAttest).To(BeNil())
+		})
+
+		It("returns an error if the root certificate is invalid", func() {
+			cfg := fixture.Config()
+			cfg.RootCertificate = "invalid"
+			appAttest, err := apple.NewAppAttest(cfg)
+			Expect(err).To(MatchError("root certificate is invalid"))
+			Expect(appAttest).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(apple.NewAppAttest(fixture.Config())).ToNot(BeNil())
+		})
+	})
+
+	Context("with app attest", func() {
+		var appAttest apple.AppAttest
+
+		BeforeEach(func() {
+			var err error
+			appAttest, err = apple.NewAppAttest(fixture.Config())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("VerifyAttestation", func() {
+			It("returns the key for a valid attestation", func() {
+				key, err := appAttest.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key).To(Equal(&apple.AppAttestKey{
+					ID:          fixture.KeyID,
+					PublicKey:   fixture.PublicKey(),
+					Receipt:     []byte("receipt"),
+					Environment: apple.AppAttestEnvironmentDevelopment,
+				}))
+			})
+
+			It("returns an error if the key id is invalid", func() {
+				_, err := appAttest.VerifyAttestation("invalid", fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("key id is invalid"))
+			})
+
+			It("returns an error if the key id does not match", func() {
+				keyID := sha256.Sum256([]byte("other"))
+				_, err := appAttest.VerifyAttestation(base64.StdEncoding.EncodeToString(keyID[:]), fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation public key does not match key id"))
+			})
+
+			It("returns an error if the attestation is not cbor", func() {
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, []byte{0x5f}, challenge)
+				Expect(err).To(MatchError("attestation is invalid"))
+			})
+
+			It("returns an error if the attestation is truncated", func() {
+				attestation := fixture.NewAttestation(challenge)
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, attestation[:len(attestation)-1], challenge)
+				Expect(err).To(MatchError("attestation is invalid"))
+			})
+
+			It("returns an error if the attestation format is invalid", func() {
+				attestation := test.EncodeCBOR(map[string]interface{}{"fmt": "packed"})
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, attestation, challenge)
+				Expect(err).To(MatchError("attestation format is invalid"))
+			})
+
+			It("returns an error if the challenge does not match", func() {
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), []byte("other"))
+				Expect(err).To(MatchError("attestation nonce does not match"))
+			})
+
+			It("returns an error if the certificate chain is not trusted", func() {
+				other, err := apple.NewAppAttest(test.NewAppAttest().Config())
+				Expect(err).ToNot(HaveOccurred())
+				_, err = other.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation certificate is not trusted"))
+			})
+
+			It("returns an error if the certificate chain has expired", func() {
+				expired, err := apple.NewAppAttestWithTime(fixture.Config(), func() time.Time { return time.Now().Add(48 * time.Hour) })
+				Expect(err).ToNot(HaveOccurred())
+				_, err = expired.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation certificate is not trusted"))
+			})
+
+			It("returns an error if the app id does not match", func() {
+				fixture.AppID = "TEAMID1234.org.tidepool.other"
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation app id does not match"))
+			})
+
+			It("returns an error if the counter is not zero", func() {
+				fixture.Counter = 1
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation counter is invalid"))
+			})
+
+			It("returns an error if the environment does not match", func() {
+				fixture.AAGUID = test.AppAttestAAGUIDProduction
+				_, err := appAttest.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).To(MatchError("attestation environment does not match"))
+			})
+
+			It("returns the key for a valid production attestation", func() {
+				cfg := fixture.Config()
+				cfg.UseDevelopmentEnvironment = false
+				production, err := apple.NewAppAttest(cfg)
+				Expect(err).ToNot(HaveOccurred())
+				fixture.AAGUID = test.AppAttestAAGUIDProduction
+				key, err := production.VerifyAttestation(fixture.KeyID, fixture.NewAttestation(challenge), challenge)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key.Environment).To(Equal(apple.AppAttestEnvironmentProduction))
+			})
+		})
+
+		Context("VerifyAssertion", func() {
+			var clientData []byte
+
+			BeforeEach(func() {
+				clientData = []byte(`{"data":"value"}`)
+			})
+
+			It("returns the counter for a valid assertion", func() {
+				Expect(appAttest.VerifyAssertion(fixture.PublicKey(), fixture.NewAssertion(clientData, 2), clientData, 1)).To(Equal(uint32(2)))
+			})
+
+			It("returns an error if the public key is invalid", func() {
+				_, err := appAttest.VerifyAssertion([]byte("invalid"), fixture.NewAssertion(clientData, 2), clientData, 1)
+				Expect(err).To(MatchError("public key is invalid"))
+			})
+
+			It("returns an error if the assertion is not cbor", func() {
+				_, err := appAttest.VerifyAssertion(fixture.PublicKey(), []byte("invalid"), clientData, 1)
+				Expect(err).To(MatchError("assertion is invalid"))
+			})
+
+			It("returns an error if the client data does not match", func() {
+				_, err := appAttest.VerifyAssertion(fixture.PublicKey(), fixture.NewAssertion(clientData, 2), []byte("other"), 1)
+				Expect(err).To(MatchError("assertion signature does not match"))
+			})
+
+			It("returns an error if signed by another key", func() {
+				_, err := appAttest.VerifyAssertion(test.NewAppAttest().PublicKey(), fixture.NewAssertion(clientData, 2), clientData, 1)
+				Expect(err).To(MatchError("assertion signature does not match"))
+			})
+
+			It("returns an error if the app id does not match", func() {
+				fixture.AppID = "TEAMID1234.org.tidepool.other"
+				_, err := appAttest.VerifyAssertion(fixture.PublicKey(), fixture.NewAssertion(clientData, 2), clientData, 1)
+				Expect(err).To(MatchError("assertion app id does not match"))
+			})
+
+			It("returns an error if the counter is replayed", func() {
+				_, err := appAttest.VerifyAssertion(fixture.PublicKey(), fixture.NewAssertion(clientData, 2), clientData, 2)
+				Expect(err).To(MatchError("assertion counter is invalid"))
+			})
+		})
+	})
+})
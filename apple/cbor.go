@@ -0,0 +1,159 @@
+package apple
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// decodeCBOR decodes the subset of CBOR (RFC 8949) used by App Attest: definite length unsigned and negative integers,
+// byte and text strings, arrays, maps with text string keys, and the simple values false, true, and null.
+func decodeCBOR(data []byte) (interface{}, error) {
+	decoder := &cborDecoder{data: data}
+	value, err := decoder.decode(0)
+	if err != nil {
+		return nil, err
+	} else if decoder.offset != len(data) {
+		return nil, errors.New("cbor has trailing data")
+	}
+	return value, nil
+}
+
+const cborDepthMaximum = 16
+
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+func (c *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > cborDepthMaximum {
+		return nil, errors.New("cbor is nested too deeply")
+	}
+
+	initial, err := c.read(1)
+	if err != nil {
+		return nil, err
+	}
+	majorType := initial[0] >> 5
+	additional := initial[0] & 0x1f
+
+	if majorType == 7 {
+		switch additional {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cbor simple value %d is not supported", additional)
+		}
+	}
+
+	argument, err := c.argument(additional)
+	if err != nil {
+		return nil, err
+	}
+
+	switch majorType {
+	case 0:
+		return argument, nil
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, errors.New("cbor negative integer is out of range")
+		}
+		return -1 - int64(argument), nil
+	case 2:
+		bites, err := c.read(argument)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, bites...), nil
+	case 3:
+		bites, err := c.read(argument)
+		if err != nil {
+			return nil, err
+		}
+		return string(bites), nil
+	case 4:
+		if argument > uint64(len(c.data)) {
+			return nil, errors.New("cbor array length is invalid")
+		}
+		array := make([]interface{}, 0, argument)
+		for index := uint64(0); index < argument; index++ {
+			value, err := c.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	case 5:
+		if argument > uint64(len(c.data)) {
+			return nil, errors.New("cbor map length is invalid")
+		}
+		object := make(map[string]interface{}, argument)
+		for index := uint64(0); index < argument; index++ {
+			key, err := c.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, errors.New("cbor map key is not a text string")
+			}
+			value, err := c.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			object[keyString] = value
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("cbor major type %d is not supported", majorType)
+	}
+}
+
+func (c *cborDecoder) argument(additional byte) (uint64, error) {
+	switch {
+	case additional < 24:
+		return uint64(additional), nil
+	case additional == 24:
+		bites, err := c.read(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(bites[0]), nil
+	case additional == 25:
+		bites, err := c.read(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(bites)), nil
+	case additional == 26:
+		bites, err := c.read(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(bites)), nil
+	case additional == 27:
+		bites, err := c.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(bites), nil
+	default:
+		return 0, errors.New("cbor indefinite length is not supported")
+	}
+}
+
+func (c *cborDecoder) read(length uint64) ([]byte, error) {
+	if length > uint64(len(c.data)-c.offset) {
+		return nil, errors.New("cbor is truncated")
+	}
+	bites := c.data[c.offset : c.offset+int(length)]
+	c.offset += int(length)
+	return bites, nil
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/tidepool-org/platform/apple"
+)
+
+const AppAttestAppID = "TEAMID1234.org.tidepool.app"
+
+var (
+	AppAttestAAGUIDDevelopment = []byte("appattestdevelop")
+	AppAttestAAGUIDProduction  = append([]byte("appattest"), 0, 0, 0, 0, 0, 0, 0)
+)
+
+// AppAttest generates attestations and assertions signed by a certificate chain to its own root, in place of the
+// Apple App Attestation Root CA. The app id, aaguid, and counter used in generated attestations may be changed to
+// generate invalid attestations.
+type AppAttest struct {
+	AppID           string
+	AAGUID          []byte
+	Counter         uint32
+	RootCertificate string
+	KeyID           string
+	PrivateKey      *ecdsa.PrivateKey
+
+	intermediateCertificate *x509.Certificate
+	intermediatePrivateKey  *ecdsa.PrivateKey
+}
+
+func NewAppAttest() *AppAttest {
+	rootPrivateKey := newPrivateKey()
+	rootCertificate := newCertificate(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test App Attestation Root CA"},
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, &rootPrivateKey.PublicKey, rootPrivateKey)
+
+	intermediatePrivateKey := newPrivateKey()
+	intermediateCertificate := newCertificate(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test App Attestation CA 1"},
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, rootCertificate, &intermediatePrivateKey.PublicKey, rootPrivateKey)
+
+	privateKey := newPrivateKey()
+	keyID := sha256.Sum256(elliptic.Marshal(privateKey.Curve, privateKey.X, privateKey.Y))
+
+	return &AppAttest{
+		AppID:                   AppAttestAppID,
+		AAGUID:                  AppAttestAAGUIDDevelopment,
+		RootCertificate:         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootCertificate.Raw})),
+		KeyID:                   base64.StdEncoding.EncodeToString(keyID[:]),
+		PrivateKey:              privateKey,
+		intermediateCertificate: intermediateCertificate,
+		intermediatePrivateKey:  intermediatePrivateKey,
+	}
+}
+
+func (a *AppAttest) Config() *apple.AppAttestConfig {
+	return &apple.AppAttestConfig{
+		AppID:                     AppAttestAppID,
+		RootCertificate:           a.RootCertificate,
+		UseDevelopmentEnvironment: true,
+	}
+}
+
+func (a *AppAttest) NewAttestation(challenge []byte) []byte {
+	keyID, err := base64.StdEncoding.DecodeString(a.KeyID)
+	if err != nil {
+		panic(err)
+	}
+
+	authData := a.authenticatorData(a.Counter)
+	authData = append(authData, a.AAGUID...)
+	authData = append(authData, byte(len(keyID)>>8), byte(len(keyID)))
+	authData = append(authData, keyID...)
+
+	clientDataHash := sha256.Sum256(challenge)
+	nonce := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	extensionValue, err := asn1.Marshal(struct {
+		Nonce []byte `asn1:"tag:1,explicit"`
+	}{Nonce: nonce[:]})
+	if err != nil {
+		panic(err)
+	}
+
+	credentialCertificate := newCertificate(&x509.Certificate{
+		Subject:         pkix.Name{CommonName: a.KeyID},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtraExtensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 8, 2}, Value: extensionValue}},
+	}, a.intermediateCertificate, &a.PrivateKey.PublicKey, a.intermediatePrivateKey)
+
+	return EncodeCBOR(map[string]interface{}{
+		"fmt": "apple-appattest",
+		"attStmt": map[string]interface{}{
+			"x5c":     []interface{}{credentialCertificate.Raw, a.intermediateCertificate.Raw},
+			"receipt": []byte("receipt"),
+		},
+		"authData": authData,
+	})
+}
+
+func (a *AppAttest) NewAssertion(clientData []byte, counter uint32) []byte {
+	authData := a.authenticatorData(counter)
+
+	clientDataHash := sha256.Sum256(clientData)
+	nonce := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	digest := sha256.Sum256(nonce[:])
+	signature, err := ecdsa.SignASN1(rand.Reader, a.PrivateKey, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return EncodeCBOR(map[string]interface{}{
+		"signature":         signature,
+		"authenticatorData": authData,
+	})
+}
+
+func (a *AppAttest) PublicKey() []byte {
+	publicKey, err := x509.MarshalPKIXPublicKey(&a.PrivateKey.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return publicKey
+}
+
+func (a *AppAttest) authenticatorData(counter uint32) []byte {
+	appIDHash := sha256.Sum256([]byte(a.AppID))
+	authData := append([]byte{}, appIDHash[:]...)
+	authData = append(authData, 0x40)
+	authData = append(authData, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:37], counter)
+	return authData
+}
+
+// EncodeCBOR encodes unsigned integers, byte and text strings, arrays, and maps with text string keys
+func EncodeCBOR(value interface{}) []byte {
+	switch value := value.(type) {
+	case uint64:
+		return encodeCBORHeader(0, value)
+	case []byte:
+		return append(encodeCBORHeader(2, uint64(len(value))), value...)
+	case string:
+		return append(encodeCBORHeader(3, uint64(len(value))), value...)
+	case []interface{}:
+		bites := encodeCBORHeader(4, uint64(len(value)))
+		for _, element := range value {
+			bites = append(bites, EncodeCBOR(element)...)
+		}
+		return bites
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		bites := encodeCBORHeader(5, uint64(len(value)))
+		for _, key := range keys {
+			bites = append(bites, EncodeCBOR(key)...)
+			bites = append(bites, EncodeCBOR(value[key])...)
+		}
+		return bites
+	default:
+		panic("cbor type is not supported")
+	}
+}
+
+func encodeCBORHeader(majorType byte, argument uint64) []byte {
+	switch {
+	case argument < 24:
+		return []byte{majorType<<5 | byte(argument)}
+	case argument <= 0xff:
+		return []byte{majorType<<5 | 24, byte(argument)}
+	case argument <= 0xffff:
+		bites := []byte{majorType<<5 | 25, 0, 0}
+		binary.BigEndian.PutUint16(bites[1:], uint16(argument))
+		return bites
+	case argument <= 0xffffffff:
+		bites := []byte{majorType<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(bites[1:], uint32(argument))
+		return bites
+	default:
+		bites := []byte{majorType<<5 | 27, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(bites[1:], argument)
+		return bites
+	}
+}
+
+func newPrivateKey() *ecdsa.PrivateKey {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return privateKey
+}
+
+func newCertificate(template *x509.Certificate, parent *x509.Certificate, publicKey *ecdsa.PublicKey, signer *ecdsa.PrivateKey) *x509.Certificate {
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		panic(err)
+	}
+	template.SerialNumber = serialNumber
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(24 * time.Hour)
+	if parent == nil {
+		parent = template
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+	if err != nil {
+		panic(err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		panic(err)
+	}
+	return certificate
+}
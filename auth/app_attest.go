@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	AppAttestChallengeExpirationDuration = 5 * time.Minute
+	AppAttestDataLengthMaximum           = 64 * 1024
+)
+
+// AppAttestChallenge is issued to the user for a single attestation and must be used before it expires
+type AppAttestChallenge struct {
+	Challenge      string    `json:"challenge" bson:"challenge"`
+	UserID         string    `json:"userId" bson:"userId"`
+	ExpirationTime time.Time `json:"expirationTime" bson:"expirationTime"`
+}
+
+func NewAppAttestChallenge(userID string) (*AppAttestChallenge, error) {
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+
+	return &AppAttestChallenge{
+		Challenge:      id.Must(id.New(32)),
+		UserID:         userID,
+		ExpirationTime: time.Now().Add(AppAttestChallengeExpirationDuration),
+	}, nil
+}
+
+// AppAttestAttestation is sent by the app to attest a new key; the attestation is base64 encoded
+type AppAttestAttestation struct {
+	KeyID       *string `json:"keyId,omitempty"`
+	Attestation *string `json:"attestation,omitempty"`
+	Challenge   *string `json:"challenge,omitempty"`
+}
+
+func NewAppAttestAttestation() *AppAttestAttestation {
+	return &AppAttestAttestation{}
+}
+
+func (a *AppAttestAttestation) Parse(parser structure.ObjectParser) {
+	a.KeyID = parser.String("keyId")
+	a.Attestation = parser.String("attestation")
+	a.Challenge = parser.String("challenge")
+}
+
+func (a *AppAttestAttestation) Validate(validator structure.Validator) {
+	validator.String("keyId", a.KeyID).Exists().NotEmpty().Using(Base64Validator)
+	validator.String("attestation", a.Attestation).Exists().NotEmpty().LengthLessThanOrEqualTo(AppAttestDataLengthMaximum).Using(Base64Validator)
+	validator.String("challenge", a.Challenge).Exists().NotEmpty()
+}
+
+// AppAttestAssertion is sent by the app to prove a request originates from an attested key; the assertion and client
+// data are base64 encoded
+type AppAttestAssertion struct {
+	KeyID      *string `json:"keyId,omitempty"`
+	Assertion  *string `json:"assertion,omitempty"`
+	ClientData *string `json:"clientData,omitempty"`
+}
+
+func NewAppAttestAssertion() *AppAttestAssertion {
+	return &AppAttestAssertion{}
+}
+
+func (a *AppAttestAssertion) Parse(parser structure.ObjectParser) {
+	a.KeyID = parser.String("keyId")
+	a.Assertion = parser.String("assertion")
+	a.ClientData = parser.String("clientData")
+}
+
+func (a *AppAttestAssertion) Validate(validator structure.Validator) {
+	validator.String("keyId", a.KeyID).Exists().NotEmpty().Using(Base64Validator)
+	validator.String("assertion", a.Assertion).Exists().NotEmpty().LengthLessThanOrEqualTo(AppAttestDataLengthMaximum).Using(Base64Validator)
+	validator.String("clientData", a.ClientData).Exists().NotEmpty().LengthLessThanOrEqualTo(AppAttestDataLengthMaximum).Using(Base64Validator)
+}
+
+// AppAttestKey is a key attested by a genuine instance of the app. The counter is that of the latest assertion.
+type AppAttestKey struct {
+	ID                string     `json:"id" bson:"id"`
+	UserID            string     `json:"userId" bson:"userId"`
+	PublicKey         []byte     `json:"-" bson:"publicKey"`
+	Receipt           []byte     `json:"-" bson:"receipt,omitempty"`
+	Environment       string     `json:"environment" bson:"environment"`
+	Counter           int64      `json:"counter" bson:"counter"`
+	CreatedTime       time.Time  `json:"createdTime" bson:"createdTime"`
+	LastAssertionTime *time.Time `json:"lastAssertionTime,omitempty" bson:"lastAssertionTime,omitempty"`
+}
+
+func Base64Validator(value string, errorReporter structure.ErrorReporter) {
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+		errorReporter.ReportError(structureValidator.ErrorValueNotValid())
+	}
+}
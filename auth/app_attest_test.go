@@ -0,0 +1,94 @@
+package auth_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+var _ = Describe("AppAttest", func() {
+	Context("NewAppAttestChallenge", func() {
+		It("returns an error if the user id is missing", func() {
+			challenge, err := auth.NewAppAttestChallenge("")
+			Expect(err).To(MatchError("user id is missing"))
+			Expect(challenge).To(BeNil())
+		})
+
+		It("returns a challenge that expires", func() {
+			challenge, err := auth.NewAppAttestChallenge("1234567890")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(challenge).ToNot(BeNil())
+			Expect(challenge.Challenge).To(HaveLen(64))
+			Expect(challenge.UserID).To(Equal("1234567890"))
+			Expect(challenge.ExpirationTime).To(BeTemporally("~", time.Now().Add(auth.AppAttestChallengeExpirationDuration), time.Second))
+		})
+
+		It("returns different challenges for each invocation", func() {
+			first, err := auth.NewAppAttestChallenge("1234567890")
+			Expect(err).ToNot(HaveOccurred())
+			second, err := auth.NewAppAttestChallenge("1234567890")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.Challenge).ToNot(Equal(second.Challenge))
+		})
+	})
+
+	Context("AppAttestAttestation", func() {
+		var attestation *auth.AppAttestAttestation
+
+		BeforeEach(func() {
+			attestation = auth.NewAppAttestAttestation()
+			attestation.KeyID = pointer.FromString("a2V5")
+			attestation.Attestation = pointer.FromString("YXR0ZXN0YXRpb24=")
+			attestation.Challenge = pointer.FromString("challenge")
+		})
+
+		It("is valid", func() {
+			Expect(structureValidator.New().Validate(attestation)).To(Succeed())
+		})
+
+		It("is invalid if the key id is not base64", func() {
+			attestation.KeyID = pointer.FromString("not base64!")
+			Expect(structureValidator.New().Validate(attestation)).ToNot(Succeed())
+		})
+
+		It("is invalid if the attestation is missing", func() {
+			attestation.Attestation = nil
+			Expect(structureValidator.New().Validate(attestation)).ToNot(Succeed())
+		})
+
+		It("is invalid if the challenge is missing", func() {
+			attestation.Challenge = nil
+			Expect(structureValidator.New().Validate(attestation)).ToNot(Succeed())
+		})
+	})
+
+	Context("AppAttestAssertion", func() {
+		var assertion *auth.AppAttestAssertion
+
+		BeforeEach(func() {
+			assertion = auth.NewAppAttestAssertion()
+			assertion.KeyID = pointer.FromString("a2V5")
+			assertion.Assertion = pointer.FromString("YXNzZXJ0aW9u")
+			assertion.ClientData = pointer.FromString("e30=")
+		})
+
+		It("is valid", func() {
+			Expect(structureValidator.New().Validate(assertion)).To(Succeed())
+		})
+
+		It("is invalid if the assertion is not base64", func() {
+			assertion.Assertion = pointer.FromString("not base64!")
+			Expect(structureValidator.New().Validate(assertion)).ToNot(Succeed())
+		})
+
+		It("is invalid if the client data is missing", func() {
+			assertion.ClientData = nil
+			Expect(structureValidator.New().Validate(assertion)).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	AuditEventTypeAuthorization             = "authorization"
+	AuditEventTypeOAuthTokenCreate          = "oauth_token.create"
+	AuditEventTypeOAuthTokenRevoke          = "oauth_token.revoke"
+	AuditEventTypePersonalAccessTokenCreate = "personal_access_token.create"
+	AuditEventTypePersonalAccessTokenRevoke = "personal_access_token.revoke"
+	AuditEventTypeProviderSessionCreate     = "provider_session.create"
+	AuditEventTypeProviderSessionDelete     = "provider_session.delete"
+	AuditEventTypeProviderSessionUpdate     = "provider_session.update"
+	AuditEventTypeRestrictedTokenCreate     = "restricted_token.create"
+	AuditEventTypeRestrictedTokenDelete     = "restricted_token.delete"
+	AuditEventTypeRestrictedTokenUpdate     = "restricted_token.update"
+
+	AuditEventResultAllowed = "allowed"
+	AuditEventResultDenied  = "denied"
+	AuditEventResultFailed  = "failed"
+	AuditEventResultSuccess = "success"
+
+	AuditEventGrantClinic     = "clinic"
+	AuditEventGrantOwner      = "owner"
+	AuditEventGrantPermission = "permission"
+	AuditEventGrantService    = "service"
+	AuditEventGrantShare      = "share"
+
+	AuditEventsLengthMaximum = 100
+)
+
+func AuditEventTypes() []string {
+	return []string{
+		AuditEventTypeAuthorization,
+		AuditEventTypeOAuthTokenCreate,
+		AuditEventTypeOAuthTokenRevoke,
+		AuditEventTypePersonalAccessTokenCreate,
+		AuditEventTypePersonalAccessTokenRevoke,
+		AuditEventTypeProviderSessionCreate,
+		AuditEventTypeProviderSessionDelete,
+		AuditEventTypeProviderSessionUpdate,
+		AuditEventTypeRestrictedTokenCreate,
+		AuditEventTypeRestrictedTokenDelete,
+		AuditEventTypeRestrictedTokenUpdate,
+	}
+}
+
+func AuditEventGrants() []string {
+	return []string{
+		AuditEventGrantClinic,
+		AuditEventGrantOwner,
+		AuditEventGrantPermission,
+		AuditEventGrantService,
+		AuditEventGrantShare,
+	}
+}
+
+func AuditEventResults() []string {
+	return []string{
+		AuditEventResultAllowed,
+		AuditEventResultDenied,
+		AuditEventResultFailed,
+		AuditEventResultSuccess,
+	}
+}
+
+// AuditRecorder records audit events. Recording never fails the caller; events that cannot be stored are logged.
+type AuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, event *AuditEvent)
+}
+
+type AuditEventAccessor interface {
+	ListUserAuditEvents(ctx context.Context, userID string, filter *AuditEventFilter, pagination *page.Pagination) (AuditEvents, error)
+	CreateAuditEvents(ctx context.Context, events AuditEvents) error
+}
+
+// AuditEventFilter filters audit events by type and by time, inclusive of the start time and exclusive of the end time
+type AuditEventFilter struct {
+	Type      *[]string  `json:"type,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+func NewAuditEventFilter() *AuditEventFilter {
+	return &AuditEventFilter{}
+}
+
+func (a *AuditEventFilter) Parse(parser structure.ObjectParser) {
+	a.Type = parser.StringArray("type")
+	a.StartTime = parser.Time("startTime", time.RFC3339Nano)
+	a.EndTime = parser.Time("endTime", time.RFC3339Nano)
+}
+
+func (a *AuditEventFilter) Validate(validator structure.Validator) {
+	validator.StringArray("type", a.Type).NotEmpty().EachOneOf(AuditEventTypes()...).EachUnique()
+	if a.StartTime != nil {
+		validator.Time("endTime", a.EndTime).After(*a.StartTime)
+	}
+}
+
+func (a *AuditEventFilter) MutateRequest(req *http.Request) error {
+	parameters := map[string][]string{}
+	if a.Type != nil {
+		parameters["type"] = *a.Type
+	}
+	if a.StartTime != nil {
+		parameters["startTime"] = []string{a.StartTime.Format(time.RFC3339Nano)}
+	}
+	if a.EndTime != nil {
+		parameters["endTime"] = []string{a.EndTime.Format(time.RFC3339Nano)}
+	}
+	return request.NewArrayParametersMutator(parameters).MutateRequest(req)
+}
+
+// AuditEvent records a single authorization decision or credential lifecycle event. The actor is the authenticated
+// caller, the target user is the user whose data or credentials were accessed, and the request id is the trace request
+// of the originating request. The grant is how the authorization decision was reached, if known.
+type AuditEvent struct {
+	ID           string    `json:"id" bson:"id"`
+	Type         string    `json:"type" bson:"type"`
+	Time         time.Time `json:"time" bson:"time"`
+	ActorMethod  string    `json:"actorMethod,omitempty" bson:"actorMethod,omitempty"`
+	ActorUserID  string    `json:"actorUserId,omitempty" bson:"actorUserId,omitempty"`
+	ActorService string    `json:"actorService,omitempty" bson:"actorService,omitempty"`
+	TargetUserID string    `json:"targetUserId" bson:"targetUserId"`
+	Permission   string    `json:"permission,omitempty" bson:"permission,omitempty"`
+	Grant        string    `json:"grant,omitempty" bson:"grant,omitempty"`
+	ResourceID   string    `json:"resourceId,omitempty" bson:"resourceId,omitempty"`
+	Route        string    `json:"route,omitempty" bson:"route,omitempty"`
+	Result       string    `json:"result" bson:"result"`
+	RequestID    string    `json:"requestId,omitempty" bson:"requestId,omitempty"`
+}
+
+// NewAuditEvent returns an audit event with the actor, route, and request id taken from the context
+func NewAuditEvent(ctx context.Context, typ string, targetUserID string, result string) *AuditEvent {
+	event := &AuditEvent{
+		ID:           NewAuditEventID(),
+		Type:         typ,
+		Time:         time.Now(),
+		TargetUserID: targetUserID,
+		Result:       result,
+		Route:        request.RouteFromContext(ctx),
+		RequestID:    request.TraceRequestFromContext(ctx),
+	}
+	if details := request.DetailsFromContext(ctx); details != nil {
+		event.ActorMethod = string(details.Method())
+		event.ActorUserID = details.UserID()
+		event.ActorService = details.ServiceName()
+	}
+	return event
+}
+
+// NewAuthorizationAuditEvent returns an audit event for the authorization decision, where a nil error is allowed, an
+// unauthorized error is denied, and any other error is failed
+func NewAuthorizationAuditEvent(ctx context.Context, targetUserID string, permission string, err error) *AuditEvent {
+	result := AuditEventResultAllowed
+	if err != nil {
+		if request.IsErrorUnauthorized(err) {
+			result = AuditEventResultDenied
+		} else {
+			result = AuditEventResultFailed
+		}
+	}
+
+	event := NewAuditEvent(ctx, AuditEventTypeAuthorization, targetUserID, result)
+	event.Permission = permission
+	return event
+}
+
+// NewLifecycleAuditEvent returns an audit event for the successful creation, update, or deletion of the user's token or
+// provider session. The resource id is empty if all of the user's tokens or provider sessions are affected.
+func NewLifecycleAuditEvent(ctx context.Context, typ string, userID string, resourceID string) *AuditEvent {
+	event := NewAuditEvent(ctx, typ, userID, AuditEventResultSuccess)
+	event.ResourceID = resourceID
+	return event
+}
+
+func (a *AuditEvent) Parse(parser structure.ObjectParser) {
+	if ptr := parser.String("id"); ptr != nil {
+		a.ID = *ptr
+	}
+	if ptr := parser.String("type"); ptr != nil {
+		a.Type = *ptr
+	}
+	if ptr := parser.Time("time", time.RFC3339Nano); ptr != nil {
+		a.Time = *ptr
+	}
+	if ptr := parser.String("actorMethod"); ptr != nil {
+		a.ActorMethod = *ptr
+	}
+	if ptr := parser.String("actorUserId"); ptr != nil {
+		a.ActorUserID = *ptr
+	}
+	if ptr := parser.String("actorService"); ptr != nil {
+		a.ActorService = *ptr
+	}
+	if ptr := parser.String("targetUserId"); ptr != nil {
+		a.TargetUserID = *ptr
+	}
+	if ptr := parser.String("permission"); ptr != nil {
+		a.Permission = *ptr
+	}
+	if ptr := parser.String("grant"); ptr != nil {
+		a.Grant = *ptr
+	}
+	if ptr := parser.String("resourceId"); ptr != nil {
+		a.ResourceID = *ptr
+	}
+	if ptr := parser.String("route"); ptr != nil {
+		a.Route = *ptr
+	}
+	if ptr := parser.String("result"); ptr != nil {
+		a.Result = *ptr
+	}
+	if ptr := parser.String("requestId"); ptr != nil {
+		a.RequestID = *ptr
+	}
+}
+
+func (a *AuditEvent) Validate(validator structure.Validator) {
+	validator.String("id", &a.ID).Using(AuditEventIDValidator)
+	validator.String("type", &a.Type).OneOf(AuditEventTypes()...)
+	validator.Time("time", &a.Time).NotZero()
+	validator.String("targetUserId", &a.TargetUserID).Using(UserIDValidator)
+	validator.String("result", &a.Result).OneOf(AuditEventResults()...)
+	if a.Grant != "" {
+		validator.String("grant", &a.Grant).OneOf(AuditEventGrants()...)
+	}
+}
+
+type AuditEvents []*AuditEvent
+
+func NewAuditEvents() *AuditEvents {
+	return &AuditEvents{}
+}
+
+func (a *AuditEvents) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		event := &AuditEvent{}
+		if objectParser := parser.WithReferenceObjectParser(reference); objectParser.Exists() {
+			event.Parse(objectParser)
+		}
+		*a = append(*a, event)
+	}
+}
+
+func (a *AuditEvents) Validate(validator structure.Validator) {
+	if length := len(*a); length == 0 {
+		validator.ReportError(structureValidator.ErrorValueEmpty())
+	} else if length > AuditEventsLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, AuditEventsLengthMaximum))
+	}
+
+	for index, event := range *a {
+		if eventValidator := validator.WithReference(strconv.Itoa(index)); event != nil {
+			event.Validate(eventValidator)
+		} else {
+			eventValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+func NewAuditEventID() string {
+	return id.Must(id.New(16))
+}
+
+func IsValidAuditEventID(value string) bool {
+	return ValidateAuditEventID(value) == nil
+}
+
+func AuditEventIDValidator(value string, errorReporter structure.ErrorReporter) {
+	errorReporter.ReportError(ValidateAuditEventID(value))
+}
+
+func ValidateAuditEventID(value string) error {
+	if value == "" {
+		return structureValidator.ErrorValueEmpty()
+	} else if !auditEventIDExpression.MatchString(value) {
+		return ErrorValueStringAsAuditEventIDNotValid(value)
+	}
+	return nil
+}
+
+func ErrorValueStringAsAuditEventIDNotValid(value string) error {
+	return errors.Preparedf(structureValidator.ErrorCodeValueNotValid, "value is not valid", "value %q is not valid as audit event id", value)
+}
+
+var auditEventIDExpression = regexp.MustCompile("^[0-9a-z]{32}$")
@@ -0,0 +1,140 @@
+package auth_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	authTest "github.com/tidepool-org/platform/auth/test"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/permission"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+var _ = Describe("Audit", func() {
+	var actorUserID string
+	var targetUserID string
+	var ctx context.Context
+
+	BeforeEach(func() {
+		actorUserID = authTest.RandomUserID()
+		targetUserID = authTest.RandomUserID()
+		ctx = context.Background()
+		ctx = request.NewContextWithDetails(ctx, request.NewDetails(request.MethodSessionToken, actorUserID, authTest.NewSessionToken()))
+		ctx = request.NewContextWithTraceRequest(ctx, "0123456789abcdef")
+		ctx = request.NewContextWithRoute(ctx, "GET /v1/users/"+targetUserID+"/data_sets")
+	})
+
+	Context("NewAuditEvent", func() {
+		It("returns a valid audit event with the actor, route, and request id from the context", func() {
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeRestrictedTokenCreate, targetUserID, auth.AuditEventResultSuccess)
+			Expect(event).ToNot(BeNil())
+			Expect(auth.IsValidAuditEventID(event.ID)).To(BeTrue())
+			Expect(event.Type).To(Equal(auth.AuditEventTypeRestrictedTokenCreate))
+			Expect(event.Time).To(BeTemporally("~", time.Now(), time.Second))
+			Expect(event.ActorMethod).To(Equal("session token"))
+			Expect(event.ActorUserID).To(Equal(actorUserID))
+			Expect(event.TargetUserID).To(Equal(targetUserID))
+			Expect(event.Route).To(Equal("GET /v1/users/" + targetUserID + "/data_sets"))
+			Expect(event.Result).To(Equal(auth.AuditEventResultSuccess))
+			Expect(event.RequestID).To(Equal("0123456789abcdef"))
+			Expect(structureValidator.New().Validate(event)).To(Succeed())
+		})
+
+		It("returns an audit event without an actor if the context does not have details", func() {
+			event := auth.NewAuditEvent(context.Background(), auth.AuditEventTypeRestrictedTokenCreate, targetUserID, auth.AuditEventResultSuccess)
+			Expect(event.ActorMethod).To(BeEmpty())
+			Expect(event.ActorUserID).To(BeEmpty())
+			Expect(event.Route).To(BeEmpty())
+			Expect(event.RequestID).To(BeEmpty())
+		})
+	})
+
+	Context("NewAuthorizationAuditEvent", func() {
+		It("returns an allowed audit event if there is no error", func() {
+			event := auth.NewAuthorizationAuditEvent(ctx, targetUserID, permission.Read, nil)
+			Expect(event.Type).To(Equal(auth.AuditEventTypeAuthorization))
+			Expect(event.Permission).To(Equal(permission.Read))
+			Expect(event.Result).To(Equal(auth.AuditEventResultAllowed))
+		})
+
+		It("returns a denied audit event if the error is unauthorized", func() {
+			event := auth.NewAuthorizationAuditEvent(ctx, targetUserID, permission.Write, request.ErrorUnauthorized())
+			Expect(event.Permission).To(Equal(permission.Write))
+			Expect(event.Result).To(Equal(auth.AuditEventResultDenied))
+		})
+
+		It("returns a failed audit event if the error is not unauthorized", func() {
+			event := auth.NewAuthorizationAuditEvent(ctx, targetUserID, permission.Read, errors.New("test error"))
+			Expect(event.Result).To(Equal(auth.AuditEventResultFailed))
+		})
+	})
+
+	Context("NewLifecycleAuditEvent", func() {
+		It("returns a successful audit event with the resource id", func() {
+			event := auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypePersonalAccessTokenRevoke, targetUserID, "abcdef")
+			Expect(event.Type).To(Equal(auth.AuditEventTypePersonalAccessTokenRevoke))
+			Expect(event.ResourceID).To(Equal("abcdef"))
+			Expect(event.Result).To(Equal(auth.AuditEventResultSuccess))
+		})
+	})
+
+	Context("AuditEvents", func() {
+		It("is invalid if empty", func() {
+			Expect(structureValidator.New().Validate(auth.NewAuditEvents())).To(MatchError("value is empty"))
+		})
+
+		It("is invalid if there are too many audit events", func() {
+			events := auth.AuditEvents{}
+			for index := 0; index <= auth.AuditEventsLengthMaximum; index++ {
+				events = append(events, auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, targetUserID, auth.AuditEventResultAllowed))
+			}
+			Expect(structureValidator.New().Validate(&events)).To(MatchError("length 101 is not less than or equal to 100"))
+		})
+
+		It("is invalid if an audit event has an unknown result", func() {
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, targetUserID, "maybe")
+			Expect(structureValidator.New().Validate(&auth.AuditEvents{event})).To(MatchError(`value "maybe" is not one of ["allowed", "denied", "failed", "success"]`))
+		})
+
+		It("is invalid if an audit event has an unknown grant", func() {
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, targetUserID, auth.AuditEventResultAllowed)
+			event.Grant = "friend"
+			Expect(structureValidator.New().Validate(&auth.AuditEvents{event})).To(MatchError(`value "friend" is not one of ["clinic", "owner", "permission", "service", "share"]`))
+		})
+
+		It("is valid", func() {
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, targetUserID, auth.AuditEventResultAllowed)
+			event.Grant = auth.AuditEventGrantClinic
+			Expect(structureValidator.New().Validate(&auth.AuditEvents{event})).To(Succeed())
+		})
+	})
+
+	Context("AuditEventFilter", func() {
+		It("is invalid if the type is unknown", func() {
+			filter := auth.NewAuditEventFilter()
+			filter.Type = pointer.FromStringArray([]string{"unknown"})
+			Expect(structureValidator.New().Validate(filter)).To(HaveOccurred())
+		})
+
+		It("is invalid if the end time is not after the start time", func() {
+			filter := auth.NewAuditEventFilter()
+			filter.StartTime = pointer.FromTime(time.Now())
+			filter.EndTime = pointer.FromTime(filter.StartTime.Add(-time.Hour))
+			Expect(structureValidator.New().Validate(filter)).To(HaveOccurred())
+		})
+
+		It("is valid", func() {
+			filter := auth.NewAuditEventFilter()
+			filter.Type = pointer.FromStringArray([]string{auth.AuditEventTypeAuthorization})
+			filter.StartTime = pointer.FromTime(time.Now().Add(-time.Hour))
+			filter.EndTime = pointer.FromTime(time.Now())
+			Expect(structureValidator.New().Validate(filter)).To(Succeed())
+		})
+	})
+})
@@ -7,15 +7,19 @@ import (
 )
 
 const (
-	TidepoolServiceSecretHeaderKey      = "X-Tidepool-Service-Secret"
-	TidepoolAuthorizationHeaderKey      = "Authorization"
-	TidepoolSessionTokenHeaderKey       = "X-Tidepool-Session-Token"
-	TidepoolRestrictedTokenParameterKey = "restricted_token"
+	TidepoolServiceSecretHeaderKey       = "X-Tidepool-Service-Secret"
+	TidepoolAuthorizationHeaderKey       = "Authorization"
+	TidepoolSessionTokenHeaderKey        = "X-Tidepool-Session-Token"
+	TidepoolPersonalAccessTokenHeaderKey = "X-Tidepool-Personal-Access-Token"
+	TidepoolRestrictedTokenParameterKey  = "restricted_token"
 )
 
 type Client interface {
 	ProviderSessionAccessor
 	RestrictedTokenAccessor
+	PersonalAccessTokenAccessor
+	OAuthAccessor
+	AuditEventAccessor
 	ExternalAccessor
 }
 
@@ -25,6 +29,7 @@ type ExternalAccessor interface {
 	EnsureAuthorized(ctx context.Context) error
 	EnsureAuthorizedService(ctx context.Context) error
 	EnsureAuthorizedUser(ctx context.Context, targetUserID string, permission string) (string, error)
+	AuditRecorder
 }
 
 type contextKey string
@@ -70,10 +70,13 @@ func NewClient(cfg *Config, authorizeAs platform.AuthorizeAs, name string, lgr l
 		return nil, err
 	}
 
-	return &Client{
+	c := &Client{
 		client:   clnt,
 		External: extrnl,
-	}, nil
+	}
+	extrnl.SetAuditEventSink(c)
+
+	return c, nil
 }
 
 func (c *Client) ListUserProviderSessions(ctx context.Context, userID string, filter *auth.ProviderSessionFilter, pagination *page.Pagination) (auth.ProviderSessions, error) {
@@ -194,6 +197,20 @@ func (c *Client) DeleteProviderSession(ctx context.Context, id string) error {
 	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
 }
 
+func (c *Client) RotateProviderSessionEncryption(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "provider_sessions", "rotate_encryption")
+	var count int
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, nil, &count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (c *Client) ListUserRestrictedTokens(ctx context.Context, userID string, filter *auth.RestrictedTokenFilter, pagination *page.Pagination) (auth.RestrictedTokens, error) {
 	if ctx == nil {
 		return nil, errors.New("context is missing")
@@ -311,3 +328,234 @@ func (c *Client) DeleteRestrictedToken(ctx context.Context, id string) error {
 	url := c.client.ConstructURL("v1", "restricted_tokens", id)
 	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
 }
+
+func (c *Client) UseRestrictedToken(ctx context.Context, id string) (*auth.RestrictedToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "restricted_tokens", id, "use")
+	restrictedToken := &auth.RestrictedToken{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, nil, restrictedToken); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return restrictedToken, nil
+}
+
+func (c *Client) ListUserPersonalAccessTokens(ctx context.Context, userID string, filter *auth.PersonalAccessTokenFilter, pagination *page.Pagination) (auth.PersonalAccessTokens, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if filter == nil {
+		filter = auth.NewPersonalAccessTokenFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "users", userID, "personal_access_tokens")
+	personalAccessTokens := auth.PersonalAccessTokens{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, []request.RequestMutator{filter, pagination}, nil, &personalAccessTokens); err != nil {
+		return nil, err
+	}
+
+	return personalAccessTokens, nil
+}
+
+func (c *Client) CreateUserPersonalAccessToken(ctx context.Context, userID string, create *auth.PersonalAccessTokenCreate) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "users", userID, "personal_access_tokens")
+	personalAccessToken := &auth.PersonalAccessToken{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, create, personalAccessToken); err != nil {
+		return nil, err
+	}
+
+	return personalAccessToken, nil
+}
+
+func (c *Client) DeleteAllPersonalAccessTokens(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "users", userID, "personal_access_tokens")
+	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
+}
+
+func (c *Client) GetPersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "personal_access_tokens", id)
+	personalAccessToken := &auth.PersonalAccessToken{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, nil, nil, personalAccessToken); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return personalAccessToken, nil
+}
+
+func (c *Client) RevokePersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "personal_access_tokens", id)
+	personalAccessToken := &auth.PersonalAccessToken{}
+	if err := c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, personalAccessToken); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return personalAccessToken, nil
+}
+
+// AuthenticatePersonalAccessToken caches authenticated personal access tokens for the cache time to live, so the last
+// used time recorded by the auth service is only updated once per time to live rather than on every request. As with
+// session tokens, a revoked personal access token remains valid here until its cache entry expires.
+func (c *Client) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if token == "" {
+		return nil, errors.New("token is missing")
+	}
+
+	key := personalAccessTokenCacheKey(token)
+	if value, ok := c.cache.Get(key); ok {
+		if err, ok := value.(error); ok {
+			if request.IsErrorResourceNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return value.(*auth.PersonalAccessToken), nil
+	}
+
+	url := c.client.ConstructURL("v1", "personal_access_tokens", "authenticate")
+	authenticate := &auth.PersonalAccessTokenAuthenticate{Token: &token}
+	personalAccessToken := &auth.PersonalAccessToken{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, authenticate, personalAccessToken); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			c.cache.SetError(key, err)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	c.cache.Set(key, personalAccessToken, personalAccessToken.UserID)
+	return personalAccessToken, nil
+}
+
+func (c *Client) DeleteAllOAuthConsents(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "users", userID, "oauth2", "consents")
+	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
+}
+
+func (c *Client) IntrospectOAuthToken(ctx context.Context, token string) (*auth.OAuthTokenIntrospection, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if token == "" {
+		return nil, errors.New("token is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "oauth2", "introspect")
+	introspect := &auth.OAuthTokenIntrospect{Token: &token}
+	introspection := &auth.OAuthTokenIntrospection{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, introspect, introspection); err != nil {
+		return nil, err
+	}
+
+	return introspection, nil
+}
+
+func (c *Client) ListUserAuditEvents(ctx context.Context, userID string, filter *auth.AuditEventFilter, pagination *page.Pagination) (auth.AuditEvents, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if filter == nil {
+		filter = auth.NewAuditEventFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "users", userID, "audit_events")
+	events := auth.AuditEvents{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, []request.RequestMutator{filter, pagination}, nil, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (c *Client) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if err := structureValidator.New().Validate(&events); err != nil {
+		return errors.Wrap(err, "events are invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "audit_events")
+	return c.client.RequestData(ctx, http.MethodPost, url, nil, events, nil)
+}
+
+func personalAccessTokenCacheKey(token string) string {
+	return "personalAccessToken:" + auth.HashPersonalAccessTokenSecret(token)
+}
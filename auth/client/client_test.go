@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -16,7 +17,9 @@ import (
 	errorsTest "github.com/tidepool-org/platform/errors/test"
 	"github.com/tidepool-org/platform/log"
 	logTest "github.com/tidepool-org/platform/log/test"
+	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/platform"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/test"
 	testHttp "github.com/tidepool-org/platform/test/http"
@@ -302,6 +305,39 @@ var _ = Describe("Client", func() {
 				Expect(client.Start()).To(Succeed())
 			})
 
+			Context("RecordAuditEvent", func() {
+				var userID string
+
+				BeforeEach(func() {
+					userID = authTest.RandomUserID()
+					server.AppendHandlers(
+						CombineHandlers(
+							VerifyRequest("POST", "/v1/audit_events"),
+							VerifyHeaderKV("X-Tidepool-Service-Secret", config.Config.ServiceSecret),
+							VerifyContentType("application/json; charset=utf-8"),
+							func(res http.ResponseWriter, req *http.Request) {
+								events := auth.AuditEvents{}
+								Expect(json.NewDecoder(req.Body).Decode(&events)).To(Succeed())
+								Expect(events).To(HaveLen(1))
+								Expect(events[0].Type).To(Equal(auth.AuditEventTypeAuthorization))
+								Expect(events[0].ActorMethod).To(Equal(string(request.MethodSessionToken)))
+								Expect(events[0].ActorUserID).To(Equal(userID))
+								Expect(events[0].TargetUserID).To(Equal(userID))
+								Expect(events[0].Permission).To(Equal(permission.Read))
+								Expect(events[0].Result).To(Equal(auth.AuditEventResultAllowed))
+							},
+							RespondWith(http.StatusCreated, nil)),
+					)
+				})
+
+				It("sends the audit event for an authorization decision before closing", func() {
+					authorizedCtx := request.NewContextWithDetails(ctx, request.NewDetails(request.MethodSessionToken, userID, token))
+					Expect(client.EnsureAuthorizedUser(authorizedCtx, userID, permission.Read)).To(Equal(userID))
+					client.Close()
+					Expect(server.ReceivedRequests()).To(HaveLen(2))
+				})
+			})
+
 			Context("ServerSessionToken", func() {
 				It("returns a server token", func() {
 					returnedServerSessionToken, err := client.ServerSessionToken()
@@ -378,6 +414,15 @@ var _ = Describe("Client", func() {
 						Expect(details).To(BeNil())
 						Expect(server.ReceivedRequests()).To(HaveLen(2))
 					})
+
+					It("returns the cached error without a second request", func() {
+						_, err := client.ValidateSessionToken(ctx, token)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthenticated())
+						details, err := client.ValidateSessionToken(ctx, token)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthenticated())
+						Expect(details).To(BeNil())
+						Expect(server.ReceivedRequests()).To(HaveLen(2))
+					})
 				})
 
 				Context("with a successful response, but not parseable", func() {
@@ -438,6 +483,31 @@ var _ = Describe("Client", func() {
 						Expect(details.IsService()).To(BeFalse())
 						Expect(details.UserID()).To(Equal("session-user-id"))
 					})
+
+					It("returns the cached user id without a second request", func() {
+						_, err := client.ValidateSessionToken(ctx, token)
+						Expect(err).ToNot(HaveOccurred())
+						details, err := client.ValidateSessionToken(ctx, token)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(details).ToNot(BeNil())
+						Expect(details.UserID()).To(Equal("session-user-id"))
+						Expect(server.ReceivedRequests()).To(HaveLen(2))
+					})
+
+					It("requests validation again after invalidating the user", func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("GET", "/auth/token/"+token),
+								RespondWith(http.StatusUnauthorized, nil)),
+						)
+						_, err := client.ValidateSessionToken(ctx, token)
+						Expect(err).ToNot(HaveOccurred())
+						client.InvalidateUser("session-user-id")
+						details, err := client.ValidateSessionToken(ctx, token)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthenticated())
+						Expect(details).To(BeNil())
+						Expect(server.ReceivedRequests()).To(HaveLen(3))
+					})
 				})
 
 				Context("with a successful response and is server", func() {
@@ -461,6 +531,103 @@ var _ = Describe("Client", func() {
 					})
 				})
 			})
+
+			Context("AuthenticatePersonalAccessToken", func() {
+				var personalAccessToken string
+
+				BeforeEach(func() {
+					personalAccessToken = auth.NewPersonalAccessTokenSecret()
+				})
+
+				Context("with a not found response", func() {
+					BeforeEach(func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("POST", "/v1/personal_access_tokens/authenticate"),
+								VerifyHeaderKV("X-Tidepool-Service-Secret", config.Config.ServiceSecret),
+								RespondWith(http.StatusNotFound, nil)),
+						)
+					})
+
+					It("returns the cached missing personal access token without a second request", func() {
+						result, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result).To(BeNil())
+						result, err = client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result).To(BeNil())
+						Expect(server.ReceivedRequests()).To(HaveLen(2))
+					})
+				})
+
+				Context("with an unexpected response", func() {
+					BeforeEach(func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("POST", "/v1/personal_access_tokens/authenticate"),
+								RespondWith(http.StatusInternalServerError, nil)),
+							CombineHandlers(
+								VerifyRequest("POST", "/v1/personal_access_tokens/authenticate"),
+								RespondWith(http.StatusInternalServerError, nil)),
+						)
+					})
+
+					It("returns the error without caching it", func() {
+						result, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).To(HaveOccurred())
+						Expect(result).To(BeNil())
+						result, err = client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).To(HaveOccurred())
+						Expect(result).To(BeNil())
+						Expect(server.ReceivedRequests()).To(HaveLen(3))
+					})
+				})
+
+				Context("with a successful response", func() {
+					var userID string
+
+					BeforeEach(func() {
+						userID = authTest.RandomUserID()
+						create := &auth.PersonalAccessTokenCreate{
+							Name:   pointer.FromString("test"),
+							Scopes: &auth.RestrictedTokenScopes{{Path: pointer.FromString("/v1/test")}},
+						}
+						personalAccessTokenObject, err := auth.NewPersonalAccessToken(userID, create)
+						Expect(err).ToNot(HaveOccurred())
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("POST", "/v1/personal_access_tokens/authenticate"),
+								VerifyHeaderKV("X-Tidepool-Service-Secret", config.Config.ServiceSecret),
+								RespondWithJSONEncoded(http.StatusOK, personalAccessTokenObject)),
+						)
+					})
+
+					It("returns the cached personal access token without a second request", func() {
+						_, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						result, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result).ToNot(BeNil())
+						Expect(result.UserID).To(Equal(userID))
+						Expect(server.ReceivedRequests()).To(HaveLen(2))
+					})
+
+					It("authenticates again after invalidating the user", func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("POST", "/v1/personal_access_tokens/authenticate"),
+								RespondWith(http.StatusNotFound, nil)),
+						)
+						_, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						client.InvalidateUser(userID)
+						result, err := client.AuthenticatePersonalAccessToken(ctx, personalAccessToken)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result).To(BeNil())
+						Expect(server.ReceivedRequests()).To(HaveLen(3))
+					})
+				})
+			})
 		})
 	})
 })
@@ -10,6 +10,7 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/cache"
 	"github.com/tidepool-org/platform/config"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/log"
@@ -24,6 +25,11 @@ const (
 
 	ServerSessionTokenTimeoutOnFailureFirst = 1 * time.Second
 	ServerSessionTokenTimeoutOnFailureLast  = 60 * time.Second
+
+	AuditEventsQueueLength     = 1000
+	AuditEventsTimeout         = 10 * time.Second
+	AuditEventsAttemptsMaximum = 3
+	AuditEventsRetryDelayFirst = 100 * time.Millisecond
 )
 
 var ExternalClientModule = fx.Provide(func(name ServiceName, reporter config.Reporter, logger log.Logger, lifecycle fx.Lifecycle) (auth.ExternalAccessor, error) {
@@ -60,12 +66,14 @@ type ExternalConfig struct {
 	*platform.Config
 	ServerSessionTokenSecret  string
 	ServerSessionTokenTimeout time.Duration
+	Cache                     *cache.Config
 }
 
 func NewExternalConfig() *ExternalConfig {
 	return &ExternalConfig{
 		Config:                    platform.NewConfig(),
 		ServerSessionTokenTimeout: 3600 * time.Second,
+		Cache:                     cache.NewConfig(),
 	}
 }
 
@@ -84,7 +92,7 @@ func (e *ExternalConfig) Load(configReporter config.Reporter) error {
 		e.ServerSessionTokenTimeout = time.Duration(serverSessionTokenTimeoutInteger) * time.Second
 	}
 
-	return nil
+	return e.Cache.Load(configReporter.WithScopes("cache"))
 }
 
 func (e *ExternalConfig) Validate() error {
@@ -98,10 +106,20 @@ func (e *ExternalConfig) Validate() error {
 	if e.ServerSessionTokenTimeout <= 0 {
 		return errors.New("server session token timeout is invalid")
 	}
+	if e.Cache == nil {
+		return errors.New("cache is missing")
+	} else if err := e.Cache.Validate(); err != nil {
+		return errors.Wrap(err, "cache is invalid")
+	}
 
 	return nil
 }
 
+// AuditEventSink stores the audit events recorded by the external client
+type AuditEventSink interface {
+	CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error
+}
+
 type External struct {
 	client                    *platform.Client
 	name                      string
@@ -111,6 +129,11 @@ type External struct {
 	serverSessionTokenMutex   sync.Mutex
 	serverSessionTokenSafe    string
 	closingChannel            chan chan bool
+	cache                     *cache.Cache
+	auditEventSink            AuditEventSink
+	auditEventsChannel        chan *auth.AuditEvent
+	auditMutex                sync.RWMutex
+	auditClosingChannel       chan chan bool
 }
 
 func NewExternal(cfg *ExternalConfig, authorizeAs platform.AuthorizeAs, name string, lgr log.Logger) (*External, error) {
@@ -133,15 +156,30 @@ func NewExternal(cfg *ExternalConfig, authorizeAs platform.AuthorizeAs, name str
 		return nil, err
 	}
 
+	cch, err := cache.New(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+
 	return &External{
 		client:                    clnt,
 		logger:                    lgr,
 		name:                      name,
 		serverSessionTokenSecret:  cfg.ServerSessionTokenSecret,
 		serverSessionTokenTimeout: cfg.ServerSessionTokenTimeout,
+		cache:                     cch,
+		auditEventSink:            &loggerAuditEventSink{logger: lgr},
+		auditEventsChannel:        make(chan *auth.AuditEvent, AuditEventsQueueLength),
 	}, nil
 }
 
+// SetAuditEventSink replaces the default sink, which only logs audit events. It must be called before Start.
+func (e *External) SetAuditEventSink(auditEventSink AuditEventSink) {
+	if auditEventSink != nil {
+		e.auditEventSink = auditEventSink
+	}
+}
+
 func (e *External) Start() error {
 	if e.closingChannel == nil {
 		closingChannel := make(chan chan bool)
@@ -164,6 +202,30 @@ func (e *External) Start() error {
 		}()
 	}
 
+	e.auditMutex.Lock()
+	defer e.auditMutex.Unlock()
+
+	if e.auditClosingChannel == nil {
+		auditClosingChannel := make(chan chan bool)
+		e.auditClosingChannel = auditClosingChannel
+
+		go func() {
+			for {
+				select {
+				case closedChannel := <-auditClosingChannel:
+					for events := e.dequeueAuditEvents(nil); len(events) > 0; events = e.dequeueAuditEvents(nil) {
+						e.sendAuditEvents(events)
+					}
+					closedChannel <- true
+					close(closedChannel)
+					return
+				case event := <-e.auditEventsChannel:
+					e.sendAuditEvents(e.dequeueAuditEvents(auth.AuditEvents{event}))
+				}
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -177,6 +239,18 @@ func (e *External) Close() {
 		close(closingChannel)
 		<-closedChannel
 	}
+
+	e.auditMutex.Lock()
+	auditClosingChannel := e.auditClosingChannel
+	e.auditClosingChannel = nil
+	e.auditMutex.Unlock()
+
+	if auditClosingChannel != nil {
+		closedChannel := make(chan bool)
+		auditClosingChannel <- closedChannel
+		close(auditClosingChannel)
+		<-closedChannel
+	}
 }
 
 func (e *External) ServerSessionToken() (string, error) {
@@ -192,6 +266,9 @@ func (e *External) ServerSessionToken() (string, error) {
 	return serverSessionToken, nil
 }
 
+// ValidateSessionToken caches valid session tokens for the cache time to live. Session logout and revocation publish no
+// user event, so a session token that is logged out or revoked remains valid here until its cache entry expires. Set the
+// cache time to live accordingly, or zero the cache size to disable caching entirely.
 func (e *External) ValidateSessionToken(ctx context.Context, token string) (request.Details, error) {
 	if ctx == nil {
 		return nil, errors.New("context is missing")
@@ -200,11 +277,22 @@ func (e *External) ValidateSessionToken(ctx context.Context, token string) (requ
 		return nil, errors.New("token is missing")
 	}
 
+	key := sessionTokenCacheKey(token)
+	if value, ok := e.cache.Get(key); ok {
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+		return value.(request.Details), nil
+	}
+
 	var result struct {
 		IsServer bool
 		UserID   string
 	}
 	if err := e.client.RequestData(ctx, "GET", e.client.ConstructURL("auth", "token", token), nil, nil, &result); err != nil {
+		if request.IsErrorUnauthenticated(err) || request.IsErrorUnauthorized(err) || request.IsErrorResourceNotFound(err) {
+			e.cache.SetError(key, err)
+		}
 		return nil, err
 	}
 
@@ -214,7 +302,14 @@ func (e *External) ValidateSessionToken(ctx context.Context, token string) (requ
 		return nil, errors.New("user id is missing")
 	}
 
-	return request.NewDetails(request.MethodSessionToken, result.UserID, token), nil
+	details := request.NewDetails(request.MethodSessionToken, result.UserID, token)
+	e.cache.Set(key, details, result.UserID)
+	return details, nil
+}
+
+// InvalidateUser removes all cached session tokens and permissions related to the user
+func (e *External) InvalidateUser(userID string) {
+	e.cache.DeleteTagged(userID)
 }
 
 func (e *External) EnsureAuthorized(ctx context.Context) error {
@@ -254,6 +349,40 @@ func (e *External) EnsureAuthorizedUser(ctx context.Context, targetUserID string
 		return "", errors.New("authorized permission is missing")
 	}
 
+	authorizedUserID, err := e.ensureAuthorizedUser(ctx, targetUserID, authorizedPermission)
+	e.RecordAuditEvent(ctx, auth.NewAuthorizationAuditEvent(ctx, targetUserID, authorizedPermission, err))
+	return authorizedUserID, err
+}
+
+// RecordAuditEvent queues the audit event to be sent to the audit event sink. If the client is not started or the
+// queue is full, then the audit event is sent synchronously, applying backpressure to the caller rather than dropping it.
+func (e *External) RecordAuditEvent(ctx context.Context, event *auth.AuditEvent) {
+	if event == nil {
+		return
+	}
+
+	if !e.queueAuditEvent(event) {
+		e.sendAuditEvents(auth.AuditEvents{event})
+	}
+}
+
+func (e *External) queueAuditEvent(event *auth.AuditEvent) bool {
+	e.auditMutex.RLock()
+	defer e.auditMutex.RUnlock()
+
+	if e.auditClosingChannel == nil {
+		return false
+	}
+
+	select {
+	case e.auditEventsChannel <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *External) ensureAuthorizedUser(ctx context.Context, targetUserID string, authorizedPermission string) (string, error) {
 	if details := request.DetailsFromContext(ctx); details != nil {
 		if details.IsService() {
 			return "", nil
@@ -265,17 +394,12 @@ func (e *External) EnsureAuthorizedUser(ctx context.Context, targetUserID string
 				return authenticatedUserID, nil
 			}
 		} else {
-			url := e.client.ConstructURL("access", targetUserID, authenticatedUserID)
-			permissions := permission.Permissions{}
-			if err := e.client.RequestData(ctx, "GET", url, nil, nil, &permissions); err != nil {
+			if permissions, err := e.getUserPermissions(ctx, authenticatedUserID, targetUserID); err != nil {
 				if !request.IsErrorResourceNotFound(err) {
 					return "", errors.Wrap(err, "unable to get user permissions")
 				}
-			} else {
-				permissions = permission.FixOwnerPermissions(permissions)
-				if _, ok := permissions[authorizedPermission]; ok {
-					return authenticatedUserID, nil
-				}
+			} else if _, ok := permissions[authorizedPermission]; ok {
+				return authenticatedUserID, nil
 			}
 		}
 	}
@@ -283,6 +407,29 @@ func (e *External) EnsureAuthorizedUser(ctx context.Context, targetUserID string
 	return "", request.ErrorUnauthorized()
 }
 
+func (e *External) getUserPermissions(ctx context.Context, requestUserID string, targetUserID string) (permission.Permissions, error) {
+	key := permission.CacheKey(requestUserID, targetUserID)
+	if value, ok := e.cache.Get(key); ok {
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+		return value.(permission.Permissions), nil
+	}
+
+	url := e.client.ConstructURL("access", targetUserID, requestUserID)
+	permissions := permission.Permissions{}
+	if err := e.client.RequestData(ctx, "GET", url, nil, nil, &permissions); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			e.cache.SetError(key, err, requestUserID, targetUserID)
+		}
+		return nil, err
+	}
+
+	permissions = permission.FixOwnerPermissions(permissions)
+	e.cache.Set(key, permissions, requestUserID, targetUserID)
+	return permissions, nil
+}
+
 func (e *External) timeoutServerSessionToken(serverSessionTokenTimeout time.Duration) time.Duration {
 	if err := e.refreshServerSessionToken(); err != nil {
 		if serverSessionTokenTimeout == 0 || serverSessionTokenTimeout == e.serverSessionTokenTimeout {
@@ -349,3 +496,64 @@ func (e *External) serverSessionToken() string {
 
 	return e.serverSessionTokenSafe
 }
+
+// dequeueAuditEvents appends any queued audit events, up to the maximum that can be created at once
+func (e *External) dequeueAuditEvents(events auth.AuditEvents) auth.AuditEvents {
+	for len(events) < auth.AuditEventsLengthMaximum {
+		select {
+		case event := <-e.auditEventsChannel:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+// sendAuditEvents creates the audit events with the audit event sink, retrying on failure. If every attempt fails,
+// then each audit event is logged in full so that it is still recorded.
+func (e *External) sendAuditEvents(events auth.AuditEvents) {
+	var err error
+	retryDelay := AuditEventsRetryDelayFirst
+	for attempt := 1; attempt <= AuditEventsAttemptsMaximum; attempt++ {
+		if err = e.createAuditEvents(events); err == nil {
+			return
+		}
+
+		e.logger.WithError(err).WithField("attempt", attempt).Warn("Unable to create audit events")
+		if attempt < AuditEventsAttemptsMaximum {
+			time.Sleep(retryDelay)
+			retryDelay *= 2
+		}
+	}
+
+	for _, event := range events {
+		e.logger.WithError(err).WithField("auditEvent", event).Error("Audit event not created")
+	}
+}
+
+func (e *External) createAuditEvents(events auth.AuditEvents) error {
+	ctx, cancel := context.WithTimeout(log.NewContextWithLogger(context.Background(), e.logger), AuditEventsTimeout)
+	defer cancel()
+
+	if serverSessionToken := e.serverSessionToken(); serverSessionToken != "" {
+		ctx = auth.NewContextWithServerSessionToken(ctx, serverSessionToken)
+	}
+
+	return e.auditEventSink.CreateAuditEvents(ctx, events)
+}
+
+type loggerAuditEventSink struct {
+	logger log.Logger
+}
+
+func (l *loggerAuditEventSink) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	for _, event := range events {
+		l.logger.WithField("auditEvent", event).Info("Audit event")
+	}
+	return nil
+}
+
+func sessionTokenCacheKey(token string) string {
+	return "sessionToken:" + token
+}
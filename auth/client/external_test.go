@@ -3,6 +3,7 @@ package client_test
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -70,11 +71,73 @@ var _ = Describe("External", func() {
 			Expect(client).To(BeNil())
 		})
 
+		It("returns an error when the cache config is invalid", func() {
+			config.Cache.Size = -1
+			client, err := authClient.NewExternal(config, authorizeAs, name, logger)
+			Expect(err).To(MatchError("config is invalid; cache is invalid; size is invalid"))
+			Expect(client).To(BeNil())
+		})
+
 		It("returns success", func() {
 			Expect(authClient.NewExternal(config, authorizeAs, name, logger)).ToNot(BeNil())
 		})
 	})
 
+	Context("RecordAuditEvent", func() {
+		var sink *auditEventSink
+		var client *authClient.External
+		var ctx context.Context
+
+		BeforeEach(func() {
+			config.Address = testHttp.NewAddress()
+			sink = &auditEventSink{}
+			var err error
+			client, err = authClient.NewExternal(config, authorizeAs, name, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client).ToNot(BeNil())
+			client.SetAuditEventSink(sink)
+			ctx = log.NewContextWithLogger(context.Background(), logger)
+		})
+
+		AfterEach(func() {
+			client.Close()
+		})
+
+		It("sends the audit event synchronously when the client is not started", func() {
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, authTest.RandomUserID(), auth.AuditEventResultAllowed)
+			client.RecordAuditEvent(ctx, event)
+			Expect(sink.Events()).To(Equal(auth.AuditEvents{event}))
+		})
+
+		It("sends the audit event synchronously when the queue is full", func() {
+			sink.blockingChannel = make(chan bool)
+			Expect(client.Start()).To(Succeed())
+
+			client.RecordAuditEvent(ctx, auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, authTest.RandomUserID(), auth.AuditEventResultAllowed))
+			Eventually(sink.Blocked).Should(BeTrue())
+			for index := 0; index < authClient.AuditEventsQueueLength; index++ {
+				client.RecordAuditEvent(ctx, auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, authTest.RandomUserID(), auth.AuditEventResultAllowed))
+			}
+
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, authTest.RandomUserID(), auth.AuditEventResultAllowed)
+			client.RecordAuditEvent(ctx, event)
+			Expect(sink.Events()).To(Equal(auth.AuditEvents{event}))
+
+			close(sink.blockingChannel)
+			client.Close()
+			Expect(sink.Events()).To(HaveLen(authClient.AuditEventsQueueLength + 2))
+		})
+
+		It("retries the sink and logs the audit event when every attempt fails", func() {
+			sink.err = errorsTest.RandomError()
+			event := auth.NewAuditEvent(ctx, auth.AuditEventTypeAuthorization, authTest.RandomUserID(), auth.AuditEventResultAllowed)
+			client.RecordAuditEvent(ctx, event)
+			Expect(sink.Attempts()).To(Equal(authClient.AuditEventsAttemptsMaximum))
+			Expect(sink.Events()).To(BeEmpty())
+			logger.AssertError("Audit event not created", log.Fields{"auditEvent": event})
+		})
+	})
+
 	Context("with server and new client", func() {
 		var server *Server
 		var requestHandlers []http.HandlerFunc
@@ -297,6 +360,14 @@ var _ = Describe("External", func() {
 						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
 						Expect(userID).To(Equal(""))
 					})
+
+					It("returns an error from the cache without a second request", func() {
+						_, err := client.EnsureAuthorizedUser(ctx, targetUserID, authorizedPermission)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
+						userID, err := client.EnsureAuthorizedUser(ctx, targetUserID, authorizedPermission)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
+						Expect(userID).To(Equal(""))
+					})
 				})
 
 				Context("with a successful response, but with no permissions", func() {
@@ -333,8 +404,59 @@ var _ = Describe("External", func() {
 						authorizedPermission = permission.Write
 						Expect(client.EnsureAuthorizedUser(ctx, targetUserID, authorizedPermission)).To(Equal(requestUserID))
 					})
+
+					It("returns successfully with cached permissions without a second request", func() {
+						Expect(client.EnsureAuthorizedUser(ctx, targetUserID, permission.Write)).To(Equal(requestUserID))
+						Expect(client.EnsureAuthorizedUser(ctx, targetUserID, permission.Read)).To(Equal(requestUserID))
+					})
 				})
 			})
 		})
 	})
 })
+
+type auditEventSink struct {
+	mutex           sync.Mutex
+	blockingChannel chan bool
+	blocked         bool
+	err             error
+	attempts        int
+	events          auth.AuditEvents
+}
+
+func (a *auditEventSink) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	a.mutex.Lock()
+	blockingChannel := a.blockingChannel
+	if blockingChannel != nil && !a.blocked {
+		a.blocked = true
+		a.mutex.Unlock()
+		<-blockingChannel
+		a.mutex.Lock()
+	}
+	defer a.mutex.Unlock()
+
+	a.attempts++
+	if a.err != nil {
+		return a.err
+	}
+	a.events = append(a.events, events...)
+	return nil
+}
+
+func (a *auditEventSink) Blocked() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.blocked
+}
+
+func (a *auditEventSink) Attempts() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.attempts
+}
+
+func (a *auditEventSink) Events() auth.AuditEvents {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append(auth.AuditEvents{}, a.events...)
+}
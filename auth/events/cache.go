@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+
+	ev "github.com/tidepool-org/go-common/events"
+
+	"github.com/tidepool-org/platform/log"
+)
+
+// UserCacheInvalidator removes any locally cached session tokens or permissions related to a user. Caches are local to
+// each replica, so the invalidation handler must run on a broadcast runner (see events.NewBroadcastRunner).
+type UserCacheInvalidator interface {
+	InvalidateUser(userID string)
+}
+
+type userCacheInvalidationEventsHandler struct {
+	ev.NoopUserEventsHandler
+
+	ctx          context.Context
+	invalidators []UserCacheInvalidator
+}
+
+func NewUserCacheInvalidationHandler(ctx context.Context, invalidators ...UserCacheInvalidator) ev.EventHandler {
+	return ev.NewUserEventsHandler(&userCacheInvalidationEventsHandler{
+		ctx:          ctx,
+		invalidators: invalidators,
+	})
+}
+
+func (u *userCacheInvalidationEventsHandler) HandleUpdateUserEvent(payload ev.UpdateUserEvent) error {
+	u.invalidateUser(payload.Original.UserID)
+	return nil
+}
+
+func (u *userCacheInvalidationEventsHandler) HandleDeleteUserEvent(payload ev.DeleteUserEvent) error {
+	u.invalidateUser(payload.UserID)
+	return nil
+}
+
+func (u *userCacheInvalidationEventsHandler) invalidateUser(userID string) {
+	if userID == "" {
+		return
+	}
+
+	log.LoggerFromContext(u.ctx).WithField("userId", userID).Debug("Invalidating cache for user")
+	for _, invalidator := range u.invalidators {
+		invalidator.InvalidateUser(userID)
+	}
+}
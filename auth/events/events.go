@@ -34,6 +34,18 @@ func (u *userDeletionEventsHandler) HandleDeleteUserEvent(payload ev.DeleteUserE
 		logger.WithError(err).Error("unable to delete restricted tokens for user")
 	}
 
+	logger.Infof("Deleting personal access tokens for user")
+	if err := u.client.DeleteAllPersonalAccessTokens(u.ctx, payload.UserID); err != nil {
+		errs = append(errs, err)
+		logger.WithError(err).Error("unable to delete personal access tokens for user")
+	}
+
+	logger.Infof("Deleting oauth consents for user")
+	if err := u.client.DeleteAllOAuthConsents(u.ctx, payload.UserID); err != nil {
+		errs = append(errs, err)
+		logger.WithError(err).Error("unable to delete oauth consents for user")
+	}
+
 	logger.Infof("Deleting provider sessions for user")
 	if err := u.client.DeleteAllProviderSessions(u.ctx, payload.UserID); err != nil {
 		errs = append(errs, err)
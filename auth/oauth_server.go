@@ -0,0 +1,529 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tidepool-org/platform/crypto"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/user"
+)
+
+const (
+	OAuthScopeDataRead        = "data/read"
+	OAuthScopeDataWrite       = "data/write"
+	OAuthScopeDataSourcesRead = "data_sources/read"
+
+	OAuthGrantTypeAuthorizationCode = "authorization_code"
+	OAuthGrantTypeRefreshToken      = "refresh_token"
+
+	OAuthResponseTypeCode        = "code"
+	OAuthCodeChallengeMethodS256 = "S256"
+	OAuthTokenTypeBearer         = "Bearer"
+
+	OAuthClientNameLengthMaximum         = 100
+	OAuthClientRedirectURIsLengthMaximum = 10
+	OAuthStateLengthMaximum              = 500
+
+	OAuthAuthorizationCodeExpirationDuration = 10 * time.Minute
+	OAuthAccessTokenExpirationDuration       = time.Hour
+	OAuthRefreshTokenExpirationDuration      = 90 * 24 * time.Hour
+
+	OAuthClientSecretPrefix = "tpocs_"
+	OAuthAccessTokenPrefix  = "tpoat_"
+	OAuthRefreshTokenPrefix = "tport_"
+)
+
+type OAuthAccessor interface {
+	// DeleteAllOAuthConsents deletes the consents of the user and all tokens issued under them
+	DeleteAllOAuthConsents(ctx context.Context, userID string) error
+
+	// IntrospectOAuthToken returns the introspection of the access token, which is not active if it is unknown,
+	// revoked, or expired
+	IntrospectOAuthToken(ctx context.Context, token string) (*OAuthTokenIntrospection, error)
+}
+
+func OAuthScopes() []string {
+	return []string{
+		OAuthScopeDataRead,
+		OAuthScopeDataWrite,
+		OAuthScopeDataSourcesRead,
+	}
+}
+
+// OAuthScopeRestrictedTokenScopes returns the requests authorized by the scope. Paths with the user id parameter are
+// limited to the user that authorized the token; other paths are limited by the permissions of that user.
+func OAuthScopeRestrictedTokenScopes(scope string) RestrictedTokenScopes {
+	get := []string{http.MethodGet}
+	write := []string{http.MethodPost, http.MethodPut}
+
+	switch scope {
+	case OAuthScopeDataRead:
+		return RestrictedTokenScopes{
+			{Methods: pointer.FromStringArray(get), Path: pointer.FromString("/data/:userId")},
+			{Methods: pointer.FromStringArray(get), Path: pointer.FromString("/v1/users/:userId/data_sets")},
+			{Methods: pointer.FromStringArray(get), Path: pointer.FromString("/v1/users/:userId/datasets")},
+			{Methods: pointer.FromStringArray(get), Path: pointer.FromString("/v1/summaries/:userId")},
+		}
+	case OAuthScopeDataWrite:
+		return RestrictedTokenScopes{
+			{Methods: pointer.FromStringArray(write), Path: pointer.FromString("/v1/users/:userId/data_sets")},
+			{Methods: pointer.FromStringArray(write), Path: pointer.FromString("/v1/users/:userId/datasets")},
+			{Methods: pointer.FromStringArray(write), Path: pointer.FromString("/v1/data_sets")},
+			{Methods: pointer.FromStringArray(write), Path: pointer.FromString("/v1/datasets")},
+		}
+	case OAuthScopeDataSourcesRead:
+		return RestrictedTokenScopes{
+			{Methods: pointer.FromStringArray(get), Path: pointer.FromString("/v1/users/:userId/data_sources")},
+		}
+	}
+	return nil
+}
+
+// ParseOAuthScope returns the sorted, unique scopes of the space-delimited scope parameter
+func ParseOAuthScope(value string) []string {
+	unique := map[string]bool{}
+	for _, scope := range strings.Fields(value) {
+		unique[scope] = true
+	}
+	scopes := make([]string, 0, len(unique))
+	for scope := range unique {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func FormatOAuthScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+func OAuthScopeValidator(value string, errorReporter structure.ErrorReporter) {
+	scopes := ParseOAuthScope(value)
+	if len(scopes) == 0 {
+		errorReporter.ReportError(structureValidator.ErrorValueEmpty())
+	}
+	for _, scope := range scopes {
+		if !containsString(OAuthScopes(), scope) {
+			errorReporter.ReportError(structureValidator.ErrorValueStringNotOneOf(scope, OAuthScopes()))
+		}
+	}
+}
+
+func OAuthRedirectURIValidator(value string, errorReporter structure.ErrorReporter) {
+	if parsed, err := url.Parse(value); err != nil || !parsed.IsAbs() || parsed.Host == "" || parsed.Fragment != "" {
+		errorReporter.ReportError(structureValidator.ErrorValueNotValid())
+	}
+}
+
+// NewOAuthSecret returns a new secret, recognizable by its prefix
+func NewOAuthSecret(prefix string) string {
+	return prefix + id.Must(id.New(32))
+}
+
+func IsOAuthAccessToken(value string) bool {
+	return strings.HasPrefix(value, OAuthAccessTokenPrefix)
+}
+
+func IsOAuthRefreshToken(value string) bool {
+	return strings.HasPrefix(value, OAuthRefreshTokenPrefix)
+}
+
+// HashOAuthSecret returns the hash of the secret, which is the only form stored
+func HashOAuthSecret(secret string) string {
+	return crypto.HexEncodedSHA256Hash(secret)
+}
+
+// OAuthClientCreate registers a third-party app. A confidential client is issued a secret that it must present with
+// each token request; all clients must use PKCE.
+type OAuthClientCreate struct {
+	OwnerUserID  *string   `json:"ownerUserId,omitempty"`
+	Name         *string   `json:"name,omitempty"`
+	RedirectURIs *[]string `json:"redirectUris,omitempty"`
+	Scopes       *[]string `json:"scopes,omitempty"`
+	Confidential *bool     `json:"confidential,omitempty"`
+}
+
+func NewOAuthClientCreate() *OAuthClientCreate {
+	return &OAuthClientCreate{}
+}
+
+func (o *OAuthClientCreate) Parse(parser structure.ObjectParser) {
+	o.OwnerUserID = parser.String("ownerUserId")
+	o.Name = parser.String("name")
+	o.RedirectURIs = parser.StringArray("redirectUris")
+	o.Scopes = parser.StringArray("scopes")
+	o.Confidential = parser.Bool("confidential")
+}
+
+func (o *OAuthClientCreate) Validate(validator structure.Validator) {
+	validator.String("ownerUserId", o.OwnerUserID).Using(user.IDValidator)
+	validator.String("name", o.Name).Exists().NotEmpty().LengthLessThanOrEqualTo(OAuthClientNameLengthMaximum)
+	validator.StringArray("redirectUris", o.RedirectURIs).Exists().NotEmpty().LengthLessThanOrEqualTo(OAuthClientRedirectURIsLengthMaximum).EachUsing(OAuthRedirectURIValidator).EachUnique()
+	validator.StringArray("scopes", o.Scopes).Exists().NotEmpty().EachOneOf(OAuthScopes()...).EachUnique()
+}
+
+// OAuthClient is a registered third-party app, optionally owned by the user who develops it. The secret is only
+// returned when the client is created; only its hash is stored.
+type OAuthClient struct {
+	ID           string    `json:"id" bson:"id"`
+	OwnerUserID  *string   `json:"ownerUserId,omitempty" bson:"ownerUserId,omitempty"`
+	Name         string    `json:"name" bson:"name"`
+	RedirectURIs []string  `json:"redirectUris" bson:"redirectUris"`
+	Scopes       []string  `json:"scopes" bson:"scopes"`
+	Confidential bool      `json:"confidential" bson:"confidential"`
+	Secret       *string   `json:"secret,omitempty" bson:"-"`
+	SecretHash   string    `json:"-" bson:"secretHash,omitempty"`
+	CreatedTime  time.Time `json:"createdTime" bson:"createdTime"`
+}
+
+func NewOAuthClient(create *OAuthClientCreate) (*OAuthClient, error) {
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	client := &OAuthClient{
+		ID:           id.Must(id.New(16)),
+		OwnerUserID:  create.OwnerUserID,
+		Name:         *create.Name,
+		RedirectURIs: *create.RedirectURIs,
+		Scopes:       ParseOAuthScope(FormatOAuthScope(*create.Scopes)),
+		Confidential: create.Confidential != nil && *create.Confidential,
+		CreatedTime:  time.Now(),
+	}
+	if client.Confidential {
+		secret := NewOAuthSecret(OAuthClientSecretPrefix)
+		client.Secret = pointer.FromString(secret)
+		client.SecretHash = HashOAuthSecret(secret)
+	}
+	return client, nil
+}
+
+// AuthenticatesSecret returns true if the client is public and no secret is presented, or if the client is
+// confidential and the secret matches
+func (o *OAuthClient) AuthenticatesSecret(secret string) bool {
+	if !o.Confidential {
+		return secret == ""
+	}
+	return secret != "" && subtle.ConstantTimeCompare([]byte(HashOAuthSecret(secret)), []byte(o.SecretHash)) == 1
+}
+
+// HasRedirectURI requires an exact match of a registered redirect uri
+func (o *OAuthClient) HasRedirectURI(redirectURI string) bool {
+	return containsString(o.RedirectURIs, redirectURI)
+}
+
+func (o *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsString(o.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// OAuthAuthorize is sent by the consent screen, with the parameters of the authorization request, once the user
+// consents to the third-party app
+type OAuthAuthorize struct {
+	ResponseType        *string `json:"responseType,omitempty"`
+	ClientID            *string `json:"clientId,omitempty"`
+	RedirectURI         *string `json:"redirectUri,omitempty"`
+	Scope               *string `json:"scope,omitempty"`
+	State               *string `json:"state,omitempty"`
+	CodeChallenge       *string `json:"codeChallenge,omitempty"`
+	CodeChallengeMethod *string `json:"codeChallengeMethod,omitempty"`
+}
+
+func NewOAuthAuthorize() *OAuthAuthorize {
+	return &OAuthAuthorize{}
+}
+
+func (o *OAuthAuthorize) Parse(parser structure.ObjectParser) {
+	o.ResponseType = parser.String("responseType")
+	o.ClientID = parser.String("clientId")
+	o.RedirectURI = parser.String("redirectUri")
+	o.Scope = parser.String("scope")
+	o.State = parser.String("state")
+	o.CodeChallenge = parser.String("codeChallenge")
+	o.CodeChallengeMethod = parser.String("codeChallengeMethod")
+}
+
+func (o *OAuthAuthorize) Validate(validator structure.Validator) {
+	validator.String("responseType", o.ResponseType).Exists().EqualTo(OAuthResponseTypeCode)
+	validator.String("clientId", o.ClientID).Exists().NotEmpty()
+	validator.String("redirectUri", o.RedirectURI).Exists().Using(OAuthRedirectURIValidator)
+	validator.String("scope", o.Scope).Exists().Using(OAuthScopeValidator)
+	validator.String("state", o.State).LengthLessThanOrEqualTo(OAuthStateLengthMaximum)
+	validator.String("codeChallenge", o.CodeChallenge).Exists().LengthInRange(43, 128)
+	validator.String("codeChallengeMethod", o.CodeChallengeMethod).Exists().EqualTo(OAuthCodeChallengeMethodS256)
+}
+
+// OAuthAuthorizationCode is issued to the redirect uri and may be exchanged once, before it expires, by the client
+// that presents the matching PKCE code verifier. Only the hash of the code is stored.
+type OAuthAuthorizationCode struct {
+	Code           *string   `json:"-" bson:"-"`
+	CodeHash       string    `json:"-" bson:"codeHash"`
+	ClientID       string    `json:"clientId" bson:"clientId"`
+	UserID         string    `json:"userId" bson:"userId"`
+	RedirectURI    string    `json:"redirectUri" bson:"redirectUri"`
+	Scopes         []string  `json:"scopes" bson:"scopes"`
+	CodeChallenge  string    `json:"-" bson:"codeChallenge"`
+	ExpirationTime time.Time `json:"expirationTime" bson:"expirationTime"`
+	CreatedTime    time.Time `json:"createdTime" bson:"createdTime"`
+}
+
+func NewOAuthAuthorizationCode(userID string, authorize *OAuthAuthorize) (*OAuthAuthorizationCode, error) {
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if authorize == nil {
+		return nil, errors.New("authorize is missing")
+	} else if err := structureValidator.New().Validate(authorize); err != nil {
+		return nil, errors.Wrap(err, "authorize is invalid")
+	}
+
+	now := time.Now()
+	code := id.Must(id.New(32))
+	return &OAuthAuthorizationCode{
+		Code:           pointer.FromString(code),
+		CodeHash:       HashOAuthSecret(code),
+		ClientID:       *authorize.ClientID,
+		UserID:         userID,
+		RedirectURI:    *authorize.RedirectURI,
+		Scopes:         ParseOAuthScope(*authorize.Scope),
+		CodeChallenge:  *authorize.CodeChallenge,
+		ExpirationTime: now.Add(OAuthAuthorizationCodeExpirationDuration),
+		CreatedTime:    now,
+	}, nil
+}
+
+// VerifiesCodeVerifier returns true if the S256 code challenge was calculated from the code verifier
+func (o *OAuthAuthorizationCode) VerifiesCodeVerifier(codeVerifier string) bool {
+	if len(codeVerifier) < 43 || len(codeVerifier) > 128 {
+		return false
+	}
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(hash[:])), []byte(o.CodeChallenge)) == 1
+}
+
+// OAuthTokenRequest is the form sent by the client to the token endpoint
+type OAuthTokenRequest struct {
+	GrantType    *string
+	Code         *string
+	RedirectURI  *string
+	CodeVerifier *string
+	RefreshToken *string
+	ClientID     *string
+	ClientSecret *string
+}
+
+func NewOAuthTokenRequest() *OAuthTokenRequest {
+	return &OAuthTokenRequest{}
+}
+
+func (o *OAuthTokenRequest) Parse(parser structure.ObjectParser) {
+	o.GrantType = parser.String("grant_type")
+	o.Code = parser.String("code")
+	o.RedirectURI = parser.String("redirect_uri")
+	o.CodeVerifier = parser.String("code_verifier")
+	o.RefreshToken = parser.String("refresh_token")
+	o.ClientID = parser.String("client_id")
+	o.ClientSecret = parser.String("client_secret")
+}
+
+func (o *OAuthTokenRequest) Validate(validator structure.Validator) {
+	validator.String("grant_type", o.GrantType).Exists().OneOf(OAuthGrantTypeAuthorizationCode, OAuthGrantTypeRefreshToken)
+	validator.String("client_id", o.ClientID).Exists().NotEmpty()
+	if o.GrantType != nil && *o.GrantType == OAuthGrantTypeAuthorizationCode {
+		validator.String("code", o.Code).Exists().NotEmpty()
+		validator.String("redirect_uri", o.RedirectURI).Exists().NotEmpty()
+		validator.String("code_verifier", o.CodeVerifier).Exists().NotEmpty()
+	} else if o.GrantType != nil && *o.GrantType == OAuthGrantTypeRefreshToken {
+		validator.String("refresh_token", o.RefreshToken).Exists().NotEmpty()
+	}
+}
+
+// OAuthTokenResponse is returned by the token endpoint
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthToken is an access token and refresh token pair issued to a client on behalf of a user. Only the hashes of the
+// tokens are stored. A refresh token may be used once; using it revokes the pair and issues a new pair.
+type OAuthToken struct {
+	ID                         string     `json:"id" bson:"id"`
+	ClientID                   string     `json:"clientId" bson:"clientId"`
+	UserID                     string     `json:"userId" bson:"userId"`
+	Scopes                     []string   `json:"scopes" bson:"scopes"`
+	AccessToken                *string    `json:"-" bson:"-"`
+	AccessTokenHash            string     `json:"-" bson:"accessTokenHash"`
+	AccessTokenExpirationTime  time.Time  `json:"accessTokenExpirationTime" bson:"accessTokenExpirationTime"`
+	RefreshToken               *string    `json:"-" bson:"-"`
+	RefreshTokenHash           string     `json:"-" bson:"refreshTokenHash"`
+	RefreshTokenExpirationTime time.Time  `json:"refreshTokenExpirationTime" bson:"refreshTokenExpirationTime"`
+	RevokedTime                *time.Time `json:"revokedTime,omitempty" bson:"revokedTime,omitempty"`
+	CreatedTime                time.Time  `json:"createdTime" bson:"createdTime"`
+}
+
+func NewOAuthToken(clientID string, userID string, scopes []string) (*OAuthToken, error) {
+	if clientID == "" {
+		return nil, errors.New("client id is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("scopes are missing")
+	}
+
+	now := time.Now()
+	accessToken := NewOAuthSecret(OAuthAccessTokenPrefix)
+	refreshToken := NewOAuthSecret(OAuthRefreshTokenPrefix)
+	return &OAuthToken{
+		ID:                         id.Must(id.New(16)),
+		ClientID:                   clientID,
+		UserID:                     userID,
+		Scopes:                     scopes,
+		AccessToken:                pointer.FromString(accessToken),
+		AccessTokenHash:            HashOAuthSecret(accessToken),
+		AccessTokenExpirationTime:  now.Add(OAuthAccessTokenExpirationDuration),
+		RefreshToken:               pointer.FromString(refreshToken),
+		RefreshTokenHash:           HashOAuthSecret(refreshToken),
+		RefreshTokenExpirationTime: now.Add(OAuthRefreshTokenExpirationDuration),
+		CreatedTime:                now,
+	}, nil
+}
+
+func (o *OAuthToken) Response() *OAuthTokenResponse {
+	response := &OAuthTokenResponse{
+		TokenType: OAuthTokenTypeBearer,
+		ExpiresIn: int(time.Until(o.AccessTokenExpirationTime) / time.Second),
+		Scope:     FormatOAuthScope(o.Scopes),
+	}
+	if o.AccessToken != nil {
+		response.AccessToken = *o.AccessToken
+	}
+	if o.RefreshToken != nil {
+		response.RefreshToken = *o.RefreshToken
+	}
+	return response
+}
+
+func (o *OAuthToken) Introspection() *OAuthTokenIntrospection {
+	if o.RevokedTime != nil || !time.Now().Before(o.AccessTokenExpirationTime) {
+		return &OAuthTokenIntrospection{}
+	}
+	return &OAuthTokenIntrospection{
+		Active:         true,
+		Scope:          pointer.FromString(FormatOAuthScope(o.Scopes)),
+		ClientID:       pointer.FromString(o.ClientID),
+		UserID:         pointer.FromString(o.UserID),
+		TokenType:      pointer.FromString(OAuthTokenTypeBearer),
+		ExpirationTime: pointer.FromInt64(o.AccessTokenExpirationTime.Unix()),
+		IssuedTime:     pointer.FromInt64(o.CreatedTime.Unix()),
+	}
+}
+
+// OAuthTokenIntrospect is sent by a service to introspect an access token on behalf of a request
+type OAuthTokenIntrospect struct {
+	Token *string `json:"token,omitempty"`
+}
+
+func NewOAuthTokenIntrospect() *OAuthTokenIntrospect {
+	return &OAuthTokenIntrospect{}
+}
+
+func (o *OAuthTokenIntrospect) Parse(parser structure.ObjectParser) {
+	o.Token = parser.String("token")
+}
+
+func (o *OAuthTokenIntrospect) Validate(validator structure.Validator) {
+	validator.String("token", o.Token).Exists().NotEmpty()
+}
+
+// OAuthTokenIntrospection is the token introspection response (RFC 7662); only active is returned if the token is not
+// active
+type OAuthTokenIntrospection struct {
+	Active         bool    `json:"active"`
+	Scope          *string `json:"scope,omitempty"`
+	ClientID       *string `json:"client_id,omitempty"`
+	UserID         *string `json:"sub,omitempty"`
+	TokenType      *string `json:"token_type,omitempty"`
+	ExpirationTime *int64  `json:"exp,omitempty"`
+	IssuedTime     *int64  `json:"iat,omitempty"`
+}
+
+func (o *OAuthTokenIntrospection) Authenticates(req *http.Request) bool {
+	if req == nil || req.URL == nil {
+		return false
+	}
+	if !o.Active || o.UserID == nil || o.Scope == nil {
+		return false
+	}
+	if o.ExpirationTime == nil || !time.Now().Before(time.Unix(*o.ExpirationTime, 0)) {
+		return false
+	}
+	for _, scope := range ParseOAuthScope(*o.Scope) {
+		for _, restrictedTokenScope := range OAuthScopeRestrictedTokenScopes(scope) {
+			if restrictedTokenScope.Authenticates(req, *o.UserID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OAuthConsent records the scopes the user has granted to a client. Deleting the consent revokes all tokens issued
+// to the client on behalf of the user.
+type OAuthConsent struct {
+	UserID       string    `json:"userId" bson:"userId"`
+	ClientID     string    `json:"clientId" bson:"clientId"`
+	Scopes       []string  `json:"scopes" bson:"scopes"`
+	CreatedTime  time.Time `json:"createdTime" bson:"createdTime"`
+	ModifiedTime time.Time `json:"modifiedTime" bson:"modifiedTime"`
+}
+
+func (o *OAuthConsent) Sanitize(details request.Details) error {
+	if details != nil && (details.IsService() || details.UserID() == o.UserID) {
+		return nil
+	}
+	return errors.New("unable to sanitize")
+}
+
+type OAuthConsents []*OAuthConsent
+
+func (o OAuthConsents) Sanitize(details request.Details) error {
+	for _, consent := range o {
+		if err := consent.Sanitize(details); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
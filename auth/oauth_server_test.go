@@ -0,0 +1,229 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	userTest "github.com/tidepool-org/platform/user/test"
+)
+
+var _ = Describe("OAuthServer", func() {
+	Context("ParseOAuthScope", func() {
+		It("returns sorted unique scopes", func() {
+			Expect(auth.ParseOAuthScope(" data/write  data/read data/write ")).To(Equal([]string{"data/read", "data/write"}))
+		})
+
+		It("returns empty scopes if the scope is empty", func() {
+			Expect(auth.ParseOAuthScope("")).To(BeEmpty())
+		})
+	})
+
+	Context("OAuthClientCreate", func() {
+		var create *auth.OAuthClientCreate
+
+		BeforeEach(func() {
+			create = auth.NewOAuthClientCreate()
+			create.Name = pointer.FromString("Research App")
+			create.RedirectURIs = pointer.FromStringArray([]string{"https://example.com/callback"})
+			create.Scopes = pointer.FromStringArray([]string{auth.OAuthScopeDataRead})
+		})
+
+		It("is valid", func() {
+			Expect(structureValidator.New().Validate(create)).To(Succeed())
+		})
+
+		It("is invalid if a redirect uri is relative", func() {
+			create.RedirectURIs = pointer.FromStringArray([]string{"/callback"})
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is invalid if a redirect uri has a fragment", func() {
+			create.RedirectURIs = pointer.FromStringArray([]string{"https://example.com/callback#fragment"})
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is invalid if a scope is unknown", func() {
+			create.Scopes = pointer.FromStringArray([]string{"admin"})
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is valid with an owner user id", func() {
+			create.OwnerUserID = pointer.FromString(userTest.RandomID())
+			Expect(structureValidator.New().Validate(create)).To(Succeed())
+		})
+
+		It("is invalid if the owner user id is invalid", func() {
+			create.OwnerUserID = pointer.FromString("invalid")
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+	})
+
+	Context("NewOAuthClient", func() {
+		var create *auth.OAuthClientCreate
+
+		BeforeEach(func() {
+			create = auth.NewOAuthClientCreate()
+			create.Name = pointer.FromString("Research App")
+			create.RedirectURIs = pointer.FromStringArray([]string{"https://example.com/callback"})
+			create.Scopes = pointer.FromStringArray([]string{auth.OAuthScopeDataWrite, auth.OAuthScopeDataRead})
+		})
+
+		It("returns an error if the create is missing", func() {
+			client, err := auth.NewOAuthClient(nil)
+			Expect(err).To(MatchError("create is missing"))
+			Expect(client).To(BeNil())
+		})
+
+		It("returns a public client without a secret", func() {
+			client, err := auth.NewOAuthClient(create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.ID).To(HaveLen(32))
+			Expect(client.Scopes).To(Equal([]string{auth.OAuthScopeDataRead, auth.OAuthScopeDataWrite}))
+			Expect(client.Confidential).To(BeFalse())
+			Expect(client.Secret).To(BeNil())
+			Expect(client.AuthenticatesSecret("")).To(BeTrue())
+			Expect(client.AuthenticatesSecret("secret")).To(BeFalse())
+		})
+
+		It("returns a confidential client that stores only the hash of the secret", func() {
+			create.Confidential = pointer.FromBool(true)
+			client, err := auth.NewOAuthClient(create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Confidential).To(BeTrue())
+			Expect(client.Secret).ToNot(BeNil())
+			Expect(client.SecretHash).To(Equal(auth.HashOAuthSecret(*client.Secret)))
+			Expect(client.AuthenticatesSecret(*client.Secret)).To(BeTrue())
+			Expect(client.AuthenticatesSecret("")).To(BeFalse())
+			Expect(client.AuthenticatesSecret("secret")).To(BeFalse())
+		})
+
+		It("returns a client with the owner user id", func() {
+			create.OwnerUserID = pointer.FromString(userTest.RandomID())
+			client, err := auth.NewOAuthClient(create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.OwnerUserID).To(Equal(create.OwnerUserID))
+		})
+
+		It("allows only registered redirect uris and scopes", func() {
+			client, err := auth.NewOAuthClient(create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.HasRedirectURI("https://example.com/callback")).To(BeTrue())
+			Expect(client.HasRedirectURI("https://example.com/callback/other")).To(BeFalse())
+			Expect(client.AllowsScopes([]string{auth.OAuthScopeDataRead})).To(BeTrue())
+			Expect(client.AllowsScopes([]string{auth.OAuthScopeDataSourcesRead})).To(BeFalse())
+		})
+	})
+
+	Context("NewOAuthAuthorizationCode", func() {
+		var authorize *auth.OAuthAuthorize
+
+		BeforeEach(func() {
+			authorize = auth.NewOAuthAuthorize()
+			authorize.ResponseType = pointer.FromString(auth.OAuthResponseTypeCode)
+			authorize.ClientID = pointer.FromString("1234567890abcdef1234567890abcdef")
+			authorize.RedirectURI = pointer.FromString("https://example.com/callback")
+			authorize.Scope = pointer.FromString("data/read")
+			authorize.State = pointer.FromString("state")
+			authorize.CodeChallenge = pointer.FromString("VmobszQGMm2Ji92ow84rlotw0U_BCnHxRqB6ep-TCis")
+			authorize.CodeChallengeMethod = pointer.FromString(auth.OAuthCodeChallengeMethodS256)
+		})
+
+		It("returns an error if the user id is missing", func() {
+			code, err := auth.NewOAuthAuthorizationCode("", authorize)
+			Expect(err).To(MatchError("user id is missing"))
+			Expect(code).To(BeNil())
+		})
+
+		It("returns an error if the code challenge method is plain", func() {
+			authorize.CodeChallengeMethod = pointer.FromString("plain")
+			code, err := auth.NewOAuthAuthorizationCode("1234567890", authorize)
+			Expect(err).To(HaveOccurred())
+			Expect(code).To(BeNil())
+		})
+
+		It("returns an error if the scope is unknown", func() {
+			authorize.Scope = pointer.FromString("data/read admin")
+			code, err := auth.NewOAuthAuthorizationCode("1234567890", authorize)
+			Expect(err).To(HaveOccurred())
+			Expect(code).To(BeNil())
+		})
+
+		It("returns a code that verifies the matching code verifier", func() {
+			code, err := auth.NewOAuthAuthorizationCode("1234567890", authorize)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code.Code).ToNot(BeNil())
+			Expect(code.CodeHash).To(Equal(auth.HashOAuthSecret(*code.Code)))
+			Expect(code.Scopes).To(Equal([]string{auth.OAuthScopeDataRead}))
+			Expect(code.ExpirationTime).To(BeTemporally("~", time.Now().Add(auth.OAuthAuthorizationCodeExpirationDuration), time.Second))
+			Expect(code.VerifiesCodeVerifier("dBjftJeZ4CVP-mJ92K9qPNGuI5k9VT6ZmAvx0r7cSLk")).To(BeTrue())
+			Expect(code.VerifiesCodeVerifier("dBjftJeZ4CVP-mJ92K9qPNGuI5k9VT6ZmAvx0r7cSLQ")).To(BeFalse())
+			Expect(code.VerifiesCodeVerifier("")).To(BeFalse())
+		})
+	})
+
+	Context("OAuthToken", func() {
+		var token *auth.OAuthToken
+
+		BeforeEach(func() {
+			var err error
+			token, err = auth.NewOAuthToken("1234567890abcdef1234567890abcdef", "1234567890", []string{auth.OAuthScopeDataRead})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a response with the tokens", func() {
+			response := token.Response()
+			Expect(response.AccessToken).To(Equal(*token.AccessToken))
+			Expect(auth.IsOAuthAccessToken(response.AccessToken)).To(BeTrue())
+			Expect(response.RefreshToken).To(Equal(*token.RefreshToken))
+			Expect(auth.IsOAuthRefreshToken(response.RefreshToken)).To(BeTrue())
+			Expect(response.TokenType).To(Equal("Bearer"))
+			Expect(response.ExpiresIn).To(BeNumerically("~", 3600, 1))
+			Expect(response.Scope).To(Equal("data/read"))
+		})
+
+		It("stores only the hashes of the tokens", func() {
+			Expect(token.AccessTokenHash).To(Equal(auth.HashOAuthSecret(*token.AccessToken)))
+			Expect(token.RefreshTokenHash).To(Equal(auth.HashOAuthSecret(*token.RefreshToken)))
+		})
+
+		Context("Introspection", func() {
+			It("is active", func() {
+				introspection := token.Introspection()
+				Expect(introspection.Active).To(BeTrue())
+				Expect(introspection.UserID).To(Equal(pointer.FromString("1234567890")))
+				Expect(introspection.Scope).To(Equal(pointer.FromString("data/read")))
+			})
+
+			It("is not active if revoked", func() {
+				token.RevokedTime = pointer.FromTime(time.Now())
+				Expect(token.Introspection()).To(Equal(&auth.OAuthTokenIntrospection{}))
+			})
+
+			It("is not active if expired", func() {
+				token.AccessTokenExpirationTime = time.Now().Add(-time.Second)
+				Expect(token.Introspection()).To(Equal(&auth.OAuthTokenIntrospection{}))
+			})
+
+			It("authenticates requests to the data of the user within the scope", func() {
+				introspection := token.Introspection()
+				Expect(introspection.Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeTrue())
+				Expect(introspection.Authenticates(httptest.NewRequest(http.MethodGet, "/v1/users/1234567890/data_sets", nil))).To(BeTrue())
+				Expect(introspection.Authenticates(httptest.NewRequest(http.MethodGet, "/data/0987654321", nil))).To(BeFalse())
+				Expect(introspection.Authenticates(httptest.NewRequest(http.MethodPost, "/v1/users/1234567890/data_sets", nil))).To(BeFalse())
+				Expect(introspection.Authenticates(httptest.NewRequest(http.MethodGet, "/v1/users/1234567890/personal_access_tokens", nil))).To(BeFalse())
+			})
+
+			It("does not authenticate requests if not active", func() {
+				token.RevokedTime = pointer.FromTime(time.Now())
+				Expect(token.Introspection().Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidepool-org/platform/crypto"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	PersonalAccessTokenNameLengthMaximum = 100
+	PersonalAccessTokenPrefix            = "tpat_"
+)
+
+type PersonalAccessTokenAccessor interface {
+	ListUserPersonalAccessTokens(ctx context.Context, userID string, filter *PersonalAccessTokenFilter, pagination *page.Pagination) (PersonalAccessTokens, error)
+	CreateUserPersonalAccessToken(ctx context.Context, userID string, create *PersonalAccessTokenCreate) (*PersonalAccessToken, error)
+	DeleteAllPersonalAccessTokens(ctx context.Context, userID string) error
+
+	GetPersonalAccessToken(ctx context.Context, id string) (*PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, id string) (*PersonalAccessToken, error)
+
+	// AuthenticatePersonalAccessToken returns the personal access token matching the secret token and records its use. If
+	// there is no matching personal access token, or it is revoked or expired, then nil is returned.
+	AuthenticatePersonalAccessToken(ctx context.Context, token string) (*PersonalAccessToken, error)
+}
+
+// PersonalAccessTokenFilter filters by whether the token is revoked, if specified
+type PersonalAccessTokenFilter struct {
+	Revoked *bool `json:"revoked,omitempty"`
+}
+
+func NewPersonalAccessTokenFilter() *PersonalAccessTokenFilter {
+	return &PersonalAccessTokenFilter{}
+}
+
+func (p *PersonalAccessTokenFilter) Parse(parser structure.ObjectParser) {
+	p.Revoked = parser.Bool("revoked")
+}
+
+func (p *PersonalAccessTokenFilter) Validate(validator structure.Validator) {}
+
+func (p *PersonalAccessTokenFilter) MutateRequest(req *http.Request) error {
+	parameters := map[string]string{}
+	if p.Revoked != nil {
+		parameters["revoked"] = strconv.FormatBool(*p.Revoked)
+	}
+	return request.NewParametersMutator(parameters).MutateRequest(req)
+}
+
+// PersonalAccessTokenCreate authenticates requests matching any of the scopes. If the expiration time is not
+// specified, then the token does not expire.
+type PersonalAccessTokenCreate struct {
+	Name           *string                `json:"name,omitempty"`
+	Scopes         *RestrictedTokenScopes `json:"scopes,omitempty"`
+	ExpirationTime *time.Time             `json:"expirationTime,omitempty"`
+}
+
+func NewPersonalAccessTokenCreate() *PersonalAccessTokenCreate {
+	return &PersonalAccessTokenCreate{}
+}
+
+func (p *PersonalAccessTokenCreate) Parse(parser structure.ObjectParser) {
+	p.Name = parser.String("name")
+	p.Scopes = ParseRestrictedTokenScopes(parser.WithReferenceArrayParser("scopes"))
+	p.ExpirationTime = parser.Time("expirationTime", time.RFC3339Nano)
+}
+
+func (p *PersonalAccessTokenCreate) Validate(validator structure.Validator) {
+	validator.String("name", p.Name).Exists().NotEmpty().LengthLessThanOrEqualTo(PersonalAccessTokenNameLengthMaximum)
+	if scopesValidator := validator.WithReference("scopes"); p.Scopes != nil {
+		p.Scopes.Validate(scopesValidator)
+	} else {
+		scopesValidator.ReportError(structureValidator.ErrorValueNotExists())
+	}
+	validator.Time("expirationTime", p.ExpirationTime).AfterNow(0)
+}
+
+// PersonalAccessTokenAuthenticate is sent by a service to authenticate a secret token on behalf of a request
+type PersonalAccessTokenAuthenticate struct {
+	Token *string `json:"token,omitempty"`
+}
+
+func NewPersonalAccessTokenAuthenticate() *PersonalAccessTokenAuthenticate {
+	return &PersonalAccessTokenAuthenticate{}
+}
+
+func (p *PersonalAccessTokenAuthenticate) Parse(parser structure.ObjectParser) {
+	p.Token = parser.String("token")
+}
+
+func (p *PersonalAccessTokenAuthenticate) Validate(validator structure.Validator) {
+	validator.String("token", p.Token).Exists().NotEmpty()
+}
+
+func NewPersonalAccessTokenID() string {
+	return id.Must(id.New(16))
+}
+
+func IsValidPersonalAccessTokenID(value string) bool {
+	return ValidatePersonalAccessTokenID(value) == nil
+}
+
+func PersonalAccessTokenIDValidator(value string, errorReporter structure.ErrorReporter) {
+	errorReporter.ReportError(ValidatePersonalAccessTokenID(value))
+}
+
+func ValidatePersonalAccessTokenID(value string) error {
+	if value == "" {
+		return structureValidator.ErrorValueEmpty()
+	} else if !personalAccessTokenIDExpression.MatchString(value) {
+		return ErrorValueStringAsPersonalAccessTokenIDNotValid(value)
+	}
+	return nil
+}
+
+func ErrorValueStringAsPersonalAccessTokenIDNotValid(value string) error {
+	return errors.Preparedf(structureValidator.ErrorCodeValueNotValid, "value is not valid", "value %q is not valid as personal access token id", value)
+}
+
+var personalAccessTokenIDExpression = regexp.MustCompile("^[0-9a-z]{32}$")
+
+// NewPersonalAccessTokenSecret returns a new secret token, recognizable by its prefix
+func NewPersonalAccessTokenSecret() string {
+	return PersonalAccessTokenPrefix + id.Must(id.New(32))
+}
+
+func IsPersonalAccessTokenSecret(value string) bool {
+	return strings.HasPrefix(value, PersonalAccessTokenPrefix)
+}
+
+// HashPersonalAccessTokenSecret returns the hash of the secret token, which is the only form stored
+func HashPersonalAccessTokenSecret(token string) string {
+	return crypto.HexEncodedSHA256Hash(token)
+}
+
+// PersonalAccessToken is a long-lived user credential for scripts and integrations. The secret token is only returned
+// when the personal access token is created; only its hash is stored.
+type PersonalAccessToken struct {
+	ID             string                 `json:"id" bson:"id"`
+	UserID         string                 `json:"userId" bson:"userId"`
+	Name           string                 `json:"name" bson:"name"`
+	Scopes         *RestrictedTokenScopes `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	Token          *string                `json:"token,omitempty" bson:"-"`
+	TokenHash      string                 `json:"-" bson:"tokenHash"`
+	ExpirationTime *time.Time             `json:"expirationTime,omitempty" bson:"expirationTime,omitempty"`
+	LastUsedTime   *time.Time             `json:"lastUsedTime,omitempty" bson:"lastUsedTime,omitempty"`
+	RevokedTime    *time.Time             `json:"revokedTime,omitempty" bson:"revokedTime,omitempty"`
+	CreatedTime    time.Time              `json:"createdTime" bson:"createdTime"`
+}
+
+func NewPersonalAccessToken(userID string, create *PersonalAccessTokenCreate) (*PersonalAccessToken, error) {
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	token := NewPersonalAccessTokenSecret()
+	return &PersonalAccessToken{
+		ID:             NewPersonalAccessTokenID(),
+		UserID:         userID,
+		Name:           *create.Name,
+		Scopes:         create.Scopes,
+		Token:          pointer.FromString(token),
+		TokenHash:      HashPersonalAccessTokenSecret(token),
+		ExpirationTime: create.ExpirationTime,
+		CreatedTime:    time.Now(),
+	}, nil
+}
+
+func (p *PersonalAccessToken) Parse(parser structure.ObjectParser) {
+	if ptr := parser.String("id"); ptr != nil {
+		p.ID = *ptr
+	}
+	if ptr := parser.String("userId"); ptr != nil {
+		p.UserID = *ptr
+	}
+	if ptr := parser.String("name"); ptr != nil {
+		p.Name = *ptr
+	}
+	p.Scopes = ParseRestrictedTokenScopes(parser.WithReferenceArrayParser("scopes"))
+	p.Token = parser.String("token")
+	p.ExpirationTime = parser.Time("expirationTime", time.RFC3339Nano)
+	p.LastUsedTime = parser.Time("lastUsedTime", time.RFC3339Nano)
+	p.RevokedTime = parser.Time("revokedTime", time.RFC3339Nano)
+	if ptr := parser.Time("createdTime", time.RFC3339Nano); ptr != nil {
+		p.CreatedTime = *ptr
+	}
+}
+
+func (p *PersonalAccessToken) Validate(validator structure.Validator) {
+	validator.String("id", &p.ID).Using(PersonalAccessTokenIDValidator)
+	validator.String("userId", &p.UserID).Using(UserIDValidator)
+	validator.String("name", &p.Name).NotEmpty().LengthLessThanOrEqualTo(PersonalAccessTokenNameLengthMaximum)
+	if scopesValidator := validator.WithReference("scopes"); p.Scopes != nil {
+		p.Scopes.Validate(scopesValidator)
+	} else {
+		scopesValidator.ReportError(structureValidator.ErrorValueNotExists())
+	}
+	validator.Time("createdTime", &p.CreatedTime).NotZero().BeforeNow(time.Second)
+	validator.Time("lastUsedTime", p.LastUsedTime).After(p.CreatedTime).BeforeNow(time.Second)
+	validator.Time("revokedTime", p.RevokedTime).After(p.CreatedTime).BeforeNow(time.Second)
+}
+
+func (p *PersonalAccessToken) IsActive() bool {
+	return p.RevokedTime == nil && (p.ExpirationTime == nil || time.Now().Before(*p.ExpirationTime))
+}
+
+func (p *PersonalAccessToken) Authenticates(req *http.Request) bool {
+	if req == nil || req.URL == nil {
+		return false
+	}
+	if !p.IsActive() || p.Scopes == nil {
+		return false
+	}
+	for _, scope := range *p.Scopes {
+		if scope != nil && scope.Authenticates(req, p.UserID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PersonalAccessToken) Sanitize(details request.Details) error {
+	if details != nil && (details.IsService() || details.UserID() == p.UserID) {
+		return nil
+	}
+	return errors.New("unable to sanitize")
+}
+
+type PersonalAccessTokens []*PersonalAccessToken
+
+func (p PersonalAccessTokens) Sanitize(details request.Details) error {
+	for _, personalAccessToken := range p {
+		if err := personalAccessToken.Sanitize(details); err != nil {
+			return err
+		}
+	}
+	return nil
+}
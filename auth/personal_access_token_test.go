@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+var _ = Describe("PersonalAccessToken", func() {
+	Context("NewPersonalAccessTokenSecret", func() {
+		It("returns a prefixed secret", func() {
+			secret := auth.NewPersonalAccessTokenSecret()
+			Expect(secret).To(MatchRegexp("^tpat_[0-9a-f]{64}$"))
+			Expect(auth.IsPersonalAccessTokenSecret(secret)).To(BeTrue())
+		})
+
+		It("returns different secrets for each invocation", func() {
+			Expect(auth.NewPersonalAccessTokenSecret()).ToNot(Equal(auth.NewPersonalAccessTokenSecret()))
+		})
+	})
+
+	Context("NewPersonalAccessToken", func() {
+		var create *auth.PersonalAccessTokenCreate
+
+		BeforeEach(func() {
+			create = auth.NewPersonalAccessTokenCreate()
+			create.Name = pointer.FromString("Research Scripts")
+			create.Scopes = &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{http.MethodGet}), Path: pointer.FromString("/data/:userId")}}
+		})
+
+		It("returns an error if the user id is missing", func() {
+			personalAccessToken, err := auth.NewPersonalAccessToken("", create)
+			Expect(err).To(MatchError("user id is missing"))
+			Expect(personalAccessToken).To(BeNil())
+		})
+
+		It("returns an error if the create is missing", func() {
+			personalAccessToken, err := auth.NewPersonalAccessToken("1234567890", nil)
+			Expect(err).To(MatchError("create is missing"))
+			Expect(personalAccessToken).To(BeNil())
+		})
+
+		It("returns an error if the create does not have scopes", func() {
+			create.Scopes = nil
+			personalAccessToken, err := auth.NewPersonalAccessToken("1234567890", create)
+			Expect(err).To(MatchError("create is invalid; value does not exist"))
+			Expect(personalAccessToken).To(BeNil())
+		})
+
+		It("returns a personal access token that stores only the hash of the secret", func() {
+			personalAccessToken, err := auth.NewPersonalAccessToken("1234567890", create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(personalAccessToken).ToNot(BeNil())
+			Expect(personalAccessToken.Token).ToNot(BeNil())
+			Expect(personalAccessToken.TokenHash).To(Equal(auth.HashPersonalAccessTokenSecret(*personalAccessToken.Token)))
+			Expect(personalAccessToken.TokenHash).ToNot(ContainSubstring(*personalAccessToken.Token))
+			Expect(structureValidator.New().Validate(personalAccessToken)).To(Succeed())
+		})
+	})
+
+	Context("Authenticates", func() {
+		var personalAccessToken *auth.PersonalAccessToken
+
+		BeforeEach(func() {
+			personalAccessToken = &auth.PersonalAccessToken{
+				ID:          auth.NewPersonalAccessTokenID(),
+				UserID:      "1234567890",
+				Name:        "Research Scripts",
+				Scopes:      &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{http.MethodGet}), Path: pointer.FromString("/data/:userId")}},
+				CreatedTime: time.Now(),
+			}
+		})
+
+		It("returns true if the request matches a scope", func() {
+			Expect(personalAccessToken.Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeTrue())
+		})
+
+		It("returns false if the request does not match a scope", func() {
+			Expect(personalAccessToken.Authenticates(httptest.NewRequest(http.MethodDelete, "/data/1234567890", nil))).To(BeFalse())
+		})
+
+		It("returns false if revoked", func() {
+			personalAccessToken.RevokedTime = pointer.FromTime(time.Now())
+			Expect(personalAccessToken.Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeFalse())
+		})
+
+		It("returns false if expired", func() {
+			personalAccessToken.ExpirationTime = pointer.FromTime(time.Now().Add(-time.Minute))
+			Expect(personalAccessToken.Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeFalse())
+		})
+
+		It("returns true if not yet expired", func() {
+			personalAccessToken.ExpirationTime = pointer.FromTime(time.Now().Add(time.Minute))
+			Expect(personalAccessToken.Authenticates(httptest.NewRequest(http.MethodGet, "/data/1234567890", nil))).To(BeTrue())
+		})
+	})
+})
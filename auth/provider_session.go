@@ -33,6 +33,8 @@ type ProviderSessionAccessor interface {
 	GetProviderSession(ctx context.Context, id string) (*ProviderSession, error)
 	UpdateProviderSession(ctx context.Context, id string, update *ProviderSessionUpdate) (*ProviderSession, error)
 	DeleteProviderSession(ctx context.Context, id string) error
+
+	RotateProviderSessionEncryption(ctx context.Context) (int, error)
 }
 
 type ProviderSessionFilter struct {
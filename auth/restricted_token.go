@@ -3,7 +3,9 @@ package auth
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +18,15 @@ import (
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 )
 
-const MaximumExpirationDuration = time.Hour
+const (
+	MaximumExpirationDuration         = time.Hour
+	MaximumReadOnlyExpirationDuration = 7 * 24 * time.Hour
+
+	RestrictedTokenPathsLengthMaximum       = 10
+	RestrictedTokenScopesLengthMaximum      = 10
+	RestrictedTokenScopeQueryLengthMaximum  = 10
+	RestrictedTokenScopePathUserIDParameter = ":userId"
+)
 
 var pathExpression = regexp.MustCompile("^/.*$")
 
@@ -28,27 +38,56 @@ type RestrictedTokenAccessor interface {
 	GetRestrictedToken(ctx context.Context, id string) (*RestrictedToken, error)
 	UpdateRestrictedToken(ctx context.Context, id string, update *RestrictedTokenUpdate) (*RestrictedToken, error)
 	DeleteRestrictedToken(ctx context.Context, id string) error
+
+	// UseRestrictedToken increments the use count of the restricted token and returns it. If the restricted token is
+	// missing or has no uses remaining, then nil is returned.
+	UseRestrictedToken(ctx context.Context, id string) (*RestrictedToken, error)
 }
 
-type RestrictedTokenFilter struct{}
+// RestrictedTokenFilter filters by whether the token has expired, if specified, and by method and path, if specified,
+// matching tokens with a scope for the method and the exact path pattern
+type RestrictedTokenFilter struct {
+	Expired *bool   `json:"expired,omitempty"`
+	Method  *string `json:"method,omitempty"`
+	Path    *string `json:"path,omitempty"`
+}
 
 func NewRestrictedTokenFilter() *RestrictedTokenFilter {
 	return &RestrictedTokenFilter{}
 }
 
 func (r *RestrictedTokenFilter) Parse(parser structure.ObjectParser) {
+	r.Expired = parser.Bool("expired")
+	r.Method = parser.String("method")
+	r.Path = parser.String("path")
 }
 
 func (r *RestrictedTokenFilter) Validate(validator structure.Validator) {
+	validator.String("method", r.Method).OneOf(RestrictedTokenScopeMethods()...)
+	validator.String("path", r.Path).Matches(pathExpression)
 }
 
 func (r *RestrictedTokenFilter) MutateRequest(req *http.Request) error {
-	return nil
+	parameters := map[string]string{}
+	if r.Expired != nil {
+		parameters["expired"] = strconv.FormatBool(*r.Expired)
+	}
+	if r.Method != nil {
+		parameters["method"] = *r.Method
+	}
+	if r.Path != nil {
+		parameters["path"] = *r.Path
+	}
+	return request.NewParametersMutator(parameters).MutateRequest(req)
 }
 
+// RestrictedTokenCreate authenticates requests matching any of the paths or scopes, if either is specified. If the
+// maximum use count is specified, then the token no longer authenticates once used that many times.
 type RestrictedTokenCreate struct {
-	Paths          *[]string  `json:"paths,omitempty"`
-	ExpirationTime *time.Time `json:"expirationTime,omitempty"`
+	Paths           *[]string              `json:"paths,omitempty"`
+	Scopes          *RestrictedTokenScopes `json:"scopes,omitempty"`
+	ExpirationTime  *time.Time             `json:"expirationTime,omitempty"`
+	MaximumUseCount *int                   `json:"maximumUseCount,omitempty"`
 }
 
 func NewRestrictedTokenCreate() *RestrictedTokenCreate {
@@ -59,17 +98,23 @@ func NewRestrictedTokenCreate() *RestrictedTokenCreate {
 
 func (r *RestrictedTokenCreate) Parse(parser structure.ObjectParser) {
 	r.Paths = parser.StringArray("paths")
+	r.Scopes = ParseRestrictedTokenScopes(parser.WithReferenceArrayParser("scopes"))
 	r.ExpirationTime = parser.Time("expirationTime", time.RFC3339Nano)
+	r.MaximumUseCount = parser.Int("maximumUseCount")
 }
 
 func (r *RestrictedTokenCreate) Validate(validator structure.Validator) {
-	validator.StringArray("paths", r.Paths).LengthInRange(1, 10).EachMatches(pathExpression)
-	validator.Time("expirationTime", r.ExpirationTime).Before(time.Now().Add(MaximumExpirationDuration))
+	validatePathsAndScopes(validator, r.Paths, r.Scopes)
+	validator.Time("expirationTime", r.ExpirationTime).Before(time.Now().Add(RestrictedTokenMaximumExpirationDuration(r.Paths, r.Scopes)))
+	validator.Int("maximumUseCount", r.MaximumUseCount).GreaterThanOrEqualTo(1)
 }
 
+// RestrictedTokenUpdate replaces the paths and scopes together, if either is specified
 type RestrictedTokenUpdate struct {
-	Paths          *[]string  `json:"paths,omitempty"`
-	ExpirationTime *time.Time `json:"expirationTime,omitempty"`
+	Paths           *[]string              `json:"paths,omitempty"`
+	Scopes          *RestrictedTokenScopes `json:"scopes,omitempty"`
+	ExpirationTime  *time.Time             `json:"expirationTime,omitempty"`
+	MaximumUseCount *int                   `json:"maximumUseCount,omitempty"`
 }
 
 func NewRestrictedTokenUpdate() *RestrictedTokenUpdate {
@@ -78,16 +123,202 @@ func NewRestrictedTokenUpdate() *RestrictedTokenUpdate {
 
 func (r *RestrictedTokenUpdate) Parse(parser structure.ObjectParser) {
 	r.Paths = parser.StringArray("paths")
+	r.Scopes = ParseRestrictedTokenScopes(parser.WithReferenceArrayParser("scopes"))
 	r.ExpirationTime = parser.Time("expirationTime", time.RFC3339Nano)
+	r.MaximumUseCount = parser.Int("maximumUseCount")
 }
 
 func (r *RestrictedTokenUpdate) Validate(validator structure.Validator) {
-	validator.StringArray("paths", r.Paths).LengthInRange(1, 10).EachMatches(pathExpression)
-	validator.Time("expirationTime", r.ExpirationTime).Before(time.Now().Add(MaximumExpirationDuration))
+	validatePathsAndScopes(validator, r.Paths, r.Scopes)
+	validator.Time("expirationTime", r.ExpirationTime).Before(time.Now().Add(MaximumReadOnlyExpirationDuration))
+	validator.Int("maximumUseCount", r.MaximumUseCount).GreaterThanOrEqualTo(1)
 }
 
 func (r *RestrictedTokenUpdate) IsEmpty() bool {
-	return r.Paths == nil && r.ExpirationTime == nil
+	return r.Paths == nil && r.Scopes == nil && r.ExpirationTime == nil && r.MaximumUseCount == nil
+}
+
+func validatePathsAndScopes(validator structure.Validator, paths *[]string, scopes *RestrictedTokenScopes) {
+	validator.StringArray("paths", paths).LengthInRange(1, RestrictedTokenPathsLengthMaximum).EachMatches(pathExpression)
+	if scopes != nil {
+		scopes.Validate(validator.WithReference("scopes"))
+	}
+}
+
+// RestrictedTokenMaximumExpirationDuration returns the maximum expiration duration of a token with the paths and
+// scopes. A token with only read-only scopes, such as for a share link, may expire later than any other token.
+func RestrictedTokenMaximumExpirationDuration(paths *[]string, scopes *RestrictedTokenScopes) time.Duration {
+	if paths == nil && scopes != nil && scopes.IsReadOnly() {
+		return MaximumReadOnlyExpirationDuration
+	}
+	return MaximumExpirationDuration
+}
+
+func RestrictedTokenScopeMethods() []string {
+	return []string{
+		http.MethodDelete,
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPatch,
+		http.MethodPost,
+		http.MethodPut,
+	}
+}
+
+// RestrictedTokenScope authenticates requests with one of the methods, if specified, to the path, or any path below
+// it. A path segment of ":userId" only matches the user id of the token. If the query is specified, then the request
+// must include exactly one value for each query parameter that equals the specified value.
+type RestrictedTokenScope struct {
+	Methods *[]string          `json:"methods,omitempty" bson:"methods,omitempty"`
+	Path    *string            `json:"path,omitempty" bson:"path,omitempty"`
+	Query   *map[string]string `json:"query,omitempty" bson:"query,omitempty"`
+}
+
+func ParseRestrictedTokenScope(parser structure.ObjectParser) *RestrictedTokenScope {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewRestrictedTokenScope()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewRestrictedTokenScope() *RestrictedTokenScope {
+	return &RestrictedTokenScope{}
+}
+
+func (r *RestrictedTokenScope) Parse(parser structure.ObjectParser) {
+	r.Methods = parser.StringArray("methods")
+	r.Path = parser.String("path")
+	if queryParser := parser.WithReferenceObjectParser("query"); queryParser.Exists() {
+		query := map[string]string{}
+		for _, reference := range queryParser.References() {
+			if value := queryParser.String(reference); value != nil {
+				query[reference] = *value
+			}
+		}
+		r.Query = &query
+	}
+}
+
+func (r *RestrictedTokenScope) Validate(validator structure.Validator) {
+	validator.StringArray("methods", r.Methods).NotEmpty().EachOneOf(RestrictedTokenScopeMethods()...).EachUnique()
+	validator.String("path", r.Path).Exists().Matches(pathExpression)
+	if r.Query != nil {
+		queryValidator := validator.WithReference("query")
+		if length := len(*r.Query); length == 0 {
+			queryValidator.ReportError(structureValidator.ErrorValueEmpty())
+		} else if length > RestrictedTokenScopeQueryLengthMaximum {
+			queryValidator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, RestrictedTokenScopeQueryLengthMaximum))
+		}
+		for key, value := range *r.Query {
+			if key == "" {
+				queryValidator.ReportError(structureValidator.ErrorValueEmpty())
+			} else if key == TidepoolRestrictedTokenParameterKey {
+				queryValidator.WithReference(key).ReportError(structureValidator.ErrorValueNotValid())
+			} else {
+				queryValidator.String(key, &value).NotEmpty()
+			}
+		}
+	}
+}
+
+// IsReadOnly returns true if the scope only authenticates GET and HEAD requests
+func (r *RestrictedTokenScope) IsReadOnly() bool {
+	if r.Methods == nil {
+		return false
+	}
+	for _, method := range *r.Methods {
+		if method != http.MethodGet && method != http.MethodHead {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RestrictedTokenScope) Authenticates(req *http.Request, userID string) bool {
+	if r.Methods != nil {
+		found := false
+		for _, method := range *r.Methods {
+			if method == req.Method {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.Path == nil {
+		return false
+	}
+	segments := strings.Split(*r.Path, "/")
+	for index, segment := range segments {
+		if segment == RestrictedTokenScopePathUserIDParameter {
+			segments[index] = url.PathEscape(userID)
+		}
+	}
+	if !matchesPath(req.URL.EscapedPath(), strings.Join(segments, "/")) {
+		return false
+	}
+
+	if r.Query != nil {
+		query := req.URL.Query()
+		for key, value := range *r.Query {
+			if values := query[key]; len(values) != 1 || values[0] != value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+type RestrictedTokenScopes []*RestrictedTokenScope
+
+func ParseRestrictedTokenScopes(parser structure.ArrayParser) *RestrictedTokenScopes {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewRestrictedTokenScopes()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewRestrictedTokenScopes() *RestrictedTokenScopes {
+	return &RestrictedTokenScopes{}
+}
+
+func (r *RestrictedTokenScopes) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*r = append(*r, ParseRestrictedTokenScope(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (r *RestrictedTokenScopes) Validate(validator structure.Validator) {
+	if length := len(*r); length == 0 {
+		validator.ReportError(structureValidator.ErrorValueEmpty())
+	} else if length > RestrictedTokenScopesLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, RestrictedTokenScopesLengthMaximum))
+	}
+
+	for index, datum := range *r {
+		if datumValidator := validator.WithReference(strconv.Itoa(index)); datum != nil {
+			datum.Validate(datumValidator)
+		} else {
+			datumValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+func (r *RestrictedTokenScopes) IsReadOnly() bool {
+	for _, scope := range *r {
+		if scope == nil || !scope.IsReadOnly() {
+			return false
+		}
+	}
+	return true
 }
 
 func NewRestrictedTokenID() string {
@@ -118,12 +349,15 @@ func ErrorValueStringAsRestrictedTokenIDNotValid(value string) error {
 var restrictedTokenIDExpression = regexp.MustCompile("^[0-9a-z]{32}$")
 
 type RestrictedToken struct {
-	ID             string     `json:"id" bson:"id"`
-	UserID         string     `json:"userId" bson:"userId"`
-	Paths          *[]string  `json:"paths,omitempty" bson:"paths,omitempty"`
-	ExpirationTime time.Time  `json:"expirationTime" bson:"expirationTime"`
-	CreatedTime    time.Time  `json:"createdTime" bson:"createdTime"`
-	ModifiedTime   *time.Time `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
+	ID              string                 `json:"id" bson:"id"`
+	UserID          string                 `json:"userId" bson:"userId"`
+	Paths           *[]string              `json:"paths,omitempty" bson:"paths,omitempty"`
+	Scopes          *RestrictedTokenScopes `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	ExpirationTime  time.Time              `json:"expirationTime" bson:"expirationTime"`
+	MaximumUseCount *int                   `json:"maximumUseCount,omitempty" bson:"maximumUseCount,omitempty"`
+	UseCount        int                    `json:"useCount" bson:"useCount"`
+	CreatedTime     time.Time              `json:"createdTime" bson:"createdTime"`
+	ModifiedTime    *time.Time             `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
 }
 
 func NewRestrictedToken(userID string, create *RestrictedTokenCreate) (*RestrictedToken, error) {
@@ -137,10 +371,12 @@ func NewRestrictedToken(userID string, create *RestrictedTokenCreate) (*Restrict
 	}
 
 	restrictedToken := &RestrictedToken{
-		ID:          NewRestrictedTokenID(),
-		UserID:      userID,
-		Paths:       create.Paths,
-		CreatedTime: time.Now(),
+		ID:              NewRestrictedTokenID(),
+		UserID:          userID,
+		Paths:           create.Paths,
+		Scopes:          create.Scopes,
+		MaximumUseCount: create.MaximumUseCount,
+		CreatedTime:     time.Now(),
 	}
 	if create.ExpirationTime != nil {
 		restrictedToken.ExpirationTime = *create.ExpirationTime
@@ -159,9 +395,14 @@ func (r *RestrictedToken) Parse(parser structure.ObjectParser) {
 		r.UserID = *ptr
 	}
 	r.Paths = parser.StringArray("paths")
+	r.Scopes = ParseRestrictedTokenScopes(parser.WithReferenceArrayParser("scopes"))
 	if ptr := parser.Time("expirationTime", time.RFC3339Nano); ptr != nil {
 		r.ExpirationTime = *ptr
 	}
+	r.MaximumUseCount = parser.Int("maximumUseCount")
+	if ptr := parser.Int("useCount"); ptr != nil {
+		r.UseCount = *ptr
+	}
 	if ptr := parser.Time("createdTime", time.RFC3339Nano); ptr != nil {
 		r.CreatedTime = *ptr
 	}
@@ -171,8 +412,10 @@ func (r *RestrictedToken) Parse(parser structure.ObjectParser) {
 func (r *RestrictedToken) Validate(validator structure.Validator) {
 	validator.String("id", &r.ID).Using(RestrictedTokenIDValidator)
 	validator.String("userId", &r.UserID).Using(UserIDValidator)
-	validator.StringArray("paths", r.Paths).LengthInRange(1, 10).EachMatches(pathExpression)
-	validator.Time("expirationTime", &r.ExpirationTime).Before(time.Now().Add(MaximumExpirationDuration))
+	validatePathsAndScopes(validator, r.Paths, r.Scopes)
+	validator.Time("expirationTime", &r.ExpirationTime).Before(time.Now().Add(RestrictedTokenMaximumExpirationDuration(r.Paths, r.Scopes)))
+	validator.Int("maximumUseCount", r.MaximumUseCount).GreaterThanOrEqualTo(1)
+	validator.Int("useCount", &r.UseCount).GreaterThanOrEqualTo(0)
 	validator.Time("createdTime", &r.CreatedTime).NotZero().BeforeNow(time.Second)
 	validator.Time("modifiedTime", r.ModifiedTime).After(r.CreatedTime).BeforeNow(time.Second)
 }
@@ -184,16 +427,32 @@ func (r *RestrictedToken) Authenticates(req *http.Request) bool {
 	if time.Now().After(r.ExpirationTime) {
 		return false
 	}
+	if r.IsUsedUp() {
+		return false
+	}
+	if r.Paths == nil && r.Scopes == nil {
+		return true
+	}
 	if r.Paths != nil {
 		escapedPath := req.URL.EscapedPath()
 		for _, path := range *r.Paths {
-			if path == escapedPath || strings.HasPrefix(escapedPath, strings.TrimSuffix(path, "/")+"/") {
+			if matchesPath(escapedPath, path) {
 				return true
 			}
 		}
-		return false
 	}
-	return true
+	if r.Scopes != nil {
+		for _, scope := range *r.Scopes {
+			if scope != nil && scope.Authenticates(req, r.UserID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *RestrictedToken) IsUsedUp() bool {
+	return r.MaximumUseCount != nil && r.UseCount >= *r.MaximumUseCount
 }
 
 func (r *RestrictedToken) Sanitize(details request.Details) error {
@@ -203,6 +462,10 @@ func (r *RestrictedToken) Sanitize(details request.Details) error {
 	return errors.New("unable to sanitize")
 }
 
+func matchesPath(escapedPath string, path string) bool {
+	return path == escapedPath || strings.HasPrefix(escapedPath, strings.TrimSuffix(path, "/")+"/")
+}
+
 type RestrictedTokens []*RestrictedToken
 
 func (r RestrictedTokens) Sanitize(details request.Details) error {
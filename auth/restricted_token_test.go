@@ -1,12 +1,17 @@
 package auth_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"github.com/tidepool-org/platform/auth"
 	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/pointer"
 	structureTest "github.com/tidepool-org/platform/structure/test"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 	"github.com/tidepool-org/platform/test"
@@ -49,4 +54,110 @@ var _ = Describe("RestrictedToken", func() {
 			Entry("is ErrorValueStringAsRestrictedTokenIDNotValid with non-empty string", auth.ErrorValueStringAsRestrictedTokenIDNotValid("0123456789abcdef0123456789abcdef"), "value-not-valid", "value is not valid", `value "0123456789abcdef0123456789abcdef" is not valid as restricted token id`),
 		)
 	})
+
+	Context("RestrictedTokenCreate", func() {
+		It("is valid with read-only scopes expiring after the maximum expiration duration", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Scopes = &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{http.MethodGet}), Path: pointer.FromString("/v1/images/abc")}}
+			create.ExpirationTime = pointer.FromTime(time.Now().Add(24 * time.Hour))
+			Expect(structureValidator.New().Validate(create)).To(Succeed())
+		})
+
+		It("is not valid with paths expiring after the maximum expiration duration", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Paths = pointer.FromStringArray([]string{"/v1/images"})
+			create.Scopes = &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{http.MethodGet}), Path: pointer.FromString("/v1/images/abc")}}
+			create.ExpirationTime = pointer.FromTime(time.Now().Add(24 * time.Hour))
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is not valid with scopes that are not read-only expiring after the maximum expiration duration", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Scopes = &auth.RestrictedTokenScopes{{Path: pointer.FromString("/v1/images/abc")}}
+			create.ExpirationTime = pointer.FromTime(time.Now().Add(24 * time.Hour))
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is not valid with a scope without a path", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Scopes = &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{http.MethodGet})}}
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is not valid with a scope with an invalid method", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Scopes = &auth.RestrictedTokenScopes{{Methods: pointer.FromStringArray([]string{"INVALID"}), Path: pointer.FromString("/v1/images/abc")}}
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+
+		It("is not valid with a maximum use count less than one", func() {
+			create := auth.NewRestrictedTokenCreate()
+			create.Paths = pointer.FromStringArray([]string{"/v1/images"})
+			create.MaximumUseCount = pointer.FromInt(0)
+			Expect(structureValidator.New().Validate(create)).ToNot(Succeed())
+		})
+	})
+
+	Context("RestrictedToken", func() {
+		var restrictedToken *auth.RestrictedToken
+
+		BeforeEach(func() {
+			restrictedToken = &auth.RestrictedToken{
+				ID:             auth.NewRestrictedTokenID(),
+				UserID:         "1234567890",
+				ExpirationTime: time.Now().Add(time.Hour),
+				CreatedTime:    time.Now(),
+			}
+		})
+
+		Context("Authenticates", func() {
+			It("returns true if neither paths nor scopes are specified", func() {
+				Expect(restrictedToken.Authenticates(httptest.NewRequest(http.MethodPost, "/v1/anything", nil))).To(BeTrue())
+			})
+
+			It("returns false if expired", func() {
+				restrictedToken.ExpirationTime = time.Now().Add(-time.Minute)
+				Expect(restrictedToken.Authenticates(httptest.NewRequest(http.MethodGet, "/v1/anything", nil))).To(BeFalse())
+			})
+
+			It("returns false if no uses remain", func() {
+				restrictedToken.MaximumUseCount = pointer.FromInt(1)
+				restrictedToken.UseCount = 1
+				Expect(restrictedToken.Authenticates(httptest.NewRequest(http.MethodGet, "/v1/anything", nil))).To(BeFalse())
+			})
+
+			It("returns true if uses remain", func() {
+				restrictedToken.MaximumUseCount = pointer.FromInt(2)
+				restrictedToken.UseCount = 1
+				Expect(restrictedToken.Authenticates(httptest.NewRequest(http.MethodGet, "/v1/anything", nil))).To(BeTrue())
+			})
+
+			Context("with scopes", func() {
+				BeforeEach(func() {
+					restrictedToken.Scopes = &auth.RestrictedTokenScopes{
+						{
+							Methods: pointer.FromStringArray([]string{http.MethodGet}),
+							Path:    pointer.FromString("/v1/users/:userId/images/abc/rendition"),
+							Query:   &map[string]string{"size": "small"},
+						},
+					}
+				})
+
+				DescribeTable("returns expected result",
+					func(method string, target string, expected bool) {
+						Expect(restrictedToken.Authenticates(httptest.NewRequest(method, target, nil))).To(Equal(expected))
+					},
+					Entry("matches method, path, and query", http.MethodGet, "/v1/users/1234567890/images/abc/rendition?size=small", true),
+					Entry("matches method, sub path, and query", http.MethodGet, "/v1/users/1234567890/images/abc/rendition/thumbnail.jpg?size=small&restricted_token=x", true),
+					Entry("does not match method", http.MethodPut, "/v1/users/1234567890/images/abc/rendition?size=small", false),
+					Entry("does not match user id", http.MethodGet, "/v1/users/0987654321/images/abc/rendition?size=small", false),
+					Entry("does not match path", http.MethodGet, "/v1/users/1234567890/images/def/rendition?size=small", false),
+					Entry("does not match path prefix", http.MethodGet, "/v1/users/1234567890/images/abc/renditions?size=small", false),
+					Entry("is missing query", http.MethodGet, "/v1/users/1234567890/images/abc/rendition", false),
+					Entry("does not match query", http.MethodGet, "/v1/users/1234567890/images/abc/rendition?size=large", false),
+					Entry("has multiple query values", http.MethodGet, "/v1/users/1234567890/images/abc/rendition?size=small&size=large", false),
+				)
+			})
+		})
+	})
 })
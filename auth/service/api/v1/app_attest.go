@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"encoding/base64"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/api"
+)
+
+type VerifyAppAttestAssertionResponse struct {
+	Valid bool `json:"valid"`
+}
+
+func (r *Router) AppAttestRoutes() []*rest.Route {
+	return []*rest.Route{
+		rest.Post("/v1/app_attest/challenges", api.RequireUser(r.CreateAppAttestChallenge)),
+		rest.Post("/v1/app_attest/attestations", api.RequireUser(r.CreateAppAttestAttestation)),
+		rest.Post("/v1/app_attest/assertions/verify", api.RequireUser(r.VerifyAppAttestAssertion)),
+	}
+}
+
+func (r *Router) CreateAppAttestChallenge(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	if r.AppAttest() == nil {
+		responder.Error(http.StatusNotImplemented, errors.New("app attest is not configured"))
+		return
+	}
+
+	challenge, err := r.AuthStore().NewAppAttestChallengeRepository().CreateAppAttestChallenge(req.Context(), details.UserID())
+	if err != nil {
+		responder.InternalServerError(err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, challenge)
+}
+
+// CreateAppAttestAttestation consumes the challenge, so a failed attestation must be retried with a new challenge
+func (r *Router) CreateAppAttestAttestation(res rest.ResponseWriter, req *rest.Request) {
+	ctx := req.Context()
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(ctx)
+
+	if r.AppAttest() == nil {
+		responder.Error(http.StatusNotImplemented, errors.New("app attest is not configured"))
+		return
+	}
+
+	attestation := auth.NewAppAttestAttestation()
+	if err := request.DecodeRequestBody(req.Request, attestation); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	if consumed, err := r.AuthStore().NewAppAttestChallengeRepository().ConsumeAppAttestChallenge(ctx, details.UserID(), *attestation.Challenge); err != nil {
+		responder.InternalServerError(err)
+		return
+	} else if !consumed {
+		responder.Error(http.StatusUnauthorized, request.ErrorUnauthorized())
+		return
+	}
+
+	attestationBytes, _ := base64.StdEncoding.DecodeString(*attestation.Attestation)
+	appAttestKey, err := r.AppAttest().VerifyAttestation(*attestation.KeyID, attestationBytes, []byte(*attestation.Challenge))
+	if err != nil {
+		log.LoggerFromContext(ctx).WithError(err).Warn("Unable to verify app attest attestation")
+		responder.Error(http.StatusUnauthorized, request.ErrorUnauthorized())
+		return
+	}
+
+	key := &auth.AppAttestKey{
+		ID:          appAttestKey.ID,
+		UserID:      details.UserID(),
+		PublicKey:   appAttestKey.PublicKey,
+		Receipt:     appAttestKey.Receipt,
+		Environment: appAttestKey.Environment,
+		CreatedTime: time.Now(),
+	}
+	if err = r.AuthStore().NewAppAttestKeyRepository().CreateAppAttestKey(ctx, key); err != nil {
+		responder.InternalServerError(err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, key)
+}
+
+// VerifyAppAttestAssertion responds that the assertion is not valid if it was not signed by a key attested for the user
+// or was replayed
+func (r *Router) VerifyAppAttestAssertion(res rest.ResponseWriter, req *rest.Request) {
+	ctx := req.Context()
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(ctx)
+
+	if r.AppAttest() == nil {
+		responder.Error(http.StatusNotImplemented, errors.New("app attest is not configured"))
+		return
+	}
+
+	assertion := auth.NewAppAttestAssertion()
+	if err := request.DecodeRequestBody(req.Request, assertion); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	repository := r.AuthStore().NewAppAttestKeyRepository()
+	key, err := repository.GetAppAttestKey(ctx, details.UserID(), *assertion.KeyID)
+	if err != nil {
+		responder.InternalServerError(err)
+		return
+	} else if key == nil {
+		responder.Data(http.StatusOK, VerifyAppAttestAssertionResponse{Valid: false})
+		return
+	} else if key.Counter < 0 || key.Counter > math.MaxUint32 {
+		responder.InternalServerError(errors.New("app attest key counter is invalid"))
+		return
+	}
+
+	assertionBytes, _ := base64.StdEncoding.DecodeString(*assertion.Assertion)
+	clientDataBytes, _ := base64.StdEncoding.DecodeString(*assertion.ClientData)
+	counter, err := r.AppAttest().VerifyAssertion(key.PublicKey, assertionBytes, clientDataBytes, uint32(key.Counter))
+	if err != nil {
+		log.LoggerFromContext(ctx).WithError(err).WithField("keyId", key.ID).Warn("Unable to verify app attest assertion")
+		responder.Data(http.StatusOK, VerifyAppAttestAssertionResponse{Valid: false})
+		return
+	}
+
+	// A concurrent assertion with the same or later counter wins and this one is treated as replayed
+	updated, err := repository.UpdateAppAttestKeyCounter(ctx, key.ID, key.Counter, int64(counter))
+	if err != nil {
+		responder.InternalServerError(err)
+		return
+	}
+
+	responder.Data(http.StatusOK, VerifyAppAttestAssertionResponse{Valid: updated})
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/api"
+)
+
+// AuditEventsRoutes are only available to services. Audit events are created by services on behalf of the requests
+// they authorize and cannot be updated or deleted.
+func (r *Router) AuditEventsRoutes() []*rest.Route {
+	return []*rest.Route{
+		rest.Get("/v1/users/:userId/audit_events", api.RequireServer(r.ListUserAuditEvents)),
+		rest.Post("/v1/audit_events", api.RequireServer(r.CreateAuditEvents)),
+	}
+}
+
+func (r *Router) ListUserAuditEvents(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	filter := auth.NewAuditEventFilter()
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, filter, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := r.AuthClient().ListUserAuditEvents(req.Context(), userID, filter, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, events)
+}
+
+func (r *Router) CreateAuditEvents(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	events := auth.NewAuditEvents()
+	if err := request.DecodeRequestBody(req.Request, events); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.AuthClient().CreateAuditEvents(req.Context(), *events); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Empty(http.StatusCreated)
+}
@@ -56,7 +56,7 @@ func (r *Router) OAuthProviderAuthorizeGet(res rest.ResponseWriter, req *rest.Re
 	}
 
 	responder.SetCookie(r.providerCookie(prvdr, details.Token(), int(maxAge)))
-	responder.Redirect(http.StatusTemporaryRedirect, prvdr.GetAuthorizationCodeURLWithState(prvdr.CalculateStateForRestrictedToken(details.Token())))
+	responder.Redirect(http.StatusTemporaryRedirect, prvdr.GetAuthorizationCodeURLWithState(prvdr.CalculateStateForRestrictedToken(details.Token()), prvdr.CalculateCodeVerifierForRestrictedToken(details.Token())))
 }
 
 func (r *Router) OAuthProviderAuthorizeDelete(res rest.ResponseWriter, req *rest.Request) {
@@ -136,7 +136,7 @@ func (r *Router) OAuthProviderRedirectGet(res rest.ResponseWriter, req *rest.Req
 		return
 	}
 
-	oauthToken, err := prvdr.ExchangeAuthorizationCodeForToken(ctx, query.Get("code"))
+	oauthToken, err := prvdr.ExchangeAuthorizationCodeForToken(ctx, query.Get("code"), prvdr.CalculateCodeVerifierForRestrictedToken(restrictedToken.ID))
 	if err != nil {
 		r.htmlOnError(res, req, err)
 		return
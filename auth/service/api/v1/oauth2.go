@@ -0,0 +1,422 @@
+package v1
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/api"
+)
+
+const (
+	OAuthErrorInvalidRequest = "invalid_request"
+	OAuthErrorInvalidClient  = "invalid_client"
+	OAuthErrorInvalidGrant   = "invalid_grant"
+	OAuthErrorServerError    = "server_error"
+)
+
+type OAuthAuthorizeResponse struct {
+	RedirectURI string `json:"redirectUri"`
+}
+
+// OAuthErrorResponse is the error response of the token and revocation endpoints (RFC 6749 section 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuth2Routes are the authorization server for third-party apps. The consent screen posts to the authorize route
+// with the user's session and then redirects the browser to the returned redirect uri. Clients authenticate to the
+// token and revocation routes with form parameters, since the Authorization header is reserved for bearer tokens.
+func (r *Router) OAuth2Routes() []*rest.Route {
+	return []*rest.Route{
+		rest.Post("/v1/oauth2/clients", api.RequireServer(r.CreateOAuthClient)),
+		rest.Get("/v1/oauth2/clients/:clientId", api.Require(r.GetOAuthClient)),
+		rest.Delete("/v1/oauth2/clients/:clientId", api.RequireServer(r.DeleteOAuthClient)),
+		rest.Post("/v1/oauth2/authorize", api.RequireUser(r.OAuthAuthorize)),
+		rest.Post("/v1/oauth2/token", r.OAuthToken),
+		rest.Post("/v1/oauth2/revoke", r.OAuthRevoke),
+		rest.Post("/v1/oauth2/introspect", api.RequireServer(r.OAuthIntrospect)),
+		rest.Get("/v1/users/:userId/oauth2/consents", api.Require(r.ListUserOAuthConsents)),
+		rest.Delete("/v1/users/:userId/oauth2/consents", api.RequireServer(r.DeleteAllOAuthConsents)),
+		rest.Delete("/v1/users/:userId/oauth2/consents/:clientId", api.Require(r.DeleteUserOAuthConsent)),
+	}
+}
+
+func (r *Router) CreateOAuthClient(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	create := auth.NewOAuthClientCreate()
+	if err := request.DecodeRequestBody(req.Request, create); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	client, err := auth.NewOAuthClient(create)
+	if err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	if err = r.AuthStore().NewOAuthClientRepository().CreateOAuthClient(req.Context(), client); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, client)
+}
+
+func (r *Router) GetOAuthClient(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	clientID := req.PathParam("clientId")
+	if clientID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("clientId"))
+		return
+	}
+
+	client, err := r.AuthStore().NewOAuthClientRepository().GetOAuthClient(req.Context(), clientID)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if client == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(clientID))
+		return
+	}
+
+	if details := request.DetailsFromContext(req.Context()); !details.IsService() && (client.OwnerUserID == nil || *client.OwnerUserID != details.UserID()) {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	responder.Data(http.StatusOK, client)
+}
+
+func (r *Router) DeleteOAuthClient(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	clientID := req.PathParam("clientId")
+	if clientID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("clientId"))
+		return
+	}
+
+	deleted, err := r.AuthStore().NewOAuthClientRepository().DeleteOAuthClient(req.Context(), clientID)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if !deleted {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(clientID))
+		return
+	}
+
+	if err = r.AuthStore().NewOAuthTokenRepository().RevokeClientOAuthTokens(req.Context(), clientID); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Empty(http.StatusNoContent)
+}
+
+// OAuthAuthorize requires a session so that only the user, and not a token issued to another app, may consent
+func (r *Router) OAuthAuthorize(res rest.ResponseWriter, req *rest.Request) {
+	ctx := req.Context()
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(ctx)
+
+	if details.Method() != request.MethodSessionToken {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	authorize := auth.NewOAuthAuthorize()
+	if err := request.DecodeRequestBody(req.Request, authorize); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	client, err := r.AuthStore().NewOAuthClientRepository().GetOAuthClient(ctx, *authorize.ClientID)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if client == nil {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterInvalid("clientId"))
+		return
+	} else if !client.HasRedirectURI(*authorize.RedirectURI) {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterInvalid("redirectUri"))
+		return
+	}
+
+	code, err := auth.NewOAuthAuthorizationCode(details.UserID(), authorize)
+	if err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	} else if !client.AllowsScopes(code.Scopes) {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterInvalid("scope"))
+		return
+	}
+
+	if _, err = r.AuthStore().NewOAuthConsentRepository().UpsertOAuthConsent(ctx, details.UserID(), client.ID, code.Scopes); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+	if err = r.AuthStore().NewOAuthAuthorizationCodeRepository().CreateOAuthAuthorizationCode(ctx, code); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	redirectURI, err := url.Parse(code.RedirectURI)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+	query := redirectURI.Query()
+	query.Set("code", *code.Code)
+	if authorize.State != nil {
+		query.Set("state", *authorize.State)
+	}
+	redirectURI.RawQuery = query.Encode()
+
+	responder.Data(http.StatusOK, OAuthAuthorizeResponse{RedirectURI: redirectURI.String()})
+}
+
+func (r *Router) OAuthToken(res rest.ResponseWriter, req *rest.Request) {
+	ctx := req.Context()
+	responder := request.MustNewResponder(res, req)
+	noStore := request.NewHeaderMutator("Cache-Control", "no-store")
+
+	tokenRequest := auth.NewOAuthTokenRequest()
+	if err := req.ParseForm(); err != nil {
+		responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidRequest}, noStore)
+		return
+	} else if err = request.DecodeValues(req.PostForm, tokenRequest); err != nil {
+		responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidRequest, ErrorDescription: err.Error()}, noStore)
+		return
+	}
+
+	client, ok := r.authenticateOAuthClient(res, req, *tokenRequest.ClientID, tokenRequest.ClientSecret)
+	if !ok {
+		return
+	}
+
+	var token *auth.OAuthToken
+	var err error
+	switch *tokenRequest.GrantType {
+	case auth.OAuthGrantTypeAuthorizationCode:
+		var code *auth.OAuthAuthorizationCode
+		// Only an authorization code issued to the client is consumed, so that no other client may destroy it
+		if code, err = r.AuthStore().NewOAuthAuthorizationCodeRepository().ConsumeOAuthAuthorizationCode(ctx, client.ID, *tokenRequest.Code); err != nil {
+			break
+		} else if code == nil || code.RedirectURI != *tokenRequest.RedirectURI || !code.VerifiesCodeVerifier(*tokenRequest.CodeVerifier) {
+			responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidGrant}, noStore)
+			return
+		}
+		token, err = auth.NewOAuthToken(client.ID, code.UserID, code.Scopes)
+	case auth.OAuthGrantTypeRefreshToken:
+		var previous *auth.OAuthToken
+		if !auth.IsOAuthRefreshToken(*tokenRequest.RefreshToken) {
+			responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidGrant}, noStore)
+			return
+		} else if previous, err = r.AuthStore().NewOAuthTokenRepository().RevokeOAuthToken(ctx, client.ID, *tokenRequest.RefreshToken); err != nil {
+			break
+		} else if previous == nil || !time.Now().Before(previous.RefreshTokenExpirationTime) {
+			responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidGrant}, noStore)
+			return
+		}
+		r.AuthClient().RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeOAuthTokenRevoke, previous.UserID, previous.ID))
+		token, err = auth.NewOAuthToken(client.ID, previous.UserID, previous.Scopes)
+	}
+	if err == nil {
+		err = r.AuthStore().NewOAuthTokenRepository().CreateOAuthToken(ctx, token)
+	}
+	if err != nil {
+		log.LoggerFromContext(ctx).WithError(err).Error("Unable to issue oauth token")
+		responder.Data(http.StatusInternalServerError, OAuthErrorResponse{Error: OAuthErrorServerError}, noStore)
+		return
+	}
+
+	r.AuthClient().RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeOAuthTokenCreate, token.UserID, token.ID))
+	responder.Data(http.StatusOK, token.Response(), noStore)
+}
+
+// OAuthRevoke responds successfully even if the token is unknown, already revoked, or was issued to another client, which
+// it leaves intact (RFC 7009)
+func (r *Router) OAuthRevoke(res rest.ResponseWriter, req *rest.Request) {
+	ctx := req.Context()
+	responder := request.MustNewResponder(res, req)
+
+	if err := req.ParseForm(); err != nil {
+		responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidRequest})
+		return
+	}
+	token := req.PostForm.Get("token")
+	clientID := req.PostForm.Get("client_id")
+	if token == "" || clientID == "" {
+		responder.Data(http.StatusBadRequest, OAuthErrorResponse{Error: OAuthErrorInvalidRequest})
+		return
+	}
+
+	var clientSecret *string
+	if values, ok := req.PostForm["client_secret"]; ok && len(values) > 0 {
+		clientSecret = &values[0]
+	}
+	client, ok := r.authenticateOAuthClient(res, req, clientID, clientSecret)
+	if !ok {
+		return
+	}
+
+	revoked, err := r.AuthStore().NewOAuthTokenRepository().RevokeOAuthToken(ctx, client.ID, token)
+	if err != nil {
+		log.LoggerFromContext(ctx).WithError(err).Error("Unable to revoke oauth token")
+		responder.Data(http.StatusInternalServerError, OAuthErrorResponse{Error: OAuthErrorServerError})
+		return
+	} else if revoked != nil {
+		r.AuthClient().RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeOAuthTokenRevoke, revoked.UserID, revoked.ID))
+	}
+
+	responder.Empty(http.StatusOK)
+}
+
+// OAuthIntrospect accepts the token as a form parameter (RFC 7662) or in a JSON body
+func (r *Router) OAuthIntrospect(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	introspect := auth.NewOAuthTokenIntrospect()
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := req.ParseForm(); err != nil {
+			responder.Error(http.StatusBadRequest, request.ErrorBadRequest())
+			return
+		} else if err = request.DecodeValues(req.PostForm, introspect); err != nil {
+			responder.Error(http.StatusBadRequest, err)
+			return
+		}
+	} else if err := request.DecodeRequestBody(req.Request, introspect); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	introspection, err := r.AuthClient().IntrospectOAuthToken(req.Context(), *introspect.Token)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, introspection)
+}
+
+func (r *Router) ListUserOAuthConsents(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != userID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	consents, err := r.AuthStore().NewOAuthConsentRepository().ListUserOAuthConsents(req.Context(), userID, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, consents)
+}
+
+func (r *Router) DeleteAllOAuthConsents(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if err := r.AuthClient().DeleteAllOAuthConsents(req.Context(), userID); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Empty(http.StatusNoContent)
+}
+
+// DeleteUserOAuthConsent also revokes all tokens issued to the client on behalf of the user
+func (r *Router) DeleteUserOAuthConsent(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+	clientID := req.PathParam("clientId")
+	if clientID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("clientId"))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != userID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	deleted, err := r.AuthStore().NewOAuthConsentRepository().DeleteOAuthConsent(req.Context(), userID, clientID)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = r.AuthStore().NewOAuthTokenRepository().RevokeUserClientOAuthTokens(req.Context(), userID, clientID); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	if !deleted {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(clientID))
+		return
+	}
+
+	r.AuthClient().RecordAuditEvent(req.Context(), auth.NewLifecycleAuditEvent(req.Context(), auth.AuditEventTypeOAuthTokenRevoke, userID, clientID))
+	responder.Empty(http.StatusNoContent)
+}
+
+func (r *Router) authenticateOAuthClient(res rest.ResponseWriter, req *rest.Request, clientID string, clientSecret *string) (*auth.OAuthClient, bool) {
+	responder := request.MustNewResponder(res, req)
+
+	client, err := r.AuthStore().NewOAuthClientRepository().GetOAuthClient(req.Context(), clientID)
+	if err != nil {
+		log.LoggerFromContext(req.Context()).WithError(err).Error("Unable to get oauth client")
+		responder.Data(http.StatusInternalServerError, OAuthErrorResponse{Error: OAuthErrorServerError})
+		return nil, false
+	}
+
+	secret := ""
+	if clientSecret != nil {
+		secret = *clientSecret
+	}
+	if client == nil || !client.AuthenticatesSecret(secret) {
+		responder.Data(http.StatusUnauthorized, OAuthErrorResponse{Error: OAuthErrorInvalidClient})
+		return nil, false
+	}
+
+	return client, true
+}
@@ -1,8 +1,70 @@
 package v1_test
 
 import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	authServiceApiV1 "github.com/tidepool-org/platform/auth/service/api/v1"
+	serviceTest "github.com/tidepool-org/platform/auth/service/test"
+	authStoreTest "github.com/tidepool-org/platform/auth/store/test"
+	authTest "github.com/tidepool-org/platform/auth/test"
+	"github.com/tidepool-org/platform/log"
+	logNull "github.com/tidepool-org/platform/log/null"
+	platformRequest "github.com/tidepool-org/platform/request"
+	testRest "github.com/tidepool-org/platform/test/rest"
 )
 
 var _ = Describe("OAuth", func() {
+	Context("DeleteUserOAuthConsent", func() {
+		var svc *serviceTest.Service
+		var rtr *authServiceApiV1.Router
+		var response *testRest.ResponseWriter
+		var request *rest.Request
+		var userID string
+		var clientID string
+
+		BeforeEach(func() {
+			var err error
+			svc = serviceTest.NewService()
+			rtr, err = authServiceApiV1.NewRouter(svc)
+			Expect(err).ToNot(HaveOccurred())
+			userID = authTest.RandomUserID()
+			clientID = "test-client"
+			response = testRest.NewResponseWriter()
+			response.HeaderOutput = &http.Header{}
+			request = testRest.NewRequest()
+			ctx := log.NewContextWithLogger(request.Context(), logNull.NewLogger())
+			ctx = platformRequest.NewContextWithDetails(ctx, platformRequest.NewDetails(platformRequest.MethodSessionToken, userID, authTest.NewSessionToken()))
+			request.Request = request.WithContext(ctx)
+			request.PathParams["userId"] = userID
+			request.PathParams["clientId"] = clientID
+			svc.AuthStoreImpl.NewOAuthTokenRepositoryImpl.RevokeUserClientOAuthTokensOutputs = []error{nil}
+		})
+
+		AfterEach(func() {
+			svc.AuthStoreImpl.Expectations()
+			response.AssertOutputsEmpty()
+		})
+
+		It("records the revoke audit event with the client id after deleting the consent", func() {
+			svc.AuthStoreImpl.NewOAuthConsentRepositoryImpl.DeleteOAuthConsentOutputs = []authStoreTest.DeleteOAuthConsentOutput{{Deleted: true}}
+			rtr.DeleteUserOAuthConsent(response, request)
+			Expect(response.WriteHeaderInputs).To(Equal([]int{http.StatusNoContent}))
+			Expect(svc.AuthClientImpl.RecordAuditEventInputs).To(HaveLen(1))
+			Expect(svc.AuthClientImpl.RecordAuditEventInputs[0].Type).To(Equal(auth.AuditEventTypeOAuthTokenRevoke))
+			Expect(svc.AuthClientImpl.RecordAuditEventInputs[0].ResourceID).To(Equal(clientID))
+		})
+
+		It("returns not found without recording an audit event if there is no consent", func() {
+			response.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+			svc.AuthStoreImpl.NewOAuthConsentRepositoryImpl.DeleteOAuthConsentOutputs = []authStoreTest.DeleteOAuthConsentOutput{{Deleted: false}}
+			rtr.DeleteUserOAuthConsent(response, request)
+			Expect(response.WriteHeaderInputs).To(Equal([]int{http.StatusNotFound}))
+			Expect(svc.AuthClientImpl.RecordAuditEventInvocations).To(BeZero())
+		})
+	})
 })
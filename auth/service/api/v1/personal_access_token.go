@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/api"
+)
+
+func (r *Router) PersonalAccessTokensRoutes() []*rest.Route {
+	return []*rest.Route{
+		rest.Get("/v1/users/:userId/personal_access_tokens", api.Require(r.ListUserPersonalAccessTokens)),
+		rest.Post("/v1/users/:userId/personal_access_tokens", api.Require(r.CreateUserPersonalAccessToken)),
+		rest.Delete("/v1/users/:userId/personal_access_tokens", api.RequireServer(r.DeleteAllPersonalAccessTokens)),
+		rest.Post("/v1/personal_access_tokens/authenticate", api.RequireServer(r.AuthenticatePersonalAccessToken)),
+		rest.Get("/v1/personal_access_tokens/:id", api.Require(r.GetPersonalAccessToken)),
+		rest.Delete("/v1/personal_access_tokens/:id", api.Require(r.RevokePersonalAccessToken)),
+	}
+}
+
+func (r *Router) ListUserPersonalAccessTokens(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != userID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	filter := auth.NewPersonalAccessTokenFilter()
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, filter, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	personalAccessTokens, err := r.AuthClient().ListUserPersonalAccessTokens(req.Context(), userID, filter, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, personalAccessTokens)
+}
+
+// CreateUserPersonalAccessToken requires a session so that a personal access token cannot be used to create another
+func (r *Router) CreateUserPersonalAccessToken(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if !details.IsService() && (details.UserID() != userID || details.Method() != request.MethodSessionToken) {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	create := auth.NewPersonalAccessTokenCreate()
+	if err := request.DecodeRequestBody(req.Request, create); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	personalAccessToken, err := r.AuthClient().CreateUserPersonalAccessToken(req.Context(), userID, create)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, personalAccessToken)
+}
+
+func (r *Router) DeleteAllPersonalAccessTokens(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if err := r.AuthClient().DeleteAllPersonalAccessTokens(req.Context(), userID); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Empty(http.StatusNoContent)
+}
+
+func (r *Router) AuthenticatePersonalAccessToken(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	authenticate := auth.NewPersonalAccessTokenAuthenticate()
+	if err := request.DecodeRequestBody(req.Request, authenticate); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	personalAccessToken, err := r.AuthClient().AuthenticatePersonalAccessToken(req.Context(), *authenticate.Token)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if personalAccessToken == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFound())
+		return
+	}
+
+	responder.Data(http.StatusOK, personalAccessToken)
+}
+
+func (r *Router) GetPersonalAccessToken(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	personalAccessToken, err := r.AuthClient().GetPersonalAccessToken(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if personalAccessToken == nil || (!details.IsService() && details.UserID() != personalAccessToken.UserID) {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	responder.Data(http.StatusOK, personalAccessToken)
+}
+
+func (r *Router) RevokePersonalAccessToken(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+	details := request.DetailsFromContext(req.Context())
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	personalAccessToken, err := r.AuthClient().GetPersonalAccessToken(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if personalAccessToken == nil || (!details.IsService() && details.UserID() != personalAccessToken.UserID) {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	personalAccessToken, err = r.AuthClient().RevokePersonalAccessToken(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if personalAccessToken == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	responder.Data(http.StatusOK, personalAccessToken)
+}
@@ -19,6 +19,7 @@ func (r *Router) ProviderSessionsRoutes() []*rest.Route {
 		rest.Get("/v1/provider_sessions/:id", api.RequireServer(r.GetProviderSession)),
 		rest.Put("/v1/provider_sessions/:id", api.RequireServer(r.UpdateProviderSession)),
 		rest.Delete("/v1/provider_sessions/:id", api.RequireServer(r.DeleteProviderSession)),
+		rest.Post("/v1/provider_sessions/rotate_encryption", api.RequireServer(r.RotateProviderSessionEncryption)),
 	}
 }
 
@@ -153,3 +154,15 @@ func (r *Router) DeleteProviderSession(res rest.ResponseWriter, req *rest.Reques
 
 	responder.Empty(http.StatusOK)
 }
+
+func (r *Router) RotateProviderSessionEncryption(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	count, err := r.AuthClient().RotateProviderSessionEncryption(req.Context())
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, count)
+}
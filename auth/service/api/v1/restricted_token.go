@@ -19,6 +19,7 @@ func (r *Router) RestrictedTokensRoutes() []*rest.Route {
 		rest.Get("/v1/restricted_tokens/:id", api.RequireServer(r.GetRestrictedToken)),
 		rest.Put("/v1/restricted_tokens/:id", api.RequireServer(r.UpdateRestrictedToken)),
 		rest.Delete("/v1/restricted_tokens/:id", api.Require(r.DeleteRestrictedToken)),
+		rest.Post("/v1/restricted_tokens/:id/use", api.RequireServer(r.UseRestrictedToken)),
 	}
 }
 
@@ -174,3 +175,24 @@ func (r *Router) DeleteRestrictedToken(res rest.ResponseWriter, req *rest.Reques
 
 	responder.Empty(http.StatusOK)
 }
+
+func (r *Router) UseRestrictedToken(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	restrictedToken, err := r.AuthClient().UseRestrictedToken(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if restrictedToken == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	responder.Data(http.StatusOK, restrictedToken)
+}
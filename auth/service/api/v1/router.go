@@ -26,7 +26,11 @@ func (r *Router) Routes() []*rest.Route {
 		r.OAuthRoutes(),
 		r.ProviderSessionsRoutes(),
 		r.RestrictedTokensRoutes(),
+		r.PersonalAccessTokensRoutes(),
 		r.DeviceCheckRoutes(),
+		r.AppAttestRoutes(),
+		r.OAuth2Routes(),
+		r.AuditEventsRoutes(),
 	}
 	acc := make([]*rest.Route, 0)
 	for _, r := range routes {
@@ -21,6 +21,7 @@ type Service interface {
 
 	TaskClient() task.Client
 	DeviceCheck() apple.DeviceCheck
+	AppAttest() apple.AppAttest
 
 	Status(context.Context) *Status
 }
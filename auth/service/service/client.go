@@ -8,6 +8,7 @@ import (
 	authStore "github.com/tidepool-org/platform/auth/store"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/oauth"
 	"github.com/tidepool-org/platform/page"
 	"github.com/tidepool-org/platform/platform"
 	"github.com/tidepool-org/platform/provider"
@@ -45,11 +46,14 @@ func NewClient(cfg *client.ExternalConfig, authorizeAs platform.AuthorizeAs, nam
 		return nil, err
 	}
 
-	return &Client{
+	c := &Client{
 		External:        external,
 		authStore:       authStore,
 		providerFactory: providerFactory,
-	}, nil
+	}
+	external.SetAuditEventSink(c)
+
+	return c, nil
 }
 
 func (c *Client) ListUserProviderSessions(ctx context.Context, userID string, filter *auth.ProviderSessionFilter, pagination *page.Pagination) (auth.ProviderSessions, error) {
@@ -76,6 +80,7 @@ func (c *Client) CreateUserProviderSession(ctx context.Context, userID string, c
 		return nil, err
 	}
 
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeProviderSessionCreate, providerSession.UserID, providerSession.ID))
 	return providerSession, nil
 }
 
@@ -107,9 +112,12 @@ func (c *Client) deleteProviderSession(ctx context.Context, repository authStore
 
 	if err = repository.DeleteProviderSession(ctx, providerSession.ID); err != nil {
 		logger.WithError(err).Warn("Unable to delete provider session")
+	} else {
+		c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeProviderSessionDelete, providerSession.UserID, providerSession.ID))
 	}
 
 	if prvdr != nil {
+		revokeProviderSessionToken(ctx, prvdr, providerSession)
 		if err = prvdr.OnDelete(ctx, providerSession.UserID, providerSession.ID); err != nil {
 			logger.WithError(err).Warn("Unable to delete provider session from provider")
 		}
@@ -121,10 +129,22 @@ func (c *Client) GetProviderSession(ctx context.Context, id string) (*auth.Provi
 	return repository.GetProviderSession(ctx, id)
 }
 
+func (c *Client) RotateProviderSessionEncryption(ctx context.Context) (int, error) {
+	repository := c.authStore.NewProviderSessionRepository()
+	return repository.RotateProviderSessionEncryption(ctx)
+}
+
 func (c *Client) UpdateProviderSession(ctx context.Context, id string, update *auth.ProviderSessionUpdate) (*auth.ProviderSession, error) {
 	repository := c.authStore.NewProviderSessionRepository()
 
-	return repository.UpdateProviderSession(ctx, id, update)
+	providerSession, err := repository.UpdateProviderSession(ctx, id, update)
+	if err != nil {
+		return nil, err
+	} else if providerSession != nil {
+		c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeProviderSessionUpdate, providerSession.UserID, providerSession.ID))
+	}
+
+	return providerSession, nil
 }
 
 func (c *Client) DeleteProviderSession(ctx context.Context, id string) error {
@@ -146,9 +166,23 @@ func (c *Client) DeleteProviderSession(ctx context.Context, id string) error {
 		return err
 	}
 
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeProviderSessionDelete, providerSession.UserID, providerSession.ID))
+
+	revokeProviderSessionToken(ctx, prvdr, providerSession)
+
 	return prvdr.OnDelete(ctx, providerSession.UserID, providerSession.ID)
 }
 
+// revokeProviderSessionToken revokes the token with the partner, if the provider supports it. The provider session is
+// already deleted, so failure is only logged.
+func revokeProviderSessionToken(ctx context.Context, prvdr provider.Provider, providerSession *auth.ProviderSession) {
+	if tokenRevoker, ok := prvdr.(oauth.TokenRevoker); ok && providerSession.OAuthToken != nil {
+		if err := tokenRevoker.RevokeToken(ctx, providerSession.OAuthToken); err != nil {
+			log.LoggerFromContext(ctx).WithError(err).WithField("providerSessionId", providerSession.ID).Warn("Unable to revoke provider session token")
+		}
+	}
+}
+
 func (c *Client) ListUserRestrictedTokens(ctx context.Context, userID string, filter *auth.RestrictedTokenFilter, pagination *page.Pagination) (auth.RestrictedTokens, error) {
 	repository := c.authStore.NewRestrictedTokenRepository()
 	return repository.ListUserRestrictedTokens(ctx, userID, filter, pagination)
@@ -156,12 +190,25 @@ func (c *Client) ListUserRestrictedTokens(ctx context.Context, userID string, fi
 
 func (c *Client) CreateUserRestrictedToken(ctx context.Context, userID string, create *auth.RestrictedTokenCreate) (*auth.RestrictedToken, error) {
 	repository := c.authStore.NewRestrictedTokenRepository()
-	return repository.CreateUserRestrictedToken(ctx, userID, create)
+
+	restrictedToken, err := repository.CreateUserRestrictedToken(ctx, userID, create)
+	if err != nil {
+		return nil, err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeRestrictedTokenCreate, restrictedToken.UserID, restrictedToken.ID))
+	return restrictedToken, nil
 }
 
 func (c *Client) DeleteAllRestrictedTokens(ctx context.Context, userID string) error {
 	repository := c.authStore.NewRestrictedTokenRepository()
-	return repository.DeleteAllRestrictedTokens(ctx, userID)
+
+	if err := repository.DeleteAllRestrictedTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeRestrictedTokenDelete, userID, ""))
+	return nil
 }
 
 func (c *Client) GetRestrictedToken(ctx context.Context, id string) (*auth.RestrictedToken, error) {
@@ -171,11 +218,120 @@ func (c *Client) GetRestrictedToken(ctx context.Context, id string) (*auth.Restr
 
 func (c *Client) UpdateRestrictedToken(ctx context.Context, id string, update *auth.RestrictedTokenUpdate) (*auth.RestrictedToken, error) {
 	repository := c.authStore.NewRestrictedTokenRepository()
-	return repository.UpdateRestrictedToken(ctx, id, update)
+
+	restrictedToken, err := repository.UpdateRestrictedToken(ctx, id, update)
+	if err != nil {
+		return nil, err
+	} else if restrictedToken != nil {
+		c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeRestrictedTokenUpdate, restrictedToken.UserID, restrictedToken.ID))
+	}
+
+	return restrictedToken, nil
+}
+
+func (c *Client) UseRestrictedToken(ctx context.Context, id string) (*auth.RestrictedToken, error) {
+	repository := c.authStore.NewRestrictedTokenRepository()
+	return repository.UseRestrictedToken(ctx, id)
 }
 
 func (c *Client) DeleteRestrictedToken(ctx context.Context, id string) error {
 	repository := c.authStore.NewRestrictedTokenRepository()
 
-	return repository.DeleteRestrictedToken(ctx, id)
+	restrictedToken, err := repository.GetRestrictedToken(ctx, id)
+	if err != nil {
+		return err
+	} else if restrictedToken == nil {
+		return nil
+	}
+
+	if err = repository.DeleteRestrictedToken(ctx, id); err != nil {
+		return err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeRestrictedTokenDelete, restrictedToken.UserID, restrictedToken.ID))
+	return nil
+}
+
+func (c *Client) ListUserPersonalAccessTokens(ctx context.Context, userID string, filter *auth.PersonalAccessTokenFilter, pagination *page.Pagination) (auth.PersonalAccessTokens, error) {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+	return repository.ListUserPersonalAccessTokens(ctx, userID, filter, pagination)
+}
+
+func (c *Client) CreateUserPersonalAccessToken(ctx context.Context, userID string, create *auth.PersonalAccessTokenCreate) (*auth.PersonalAccessToken, error) {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+
+	personalAccessToken, err := repository.CreateUserPersonalAccessToken(ctx, userID, create)
+	if err != nil {
+		return nil, err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypePersonalAccessTokenCreate, personalAccessToken.UserID, personalAccessToken.ID))
+	return personalAccessToken, nil
+}
+
+func (c *Client) DeleteAllPersonalAccessTokens(ctx context.Context, userID string) error {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+
+	if err := repository.DeleteAllPersonalAccessTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypePersonalAccessTokenRevoke, userID, ""))
+	return nil
+}
+
+func (c *Client) GetPersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+	return repository.GetPersonalAccessToken(ctx, id)
+}
+
+func (c *Client) RevokePersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+
+	personalAccessToken, err := repository.RevokePersonalAccessToken(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if personalAccessToken != nil {
+		c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypePersonalAccessTokenRevoke, personalAccessToken.UserID, personalAccessToken.ID))
+	}
+
+	return personalAccessToken, nil
+}
+
+func (c *Client) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.PersonalAccessToken, error) {
+	repository := c.authStore.NewPersonalAccessTokenRepository()
+	return repository.AuthenticatePersonalAccessToken(ctx, token)
+}
+
+func (c *Client) DeleteAllOAuthConsents(ctx context.Context, userID string) error {
+	if err := c.authStore.NewOAuthConsentRepository().DeleteAllOAuthConsents(ctx, userID); err != nil {
+		return err
+	}
+	if err := c.authStore.NewOAuthTokenRepository().DeleteAllOAuthTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	c.RecordAuditEvent(ctx, auth.NewLifecycleAuditEvent(ctx, auth.AuditEventTypeOAuthTokenRevoke, userID, ""))
+	return nil
+}
+
+func (c *Client) IntrospectOAuthToken(ctx context.Context, token string) (*auth.OAuthTokenIntrospection, error) {
+	repository := c.authStore.NewOAuthTokenRepository()
+	oauthToken, err := repository.GetOAuthTokenByAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	} else if oauthToken == nil {
+		return &auth.OAuthTokenIntrospection{}, nil
+	}
+	return oauthToken.Introspection(), nil
+}
+
+func (c *Client) ListUserAuditEvents(ctx context.Context, userID string, filter *auth.AuditEventFilter, pagination *page.Pagination) (auth.AuditEvents, error) {
+	repository := c.authStore.NewAuditEventRepository()
+	return repository.ListUserAuditEvents(ctx, userID, filter, pagination)
+}
+
+func (c *Client) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	repository := c.authStore.NewAuditEventRepository()
+	return repository.CreateAuditEvents(ctx, events)
 }
@@ -17,6 +17,7 @@ import (
 	authServiceApiV1 "github.com/tidepool-org/platform/auth/service/api/v1"
 	"github.com/tidepool-org/platform/auth/store"
 	authMongo "github.com/tidepool-org/platform/auth/store/mongo"
+	"github.com/tidepool-org/platform/crypto"
 	dataSource "github.com/tidepool-org/platform/data/source"
 	dataSourceClient "github.com/tidepool-org/platform/data/source/client"
 	dexcomProvider "github.com/tidepool-org/platform/dexcom/provider"
@@ -34,14 +35,16 @@ import (
 
 type Service struct {
 	*serviceService.Service
-	domain            string
-	authStore         *authMongo.Store
-	dataSourceClient  *dataSourceClient.Client
-	taskClient        task.Client
-	providerFactory   provider.Factory
-	authClient        *Client
-	userEventsHandler events.Runner
-	deviceCheck       apple.DeviceCheck
+	domain             string
+	authStore          *authMongo.Store
+	dataSourceClient   *dataSourceClient.Client
+	taskClient         task.Client
+	providerFactory    provider.Factory
+	authClient         *Client
+	userEventsHandler  events.Runner
+	cacheEventsHandler events.Runner
+	deviceCheck        apple.DeviceCheck
+	appAttest          apple.AppAttest
 }
 
 func New() *Service {
@@ -55,6 +58,9 @@ func (s *Service) Run() error {
 	go func() {
 		errs <- s.userEventsHandler.Run()
 	}()
+	go func() {
+		errs <- s.cacheEventsHandler.Run()
+	}()
 	go func() {
 		errs <- s.Service.Run()
 	}()
@@ -91,6 +97,9 @@ func (s *Service) Initialize(provider application.Provider) error {
 	if err := s.initializeDeviceCheck(); err != nil {
 		return err
 	}
+	if err := s.initializeAppAttest(); err != nil {
+		return err
+	}
 	return s.initializeUserEventsHandler()
 }
 
@@ -130,6 +139,10 @@ func (s *Service) DeviceCheck() apple.DeviceCheck {
 	return s.deviceCheck
 }
 
+func (s *Service) AppAttest() apple.AppAttest {
+	return s.appAttest
+}
+
 func (s *Service) Status(ctx context.Context) *service.Status {
 	return &service.Status{
 		Version: s.VersionReporter().Long(),
@@ -190,9 +203,26 @@ func (s *Service) initializeAuthStore() error {
 		return errors.Wrap(err, "unable to load auth store config")
 	}
 
+	s.Logger().Debug("Loading provider session encryption config")
+
+	envelopeCfg := crypto.NewEnvelopeConfig()
+	if err := envelopeCfg.Load(s.ConfigReporter().WithScopes("provider_session", "encryption")); err != nil {
+		return errors.Wrap(err, "unable to load provider session encryption config")
+	}
+
+	var envelope *crypto.Envelope
+	if envelopeCfg.IsEnabled() {
+		var err error
+		if envelope, err = crypto.NewEnvelope(envelopeCfg); err != nil {
+			return errors.Wrap(err, "unable to create provider session encryption")
+		}
+	} else {
+		s.Logger().Warn("Provider session encryption is not enabled")
+	}
+
 	s.Logger().Debug("Creating auth store")
 
-	str, err := authMongo.NewStore(cfg)
+	str, err := authMongo.NewStore(cfg, envelope)
 	if err != nil {
 		return errors.Wrap(err, "unable to create auth store")
 	}
@@ -342,8 +372,7 @@ func (s *Service) initializeUserEventsHandler() error {
 	s.Logger().Debug("Initializing user events handler")
 
 	ctx := logInternal.NewContextWithLogger(context.Background(), s.Logger())
-	handler := authEvents.NewUserDataDeletionHandler(ctx, s.authClient)
-	handlers := []eventsCommon.EventHandler{handler}
+	handlers := []eventsCommon.EventHandler{authEvents.NewUserDataDeletionHandler(ctx, s.authClient)}
 	runner := events.NewRunner(handlers)
 
 	if err := runner.Initialize(); err != nil {
@@ -351,6 +380,15 @@ func (s *Service) initializeUserEventsHandler() error {
 	}
 	s.userEventsHandler = runner
 
+	// Every replica caches independently, so each must receive every event
+	cacheHandlers := []eventsCommon.EventHandler{authEvents.NewUserCacheInvalidationHandler(ctx, s.authClient)}
+	cacheRunner := events.NewBroadcastRunner(cacheHandlers)
+
+	if err := cacheRunner.Initialize(); err != nil {
+		return errors.Wrap(err, "unable to initialize cache events runner")
+	}
+	s.cacheEventsHandler = cacheRunner
+
 	return nil
 }
 
@@ -371,6 +409,27 @@ func (s *Service) initializeDeviceCheck() error {
 	return nil
 }
 
+func (s *Service) initializeAppAttest() error {
+	s.Logger().Debug("Initializing app attest")
+
+	cfg := apple.NewAppAttestConfig()
+	if err := cfg.Load(); err != nil {
+		return errors.Wrap(err, "unable to load app attest config")
+	}
+	if !cfg.IsConfigured() {
+		s.Logger().Warn("App attest is not configured")
+		return nil
+	}
+
+	appAttest, err := apple.NewAppAttest(cfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to create app attest")
+	}
+	s.appAttest = appAttest
+
+	return nil
+}
+
 func (s *Service) terminateUserEventsHandler() {
 	if s.userEventsHandler != nil {
 		s.Logger().Info("Terminating the userEventsHandler")
@@ -379,4 +438,11 @@ func (s *Service) terminateUserEventsHandler() {
 		}
 		s.userEventsHandler = nil
 	}
+	if s.cacheEventsHandler != nil {
+		s.Logger().Info("Terminating the cacheEventsHandler")
+		if err := s.cacheEventsHandler.Terminate(); err != nil {
+			s.Logger().Errorf("Error while terminating the cacheEventsHandler: %v", err)
+		}
+		s.cacheEventsHandler = nil
+	}
 }
@@ -27,6 +27,7 @@ type Service struct {
 	ProviderFactoryImpl        *providerTest.Factory
 	TaskClientInvocations      int
 	TaskClientImpl             *taskTest.Client
+	AppAttestImpl              apple.AppAttest
 	StatusInvocations          int
 	StatusOutputs              []*service.Status
 }
@@ -72,6 +73,10 @@ func (s *Service) DeviceCheck() apple.DeviceCheck {
 	return nil
 }
 
+func (s *Service) AppAttest() apple.AppAttest {
+	return s.AppAttestImpl
+}
+
 func (s *Service) Status(ctx context.Context) *service.Status {
 	s.StatusInvocations++
 
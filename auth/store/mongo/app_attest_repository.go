@@ -0,0 +1,182 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+)
+
+type AppAttestChallengeRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (a *AppAttestChallengeRepository) EnsureIndexes() error {
+	return a.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "challenge", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "expirationTime", Value: 1}},
+			Options: options.Index().
+				SetExpireAfterSeconds(0).
+				SetBackground(true),
+		},
+	})
+}
+
+func (a *AppAttestChallengeRepository) CreateAppAttestChallenge(ctx context.Context, userID string) (*auth.AppAttestChallenge, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	challenge, err := auth.NewAppAttestChallenge(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	_, err = a.InsertOne(ctx, challenge)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateAppAttestChallenge")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create app attest challenge")
+	}
+
+	return challenge, nil
+}
+
+func (a *AppAttestChallengeRepository) ConsumeAppAttestChallenge(ctx context.Context, userID string, challenge string) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if userID == "" {
+		return false, errors.New("user id is missing")
+	}
+	if challenge == "" {
+		return false, errors.New("challenge is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	selector := bson.M{
+		"challenge":      challenge,
+		"userId":         userID,
+		"expirationTime": bson.M{"$gt": now},
+	}
+	changeInfo, err := a.DeleteOne(ctx, selector)
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ConsumeAppAttestChallenge")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to consume app attest challenge")
+	}
+
+	return changeInfo.DeletedCount > 0, nil
+}
+
+type AppAttestKeyRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (a *AppAttestKeyRepository) EnsureIndexes() error {
+	return a.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+	})
+}
+
+func (a *AppAttestKeyRepository) CreateAppAttestKey(ctx context.Context, key *auth.AppAttestKey) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if key == nil {
+		return errors.New("key is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": key.ID, "userId": key.UserID})
+
+	_, err := a.InsertOne(ctx, key)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateAppAttestKey")
+	if err != nil {
+		return errors.Wrap(err, "unable to create app attest key")
+	}
+
+	return nil
+}
+
+func (a *AppAttestKeyRepository) GetAppAttestKey(ctx context.Context, userID string, id string) (*auth.AppAttestKey, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": id, "userId": userID})
+
+	key := &auth.AppAttestKey{}
+	err := a.FindOne(ctx, bson.M{"id": id, "userId": userID}).Decode(key)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetAppAttestKey")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to get app attest key")
+	}
+
+	return key, nil
+}
+
+func (a *AppAttestKeyRepository) UpdateAppAttestKeyCounter(ctx context.Context, id string, previousCounter int64, counter int64) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if id == "" {
+		return false, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	selector := bson.M{
+		"id":      id,
+		"counter": previousCounter,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"counter":           counter,
+			"lastAssertionTime": now,
+		},
+	}
+	changeInfo, err := a.UpdateOne(ctx, selector, update)
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UpdateAppAttestKeyCounter")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to update app attest key counter")
+	}
+
+	return changeInfo.ModifiedCount > 0, nil
+}
@@ -0,0 +1,139 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+// AuditEventRepository only inserts and finds audit events. Audit events are retained after the target user is
+// deleted.
+type AuditEventRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (a *AuditEventRepository) EnsureIndexes() error {
+	return a.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "targetUserId", Value: 1}, {Key: "time", Value: -1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+	})
+}
+
+func (a *AuditEventRepository) ListUserAuditEvents(ctx context.Context, userID string, filter *auth.AuditEventFilter, pagination *page.Pagination) (auth.AuditEvents, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if filter == nil {
+		filter = auth.NewAuditEventFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "filter": filter, "pagination": pagination})
+
+	selector := bson.M{
+		"targetUserId": userID,
+	}
+	if filter.Type != nil {
+		selector["type"] = bson.M{"$in": *filter.Type}
+	}
+	if filter.StartTime != nil || filter.EndTime != nil {
+		timeSelector := bson.M{}
+		if filter.StartTime != nil {
+			timeSelector["$gte"] = *filter.StartTime
+		}
+		if filter.EndTime != nil {
+			timeSelector["$lt"] = *filter.EndTime
+		}
+		selector["time"] = timeSelector
+	}
+
+	events := auth.AuditEvents{}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"time": -1})
+	cursor, err := a.Find(ctx, selector, opts)
+	logger.WithFields(log.Fields{"count": len(events), "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListUserAuditEvents")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list user audit events")
+	}
+
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, errors.Wrap(err, "unable to decode user audit events")
+	}
+
+	if events == nil {
+		events = auth.AuditEvents{}
+	}
+
+	return events, nil
+}
+
+// CreateAuditEvents inserts the audit events, ignoring any already inserted so that a batch can be safely retried
+func (a *AuditEventRepository) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if err := structureValidator.New().Validate(&events); err != nil {
+		return errors.Wrap(err, "events are invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("count", len(events))
+
+	documents := make([]interface{}, len(events))
+	for index, event := range events {
+		documents[index] = event
+	}
+
+	_, err := a.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && isOnlyDuplicateKeyError(err) {
+		err = nil
+	}
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateAuditEvents")
+	if err != nil {
+		return errors.Wrap(err, "unable to create audit events")
+	}
+
+	return nil
+}
+
+func isOnlyDuplicateKeyError(err error) bool {
+	bulkWriteException, ok := err.(mongo.BulkWriteException)
+	if !ok || bulkWriteException.WriteConcernError != nil {
+		return false
+	}
+	for _, writeError := range bulkWriteException.WriteErrors {
+		if writeError.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+)
+
+type OAuthAuthorizationCodeRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (o *OAuthAuthorizationCodeRepository) EnsureIndexes() error {
+	return o.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "codeHash", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "expirationTime", Value: 1}},
+			Options: options.Index().
+				SetExpireAfterSeconds(0).
+				SetBackground(true),
+		},
+	})
+}
+
+func (o *OAuthAuthorizationCodeRepository) CreateOAuthAuthorizationCode(ctx context.Context, code *auth.OAuthAuthorizationCode) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if code == nil {
+		return errors.New("code is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"clientId": code.ClientID, "userId": code.UserID})
+
+	_, err := o.InsertOne(ctx, code)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateOAuthAuthorizationCode")
+	if err != nil {
+		return errors.Wrap(err, "unable to create oauth authorization code")
+	}
+
+	return nil
+}
+
+func (o *OAuthAuthorizationCodeRepository) ConsumeOAuthAuthorizationCode(ctx context.Context, clientID string, code string) (*auth.OAuthAuthorizationCode, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if clientID == "" {
+		return nil, errors.New("client id is missing")
+	}
+	if code == "" {
+		return nil, errors.New("code is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("clientId", clientID)
+
+	selector := bson.M{
+		"clientId":       clientID,
+		"codeHash":       auth.HashOAuthSecret(code),
+		"expirationTime": bson.M{"$gt": now},
+	}
+	authorizationCode := &auth.OAuthAuthorizationCode{}
+	err := o.FindOneAndDelete(ctx, selector).Decode(authorizationCode)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("ConsumeOAuthAuthorizationCode")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to consume oauth authorization code")
+	}
+
+	return authorizationCode, nil
+}
@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+)
+
+type OAuthClientRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (o *OAuthClientRepository) EnsureIndexes() error {
+	return o.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+	})
+}
+
+func (o *OAuthClientRepository) CreateOAuthClient(ctx context.Context, client *auth.OAuthClient) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if client == nil {
+		return errors.New("client is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": client.ID, "name": client.Name})
+
+	_, err := o.InsertOne(ctx, client)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateOAuthClient")
+	if err != nil {
+		return errors.Wrap(err, "unable to create oauth client")
+	}
+
+	return nil
+}
+
+func (o *OAuthClientRepository) GetOAuthClient(ctx context.Context, id string) (*auth.OAuthClient, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	client := &auth.OAuthClient{}
+	err := o.FindOne(ctx, bson.M{"id": id}).Decode(client)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetOAuthClient")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to get oauth client")
+	}
+
+	return client, nil
+}
+
+func (o *OAuthClientRepository) DeleteOAuthClient(ctx context.Context, id string) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if id == "" {
+		return false, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	changeInfo, err := o.DeleteOne(ctx, bson.M{"id": id})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteOAuthClient")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to delete oauth client")
+	}
+
+	return changeInfo.DeletedCount > 0, nil
+}
@@ -0,0 +1,148 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+type OAuthConsentRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (o *OAuthConsentRepository) EnsureIndexes() error {
+	return o.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "clientId", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+	})
+}
+
+func (o *OAuthConsentRepository) ListUserOAuthConsents(ctx context.Context, userID string, pagination *page.Pagination) (auth.OAuthConsents, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "pagination": pagination})
+
+	consents := auth.OAuthConsents{}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"createdTime": -1})
+	cursor, err := o.Find(ctx, bson.M{"userId": userID}, opts)
+	logger.WithFields(log.Fields{"count": len(consents), "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListUserOAuthConsents")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list user oauth consents")
+	}
+
+	if err = cursor.All(ctx, &consents); err != nil {
+		return nil, errors.Wrap(err, "unable to decode user oauth consents")
+	}
+
+	if consents == nil {
+		consents = auth.OAuthConsents{}
+	}
+
+	return consents, nil
+}
+
+func (o *OAuthConsentRepository) UpsertOAuthConsent(ctx context.Context, userID string, clientID string, scopes []string) (*auth.OAuthConsent, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if clientID == "" {
+		return nil, errors.New("client id is missing")
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("scopes are missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "clientId": clientID, "scopes": scopes})
+
+	selector := bson.M{
+		"userId":   userID,
+		"clientId": clientID,
+	}
+	update := bson.M{
+		"$addToSet":    bson.M{"scopes": bson.M{"$each": scopes}},
+		"$set":         bson.M{"modifiedTime": now},
+		"$setOnInsert": bson.M{"createdTime": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	consent := &auth.OAuthConsent{}
+	err := o.FindOneAndUpdate(ctx, selector, update, opts).Decode(consent)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("UpsertOAuthConsent")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to upsert oauth consent")
+	}
+
+	return consent, nil
+}
+
+func (o *OAuthConsentRepository) DeleteOAuthConsent(ctx context.Context, userID string, clientID string) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if userID == "" {
+		return false, errors.New("user id is missing")
+	}
+	if clientID == "" {
+		return false, errors.New("client id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "clientId": clientID})
+
+	changeInfo, err := o.DeleteOne(ctx, bson.M{"userId": userID, "clientId": clientID})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteOAuthConsent")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to delete oauth consent")
+	}
+
+	return changeInfo.DeletedCount > 0, nil
+}
+
+func (o *OAuthConsentRepository) DeleteAllOAuthConsents(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	changeInfo, err := o.DeleteMany(ctx, bson.M{"userId": userID})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteAllOAuthConsents")
+	if err != nil {
+		return errors.Wrap(err, "unable to delete all oauth consents")
+	}
+
+	return nil
+}
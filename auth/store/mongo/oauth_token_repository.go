@@ -0,0 +1,194 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+)
+
+type OAuthTokenRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (o *OAuthTokenRepository) EnsureIndexes() error {
+	return o.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "accessTokenHash", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "refreshTokenHash", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "clientId", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "clientId", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "refreshTokenExpirationTime", Value: 1}},
+			Options: options.Index().
+				SetExpireAfterSeconds(0).
+				SetBackground(true),
+		},
+	})
+}
+
+func (o *OAuthTokenRepository) CreateOAuthToken(ctx context.Context, token *auth.OAuthToken) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if token == nil {
+		return errors.New("token is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": token.ID, "clientId": token.ClientID, "userId": token.UserID})
+
+	_, err := o.InsertOne(ctx, token)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CreateOAuthToken")
+	if err != nil {
+		return errors.Wrap(err, "unable to create oauth token")
+	}
+
+	return nil
+}
+
+func (o *OAuthTokenRepository) GetOAuthTokenByAccessToken(ctx context.Context, accessToken string) (*auth.OAuthToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if accessToken == "" {
+		return nil, errors.New("access token is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx)
+
+	token := &auth.OAuthToken{}
+	err := o.FindOne(ctx, bson.M{"accessTokenHash": auth.HashOAuthSecret(accessToken)}).Decode(token)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetOAuthTokenByAccessToken")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to get oauth token by access token")
+	}
+
+	return token, nil
+}
+
+func (o *OAuthTokenRepository) RevokeOAuthToken(ctx context.Context, clientID string, accessOrRefreshToken string) (*auth.OAuthToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if clientID == "" {
+		return nil, errors.New("client id is missing")
+	}
+	if accessOrRefreshToken == "" {
+		return nil, errors.New("access or refresh token is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("clientId", clientID)
+
+	selector := bson.M{
+		"clientId":    clientID,
+		"revokedTime": bson.M{"$exists": false},
+	}
+	if auth.IsOAuthRefreshToken(accessOrRefreshToken) {
+		selector["refreshTokenHash"] = auth.HashOAuthSecret(accessOrRefreshToken)
+	} else {
+		selector["accessTokenHash"] = auth.HashOAuthSecret(accessOrRefreshToken)
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	token := &auth.OAuthToken{}
+	err := o.FindOneAndUpdate(ctx, selector, bson.M{"$set": bson.M{"revokedTime": now}}, opts).Decode(token)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("RevokeOAuthToken")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to revoke oauth token")
+	}
+
+	return token, nil
+}
+
+func (o *OAuthTokenRepository) RevokeUserClientOAuthTokens(ctx context.Context, userID string, clientID string) error {
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+	if clientID == "" {
+		return errors.New("client id is missing")
+	}
+
+	return o.revokeOAuthTokens(ctx, bson.M{"userId": userID, "clientId": clientID}, "RevokeUserClientOAuthTokens")
+}
+
+func (o *OAuthTokenRepository) RevokeClientOAuthTokens(ctx context.Context, clientID string) error {
+	if clientID == "" {
+		return errors.New("client id is missing")
+	}
+
+	return o.revokeOAuthTokens(ctx, bson.M{"clientId": clientID}, "RevokeClientOAuthTokens")
+}
+
+func (o *OAuthTokenRepository) DeleteAllOAuthTokens(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	changeInfo, err := o.DeleteMany(ctx, bson.M{"userId": userID})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteAllOAuthTokens")
+	if err != nil {
+		return errors.Wrap(err, "unable to delete all oauth tokens")
+	}
+
+	return nil
+}
+
+func (o *OAuthTokenRepository) revokeOAuthTokens(ctx context.Context, selector bson.M, operation string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("selector", selector)
+
+	selector["revokedTime"] = bson.M{"$exists": false}
+	changeInfo, err := o.UpdateMany(ctx, selector, bson.M{"$set": bson.M{"revokedTime": now}})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug(operation)
+	if err != nil {
+		return errors.Wrap(err, "unable to revoke oauth tokens")
+	}
+
+	return nil
+}
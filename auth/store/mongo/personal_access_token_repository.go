@@ -0,0 +1,229 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+type PersonalAccessTokenRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (p *PersonalAccessTokenRepository) EnsureIndexes() error {
+	return p.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+	})
+}
+
+func (p *PersonalAccessTokenRepository) ListUserPersonalAccessTokens(ctx context.Context, userID string, filter *auth.PersonalAccessTokenFilter, pagination *page.Pagination) (auth.PersonalAccessTokens, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if filter == nil {
+		filter = auth.NewPersonalAccessTokenFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "filter": filter, "pagination": pagination})
+
+	personalAccessTokens := auth.PersonalAccessTokens{}
+	selector := bson.M{
+		"userId": userID,
+	}
+	if filter.Revoked != nil {
+		selector["revokedTime"] = bson.M{"$exists": *filter.Revoked}
+	}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"createdTime": -1})
+	cursor, err := p.Find(ctx, selector, opts)
+	logger.WithFields(log.Fields{"count": len(personalAccessTokens), "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListUserPersonalAccessTokens")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list user personal access tokens")
+	}
+
+	if err = cursor.All(ctx, &personalAccessTokens); err != nil {
+		return nil, errors.Wrap(err, "unable to decode user personal access tokens")
+	}
+
+	if personalAccessTokens == nil {
+		personalAccessTokens = auth.PersonalAccessTokens{}
+	}
+
+	return personalAccessTokens, nil
+}
+
+func (p *PersonalAccessTokenRepository) CreateUserPersonalAccessToken(ctx context.Context, userID string, create *auth.PersonalAccessTokenCreate) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	personalAccessToken, err := auth.NewPersonalAccessToken(userID, create)
+	if err != nil {
+		return nil, err
+	} else if err = structureValidator.New().Validate(personalAccessToken); err != nil {
+		return nil, errors.Wrap(err, "personal access token is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "name": create.Name})
+
+	_, err = p.InsertOne(ctx, personalAccessToken)
+	logger.WithFields(log.Fields{"id": personalAccessToken.ID, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("CreateUserPersonalAccessToken")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create user personal access token")
+	}
+
+	return personalAccessToken, nil
+}
+
+func (p *PersonalAccessTokenRepository) DeleteAllPersonalAccessTokens(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	changeInfo, err := p.DeleteMany(ctx, bson.M{"userId": userID})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteAllPersonalAccessTokens")
+	if err != nil {
+		return errors.Wrap(err, "unable to delete all personal access tokens")
+	}
+
+	return nil
+}
+
+func (p *PersonalAccessTokenRepository) GetPersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	personalAccessToken, err := p.findOne(ctx, bson.M{"id": id})
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetPersonalAccessToken")
+	return personalAccessToken, err
+}
+
+func (p *PersonalAccessTokenRepository) RevokePersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	selector := bson.M{
+		"id":          id,
+		"revokedTime": bson.M{"$exists": false},
+	}
+	changeInfo, err := p.UpdateMany(ctx, selector, p.ConstructUpdate(bson.M{"revokedTime": now}, bson.M{}))
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("RevokePersonalAccessToken")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to revoke personal access token")
+	}
+
+	return p.GetPersonalAccessToken(ctx, id)
+}
+
+func (p *PersonalAccessTokenRepository) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.PersonalAccessToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if token == "" {
+		return nil, errors.New("token is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx)
+
+	selector := bson.M{
+		"tokenHash":   auth.HashPersonalAccessTokenSecret(token),
+		"revokedTime": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expirationTime": bson.M{"$exists": false}},
+			bson.M{"expirationTime": bson.M{"$gt": now}},
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	personalAccessToken := &auth.PersonalAccessToken{}
+	err := p.FindOneAndUpdate(ctx, selector, bson.M{"$set": bson.M{"lastUsedTime": now}}, opts).Decode(personalAccessToken)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("AuthenticatePersonalAccessToken")
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to authenticate personal access token")
+	}
+
+	logger.WithFields(log.Fields{"id": personalAccessToken.ID, "userId": personalAccessToken.UserID}).Debug("Authenticated personal access token")
+	return personalAccessToken, nil
+}
+
+func (p *PersonalAccessTokenRepository) findOne(ctx context.Context, selector bson.M) (*auth.PersonalAccessToken, error) {
+	personalAccessTokens := auth.PersonalAccessTokens{}
+	cursor, err := p.Find(ctx, selector, options.Find().SetLimit(2))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get personal access token")
+	}
+
+	if err = cursor.All(ctx, &personalAccessTokens); err != nil {
+		return nil, errors.Wrap(err, "unable to decode personal access tokens")
+	}
+
+	switch count := len(personalAccessTokens); count {
+	case 0:
+		return nil, nil
+	case 1:
+		return personalAccessTokens[0], nil
+	default:
+		log.LoggerFromContext(ctx).WithField("count", count).Warn("Multiple personal access tokens found")
+		return personalAccessTokens[0], nil
+	}
+}
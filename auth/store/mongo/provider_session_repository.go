@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,8 +10,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/crypto"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/oauth"
 	"github.com/tidepool-org/platform/page"
 	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
@@ -18,6 +21,14 @@ import (
 
 type ProviderSessionRepository struct {
 	*storeStructuredMongo.Repository
+	envelope *crypto.Envelope
+}
+
+// providerSessionDocument is the stored provider session. If encrypted, the OAuth token is stored only in the
+// encrypted OAuth token field.
+type providerSessionDocument struct {
+	auth.ProviderSession `bson:",inline"`
+	EncryptedOAuthToken  *crypto.Sealed `bson:"encryptedOAuthToken,omitempty"`
 }
 
 func (p *ProviderSessionRepository) EnsureIndexes() error {
@@ -63,7 +74,7 @@ func (p *ProviderSessionRepository) ListUserProviderSessions(ctx context.Context
 	now := time.Now()
 	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "filter": filter, "pagination": pagination})
 
-	providerSessions := auth.ProviderSessions{}
+	documents := []*providerSessionDocument{}
 	selector := bson.M{
 		"userId": userID,
 	}
@@ -76,16 +87,21 @@ func (p *ProviderSessionRepository) ListUserProviderSessions(ctx context.Context
 	opts := storeStructuredMongo.FindWithPagination(pagination).
 		SetSort(bson.M{"createdTime": -1})
 	cursor, err := p.Find(ctx, selector, opts)
-	logger.WithFields(log.Fields{"count": len(providerSessions), "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListUserProviderSessions")
+	logger.WithFields(log.Fields{"duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListUserProviderSessions")
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to list user provider sessions")
 	}
-	if err = cursor.All(ctx, &providerSessions); err != nil {
+	if err = cursor.All(ctx, &documents); err != nil {
 		return nil, errors.Wrap(err, "unable to decode user provider sessions")
 	}
 
-	if providerSessions == nil {
-		providerSessions = auth.ProviderSessions{}
+	providerSessions := auth.ProviderSessions{}
+	for _, document := range documents {
+		providerSession, err := p.decryptDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		providerSessions = append(providerSessions, providerSession)
 	}
 
 	return providerSessions, nil
@@ -106,7 +122,12 @@ func (p *ProviderSessionRepository) CreateUserProviderSession(ctx context.Contex
 	now := time.Now()
 	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "create": create})
 
-	_, err = p.InsertOne(ctx, providerSession)
+	document, err := p.encryptDocument(providerSession)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.InsertOne(ctx, document)
 	logger.WithFields(log.Fields{"id": providerSession.ID, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("CreateUserProviderSession")
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create user provider session")
@@ -146,14 +167,16 @@ func (p *ProviderSessionRepository) GetProviderSession(ctx context.Context, id s
 	now := time.Now()
 	logger := log.LoggerFromContext(ctx).WithField("id", id)
 
-	var providerSession *auth.ProviderSession
-	err := p.FindOne(ctx, bson.M{"id": id}).Decode(&providerSession)
+	var document *providerSessionDocument
+	err := p.FindOne(ctx, bson.M{"id": id}).Decode(&document)
 	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetProviderSession")
 	if err == mongo.ErrNoDocuments {
 		return nil, nil
+	} else if err != nil {
+		return nil, err
 	}
 
-	return providerSession, err
+	return p.decryptDocument(document)
 }
 
 func (p *ProviderSessionRepository) UpdateProviderSession(ctx context.Context, id string, update *auth.ProviderSessionUpdate) (*auth.ProviderSession, error) {
@@ -177,9 +200,20 @@ func (p *ProviderSessionRepository) UpdateProviderSession(ctx context.Context, i
 	}
 	unset := bson.M{}
 	if update.OAuthToken != nil {
-		set["oauthToken"] = update.OAuthToken
+		if p.envelope != nil {
+			encryptedOAuthToken, err := p.encryptOAuthToken(update.OAuthToken)
+			if err != nil {
+				return nil, err
+			}
+			set["encryptedOAuthToken"] = encryptedOAuthToken
+			unset["oauthToken"] = true
+		} else {
+			set["oauthToken"] = update.OAuthToken
+			unset["encryptedOAuthToken"] = true
+		}
 	} else {
 		unset["oauthToken"] = true
+		unset["encryptedOAuthToken"] = true
 	}
 	changeInfo, err := p.UpdateMany(ctx, bson.M{"id": id}, p.ConstructUpdate(set, unset))
 	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UpdateProviderSession")
@@ -209,3 +243,110 @@ func (p *ProviderSessionRepository) DeleteProviderSession(ctx context.Context, i
 
 	return nil
 }
+
+// RotateProviderSessionEncryption encrypts the OAuth token of each provider session that is either stored in plaintext
+// or encrypted with a master key other than the current master key, and returns the number of provider sessions
+// rotated. If encryption is not enabled, then there is nothing to rotate.
+func (p *ProviderSessionRepository) RotateProviderSessionEncryption(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+	if p.envelope == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx)
+
+	selector := bson.M{
+		"$or": bson.A{
+			bson.M{"oauthToken": bson.M{"$exists": true}},
+			bson.M{"encryptedOAuthToken": bson.M{"$exists": true}, "encryptedOAuthToken.masterKeyId": bson.M{"$ne": p.envelope.MasterKeyID()}},
+		},
+	}
+	cursor, err := p.Find(ctx, selector)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to list provider sessions to rotate")
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var document *providerSessionDocument
+		if err = cursor.Decode(&document); err != nil {
+			return count, errors.Wrap(err, "unable to decode provider session to rotate")
+		}
+
+		providerSession, err := p.decryptDocument(document)
+		if err != nil {
+			logger.WithError(err).WithField("id", document.ID).Error("Unable to decrypt provider session to rotate")
+			continue
+		} else if providerSession.OAuthToken == nil {
+			continue
+		}
+
+		encryptedOAuthToken, err := p.encryptOAuthToken(providerSession.OAuthToken)
+		if err != nil {
+			return count, err
+		}
+
+		// Only update if unchanged since read, otherwise a concurrently refreshed token could be overwritten
+		selector := bson.M{"id": document.ID, "modifiedTime": document.ModifiedTime}
+		set := bson.M{"encryptedOAuthToken": encryptedOAuthToken}
+		unset := bson.M{"oauthToken": true}
+		if _, err = p.UpdateOne(ctx, selector, p.ConstructUpdate(set, unset)); err != nil {
+			return count, errors.Wrap(err, "unable to update provider session to rotate")
+		}
+		count++
+	}
+	if err = cursor.Err(); err != nil {
+		return count, errors.Wrap(err, "unable to iterate provider sessions to rotate")
+	}
+
+	logger.WithFields(log.Fields{"count": count, "duration": time.Since(now) / time.Microsecond}).Debug("RotateProviderSessionEncryption")
+	return count, nil
+}
+
+func (p *ProviderSessionRepository) encryptDocument(providerSession *auth.ProviderSession) (*providerSessionDocument, error) {
+	document := &providerSessionDocument{ProviderSession: *providerSession}
+	if p.envelope != nil && providerSession.OAuthToken != nil {
+		encryptedOAuthToken, err := p.encryptOAuthToken(providerSession.OAuthToken)
+		if err != nil {
+			return nil, err
+		}
+		document.OAuthToken = nil
+		document.EncryptedOAuthToken = encryptedOAuthToken
+	}
+	return document, nil
+}
+
+func (p *ProviderSessionRepository) decryptDocument(document *providerSessionDocument) (*auth.ProviderSession, error) {
+	providerSession := document.ProviderSession
+	if document.EncryptedOAuthToken != nil {
+		if p.envelope == nil {
+			return nil, errors.New("unable to decrypt oauth token without encryption")
+		}
+		bites, err := p.envelope.Open(document.EncryptedOAuthToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decrypt oauth token")
+		}
+		oauthToken := oauth.NewToken()
+		if err = json.Unmarshal(bites, oauthToken); err != nil {
+			return nil, errors.Wrap(err, "unable to decode oauth token")
+		}
+		providerSession.OAuthToken = oauthToken
+	}
+	return &providerSession, nil
+}
+
+func (p *ProviderSessionRepository) encryptOAuthToken(oauthToken *oauth.Token) (*crypto.Sealed, error) {
+	bites, err := json.Marshal(oauthToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode oauth token")
+	}
+	encryptedOAuthToken, err := p.envelope.Seal(bites)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt oauth token")
+	}
+	return encryptedOAuthToken, nil
+}
@@ -61,6 +61,26 @@ func (r *RestrictedTokenRepository) ListUserRestrictedTokens(ctx context.Context
 	selector := bson.M{
 		"userId": userID,
 	}
+	if filter.Expired != nil {
+		if *filter.Expired {
+			selector["expirationTime"] = bson.M{"$lte": now}
+		} else {
+			selector["expirationTime"] = bson.M{"$gt": now}
+		}
+	}
+	if filter.Method != nil || filter.Path != nil {
+		scopeSelector := bson.M{}
+		if filter.Method != nil {
+			scopeSelector["$or"] = bson.A{
+				bson.M{"methods": *filter.Method},
+				bson.M{"methods": bson.M{"$exists": false}},
+			}
+		}
+		if filter.Path != nil {
+			scopeSelector["path"] = *filter.Path
+		}
+		selector["scopes"] = bson.M{"$elemMatch": scopeSelector}
+	}
 	opts := storeStructuredMongo.FindWithPagination(pagination).
 		SetSort(bson.M{"createdTime": -1})
 	cursor, err := r.Find(ctx, selector, opts)
@@ -171,6 +191,26 @@ func (r *RestrictedTokenRepository) UpdateRestrictedToken(ctx context.Context, i
 		return nil, errors.Wrap(err, "update is invalid")
 	}
 
+	restrictedToken, err := r.GetRestrictedToken(ctx, id)
+	if err != nil || restrictedToken == nil {
+		return nil, err
+	}
+
+	// Validate the updated token as a whole, since the maximum expiration time depends upon the paths and scopes
+	if update.Paths != nil || update.Scopes != nil {
+		restrictedToken.Paths = update.Paths
+		restrictedToken.Scopes = update.Scopes
+	}
+	if update.ExpirationTime != nil {
+		restrictedToken.ExpirationTime = *update.ExpirationTime
+	}
+	if update.MaximumUseCount != nil {
+		restrictedToken.MaximumUseCount = update.MaximumUseCount
+	}
+	if err = structureValidator.New().Validate(restrictedToken); err != nil {
+		return nil, errors.Wrap(err, "restricted token is invalid")
+	}
+
 	now := time.Now()
 	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": id, "update": update})
 
@@ -178,12 +218,24 @@ func (r *RestrictedTokenRepository) UpdateRestrictedToken(ctx context.Context, i
 		"modifiedTime": now,
 	}
 	unset := bson.M{}
-	if update.Paths != nil {
-		set["path"] = *update.Paths
+	if update.Paths != nil || update.Scopes != nil {
+		if update.Paths != nil {
+			set["paths"] = *update.Paths
+		} else {
+			unset["paths"] = true
+		}
+		if update.Scopes != nil {
+			set["scopes"] = *update.Scopes
+		} else {
+			unset["scopes"] = true
+		}
 	}
 	if update.ExpirationTime != nil {
 		set["expirationTime"] = *update.ExpirationTime
 	}
+	if update.MaximumUseCount != nil {
+		set["maximumUseCount"] = *update.MaximumUseCount
+	}
 	changeInfo, err := r.UpdateMany(ctx, bson.M{"id": id}, r.ConstructUpdate(set, unset))
 	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UpdateRestrictedToken")
 	if err != nil {
@@ -212,3 +264,33 @@ func (r *RestrictedTokenRepository) DeleteRestrictedToken(ctx context.Context, i
 
 	return nil
 }
+
+func (r *RestrictedTokenRepository) UseRestrictedToken(ctx context.Context, id string) (*auth.RestrictedToken, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	// Only increment the use count if uses remain, atomically, so concurrent requests cannot exceed the maximum
+	selector := bson.M{
+		"id": id,
+		"$or": bson.A{
+			bson.M{"maximumUseCount": bson.M{"$exists": false}},
+			bson.M{"$expr": bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$useCount", 0}}, "$maximumUseCount"}}},
+		},
+	}
+	changeInfo, err := r.UpdateOne(ctx, selector, bson.M{"$inc": bson.M{"useCount": 1}})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UseRestrictedToken")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to use restricted token")
+	} else if changeInfo.ModifiedCount == 0 {
+		return nil, nil
+	}
+
+	return r.GetRestrictedToken(ctx, id)
+}
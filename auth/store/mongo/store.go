@@ -2,22 +2,27 @@ package mongo
 
 import (
 	"github.com/tidepool-org/platform/auth/store"
+	"github.com/tidepool-org/platform/crypto"
 	"github.com/tidepool-org/platform/errors"
 	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
 )
 
 type Store struct {
 	*storeStructuredMongo.Store
+	envelope *crypto.Envelope
 }
 
-func NewStore(c *storeStructuredMongo.Config) (*Store, error) {
+// NewStore returns the auth store. If the envelope is specified, then provider session OAuth tokens are encrypted at
+// rest, otherwise they are stored in plaintext.
+func NewStore(c *storeStructuredMongo.Config, envelope *crypto.Envelope) (*Store, error) {
 	if c == nil {
 		return nil, errors.New("config is missing")
 	}
 
 	str, err := storeStructuredMongo.NewStore(c)
 	return &Store{
-		str,
+		Store:    str,
+		envelope: envelope,
 	}, err
 }
 
@@ -28,7 +33,47 @@ func (s *Store) EnsureIndexes() error {
 	}
 
 	restrictedTokenRepository := s.restrictedTokenRepository()
-	return restrictedTokenRepository.EnsureIndexes()
+	if err := restrictedTokenRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	personalAccessTokenRepository := s.personalAccessTokenRepository()
+	if err := personalAccessTokenRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	appAttestChallengeRepository := s.appAttestChallengeRepository()
+	if err := appAttestChallengeRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	appAttestKeyRepository := s.appAttestKeyRepository()
+	if err := appAttestKeyRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	oauthClientRepository := s.oauthClientRepository()
+	if err := oauthClientRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	oauthAuthorizationCodeRepository := s.oauthAuthorizationCodeRepository()
+	if err := oauthAuthorizationCodeRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	oauthTokenRepository := s.oauthTokenRepository()
+	if err := oauthTokenRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	oauthConsentRepository := s.oauthConsentRepository()
+	if err := oauthConsentRepository.EnsureIndexes(); err != nil {
+		return err
+	}
+
+	auditEventRepository := s.auditEventRepository()
+	return auditEventRepository.EnsureIndexes()
 }
 
 func (s *Store) NewProviderSessionRepository() store.ProviderSessionRepository {
@@ -39,9 +84,42 @@ func (s *Store) NewRestrictedTokenRepository() store.RestrictedTokenRepository {
 	return s.restrictedTokenRepository()
 }
 
+func (s *Store) NewPersonalAccessTokenRepository() store.PersonalAccessTokenRepository {
+	return s.personalAccessTokenRepository()
+}
+
+func (s *Store) NewAppAttestChallengeRepository() store.AppAttestChallengeRepository {
+	return s.appAttestChallengeRepository()
+}
+
+func (s *Store) NewAppAttestKeyRepository() store.AppAttestKeyRepository {
+	return s.appAttestKeyRepository()
+}
+
+func (s *Store) NewOAuthClientRepository() store.OAuthClientRepository {
+	return s.oauthClientRepository()
+}
+
+func (s *Store) NewOAuthAuthorizationCodeRepository() store.OAuthAuthorizationCodeRepository {
+	return s.oauthAuthorizationCodeRepository()
+}
+
+func (s *Store) NewOAuthTokenRepository() store.OAuthTokenRepository {
+	return s.oauthTokenRepository()
+}
+
+func (s *Store) NewOAuthConsentRepository() store.OAuthConsentRepository {
+	return s.oauthConsentRepository()
+}
+
+func (s *Store) NewAuditEventRepository() store.AuditEventRepository {
+	return s.auditEventRepository()
+}
+
 func (s *Store) providerSessionRepository() *ProviderSessionRepository {
 	return &ProviderSessionRepository{
-		s.Store.GetRepository("provider_sessions"),
+		Repository: s.Store.GetRepository("provider_sessions"),
+		envelope:   s.envelope,
 	}
 }
 
@@ -50,3 +128,51 @@ func (s *Store) restrictedTokenRepository() *RestrictedTokenRepository {
 		s.Store.GetRepository("restricted_tokens"),
 	}
 }
+
+func (s *Store) personalAccessTokenRepository() *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{
+		s.Store.GetRepository("personal_access_tokens"),
+	}
+}
+
+func (s *Store) appAttestChallengeRepository() *AppAttestChallengeRepository {
+	return &AppAttestChallengeRepository{
+		s.Store.GetRepository("app_attest_challenges"),
+	}
+}
+
+func (s *Store) appAttestKeyRepository() *AppAttestKeyRepository {
+	return &AppAttestKeyRepository{
+		s.Store.GetRepository("app_attest_keys"),
+	}
+}
+
+func (s *Store) oauthClientRepository() *OAuthClientRepository {
+	return &OAuthClientRepository{
+		s.Store.GetRepository("oauth_clients"),
+	}
+}
+
+func (s *Store) oauthAuthorizationCodeRepository() *OAuthAuthorizationCodeRepository {
+	return &OAuthAuthorizationCodeRepository{
+		s.Store.GetRepository("oauth_authorization_codes"),
+	}
+}
+
+func (s *Store) oauthTokenRepository() *OAuthTokenRepository {
+	return &OAuthTokenRepository{
+		s.Store.GetRepository("oauth_tokens"),
+	}
+}
+
+func (s *Store) oauthConsentRepository() *OAuthConsentRepository {
+	return &OAuthConsentRepository{
+		s.Store.GetRepository("oauth_consents"),
+	}
+}
+
+func (s *Store) auditEventRepository() *AuditEventRepository {
+	return &AuditEventRepository{
+		s.Store.GetRepository("audit_events"),
+	}
+}
@@ -29,14 +29,14 @@ var _ = Describe("Store", func() {
 	Context("New", func() {
 		It("returns an error if unsuccessful", func() {
 			var err error
-			str, err = mongo.NewStore(nil)
+			str, err = mongo.NewStore(nil, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(str).To(BeNil())
 		})
 
 		It("returns successfully", func() {
 			var err error
-			str, err = mongo.NewStore(config)
+			str, err = mongo.NewStore(config, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(str).ToNot(BeNil())
 		})
@@ -45,7 +45,7 @@ var _ = Describe("Store", func() {
 	Context("with a new store", func() {
 		BeforeEach(func() {
 			var err error
-			str, err = mongo.NewStore(config)
+			str, err = mongo.NewStore(config, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(str).ToNot(BeNil())
 		})
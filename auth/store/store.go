@@ -1,12 +1,23 @@
 package store
 
 import (
+	"context"
+
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
 )
 
 type Store interface {
 	NewProviderSessionRepository() ProviderSessionRepository
 	NewRestrictedTokenRepository() RestrictedTokenRepository
+	NewPersonalAccessTokenRepository() PersonalAccessTokenRepository
+	NewAppAttestChallengeRepository() AppAttestChallengeRepository
+	NewAppAttestKeyRepository() AppAttestKeyRepository
+	NewOAuthClientRepository() OAuthClientRepository
+	NewOAuthAuthorizationCodeRepository() OAuthAuthorizationCodeRepository
+	NewOAuthTokenRepository() OAuthTokenRepository
+	NewOAuthConsentRepository() OAuthConsentRepository
+	NewAuditEventRepository() AuditEventRepository
 }
 
 type ProviderSessionRepository interface {
@@ -16,3 +27,62 @@ type ProviderSessionRepository interface {
 type RestrictedTokenRepository interface {
 	auth.RestrictedTokenAccessor
 }
+
+type PersonalAccessTokenRepository interface {
+	auth.PersonalAccessTokenAccessor
+}
+
+type AppAttestChallengeRepository interface {
+	CreateAppAttestChallenge(ctx context.Context, userID string) (*auth.AppAttestChallenge, error)
+
+	// ConsumeAppAttestChallenge deletes the challenge and returns true if it was issued to the user and has not expired
+	ConsumeAppAttestChallenge(ctx context.Context, userID string, challenge string) (bool, error)
+}
+
+type AppAttestKeyRepository interface {
+	CreateAppAttestKey(ctx context.Context, key *auth.AppAttestKey) error
+	GetAppAttestKey(ctx context.Context, userID string, id string) (*auth.AppAttestKey, error)
+
+	// UpdateAppAttestKeyCounter sets the counter only if it is still the previous counter and returns whether it was set
+	UpdateAppAttestKeyCounter(ctx context.Context, id string, previousCounter int64, counter int64) (bool, error)
+}
+
+type OAuthClientRepository interface {
+	CreateOAuthClient(ctx context.Context, client *auth.OAuthClient) error
+	GetOAuthClient(ctx context.Context, id string) (*auth.OAuthClient, error)
+	DeleteOAuthClient(ctx context.Context, id string) (bool, error)
+}
+
+type OAuthAuthorizationCodeRepository interface {
+	CreateOAuthAuthorizationCode(ctx context.Context, code *auth.OAuthAuthorizationCode) error
+
+	// ConsumeOAuthAuthorizationCode deletes and returns the authorization code of the client matching the code if it has
+	// not expired. The authorization code of any other client is left intact.
+	ConsumeOAuthAuthorizationCode(ctx context.Context, clientID string, code string) (*auth.OAuthAuthorizationCode, error)
+}
+
+type OAuthTokenRepository interface {
+	CreateOAuthToken(ctx context.Context, token *auth.OAuthToken) error
+	GetOAuthTokenByAccessToken(ctx context.Context, accessToken string) (*auth.OAuthToken, error)
+
+	// RevokeOAuthToken revokes and returns the token of the client matching the access or refresh token if it is not
+	// already revoked. The token of any other client is left intact.
+	RevokeOAuthToken(ctx context.Context, clientID string, accessOrRefreshToken string) (*auth.OAuthToken, error)
+	RevokeUserClientOAuthTokens(ctx context.Context, userID string, clientID string) error
+	RevokeClientOAuthTokens(ctx context.Context, clientID string) error
+	DeleteAllOAuthTokens(ctx context.Context, userID string) error
+}
+
+type OAuthConsentRepository interface {
+	ListUserOAuthConsents(ctx context.Context, userID string, pagination *page.Pagination) (auth.OAuthConsents, error)
+
+	// UpsertOAuthConsent adds the scopes to the consent of the user to the client, creating it if necessary
+	UpsertOAuthConsent(ctx context.Context, userID string, clientID string, scopes []string) (*auth.OAuthConsent, error)
+	DeleteOAuthConsent(ctx context.Context, userID string, clientID string) (bool, error)
+	DeleteAllOAuthConsents(ctx context.Context, userID string) error
+}
+
+// AuditEventRepository is append-only; audit events are never updated or deleted
+type AuditEventRepository interface {
+	auth.AuditEventAccessor
+}
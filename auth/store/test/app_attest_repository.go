@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+)
+
+type CreateAppAttestChallengeInput struct {
+	Context context.Context
+	UserID  string
+}
+
+type CreateAppAttestChallengeOutput struct {
+	AppAttestChallenge *auth.AppAttestChallenge
+	Error              error
+}
+
+type ConsumeAppAttestChallengeInput struct {
+	Context   context.Context
+	UserID    string
+	Challenge string
+}
+
+type ConsumeAppAttestChallengeOutput struct {
+	Consumed bool
+	Error    error
+}
+
+type AppAttestChallengeRepository struct {
+	CreateAppAttestChallengeInvocations  int
+	CreateAppAttestChallengeInputs       []CreateAppAttestChallengeInput
+	CreateAppAttestChallengeOutputs      []CreateAppAttestChallengeOutput
+	ConsumeAppAttestChallengeInvocations int
+	ConsumeAppAttestChallengeInputs      []ConsumeAppAttestChallengeInput
+	ConsumeAppAttestChallengeOutputs     []ConsumeAppAttestChallengeOutput
+}
+
+func NewAppAttestChallengeRepository() *AppAttestChallengeRepository {
+	return &AppAttestChallengeRepository{}
+}
+
+func (a *AppAttestChallengeRepository) CreateAppAttestChallenge(ctx context.Context, userID string) (*auth.AppAttestChallenge, error) {
+	a.CreateAppAttestChallengeInvocations++
+
+	a.CreateAppAttestChallengeInputs = append(a.CreateAppAttestChallengeInputs, CreateAppAttestChallengeInput{Context: ctx, UserID: userID})
+
+	gomega.Expect(a.CreateAppAttestChallengeOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.CreateAppAttestChallengeOutputs[0]
+	a.CreateAppAttestChallengeOutputs = a.CreateAppAttestChallengeOutputs[1:]
+	return output.AppAttestChallenge, output.Error
+}
+
+func (a *AppAttestChallengeRepository) ConsumeAppAttestChallenge(ctx context.Context, userID string, challenge string) (bool, error) {
+	a.ConsumeAppAttestChallengeInvocations++
+
+	a.ConsumeAppAttestChallengeInputs = append(a.ConsumeAppAttestChallengeInputs, ConsumeAppAttestChallengeInput{Context: ctx, UserID: userID, Challenge: challenge})
+
+	gomega.Expect(a.ConsumeAppAttestChallengeOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.ConsumeAppAttestChallengeOutputs[0]
+	a.ConsumeAppAttestChallengeOutputs = a.ConsumeAppAttestChallengeOutputs[1:]
+	return output.Consumed, output.Error
+}
+
+func (a *AppAttestChallengeRepository) Expectations() {
+	gomega.Expect(a.CreateAppAttestChallengeOutputs).To(gomega.BeEmpty())
+	gomega.Expect(a.ConsumeAppAttestChallengeOutputs).To(gomega.BeEmpty())
+}
+
+type CreateAppAttestKeyInput struct {
+	Context context.Context
+	Key     *auth.AppAttestKey
+}
+
+type GetAppAttestKeyInput struct {
+	Context context.Context
+	UserID  string
+	ID      string
+}
+
+type GetAppAttestKeyOutput struct {
+	AppAttestKey *auth.AppAttestKey
+	Error        error
+}
+
+type UpdateAppAttestKeyCounterInput struct {
+	Context         context.Context
+	ID              string
+	PreviousCounter int64
+	Counter         int64
+}
+
+type UpdateAppAttestKeyCounterOutput struct {
+	Updated bool
+	Error   error
+}
+
+type AppAttestKeyRepository struct {
+	CreateAppAttestKeyInvocations        int
+	CreateAppAttestKeyInputs             []CreateAppAttestKeyInput
+	CreateAppAttestKeyOutputs            []error
+	GetAppAttestKeyInvocations           int
+	GetAppAttestKeyInputs                []GetAppAttestKeyInput
+	GetAppAttestKeyOutputs               []GetAppAttestKeyOutput
+	UpdateAppAttestKeyCounterInvocations int
+	UpdateAppAttestKeyCounterInputs      []UpdateAppAttestKeyCounterInput
+	UpdateAppAttestKeyCounterOutputs     []UpdateAppAttestKeyCounterOutput
+}
+
+func NewAppAttestKeyRepository() *AppAttestKeyRepository {
+	return &AppAttestKeyRepository{}
+}
+
+func (a *AppAttestKeyRepository) CreateAppAttestKey(ctx context.Context, key *auth.AppAttestKey) error {
+	a.CreateAppAttestKeyInvocations++
+
+	a.CreateAppAttestKeyInputs = append(a.CreateAppAttestKeyInputs, CreateAppAttestKeyInput{Context: ctx, Key: key})
+
+	gomega.Expect(a.CreateAppAttestKeyOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.CreateAppAttestKeyOutputs[0]
+	a.CreateAppAttestKeyOutputs = a.CreateAppAttestKeyOutputs[1:]
+	return output
+}
+
+func (a *AppAttestKeyRepository) GetAppAttestKey(ctx context.Context, userID string, id string) (*auth.AppAttestKey, error) {
+	a.GetAppAttestKeyInvocations++
+
+	a.GetAppAttestKeyInputs = append(a.GetAppAttestKeyInputs, GetAppAttestKeyInput{Context: ctx, UserID: userID, ID: id})
+
+	gomega.Expect(a.GetAppAttestKeyOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.GetAppAttestKeyOutputs[0]
+	a.GetAppAttestKeyOutputs = a.GetAppAttestKeyOutputs[1:]
+	return output.AppAttestKey, output.Error
+}
+
+func (a *AppAttestKeyRepository) UpdateAppAttestKeyCounter(ctx context.Context, id string, previousCounter int64, counter int64) (bool, error) {
+	a.UpdateAppAttestKeyCounterInvocations++
+
+	a.UpdateAppAttestKeyCounterInputs = append(a.UpdateAppAttestKeyCounterInputs, UpdateAppAttestKeyCounterInput{Context: ctx, ID: id, PreviousCounter: previousCounter, Counter: counter})
+
+	gomega.Expect(a.UpdateAppAttestKeyCounterOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.UpdateAppAttestKeyCounterOutputs[0]
+	a.UpdateAppAttestKeyCounterOutputs = a.UpdateAppAttestKeyCounterOutputs[1:]
+	return output.Updated, output.Error
+}
+
+func (a *AppAttestKeyRepository) Expectations() {
+	gomega.Expect(a.CreateAppAttestKeyOutputs).To(gomega.BeEmpty())
+	gomega.Expect(a.GetAppAttestKeyOutputs).To(gomega.BeEmpty())
+	gomega.Expect(a.UpdateAppAttestKeyCounterOutputs).To(gomega.BeEmpty())
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+)
+
+type CreateOAuthAuthorizationCodeInput struct {
+	Context context.Context
+	Code    *auth.OAuthAuthorizationCode
+}
+
+type ConsumeOAuthAuthorizationCodeInput struct {
+	Context  context.Context
+	ClientID string
+	Code     string
+}
+
+type ConsumeOAuthAuthorizationCodeOutput struct {
+	OAuthAuthorizationCode *auth.OAuthAuthorizationCode
+	Error                  error
+}
+
+type OAuthAuthorizationCodeRepository struct {
+	CreateOAuthAuthorizationCodeInvocations  int
+	CreateOAuthAuthorizationCodeInputs       []CreateOAuthAuthorizationCodeInput
+	CreateOAuthAuthorizationCodeOutputs      []error
+	ConsumeOAuthAuthorizationCodeInvocations int
+	ConsumeOAuthAuthorizationCodeInputs      []ConsumeOAuthAuthorizationCodeInput
+	ConsumeOAuthAuthorizationCodeOutputs     []ConsumeOAuthAuthorizationCodeOutput
+}
+
+func NewOAuthAuthorizationCodeRepository() *OAuthAuthorizationCodeRepository {
+	return &OAuthAuthorizationCodeRepository{}
+}
+
+func (o *OAuthAuthorizationCodeRepository) CreateOAuthAuthorizationCode(ctx context.Context, code *auth.OAuthAuthorizationCode) error {
+	o.CreateOAuthAuthorizationCodeInvocations++
+
+	o.CreateOAuthAuthorizationCodeInputs = append(o.CreateOAuthAuthorizationCodeInputs, CreateOAuthAuthorizationCodeInput{Context: ctx, Code: code})
+
+	gomega.Expect(o.CreateOAuthAuthorizationCodeOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.CreateOAuthAuthorizationCodeOutputs[0]
+	o.CreateOAuthAuthorizationCodeOutputs = o.CreateOAuthAuthorizationCodeOutputs[1:]
+	return output
+}
+
+func (o *OAuthAuthorizationCodeRepository) ConsumeOAuthAuthorizationCode(ctx context.Context, clientID string, code string) (*auth.OAuthAuthorizationCode, error) {
+	o.ConsumeOAuthAuthorizationCodeInvocations++
+
+	o.ConsumeOAuthAuthorizationCodeInputs = append(o.ConsumeOAuthAuthorizationCodeInputs, ConsumeOAuthAuthorizationCodeInput{Context: ctx, ClientID: clientID, Code: code})
+
+	gomega.Expect(o.ConsumeOAuthAuthorizationCodeOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.ConsumeOAuthAuthorizationCodeOutputs[0]
+	o.ConsumeOAuthAuthorizationCodeOutputs = o.ConsumeOAuthAuthorizationCodeOutputs[1:]
+	return output.OAuthAuthorizationCode, output.Error
+}
+
+func (o *OAuthAuthorizationCodeRepository) Expectations() {
+	gomega.Expect(o.CreateOAuthAuthorizationCodeOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.ConsumeOAuthAuthorizationCodeOutputs).To(gomega.BeEmpty())
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+)
+
+type CreateOAuthClientInput struct {
+	Context context.Context
+	Client  *auth.OAuthClient
+}
+
+type GetOAuthClientInput struct {
+	Context context.Context
+	ID      string
+}
+
+type GetOAuthClientOutput struct {
+	OAuthClient *auth.OAuthClient
+	Error       error
+}
+
+type DeleteOAuthClientInput struct {
+	Context context.Context
+	ID      string
+}
+
+type DeleteOAuthClientOutput struct {
+	Deleted bool
+	Error   error
+}
+
+type OAuthClientRepository struct {
+	CreateOAuthClientInvocations int
+	CreateOAuthClientInputs      []CreateOAuthClientInput
+	CreateOAuthClientOutputs     []error
+	GetOAuthClientInvocations    int
+	GetOAuthClientInputs         []GetOAuthClientInput
+	GetOAuthClientOutputs        []GetOAuthClientOutput
+	DeleteOAuthClientInvocations int
+	DeleteOAuthClientInputs      []DeleteOAuthClientInput
+	DeleteOAuthClientOutputs     []DeleteOAuthClientOutput
+}
+
+func NewOAuthClientRepository() *OAuthClientRepository {
+	return &OAuthClientRepository{}
+}
+
+func (o *OAuthClientRepository) CreateOAuthClient(ctx context.Context, client *auth.OAuthClient) error {
+	o.CreateOAuthClientInvocations++
+
+	o.CreateOAuthClientInputs = append(o.CreateOAuthClientInputs, CreateOAuthClientInput{Context: ctx, Client: client})
+
+	gomega.Expect(o.CreateOAuthClientOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.CreateOAuthClientOutputs[0]
+	o.CreateOAuthClientOutputs = o.CreateOAuthClientOutputs[1:]
+	return output
+}
+
+func (o *OAuthClientRepository) GetOAuthClient(ctx context.Context, id string) (*auth.OAuthClient, error) {
+	o.GetOAuthClientInvocations++
+
+	o.GetOAuthClientInputs = append(o.GetOAuthClientInputs, GetOAuthClientInput{Context: ctx, ID: id})
+
+	gomega.Expect(o.GetOAuthClientOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.GetOAuthClientOutputs[0]
+	o.GetOAuthClientOutputs = o.GetOAuthClientOutputs[1:]
+	return output.OAuthClient, output.Error
+}
+
+func (o *OAuthClientRepository) DeleteOAuthClient(ctx context.Context, id string) (bool, error) {
+	o.DeleteOAuthClientInvocations++
+
+	o.DeleteOAuthClientInputs = append(o.DeleteOAuthClientInputs, DeleteOAuthClientInput{Context: ctx, ID: id})
+
+	gomega.Expect(o.DeleteOAuthClientOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.DeleteOAuthClientOutputs[0]
+	o.DeleteOAuthClientOutputs = o.DeleteOAuthClientOutputs[1:]
+	return output.Deleted, output.Error
+}
+
+func (o *OAuthClientRepository) Expectations() {
+	gomega.Expect(o.CreateOAuthClientOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.GetOAuthClientOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.DeleteOAuthClientOutputs).To(gomega.BeEmpty())
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
+)
+
+type ListUserOAuthConsentsInput struct {
+	Context    context.Context
+	UserID     string
+	Pagination *page.Pagination
+}
+
+type ListUserOAuthConsentsOutput struct {
+	OAuthConsents auth.OAuthConsents
+	Error         error
+}
+
+type UpsertOAuthConsentInput struct {
+	Context  context.Context
+	UserID   string
+	ClientID string
+	Scopes   []string
+}
+
+type UpsertOAuthConsentOutput struct {
+	OAuthConsent *auth.OAuthConsent
+	Error        error
+}
+
+type DeleteOAuthConsentInput struct {
+	Context  context.Context
+	UserID   string
+	ClientID string
+}
+
+type DeleteOAuthConsentOutput struct {
+	Deleted bool
+	Error   error
+}
+
+type DeleteAllOAuthConsentsInput struct {
+	Context context.Context
+	UserID  string
+}
+
+type OAuthConsentRepository struct {
+	ListUserOAuthConsentsInvocations  int
+	ListUserOAuthConsentsInputs       []ListUserOAuthConsentsInput
+	ListUserOAuthConsentsOutputs      []ListUserOAuthConsentsOutput
+	UpsertOAuthConsentInvocations     int
+	UpsertOAuthConsentInputs          []UpsertOAuthConsentInput
+	UpsertOAuthConsentOutputs         []UpsertOAuthConsentOutput
+	DeleteOAuthConsentInvocations     int
+	DeleteOAuthConsentInputs          []DeleteOAuthConsentInput
+	DeleteOAuthConsentOutputs         []DeleteOAuthConsentOutput
+	DeleteAllOAuthConsentsInvocations int
+	DeleteAllOAuthConsentsInputs      []DeleteAllOAuthConsentsInput
+	DeleteAllOAuthConsentsOutputs     []error
+}
+
+func NewOAuthConsentRepository() *OAuthConsentRepository {
+	return &OAuthConsentRepository{}
+}
+
+func (o *OAuthConsentRepository) ListUserOAuthConsents(ctx context.Context, userID string, pagination *page.Pagination) (auth.OAuthConsents, error) {
+	o.ListUserOAuthConsentsInvocations++
+
+	o.ListUserOAuthConsentsInputs = append(o.ListUserOAuthConsentsInputs, ListUserOAuthConsentsInput{Context: ctx, UserID: userID, Pagination: pagination})
+
+	gomega.Expect(o.ListUserOAuthConsentsOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.ListUserOAuthConsentsOutputs[0]
+	o.ListUserOAuthConsentsOutputs = o.ListUserOAuthConsentsOutputs[1:]
+	return output.OAuthConsents, output.Error
+}
+
+func (o *OAuthConsentRepository) UpsertOAuthConsent(ctx context.Context, userID string, clientID string, scopes []string) (*auth.OAuthConsent, error) {
+	o.UpsertOAuthConsentInvocations++
+
+	o.UpsertOAuthConsentInputs = append(o.UpsertOAuthConsentInputs, UpsertOAuthConsentInput{Context: ctx, UserID: userID, ClientID: clientID, Scopes: scopes})
+
+	gomega.Expect(o.UpsertOAuthConsentOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.UpsertOAuthConsentOutputs[0]
+	o.UpsertOAuthConsentOutputs = o.UpsertOAuthConsentOutputs[1:]
+	return output.OAuthConsent, output.Error
+}
+
+func (o *OAuthConsentRepository) DeleteOAuthConsent(ctx context.Context, userID string, clientID string) (bool, error) {
+	o.DeleteOAuthConsentInvocations++
+
+	o.DeleteOAuthConsentInputs = append(o.DeleteOAuthConsentInputs, DeleteOAuthConsentInput{Context: ctx, UserID: userID, ClientID: clientID})
+
+	gomega.Expect(o.DeleteOAuthConsentOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.DeleteOAuthConsentOutputs[0]
+	o.DeleteOAuthConsentOutputs = o.DeleteOAuthConsentOutputs[1:]
+	return output.Deleted, output.Error
+}
+
+func (o *OAuthConsentRepository) DeleteAllOAuthConsents(ctx context.Context, userID string) error {
+	o.DeleteAllOAuthConsentsInvocations++
+
+	o.DeleteAllOAuthConsentsInputs = append(o.DeleteAllOAuthConsentsInputs, DeleteAllOAuthConsentsInput{Context: ctx, UserID: userID})
+
+	gomega.Expect(o.DeleteAllOAuthConsentsOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.DeleteAllOAuthConsentsOutputs[0]
+	o.DeleteAllOAuthConsentsOutputs = o.DeleteAllOAuthConsentsOutputs[1:]
+	return output
+}
+
+func (o *OAuthConsentRepository) Expectations() {
+	gomega.Expect(o.ListUserOAuthConsentsOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.UpsertOAuthConsentOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.DeleteOAuthConsentOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.DeleteAllOAuthConsentsOutputs).To(gomega.BeEmpty())
+}
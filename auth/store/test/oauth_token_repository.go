@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+)
+
+type CreateOAuthTokenInput struct {
+	Context context.Context
+	Token   *auth.OAuthToken
+}
+
+type GetOAuthTokenByAccessTokenInput struct {
+	Context     context.Context
+	AccessToken string
+}
+
+type GetOAuthTokenByAccessTokenOutput struct {
+	OAuthToken *auth.OAuthToken
+	Error      error
+}
+
+type RevokeOAuthTokenInput struct {
+	Context              context.Context
+	ClientID             string
+	AccessOrRefreshToken string
+}
+
+type RevokeOAuthTokenOutput struct {
+	OAuthToken *auth.OAuthToken
+	Error      error
+}
+
+type RevokeUserClientOAuthTokensInput struct {
+	Context  context.Context
+	UserID   string
+	ClientID string
+}
+
+type RevokeClientOAuthTokensInput struct {
+	Context  context.Context
+	ClientID string
+}
+
+type DeleteAllOAuthTokensInput struct {
+	Context context.Context
+	UserID  string
+}
+
+type OAuthTokenRepository struct {
+	CreateOAuthTokenInvocations            int
+	CreateOAuthTokenInputs                 []CreateOAuthTokenInput
+	CreateOAuthTokenOutputs                []error
+	GetOAuthTokenByAccessTokenInvocations  int
+	GetOAuthTokenByAccessTokenInputs       []GetOAuthTokenByAccessTokenInput
+	GetOAuthTokenByAccessTokenOutputs      []GetOAuthTokenByAccessTokenOutput
+	RevokeOAuthTokenInvocations            int
+	RevokeOAuthTokenInputs                 []RevokeOAuthTokenInput
+	RevokeOAuthTokenOutputs                []RevokeOAuthTokenOutput
+	RevokeUserClientOAuthTokensInvocations int
+	RevokeUserClientOAuthTokensInputs      []RevokeUserClientOAuthTokensInput
+	RevokeUserClientOAuthTokensOutputs     []error
+	RevokeClientOAuthTokensInvocations     int
+	RevokeClientOAuthTokensInputs          []RevokeClientOAuthTokensInput
+	RevokeClientOAuthTokensOutputs         []error
+	DeleteAllOAuthTokensInvocations        int
+	DeleteAllOAuthTokensInputs             []DeleteAllOAuthTokensInput
+	DeleteAllOAuthTokensOutputs            []error
+}
+
+func NewOAuthTokenRepository() *OAuthTokenRepository {
+	return &OAuthTokenRepository{}
+}
+
+func (o *OAuthTokenRepository) CreateOAuthToken(ctx context.Context, token *auth.OAuthToken) error {
+	o.CreateOAuthTokenInvocations++
+
+	o.CreateOAuthTokenInputs = append(o.CreateOAuthTokenInputs, CreateOAuthTokenInput{Context: ctx, Token: token})
+
+	gomega.Expect(o.CreateOAuthTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.CreateOAuthTokenOutputs[0]
+	o.CreateOAuthTokenOutputs = o.CreateOAuthTokenOutputs[1:]
+	return output
+}
+
+func (o *OAuthTokenRepository) GetOAuthTokenByAccessToken(ctx context.Context, accessToken string) (*auth.OAuthToken, error) {
+	o.GetOAuthTokenByAccessTokenInvocations++
+
+	o.GetOAuthTokenByAccessTokenInputs = append(o.GetOAuthTokenByAccessTokenInputs, GetOAuthTokenByAccessTokenInput{Context: ctx, AccessToken: accessToken})
+
+	gomega.Expect(o.GetOAuthTokenByAccessTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.GetOAuthTokenByAccessTokenOutputs[0]
+	o.GetOAuthTokenByAccessTokenOutputs = o.GetOAuthTokenByAccessTokenOutputs[1:]
+	return output.OAuthToken, output.Error
+}
+
+func (o *OAuthTokenRepository) RevokeOAuthToken(ctx context.Context, clientID string, accessOrRefreshToken string) (*auth.OAuthToken, error) {
+	o.RevokeOAuthTokenInvocations++
+
+	o.RevokeOAuthTokenInputs = append(o.RevokeOAuthTokenInputs, RevokeOAuthTokenInput{Context: ctx, ClientID: clientID, AccessOrRefreshToken: accessOrRefreshToken})
+
+	gomega.Expect(o.RevokeOAuthTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.RevokeOAuthTokenOutputs[0]
+	o.RevokeOAuthTokenOutputs = o.RevokeOAuthTokenOutputs[1:]
+	return output.OAuthToken, output.Error
+}
+
+func (o *OAuthTokenRepository) RevokeUserClientOAuthTokens(ctx context.Context, userID string, clientID string) error {
+	o.RevokeUserClientOAuthTokensInvocations++
+
+	o.RevokeUserClientOAuthTokensInputs = append(o.RevokeUserClientOAuthTokensInputs, RevokeUserClientOAuthTokensInput{Context: ctx, UserID: userID, ClientID: clientID})
+
+	gomega.Expect(o.RevokeUserClientOAuthTokensOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.RevokeUserClientOAuthTokensOutputs[0]
+	o.RevokeUserClientOAuthTokensOutputs = o.RevokeUserClientOAuthTokensOutputs[1:]
+	return output
+}
+
+func (o *OAuthTokenRepository) RevokeClientOAuthTokens(ctx context.Context, clientID string) error {
+	o.RevokeClientOAuthTokensInvocations++
+
+	o.RevokeClientOAuthTokensInputs = append(o.RevokeClientOAuthTokensInputs, RevokeClientOAuthTokensInput{Context: ctx, ClientID: clientID})
+
+	gomega.Expect(o.RevokeClientOAuthTokensOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.RevokeClientOAuthTokensOutputs[0]
+	o.RevokeClientOAuthTokensOutputs = o.RevokeClientOAuthTokensOutputs[1:]
+	return output
+}
+
+func (o *OAuthTokenRepository) DeleteAllOAuthTokens(ctx context.Context, userID string) error {
+	o.DeleteAllOAuthTokensInvocations++
+
+	o.DeleteAllOAuthTokensInputs = append(o.DeleteAllOAuthTokensInputs, DeleteAllOAuthTokensInput{Context: ctx, UserID: userID})
+
+	gomega.Expect(o.DeleteAllOAuthTokensOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.DeleteAllOAuthTokensOutputs[0]
+	o.DeleteAllOAuthTokensOutputs = o.DeleteAllOAuthTokensOutputs[1:]
+	return output
+}
+
+func (o *OAuthTokenRepository) Expectations() {
+	gomega.Expect(o.CreateOAuthTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.GetOAuthTokenByAccessTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.RevokeOAuthTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.RevokeUserClientOAuthTokensOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.RevokeClientOAuthTokensOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.DeleteAllOAuthTokensOutputs).To(gomega.BeEmpty())
+}
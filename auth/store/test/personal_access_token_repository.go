@@ -0,0 +1,19 @@
+package test
+
+import (
+	authTest "github.com/tidepool-org/platform/auth/test"
+)
+
+type PersonalAccessTokenRepository struct {
+	*authTest.PersonalAccessTokenAccessor
+}
+
+func NewPersonalAccessTokenRepository() *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{
+		PersonalAccessTokenAccessor: authTest.NewPersonalAccessTokenAccessor(),
+	}
+}
+
+func (p *PersonalAccessTokenRepository) Expectations() {
+	p.PersonalAccessTokenAccessor.Expectations()
+}
@@ -5,16 +5,40 @@ import (
 )
 
 type Store struct {
-	NewProviderSessionRepositoryInvocations int
-	NewProviderSessionRepositoryImpl        *ProviderSessionRepository
-	NewRestrictedTokenRepositoryInvocations int
-	NewRestrictedTokenRepositoryImpl        *RestrictedTokenRepository
+	NewProviderSessionRepositoryInvocations        int
+	NewProviderSessionRepositoryImpl               *ProviderSessionRepository
+	NewRestrictedTokenRepositoryInvocations        int
+	NewRestrictedTokenRepositoryImpl               *RestrictedTokenRepository
+	NewPersonalAccessTokenRepositoryInvocations    int
+	NewPersonalAccessTokenRepositoryImpl           *PersonalAccessTokenRepository
+	NewAppAttestChallengeRepositoryInvocations     int
+	NewAppAttestChallengeRepositoryImpl            *AppAttestChallengeRepository
+	NewAppAttestKeyRepositoryInvocations           int
+	NewAppAttestKeyRepositoryImpl                  *AppAttestKeyRepository
+	NewOAuthClientRepositoryInvocations            int
+	NewOAuthClientRepositoryImpl                   *OAuthClientRepository
+	NewOAuthAuthorizationCodeRepositoryInvocations int
+	NewOAuthAuthorizationCodeRepositoryImpl        *OAuthAuthorizationCodeRepository
+	NewOAuthTokenRepositoryInvocations             int
+	NewOAuthTokenRepositoryImpl                    *OAuthTokenRepository
+	NewOAuthConsentRepositoryInvocations           int
+	NewOAuthConsentRepositoryImpl                  *OAuthConsentRepository
+	NewAuditEventRepositoryInvocations             int
+	NewAuditEventRepositoryImpl                    *AuditEventRepository
 }
 
 func NewStore() *Store {
 	return &Store{
-		NewProviderSessionRepositoryImpl: NewProviderSessionRepository(),
-		NewRestrictedTokenRepositoryImpl: NewRestrictedTokenRepository(),
+		NewProviderSessionRepositoryImpl:        NewProviderSessionRepository(),
+		NewRestrictedTokenRepositoryImpl:        NewRestrictedTokenRepository(),
+		NewPersonalAccessTokenRepositoryImpl:    NewPersonalAccessTokenRepository(),
+		NewAppAttestChallengeRepositoryImpl:     NewAppAttestChallengeRepository(),
+		NewAppAttestKeyRepositoryImpl:           NewAppAttestKeyRepository(),
+		NewOAuthClientRepositoryImpl:            NewOAuthClientRepository(),
+		NewOAuthAuthorizationCodeRepositoryImpl: NewOAuthAuthorizationCodeRepository(),
+		NewOAuthTokenRepositoryImpl:             NewOAuthTokenRepository(),
+		NewOAuthConsentRepositoryImpl:           NewOAuthConsentRepository(),
+		NewAuditEventRepositoryImpl:             NewAuditEventRepository(),
 	}
 }
 
@@ -28,7 +52,55 @@ func (s *Store) NewRestrictedTokenRepository() store.RestrictedTokenRepository {
 	return s.NewRestrictedTokenRepositoryImpl
 }
 
+func (s *Store) NewPersonalAccessTokenRepository() store.PersonalAccessTokenRepository {
+	s.NewPersonalAccessTokenRepositoryInvocations++
+	return s.NewPersonalAccessTokenRepositoryImpl
+}
+
+func (s *Store) NewAppAttestChallengeRepository() store.AppAttestChallengeRepository {
+	s.NewAppAttestChallengeRepositoryInvocations++
+	return s.NewAppAttestChallengeRepositoryImpl
+}
+
+func (s *Store) NewAppAttestKeyRepository() store.AppAttestKeyRepository {
+	s.NewAppAttestKeyRepositoryInvocations++
+	return s.NewAppAttestKeyRepositoryImpl
+}
+
+func (s *Store) NewOAuthClientRepository() store.OAuthClientRepository {
+	s.NewOAuthClientRepositoryInvocations++
+	return s.NewOAuthClientRepositoryImpl
+}
+
+func (s *Store) NewOAuthAuthorizationCodeRepository() store.OAuthAuthorizationCodeRepository {
+	s.NewOAuthAuthorizationCodeRepositoryInvocations++
+	return s.NewOAuthAuthorizationCodeRepositoryImpl
+}
+
+func (s *Store) NewOAuthTokenRepository() store.OAuthTokenRepository {
+	s.NewOAuthTokenRepositoryInvocations++
+	return s.NewOAuthTokenRepositoryImpl
+}
+
+func (s *Store) NewOAuthConsentRepository() store.OAuthConsentRepository {
+	s.NewOAuthConsentRepositoryInvocations++
+	return s.NewOAuthConsentRepositoryImpl
+}
+
+func (s *Store) NewAuditEventRepository() store.AuditEventRepository {
+	s.NewAuditEventRepositoryInvocations++
+	return s.NewAuditEventRepositoryImpl
+}
+
 func (s *Store) Expectations() {
 	s.NewProviderSessionRepositoryImpl.Expectations()
 	s.NewRestrictedTokenRepositoryImpl.Expectations()
+	s.NewPersonalAccessTokenRepositoryImpl.Expectations()
+	s.NewAppAttestChallengeRepositoryImpl.Expectations()
+	s.NewAppAttestKeyRepositoryImpl.Expectations()
+	s.NewOAuthClientRepositoryImpl.Expectations()
+	s.NewOAuthAuthorizationCodeRepositoryImpl.Expectations()
+	s.NewOAuthTokenRepositoryImpl.Expectations()
+	s.NewOAuthConsentRepositoryImpl.Expectations()
+	s.NewAuditEventRepositoryImpl.Expectations()
 }
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
+)
+
+type ListUserAuditEventsInput struct {
+	Context    context.Context
+	UserID     string
+	Filter     *auth.AuditEventFilter
+	Pagination *page.Pagination
+}
+
+type ListUserAuditEventsOutput struct {
+	AuditEvents auth.AuditEvents
+	Error       error
+}
+
+type CreateAuditEventsInput struct {
+	Context context.Context
+	Events  auth.AuditEvents
+}
+
+type AuditEventAccessor struct {
+	ListUserAuditEventsInvocations int
+	ListUserAuditEventsInputs      []ListUserAuditEventsInput
+	ListUserAuditEventsOutputs     []ListUserAuditEventsOutput
+	CreateAuditEventsInvocations   int
+	CreateAuditEventsInputs        []CreateAuditEventsInput
+	CreateAuditEventsOutputs       []error
+}
+
+func NewAuditEventAccessor() *AuditEventAccessor {
+	return &AuditEventAccessor{}
+}
+
+func (a *AuditEventAccessor) ListUserAuditEvents(ctx context.Context, userID string, filter *auth.AuditEventFilter, pagination *page.Pagination) (auth.AuditEvents, error) {
+	a.ListUserAuditEventsInvocations++
+
+	a.ListUserAuditEventsInputs = append(a.ListUserAuditEventsInputs, ListUserAuditEventsInput{Context: ctx, UserID: userID, Filter: filter, Pagination: pagination})
+
+	gomega.Expect(a.ListUserAuditEventsOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.ListUserAuditEventsOutputs[0]
+	a.ListUserAuditEventsOutputs = a.ListUserAuditEventsOutputs[1:]
+	return output.AuditEvents, output.Error
+}
+
+func (a *AuditEventAccessor) CreateAuditEvents(ctx context.Context, events auth.AuditEvents) error {
+	a.CreateAuditEventsInvocations++
+
+	a.CreateAuditEventsInputs = append(a.CreateAuditEventsInputs, CreateAuditEventsInput{Context: ctx, Events: events})
+
+	gomega.Expect(a.CreateAuditEventsOutputs).ToNot(gomega.BeEmpty())
+
+	output := a.CreateAuditEventsOutputs[0]
+	a.CreateAuditEventsOutputs = a.CreateAuditEventsOutputs[1:]
+	return output
+}
+
+func (a *AuditEventAccessor) Expectations() {
+	gomega.Expect(a.ListUserAuditEventsOutputs).To(gomega.BeEmpty())
+	gomega.Expect(a.CreateAuditEventsOutputs).To(gomega.BeEmpty())
+}
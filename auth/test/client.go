@@ -3,19 +3,28 @@ package test
 type Client struct {
 	*ProviderSessionAccessor
 	*RestrictedTokenAccessor
+	*PersonalAccessTokenAccessor
+	*OAuthAccessor
+	*AuditEventAccessor
 	*ExternalAccessor
 }
 
 func NewClient() *Client {
 	return &Client{
-		ProviderSessionAccessor: NewProviderSessionAccessor(),
-		RestrictedTokenAccessor: NewRestrictedTokenAccessor(),
-		ExternalAccessor:        NewExternalAccessor(),
+		ProviderSessionAccessor:     NewProviderSessionAccessor(),
+		RestrictedTokenAccessor:     NewRestrictedTokenAccessor(),
+		PersonalAccessTokenAccessor: NewPersonalAccessTokenAccessor(),
+		OAuthAccessor:               NewOAuthAccessor(),
+		AuditEventAccessor:          NewAuditEventAccessor(),
+		ExternalAccessor:            NewExternalAccessor(),
 	}
 }
 
 func (c *Client) AssertOutputsEmpty() {
 	c.ProviderSessionAccessor.Expectations()
 	c.RestrictedTokenAccessor.Expectations()
+	c.PersonalAccessTokenAccessor.Expectations()
+	c.OAuthAccessor.Expectations()
+	c.AuditEventAccessor.Expectations()
 	c.ExternalAccessor.AssertOutputsEmpty()
 }
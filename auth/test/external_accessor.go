@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 
+	"github.com/tidepool-org/platform/auth"
 	"github.com/tidepool-org/platform/request"
 )
 
@@ -49,6 +50,8 @@ type ExternalAccessor struct {
 	EnsureAuthorizedUserStub           func(ctx context.Context, targetUserID string, authorizedPermission string) (string, error)
 	EnsureAuthorizedUserOutputs        []EnsureAuthorizedUserOutput
 	EnsureAuthorizedUserOutput         *EnsureAuthorizedUserOutput
+	RecordAuditEventInvocations        int
+	RecordAuditEventInputs             []*auth.AuditEvent
 }
 
 func NewExternalAccessor() *ExternalAccessor {
@@ -137,6 +140,11 @@ func (e *ExternalAccessor) EnsureAuthorizedUser(ctx context.Context, targetUserI
 	panic("EnsureAuthorizedUser has no output")
 }
 
+func (e *ExternalAccessor) RecordAuditEvent(ctx context.Context, event *auth.AuditEvent) {
+	e.RecordAuditEventInvocations++
+	e.RecordAuditEventInputs = append(e.RecordAuditEventInputs, event)
+}
+
 func (e *ExternalAccessor) AssertOutputsEmpty() {
 	if len(e.ServerSessionTokenOutputs) > 0 {
 		panic("ServerSessionTokenOutputs is not empty")
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+)
+
+type DeleteAllOAuthConsentsInput struct {
+	Context context.Context
+	UserID  string
+}
+
+type IntrospectOAuthTokenInput struct {
+	Context context.Context
+	Token   string
+}
+
+type IntrospectOAuthTokenOutput struct {
+	OAuthTokenIntrospection *auth.OAuthTokenIntrospection
+	Error                   error
+}
+
+type OAuthAccessor struct {
+	DeleteAllOAuthConsentsInvocations int
+	DeleteAllOAuthConsentsInputs      []DeleteAllOAuthConsentsInput
+	DeleteAllOAuthConsentsOutputs     []error
+	IntrospectOAuthTokenInvocations   int
+	IntrospectOAuthTokenInputs        []IntrospectOAuthTokenInput
+	IntrospectOAuthTokenOutputs       []IntrospectOAuthTokenOutput
+}
+
+func NewOAuthAccessor() *OAuthAccessor {
+	return &OAuthAccessor{}
+}
+
+func (o *OAuthAccessor) DeleteAllOAuthConsents(ctx context.Context, userID string) error {
+	o.DeleteAllOAuthConsentsInvocations++
+
+	o.DeleteAllOAuthConsentsInputs = append(o.DeleteAllOAuthConsentsInputs, DeleteAllOAuthConsentsInput{Context: ctx, UserID: userID})
+
+	gomega.Expect(o.DeleteAllOAuthConsentsOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.DeleteAllOAuthConsentsOutputs[0]
+	o.DeleteAllOAuthConsentsOutputs = o.DeleteAllOAuthConsentsOutputs[1:]
+	return output
+}
+
+func (o *OAuthAccessor) IntrospectOAuthToken(ctx context.Context, token string) (*auth.OAuthTokenIntrospection, error) {
+	o.IntrospectOAuthTokenInvocations++
+
+	o.IntrospectOAuthTokenInputs = append(o.IntrospectOAuthTokenInputs, IntrospectOAuthTokenInput{Context: ctx, Token: token})
+
+	gomega.Expect(o.IntrospectOAuthTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := o.IntrospectOAuthTokenOutputs[0]
+	o.IntrospectOAuthTokenOutputs = o.IntrospectOAuthTokenOutputs[1:]
+	return output.OAuthTokenIntrospection, output.Error
+}
+
+func (o *OAuthAccessor) Expectations() {
+	gomega.Expect(o.DeleteAllOAuthConsentsOutputs).To(gomega.BeEmpty())
+	gomega.Expect(o.IntrospectOAuthTokenOutputs).To(gomega.BeEmpty())
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/page"
+)
+
+type ListUserPersonalAccessTokensInput struct {
+	Context    context.Context
+	UserID     string
+	Filter     *auth.PersonalAccessTokenFilter
+	Pagination *page.Pagination
+}
+
+type ListUserPersonalAccessTokensOutput struct {
+	PersonalAccessTokens auth.PersonalAccessTokens
+	Error                error
+}
+
+type CreateUserPersonalAccessTokenInput struct {
+	Context context.Context
+	UserID  string
+	Create  *auth.PersonalAccessTokenCreate
+}
+
+type CreateUserPersonalAccessTokenOutput struct {
+	PersonalAccessToken *auth.PersonalAccessToken
+	Error               error
+}
+
+type DeleteAllPersonalAccessTokensInput struct {
+	Context context.Context
+	UserID  string
+}
+
+type GetPersonalAccessTokenInput struct {
+	Context context.Context
+	ID      string
+}
+
+type GetPersonalAccessTokenOutput struct {
+	PersonalAccessToken *auth.PersonalAccessToken
+	Error               error
+}
+
+type RevokePersonalAccessTokenInput struct {
+	Context context.Context
+	ID      string
+}
+
+type RevokePersonalAccessTokenOutput struct {
+	PersonalAccessToken *auth.PersonalAccessToken
+	Error               error
+}
+
+type AuthenticatePersonalAccessTokenInput struct {
+	Context context.Context
+	Token   string
+}
+
+type AuthenticatePersonalAccessTokenOutput struct {
+	PersonalAccessToken *auth.PersonalAccessToken
+	Error               error
+}
+
+type PersonalAccessTokenAccessor struct {
+	ListUserPersonalAccessTokensInvocations    int
+	ListUserPersonalAccessTokensInputs         []ListUserPersonalAccessTokensInput
+	ListUserPersonalAccessTokensOutputs        []ListUserPersonalAccessTokensOutput
+	CreateUserPersonalAccessTokenInvocations   int
+	CreateUserPersonalAccessTokenInputs        []CreateUserPersonalAccessTokenInput
+	CreateUserPersonalAccessTokenOutputs       []CreateUserPersonalAccessTokenOutput
+	DeleteAllPersonalAccessTokensInvocations   int
+	DeleteAllPersonalAccessTokensInputs        []DeleteAllPersonalAccessTokensInput
+	DeleteAllPersonalAccessTokensOutputs       []error
+	GetPersonalAccessTokenInvocations          int
+	GetPersonalAccessTokenInputs               []GetPersonalAccessTokenInput
+	GetPersonalAccessTokenOutputs              []GetPersonalAccessTokenOutput
+	RevokePersonalAccessTokenInvocations       int
+	RevokePersonalAccessTokenInputs            []RevokePersonalAccessTokenInput
+	RevokePersonalAccessTokenOutputs           []RevokePersonalAccessTokenOutput
+	AuthenticatePersonalAccessTokenInvocations int
+	AuthenticatePersonalAccessTokenInputs      []AuthenticatePersonalAccessTokenInput
+	AuthenticatePersonalAccessTokenOutputs     []AuthenticatePersonalAccessTokenOutput
+}
+
+func NewPersonalAccessTokenAccessor() *PersonalAccessTokenAccessor {
+	return &PersonalAccessTokenAccessor{}
+}
+
+func (p *PersonalAccessTokenAccessor) ListUserPersonalAccessTokens(ctx context.Context, userID string, filter *auth.PersonalAccessTokenFilter, pagination *page.Pagination) (auth.PersonalAccessTokens, error) {
+	p.ListUserPersonalAccessTokensInvocations++
+
+	p.ListUserPersonalAccessTokensInputs = append(p.ListUserPersonalAccessTokensInputs, ListUserPersonalAccessTokensInput{Context: ctx, UserID: userID, Filter: filter, Pagination: pagination})
+
+	gomega.Expect(p.ListUserPersonalAccessTokensOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.ListUserPersonalAccessTokensOutputs[0]
+	p.ListUserPersonalAccessTokensOutputs = p.ListUserPersonalAccessTokensOutputs[1:]
+	return output.PersonalAccessTokens, output.Error
+}
+
+func (p *PersonalAccessTokenAccessor) CreateUserPersonalAccessToken(ctx context.Context, userID string, create *auth.PersonalAccessTokenCreate) (*auth.PersonalAccessToken, error) {
+	p.CreateUserPersonalAccessTokenInvocations++
+
+	p.CreateUserPersonalAccessTokenInputs = append(p.CreateUserPersonalAccessTokenInputs, CreateUserPersonalAccessTokenInput{Context: ctx, UserID: userID, Create: create})
+
+	gomega.Expect(p.CreateUserPersonalAccessTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.CreateUserPersonalAccessTokenOutputs[0]
+	p.CreateUserPersonalAccessTokenOutputs = p.CreateUserPersonalAccessTokenOutputs[1:]
+	return output.PersonalAccessToken, output.Error
+}
+
+func (p *PersonalAccessTokenAccessor) DeleteAllPersonalAccessTokens(ctx context.Context, userID string) error {
+	p.DeleteAllPersonalAccessTokensInvocations++
+
+	p.DeleteAllPersonalAccessTokensInputs = append(p.DeleteAllPersonalAccessTokensInputs, DeleteAllPersonalAccessTokensInput{Context: ctx, UserID: userID})
+
+	gomega.Expect(p.DeleteAllPersonalAccessTokensOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.DeleteAllPersonalAccessTokensOutputs[0]
+	p.DeleteAllPersonalAccessTokensOutputs = p.DeleteAllPersonalAccessTokensOutputs[1:]
+	return output
+}
+
+func (p *PersonalAccessTokenAccessor) GetPersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	p.GetPersonalAccessTokenInvocations++
+
+	p.GetPersonalAccessTokenInputs = append(p.GetPersonalAccessTokenInputs, GetPersonalAccessTokenInput{Context: ctx, ID: id})
+
+	gomega.Expect(p.GetPersonalAccessTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.GetPersonalAccessTokenOutputs[0]
+	p.GetPersonalAccessTokenOutputs = p.GetPersonalAccessTokenOutputs[1:]
+	return output.PersonalAccessToken, output.Error
+}
+
+func (p *PersonalAccessTokenAccessor) RevokePersonalAccessToken(ctx context.Context, id string) (*auth.PersonalAccessToken, error) {
+	p.RevokePersonalAccessTokenInvocations++
+
+	p.RevokePersonalAccessTokenInputs = append(p.RevokePersonalAccessTokenInputs, RevokePersonalAccessTokenInput{Context: ctx, ID: id})
+
+	gomega.Expect(p.RevokePersonalAccessTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.RevokePersonalAccessTokenOutputs[0]
+	p.RevokePersonalAccessTokenOutputs = p.RevokePersonalAccessTokenOutputs[1:]
+	return output.PersonalAccessToken, output.Error
+}
+
+func (p *PersonalAccessTokenAccessor) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.PersonalAccessToken, error) {
+	p.AuthenticatePersonalAccessTokenInvocations++
+
+	p.AuthenticatePersonalAccessTokenInputs = append(p.AuthenticatePersonalAccessTokenInputs, AuthenticatePersonalAccessTokenInput{Context: ctx, Token: token})
+
+	gomega.Expect(p.AuthenticatePersonalAccessTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.AuthenticatePersonalAccessTokenOutputs[0]
+	p.AuthenticatePersonalAccessTokenOutputs = p.AuthenticatePersonalAccessTokenOutputs[1:]
+	return output.PersonalAccessToken, output.Error
+}
+
+func (p *PersonalAccessTokenAccessor) Expectations() {
+	gomega.Expect(p.ListUserPersonalAccessTokensOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.CreateUserPersonalAccessTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.DeleteAllPersonalAccessTokensOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.GetPersonalAccessTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.RevokePersonalAccessTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.AuthenticatePersonalAccessTokenOutputs).To(gomega.BeEmpty())
+}
@@ -63,25 +63,33 @@ type DeleteProviderSessionInput struct {
 	ID      string
 }
 
+type RotateProviderSessionEncryptionOutput struct {
+	Count int
+	Error error
+}
+
 type ProviderSessionAccessor struct {
-	ListUserProviderSessionsInvocations  int
-	ListUserProviderSessionsInputs       []ListUserProviderSessionsInput
-	ListUserProviderSessionsOutputs      []ListUserProviderSessionsOutput
-	CreateUserProviderSessionInvocations int
-	CreateUserProviderSessionInputs      []CreateUserProviderSessionInput
-	CreateUserProviderSessionOutputs     []CreateUserProviderSessionOutput
-	DeleteAllProviderSessionsInvocations int
-	DeleteAllProviderSessionsInputs      []DeleteAllProviderSessionsInput
-	DeleteAllProviderSessionsOutputs     []error
-	GetProviderSessionInvocations        int
-	GetProviderSessionInputs             []GetProviderSessionInput
-	GetProviderSessionOutputs            []GetProviderSessionOutput
-	UpdateProviderSessionInvocations     int
-	UpdateProviderSessionInputs          []UpdateProviderSessionInput
-	UpdateProviderSessionOutputs         []UpdateProviderSessionOutput
-	DeleteProviderSessionInvocations     int
-	DeleteProviderSessionInputs          []DeleteProviderSessionInput
-	DeleteProviderSessionOutputs         []error
+	ListUserProviderSessionsInvocations        int
+	ListUserProviderSessionsInputs             []ListUserProviderSessionsInput
+	ListUserProviderSessionsOutputs            []ListUserProviderSessionsOutput
+	CreateUserProviderSessionInvocations       int
+	CreateUserProviderSessionInputs            []CreateUserProviderSessionInput
+	CreateUserProviderSessionOutputs           []CreateUserProviderSessionOutput
+	DeleteAllProviderSessionsInvocations       int
+	DeleteAllProviderSessionsInputs            []DeleteAllProviderSessionsInput
+	DeleteAllProviderSessionsOutputs           []error
+	GetProviderSessionInvocations              int
+	GetProviderSessionInputs                   []GetProviderSessionInput
+	GetProviderSessionOutputs                  []GetProviderSessionOutput
+	UpdateProviderSessionInvocations           int
+	UpdateProviderSessionInputs                []UpdateProviderSessionInput
+	UpdateProviderSessionOutputs               []UpdateProviderSessionOutput
+	DeleteProviderSessionInvocations           int
+	DeleteProviderSessionInputs                []DeleteProviderSessionInput
+	DeleteProviderSessionOutputs               []error
+	RotateProviderSessionEncryptionInvocations int
+	RotateProviderSessionEncryptionInputs      []context.Context
+	RotateProviderSessionEncryptionOutputs     []RotateProviderSessionEncryptionOutput
 }
 
 func NewProviderSessionAccessor() *ProviderSessionAccessor {
@@ -160,6 +168,18 @@ func (p *ProviderSessionAccessor) DeleteProviderSession(ctx context.Context, id
 	return output
 }
 
+func (p *ProviderSessionAccessor) RotateProviderSessionEncryption(ctx context.Context) (int, error) {
+	p.RotateProviderSessionEncryptionInvocations++
+
+	p.RotateProviderSessionEncryptionInputs = append(p.RotateProviderSessionEncryptionInputs, ctx)
+
+	gomega.Expect(p.RotateProviderSessionEncryptionOutputs).ToNot(gomega.BeEmpty())
+
+	output := p.RotateProviderSessionEncryptionOutputs[0]
+	p.RotateProviderSessionEncryptionOutputs = p.RotateProviderSessionEncryptionOutputs[1:]
+	return output.Count, output.Error
+}
+
 func (p *ProviderSessionAccessor) Expectations() {
 	gomega.Expect(p.ListUserProviderSessionsOutputs).To(gomega.BeEmpty())
 	gomega.Expect(p.CreateUserProviderSessionOutputs).To(gomega.BeEmpty())
@@ -167,4 +187,5 @@ func (p *ProviderSessionAccessor) Expectations() {
 	gomega.Expect(p.GetProviderSessionOutputs).To(gomega.BeEmpty())
 	gomega.Expect(p.UpdateProviderSessionOutputs).To(gomega.BeEmpty())
 	gomega.Expect(p.DeleteProviderSessionOutputs).To(gomega.BeEmpty())
+	gomega.Expect(p.RotateProviderSessionEncryptionOutputs).To(gomega.BeEmpty())
 }
@@ -63,6 +63,16 @@ type DeleteRestrictedTokenInput struct {
 	ID      string
 }
 
+type UseRestrictedTokenInput struct {
+	Context context.Context
+	ID      string
+}
+
+type UseRestrictedTokenOutput struct {
+	RestrictedToken *auth.RestrictedToken
+	Error           error
+}
+
 type RestrictedTokenAccessor struct {
 	ListUserRestrictedTokensInvocations  int
 	ListUserRestrictedTokensInputs       []ListUserRestrictedTokensInput
@@ -82,6 +92,9 @@ type RestrictedTokenAccessor struct {
 	DeleteRestrictedTokenInvocations     int
 	DeleteRestrictedTokenInputs          []DeleteRestrictedTokenInput
 	DeleteRestrictedTokenOutputs         []error
+	UseRestrictedTokenInvocations        int
+	UseRestrictedTokenInputs             []UseRestrictedTokenInput
+	UseRestrictedTokenOutputs            []UseRestrictedTokenOutput
 }
 
 func NewRestrictedTokenAccessor() *RestrictedTokenAccessor {
@@ -160,6 +173,18 @@ func (r *RestrictedTokenAccessor) DeleteRestrictedToken(ctx context.Context, id
 	return output
 }
 
+func (r *RestrictedTokenAccessor) UseRestrictedToken(ctx context.Context, id string) (*auth.RestrictedToken, error) {
+	r.UseRestrictedTokenInvocations++
+
+	r.UseRestrictedTokenInputs = append(r.UseRestrictedTokenInputs, UseRestrictedTokenInput{Context: ctx, ID: id})
+
+	gomega.Expect(r.UseRestrictedTokenOutputs).ToNot(gomega.BeEmpty())
+
+	output := r.UseRestrictedTokenOutputs[0]
+	r.UseRestrictedTokenOutputs = r.UseRestrictedTokenOutputs[1:]
+	return output.RestrictedToken, output.Error
+}
+
 func (r *RestrictedTokenAccessor) Expectations() {
 	gomega.Expect(r.ListUserRestrictedTokensOutputs).To(gomega.BeEmpty())
 	gomega.Expect(r.CreateUserRestrictedTokenOutputs).To(gomega.BeEmpty())
@@ -167,4 +192,5 @@ func (r *RestrictedTokenAccessor) Expectations() {
 	gomega.Expect(r.GetRestrictedTokenOutputs).To(gomega.BeEmpty())
 	gomega.Expect(r.UpdateRestrictedTokenOutputs).To(gomega.BeEmpty())
 	gomega.Expect(r.DeleteRestrictedTokenOutputs).To(gomega.BeEmpty())
+	gomega.Expect(r.UseRestrictedTokenOutputs).To(gomega.BeEmpty())
 }
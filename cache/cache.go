@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+)
+
+// Cache is a bounded, least recently used cache with separate time to live for successful and failed lookups.
+// Entries may be tagged, for example with the user ids they relate to, so that they can be invalidated together.
+type Cache struct {
+	mutex       sync.Mutex
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	entries     map[string]*list.Element
+	order       *list.List
+}
+
+type entry struct {
+	key            string
+	value          interface{}
+	tags           []string
+	expirationTime time.Time
+}
+
+func New(cfg *Config) (*Cache, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+
+	return &Cache{
+		size:        cfg.Size,
+		ttl:         cfg.TTL,
+		negativeTTL: cfg.NegativeTTL,
+		entries:     map[string]*list.Element{},
+		order:       list.New(),
+	}, nil
+}
+
+// Get returns the unexpired value for the key, if any. The value of a failed lookup is the error set with SetError.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	ntry := element.Value.(*entry)
+	if !time.Now().Before(ntry.expirationTime) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return ntry.value, true
+}
+
+func (c *Cache) Set(key string, value interface{}, tags ...string) {
+	c.set(key, value, c.ttl, tags)
+}
+
+func (c *Cache) SetError(key string, err error, tags ...string) {
+	c.set(key, err, c.negativeTTL, tags)
+}
+
+func (c *Cache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// DeleteTagged deletes all entries with the tag
+func (c *Cache) DeleteTagged(tag string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		for _, t := range element.Value.(*entry).tags {
+			if t == tag {
+				c.remove(element)
+				break
+			}
+		}
+		element = next
+	}
+}
+
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache) set(key string, value interface{}, ttl time.Duration, tags []string) {
+	if c.size == 0 || ttl <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ntry := &entry{
+		key:            key,
+		value:          value,
+		tags:           tags,
+		expirationTime: time.Now().Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = ntry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(ntry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
@@ -0,0 +1,230 @@
+package cache_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/cache"
+	configTest "github.com/tidepool-org/platform/config/test"
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+)
+
+var _ = Describe("Config", func() {
+	var cfg *cache.Config
+
+	BeforeEach(func() {
+		cfg = cache.NewConfig()
+	})
+
+	It("returns default values", func() {
+		Expect(cfg.Size).To(Equal(10000))
+		Expect(cfg.TTL).To(Equal(60 * time.Second))
+		Expect(cfg.NegativeTTL).To(Equal(10 * time.Second))
+	})
+
+	Context("Load", func() {
+		var configReporter *configTest.Reporter
+
+		BeforeEach(func() {
+			configReporter = configTest.NewReporter()
+		})
+
+		It("returns an error if the config reporter is missing", func() {
+			errorsTest.ExpectEqual(cfg.Load(nil), errors.New("config reporter is missing"))
+		})
+
+		It("returns an error if the size is invalid", func() {
+			configReporter.Config["size"] = "invalid"
+			errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("size is invalid"))
+		})
+
+		It("returns an error if the ttl is invalid", func() {
+			configReporter.Config["ttl"] = "invalid"
+			errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("ttl is invalid"))
+		})
+
+		It("returns an error if the negative ttl is invalid", func() {
+			configReporter.Config["negative_ttl"] = "invalid"
+			errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("negative ttl is invalid"))
+		})
+
+		It("loads the values", func() {
+			configReporter.Config["size"] = "100"
+			configReporter.Config["ttl"] = "30"
+			configReporter.Config["negative_ttl"] = "5"
+			Expect(cfg.Load(configReporter)).To(Succeed())
+			Expect(cfg.Size).To(Equal(100))
+			Expect(cfg.TTL).To(Equal(30 * time.Second))
+			Expect(cfg.NegativeTTL).To(Equal(5 * time.Second))
+		})
+	})
+
+	Context("Validate", func() {
+		It("returns an error if the size is invalid", func() {
+			cfg.Size = -1
+			errorsTest.ExpectEqual(cfg.Validate(), errors.New("size is invalid"))
+		})
+
+		It("returns an error if the ttl is invalid", func() {
+			cfg.TTL = -1
+			errorsTest.ExpectEqual(cfg.Validate(), errors.New("ttl is invalid"))
+		})
+
+		It("returns an error if the negative ttl is invalid", func() {
+			cfg.NegativeTTL = -1
+			errorsTest.ExpectEqual(cfg.Validate(), errors.New("negative ttl is invalid"))
+		})
+
+		It("returns successfully", func() {
+			Expect(cfg.Validate()).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Cache", func() {
+	var cfg *cache.Config
+
+	BeforeEach(func() {
+		cfg = cache.NewConfig()
+		cfg.Size = 3
+	})
+
+	Context("New", func() {
+		It("returns an error if the config is missing", func() {
+			cch, err := cache.New(nil)
+			errorsTest.ExpectEqual(err, errors.New("config is missing"))
+			Expect(cch).To(BeNil())
+		})
+
+		It("returns an error if the config is invalid", func() {
+			cfg.Size = -1
+			cch, err := cache.New(cfg)
+			Expect(err).To(MatchError("config is invalid; size is invalid"))
+			Expect(cch).To(BeNil())
+		})
+	})
+
+	Context("with new cache", func() {
+		var cch *cache.Cache
+
+		BeforeEach(func() {
+			var err error
+			cch, err = cache.New(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cch).ToNot(BeNil())
+		})
+
+		It("returns not found for a missing key", func() {
+			value, ok := cch.Get("missing")
+			Expect(ok).To(BeFalse())
+			Expect(value).To(BeNil())
+		})
+
+		It("returns a value that was set", func() {
+			cch.Set("key", "value")
+			value, ok := cch.Get("key")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("value"))
+		})
+
+		It("returns an error that was set", func() {
+			err := errors.New("test error")
+			cch.SetError("key", err)
+			value, ok := cch.Get("key")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal(err))
+		})
+
+		It("replaces a value that was set", func() {
+			cch.Set("key", "value")
+			cch.Set("key", "replaced")
+			Expect(cachedValue(cch, "key")).To(Equal("replaced"))
+			Expect(cch.Len()).To(Equal(1))
+		})
+
+		It("evicts the least recently used entry when full", func() {
+			cch.Set("one", 1)
+			cch.Set("two", 2)
+			cch.Set("three", 3)
+			_, _ = cch.Get("one")
+			cch.Set("four", 4)
+			Expect(cch.Len()).To(Equal(3))
+			_, ok := cch.Get("two")
+			Expect(ok).To(BeFalse())
+			Expect(cachedValue(cch, "one")).To(Equal(1))
+			Expect(cachedValue(cch, "three")).To(Equal(3))
+			Expect(cachedValue(cch, "four")).To(Equal(4))
+		})
+
+		It("deletes an entry", func() {
+			cch.Set("key", "value")
+			cch.Delete("key")
+			_, ok := cch.Get("key")
+			Expect(ok).To(BeFalse())
+			Expect(cch.Len()).To(Equal(0))
+		})
+
+		It("deletes all tagged entries", func() {
+			cch.Set("one", 1, "a")
+			cch.Set("two", 2, "a", "b")
+			cch.SetError("three", errors.New("test error"), "b")
+			cch.DeleteTagged("b")
+			Expect(cch.Len()).To(Equal(1))
+			Expect(cachedValue(cch, "one")).To(Equal(1))
+		})
+	})
+
+	Context("with expiring cache", func() {
+		var cch *cache.Cache
+
+		BeforeEach(func() {
+			cfg.TTL = 50 * time.Millisecond
+			cfg.NegativeTTL = 10 * time.Millisecond
+			var err error
+			cch, err = cache.New(cfg)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("expires values after the ttl", func() {
+			cch.Set("key", "value")
+			Eventually(func() bool { _, ok := cch.Get("key"); return ok }).Should(BeFalse())
+			Expect(cch.Len()).To(Equal(0))
+		})
+
+		It("expires errors before values", func() {
+			cch.Set("value", "value")
+			cch.SetError("error", errors.New("test error"))
+			Eventually(func() bool { _, ok := cch.Get("error"); return ok }).Should(BeFalse())
+			Expect(cachedValue(cch, "value")).To(Equal("value"))
+		})
+	})
+
+	Context("with disabled cache", func() {
+		It("does not store values if the size is zero", func() {
+			cfg.Size = 0
+			cch, err := cache.New(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			cch.Set("key", "value")
+			_, ok := cch.Get("key")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("does not store errors if the negative ttl is zero", func() {
+			cfg.NegativeTTL = 0
+			cch, err := cache.New(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			cch.SetError("key", errors.New("test error"))
+			_, ok := cch.Get("key")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+func cachedValue(cch *cache.Cache, key string) interface{} {
+	value, ok := cch.Get(key)
+	Expect(ok).To(BeTrue())
+	return value
+}
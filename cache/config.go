@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/config"
+	"github.com/tidepool-org/platform/errors"
+)
+
+type Config struct {
+	Size        int           // Maximum number of entries; zero disables the cache
+	TTL         time.Duration // Time to live of successful lookups
+	NegativeTTL time.Duration // Time to live of failed lookups
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Size:        10000,
+		TTL:         60 * time.Second,
+		NegativeTTL: 10 * time.Second,
+	}
+}
+
+func (c *Config) Load(configReporter config.Reporter) error {
+	if configReporter == nil {
+		return errors.New("config reporter is missing")
+	}
+
+	if sizeString, err := configReporter.Get("size"); err == nil {
+		var size int64
+		size, err = strconv.ParseInt(sizeString, 10, 0)
+		if err != nil {
+			return errors.New("size is invalid")
+		}
+		c.Size = int(size)
+	}
+	if ttlString, err := configReporter.Get("ttl"); err == nil {
+		var ttl int64
+		ttl, err = strconv.ParseInt(ttlString, 10, 0)
+		if err != nil {
+			return errors.New("ttl is invalid")
+		}
+		c.TTL = time.Duration(ttl) * time.Second
+	}
+	if negativeTTLString, err := configReporter.Get("negative_ttl"); err == nil {
+		var negativeTTL int64
+		negativeTTL, err = strconv.ParseInt(negativeTTLString, 10, 0)
+		if err != nil {
+			return errors.New("negative ttl is invalid")
+		}
+		c.NegativeTTL = time.Duration(negativeTTL) * time.Second
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if c.Size < 0 {
+		return errors.New("size is invalid")
+	}
+	if c.TTL < 0 {
+		return errors.New("ttl is invalid")
+	}
+	if c.NegativeTTL < 0 {
+		return errors.New("negative ttl is invalid")
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"net/url"
 	"time"
 
@@ -8,10 +11,16 @@ import (
 	"github.com/tidepool-org/platform/errors"
 )
 
+// Config specifies the client. If the TLS certificate and key files are specified, then the client presents the
+// certificate to servers that request it. If the TLS CA file is specified, then servers are verified against it rather
+// than the system roots.
 type Config struct {
-	Address   string
-	UserAgent string
-	Timeout   *time.Duration
+	Address            string
+	UserAgent          string
+	Timeout            *time.Duration
+	TLSCertificateFile string
+	TLSKeyFile         string
+	TLSCAFile          string
 }
 
 func NewConfig() *Config {
@@ -25,6 +34,9 @@ func (c *Config) Load(configReporter config.Reporter) error {
 
 	c.Address = configReporter.GetWithDefault("address", c.Address)
 	c.UserAgent = configReporter.GetWithDefault("user_agent", c.UserAgent)
+	c.TLSCertificateFile = configReporter.GetWithDefault("tls_certificate_file", c.TLSCertificateFile)
+	c.TLSKeyFile = configReporter.GetWithDefault("tls_key_file", c.TLSKeyFile)
+	c.TLSCAFile = configReporter.GetWithDefault("tls_ca_file", c.TLSCAFile)
 
 	return nil
 }
@@ -38,6 +50,44 @@ func (c *Config) Validate() error {
 	if c.UserAgent == "" {
 		return errors.New("user agent is missing")
 	}
+	if c.TLSCertificateFile != "" && c.TLSKeyFile == "" {
+		return errors.New("tls key file is missing")
+	} else if c.TLSCertificateFile == "" && c.TLSKeyFile != "" {
+		return errors.New("tls certificate file is missing")
+	}
 
 	return nil
 }
+
+func (c *Config) HasTLSCertificate() bool {
+	return c.TLSCertificateFile != "" && c.TLSKeyFile != ""
+}
+
+// TLSConfig returns the TLS config for the client certificate and CA, if either is specified, otherwise nil
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.HasTLSCertificate() && c.TLSCAFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if c.HasTLSCertificate() {
+		certificate, err := tls.LoadX509KeyPair(c.TLSCertificateFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to load tls certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if c.TLSCAFile != "" {
+		tlsCAs, err := ioutil.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read tls ca file")
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(tlsCAs) {
+			return nil, errors.New("tls ca file is invalid")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return tlsConfig, nil
+}
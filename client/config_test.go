@@ -21,6 +21,9 @@ var _ = Describe("Config", func() {
 			Expect(cfg).ToNot(BeNil())
 			Expect(cfg.Address).To(BeEmpty())
 			Expect(cfg.UserAgent).To(BeEmpty())
+			Expect(cfg.TLSCertificateFile).To(BeEmpty())
+			Expect(cfg.TLSKeyFile).To(BeEmpty())
+			Expect(cfg.TLSCAFile).To(BeEmpty())
 		})
 	})
 
@@ -43,6 +46,9 @@ var _ = Describe("Config", func() {
 				configReporter = configTest.NewReporter()
 				configReporter.Config["address"] = address
 				configReporter.Config["user_agent"] = userAgent
+				configReporter.Config["tls_certificate_file"] = "client.crt"
+				configReporter.Config["tls_key_file"] = "client.key"
+				configReporter.Config["tls_ca_file"] = "ca.crt"
 			})
 
 			It("returns an error if config reporter is missing", func() {
@@ -71,6 +77,9 @@ var _ = Describe("Config", func() {
 				Expect(cfg.Load(configReporter)).To(Succeed())
 				Expect(cfg.Address).To(Equal(address))
 				Expect(cfg.UserAgent).To(Equal(userAgent))
+				Expect(cfg.TLSCertificateFile).To(Equal("client.crt"))
+				Expect(cfg.TLSKeyFile).To(Equal("client.key"))
+				Expect(cfg.TLSCAFile).To(Equal("ca.crt"))
 			})
 		})
 
@@ -96,6 +105,16 @@ var _ = Describe("Config", func() {
 					Expect(cfg.Validate()).To(MatchError("user agent is missing"))
 				})
 
+				It("returns an error if the tls certificate file is specified without the tls key file", func() {
+					cfg.TLSCertificateFile = "client.crt"
+					Expect(cfg.Validate()).To(MatchError("tls key file is missing"))
+				})
+
+				It("returns an error if the tls key file is specified without the tls certificate file", func() {
+					cfg.TLSKeyFile = "client.key"
+					Expect(cfg.Validate()).To(MatchError("tls certificate file is missing"))
+				})
+
 				It("returns success", func() {
 					Expect(cfg.Validate()).To(Succeed())
 					Expect(cfg.Address).To(Equal(address))
@@ -103,5 +122,26 @@ var _ = Describe("Config", func() {
 				})
 			})
 		})
+
+		Context("TLSConfig", func() {
+			It("returns nil if neither a tls certificate nor a tls ca file is specified", func() {
+				Expect(cfg.TLSConfig()).To(BeNil())
+			})
+
+			It("returns an error if the tls certificate cannot be loaded", func() {
+				cfg.TLSCertificateFile = "does_not_exist.crt"
+				cfg.TLSKeyFile = "does_not_exist.key"
+				tlsConfig, err := cfg.TLSConfig()
+				Expect(err).To(MatchError(HavePrefix("unable to load tls certificate")))
+				Expect(tlsConfig).To(BeNil())
+			})
+
+			It("returns an error if the tls ca file does not contain certificates", func() {
+				cfg.TLSCAFile = "config_test.go"
+				tlsConfig, err := cfg.TLSConfig()
+				Expect(err).To(MatchError("tls ca file is invalid"))
+				Expect(tlsConfig).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,79 @@
+package clinics
+
+import (
+	"context"
+
+	"github.com/tidepool-org/platform/cache"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/request"
+)
+
+// CachingClient caches whether clinicians are members of their patients' clinics. Clinicians that are not are cached
+// for the shorter negative time to live, so that newly added clinic members gain access promptly.
+type CachingClient struct {
+	Client
+	cache *cache.Cache
+}
+
+func NewCachingClient(client Client, cfg *cache.Config) (*CachingClient, error) {
+	if client == nil {
+		return nil, errors.New("client is missing")
+	}
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+
+	cch, err := cache.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachingClient{
+		Client: client,
+		cache:  cch,
+	}, nil
+}
+
+func (c *CachingClient) IsPatientClinician(ctx context.Context, patientID, clinicianID string) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if patientID == "" {
+		return false, errors.New("patient id is missing")
+	}
+	if clinicianID == "" {
+		return false, errors.New("clinician id is missing")
+	}
+
+	key := CacheKey(patientID, clinicianID)
+	if value, ok := c.cache.Get(key); ok {
+		if _, ok := value.(error); ok {
+			return false, nil
+		}
+		return value.(bool), nil
+	}
+
+	isPatientClinician, err := c.Client.IsPatientClinician(ctx, patientID, clinicianID)
+	if err != nil {
+		return false, err
+	}
+
+	if isPatientClinician {
+		c.cache.Set(key, true, patientID, clinicianID)
+	} else {
+		c.cache.SetError(key, request.ErrorUnauthorized(), patientID, clinicianID)
+	}
+	return isPatientClinician, nil
+}
+
+// InvalidateUser removes all cached clinic memberships related to the user
+func (c *CachingClient) InvalidateUser(userID string) {
+	c.cache.DeleteTagged(userID)
+}
+
+// CacheKey returns the key of the cached clinic membership of the clinician for the patient
+func CacheKey(patientID string, clinicianID string) string {
+	return "clinics:" + patientID + ":" + clinicianID
+}
@@ -0,0 +1,118 @@
+package clinics_test
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/cache"
+	"github.com/tidepool-org/platform/clinics"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	userTest "github.com/tidepool-org/platform/user/test"
+)
+
+var _ = Describe("CachingClient", func() {
+	var ctrl *gomock.Controller
+	var client *clinics.MockClient
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = clinics.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("NewCachingClient", func() {
+		It("returns an error if the client is missing", func() {
+			cachingClient, err := clinics.NewCachingClient(nil, cache.NewConfig())
+			Expect(err).To(MatchError("client is missing"))
+			Expect(cachingClient).To(BeNil())
+		})
+
+		It("returns an error if the config is missing", func() {
+			cachingClient, err := clinics.NewCachingClient(client, nil)
+			Expect(err).To(MatchError("config is missing"))
+			Expect(cachingClient).To(BeNil())
+		})
+
+		It("returns an error if the config is invalid", func() {
+			cfg := cache.NewConfig()
+			cfg.Size = -1
+			cachingClient, err := clinics.NewCachingClient(client, cfg)
+			Expect(err).To(MatchError("config is invalid; size is invalid"))
+			Expect(cachingClient).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(clinics.NewCachingClient(client, cache.NewConfig())).ToNot(BeNil())
+		})
+	})
+
+	Context("with caching client", func() {
+		var ctx context.Context
+		var patientID string
+		var clinicianID string
+		var cachingClient *clinics.CachingClient
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			patientID = userTest.RandomID()
+			clinicianID = userTest.RandomID()
+			var err error
+			cachingClient, err = clinics.NewCachingClient(client, cache.NewConfig())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("IsPatientClinician", func() {
+			It("returns an error if the patient id is missing", func() {
+				isPatientClinician, err := cachingClient.IsPatientClinician(ctx, "", clinicianID)
+				Expect(err).To(MatchError("patient id is missing"))
+				Expect(isPatientClinician).To(BeFalse())
+			})
+
+			It("returns an error if the clinician id is missing", func() {
+				isPatientClinician, err := cachingClient.IsPatientClinician(ctx, patientID, "")
+				Expect(err).To(MatchError("clinician id is missing"))
+				Expect(isPatientClinician).To(BeFalse())
+			})
+
+			It("returns the error and does not cache it", func() {
+				testErr := errorsTest.RandomError()
+				client.EXPECT().IsPatientClinician(ctx, patientID, clinicianID).Return(false, testErr).Times(2)
+				for index := 0; index < 2; index++ {
+					isPatientClinician, err := cachingClient.IsPatientClinician(ctx, patientID, clinicianID)
+					Expect(err).To(Equal(testErr))
+					Expect(isPatientClinician).To(BeFalse())
+				}
+			})
+
+			It("caches the clinician being a member of the patient's clinic", func() {
+				client.EXPECT().IsPatientClinician(ctx, patientID, clinicianID).Return(true, nil).Times(1)
+				for index := 0; index < 2; index++ {
+					Expect(cachingClient.IsPatientClinician(ctx, patientID, clinicianID)).To(BeTrue())
+				}
+			})
+
+			It("caches the clinician not being a member of the patient's clinic", func() {
+				client.EXPECT().IsPatientClinician(ctx, patientID, clinicianID).Return(false, nil).Times(1)
+				for index := 0; index < 2; index++ {
+					Expect(cachingClient.IsPatientClinician(ctx, patientID, clinicianID)).To(BeFalse())
+				}
+			})
+
+			It("does not return the cached membership once either user is invalidated", func() {
+				client.EXPECT().IsPatientClinician(ctx, patientID, clinicianID).Return(true, nil).Times(3)
+				Expect(cachingClient.IsPatientClinician(ctx, patientID, clinicianID)).To(BeTrue())
+				cachingClient.InvalidateUser(patientID)
+				Expect(cachingClient.IsPatientClinician(ctx, patientID, clinicianID)).To(BeTrue())
+				cachingClient.InvalidateUser(clinicianID)
+				Expect(cachingClient.IsPatientClinician(ctx, patientID, clinicianID)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -50,6 +50,21 @@ func (mr *MockClientMockRecorder) GetClinician(ctx, clinicID, clinicianID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClinician", reflect.TypeOf((*MockClient)(nil).GetClinician), ctx, clinicID, clinicianID)
 }
 
+// IsPatientClinician mocks base method.
+func (m *MockClient) IsPatientClinician(ctx context.Context, patientID, clinicianID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPatientClinician", ctx, patientID, clinicianID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPatientClinician indicates an expected call of IsPatientClinician.
+func (mr *MockClientMockRecorder) IsPatientClinician(ctx, patientID, clinicianID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPatientClinician", reflect.TypeOf((*MockClient)(nil).IsPatientClinician), ctx, patientID, clinicianID)
+}
+
 // SharePatientAccount mocks base method.
 func (m *MockClient) SharePatientAccount(ctx context.Context, clinicID, patientID string) (*api.Patient, error) {
 	m.ctrl.T.Helper()
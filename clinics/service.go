@@ -22,6 +22,9 @@ var ClientModule = fx.Provide(NewClient)
 type Client interface {
 	GetClinician(ctx context.Context, clinicID, clinicianID string) (*clinic.Clinician, error)
 	SharePatientAccount(ctx context.Context, clinicID, patientID string) (*clinic.Patient, error)
+
+	// IsPatientClinician returns true if the clinician is a member of any clinic the patient has shared their data with
+	IsPatientClinician(ctx context.Context, patientID, clinicianID string) (bool, error)
 }
 
 type config struct {
@@ -98,6 +101,57 @@ func (d *defaultClient) SharePatientAccount(ctx context.Context, clinicID, patie
 	return response.JSON200, nil
 }
 
+func (d *defaultClient) IsPatientClinician(ctx context.Context, patientID, clinicianID string) (bool, error) {
+	clinicIDs := map[string]bool{}
+	for offset := 0; ; offset += listLimit {
+		params := &clinic.ListClinicsForPatientParams{Offset: offsetParam(offset), Limit: limitParam()}
+		response, err := d.httpClient.ListClinicsForPatientWithResponse(ctx, clinic.UserId(patientID), params)
+		if err != nil {
+			return false, err
+		}
+		if response.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		if response.StatusCode() != http.StatusOK || response.JSON200 == nil {
+			return false, fmt.Errorf("unexpected response status code %v from %v", response.StatusCode(), response.HTTPResponse.Request.URL)
+		}
+		for _, relationship := range *response.JSON200 {
+			if hasReadPermission(relationship.Patient.Permissions) {
+				clinicIDs[string(relationship.Clinic.Id)] = true
+			}
+		}
+		if len(*response.JSON200) < listLimit {
+			break
+		}
+	}
+	if len(clinicIDs) == 0 {
+		return false, nil
+	}
+
+	for offset := 0; ; offset += listLimit {
+		params := &clinic.ListClinicsForClinicianParams{Offset: offsetParam(offset), Limit: limitParam()}
+		response, err := d.httpClient.ListClinicsForClinicianWithResponse(ctx, clinic.UserId(clinicianID), params)
+		if err != nil {
+			return false, err
+		}
+		if response.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		if response.StatusCode() != http.StatusOK || response.JSON200 == nil {
+			return false, fmt.Errorf("unexpected response status code %v from %v", response.StatusCode(), response.HTTPResponse.Request.URL)
+		}
+		for _, relationship := range *response.JSON200 {
+			if clinicIDs[string(relationship.Clinic.Id)] {
+				return true, nil
+			}
+		}
+		if len(*response.JSON200) < listLimit {
+			break
+		}
+	}
+	return false, nil
+}
+
 func (d *defaultClient) getPatient(ctx context.Context, clinicID, patientID string) (*clinic.Patient, error) {
 	response, err := d.httpClient.GetPatientWithResponse(ctx, clinic.ClinicId(clinicID), clinic.PatientId(patientID))
 	if err != nil {
@@ -108,3 +162,19 @@ func (d *defaultClient) getPatient(ctx context.Context, clinicID, patientID stri
 	}
 	return response.JSON200, nil
 }
+
+const listLimit = 100
+
+func offsetParam(offset int) *clinic.Offset {
+	value := clinic.Offset(offset)
+	return &value
+}
+
+func limitParam() *clinic.Limit {
+	value := clinic.Limit(listLimit)
+	return &value
+}
+
+func hasReadPermission(permissions *clinic.PatientPermissions) bool {
+	return permissions != nil && (permissions.View != nil || permissions.Upload != nil || permissions.Custodian != nil)
+}
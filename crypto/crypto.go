@@ -4,6 +4,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 
@@ -45,6 +46,11 @@ func HexEncodedMD5Hash(sourceString string) string {
 	return hex.EncodeToString(md5Sum[:])
 }
 
+func HexEncodedSHA256Hash(sourceString string) string {
+	sha256Sum := sha256.Sum256([]byte(sourceString))
+	return hex.EncodeToString(sha256Sum[:])
+}
+
 func EncryptWithAES256UsingPassphrase(bites []byte, passphrase []byte) (_ []byte, err error) {
 	defer func() {
 		if r := recover(); r != nil {
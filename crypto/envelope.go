@@ -0,0 +1,181 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/tidepool-org/platform/config"
+	"github.com/tidepool-org/platform/errors"
+)
+
+const EnvelopeKeyLength = 32
+
+// EnvelopeConfig contains the master keys as a comma-separated list of "id:key" pairs, where each key is the Base64
+// encoding of 32 random bytes, and the id of the master key used to encrypt new data keys. Retired master keys must
+// remain in the list until all data keys encrypted with them are rotated.
+type EnvelopeConfig struct {
+	MasterKeys  string
+	MasterKeyID string
+}
+
+func NewEnvelopeConfig() *EnvelopeConfig {
+	return &EnvelopeConfig{}
+}
+
+func (e *EnvelopeConfig) Load(configReporter config.Reporter) error {
+	if configReporter == nil {
+		return errors.New("config reporter is missing")
+	}
+
+	e.MasterKeys = configReporter.GetWithDefault("master_keys", e.MasterKeys)
+	e.MasterKeyID = configReporter.GetWithDefault("master_key_id", e.MasterKeyID)
+
+	return nil
+}
+
+// IsEnabled returns true if any master keys are configured
+func (e *EnvelopeConfig) IsEnabled() bool {
+	return strings.TrimSpace(e.MasterKeys) != ""
+}
+
+// Sealed is data encrypted with a random data key, along with the data key encrypted with the identified master key
+type Sealed struct {
+	MasterKeyID      string `json:"masterKeyId" bson:"masterKeyId"`
+	EncryptedDataKey []byte `json:"encryptedDataKey" bson:"encryptedDataKey"`
+	Ciphertext       []byte `json:"ciphertext" bson:"ciphertext"`
+}
+
+// Envelope encrypts each value with a new data key using AES-256-GCM, and encrypts the data key with the current
+// master key. Values encrypted with any known master key can be decrypted.
+type Envelope struct {
+	masterKeys  map[string][]byte
+	masterKeyID string
+}
+
+func NewEnvelope(cfg *EnvelopeConfig) (*Envelope, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	}
+
+	masterKeys := map[string][]byte{}
+	for _, masterKeyString := range strings.Split(cfg.MasterKeys, ",") {
+		if masterKeyString = strings.TrimSpace(masterKeyString); masterKeyString == "" {
+			continue
+		}
+		parts := strings.SplitN(masterKeyString, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New("master keys is invalid")
+		}
+		masterKey, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil || len(masterKey) != EnvelopeKeyLength {
+			return nil, errors.Newf("master key %q is invalid", parts[0])
+		}
+		masterKeys[parts[0]] = masterKey
+	}
+	if len(masterKeys) == 0 {
+		return nil, errors.New("master keys is missing")
+	}
+	if cfg.MasterKeyID == "" {
+		return nil, errors.New("master key id is missing")
+	} else if _, ok := masterKeys[cfg.MasterKeyID]; !ok {
+		return nil, errors.New("master key id is invalid")
+	}
+
+	return &Envelope{
+		masterKeys:  masterKeys,
+		masterKeyID: cfg.MasterKeyID,
+	}, nil
+}
+
+func (e *Envelope) MasterKeyID() string {
+	return e.masterKeyID
+}
+
+func (e *Envelope) Seal(plaintext []byte) (*Sealed, error) {
+	if len(plaintext) == 0 {
+		return nil, errors.New("plaintext is missing")
+	}
+
+	dataKey := make([]byte, EnvelopeKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, errors.Wrap(err, "unable to generate data key")
+	}
+
+	ciphertext, err := encryptWithAES256GCM(plaintext, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt plaintext")
+	}
+	encryptedDataKey, err := encryptWithAES256GCM(dataKey, e.masterKeys[e.masterKeyID])
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt data key")
+	}
+
+	return &Sealed{
+		MasterKeyID:      e.masterKeyID,
+		EncryptedDataKey: encryptedDataKey,
+		Ciphertext:       ciphertext,
+	}, nil
+}
+
+func (e *Envelope) Open(sealed *Sealed) ([]byte, error) {
+	if sealed == nil {
+		return nil, errors.New("sealed is missing")
+	}
+
+	masterKey, ok := e.masterKeys[sealed.MasterKeyID]
+	if !ok {
+		return nil, errors.Newf("master key %q is unknown", sealed.MasterKeyID)
+	}
+
+	dataKey, err := decryptWithAES256GCM(sealed.EncryptedDataKey, masterKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decrypt data key")
+	}
+	plaintext, err := decryptWithAES256GCM(sealed.Ciphertext, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decrypt ciphertext")
+	}
+
+	return plaintext, nil
+}
+
+// IsCurrent returns true if the sealed data key is encrypted with the current master key
+func (e *Envelope) IsCurrent(sealed *Sealed) bool {
+	return sealed != nil && sealed.MasterKeyID == e.masterKeyID
+}
+
+func encryptWithAES256GCM(plaintext []byte, key []byte) ([]byte, error) {
+	gcm, err := newAES256GCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptWithAES256GCM(ciphertext []byte, key []byte) ([]byte, error) {
+	gcm, err := newAES256GCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is invalid")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+func newAES256GCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
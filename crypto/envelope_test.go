@@ -0,0 +1,153 @@
+package crypto_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	"github.com/tidepool-org/platform/crypto"
+	"github.com/tidepool-org/platform/test"
+)
+
+func randomMasterKey() string {
+	return base64.StdEncoding.EncodeToString(test.RandomBytesFromRange(crypto.EnvelopeKeyLength, crypto.EnvelopeKeyLength))
+}
+
+var _ = Describe("Envelope", func() {
+	Context("EnvelopeConfig", func() {
+		var cfg *crypto.EnvelopeConfig
+
+		BeforeEach(func() {
+			cfg = crypto.NewEnvelopeConfig()
+			Expect(cfg).ToNot(BeNil())
+		})
+
+		It("is not enabled by default", func() {
+			Expect(cfg.IsEnabled()).To(BeFalse())
+		})
+
+		It("returns an error if the config reporter is missing", func() {
+			Expect(cfg.Load(nil)).To(MatchError("config reporter is missing"))
+		})
+
+		It("loads the master keys", func() {
+			configReporter := configTest.NewReporter()
+			configReporter.Config["master_keys"] = "one:" + randomMasterKey()
+			configReporter.Config["master_key_id"] = "one"
+			Expect(cfg.Load(configReporter)).To(Succeed())
+			Expect(cfg.IsEnabled()).To(BeTrue())
+			Expect(cfg.MasterKeyID).To(Equal("one"))
+		})
+	})
+
+	Context("NewEnvelope", func() {
+		var cfg *crypto.EnvelopeConfig
+
+		BeforeEach(func() {
+			cfg = crypto.NewEnvelopeConfig()
+			cfg.MasterKeys = "one:" + randomMasterKey() + ", two:" + randomMasterKey()
+			cfg.MasterKeyID = "two"
+		})
+
+		It("returns an error if the config is missing", func() {
+			envelope, err := crypto.NewEnvelope(nil)
+			Expect(err).To(MatchError("config is missing"))
+			Expect(envelope).To(BeNil())
+		})
+
+		It("returns an error if the master keys are missing", func() {
+			cfg.MasterKeys = ""
+			envelope, err := crypto.NewEnvelope(cfg)
+			Expect(err).To(MatchError("master keys is missing"))
+			Expect(envelope).To(BeNil())
+		})
+
+		It("returns an error if the master keys are invalid", func() {
+			cfg.MasterKeys = "invalid"
+			envelope, err := crypto.NewEnvelope(cfg)
+			Expect(err).To(MatchError("master keys is invalid"))
+			Expect(envelope).To(BeNil())
+		})
+
+		It("returns an error if a master key is not the correct length", func() {
+			cfg.MasterKeys = "one:" + base64.StdEncoding.EncodeToString([]byte("short"))
+			envelope, err := crypto.NewEnvelope(cfg)
+			Expect(err).To(MatchError(`master key "one" is invalid`))
+			Expect(envelope).To(BeNil())
+		})
+
+		It("returns an error if the master key id is missing", func() {
+			cfg.MasterKeyID = ""
+			envelope, err := crypto.NewEnvelope(cfg)
+			Expect(err).To(MatchError("master key id is missing"))
+			Expect(envelope).To(BeNil())
+		})
+
+		It("returns an error if the master key id is not one of the master keys", func() {
+			cfg.MasterKeyID = "three"
+			envelope, err := crypto.NewEnvelope(cfg)
+			Expect(err).To(MatchError("master key id is invalid"))
+			Expect(envelope).To(BeNil())
+		})
+
+		Context("with new envelope", func() {
+			var envelope *crypto.Envelope
+			var plaintext []byte
+
+			BeforeEach(func() {
+				var err error
+				envelope, err = crypto.NewEnvelope(cfg)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(envelope.MasterKeyID()).To(Equal("two"))
+				plaintext = test.RandomBytes()
+			})
+
+			It("returns an error if the plaintext is missing", func() {
+				sealed, err := envelope.Seal(nil)
+				Expect(err).To(MatchError("plaintext is missing"))
+				Expect(sealed).To(BeNil())
+			})
+
+			It("seals with the current master key and opens successfully", func() {
+				sealed, err := envelope.Seal(plaintext)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sealed.MasterKeyID).To(Equal("two"))
+				Expect(sealed.Ciphertext).ToNot(Equal(plaintext))
+				Expect(envelope.IsCurrent(sealed)).To(BeTrue())
+				Expect(envelope.Open(sealed)).To(Equal(plaintext))
+			})
+
+			It("opens data sealed with a previous master key", func() {
+				previousCfg := crypto.NewEnvelopeConfig()
+				previousCfg.MasterKeys = cfg.MasterKeys
+				previousCfg.MasterKeyID = "one"
+				previousEnvelope, err := crypto.NewEnvelope(previousCfg)
+				Expect(err).ToNot(HaveOccurred())
+				sealed, err := previousEnvelope.Seal(plaintext)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(envelope.IsCurrent(sealed)).To(BeFalse())
+				Expect(envelope.Open(sealed)).To(Equal(plaintext))
+			})
+
+			It("returns an error if the master key is unknown", func() {
+				sealed, err := envelope.Seal(plaintext)
+				Expect(err).ToNot(HaveOccurred())
+				sealed.MasterKeyID = "three"
+				opened, err := envelope.Open(sealed)
+				Expect(err).To(MatchError(`master key "three" is unknown`))
+				Expect(opened).To(BeNil())
+			})
+
+			It("returns an error if the ciphertext was modified", func() {
+				sealed, err := envelope.Seal(plaintext)
+				Expect(err).ToNot(HaveOccurred())
+				sealed.Ciphertext[len(sealed.Ciphertext)-1] ^= 0xff
+				opened, err := envelope.Open(sealed)
+				Expect(err).To(MatchError(HavePrefix("unable to decrypt ciphertext")))
+				Expect(opened).To(BeNil())
+			})
+		})
+	})
+})
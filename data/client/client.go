@@ -28,6 +28,7 @@ type Client interface {
 	data.DataSetAccessor
 
 	CreateDataSetsData(ctx context.Context, dataSetID string, datumArray []data.Datum) error
+	DeleteDataSetsData(ctx context.Context, dataSetID string, selectors *data.Selectors) error
 
 	DestroyDataForUserByID(ctx context.Context, userID string) error
 
@@ -268,6 +269,29 @@ func (c *ClientImpl) CreateDataSetsData(ctx context.Context, dataSetID string, d
 	return c.client.RequestData(ctx, http.MethodPost, url, nil, datumArray, &response)
 }
 
+func (c *ClientImpl) DeleteDataSetsData(ctx context.Context, dataSetID string, selectors *data.Selectors) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if dataSetID == "" {
+		return errors.New("data set id is missing")
+	}
+	if selectors == nil {
+		return errors.New("selectors is missing")
+	} else if err := structureValidator.New().Validate(selectors); err != nil {
+		return errors.Wrap(err, "selectors is invalid")
+	}
+
+	// TODO: Remove response wrapper once service is updated
+	url := c.client.ConstructURL("v1", "data_sets", dataSetID, "data")
+	response := struct {
+		Data   *interface{}     `json:"data,omitempty"`
+		Errors []*service.Error `json:"errors,omitempty"`
+		Meta   *interface{}     `json:"meta,omitempty"`
+	}{}
+	return c.client.RequestData(ctx, http.MethodDelete, url, nil, selectors, &response)
+}
+
 // TODO: Rename for consistency
 
 func (c *ClientImpl) DestroyDataForUserByID(ctx context.Context, userID string) error {
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega/ghttp"
 
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/data"
 	dataClient "github.com/tidepool-org/platform/data/client"
 	dataTest "github.com/tidepool-org/platform/data/test"
 	"github.com/tidepool-org/platform/log"
@@ -83,6 +84,82 @@ var _ = Describe("Client", func() {
 			}
 		})
 
+		Context("DeleteDataSetsData", func() {
+			var dataSetID string
+			var selectors *data.Selectors
+
+			BeforeEach(func() {
+				dataSetID = dataTest.RandomSetID()
+				selectors = dataTest.RandomSelectors()
+			})
+
+			It("returns error if context is missing", func() {
+				Expect(clnt.DeleteDataSetsData(nil, dataSetID, selectors)).To(MatchError("context is missing"))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("returns error if data set id is missing", func() {
+				Expect(clnt.DeleteDataSetsData(ctx, "", selectors)).To(MatchError("data set id is missing"))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("returns error if selectors is missing", func() {
+				Expect(clnt.DeleteDataSetsData(ctx, dataSetID, nil)).To(MatchError("selectors is missing"))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("returns error if selectors is invalid", func() {
+				Expect(clnt.DeleteDataSetsData(ctx, dataSetID, data.NewSelectors())).To(MatchError("selectors is invalid; value is empty"))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			Context("with server token", func() {
+				var token string
+
+				BeforeEach(func() {
+					token = dataTest.NewSessionToken()
+					ctx = auth.NewContextWithServerSessionToken(ctx, token)
+				})
+
+				Context("with an unauthorized response", func() {
+					BeforeEach(func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("DELETE", fmt.Sprintf("/v1/data_sets/%s/data", dataSetID)),
+								VerifyHeaderKV("User-Agent", userAgent),
+								VerifyHeaderKV("X-Tidepool-Session-Token", token),
+								VerifyJSONRepresenting(selectors),
+								RespondWith(http.StatusUnauthorized, nil)),
+						)
+					})
+
+					It("returns an error", func() {
+						err := clnt.DeleteDataSetsData(ctx, dataSetID, selectors)
+						Expect(err).To(MatchError("authentication token is invalid"))
+						Expect(server.ReceivedRequests()).To(HaveLen(1))
+					})
+				})
+
+				Context("with a successful response", func() {
+					BeforeEach(func() {
+						server.AppendHandlers(
+							CombineHandlers(
+								VerifyRequest("DELETE", fmt.Sprintf("/v1/data_sets/%s/data", dataSetID)),
+								VerifyHeaderKV("User-Agent", userAgent),
+								VerifyHeaderKV("X-Tidepool-Session-Token", token),
+								VerifyJSONRepresenting(selectors),
+								RespondWith(http.StatusOK, "{}")),
+						)
+					})
+
+					It("returns success", func() {
+						Expect(clnt.DeleteDataSetsData(ctx, dataSetID, selectors)).To(Succeed())
+						Expect(server.ReceivedRequests()).To(HaveLen(1))
+					})
+				})
+			})
+		})
+
 		Context("DestroyDataForUserByID", func() {
 			var userID string
 
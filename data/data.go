@@ -3,6 +3,7 @@ package data
 import (
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/id"
@@ -12,6 +13,8 @@ import (
 
 const (
 	SelectorOriginIDLengthMaximum = 100
+
+	SelectorRangeTimeFormat = time.RFC3339Nano
 )
 
 type SelectorOrigin struct {
@@ -39,9 +42,46 @@ func (s *SelectorOrigin) Validate(validator structure.Validator) {
 	validator.String("id", s.ID).Exists().NotEmpty().LengthLessThanOrEqualTo(SelectorOriginIDLengthMaximum)
 }
 
+// SelectorRange selects data of any of the types with a time within the time range, inclusive of the start time and
+// exclusive of the end time
+type SelectorRange struct {
+	Types     *[]string  `json:"types,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+func ParseSelectorRange(parser structure.ObjectParser) *SelectorRange {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSelectorRange()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSelectorRange() *SelectorRange {
+	return &SelectorRange{}
+}
+
+func (s *SelectorRange) Parse(parser structure.ObjectParser) {
+	s.Types = parser.StringArray("types")
+	s.StartTime = parser.Time("startTime", SelectorRangeTimeFormat)
+	s.EndTime = parser.Time("endTime", SelectorRangeTimeFormat)
+}
+
+func (s *SelectorRange) Validate(validator structure.Validator) {
+	validator.StringArray("types", s.Types).Exists().NotEmpty().EachNotEmpty().EachNotOneOf("upload").EachUnique()
+	validator.Time("startTime", s.StartTime).Exists().NotZero()
+	endTimeValidator := validator.Time("endTime", s.EndTime).Exists()
+	if s.StartTime != nil {
+		endTimeValidator.After(*s.StartTime)
+	}
+}
+
 type Selector struct {
 	ID     *string         `json:"id,omitempty"`
 	Origin *SelectorOrigin `json:"origin,omitempty"`
+	Range  *SelectorRange  `json:"range,omitempty"`
 }
 
 func ParseSelector(parser structure.ObjectParser) *Selector {
@@ -60,15 +100,25 @@ func NewSelector() *Selector {
 func (s *Selector) Parse(parser structure.ObjectParser) {
 	s.ID = parser.String("id")
 	s.Origin = ParseSelectorOrigin(parser.WithReferenceObjectParser("origin"))
+	s.Range = ParseSelectorRange(parser.WithReferenceObjectParser("range"))
 }
 
 func (s *Selector) Validate(validator structure.Validator) {
-	if (s.ID != nil) == (s.Origin != nil) {
-		validator.ReportError(structureValidator.ErrorValuesNotExistForOne("id", "origin"))
+	count := 0
+	for _, exists := range []bool{s.ID != nil, s.Origin != nil, s.Range != nil} {
+		if exists {
+			count++
+		}
+	}
+
+	if count != 1 {
+		validator.ReportError(structureValidator.ErrorValuesNotExistForOne("id", "origin", "range"))
 	} else if s.ID != nil {
 		validator.String("id", s.ID).Using(IDValidator)
-	} else {
+	} else if s.Origin != nil {
 		s.Origin.Validate(validator.WithReference("origin"))
+	} else {
+		s.Range.Validate(validator.WithReference("range"))
 	}
 }
 
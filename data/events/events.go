@@ -20,11 +20,16 @@ type userDeletionEventsHandler struct {
 }
 
 func NewUserDataDeletionHandler(ctx context.Context, dataStore dataStore.Store, dataSourceStore dataSourceStoreStructured.Store) ev.EventHandler {
-	return ev.NewUserEventsHandler(&userDeletionEventsHandler{
+	return ev.NewUserEventsHandler(NewUserDataDeletionEventsHandler(ctx, dataStore, dataSourceStore))
+}
+
+// NewUserDataDeletionEventsHandler returns the user events handler that deletes all data for a deleted user
+func NewUserDataDeletionEventsHandler(ctx context.Context, dataStore dataStore.Store, dataSourceStore dataSourceStoreStructured.Store) ev.UserEventsHandler {
+	return &userDeletionEventsHandler{
 		ctx:             ctx,
 		dataStore:       dataStore,
 		dataSourceStore: dataSourceStore,
-	})
+	}
 }
 
 func (u *userDeletionEventsHandler) HandleDeleteUserEvent(payload ev.DeleteUserEvent) error {
@@ -52,6 +57,13 @@ func (u *userDeletionEventsHandler) HandleDeleteUserEvent(payload ev.DeleteUserE
 		logger.WithError(err).Error("unable to delete summary for user")
 	}
 
+	logger.Infof("Deleting share grants for user")
+	shareGrantRepository := u.dataStore.NewShareGrantRepository()
+	if err := shareGrantRepository.DeleteUserGrants(u.ctx, payload.UserID); err != nil {
+		errs = append(errs, err)
+		logger.WithError(err).Error("unable to delete share grants for user")
+	}
+
 	if len(errs) != 0 {
 		return errors.New("Unable to delete device data for user")
 	}
@@ -0,0 +1,108 @@
+package events_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ev "github.com/tidepool-org/go-common/events"
+
+	dataEvents "github.com/tidepool-org/platform/data/events"
+	dataSourceStoreStructured "github.com/tidepool-org/platform/data/source/store/structured"
+	dataSourceStoreStructuredTest "github.com/tidepool-org/platform/data/source/store/structured/test"
+	dataStore "github.com/tidepool-org/platform/data/store"
+	dataStoreTest "github.com/tidepool-org/platform/data/store/test"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/log"
+	logTest "github.com/tidepool-org/platform/log/test"
+	userTest "github.com/tidepool-org/platform/user/test"
+)
+
+var _ = Describe("Events", func() {
+	Context("NewUserDataDeletionEventsHandler", func() {
+		var userID string
+		var dataRepository *dataStoreTest.DataRepository
+		var summaryRepository *testSummaryRepository
+		var shareGrantRepository *testShareGrantRepository
+		var dataSourcesRepository *dataSourceStoreStructuredTest.DataRepository
+		var handler ev.UserEventsHandler
+		var payload ev.DeleteUserEvent
+
+		BeforeEach(func() {
+			userID = userTest.RandomID()
+			dataRepository = dataStoreTest.NewDataRepository()
+			dataRepository.DestroyDataForUserByIDOutputs = []error{nil}
+			summaryRepository = &testSummaryRepository{}
+			shareGrantRepository = &testShareGrantRepository{}
+			dataSourcesRepository = dataSourceStoreStructuredTest.NewDataSourcesRepository()
+			dataSourcesRepository.DestroyAllOutputs = []dataSourceStoreStructuredTest.DestroyAllOutput{{Destroyed: true}}
+			dataSourceStore := dataSourceStoreStructuredTest.NewStore()
+			dataSourceStore.NewDataSourcesOutputs = []dataSourceStoreStructured.DataSourcesRepository{dataSourcesRepository}
+			store := &testStore{dataRepository: dataRepository, summaryRepository: summaryRepository, shareGrantRepository: shareGrantRepository}
+			handler = dataEvents.NewUserDataDeletionEventsHandler(log.NewContextWithLogger(context.Background(), logTest.NewLogger()), store, dataSourceStore)
+			payload = ev.DeleteUserEvent{}
+			payload.UserID = userID
+		})
+
+		AfterEach(func() {
+			dataRepository.Expectations()
+			dataSourcesRepository.AssertOutputsEmpty()
+		})
+
+		It("deletes the data, data sources, summary, and share grants for the user", func() {
+			Expect(handler.HandleDeleteUserEvent(payload)).To(Succeed())
+			Expect(dataRepository.DestroyDataForUserByIDInputs).To(HaveLen(1))
+			Expect(dataRepository.DestroyDataForUserByIDInputs[0].UserID).To(Equal(userID))
+			Expect(dataSourcesRepository.DestroyAllInputs).To(Equal([]string{userID}))
+			Expect(summaryRepository.deleteSummaryInputs).To(Equal([]string{userID}))
+			Expect(shareGrantRepository.deleteUserGrantsInputs).To(Equal([]string{userID}))
+		})
+
+		It("returns an error when the share grants are not deleted", func() {
+			shareGrantRepository.deleteUserGrantsError = errorsTest.RandomError()
+			Expect(handler.HandleDeleteUserEvent(payload)).To(MatchError("Unable to delete device data for user"))
+			Expect(shareGrantRepository.deleteUserGrantsInputs).To(Equal([]string{userID}))
+		})
+	})
+})
+
+type testStore struct {
+	dataStore.Store
+	dataRepository       dataStore.DataRepository
+	summaryRepository    dataStore.SummaryRepository
+	shareGrantRepository dataStore.ShareGrantRepository
+}
+
+func (s *testStore) NewDataRepository() dataStore.DataRepository {
+	return s.dataRepository
+}
+
+func (s *testStore) NewSummaryRepository() dataStore.SummaryRepository {
+	return s.summaryRepository
+}
+
+func (s *testStore) NewShareGrantRepository() dataStore.ShareGrantRepository {
+	return s.shareGrantRepository
+}
+
+type testSummaryRepository struct {
+	dataStore.SummaryRepository
+	deleteSummaryInputs []string
+}
+
+func (s *testSummaryRepository) DeleteSummary(ctx context.Context, id string) error {
+	s.deleteSummaryInputs = append(s.deleteSummaryInputs, id)
+	return nil
+}
+
+type testShareGrantRepository struct {
+	dataStore.ShareGrantRepository
+	deleteUserGrantsInputs []string
+	deleteUserGrantsError  error
+}
+
+func (s *testShareGrantRepository) DeleteUserGrants(ctx context.Context, userID string) error {
+	s.deleteUserGrantsInputs = append(s.deleteUserGrantsInputs, userID)
+	return s.deleteUserGrantsError
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/ant0ine/go-json-rest/rest"
 
+	"github.com/tidepool-org/platform/clinics"
 	dataClient "github.com/tidepool-org/platform/data/client"
 	"github.com/tidepool-org/platform/data/deduplicator"
 	dataService "github.com/tidepool-org/platform/data/service"
@@ -21,6 +22,7 @@ type Standard struct {
 	*api.API
 	metricClient            metric.Client
 	permissionClient        permission.Client
+	clinicsClient           clinics.Client
 	dataDeduplicatorFactory deduplicator.Factory
 	dataStore               dataStore.Store
 	syncTaskStore           syncTaskStore.Store
@@ -28,7 +30,7 @@ type Standard struct {
 	dataSourceClient        dataSource.Client
 }
 
-func NewStandard(svc service.Service, metricClient metric.Client, permissionClient permission.Client,
+func NewStandard(svc service.Service, metricClient metric.Client, permissionClient permission.Client, clinicsClient clinics.Client,
 	dataDeduplicatorFactory deduplicator.Factory,
 	store dataStore.Store, syncTaskStore syncTaskStore.Store, dataClient dataClient.Client, dataSourceClient dataSource.Client) (*Standard, error) {
 	if metricClient == nil {
@@ -37,6 +39,9 @@ func NewStandard(svc service.Service, metricClient metric.Client, permissionClie
 	if permissionClient == nil {
 		return nil, errors.New("permission client is missing")
 	}
+	if clinicsClient == nil {
+		return nil, errors.New("clinics client is missing")
+	}
 	if dataDeduplicatorFactory == nil {
 		return nil, errors.New("data deduplicator factory is missing")
 	}
@@ -62,6 +67,7 @@ func NewStandard(svc service.Service, metricClient metric.Client, permissionClie
 		API:                     a,
 		metricClient:            metricClient,
 		permissionClient:        permissionClient,
+		clinicsClient:           clinicsClient,
 		dataDeduplicatorFactory: dataDeduplicatorFactory,
 		dataStore:               store,
 		syncTaskStore:           syncTaskStore,
@@ -98,7 +104,7 @@ func (s *Standard) DEPRECATEDInitializeRouter(routes []dataService.Route) error
 }
 
 func (s *Standard) withContext(handler dataService.HandlerFunc) rest.HandlerFunc {
-	return dataContext.WithContext(s.AuthClient(), s.metricClient, s.permissionClient,
+	return dataContext.WithContext(s.AuthClient(), s.metricClient, s.permissionClient, s.clinicsClient,
 		s.dataDeduplicatorFactory,
 		s.dataStore, s.syncTaskStore, s.dataClient, s.dataSourceClient, handler)
 }
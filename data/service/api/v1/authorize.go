@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/tidepool-org/platform/auth"
+	dataService "github.com/tidepool-org/platform/data/service"
+	dataShare "github.com/tidepool-org/platform/data/share"
+	"github.com/tidepool-org/platform/permission"
+	"github.com/tidepool-org/platform/request"
+)
+
+// authorizeRead authorizes the requester to read the user's data of the type and records the authorization decision,
+// including how it was reached. It returns the share grant limiting the requester's read access to the user's data, or
+// nil if the requester's read access is not limited.
+func authorizeRead(ctx context.Context, dataServiceContext dataService.Context, userID string, typ string) (*dataShare.Grant, error) {
+	grant, grantPath, err := authorizeReadGrant(ctx, dataServiceContext, userID)
+	if err == nil && grant != nil && !grant.AllowsType(typ) {
+		err = request.ErrorUnauthorized()
+	}
+
+	event := auth.NewAuthorizationAuditEvent(ctx, userID, permission.Read, err)
+	event.Grant = grantPath
+	dataServiceContext.AuthClient().RecordAuditEvent(ctx, event)
+
+	if err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// authorizeReadGrant returns the share grant limiting the requester's read access, if any, and the grant path. The
+// user, services, and requesters with custodian or upload permission are never limited. A share grant takes precedence
+// over view permission. Otherwise, a clinician who is a member of any clinic the user has shared their data with may
+// read the user's data. If none apply, then an unauthorized error is returned.
+func authorizeReadGrant(ctx context.Context, dataServiceContext dataService.Context, userID string) (*dataShare.Grant, string, error) {
+	details := request.DetailsFromContext(ctx)
+	if details.IsService() {
+		return nil, auth.AuditEventGrantService, nil
+	} else if details.UserID() == userID {
+		return nil, auth.AuditEventGrantOwner, nil
+	}
+
+	permissions, err := dataServiceContext.PermissionClient().GetUserPermissions(ctx, details.UserID(), userID)
+	if err != nil && !request.IsErrorUnauthorized(err) {
+		return nil, "", err
+	}
+	if _, ok := permissions[permission.Custodian]; ok {
+		return nil, auth.AuditEventGrantPermission, nil
+	}
+	if _, ok := permissions[permission.Write]; ok {
+		return nil, auth.AuditEventGrantPermission, nil
+	}
+
+	grant, err := dataServiceContext.ShareGrantRepository().GetUserGranteeGrant(ctx, userID, details.UserID())
+	if err != nil {
+		return nil, "", err
+	} else if grant != nil {
+		return grant, auth.AuditEventGrantShare, nil
+	}
+
+	if _, ok := permissions[permission.Read]; ok {
+		return nil, auth.AuditEventGrantPermission, nil
+	}
+
+	if isPatientClinician, err := dataServiceContext.ClinicsClient().IsPatientClinician(ctx, userID, details.UserID()); err != nil {
+		return nil, "", err
+	} else if isPatientClinician {
+		return nil, auth.AuditEventGrantClinic, nil
+	}
+
+	return nil, "", request.ErrorUnauthorized()
+}
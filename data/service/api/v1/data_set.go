@@ -5,8 +5,8 @@ import (
 
 	"github.com/tidepool-org/platform/data"
 	dataService "github.com/tidepool-org/platform/data/service"
+	"github.com/tidepool-org/platform/data/types/upload"
 	"github.com/tidepool-org/platform/page"
-	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/request"
 )
 
@@ -48,23 +48,14 @@ func ListUserDataSets(dataServiceContext dataService.Context) {
 	}
 
 	// FUTURE: Refactor for global usage
-	if !details.IsService() && details.UserID() != userID {
-		permissions, err := dataServiceContext.PermissionClient().GetUserPermissions(req.Context(), details.UserID(), userID)
-		if err != nil {
-			if request.IsErrorUnauthorized(err) {
-				responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
-			} else {
-				responder.Error(http.StatusInternalServerError, err)
-			}
-			return
-		}
-		_, custodianPermission := permissions[permission.Custodian]
-		_, uploadPermission := permissions[permission.Write]
-		_, viewPermission := permissions[permission.Read]
-		if !custodianPermission && !uploadPermission && !viewPermission {
+	grant, err := authorizeRead(req.Context(), dataServiceContext, userID, upload.Type)
+	if err != nil {
+		if request.IsErrorUnauthorized(err) {
 			responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
-			return
+		} else {
+			responder.Error(http.StatusInternalServerError, err)
 		}
+		return
 	}
 
 	filter := data.NewDataSetFilter()
@@ -80,6 +71,16 @@ func ListUserDataSets(dataServiceContext dataService.Context) {
 		return
 	}
 
+	if grant != nil && grant.HasTimeRange() {
+		grantDataSets := data.DataSets{}
+		for _, dataSet := range dataSets {
+			if dataSet.CreatedTime != nil && grant.AllowsTime(*dataSet.CreatedTime) {
+				grantDataSets = append(grantDataSets, dataSet)
+			}
+		}
+		dataSets = grantDataSets
+	}
+
 	responder.Data(http.StatusOK, dataSets)
 }
 
@@ -26,6 +26,7 @@ func SourcesRoutes() []dataService.Route {
 		dataService.MakeRoute("POST", "/v1/users/:userId/data_sources", Authenticate(CreateSource)),
 		dataService.MakeRoute("DELETE", "/v1/users/:userId/data_sources", Authenticate(DeleteAllSources)),
 		dataService.MakeRoute("GET", "/v1/data_sources/:id", Authenticate(GetSource)),
+		dataService.MakeRoute("GET", "/v1/data_sources/:id/history", Authenticate(GetSourceHistory)),
 		dataService.MakeRoute("PUT", "/v1/data_sources/:id", Authenticate(UpdateSource)),
 		dataService.MakeRoute("DELETE", "/v1/data_sources/:id", Authenticate(DeleteSource)),
 	}
@@ -186,6 +187,50 @@ func GetSource(dataServiceContext dataService.Context) {
 	responder.Data(http.StatusOK, source)
 }
 
+// TODO: BEGIN: Update to new service paradigm
+// func (r *Router) GetSourceHistory(res rest.ResponseWriter, req *rest.Request) {
+
+func GetSourceHistory(dataServiceContext dataService.Context) {
+	res := dataServiceContext.Response()
+	req := dataServiceContext.Request()
+
+	details := request.DetailsFromContext(req.Context())
+	if details == nil {
+		request.MustNewResponder(res, req).Error(http.StatusUnauthorized, request.ErrorUnauthenticated())
+		return
+	}
+	// TODO: END: Update to new service paradigm
+
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	source, err := dataServiceContext.DataSourceClient().Get(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if source == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != *source.UserID {
+		request.MustNewResponder(res, req).Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	syncHistory := source.SyncHistory
+	if syncHistory == nil {
+		syncHistory = dataSource.NewSyncArray()
+	}
+
+	responder.Data(http.StatusOK, syncHistory)
+}
+
 // TODO: BEGIN: Update to new service paradigm
 // func (r *Router) UpdateSource(res rest.ResponseWriter, req *rest.Request) {
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/tidepool-org/platform/data"
 	dataService "github.com/tidepool-org/platform/data/service"
+	"github.com/tidepool-org/platform/log"
 	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/service"
@@ -68,5 +69,9 @@ func DataSetsDataDelete(dataServiceContext dataService.Context) {
 		return
 	}
 
+	if _, err = dataServiceContext.SummaryRepository().SetOutdated(ctx, *dataSet.UserID); err != nil {
+		log.LoggerFromContext(ctx).WithError(err).Error("Unable to set summary outdated")
+	}
+
 	dataServiceContext.RespondWithStatusAndData(http.StatusOK, []struct{}{})
 }
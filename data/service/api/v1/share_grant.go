@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"net/http"
+
+	dataService "github.com/tidepool-org/platform/data/service"
+	dataShare "github.com/tidepool-org/platform/data/share"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+)
+
+func ShareGrantsRoutes() []dataService.Route {
+	return []dataService.Route{
+		dataService.MakeRoute("GET", "/v1/users/:userId/share_grants", Authenticate(ListUserShareGrants)),
+		dataService.MakeRoute("POST", "/v1/users/:userId/share_grants", Authenticate(UpsertUserShareGrant)),
+		dataService.MakeRoute("GET", "/v1/share_grants/:id", Authenticate(GetShareGrant)),
+		dataService.MakeRoute("DELETE", "/v1/share_grants/:id", Authenticate(DeleteShareGrant)),
+	}
+}
+
+func ListUserShareGrants(dataServiceContext dataService.Context) {
+	res := dataServiceContext.Response()
+	req := dataServiceContext.Request()
+	details := request.DetailsFromContext(req.Context())
+	responder := request.MustNewResponder(res, req)
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != userID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	grants, err := dataServiceContext.ShareGrantRepository().ListUserGrants(req.Context(), userID, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, grants)
+}
+
+func UpsertUserShareGrant(dataServiceContext dataService.Context) {
+	res := dataServiceContext.Response()
+	req := dataServiceContext.Request()
+	details := request.DetailsFromContext(req.Context())
+	responder := request.MustNewResponder(res, req)
+
+	userID := req.PathParam("userId")
+	if userID == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("userId"))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != userID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	create := dataShare.NewGrantCreate()
+	if err := request.DecodeRequestBody(req.Request, create); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	} else if *create.GranteeUserID == userID {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterInvalid("granteeUserId"))
+		return
+	}
+
+	grant, err := dataServiceContext.ShareGrantRepository().UpsertUserGrant(req.Context(), userID, create)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, grant)
+}
+
+func GetShareGrant(dataServiceContext dataService.Context) {
+	res := dataServiceContext.Response()
+	req := dataServiceContext.Request()
+	details := request.DetailsFromContext(req.Context())
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	grant, err := dataServiceContext.ShareGrantRepository().GetGrant(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if grant == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != grant.UserID && details.UserID() != grant.GranteeUserID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	responder.Data(http.StatusOK, grant)
+}
+
+func DeleteShareGrant(dataServiceContext dataService.Context) {
+	res := dataServiceContext.Response()
+	req := dataServiceContext.Request()
+	details := request.DetailsFromContext(req.Context())
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	repository := dataServiceContext.ShareGrantRepository()
+
+	grant, err := repository.GetGrant(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if grant == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	if !details.IsService() && details.UserID() != grant.UserID {
+		responder.Error(http.StatusForbidden, request.ErrorUnauthorized())
+		return
+	}
+
+	if deleted, err := repository.DeleteGrant(req.Context(), id); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if !deleted {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	responder.Empty(http.StatusOK)
+}
@@ -5,9 +5,9 @@ import (
 	"net/http"
 
 	dataService "github.com/tidepool-org/platform/data/service"
-
+	dataShare "github.com/tidepool-org/platform/data/share"
+	"github.com/tidepool-org/platform/data/types/blood/glucose/continuous"
 	"github.com/tidepool-org/platform/page"
-	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/service"
 )
@@ -21,25 +21,19 @@ func SummaryRoutes() []dataService.Route {
 	}
 }
 
-func CheckPermissions(ctx context.Context, dataServiceContext dataService.Context, id string) bool {
-	details := request.DetailsFromContext(ctx)
-
-	if !details.IsService() {
-		permissions, err := dataServiceContext.PermissionClient().GetUserPermissions(ctx, details.UserID(), id)
-		if err != nil {
-			if request.IsErrorUnauthorized(err) {
-				dataServiceContext.RespondWithError(service.ErrorUnauthorized())
-			} else {
-				dataServiceContext.RespondWithInternalServerFailure("Unable to get user permissions", err)
-			}
-			return false
-		}
-		if _, ok := permissions[permission.Read]; !ok {
+// CheckPermissions returns the share grant limiting the requester's read access to the user's summary, if any, and
+// whether the requester may read the summary at all. If not, then the error response has been sent.
+func CheckPermissions(ctx context.Context, dataServiceContext dataService.Context, id string) (*dataShare.Grant, bool) {
+	grant, err := authorizeRead(ctx, dataServiceContext, id, continuous.Type)
+	if err != nil {
+		if request.IsErrorUnauthorized(err) {
 			dataServiceContext.RespondWithError(service.ErrorUnauthorized())
-			return false
+		} else {
+			dataServiceContext.RespondWithInternalServerFailure("Unable to get user permissions", err)
 		}
+		return nil, false
 	}
-	return true
+	return grant, true
 }
 
 func GetSummary(dataServiceContext dataService.Context) {
@@ -52,7 +46,8 @@ func GetSummary(dataServiceContext dataService.Context) {
 
 	id := req.PathParam("userId")
 
-	if !CheckPermissions(ctx, dataServiceContext, id) {
+	grant, ok := CheckPermissions(ctx, dataServiceContext, id)
+	if !ok {
 		return
 	}
 
@@ -63,6 +58,9 @@ func GetSummary(dataServiceContext dataService.Context) {
 	} else if summary == nil {
 		responder.Empty(http.StatusNotFound)
 	} else {
+		if grant != nil {
+			summary.Restrict(grant.StartTime, grant.EndTime)
+		}
 		responder.Data(http.StatusOK, summary)
 	}
 }
@@ -77,7 +75,8 @@ func UpdateSummary(dataServiceContext dataService.Context) {
 
 	id := req.PathParam("userId")
 
-	if !CheckPermissions(ctx, dataServiceContext, id) {
+	grant, ok := CheckPermissions(ctx, dataServiceContext, id)
+	if !ok {
 		return
 	}
 
@@ -87,6 +86,9 @@ func UpdateSummary(dataServiceContext dataService.Context) {
 	} else if summary == nil {
 		responder.Empty(http.StatusNotFound)
 	} else {
+		if grant != nil {
+			summary.Restrict(grant.StartTime, grant.EndTime)
+		}
 		responder.Data(http.StatusOK, summary)
 	}
 }
@@ -5,8 +5,8 @@ import (
 
 	dataService "github.com/tidepool-org/platform/data/service"
 	dataStore "github.com/tidepool-org/platform/data/store"
+	"github.com/tidepool-org/platform/data/types/upload"
 	"github.com/tidepool-org/platform/page"
-	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/service"
 )
@@ -20,20 +20,14 @@ func UsersDataSetsGet(dataServiceContext dataService.Context) {
 		return
 	}
 
-	if details := request.DetailsFromContext(ctx); !details.IsService() {
-		permissions, err := dataServiceContext.PermissionClient().GetUserPermissions(ctx, details.UserID(), targetUserID)
-		if err != nil {
-			if request.IsErrorUnauthorized(err) {
-				dataServiceContext.RespondWithError(service.ErrorUnauthorized())
-			} else {
-				dataServiceContext.RespondWithInternalServerFailure("Unable to get user permissions", err)
-			}
-			return
-		}
-		if _, ok := permissions[permission.Read]; !ok {
+	grant, err := authorizeRead(ctx, dataServiceContext, targetUserID, upload.Type)
+	if err != nil {
+		if request.IsErrorUnauthorized(err) {
 			dataServiceContext.RespondWithError(service.ErrorUnauthorized())
-			return
+		} else {
+			dataServiceContext.RespondWithInternalServerFailure("Unable to get user permissions", err)
 		}
+		return
 	}
 
 	filter := dataStore.NewFilter()
@@ -49,5 +43,15 @@ func UsersDataSetsGet(dataServiceContext dataService.Context) {
 		return
 	}
 
+	if grant != nil && grant.HasTimeRange() {
+		grantDataSets := []*upload.Upload{}
+		for _, dataSet := range dataSets {
+			if dataSet.CreatedTime != nil && grant.AllowsTime(*dataSet.CreatedTime) {
+				grantDataSets = append(grantDataSets, dataSet)
+			}
+		}
+		dataSets = grantDataSets
+	}
+
 	dataServiceContext.RespondWithStatusAndData(http.StatusOK, dataSets)
 }
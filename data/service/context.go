@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/clinics"
 	dataClient "github.com/tidepool-org/platform/data/client"
 	"github.com/tidepool-org/platform/data/deduplicator"
 	dataSource "github.com/tidepool-org/platform/data/source"
@@ -18,11 +19,13 @@ type Context interface {
 	AuthClient() auth.Client
 	MetricClient() metric.Client
 	PermissionClient() permission.Client
+	ClinicsClient() clinics.Client
 
 	DataDeduplicatorFactory() deduplicator.Factory
 
 	DataRepository() dataStore.DataRepository
 	SummaryRepository() dataStore.SummaryRepository
+	ShareGrantRepository() dataStore.ShareGrantRepository
 	SyncTaskRepository() syncTaskStore.SyncTaskRepository
 
 	DataClient() dataClient.Client
@@ -6,6 +6,7 @@ import (
 	"github.com/ant0ine/go-json-rest/rest"
 
 	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/clinics"
 	dataClient "github.com/tidepool-org/platform/data/client"
 	"github.com/tidepool-org/platform/data/deduplicator"
 	dataService "github.com/tidepool-org/platform/data/service"
@@ -23,21 +24,23 @@ type Standard struct {
 	authClient              auth.Client
 	metricClient            metric.Client
 	permissionClient        permission.Client
+	clinicsClient           clinics.Client
 	dataDeduplicatorFactory deduplicator.Factory
 	dataStore               dataStore.Store
 	dataRepository          dataStore.DataRepository
 	summaryRepository       dataStore.SummaryRepository
+	shareGrantRepository    dataStore.ShareGrantRepository
 	syncTaskStore           syncTaskStore.Store
 	syncTasksRepository     syncTaskStore.SyncTaskRepository
 	dataClient              dataClient.Client
 	dataSourceClient        dataSource.Client
 }
 
-func WithContext(authClient auth.Client, metricClient metric.Client, permissionClient permission.Client,
+func WithContext(authClient auth.Client, metricClient metric.Client, permissionClient permission.Client, clinicsClient clinics.Client,
 	dataDeduplicatorFactory deduplicator.Factory,
 	store dataStore.Store, syncTaskStore syncTaskStore.Store, dataClient dataClient.Client, dataSourceClient dataSource.Client, handler dataService.HandlerFunc) rest.HandlerFunc {
 	return func(response rest.ResponseWriter, request *rest.Request) {
-		standard, standardErr := NewStandard(response, request, authClient, metricClient, permissionClient,
+		standard, standardErr := NewStandard(response, request, authClient, metricClient, permissionClient, clinicsClient,
 			dataDeduplicatorFactory, store, syncTaskStore, dataClient, dataSourceClient)
 		if standardErr != nil {
 			if responder, responderErr := serviceContext.NewResponder(response, request); responderErr != nil {
@@ -54,7 +57,7 @@ func WithContext(authClient auth.Client, metricClient metric.Client, permissionC
 }
 
 func NewStandard(response rest.ResponseWriter, request *rest.Request,
-	authClient auth.Client, metricClient metric.Client, permissionClient permission.Client,
+	authClient auth.Client, metricClient metric.Client, permissionClient permission.Client, clinicsClient clinics.Client,
 	dataDeduplicatorFactory deduplicator.Factory,
 	store dataStore.Store, syncTaskStore syncTaskStore.Store, dataClient dataClient.Client, dataSourceClient dataSource.Client) (*Standard, error) {
 	if authClient == nil {
@@ -66,6 +69,9 @@ func NewStandard(response rest.ResponseWriter, request *rest.Request,
 	if permissionClient == nil {
 		return nil, errors.New("permission client is missing")
 	}
+	if clinicsClient == nil {
+		return nil, errors.New("clinics client is missing")
+	}
 	if dataDeduplicatorFactory == nil {
 		return nil, errors.New("data deduplicator factory is missing")
 	}
@@ -92,6 +98,7 @@ func NewStandard(response rest.ResponseWriter, request *rest.Request,
 		authClient:              authClient,
 		metricClient:            metricClient,
 		permissionClient:        permissionClient,
+		clinicsClient:           clinicsClient,
 		dataDeduplicatorFactory: dataDeduplicatorFactory,
 		dataStore:               store,
 		syncTaskStore:           syncTaskStore,
@@ -110,6 +117,9 @@ func (s *Standard) Close() {
 	if s.summaryRepository != nil {
 		s.summaryRepository = nil
 	}
+	if s.shareGrantRepository != nil {
+		s.shareGrantRepository = nil
+	}
 }
 
 func (s *Standard) AuthClient() auth.Client {
@@ -124,6 +134,10 @@ func (s *Standard) PermissionClient() permission.Client {
 	return s.permissionClient
 }
 
+func (s *Standard) ClinicsClient() clinics.Client {
+	return s.clinicsClient
+}
+
 func (s *Standard) DataDeduplicatorFactory() deduplicator.Factory {
 	return s.dataDeduplicatorFactory
 }
@@ -142,6 +156,13 @@ func (s *Standard) SummaryRepository() dataStore.SummaryRepository {
 	return s.summaryRepository
 }
 
+func (s *Standard) ShareGrantRepository() dataStore.ShareGrantRepository {
+	if s.shareGrantRepository == nil {
+		s.shareGrantRepository = s.dataStore.NewShareGrantRepository()
+	}
+	return s.shareGrantRepository
+}
+
 func (s *Standard) SyncTaskRepository() syncTaskStore.SyncTaskRepository {
 	if s.syncTasksRepository == nil {
 		s.syncTasksRepository = s.syncTaskStore.NewSyncTaskRepository()
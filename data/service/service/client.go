@@ -191,6 +191,10 @@ func (c *Client) CreateDataSetsData(ctx context.Context, dataSetID string, datum
 	panic("Not Implemented!")
 }
 
+func (c *Client) DeleteDataSetsData(ctx context.Context, dataSetID string, selectors *data.Selectors) error {
+	panic("Not Implemented!")
+}
+
 func (c *Client) DestroyDataForUserByID(ctx context.Context, userID string) error {
 	panic("Not Implemented!")
 }
@@ -9,11 +9,15 @@ import (
 	eventsCommon "github.com/tidepool-org/go-common/events"
 
 	"github.com/tidepool-org/platform/application"
+	authEvents "github.com/tidepool-org/platform/auth/events"
+	"github.com/tidepool-org/platform/cache"
+	"github.com/tidepool-org/platform/clinics"
 	dataDeduplicatorDeduplicator "github.com/tidepool-org/platform/data/deduplicator/deduplicator"
 	dataDeduplicatorFactory "github.com/tidepool-org/platform/data/deduplicator/factory"
 	dataEvents "github.com/tidepool-org/platform/data/events"
 	"github.com/tidepool-org/platform/data/service/api"
 	dataServiceApiV1 "github.com/tidepool-org/platform/data/service/api/v1"
+	dataSource "github.com/tidepool-org/platform/data/source"
 	dataSourceServiceClient "github.com/tidepool-org/platform/data/source/service/client"
 	dataSourceStoreStructured "github.com/tidepool-org/platform/data/source/store/structured"
 	dataSourceStoreStructuredMongo "github.com/tidepool-org/platform/data/source/store/structured/mongo"
@@ -35,13 +39,16 @@ type Standard struct {
 	*service.DEPRECATEDService
 	metricClient              *metricClient.Client
 	permissionClient          *permissionClient.Client
+	clinicsClient             *clinics.CachingClient
 	dataDeduplicatorFactory   *dataDeduplicatorFactory.Factory
 	dataStore                 *dataStoreMongo.Store
 	dataSourceStructuredStore *dataSourceStoreStructuredMongo.Store
 	syncTaskStore             *syncTaskMongo.Store
 	dataClient                *Client
 	dataSourceClient          *dataSourceServiceClient.Client
+	dataSourceEventProducer   eventsCommon.EventProducer
 	userEventsHandler         events.Runner
+	cacheEventsHandler        events.Runner
 	api                       *api.Standard
 	server                    *server.Standard
 }
@@ -63,6 +70,9 @@ func (s *Standard) Initialize(provider application.Provider) error {
 	if err := s.initializePermissionClient(); err != nil {
 		return err
 	}
+	if err := s.initializeClinicsClient(); err != nil {
+		return err
+	}
 	if err := s.initializeDataDeduplicatorFactory(); err != nil {
 		return err
 	}
@@ -78,6 +88,9 @@ func (s *Standard) Initialize(provider application.Provider) error {
 	if err := s.initializeDataClient(); err != nil {
 		return err
 	}
+	if err := s.initializeDataSourceEventProducer(); err != nil {
+		return err
+	}
 	if err := s.initializeDataSourceClient(); err != nil {
 		return err
 	}
@@ -104,7 +117,15 @@ func (s *Standard) Terminate() {
 		}
 		s.userEventsHandler = nil
 	}
+	if s.cacheEventsHandler != nil {
+		s.Logger().Info("Terminating the cacheEventsHandler")
+		if err := s.cacheEventsHandler.Terminate(); err != nil {
+			s.Logger().Errorf("Error while terminating the cacheEventsHandler: %v", err)
+		}
+		s.cacheEventsHandler = nil
+	}
 	s.api = nil
+	s.dataSourceEventProducer = nil
 	s.dataClient = nil
 	if s.syncTaskStore != nil {
 		s.syncTaskStore.Terminate(context.Background())
@@ -119,6 +140,7 @@ func (s *Standard) Terminate() {
 		s.dataStore = nil
 	}
 	s.dataDeduplicatorFactory = nil
+	s.clinicsClient = nil
 	s.permissionClient = nil
 	s.metricClient = nil
 
@@ -134,6 +156,9 @@ func (s *Standard) Run() error {
 	go func() {
 		errs <- s.userEventsHandler.Run()
 	}()
+	go func() {
+		errs <- s.cacheEventsHandler.Run()
+	}()
 	go func() {
 		errs <- s.server.Serve()
 	}()
@@ -149,6 +174,10 @@ func (s *Standard) DataSourceStructuredStore() dataSourceStoreStructured.Store {
 	return s.dataSourceStructuredStore
 }
 
+func (s *Standard) DataSourceEventProducer() eventsCommon.EventProducer {
+	return s.dataSourceEventProducer
+}
+
 func (s *Standard) initializeMetricClient() error {
 	s.Logger().Debug("Loading metric client config")
 
@@ -172,7 +201,7 @@ func (s *Standard) initializeMetricClient() error {
 func (s *Standard) initializePermissionClient() error {
 	s.Logger().Debug("Loading permission client config")
 
-	cfg := platform.NewConfig()
+	cfg := permissionClient.NewConfig()
 	cfg.UserAgent = s.UserAgent()
 	if err := cfg.Load(s.ConfigReporter().WithScopes("permission", "client")); err != nil {
 		return errors.Wrap(err, "unable to load permission client config")
@@ -189,6 +218,28 @@ func (s *Standard) initializePermissionClient() error {
 	return nil
 }
 
+func (s *Standard) initializeClinicsClient() error {
+	s.Logger().Debug("Loading clinics client cache config")
+
+	cfg := cache.NewConfig()
+	if err := cfg.Load(s.ConfigReporter().WithScopes("clinics", "client", "cache")); err != nil {
+		return errors.Wrap(err, "unable to load clinics client cache config")
+	}
+
+	s.Logger().Debug("Creating clinics client")
+
+	clnt, err := clinics.NewClient(s.AuthClient())
+	if err != nil {
+		return errors.Wrap(err, "unable to create clinics client")
+	}
+	s.clinicsClient, err = clinics.NewCachingClient(clnt, cfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to create clinics caching client")
+	}
+
+	return nil
+}
+
 func (s *Standard) initializeDataDeduplicatorFactory() error {
 	s.Logger().Debug("Creating device deactivate hash deduplicator")
 
@@ -325,6 +376,18 @@ func (s *Standard) initializeDataClient() error {
 	return nil
 }
 
+func (s *Standard) initializeDataSourceEventProducer() error {
+	s.Logger().Debug("Creating data source event producer")
+
+	producer, err := events.NewProducer(dataSource.EventTopic)
+	if err != nil {
+		return errors.Wrap(err, "unable to create data source event producer")
+	}
+	s.dataSourceEventProducer = producer
+
+	return nil
+}
+
 func (s *Standard) initializeDataSourceClient() error {
 	s.Logger().Debug("Creating data client")
 
@@ -340,7 +403,7 @@ func (s *Standard) initializeDataSourceClient() error {
 func (s *Standard) initializeAPI() error {
 	s.Logger().Debug("Creating api")
 
-	newAPI, err := api.NewStandard(s, s.metricClient, s.permissionClient,
+	newAPI, err := api.NewStandard(s, s.metricClient, s.permissionClient, s.clinicsClient,
 		s.dataDeduplicatorFactory,
 		s.dataStore, s.syncTaskStore, s.dataClient, s.dataSourceClient)
 	if err != nil {
@@ -387,13 +450,28 @@ func (s *Standard) initializeUserEventsHandler() error {
 	sarama.Logger = log.New(os.Stdout, "SARAMA ", log.LstdFlags|log.Lshortfile)
 
 	ctx := logInternal.NewContextWithLogger(context.Background(), s.Logger())
-	handler := dataEvents.NewUserDataDeletionHandler(ctx, s.dataStore, s.dataSourceStructuredStore)
-	handlers := []eventsCommon.EventHandler{handler}
+	invalidators := []authEvents.UserCacheInvalidator{s.permissionClient, s.clinicsClient}
+	if invalidator, ok := s.AuthClient().(authEvents.UserCacheInvalidator); ok {
+		invalidators = append(invalidators, invalidator)
+	}
+	handlers := []eventsCommon.EventHandler{
+		dataEvents.NewUserDataDeletionHandler(ctx, s.dataStore, s.dataSourceStructuredStore),
+	}
 	runner := events.NewRunner(handlers)
 	if err := runner.Initialize(); err != nil {
 		return errors.Wrap(err, "unable to initialize user events handler runner")
 	}
 	s.userEventsHandler = runner
 
+	// Every replica caches independently, so each must receive every event
+	cacheHandlers := []eventsCommon.EventHandler{
+		authEvents.NewUserCacheInvalidationHandler(ctx, invalidators...),
+	}
+	cacheRunner := events.NewBroadcastRunner(cacheHandlers)
+	if err := cacheRunner.Initialize(); err != nil {
+		return errors.Wrap(err, "unable to initialize cache events handler runner")
+	}
+	s.cacheEventsHandler = cacheRunner
+
 	return nil
 }
@@ -0,0 +1,206 @@
+package share
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	dataTypesFactory "github.com/tidepool-org/platform/data/types/factory"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/user"
+)
+
+const (
+	TimeFormat = time.RFC3339Nano
+)
+
+type Accessor interface {
+	ListUserGrants(ctx context.Context, userID string, pagination *page.Pagination) (Grants, error)
+
+	// UpsertUserGrant creates the grant from the user to the grantee or replaces the types and time range of the
+	// existing grant
+	UpsertUserGrant(ctx context.Context, userID string, create *GrantCreate) (*Grant, error)
+
+	// GetUserGranteeGrant returns the grant from the user to the grantee, or nil if the user has not granted any
+	GetUserGranteeGrant(ctx context.Context, userID string, granteeUserID string) (*Grant, error)
+
+	GetGrant(ctx context.Context, id string) (*Grant, error)
+	DeleteGrant(ctx context.Context, id string) (bool, error)
+
+	// DeleteUserGrants deletes all grants from the user and all grants to the user as grantee
+	DeleteUserGrants(ctx context.Context, userID string) error
+}
+
+// GrantCreate limits the grantee to the data types, if specified, and to data within the time range, inclusive of the
+// start time and exclusive of the end time, if specified
+type GrantCreate struct {
+	GranteeUserID *string    `json:"granteeUserId,omitempty"`
+	Types         *[]string  `json:"types,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty"`
+	EndTime       *time.Time `json:"endTime,omitempty"`
+}
+
+func NewGrantCreate() *GrantCreate {
+	return &GrantCreate{}
+}
+
+func (g *GrantCreate) Parse(parser structure.ObjectParser) {
+	g.GranteeUserID = parser.String("granteeUserId")
+	g.Types = parser.StringArray("types")
+	g.StartTime = parser.Time("startTime", TimeFormat)
+	g.EndTime = parser.Time("endTime", TimeFormat)
+}
+
+func (g *GrantCreate) Validate(validator structure.Validator) {
+	validator.String("granteeUserId", g.GranteeUserID).Exists().Using(user.IDValidator)
+	validateTypesAndTimeRange(validator, g.Types, g.StartTime, g.EndTime)
+}
+
+// Grant is a read-only share of the user's data with the grantee. A grant both allows the grantee to read the data
+// within its scope and limits any broader view permission the grantee has to that scope.
+type Grant struct {
+	ID            string     `json:"id" bson:"id"`
+	UserID        string     `json:"userId" bson:"userId"`
+	GranteeUserID string     `json:"granteeUserId" bson:"granteeUserId"`
+	Types         *[]string  `json:"types,omitempty" bson:"types,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty" bson:"startTime,omitempty"`
+	EndTime       *time.Time `json:"endTime,omitempty" bson:"endTime,omitempty"`
+	CreatedTime   time.Time  `json:"createdTime" bson:"createdTime"`
+}
+
+func NewGrant(userID string, create *GrantCreate) (*Grant, error) {
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	return &Grant{
+		ID:            NewID(),
+		UserID:        userID,
+		GranteeUserID: *create.GranteeUserID,
+		Types:         create.Types,
+		StartTime:     create.StartTime,
+		EndTime:       create.EndTime,
+		CreatedTime:   time.Now(),
+	}, nil
+}
+
+func (g *Grant) Parse(parser structure.ObjectParser) {
+	if ptr := parser.String("id"); ptr != nil {
+		g.ID = *ptr
+	}
+	if ptr := parser.String("userId"); ptr != nil {
+		g.UserID = *ptr
+	}
+	if ptr := parser.String("granteeUserId"); ptr != nil {
+		g.GranteeUserID = *ptr
+	}
+	g.Types = parser.StringArray("types")
+	g.StartTime = parser.Time("startTime", TimeFormat)
+	g.EndTime = parser.Time("endTime", TimeFormat)
+	if ptr := parser.Time("createdTime", TimeFormat); ptr != nil {
+		g.CreatedTime = *ptr
+	}
+}
+
+func (g *Grant) Validate(validator structure.Validator) {
+	validator.String("id", &g.ID).Using(IDValidator)
+	validator.String("userId", &g.UserID).Using(user.IDValidator)
+	validator.String("granteeUserId", &g.GranteeUserID).Using(user.IDValidator).NotEqualTo(g.UserID)
+	validateTypesAndTimeRange(validator, g.Types, g.StartTime, g.EndTime)
+	validator.Time("createdTime", &g.CreatedTime).NotZero().BeforeNow(time.Second)
+}
+
+// AllowsType returns true if the grant is not limited by type or includes the type
+func (g *Grant) AllowsType(typ string) bool {
+	if g.Types == nil {
+		return true
+	}
+	for _, grantType := range *g.Types {
+		if grantType == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsTime returns true if the time is within the time range of the grant
+func (g *Grant) AllowsTime(tm time.Time) bool {
+	if g.StartTime != nil && tm.Before(*g.StartTime) {
+		return false
+	}
+	if g.EndTime != nil && !tm.Before(*g.EndTime) {
+		return false
+	}
+	return true
+}
+
+// HasTimeRange returns true if the grant is limited by time
+func (g *Grant) HasTimeRange() bool {
+	return g.StartTime != nil || g.EndTime != nil
+}
+
+type Grants []*Grant
+
+func (g *Grants) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		grant := &Grant{}
+		if objectParser := parser.WithReferenceObjectParser(reference); objectParser.Exists() {
+			grant.Parse(objectParser)
+		}
+		*g = append(*g, grant)
+	}
+}
+
+func (g *Grants) Validate(validator structure.Validator) {
+	for index, grant := range *g {
+		if grantValidator := validator.WithReference(strconv.Itoa(index)); grant != nil {
+			grant.Validate(grantValidator)
+		} else {
+			grantValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+func validateTypesAndTimeRange(validator structure.Validator, types *[]string, startTime *time.Time, endTime *time.Time) {
+	validator.StringArray("types", types).NotEmpty().EachOneOf(dataTypesFactory.Types()...).EachUnique()
+	if startTime != nil {
+		validator.Time("endTime", endTime).After(*startTime)
+	}
+}
+
+func NewID() string {
+	return id.Must(id.New(16))
+}
+
+func IsValidID(value string) bool {
+	return ValidateID(value) == nil
+}
+
+func IDValidator(value string, errorReporter structure.ErrorReporter) {
+	errorReporter.ReportError(ValidateID(value))
+}
+
+func ValidateID(value string) error {
+	if value == "" {
+		return structureValidator.ErrorValueEmpty()
+	} else if !idExpression.MatchString(value) {
+		return ErrorValueStringAsIDNotValid(value)
+	}
+	return nil
+}
+
+func ErrorValueStringAsIDNotValid(value string) error {
+	return errors.Preparedf(structureValidator.ErrorCodeValueNotValid, "value is not valid", "value %q is not valid as share grant id", value)
+}
+
+var idExpression = regexp.MustCompile("^[0-9a-z]{32}$")
@@ -0,0 +1,159 @@
+package share_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dataShare "github.com/tidepool-org/platform/data/share"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	userTest "github.com/tidepool-org/platform/user/test"
+)
+
+var _ = Describe("Share", func() {
+	var userID string
+	var create *dataShare.GrantCreate
+
+	BeforeEach(func() {
+		userID = userTest.RandomID()
+		create = dataShare.NewGrantCreate()
+		create.GranteeUserID = pointer.FromString(userTest.RandomID())
+	})
+
+	Context("GrantCreate", func() {
+		It("is invalid if the grantee user id is missing", func() {
+			create.GranteeUserID = nil
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("is invalid if the types are empty", func() {
+			create.Types = pointer.FromStringArray([]string{})
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("is invalid if a type is unknown", func() {
+			create.Types = pointer.FromStringArray([]string{"cbg", "unknown"})
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("is invalid if the end time is not after the start time", func() {
+			create.StartTime = pointer.FromTime(time.Now())
+			create.EndTime = pointer.FromTime(create.StartTime.Add(-time.Hour))
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("is valid", func() {
+			create.Types = pointer.FromStringArray([]string{"cbg", "smbg"})
+			create.StartTime = pointer.FromTime(time.Now().Add(-time.Hour))
+			create.EndTime = pointer.FromTime(time.Now())
+			Expect(structureValidator.New().Validate(create)).To(Succeed())
+		})
+	})
+
+	Context("NewGrant", func() {
+		It("returns an error if the user id is missing", func() {
+			grant, err := dataShare.NewGrant("", create)
+			Expect(err).To(MatchError("user id is missing"))
+			Expect(grant).To(BeNil())
+		})
+
+		It("returns an error if the create is missing", func() {
+			grant, err := dataShare.NewGrant(userID, nil)
+			Expect(err).To(MatchError("create is missing"))
+			Expect(grant).To(BeNil())
+		})
+
+		It("returns a valid grant", func() {
+			create.Types = pointer.FromStringArray([]string{"cbg"})
+			grant, err := dataShare.NewGrant(userID, create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(grant).ToNot(BeNil())
+			Expect(dataShare.IsValidID(grant.ID)).To(BeTrue())
+			Expect(grant.UserID).To(Equal(userID))
+			Expect(grant.GranteeUserID).To(Equal(*create.GranteeUserID))
+			Expect(grant.Types).To(Equal(create.Types))
+			Expect(structureValidator.New().Validate(grant)).To(Succeed())
+		})
+
+		It("returns an invalid grant if the grantee is the user", func() {
+			create.GranteeUserID = pointer.FromString(userID)
+			grant, err := dataShare.NewGrant(userID, create)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(structureValidator.New().Validate(grant)).To(HaveOccurred())
+		})
+	})
+
+	Context("with grant", func() {
+		var grant *dataShare.Grant
+
+		BeforeEach(func() {
+			var err error
+			grant, err = dataShare.NewGrant(userID, create)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("AllowsType", func() {
+			It("returns true if the grant is not limited by type", func() {
+				Expect(grant.AllowsType("basal")).To(BeTrue())
+			})
+
+			It("returns true if the grant includes the type", func() {
+				grant.Types = pointer.FromStringArray([]string{"cbg", "smbg"})
+				Expect(grant.AllowsType("smbg")).To(BeTrue())
+			})
+
+			It("returns false if the grant does not include the type", func() {
+				grant.Types = pointer.FromStringArray([]string{"cbg", "smbg"})
+				Expect(grant.AllowsType("basal")).To(BeFalse())
+			})
+		})
+
+		Context("AllowsTime", func() {
+			var startTime time.Time
+			var endTime time.Time
+
+			BeforeEach(func() {
+				startTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+				endTime = startTime.AddDate(0, 1, 0)
+			})
+
+			It("returns true if the grant is not limited by time", func() {
+				Expect(grant.HasTimeRange()).To(BeFalse())
+				Expect(grant.AllowsTime(startTime)).To(BeTrue())
+			})
+
+			It("returns true if the time is the start time", func() {
+				grant.StartTime = pointer.FromTime(startTime)
+				grant.EndTime = pointer.FromTime(endTime)
+				Expect(grant.HasTimeRange()).To(BeTrue())
+				Expect(grant.AllowsTime(startTime)).To(BeTrue())
+			})
+
+			It("returns false if the time is before the start time", func() {
+				grant.StartTime = pointer.FromTime(startTime)
+				Expect(grant.AllowsTime(startTime.Add(-time.Second))).To(BeFalse())
+			})
+
+			It("returns false if the time is the end time", func() {
+				grant.EndTime = pointer.FromTime(endTime)
+				Expect(grant.AllowsTime(endTime)).To(BeFalse())
+			})
+		})
+	})
+
+	Context("ValidateID", func() {
+		It("returns an error if the id is empty", func() {
+			Expect(dataShare.ValidateID("")).To(MatchError("value is empty"))
+		})
+
+		It("returns an error if the id is invalid", func() {
+			Expect(dataShare.ValidateID("invalid")).To(MatchError(`value "invalid" is not valid as share grant id`))
+		})
+
+		It("returns successfully if the id is valid", func() {
+			Expect(dataShare.ValidateID(dataShare.NewID())).To(Succeed())
+		})
+	})
+})
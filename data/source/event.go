@@ -0,0 +1,66 @@
+package source
+
+import (
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+)
+
+const (
+	EventTopic            = "data-sources"
+	StateChangedEventType = "data-sources:state-changed"
+)
+
+// StateChangedEvent is emitted whenever the state of a data source changes so that clients can, for example, prompt
+// the user to reconnect
+type StateChangedEvent struct {
+	ID            string               `json:"id"`
+	UserID        string               `json:"userId"`
+	ProviderType  string               `json:"providerType"`
+	ProviderName  string               `json:"providerName"`
+	PreviousState string               `json:"previousState,omitempty"`
+	State         string               `json:"state"`
+	Error         *errors.Serializable `json:"error,omitempty"`
+	Time          time.Time            `json:"time"`
+}
+
+// NewStateChangedEvent returns the event if the state of the data source changed between the previous and current
+// source, otherwise nil
+func NewStateChangedEvent(previous *Source, current *Source) *StateChangedEvent {
+	if current == nil || current.State == nil {
+		return nil
+	}
+
+	var previousState string
+	if previous != nil {
+		previousState = pointer.ToString(previous.State)
+	}
+	if previousState == *current.State {
+		return nil
+	}
+
+	tm := time.Now()
+	if current.ModifiedTime != nil {
+		tm = *current.ModifiedTime
+	}
+
+	return &StateChangedEvent{
+		ID:            pointer.ToString(current.ID),
+		UserID:        pointer.ToString(current.UserID),
+		ProviderType:  pointer.ToString(current.ProviderType),
+		ProviderName:  pointer.ToString(current.ProviderName),
+		PreviousState: previousState,
+		State:         *current.State,
+		Error:         current.Error,
+		Time:          tm,
+	}
+}
+
+func (s StateChangedEvent) GetEventType() string {
+	return StateChangedEventType
+}
+
+func (s StateChangedEvent) GetEventKey() string {
+	return s.UserID
+}
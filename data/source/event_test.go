@@ -0,0 +1,49 @@
+package source_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
+	"github.com/tidepool-org/platform/pointer"
+)
+
+var _ = Describe("Event", func() {
+	Context("NewStateChangedEvent", func() {
+		var previous *dataSource.Source
+		var current *dataSource.Source
+
+		BeforeEach(func() {
+			previous = dataSourceTest.RandomSource()
+			previous.State = pointer.FromString(dataSource.StateConnected)
+			current = dataSourceTest.CloneSource(previous)
+			current.State = pointer.FromString(dataSource.StateError)
+		})
+
+		It("returns nil when the current source is missing", func() {
+			Expect(dataSource.NewStateChangedEvent(previous, nil)).To(BeNil())
+		})
+
+		It("returns nil when the state did not change", func() {
+			current.State = pointer.FromString(dataSource.StateConnected)
+			Expect(dataSource.NewStateChangedEvent(previous, current)).To(BeNil())
+		})
+
+		It("returns the event when the state changed", func() {
+			event := dataSource.NewStateChangedEvent(previous, current)
+			Expect(event).To(Equal(&dataSource.StateChangedEvent{
+				ID:            *current.ID,
+				UserID:        *current.UserID,
+				ProviderType:  *current.ProviderType,
+				ProviderName:  *current.ProviderName,
+				PreviousState: dataSource.StateConnected,
+				State:         dataSource.StateError,
+				Error:         current.Error,
+				Time:          *current.ModifiedTime,
+			}))
+			Expect(event.GetEventType()).To(Equal("data-sources:state-changed"))
+			Expect(event.GetEventKey()).To(Equal(*current.UserID))
+		})
+	})
+})
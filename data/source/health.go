@@ -0,0 +1,103 @@
+package source
+
+import (
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+)
+
+const (
+	HealthAuthenticationFailureCountMaximumDefault = 3
+	HealthFailureDurationMaximumDefault            = 30 * 24 * time.Hour
+)
+
+// HealthPolicy determines how a data source is updated after each import. Each failure is added to the failure history
+// and counted. After too many consecutive authentication failures the data source moves to the error state, and after
+// failing to import for too long the data source is disconnected. Either way, the user must reconnect.
+type HealthPolicy struct {
+	authenticationFailureCountMaximum int
+	failureDurationMaximum            time.Duration
+}
+
+func NewHealthPolicy(authenticationFailureCountMaximum int, failureDurationMaximum time.Duration) (*HealthPolicy, error) {
+	if authenticationFailureCountMaximum < 1 {
+		return nil, errors.New("authentication failure count maximum is invalid")
+	}
+	if failureDurationMaximum <= 0 {
+		return nil, errors.New("failure duration maximum is invalid")
+	}
+
+	return &HealthPolicy{
+		authenticationFailureCountMaximum: authenticationFailureCountMaximum,
+		failureDurationMaximum:            failureDurationMaximum,
+	}, nil
+}
+
+func (h *HealthPolicy) AuthenticationFailureCountMaximum() int {
+	return h.authenticationFailureCountMaximum
+}
+
+func (h *HealthPolicy) FailureDurationMaximum() time.Duration {
+	return h.failureDurationMaximum
+}
+
+// SuccessUpdate returns the update after a successful import. If the data source was failing, then it is reconnected,
+// which also resets the consecutive failure counts.
+func (h *HealthPolicy) SuccessUpdate(source *Source, now time.Time) *Update {
+	update := NewUpdate()
+	update.LastImportTime = pointer.FromTime(now)
+	if source != nil && (pointer.ToInt(source.ConsecutiveFailureCount) > 0 || source.FirstConsecutiveFailureTime != nil) {
+		update.State = pointer.FromString(StateConnected)
+	}
+	return update
+}
+
+// FailureUpdate returns the update after a failed import. If the update includes a new state, then the data source
+// requires the user to reconnect and no further imports should be attempted.
+func (h *HealthPolicy) FailureUpdate(source *Source, err error, authentication bool, now time.Time) *Update {
+	failureHistory := FailureArray{}
+	if source != nil && source.FailureHistory != nil {
+		failureHistory = append(failureHistory, *source.FailureHistory...)
+	}
+	failureHistory = append(failureHistory, &Failure{
+		Time:           pointer.FromTime(now),
+		Error:          errors.NewSerializable(err),
+		Authentication: pointer.FromBool(authentication),
+	})
+	if length := len(failureHistory); length > FailureArrayLengthMaximum {
+		failureHistory = failureHistory[length-FailureArrayLengthMaximum:]
+	}
+
+	consecutiveFailureCount := 1
+	consecutiveAuthenticationFailureCount := 0
+	firstConsecutiveFailureTime := now
+	if source != nil {
+		consecutiveFailureCount += pointer.ToInt(source.ConsecutiveFailureCount)
+		if authentication {
+			consecutiveAuthenticationFailureCount = pointer.ToInt(source.ConsecutiveAuthenticationFailureCount)
+		}
+		if source.FirstConsecutiveFailureTime != nil {
+			firstConsecutiveFailureTime = *source.FirstConsecutiveFailureTime
+		}
+	}
+	if authentication {
+		consecutiveAuthenticationFailureCount++
+	}
+
+	update := NewUpdate()
+	update.FailureHistory = &failureHistory
+	update.ConsecutiveFailureCount = pointer.FromInt(consecutiveFailureCount)
+	update.ConsecutiveAuthenticationFailureCount = pointer.FromInt(consecutiveAuthenticationFailureCount)
+	update.FirstConsecutiveFailureTime = pointer.FromTime(firstConsecutiveFailureTime)
+
+	if consecutiveAuthenticationFailureCount >= h.authenticationFailureCountMaximum {
+		update.State = pointer.FromString(StateError)
+		update.Error = errors.NewSerializable(err)
+	} else if now.Sub(firstConsecutiveFailureTime) >= h.failureDurationMaximum {
+		update.State = pointer.FromString(StateDisconnected)
+		update.Error = errors.NewSerializable(err)
+	}
+
+	return update
+}
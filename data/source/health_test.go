@@ -0,0 +1,138 @@
+package source_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+)
+
+var _ = Describe("Health", func() {
+	Context("NewHealthPolicy", func() {
+		It("returns an error when the authentication failure count maximum is invalid", func() {
+			healthPolicy, err := dataSource.NewHealthPolicy(0, time.Hour)
+			errorsTest.ExpectEqual(err, errors.New("authentication failure count maximum is invalid"))
+			Expect(healthPolicy).To(BeNil())
+		})
+
+		It("returns an error when the failure duration maximum is invalid", func() {
+			healthPolicy, err := dataSource.NewHealthPolicy(3, 0)
+			errorsTest.ExpectEqual(err, errors.New("failure duration maximum is invalid"))
+			Expect(healthPolicy).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			healthPolicy, err := dataSource.NewHealthPolicy(3, time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(healthPolicy).ToNot(BeNil())
+			Expect(healthPolicy.AuthenticationFailureCountMaximum()).To(Equal(3))
+			Expect(healthPolicy.FailureDurationMaximum()).To(Equal(time.Hour))
+		})
+	})
+
+	Context("with new health policy", func() {
+		var healthPolicy *dataSource.HealthPolicy
+		var source *dataSource.Source
+		var now time.Time
+
+		BeforeEach(func() {
+			var err error
+			healthPolicy, err = dataSource.NewHealthPolicy(3, 24*time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+			source = dataSourceTest.RandomSource()
+			source.State = pointer.FromString(dataSource.StateConnected)
+			source.Error = nil
+			source.FailureHistory = nil
+			source.ConsecutiveFailureCount = nil
+			source.ConsecutiveAuthenticationFailureCount = nil
+			source.FirstConsecutiveFailureTime = nil
+			now = time.Now()
+		})
+
+		Context("SuccessUpdate", func() {
+			It("returns only the last import time when the source is not failing", func() {
+				update := healthPolicy.SuccessUpdate(source, now)
+				Expect(update).To(Equal(&dataSource.Update{LastImportTime: pointer.FromTime(now)}))
+			})
+
+			It("returns the connected state when the source is failing", func() {
+				source.ConsecutiveFailureCount = pointer.FromInt(2)
+				source.FirstConsecutiveFailureTime = pointer.FromTime(now.Add(-time.Hour))
+				update := healthPolicy.SuccessUpdate(source, now)
+				Expect(update).To(Equal(&dataSource.Update{State: pointer.FromString(dataSource.StateConnected), LastImportTime: pointer.FromTime(now)}))
+			})
+		})
+
+		Context("FailureUpdate", func() {
+			var err error
+
+			BeforeEach(func() {
+				err = errorsTest.RandomError()
+			})
+
+			It("records the first failure", func() {
+				update := healthPolicy.FailureUpdate(source, err, false, now)
+				Expect(update.State).To(BeNil())
+				Expect(update.Error).To(BeNil())
+				Expect(update.FailureHistory).To(Equal(&dataSource.FailureArray{{Time: pointer.FromTime(now), Error: errors.NewSerializable(err), Authentication: pointer.FromBool(false)}}))
+				Expect(update.ConsecutiveFailureCount).To(Equal(pointer.FromInt(1)))
+				Expect(update.ConsecutiveAuthenticationFailureCount).To(Equal(pointer.FromInt(0)))
+				Expect(update.FirstConsecutiveFailureTime).To(Equal(pointer.FromTime(now)))
+			})
+
+			It("increments the counts and retains the first consecutive failure time", func() {
+				firstConsecutiveFailureTime := now.Add(-time.Hour)
+				source.ConsecutiveFailureCount = pointer.FromInt(4)
+				source.ConsecutiveAuthenticationFailureCount = pointer.FromInt(1)
+				source.FirstConsecutiveFailureTime = pointer.FromTime(firstConsecutiveFailureTime)
+				update := healthPolicy.FailureUpdate(source, request.ErrorUnauthenticated(), true, now)
+				Expect(update.State).To(BeNil())
+				Expect(update.ConsecutiveFailureCount).To(Equal(pointer.FromInt(5)))
+				Expect(update.ConsecutiveAuthenticationFailureCount).To(Equal(pointer.FromInt(2)))
+				Expect(update.FirstConsecutiveFailureTime).To(Equal(pointer.FromTime(firstConsecutiveFailureTime)))
+			})
+
+			It("resets the consecutive authentication failure count after a different failure", func() {
+				source.ConsecutiveFailureCount = pointer.FromInt(2)
+				source.ConsecutiveAuthenticationFailureCount = pointer.FromInt(2)
+				source.FirstConsecutiveFailureTime = pointer.FromTime(now.Add(-time.Hour))
+				update := healthPolicy.FailureUpdate(source, err, false, now)
+				Expect(update.State).To(BeNil())
+				Expect(update.ConsecutiveAuthenticationFailureCount).To(Equal(pointer.FromInt(0)))
+			})
+
+			It("retains only the most recent failures", func() {
+				source.FailureHistory = dataSourceTest.RandomFailureArray(dataSource.FailureArrayLengthMaximum, dataSource.FailureArrayLengthMaximum)
+				update := healthPolicy.FailureUpdate(source, err, false, now)
+				Expect(*update.FailureHistory).To(HaveLen(dataSource.FailureArrayLengthMaximum))
+				Expect((*update.FailureHistory)[0]).To(Equal((*source.FailureHistory)[1]))
+				Expect((*update.FailureHistory)[dataSource.FailureArrayLengthMaximum-1].Error).To(Equal(errors.NewSerializable(err)))
+			})
+
+			It("returns the error state after the maximum consecutive authentication failures", func() {
+				err = request.ErrorUnauthenticated()
+				source.ConsecutiveFailureCount = pointer.FromInt(2)
+				source.ConsecutiveAuthenticationFailureCount = pointer.FromInt(2)
+				source.FirstConsecutiveFailureTime = pointer.FromTime(now.Add(-time.Hour))
+				update := healthPolicy.FailureUpdate(source, err, true, now)
+				Expect(update.State).To(Equal(pointer.FromString(dataSource.StateError)))
+				Expect(update.Error).To(Equal(errors.NewSerializable(err)))
+			})
+
+			It("returns the disconnected state after failing for the maximum duration", func() {
+				source.ConsecutiveFailureCount = pointer.FromInt(24)
+				source.FirstConsecutiveFailureTime = pointer.FromTime(now.Add(-24 * time.Hour))
+				update := healthPolicy.FailureUpdate(source, err, false, now)
+				Expect(update.State).To(Equal(pointer.FromString(dataSource.StateDisconnected)))
+				Expect(update.Error).To(Equal(errors.NewSerializable(err)))
+			})
+		})
+	})
+})
@@ -3,10 +3,13 @@ package client
 import (
 	"context"
 
+	ev "github.com/tidepool-org/go-common/events"
+
 	"github.com/tidepool-org/platform/auth"
 	dataSource "github.com/tidepool-org/platform/data/source"
 	dataSourceStoreStructured "github.com/tidepool-org/platform/data/source/store/structured"
 	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
 	"github.com/tidepool-org/platform/page"
 	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/request"
@@ -15,6 +18,7 @@ import (
 type Provider interface {
 	AuthClient() auth.Client
 	DataSourceStructuredStore() dataSourceStoreStructured.Store
+	DataSourceEventProducer() ev.EventProducer
 }
 
 type Client struct {
@@ -85,7 +89,29 @@ func (c *Client) Update(ctx context.Context, id string, condition *request.Condi
 	}
 
 	repository := c.DataSourceStructuredStore().NewDataSourcesRepository()
-	return repository.Update(ctx, id, condition, update)
+
+	var previous *dataSource.Source
+	if update != nil && update.State != nil {
+		var err error
+		if previous, err = repository.Get(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := repository.Update(ctx, id, condition, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != nil {
+		if event := dataSource.NewStateChangedEvent(previous, result); event != nil {
+			if err = c.DataSourceEventProducer().Send(ctx, *event); err != nil {
+				log.LoggerFromContext(ctx).WithError(err).WithField("event", event).Error("Unable to send data source state changed event")
+			}
+		}
+	}
+
+	return result, nil
 }
 
 func (c *Client) Delete(ctx context.Context, id string, condition *request.Condition) (bool, error) {
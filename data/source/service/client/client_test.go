@@ -6,6 +6,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	ev "github.com/tidepool-org/go-common/events"
+
 	"github.com/tidepool-org/platform/auth"
 	authTest "github.com/tidepool-org/platform/auth/test"
 	dataSource "github.com/tidepool-org/platform/data/source"
@@ -16,11 +18,13 @@ import (
 	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
 	"github.com/tidepool-org/platform/errors"
 	errorsTest "github.com/tidepool-org/platform/errors/test"
+	eventsTest "github.com/tidepool-org/platform/events/test"
 	"github.com/tidepool-org/platform/log"
 	logTest "github.com/tidepool-org/platform/log/test"
 	"github.com/tidepool-org/platform/page"
 	pageTest "github.com/tidepool-org/platform/page/test"
 	"github.com/tidepool-org/platform/permission"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/request"
 	requestTest "github.com/tidepool-org/platform/request/test"
 	userTest "github.com/tidepool-org/platform/user/test"
@@ -311,24 +315,108 @@ var _ = Describe("Client", func() {
 						authClient.EnsureAuthorizedServiceOutputs = []error{nil}
 					})
 
-					AfterEach(func() {
-						Expect(dataSourceStructuredRepository.UpdateInputs).To(Equal([]dataSourceStoreStructuredTest.UpdateInput{{ID: id, Condition: condition, Update: update}}))
-					})
+					When("the update does not include a state", func() {
+						BeforeEach(func() {
+							update.State = nil
+						})
 
-					It("returns an error when the data source structured repository update returns an error", func() {
-						responseErr := errorsTest.RandomError()
-						dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: nil, Error: responseErr}}
-						result, err := client.Update(ctx, id, condition, update)
-						errorsTest.ExpectEqual(err, responseErr)
-						Expect(result).To(BeNil())
+						AfterEach(func() {
+							Expect(dataSourceStructuredRepository.GetInputs).To(BeEmpty())
+							Expect(dataSourceStructuredRepository.UpdateInputs).To(Equal([]dataSourceStoreStructuredTest.UpdateInput{{ID: id, Condition: condition, Update: update}}))
+						})
+
+						It("returns an error when the data source structured repository update returns an error", func() {
+							responseErr := errorsTest.RandomError()
+							dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: nil, Error: responseErr}}
+							result, err := client.Update(ctx, id, condition, update)
+							errorsTest.ExpectEqual(err, responseErr)
+							Expect(result).To(BeNil())
+						})
+
+						It("returns successfully when the data source structured repository update returns successfully", func() {
+							responseResult := dataSourceTest.RandomSource()
+							dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: responseResult, Error: nil}}
+							result, err := client.Update(ctx, id, condition, update)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(result).To(Equal(responseResult))
+						})
 					})
 
-					It("returns successfully when the data source structured repository update returns successfully", func() {
-						responseResult := dataSourceTest.RandomSource()
-						dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: responseResult, Error: nil}}
-						result, err := client.Update(ctx, id, condition, update)
-						Expect(err).ToNot(HaveOccurred())
-						Expect(result).To(Equal(responseResult))
+					When("the update includes a state", func() {
+						var previousResult *dataSource.Source
+
+						BeforeEach(func() {
+							update.State = pointer.FromString(dataSource.StateError)
+							previousResult = dataSourceTest.RandomSource()
+							previousResult.State = pointer.FromString(dataSource.StateConnected)
+						})
+
+						AfterEach(func() {
+							Expect(dataSourceStructuredRepository.GetInputs).To(Equal([]string{id}))
+						})
+
+						It("returns an error when the data source structured repository get returns an error", func() {
+							responseErr := errorsTest.RandomError()
+							dataSourceStructuredRepository.GetOutputs = []dataSourceStoreStructuredTest.GetOutput{{Source: nil, Error: responseErr}}
+							result, err := client.Update(ctx, id, condition, update)
+							errorsTest.ExpectEqual(err, responseErr)
+							Expect(result).To(BeNil())
+						})
+
+						When("the data source structured repository get returns successfully", func() {
+							var eventProducer *eventsTest.EventProducer
+
+							BeforeEach(func() {
+								dataSourceStructuredRepository.GetOutputs = []dataSourceStoreStructuredTest.GetOutput{{Source: previousResult, Error: nil}}
+								eventProducer = eventsTest.NewEventProducer()
+								provider.DataSourceEventProducerOutput = func(e ev.EventProducer) *ev.EventProducer { return &e }(eventProducer)
+							})
+
+							AfterEach(func() {
+								Expect(dataSourceStructuredRepository.UpdateInputs).To(Equal([]dataSourceStoreStructuredTest.UpdateInput{{ID: id, Condition: condition, Update: update}}))
+								eventProducer.AssertOutputsEmpty()
+							})
+
+							It("returns an error when the data source structured repository update returns an error", func() {
+								responseErr := errorsTest.RandomError()
+								dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: nil, Error: responseErr}}
+								result, err := client.Update(ctx, id, condition, update)
+								errorsTest.ExpectEqual(err, responseErr)
+								Expect(result).To(BeNil())
+								Expect(eventProducer.SendInputs).To(BeEmpty())
+							})
+
+							It("does not send an event when the state did not change", func() {
+								responseResult := dataSourceTest.CloneSource(previousResult)
+								dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: responseResult, Error: nil}}
+								result, err := client.Update(ctx, id, condition, update)
+								Expect(err).ToNot(HaveOccurred())
+								Expect(result).To(Equal(responseResult))
+								Expect(eventProducer.SendInputs).To(BeEmpty())
+							})
+
+							It("sends an event when the state changed", func() {
+								responseResult := dataSourceTest.CloneSource(previousResult)
+								responseResult.State = pointer.FromString(dataSource.StateError)
+								dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: responseResult, Error: nil}}
+								eventProducer.SendOutputs = []error{nil}
+								result, err := client.Update(ctx, id, condition, update)
+								Expect(err).ToNot(HaveOccurred())
+								Expect(result).To(Equal(responseResult))
+								Expect(eventProducer.SendInputs).To(Equal([]eventsTest.SendInput{{Context: ctx, Event: *dataSource.NewStateChangedEvent(previousResult, responseResult)}}))
+							})
+
+							It("returns successfully even if sending the event returns an error", func() {
+								responseResult := dataSourceTest.CloneSource(previousResult)
+								responseResult.State = pointer.FromString(dataSource.StateDisconnected)
+								dataSourceStructuredRepository.UpdateOutputs = []dataSourceStoreStructuredTest.UpdateOutput{{Source: responseResult, Error: nil}}
+								eventProducer.SendOutputs = []error{errorsTest.RandomError()}
+								result, err := client.Update(ctx, id, condition, update)
+								Expect(err).ToNot(HaveOccurred())
+								Expect(result).To(Equal(responseResult))
+								Expect(eventProducer.SendInputs).To(HaveLen(1))
+							})
+						})
 					})
 				})
 			})
@@ -1,6 +1,8 @@
 package test
 
 import (
+	ev "github.com/tidepool-org/go-common/events"
+
 	"github.com/tidepool-org/platform/auth"
 	dataSourceStoreStructured "github.com/tidepool-org/platform/data/source/store/structured"
 )
@@ -14,6 +16,10 @@ type Provider struct {
 	DataSourceStructuredStoreStub        func() dataSourceStoreStructured.Store
 	DataSourceStructuredStoreOutputs     []dataSourceStoreStructured.Store
 	DataSourceStructuredStoreOutput      *dataSourceStoreStructured.Store
+	DataSourceEventProducerInvocations   int
+	DataSourceEventProducerStub          func() ev.EventProducer
+	DataSourceEventProducerOutputs       []ev.EventProducer
+	DataSourceEventProducerOutput        *ev.EventProducer
 }
 
 func NewProvider() *Provider {
@@ -52,6 +58,22 @@ func (p *Provider) DataSourceStructuredStore() dataSourceStoreStructured.Store {
 	panic("DataSourceStructuredStore has no output")
 }
 
+func (p *Provider) DataSourceEventProducer() ev.EventProducer {
+	p.DataSourceEventProducerInvocations++
+	if p.DataSourceEventProducerStub != nil {
+		return p.DataSourceEventProducerStub()
+	}
+	if len(p.DataSourceEventProducerOutputs) > 0 {
+		output := p.DataSourceEventProducerOutputs[0]
+		p.DataSourceEventProducerOutputs = p.DataSourceEventProducerOutputs[1:]
+		return output
+	}
+	if p.DataSourceEventProducerOutput != nil {
+		return *p.DataSourceEventProducerOutput
+	}
+	panic("DataSourceEventProducer has no output")
+}
+
 func (p *Provider) AssertOutputsEmpty() {
 	if len(p.AuthClientOutputs) > 0 {
 		panic("AuthClientOutputs is not empty")
@@ -59,4 +81,7 @@ func (p *Provider) AssertOutputsEmpty() {
 	if len(p.DataSourceStructuredStoreOutputs) > 0 {
 		panic("DataSourceStructuredStoreOutputs is not empty")
 	}
+	if len(p.DataSourceEventProducerOutputs) > 0 {
+		panic("DataSourceEventProducerOutputs is not empty")
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/tidepool-org/platform/auth"
@@ -19,6 +20,8 @@ import (
 )
 
 const (
+	FailureArrayLengthMaximum = 10
+
 	StateConnected    = "connected"
 	StateDisconnected = "disconnected"
 	StateError        = "error"
@@ -110,13 +113,18 @@ func (c *Create) Validate(validator structure.Validator) {
 }
 
 type Update struct {
-	ProviderSessionID *string              `json:"providerSessionId,omitempty"`
-	State             *string              `json:"state,omitempty"`
-	Error             *errors.Serializable `json:"error,omitempty"`
-	DataSetIDs        *[]string            `json:"dataSetIds,omitempty"`
-	EarliestDataTime  *time.Time           `json:"earliestDataTime,omitempty"`
-	LatestDataTime    *time.Time           `json:"latestDataTime,omitempty"`
-	LastImportTime    *time.Time           `json:"lastImportTime,omitempty"`
+	ProviderSessionID                     *string              `json:"providerSessionId,omitempty"`
+	State                                 *string              `json:"state,omitempty"`
+	Error                                 *errors.Serializable `json:"error,omitempty"`
+	DataSetIDs                            *[]string            `json:"dataSetIds,omitempty"`
+	EarliestDataTime                      *time.Time           `json:"earliestDataTime,omitempty"`
+	LatestDataTime                        *time.Time           `json:"latestDataTime,omitempty"`
+	LastImportTime                        *time.Time           `json:"lastImportTime,omitempty"`
+	FailureHistory                        *FailureArray        `json:"failureHistory,omitempty"`
+	ConsecutiveFailureCount               *int                 `json:"consecutiveFailureCount,omitempty"`
+	ConsecutiveAuthenticationFailureCount *int                 `json:"consecutiveAuthenticationFailureCount,omitempty"`
+	FirstConsecutiveFailureTime           *time.Time           `json:"firstConsecutiveFailureTime,omitempty"`
+	SyncHistory                           *SyncArray           `json:"syncHistory,omitempty"`
 }
 
 func NewUpdate() *Update {
@@ -137,6 +145,11 @@ func (u *Update) Parse(parser structure.ObjectParser) {
 	u.EarliestDataTime = parser.Time("earliestDataTime", time.RFC3339Nano)
 	u.LatestDataTime = parser.Time("latestDataTime", time.RFC3339Nano)
 	u.LastImportTime = parser.Time("lastImportTime", time.RFC3339Nano)
+	u.FailureHistory = ParseFailureArray(parser.WithReferenceArrayParser("failureHistory"))
+	u.ConsecutiveFailureCount = parser.Int("consecutiveFailureCount")
+	u.ConsecutiveAuthenticationFailureCount = parser.Int("consecutiveAuthenticationFailureCount")
+	u.FirstConsecutiveFailureTime = parser.Time("firstConsecutiveFailureTime", time.RFC3339Nano)
+	u.SyncHistory = ParseSyncArray(parser.WithReferenceArrayParser("syncHistory"))
 }
 
 func (u *Update) Validate(validator structure.Validator) {
@@ -153,33 +166,55 @@ func (u *Update) Validate(validator structure.Validator) {
 	validator.Time("earliestDataTime", u.EarliestDataTime).NotZero().BeforeNow(time.Second)
 	validator.Time("latestDataTime", u.LatestDataTime).NotZero().After(pointer.ToTime(u.EarliestDataTime)).BeforeNow(time.Second)
 	validator.Time("lastImportTime", u.LastImportTime).NotZero().BeforeNow(time.Second)
+	if u.FailureHistory != nil {
+		u.FailureHistory.Validate(validator.WithReference("failureHistory"))
+	}
+	validator.Int("consecutiveFailureCount", u.ConsecutiveFailureCount).GreaterThanOrEqualTo(0)
+	validator.Int("consecutiveAuthenticationFailureCount", u.ConsecutiveAuthenticationFailureCount).GreaterThanOrEqualTo(0)
+	validator.Time("firstConsecutiveFailureTime", u.FirstConsecutiveFailureTime).NotZero().BeforeNow(time.Second)
+	if u.SyncHistory != nil {
+		u.SyncHistory.Validate(validator.WithReference("syncHistory"))
+	}
 }
 
 func (u *Update) Normalize(normalizer structure.Normalizer) {
 	if u.Error != nil {
 		u.Error.Normalize(normalizer.WithReference("error"))
 	}
+	if u.FailureHistory != nil {
+		u.FailureHistory.Normalize(normalizer.WithReference("failureHistory"))
+	}
+	if u.SyncHistory != nil {
+		u.SyncHistory.Normalize(normalizer.WithReference("syncHistory"))
+	}
 }
 
 func (u *Update) IsEmpty() bool {
-	return u.ProviderSessionID == nil && u.State == nil && u.Error == nil && u.DataSetIDs == nil && u.EarliestDataTime == nil && u.LatestDataTime == nil && u.LastImportTime == nil
+	return u.ProviderSessionID == nil && u.State == nil && u.Error == nil && u.DataSetIDs == nil && u.EarliestDataTime == nil && u.LatestDataTime == nil && u.LastImportTime == nil &&
+		u.FailureHistory == nil && u.ConsecutiveFailureCount == nil && u.ConsecutiveAuthenticationFailureCount == nil && u.FirstConsecutiveFailureTime == nil &&
+		u.SyncHistory == nil
 }
 
 type Source struct {
-	ID                *string              `json:"id,omitempty" bson:"id,omitempty"`
-	UserID            *string              `json:"userId,omitempty" bson:"userId,omitempty"`
-	ProviderType      *string              `json:"providerType,omitempty" bson:"providerType,omitempty"`
-	ProviderName      *string              `json:"providerName,omitempty" bson:"providerName,omitempty"`
-	ProviderSessionID *string              `json:"providerSessionId,omitempty" bson:"providerSessionId,omitempty"`
-	State             *string              `json:"state,omitempty" bson:"state,omitempty"`
-	Error             *errors.Serializable `json:"error,omitempty" bson:"error,omitempty"`
-	DataSetIDs        *[]string            `json:"dataSetIds,omitempty" bson:"dataSetIds,omitempty"`
-	EarliestDataTime  *time.Time           `json:"earliestDataTime,omitempty" bson:"earliestDataTime,omitempty"`
-	LatestDataTime    *time.Time           `json:"latestDataTime,omitempty" bson:"latestDataTime,omitempty"`
-	LastImportTime    *time.Time           `json:"lastImportTime,omitempty" bson:"lastImportTime,omitempty"`
-	CreatedTime       *time.Time           `json:"createdTime,omitempty" bson:"createdTime,omitempty"`
-	ModifiedTime      *time.Time           `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
-	Revision          *int                 `json:"revision,omitempty" bson:"revision,omitempty"`
+	ID                                    *string              `json:"id,omitempty" bson:"id,omitempty"`
+	UserID                                *string              `json:"userId,omitempty" bson:"userId,omitempty"`
+	ProviderType                          *string              `json:"providerType,omitempty" bson:"providerType,omitempty"`
+	ProviderName                          *string              `json:"providerName,omitempty" bson:"providerName,omitempty"`
+	ProviderSessionID                     *string              `json:"providerSessionId,omitempty" bson:"providerSessionId,omitempty"`
+	State                                 *string              `json:"state,omitempty" bson:"state,omitempty"`
+	Error                                 *errors.Serializable `json:"error,omitempty" bson:"error,omitempty"`
+	DataSetIDs                            *[]string            `json:"dataSetIds,omitempty" bson:"dataSetIds,omitempty"`
+	EarliestDataTime                      *time.Time           `json:"earliestDataTime,omitempty" bson:"earliestDataTime,omitempty"`
+	LatestDataTime                        *time.Time           `json:"latestDataTime,omitempty" bson:"latestDataTime,omitempty"`
+	LastImportTime                        *time.Time           `json:"lastImportTime,omitempty" bson:"lastImportTime,omitempty"`
+	FailureHistory                        *FailureArray        `json:"failureHistory,omitempty" bson:"failureHistory,omitempty"`
+	ConsecutiveFailureCount               *int                 `json:"consecutiveFailureCount,omitempty" bson:"consecutiveFailureCount,omitempty"`
+	ConsecutiveAuthenticationFailureCount *int                 `json:"consecutiveAuthenticationFailureCount,omitempty" bson:"consecutiveAuthenticationFailureCount,omitempty"`
+	FirstConsecutiveFailureTime           *time.Time           `json:"firstConsecutiveFailureTime,omitempty" bson:"firstConsecutiveFailureTime,omitempty"`
+	SyncHistory                           *SyncArray           `json:"syncHistory,omitempty" bson:"syncHistory,omitempty"`
+	CreatedTime                           *time.Time           `json:"createdTime,omitempty" bson:"createdTime,omitempty"`
+	ModifiedTime                          *time.Time           `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
+	Revision                              *int                 `json:"revision,omitempty" bson:"revision,omitempty"`
 }
 
 func (s *Source) Parse(parser structure.ObjectParser) {
@@ -200,6 +235,11 @@ func (s *Source) Parse(parser structure.ObjectParser) {
 	s.EarliestDataTime = parser.Time("earliestDataTime", time.RFC3339Nano)
 	s.LatestDataTime = parser.Time("latestDataTime", time.RFC3339Nano)
 	s.LastImportTime = parser.Time("lastImportTime", time.RFC3339Nano)
+	s.FailureHistory = ParseFailureArray(parser.WithReferenceArrayParser("failureHistory"))
+	s.ConsecutiveFailureCount = parser.Int("consecutiveFailureCount")
+	s.ConsecutiveAuthenticationFailureCount = parser.Int("consecutiveAuthenticationFailureCount")
+	s.FirstConsecutiveFailureTime = parser.Time("firstConsecutiveFailureTime", time.RFC3339Nano)
+	s.SyncHistory = ParseSyncArray(parser.WithReferenceArrayParser("syncHistory"))
 	s.CreatedTime = parser.Time("createdTime", time.RFC3339Nano)
 	s.ModifiedTime = parser.Time("modifiedTime", time.RFC3339Nano)
 	s.Revision = parser.Int("revision")
@@ -219,6 +259,15 @@ func (s *Source) Validate(validator structure.Validator) {
 	validator.Time("earliestDataTime", s.EarliestDataTime).NotZero().BeforeNow(time.Second)
 	validator.Time("latestDataTime", s.LatestDataTime).NotZero().After(pointer.ToTime(s.EarliestDataTime)).BeforeNow(time.Second)
 	validator.Time("lastImportTime", s.LastImportTime).NotZero().BeforeNow(time.Second)
+	if s.FailureHistory != nil {
+		s.FailureHistory.Validate(validator.WithReference("failureHistory"))
+	}
+	validator.Int("consecutiveFailureCount", s.ConsecutiveFailureCount).GreaterThanOrEqualTo(0)
+	validator.Int("consecutiveAuthenticationFailureCount", s.ConsecutiveAuthenticationFailureCount).GreaterThanOrEqualTo(0)
+	validator.Time("firstConsecutiveFailureTime", s.FirstConsecutiveFailureTime).NotZero().BeforeNow(time.Second)
+	if s.SyncHistory != nil {
+		s.SyncHistory.Validate(validator.WithReference("syncHistory"))
+	}
 	validator.Time("createdTime", s.CreatedTime).Exists().NotZero().BeforeNow(time.Second)
 	validator.Time("modifiedTime", s.ModifiedTime).NotZero().After(pointer.ToTime(s.CreatedTime)).BeforeNow(time.Second)
 	validator.Int("revision", s.Revision).Exists().GreaterThanOrEqualTo(0)
@@ -228,6 +277,12 @@ func (s *Source) Normalize(normalizer structure.Normalizer) {
 	if s.Error != nil {
 		s.Error.Normalize(normalizer.WithReference("error"))
 	}
+	if s.FailureHistory != nil {
+		s.FailureHistory.Normalize(normalizer.WithReference("failureHistory"))
+	}
+	if s.SyncHistory != nil {
+		s.SyncHistory.Normalize(normalizer.WithReference("syncHistory"))
+	}
 }
 
 func (s *Source) Sanitize(details request.Details) error {
@@ -237,19 +292,32 @@ func (s *Source) Sanitize(details request.Details) error {
 
 	if details.IsUser() {
 		s.ProviderSessionID = nil
-		if s.Error != nil && s.Error.Error != nil {
-			// TODO: Is there a way to make this a more general use case?
-			// TODO: Check all production data source errors for examples.
-			if cause := errors.Cause(s.Error.Error); request.IsErrorUnauthenticated(cause) {
-				s.Error.Error = cause
+		sanitizeSerializable(s.Error)
+		if s.FailureHistory != nil {
+			for _, failure := range *s.FailureHistory {
+				if failure != nil {
+					sanitizeSerializable(failure.Error)
+				}
 			}
-			s.Error.Error = errors.Sanitize(s.Error.Error)
 		}
+		// The sync history is only available to users through the history endpoint
+		s.SyncHistory = nil
 	}
 
 	return nil
 }
 
+func sanitizeSerializable(serializable *errors.Serializable) {
+	if serializable != nil && serializable.Error != nil {
+		// TODO: Is there a way to make this a more general use case?
+		// TODO: Check all production data source errors for examples.
+		if cause := errors.Cause(serializable.Error); request.IsErrorUnauthenticated(cause) {
+			serializable.Error = cause
+		}
+		serializable.Error = errors.Sanitize(serializable.Error)
+	}
+}
+
 type SourceArray []*Source
 
 func (s SourceArray) Sanitize(details request.Details) error {
@@ -261,6 +329,97 @@ func (s SourceArray) Sanitize(details request.Details) error {
 	return nil
 }
 
+// Failure records a single failed import. The most recent failures are kept as the failure history of the source.
+type Failure struct {
+	Time           *time.Time           `json:"time,omitempty" bson:"time,omitempty"`
+	Error          *errors.Serializable `json:"error,omitempty" bson:"error,omitempty"`
+	Authentication *bool                `json:"authentication,omitempty" bson:"authentication,omitempty"`
+}
+
+func ParseFailure(parser structure.ObjectParser) *Failure {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewFailure()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewFailure() *Failure {
+	return &Failure{}
+}
+
+func (f *Failure) Parse(parser structure.ObjectParser) {
+	f.Time = parser.Time("time", time.RFC3339Nano)
+	if parser.ReferenceExists("error") {
+		serializable := &errors.Serializable{}
+		serializable.Parse("error", parser)
+		if serializable.Error != nil {
+			f.Error = serializable
+		}
+	}
+	f.Authentication = parser.Bool("authentication")
+}
+
+func (f *Failure) Validate(validator structure.Validator) {
+	validator.Time("time", f.Time).Exists().NotZero().BeforeNow(time.Second)
+	if f.Error != nil {
+		f.Error.Validate(validator.WithReference("error"))
+	} else {
+		validator.WithReference("error").ReportError(structureValidator.ErrorValueNotExists())
+	}
+	validator.Bool("authentication", f.Authentication).Exists()
+}
+
+func (f *Failure) Normalize(normalizer structure.Normalizer) {
+	if f.Error != nil {
+		f.Error.Normalize(normalizer.WithReference("error"))
+	}
+}
+
+type FailureArray []*Failure
+
+func ParseFailureArray(parser structure.ArrayParser) *FailureArray {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewFailureArray()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewFailureArray() *FailureArray {
+	return &FailureArray{}
+}
+
+func (f *FailureArray) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*f = append(*f, ParseFailure(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (f *FailureArray) Validate(validator structure.Validator) {
+	if length := len(*f); length > FailureArrayLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, FailureArrayLengthMaximum))
+	}
+
+	for index, datum := range *f {
+		if datumValidator := validator.WithReference(strconv.Itoa(index)); datum != nil {
+			datum.Validate(datumValidator)
+		} else {
+			datumValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+func (f *FailureArray) Normalize(normalizer structure.Normalizer) {
+	for index, datum := range *f {
+		if datum != nil {
+			datum.Normalize(normalizer.WithReference(strconv.Itoa(index)))
+		}
+	}
+}
+
 func NewID() string {
 	return id.Must(id.New(16))
 }
@@ -1034,6 +1034,35 @@ var _ = Describe("Source", func() {
 						datum.LastImportTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
 					},
 				),
+				Entry("failure history missing",
+					func(datum *dataSource.Update) { datum.FailureHistory = nil },
+				),
+				Entry("failure history length out of range (upper)",
+					func(datum *dataSource.Update) {
+						datum.FailureHistory = dataSourceTest.RandomFailureArray(11, 11)
+					},
+					errorsTest.WithPointerSource(structureValidator.ErrorLengthNotLessThanOrEqualTo(11, 10), "/failureHistory"),
+				),
+				Entry("failure history element invalid",
+					func(datum *dataSource.Update) {
+						(*datum.FailureHistory)[0].Time = nil
+					},
+					errorsTest.WithPointerSource(structureValidator.ErrorValueNotExists(), "/failureHistory/0/time"),
+				),
+				Entry("consecutive failure count out of range (lower)",
+					func(datum *dataSource.Update) { datum.ConsecutiveFailureCount = pointer.FromInt(-1) },
+					errorsTest.WithPointerSource(structureValidator.ErrorValueNotGreaterThanOrEqualTo(-1, 0), "/consecutiveFailureCount"),
+				),
+				Entry("consecutive authentication failure count out of range (lower)",
+					func(datum *dataSource.Update) { datum.ConsecutiveAuthenticationFailureCount = pointer.FromInt(-1) },
+					errorsTest.WithPointerSource(structureValidator.ErrorValueNotGreaterThanOrEqualTo(-1, 0), "/consecutiveAuthenticationFailureCount"),
+				),
+				Entry("first consecutive failure time after now",
+					func(datum *dataSource.Update) {
+						datum.FirstConsecutiveFailureTime = pointer.FromTime(test.FutureFarTime())
+					},
+					errorsTest.WithPointerSource(structureValidator.ErrorValueTimeNotBeforeNow(test.FutureFarTime()), "/firstConsecutiveFailureTime"),
+				),
 				Entry("multiple errors",
 					func(datum *dataSource.Update) {
 						datum.ProviderSessionID = pointer.FromString("")
@@ -1120,6 +1149,26 @@ var _ = Describe("Source", func() {
 				Expect(datum.IsEmpty()).To(BeFalse())
 			})
 
+			It("returns false when failure history is not nil", func() {
+				datum.FailureHistory = dataSourceTest.RandomFailureArray(1, 3)
+				Expect(datum.IsEmpty()).To(BeFalse())
+			})
+
+			It("returns false when consecutive failure count is not nil", func() {
+				datum.ConsecutiveFailureCount = pointer.FromInt(1)
+				Expect(datum.IsEmpty()).To(BeFalse())
+			})
+
+			It("returns false when consecutive authentication failure count is not nil", func() {
+				datum.ConsecutiveAuthenticationFailureCount = pointer.FromInt(1)
+				Expect(datum.IsEmpty()).To(BeFalse())
+			})
+
+			It("returns false when first consecutive failure time is not nil", func() {
+				datum.FirstConsecutiveFailureTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+				Expect(datum.IsEmpty()).To(BeFalse())
+			})
+
 			It("returns false when all fields are not nil", func() {
 				datum = dataSourceTest.RandomUpdate()
 				Expect(datum.IsEmpty()).To(BeFalse())
@@ -1847,11 +1896,15 @@ var _ = Describe("Source", func() {
 				Expect(sanitized).To(Equal(original))
 			})
 
-			It("removes the provider session id and sanitizes the error when the details are user", func() {
+			It("removes the provider session id and sync history and sanitizes the error when the details are user", func() {
 				details := request.NewDetails(request.MethodSessionToken, userTest.RandomID(), authTest.NewSessionToken())
 				Expect(sanitized.Sanitize(details)).ToNot(HaveOccurred())
 				original.ProviderSessionID = nil
+				original.SyncHistory = nil
 				original.Error.Error = errors.Sanitize(original.Error.Error)
+				for _, failure := range *original.FailureHistory {
+					failure.Error.Error = errors.Sanitize(failure.Error.Error)
+				}
 				Expect(sanitized).To(Equal(original))
 			})
 
@@ -1868,7 +1921,11 @@ var _ = Describe("Source", func() {
 					details := request.NewDetails(request.MethodSessionToken, userTest.RandomID(), authTest.NewSessionToken())
 					Expect(sanitized.Sanitize(details)).ToNot(HaveOccurred())
 					original.ProviderSessionID = nil
+					original.SyncHistory = nil
 					original.Error.Error = errors.Sanitize(unauthenticatedError)
+					for _, failure := range *original.FailureHistory {
+						failure.Error.Error = errors.Sanitize(failure.Error.Error)
+					}
 					Expect(sanitized).To(Equal(original))
 				})
 			})
@@ -1895,12 +1952,16 @@ var _ = Describe("Source", func() {
 				Expect(sanitized).To(Equal(originals))
 			})
 
-			It("removes the provider session id and sanitizes the error when the details are user", func() {
+			It("removes the provider session id and sync history and sanitizes the error when the details are user", func() {
 				details := request.NewDetails(request.MethodSessionToken, userTest.RandomID(), authTest.NewSessionToken())
 				Expect(sanitized.Sanitize(details)).ToNot(HaveOccurred())
 				for _, original := range originals {
 					original.ProviderSessionID = nil
+					original.SyncHistory = nil
 					original.Error.Error = errors.Sanitize(original.Error.Error)
+					for _, failure := range *original.FailureHistory {
+						failure.Error.Error = errors.Sanitize(failure.Error.Error)
+					}
 				}
 				Expect(sanitized).To(Equal(originals))
 			})
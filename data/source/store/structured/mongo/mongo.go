@@ -283,6 +283,9 @@ func (c *DataSourcesRepository) Update(ctx context.Context, id string, condition
 				unset["error"] = true
 			case dataSource.StateConnected:
 				unset["error"] = true
+				unset["consecutiveFailureCount"] = true
+				unset["consecutiveAuthenticationFailureCount"] = true
+				unset["firstConsecutiveFailureTime"] = true
 			}
 		}
 		if update.Error != nil {
@@ -301,6 +304,24 @@ func (c *DataSourcesRepository) Update(ctx context.Context, id string, condition
 		if update.LastImportTime != nil {
 			set["lastImportTime"] = *update.LastImportTime
 		}
+		if update.FailureHistory != nil {
+			set["failureHistory"] = *update.FailureHistory
+		}
+		if update.ConsecutiveFailureCount != nil {
+			delete(unset, "consecutiveFailureCount")
+			set["consecutiveFailureCount"] = *update.ConsecutiveFailureCount
+		}
+		if update.ConsecutiveAuthenticationFailureCount != nil {
+			delete(unset, "consecutiveAuthenticationFailureCount")
+			set["consecutiveAuthenticationFailureCount"] = *update.ConsecutiveAuthenticationFailureCount
+		}
+		if update.FirstConsecutiveFailureTime != nil {
+			delete(unset, "firstConsecutiveFailureTime")
+			set["firstConsecutiveFailureTime"] = *update.FirstConsecutiveFailureTime
+		}
+		if update.SyncHistory != nil {
+			set["syncHistory"] = *update.SyncHistory
+		}
 		changeInfo, err := c.UpdateMany(ctx, query, c.ConstructUpdate(set, unset))
 		if err != nil {
 			logger.WithError(err).Error("Unable to update data source")
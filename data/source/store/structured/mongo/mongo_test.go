@@ -454,20 +454,24 @@ var _ = Describe("Mongo", func() {
 
 					It("returns the result after creating", func() {
 						matchAllFields := MatchAllFields(Fields{
-							"ID":                PointTo(Not(BeEmpty())),
-							"UserID":            PointTo(Equal(userID)),
-							"ProviderType":      Equal(create.ProviderType),
-							"ProviderName":      Equal(create.ProviderName),
-							"ProviderSessionID": Equal(create.ProviderSessionID),
-							"State":             Equal(create.State),
-							"Error":             BeNil(),
-							"DataSetIDs":        BeNil(),
-							"EarliestDataTime":  BeNil(),
-							"LatestDataTime":    BeNil(),
-							"LastImportTime":    BeNil(),
-							"CreatedTime":       PointTo(BeTemporally("~", time.Now(), time.Second)),
-							"ModifiedTime":      BeNil(),
-							"Revision":          PointTo(Equal(0)),
+							"ID":                                    PointTo(Not(BeEmpty())),
+							"UserID":                                PointTo(Equal(userID)),
+							"ProviderType":                          Equal(create.ProviderType),
+							"ProviderName":                          Equal(create.ProviderName),
+							"ProviderSessionID":                     Equal(create.ProviderSessionID),
+							"State":                                 Equal(create.State),
+							"Error":                                 BeNil(),
+							"DataSetIDs":                            BeNil(),
+							"EarliestDataTime":                      BeNil(),
+							"LatestDataTime":                        BeNil(),
+							"LastImportTime":                        BeNil(),
+							"FailureHistory":                        BeNil(),
+							"ConsecutiveFailureCount":               BeNil(),
+							"ConsecutiveAuthenticationFailureCount": BeNil(),
+							"FirstConsecutiveFailureTime":           BeNil(),
+							"CreatedTime":                           PointTo(BeTemporally("~", time.Now(), time.Second)),
+							"ModifiedTime":                          BeNil(),
+							"Revision":                              PointTo(Equal(0)),
 						})
 						result, err := repository.Create(ctx, userID, create)
 						Expect(err).ToNot(HaveOccurred())
@@ -699,20 +703,24 @@ var _ = Describe("Mongo", func() {
 								update.State = pointer.FromString(dataSource.StateConnected)
 								update.Error = nil
 								matchAllFields := MatchAllFields(Fields{
-									"ID":                PointTo(Equal(id)),
-									"UserID":            Equal(original.UserID),
-									"ProviderType":      Equal(original.ProviderType),
-									"ProviderName":      Equal(original.ProviderName),
-									"ProviderSessionID": Equal(update.ProviderSessionID),
-									"State":             Equal(update.State),
-									"Error":             Equal(update.Error),
-									"DataSetIDs":        Equal(update.DataSetIDs),
-									"EarliestDataTime":  Equal(update.EarliestDataTime),
-									"LatestDataTime":    Equal(update.LatestDataTime),
-									"LastImportTime":    Equal(update.LastImportTime),
-									"CreatedTime":       Equal(original.CreatedTime),
-									"ModifiedTime":      PointTo(BeTemporally("~", time.Now(), time.Second)),
-									"Revision":          PointTo(Equal(*original.Revision + 1)),
+									"ID":                                    PointTo(Equal(id)),
+									"UserID":                                Equal(original.UserID),
+									"ProviderType":                          Equal(original.ProviderType),
+									"ProviderName":                          Equal(original.ProviderName),
+									"ProviderSessionID":                     Equal(update.ProviderSessionID),
+									"State":                                 Equal(update.State),
+									"Error":                                 Equal(update.Error),
+									"DataSetIDs":                            Equal(update.DataSetIDs),
+									"EarliestDataTime":                      Equal(update.EarliestDataTime),
+									"LatestDataTime":                        Equal(update.LatestDataTime),
+									"LastImportTime":                        Equal(update.LastImportTime),
+									"FailureHistory":                        dataSourceTest.MatchFailureArray(update.FailureHistory),
+									"ConsecutiveFailureCount":               Equal(update.ConsecutiveFailureCount),
+									"ConsecutiveAuthenticationFailureCount": Equal(update.ConsecutiveAuthenticationFailureCount),
+									"FirstConsecutiveFailureTime":           Equal(update.FirstConsecutiveFailureTime),
+									"CreatedTime":                           Equal(original.CreatedTime),
+									"ModifiedTime":                          PointTo(BeTemporally("~", time.Now(), time.Second)),
+									"Revision":                              PointTo(Equal(*original.Revision + 1)),
 								})
 								result, err := repository.Update(ctx, id, condition, update)
 								Expect(err).ToNot(HaveOccurred())
@@ -727,25 +735,62 @@ var _ = Describe("Mongo", func() {
 								Expect(*storeResult[0]).To(matchAllFields)
 							})
 
+							It("returns updated result with reset failure counts when the id exists and state is connected without failure counts", func() {
+								update.ProviderSessionID = pointer.FromString(authTest.RandomProviderSessionID())
+								update.State = pointer.FromString(dataSource.StateConnected)
+								update.Error = nil
+								update.ConsecutiveFailureCount = nil
+								update.ConsecutiveAuthenticationFailureCount = nil
+								update.FirstConsecutiveFailureTime = nil
+								matchAllFields := MatchAllFields(Fields{
+									"ID":                                    PointTo(Equal(id)),
+									"UserID":                                Equal(original.UserID),
+									"ProviderType":                          Equal(original.ProviderType),
+									"ProviderName":                          Equal(original.ProviderName),
+									"ProviderSessionID":                     Equal(update.ProviderSessionID),
+									"State":                                 Equal(update.State),
+									"Error":                                 BeNil(),
+									"DataSetIDs":                            Equal(update.DataSetIDs),
+									"EarliestDataTime":                      Equal(update.EarliestDataTime),
+									"LatestDataTime":                        Equal(update.LatestDataTime),
+									"LastImportTime":                        Equal(update.LastImportTime),
+									"FailureHistory":                        dataSourceTest.MatchFailureArray(update.FailureHistory),
+									"ConsecutiveFailureCount":               BeNil(),
+									"ConsecutiveAuthenticationFailureCount": BeNil(),
+									"FirstConsecutiveFailureTime":           BeNil(),
+									"CreatedTime":                           Equal(original.CreatedTime),
+									"ModifiedTime":                          PointTo(BeTemporally("~", time.Now(), time.Second)),
+									"Revision":                              PointTo(Equal(*original.Revision + 1)),
+								})
+								result, err := repository.Update(ctx, id, condition, update)
+								Expect(err).ToNot(HaveOccurred())
+								Expect(result).ToNot(BeNil())
+								Expect(*result).To(matchAllFields)
+							})
+
 							It("returns updated result when the id exists and state is disconnected without error", func() {
 								update.ProviderSessionID = nil
 								update.State = pointer.FromString(dataSource.StateDisconnected)
 								update.Error = nil
 								matchAllFields := MatchAllFields(Fields{
-									"ID":                PointTo(Equal(id)),
-									"UserID":            Equal(original.UserID),
-									"ProviderType":      Equal(original.ProviderType),
-									"ProviderName":      Equal(original.ProviderName),
-									"ProviderSessionID": BeNil(),
-									"State":             Equal(update.State),
-									"Error":             Equal(update.Error),
-									"DataSetIDs":        Equal(update.DataSetIDs),
-									"EarliestDataTime":  Equal(update.EarliestDataTime),
-									"LatestDataTime":    Equal(update.LatestDataTime),
-									"LastImportTime":    Equal(update.LastImportTime),
-									"CreatedTime":       Equal(original.CreatedTime),
-									"ModifiedTime":      PointTo(BeTemporally("~", time.Now(), time.Second)),
-									"Revision":          PointTo(Equal(*original.Revision + 1)),
+									"ID":                                    PointTo(Equal(id)),
+									"UserID":                                Equal(original.UserID),
+									"ProviderType":                          Equal(original.ProviderType),
+									"ProviderName":                          Equal(original.ProviderName),
+									"ProviderSessionID":                     BeNil(),
+									"State":                                 Equal(update.State),
+									"Error":                                 Equal(update.Error),
+									"DataSetIDs":                            Equal(update.DataSetIDs),
+									"EarliestDataTime":                      Equal(update.EarliestDataTime),
+									"LatestDataTime":                        Equal(update.LatestDataTime),
+									"LastImportTime":                        Equal(update.LastImportTime),
+									"FailureHistory":                        dataSourceTest.MatchFailureArray(update.FailureHistory),
+									"ConsecutiveFailureCount":               Equal(update.ConsecutiveFailureCount),
+									"ConsecutiveAuthenticationFailureCount": Equal(update.ConsecutiveAuthenticationFailureCount),
+									"FirstConsecutiveFailureTime":           Equal(update.FirstConsecutiveFailureTime),
+									"CreatedTime":                           Equal(original.CreatedTime),
+									"ModifiedTime":                          PointTo(BeTemporally("~", time.Now(), time.Second)),
+									"Revision":                              PointTo(Equal(*original.Revision + 1)),
 								})
 								result, err := repository.Update(ctx, id, condition, update)
 								Expect(err).ToNot(HaveOccurred())
@@ -764,20 +809,24 @@ var _ = Describe("Mongo", func() {
 								update.ProviderSessionID = nil
 								update.State = pointer.FromString(dataSource.StateError)
 								matchAllFields := MatchAllFields(Fields{
-									"ID":                PointTo(Equal(id)),
-									"UserID":            Equal(original.UserID),
-									"ProviderType":      Equal(original.ProviderType),
-									"ProviderName":      Equal(original.ProviderName),
-									"ProviderSessionID": Equal(original.ProviderSessionID),
-									"State":             Equal(update.State),
-									"Error":             Equal(update.Error),
-									"DataSetIDs":        Equal(update.DataSetIDs),
-									"EarliestDataTime":  Equal(update.EarliestDataTime),
-									"LatestDataTime":    Equal(update.LatestDataTime),
-									"LastImportTime":    Equal(update.LastImportTime),
-									"CreatedTime":       Equal(original.CreatedTime),
-									"ModifiedTime":      PointTo(BeTemporally("~", time.Now(), time.Second)),
-									"Revision":          PointTo(Equal(*original.Revision + 1)),
+									"ID":                                    PointTo(Equal(id)),
+									"UserID":                                Equal(original.UserID),
+									"ProviderType":                          Equal(original.ProviderType),
+									"ProviderName":                          Equal(original.ProviderName),
+									"ProviderSessionID":                     Equal(original.ProviderSessionID),
+									"State":                                 Equal(update.State),
+									"Error":                                 Equal(update.Error),
+									"DataSetIDs":                            Equal(update.DataSetIDs),
+									"EarliestDataTime":                      Equal(update.EarliestDataTime),
+									"LatestDataTime":                        Equal(update.LatestDataTime),
+									"LastImportTime":                        Equal(update.LastImportTime),
+									"FailureHistory":                        dataSourceTest.MatchFailureArray(update.FailureHistory),
+									"ConsecutiveFailureCount":               Equal(update.ConsecutiveFailureCount),
+									"ConsecutiveAuthenticationFailureCount": Equal(update.ConsecutiveAuthenticationFailureCount),
+									"FirstConsecutiveFailureTime":           Equal(update.FirstConsecutiveFailureTime),
+									"CreatedTime":                           Equal(original.CreatedTime),
+									"ModifiedTime":                          PointTo(BeTemporally("~", time.Now(), time.Second)),
+									"Revision":                              PointTo(Equal(*original.Revision + 1)),
 								})
 								result, err := repository.Update(ctx, id, condition, update)
 								Expect(err).ToNot(HaveOccurred())
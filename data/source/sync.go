@@ -0,0 +1,324 @@
+package source
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	SyncArrayLengthMaximum        = 100
+	SyncWindowArrayLengthMaximum  = 100
+	SyncRequestArrayLengthMaximum = 20
+)
+
+// Sync records a single import: each window of data requested from the partner, the response to each partner request,
+// and the number of datum translated and stored. The most recent syncs are kept as the sync history of the source.
+type Sync struct {
+	Time    *time.Time           `json:"time,omitempty" bson:"time,omitempty"`
+	Windows *SyncWindowArray     `json:"windows,omitempty" bson:"windows,omitempty"`
+	Error   *errors.Serializable `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+func ParseSync(parser structure.ObjectParser) *Sync {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSync()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSync() *Sync {
+	return &Sync{}
+}
+
+func (s *Sync) Parse(parser structure.ObjectParser) {
+	s.Time = parser.Time("time", time.RFC3339Nano)
+	s.Windows = ParseSyncWindowArray(parser.WithReferenceArrayParser("windows"))
+	if parser.ReferenceExists("error") {
+		serializable := &errors.Serializable{}
+		serializable.Parse("error", parser)
+		if serializable.Error != nil {
+			s.Error = serializable
+		}
+	}
+}
+
+func (s *Sync) Validate(validator structure.Validator) {
+	validator.Time("time", s.Time).Exists().NotZero().BeforeNow(time.Second)
+	if s.Windows != nil {
+		s.Windows.Validate(validator.WithReference("windows"))
+	}
+	if s.Error != nil {
+		s.Error.Validate(validator.WithReference("error"))
+	}
+}
+
+func (s *Sync) Normalize(normalizer structure.Normalizer) {
+	if s.Error != nil {
+		s.Error.Normalize(normalizer.WithReference("error"))
+	}
+}
+
+// AddWindow adds a new window to the sync. If there are too many windows, then the earliest window is removed.
+func (s *Sync) AddWindow(startTime time.Time, endTime time.Time) *SyncWindow {
+	if s.Windows == nil {
+		s.Windows = NewSyncWindowArray()
+	}
+
+	window := NewSyncWindow()
+	window.StartTime = pointer.FromTime(startTime)
+	window.EndTime = pointer.FromTime(endTime)
+
+	*s.Windows = append(*s.Windows, window)
+	if length := len(*s.Windows); length > SyncWindowArrayLengthMaximum {
+		*s.Windows = (*s.Windows)[length-SyncWindowArrayLengthMaximum:]
+	}
+	return window
+}
+
+type SyncArray []*Sync
+
+func ParseSyncArray(parser structure.ArrayParser) *SyncArray {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSyncArray()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSyncArray() *SyncArray {
+	return &SyncArray{}
+}
+
+func (s *SyncArray) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*s = append(*s, ParseSync(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (s *SyncArray) Validate(validator structure.Validator) {
+	if length := len(*s); length > SyncArrayLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, SyncArrayLengthMaximum))
+	}
+
+	for index, datum := range *s {
+		if datumValidator := validator.WithReference(strconv.Itoa(index)); datum != nil {
+			datum.Validate(datumValidator)
+		} else {
+			datumValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+func (s *SyncArray) Normalize(normalizer structure.Normalizer) {
+	for index, datum := range *s {
+		if datum != nil {
+			datum.Normalize(normalizer.WithReference(strconv.Itoa(index)))
+		}
+	}
+}
+
+func (s *SyncArray) Sanitize(details request.Details) error {
+	if details == nil {
+		return errors.New("unable to sanitize")
+	}
+
+	if details.IsUser() {
+		for _, sync := range *s {
+			if sync != nil {
+				sanitizeSerializable(sync.Error)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewSyncHistory returns the sync history of the source with the sync added. If there are too many syncs, then the
+// earliest syncs are removed.
+func NewSyncHistory(source *Source, sync *Sync) *SyncArray {
+	syncHistory := SyncArray{}
+	if source != nil && source.SyncHistory != nil {
+		syncHistory = append(syncHistory, *source.SyncHistory...)
+	}
+	syncHistory = append(syncHistory, sync)
+	if length := len(syncHistory); length > SyncArrayLengthMaximum {
+		syncHistory = syncHistory[length-SyncArrayLengthMaximum:]
+	}
+	return &syncHistory
+}
+
+// SyncWindow records a single window of data requested from the partner. The translated count is the number of datum
+// translated from the partner records. The deduplicated count is the number of those datum remaining after removing
+// any previously imported, all of which are stored.
+type SyncWindow struct {
+	StartTime         *time.Time        `json:"startTime,omitempty" bson:"startTime,omitempty"`
+	EndTime           *time.Time        `json:"endTime,omitempty" bson:"endTime,omitempty"`
+	Requests          *SyncRequestArray `json:"requests,omitempty" bson:"requests,omitempty"`
+	TranslatedCount   *int              `json:"translatedCount,omitempty" bson:"translatedCount,omitempty"`
+	DeduplicatedCount *int              `json:"deduplicatedCount,omitempty" bson:"deduplicatedCount,omitempty"`
+}
+
+func ParseSyncWindow(parser structure.ObjectParser) *SyncWindow {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSyncWindow()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSyncWindow() *SyncWindow {
+	return &SyncWindow{}
+}
+
+func (s *SyncWindow) Parse(parser structure.ObjectParser) {
+	s.StartTime = parser.Time("startTime", time.RFC3339Nano)
+	s.EndTime = parser.Time("endTime", time.RFC3339Nano)
+	s.Requests = ParseSyncRequestArray(parser.WithReferenceArrayParser("requests"))
+	s.TranslatedCount = parser.Int("translatedCount")
+	s.DeduplicatedCount = parser.Int("deduplicatedCount")
+}
+
+func (s *SyncWindow) Validate(validator structure.Validator) {
+	validator.Time("startTime", s.StartTime).Exists().NotZero()
+	validator.Time("endTime", s.EndTime).Exists().After(pointer.ToTime(s.StartTime)).BeforeNow(time.Second)
+	if s.Requests != nil {
+		s.Requests.Validate(validator.WithReference("requests"))
+	}
+	validator.Int("translatedCount", s.TranslatedCount).GreaterThanOrEqualTo(0)
+	if s.TranslatedCount != nil {
+		validator.Int("deduplicatedCount", s.DeduplicatedCount).InRange(0, *s.TranslatedCount)
+	} else {
+		validator.Int("deduplicatedCount", s.DeduplicatedCount).NotExists()
+	}
+}
+
+// AddRequest adds a new partner request to the window. If there are too many requests, then the earliest request is
+// removed.
+func (s *SyncWindow) AddRequest(endpoint string, statusCode int) *SyncRequest {
+	if s.Requests == nil {
+		s.Requests = NewSyncRequestArray()
+	}
+
+	syncRequest := NewSyncRequest()
+	syncRequest.Endpoint = pointer.FromString(endpoint)
+	syncRequest.StatusCode = pointer.FromInt(statusCode)
+
+	*s.Requests = append(*s.Requests, syncRequest)
+	if length := len(*s.Requests); length > SyncRequestArrayLengthMaximum {
+		*s.Requests = (*s.Requests)[length-SyncRequestArrayLengthMaximum:]
+	}
+	return syncRequest
+}
+
+type SyncWindowArray []*SyncWindow
+
+func ParseSyncWindowArray(parser structure.ArrayParser) *SyncWindowArray {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSyncWindowArray()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSyncWindowArray() *SyncWindowArray {
+	return &SyncWindowArray{}
+}
+
+func (s *SyncWindowArray) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*s = append(*s, ParseSyncWindow(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (s *SyncWindowArray) Validate(validator structure.Validator) {
+	if length := len(*s); length > SyncWindowArrayLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, SyncWindowArrayLengthMaximum))
+	}
+
+	for index, datum := range *s {
+		if datumValidator := validator.WithReference(strconv.Itoa(index)); datum != nil {
+			datum.Validate(datumValidator)
+		} else {
+			datumValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+// SyncRequest records a single partner request. The status code is zero if there was no response. The record count is
+// the number of records returned, if reported by the partner.
+type SyncRequest struct {
+	Endpoint    *string `json:"endpoint,omitempty" bson:"endpoint,omitempty"`
+	StatusCode  *int    `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	RecordCount *int    `json:"recordCount,omitempty" bson:"recordCount,omitempty"`
+}
+
+func ParseSyncRequest(parser structure.ObjectParser) *SyncRequest {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSyncRequest()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSyncRequest() *SyncRequest {
+	return &SyncRequest{}
+}
+
+func (s *SyncRequest) Parse(parser structure.ObjectParser) {
+	s.Endpoint = parser.String("endpoint")
+	s.StatusCode = parser.Int("statusCode")
+	s.RecordCount = parser.Int("recordCount")
+}
+
+func (s *SyncRequest) Validate(validator structure.Validator) {
+	validator.String("endpoint", s.Endpoint).Exists().NotEmpty()
+	validator.Int("statusCode", s.StatusCode).Exists().InRange(0, 599)
+	validator.Int("recordCount", s.RecordCount).GreaterThanOrEqualTo(0)
+}
+
+type SyncRequestArray []*SyncRequest
+
+func ParseSyncRequestArray(parser structure.ArrayParser) *SyncRequestArray {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewSyncRequestArray()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewSyncRequestArray() *SyncRequestArray {
+	return &SyncRequestArray{}
+}
+
+func (s *SyncRequestArray) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*s = append(*s, ParseSyncRequest(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (s *SyncRequestArray) Validate(validator structure.Validator) {
+	if length := len(*s); length > SyncRequestArrayLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, SyncRequestArrayLengthMaximum))
+	}
+
+	for index, datum := range *s {
+		if datumValidator := validator.WithReference(strconv.Itoa(index)); datum != nil {
+			datum.Validate(datumValidator)
+		} else {
+			datumValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package source_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authTest "github.com/tidepool-org/platform/auth/test"
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	userTest "github.com/tidepool-org/platform/user/test"
+)
+
+var _ = Describe("Sync", func() {
+	It("SyncArrayLengthMaximum is expected", func() {
+		Expect(dataSource.SyncArrayLengthMaximum).To(Equal(100))
+	})
+
+	Context("Sync", func() {
+		Context("Validate", func() {
+			It("returns successfully", func() {
+				Expect(structureValidator.New().Validate(dataSourceTest.RandomSync())).To(Succeed())
+			})
+
+			It("returns an error when the time is missing", func() {
+				sync := dataSourceTest.RandomSync()
+				sync.Time = nil
+				errorsTest.ExpectEqual(structureValidator.New().Validate(sync), errorsTest.WithPointerSource(structureValidator.ErrorValueNotExists(), "/time"))
+			})
+
+			It("returns an error when a window end time is before the start time", func() {
+				sync := dataSourceTest.RandomSync()
+				window := (*sync.Windows)[0]
+				window.EndTime = pointer.FromTime(window.StartTime.Add(-time.Second))
+				errorsTest.ExpectEqual(structureValidator.New().Validate(sync), errorsTest.WithPointerSource(structureValidator.ErrorValueTimeNotAfter(*window.EndTime, *window.StartTime), "/windows/0/endTime"))
+			})
+
+			It("returns an error when a window deduplicated count is greater than the translated count", func() {
+				sync := dataSourceTest.RandomSync()
+				window := (*sync.Windows)[0]
+				window.DeduplicatedCount = pointer.FromInt(*window.TranslatedCount + 1)
+				errorsTest.ExpectEqual(structureValidator.New().Validate(sync), errorsTest.WithPointerSource(structureValidator.ErrorValueNotInRange(*window.DeduplicatedCount, 0, *window.TranslatedCount), "/windows/0/deduplicatedCount"))
+			})
+
+			It("returns an error when a request endpoint is missing", func() {
+				sync := dataSourceTest.RandomSync()
+				(*(*sync.Windows)[0].Requests)[0].Endpoint = nil
+				errorsTest.ExpectEqual(structureValidator.New().Validate(sync), errorsTest.WithPointerSource(structureValidator.ErrorValueNotExists(), "/windows/0/requests/0/endpoint"))
+			})
+		})
+
+		Context("AddWindow", func() {
+			It("adds the window", func() {
+				sync := dataSource.NewSync()
+				startTime := time.Now().Add(-time.Hour)
+				endTime := time.Now()
+				window := sync.AddWindow(startTime, endTime)
+				Expect(window).To(Equal(&dataSource.SyncWindow{StartTime: pointer.FromTime(startTime), EndTime: pointer.FromTime(endTime)}))
+				Expect(*sync.Windows).To(Equal(dataSource.SyncWindowArray{window}))
+			})
+
+			It("removes the earliest window when there are too many windows", func() {
+				sync := dataSource.NewSync()
+				startTime := time.Now().Add(-time.Duration(dataSource.SyncWindowArrayLengthMaximum+1) * time.Hour)
+				for index := 0; index <= dataSource.SyncWindowArrayLengthMaximum; index++ {
+					sync.AddWindow(startTime.Add(time.Duration(index)*time.Hour), startTime.Add(time.Duration(index+1)*time.Hour))
+				}
+				Expect(*sync.Windows).To(HaveLen(dataSource.SyncWindowArrayLengthMaximum))
+				Expect(*(*sync.Windows)[0].StartTime).To(Equal(startTime.Add(time.Hour)))
+			})
+		})
+	})
+
+	Context("SyncWindow", func() {
+		Context("AddRequest", func() {
+			It("adds the request", func() {
+				window := dataSource.NewSyncWindow()
+				syncRequest := window.AddRequest("/v3/users/self/egvs", 200)
+				Expect(syncRequest).To(Equal(&dataSource.SyncRequest{Endpoint: pointer.FromString("/v3/users/self/egvs"), StatusCode: pointer.FromInt(200)}))
+				Expect(*window.Requests).To(Equal(dataSource.SyncRequestArray{syncRequest}))
+			})
+
+			It("removes the earliest request when there are too many requests", func() {
+				window := dataSource.NewSyncWindow()
+				for index := 0; index <= dataSource.SyncRequestArrayLengthMaximum; index++ {
+					window.AddRequest("/endpoint", 200+index)
+				}
+				Expect(*window.Requests).To(HaveLen(dataSource.SyncRequestArrayLengthMaximum))
+				Expect(*(*window.Requests)[0].StatusCode).To(Equal(201))
+			})
+		})
+	})
+
+	Context("SyncArray", func() {
+		Context("Sanitize", func() {
+			var original *dataSource.SyncArray
+			var sanitized *dataSource.SyncArray
+
+			BeforeEach(func() {
+				original = dataSourceTest.RandomSyncArray(1, 3)
+				sanitized = dataSourceTest.CloneSyncArray(original)
+			})
+
+			It("returns an error when the details are missing", func() {
+				errorsTest.ExpectEqual(sanitized.Sanitize(nil), errors.New("unable to sanitize"))
+			})
+
+			It("does not modify the original when the details are server", func() {
+				details := request.NewDetails(request.MethodServiceSecret, "", authTest.NewSessionToken())
+				Expect(sanitized.Sanitize(details)).ToNot(HaveOccurred())
+				Expect(sanitized).To(Equal(original))
+			})
+
+			It("sanitizes the errors when the details are user", func() {
+				details := request.NewDetails(request.MethodSessionToken, userTest.RandomID(), authTest.NewSessionToken())
+				Expect(sanitized.Sanitize(details)).ToNot(HaveOccurred())
+				for _, sync := range *original {
+					sync.Error.Error = errors.Sanitize(sync.Error.Error)
+				}
+				Expect(sanitized).To(Equal(original))
+			})
+		})
+	})
+
+	Context("NewSyncHistory", func() {
+		var sync *dataSource.Sync
+
+		BeforeEach(func() {
+			sync = dataSourceTest.RandomSync()
+		})
+
+		It("returns the sync when the source is missing", func() {
+			Expect(dataSource.NewSyncHistory(nil, sync)).To(Equal(&dataSource.SyncArray{sync}))
+		})
+
+		It("appends the sync to the existing sync history", func() {
+			source := dataSourceTest.RandomSource()
+			syncHistory := dataSource.NewSyncHistory(source, sync)
+			Expect(*syncHistory).To(Equal(append(append(dataSource.SyncArray{}, *source.SyncHistory...), sync)))
+		})
+
+		It("retains only the most recent syncs", func() {
+			source := dataSourceTest.RandomSource()
+			source.SyncHistory = dataSourceTest.RandomSyncArray(dataSource.SyncArrayLengthMaximum, dataSource.SyncArrayLengthMaximum)
+			syncHistory := dataSource.NewSyncHistory(source, sync)
+			Expect(*syncHistory).To(HaveLen(dataSource.SyncArrayLengthMaximum))
+			Expect((*syncHistory)[0]).To(Equal((*source.SyncHistory)[1]))
+			Expect((*syncHistory)[dataSource.SyncArrayLengthMaximum-1]).To(Equal(sync))
+		})
+	})
+})
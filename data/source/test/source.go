@@ -95,6 +95,11 @@ func RandomUpdate() *dataSource.Update {
 	datum.EarliestDataTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
 	datum.LatestDataTime = pointer.FromTime(test.RandomTimeFromRange(*datum.EarliestDataTime, time.Now()))
 	datum.LastImportTime = pointer.FromTime(test.RandomTimeFromRange(*datum.LatestDataTime, time.Now()))
+	datum.FailureHistory = RandomFailureArray(1, 3)
+	datum.ConsecutiveFailureCount = pointer.FromInt(test.RandomIntFromRange(0, 10))
+	datum.ConsecutiveAuthenticationFailureCount = pointer.FromInt(test.RandomIntFromRange(0, *datum.ConsecutiveFailureCount))
+	datum.FirstConsecutiveFailureTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+	datum.SyncHistory = RandomSyncArray(1, 3)
 	return datum
 }
 
@@ -110,6 +115,11 @@ func CloneUpdate(datum *dataSource.Update) *dataSource.Update {
 	clone.EarliestDataTime = pointer.CloneTime(datum.EarliestDataTime)
 	clone.LatestDataTime = pointer.CloneTime(datum.LatestDataTime)
 	clone.LastImportTime = pointer.CloneTime(datum.LastImportTime)
+	clone.FailureHistory = CloneFailureArray(datum.FailureHistory)
+	clone.ConsecutiveFailureCount = pointer.CloneInt(datum.ConsecutiveFailureCount)
+	clone.ConsecutiveAuthenticationFailureCount = pointer.CloneInt(datum.ConsecutiveAuthenticationFailureCount)
+	clone.FirstConsecutiveFailureTime = pointer.CloneTime(datum.FirstConsecutiveFailureTime)
+	clone.SyncHistory = CloneSyncArray(datum.SyncHistory)
 	return clone
 }
 
@@ -139,6 +149,21 @@ func NewObjectFromUpdate(datum *dataSource.Update, objectFormat test.ObjectForma
 	if datum.LastImportTime != nil {
 		object["lastImportTime"] = test.NewObjectFromTime(*datum.LastImportTime, objectFormat)
 	}
+	if datum.FailureHistory != nil {
+		object["failureHistory"] = NewArrayFromFailureArray(datum.FailureHistory, objectFormat)
+	}
+	if datum.ConsecutiveFailureCount != nil {
+		object["consecutiveFailureCount"] = test.NewObjectFromInt(*datum.ConsecutiveFailureCount, objectFormat)
+	}
+	if datum.ConsecutiveAuthenticationFailureCount != nil {
+		object["consecutiveAuthenticationFailureCount"] = test.NewObjectFromInt(*datum.ConsecutiveAuthenticationFailureCount, objectFormat)
+	}
+	if datum.FirstConsecutiveFailureTime != nil {
+		object["firstConsecutiveFailureTime"] = test.NewObjectFromTime(*datum.FirstConsecutiveFailureTime, objectFormat)
+	}
+	if datum.SyncHistory != nil {
+		object["syncHistory"] = NewArrayFromSyncArray(datum.SyncHistory, objectFormat)
+	}
 	return object
 }
 
@@ -147,13 +172,18 @@ func MatchUpdate(datum *dataSource.Update) gomegaTypes.GomegaMatcher {
 		return gomega.BeNil()
 	}
 	return gomegaGstruct.PointTo(gomegaGstruct.MatchAllFields(gomegaGstruct.Fields{
-		"ProviderSessionID": gomega.Equal(datum.ProviderSessionID),
-		"State":             gomega.Equal(datum.State),
-		"Error":             gomega.Equal(datum.Error),
-		"DataSetIDs":        gomega.Equal(datum.DataSetIDs),
-		"EarliestDataTime":  test.MatchTime(datum.EarliestDataTime),
-		"LatestDataTime":    test.MatchTime(datum.LatestDataTime),
-		"LastImportTime":    test.MatchTime(datum.LastImportTime),
+		"ProviderSessionID":                     gomega.Equal(datum.ProviderSessionID),
+		"State":                                 gomega.Equal(datum.State),
+		"Error":                                 gomega.Equal(datum.Error),
+		"DataSetIDs":                            gomega.Equal(datum.DataSetIDs),
+		"EarliestDataTime":                      test.MatchTime(datum.EarliestDataTime),
+		"LatestDataTime":                        test.MatchTime(datum.LatestDataTime),
+		"LastImportTime":                        test.MatchTime(datum.LastImportTime),
+		"FailureHistory":                        MatchFailureArray(datum.FailureHistory),
+		"ConsecutiveFailureCount":               gomega.Equal(datum.ConsecutiveFailureCount),
+		"ConsecutiveAuthenticationFailureCount": gomega.Equal(datum.ConsecutiveAuthenticationFailureCount),
+		"FirstConsecutiveFailureTime":           test.MatchTime(datum.FirstConsecutiveFailureTime),
+		"SyncHistory":                           MatchSyncArray(datum.SyncHistory),
 	}))
 }
 
@@ -170,6 +200,11 @@ func RandomSource() *dataSource.Source {
 	datum.EarliestDataTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
 	datum.LatestDataTime = pointer.FromTime(test.RandomTimeFromRange(*datum.EarliestDataTime, time.Now()))
 	datum.LastImportTime = pointer.FromTime(test.RandomTimeFromRange(*datum.LatestDataTime, time.Now()))
+	datum.FailureHistory = RandomFailureArray(1, 3)
+	datum.ConsecutiveFailureCount = pointer.FromInt(test.RandomIntFromRange(0, 10))
+	datum.ConsecutiveAuthenticationFailureCount = pointer.FromInt(test.RandomIntFromRange(0, *datum.ConsecutiveFailureCount))
+	datum.FirstConsecutiveFailureTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+	datum.SyncHistory = RandomSyncArray(1, 3)
 	datum.CreatedTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
 	datum.ModifiedTime = pointer.FromTime(test.RandomTimeFromRange(*datum.CreatedTime, time.Now()))
 	datum.Revision = pointer.FromInt(requestTest.RandomRevision())
@@ -192,6 +227,11 @@ func CloneSource(datum *dataSource.Source) *dataSource.Source {
 	clone.EarliestDataTime = pointer.CloneTime(datum.EarliestDataTime)
 	clone.LatestDataTime = pointer.CloneTime(datum.LatestDataTime)
 	clone.LastImportTime = pointer.CloneTime(datum.LastImportTime)
+	clone.FailureHistory = CloneFailureArray(datum.FailureHistory)
+	clone.ConsecutiveFailureCount = pointer.CloneInt(datum.ConsecutiveFailureCount)
+	clone.ConsecutiveAuthenticationFailureCount = pointer.CloneInt(datum.ConsecutiveAuthenticationFailureCount)
+	clone.FirstConsecutiveFailureTime = pointer.CloneTime(datum.FirstConsecutiveFailureTime)
+	clone.SyncHistory = CloneSyncArray(datum.SyncHistory)
 	clone.CreatedTime = pointer.CloneTime(datum.CreatedTime)
 	clone.ModifiedTime = pointer.CloneTime(datum.ModifiedTime)
 	clone.Revision = pointer.CloneInt(datum.Revision)
@@ -236,6 +276,21 @@ func NewObjectFromSource(datum *dataSource.Source, objectFormat test.ObjectForma
 	if datum.LastImportTime != nil {
 		object["lastImportTime"] = test.NewObjectFromTime(*datum.LastImportTime, objectFormat)
 	}
+	if datum.FailureHistory != nil {
+		object["failureHistory"] = NewArrayFromFailureArray(datum.FailureHistory, objectFormat)
+	}
+	if datum.ConsecutiveFailureCount != nil {
+		object["consecutiveFailureCount"] = test.NewObjectFromInt(*datum.ConsecutiveFailureCount, objectFormat)
+	}
+	if datum.ConsecutiveAuthenticationFailureCount != nil {
+		object["consecutiveAuthenticationFailureCount"] = test.NewObjectFromInt(*datum.ConsecutiveAuthenticationFailureCount, objectFormat)
+	}
+	if datum.FirstConsecutiveFailureTime != nil {
+		object["firstConsecutiveFailureTime"] = test.NewObjectFromTime(*datum.FirstConsecutiveFailureTime, objectFormat)
+	}
+	if datum.SyncHistory != nil {
+		object["syncHistory"] = NewArrayFromSyncArray(datum.SyncHistory, objectFormat)
+	}
 	if datum.CreatedTime != nil {
 		object["createdTime"] = test.NewObjectFromTime(*datum.CreatedTime, objectFormat)
 	}
@@ -253,20 +308,25 @@ func MatchSource(datum *dataSource.Source) gomegaTypes.GomegaMatcher {
 		return gomega.BeNil()
 	}
 	return gomegaGstruct.PointTo(gomegaGstruct.MatchAllFields(gomegaGstruct.Fields{
-		"ID":                gomega.Equal(datum.ID),
-		"UserID":            gomega.Equal(datum.UserID),
-		"ProviderType":      gomega.Equal(datum.ProviderType),
-		"ProviderName":      gomega.Equal(datum.ProviderName),
-		"ProviderSessionID": gomega.Equal(datum.ProviderSessionID),
-		"State":             gomega.Equal(datum.State),
-		"Error":             gomega.Equal(datum.Error),
-		"DataSetIDs":        gomega.Equal(datum.DataSetIDs),
-		"EarliestDataTime":  test.MatchTime(datum.EarliestDataTime),
-		"LatestDataTime":    test.MatchTime(datum.LatestDataTime),
-		"LastImportTime":    test.MatchTime(datum.LastImportTime),
-		"CreatedTime":       test.MatchTime(datum.CreatedTime),
-		"ModifiedTime":      test.MatchTime(datum.ModifiedTime),
-		"Revision":          gomega.Equal(datum.Revision),
+		"ID":                                    gomega.Equal(datum.ID),
+		"UserID":                                gomega.Equal(datum.UserID),
+		"ProviderType":                          gomega.Equal(datum.ProviderType),
+		"ProviderName":                          gomega.Equal(datum.ProviderName),
+		"ProviderSessionID":                     gomega.Equal(datum.ProviderSessionID),
+		"State":                                 gomega.Equal(datum.State),
+		"Error":                                 gomega.Equal(datum.Error),
+		"DataSetIDs":                            gomega.Equal(datum.DataSetIDs),
+		"EarliestDataTime":                      test.MatchTime(datum.EarliestDataTime),
+		"LatestDataTime":                        test.MatchTime(datum.LatestDataTime),
+		"LastImportTime":                        test.MatchTime(datum.LastImportTime),
+		"FailureHistory":                        MatchFailureArray(datum.FailureHistory),
+		"ConsecutiveFailureCount":               gomega.Equal(datum.ConsecutiveFailureCount),
+		"ConsecutiveAuthenticationFailureCount": gomega.Equal(datum.ConsecutiveAuthenticationFailureCount),
+		"FirstConsecutiveFailureTime":           test.MatchTime(datum.FirstConsecutiveFailureTime),
+		"SyncHistory":                           MatchSyncArray(datum.SyncHistory),
+		"CreatedTime":                           test.MatchTime(datum.CreatedTime),
+		"ModifiedTime":                          test.MatchTime(datum.ModifiedTime),
+		"Revision":                              gomega.Equal(datum.Revision),
 	}))
 }
 
@@ -297,6 +357,94 @@ func MatchSourceArray(datum dataSource.SourceArray) gomegaTypes.GomegaMatcher {
 	return test.MatchArray(matchers)
 }
 
+func RandomFailure() *dataSource.Failure {
+	datum := dataSource.NewFailure()
+	datum.Time = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+	datum.Error = errorsTest.RandomSerializable()
+	datum.Authentication = pointer.FromBool(test.RandomBool())
+	return datum
+}
+
+func CloneFailure(datum *dataSource.Failure) *dataSource.Failure {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.NewFailure()
+	clone.Time = pointer.CloneTime(datum.Time)
+	clone.Error = errorsTest.CloneSerializable(datum.Error)
+	clone.Authentication = pointer.CloneBool(datum.Authentication)
+	return clone
+}
+
+func NewObjectFromFailure(datum *dataSource.Failure, objectFormat test.ObjectFormat) map[string]interface{} {
+	if datum == nil {
+		return nil
+	}
+	object := map[string]interface{}{}
+	if datum.Time != nil {
+		object["time"] = test.NewObjectFromTime(*datum.Time, objectFormat)
+	}
+	if datum.Error != nil {
+		object["error"] = errorsTest.NewObjectFromSerializable(datum.Error, objectFormat)
+	}
+	if datum.Authentication != nil {
+		object["authentication"] = test.NewObjectFromBool(*datum.Authentication, objectFormat)
+	}
+	return object
+}
+
+func MatchFailure(datum *dataSource.Failure) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	return gomegaGstruct.PointTo(gomegaGstruct.MatchAllFields(gomegaGstruct.Fields{
+		"Time":           test.MatchTime(datum.Time),
+		"Error":          gomega.Equal(datum.Error),
+		"Authentication": gomega.Equal(datum.Authentication),
+	}))
+}
+
+func RandomFailureArray(minimumLength int, maximumLength int) *dataSource.FailureArray {
+	datum := make(dataSource.FailureArray, test.RandomIntFromRange(minimumLength, maximumLength))
+	for index := range datum {
+		datum[index] = RandomFailure()
+	}
+	return &datum
+}
+
+func CloneFailureArray(datum *dataSource.FailureArray) *dataSource.FailureArray {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.FailureArray{}
+	for _, failure := range *datum {
+		clone = append(clone, CloneFailure(failure))
+	}
+	return &clone
+}
+
+func NewArrayFromFailureArray(datum *dataSource.FailureArray, objectFormat test.ObjectFormat) []interface{} {
+	if datum == nil {
+		return nil
+	}
+	array := []interface{}{}
+	for _, failure := range *datum {
+		array = append(array, NewObjectFromFailure(failure, objectFormat))
+	}
+	return array
+}
+
+func MatchFailureArray(datum *dataSource.FailureArray) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	matchers := []gomegaTypes.GomegaMatcher{}
+	for _, failure := range *datum {
+		matchers = append(matchers, MatchFailure(failure))
+	}
+	return gomegaGstruct.PointTo(test.MatchArray(matchers))
+}
+
 func RandomID() string {
 	return dataSource.NewID()
 }
@@ -0,0 +1,268 @@
+package test
+
+import (
+	"time"
+
+	"github.com/onsi/gomega"
+	gomegaGstruct "github.com/onsi/gomega/gstruct"
+	gomegaTypes "github.com/onsi/gomega/types"
+
+	dataSource "github.com/tidepool-org/platform/data/source"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/test"
+)
+
+func RandomSync() *dataSource.Sync {
+	datum := dataSource.NewSync()
+	datum.Time = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+	datum.Windows = RandomSyncWindowArray(1, 3)
+	datum.Error = errorsTest.RandomSerializable()
+	return datum
+}
+
+func CloneSync(datum *dataSource.Sync) *dataSource.Sync {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.NewSync()
+	clone.Time = pointer.CloneTime(datum.Time)
+	clone.Windows = CloneSyncWindowArray(datum.Windows)
+	clone.Error = errorsTest.CloneSerializable(datum.Error)
+	return clone
+}
+
+func NewObjectFromSync(datum *dataSource.Sync, objectFormat test.ObjectFormat) map[string]interface{} {
+	if datum == nil {
+		return nil
+	}
+	object := map[string]interface{}{}
+	if datum.Time != nil {
+		object["time"] = test.NewObjectFromTime(*datum.Time, objectFormat)
+	}
+	if datum.Windows != nil {
+		object["windows"] = NewArrayFromSyncWindowArray(datum.Windows, objectFormat)
+	}
+	if datum.Error != nil {
+		object["error"] = errorsTest.NewObjectFromSerializable(datum.Error, objectFormat)
+	}
+	return object
+}
+
+func MatchSync(datum *dataSource.Sync) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	return gomegaGstruct.PointTo(gomegaGstruct.MatchAllFields(gomegaGstruct.Fields{
+		"Time":    test.MatchTime(datum.Time),
+		"Windows": MatchSyncWindowArray(datum.Windows),
+		"Error":   gomega.Equal(datum.Error),
+	}))
+}
+
+func RandomSyncArray(minimumLength int, maximumLength int) *dataSource.SyncArray {
+	datum := make(dataSource.SyncArray, test.RandomIntFromRange(minimumLength, maximumLength))
+	for index := range datum {
+		datum[index] = RandomSync()
+	}
+	return &datum
+}
+
+func CloneSyncArray(datum *dataSource.SyncArray) *dataSource.SyncArray {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.SyncArray{}
+	for _, sync := range *datum {
+		clone = append(clone, CloneSync(sync))
+	}
+	return &clone
+}
+
+func NewArrayFromSyncArray(datum *dataSource.SyncArray, objectFormat test.ObjectFormat) []interface{} {
+	if datum == nil {
+		return nil
+	}
+	array := []interface{}{}
+	for _, sync := range *datum {
+		array = append(array, NewObjectFromSync(sync, objectFormat))
+	}
+	return array
+}
+
+func MatchSyncArray(datum *dataSource.SyncArray) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	matchers := []gomegaTypes.GomegaMatcher{}
+	for _, sync := range *datum {
+		matchers = append(matchers, MatchSync(sync))
+	}
+	return gomegaGstruct.PointTo(test.MatchArray(matchers))
+}
+
+func RandomSyncWindow() *dataSource.SyncWindow {
+	datum := dataSource.NewSyncWindow()
+	datum.StartTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now()))
+	datum.EndTime = pointer.FromTime(test.RandomTimeFromRange(*datum.StartTime, time.Now()))
+	datum.Requests = RandomSyncRequestArray(1, 3)
+	datum.TranslatedCount = pointer.FromInt(test.RandomIntFromRange(0, 1000))
+	datum.DeduplicatedCount = pointer.FromInt(test.RandomIntFromRange(0, *datum.TranslatedCount))
+	return datum
+}
+
+func CloneSyncWindow(datum *dataSource.SyncWindow) *dataSource.SyncWindow {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.NewSyncWindow()
+	clone.StartTime = pointer.CloneTime(datum.StartTime)
+	clone.EndTime = pointer.CloneTime(datum.EndTime)
+	clone.Requests = CloneSyncRequestArray(datum.Requests)
+	clone.TranslatedCount = pointer.CloneInt(datum.TranslatedCount)
+	clone.DeduplicatedCount = pointer.CloneInt(datum.DeduplicatedCount)
+	return clone
+}
+
+func NewObjectFromSyncWindow(datum *dataSource.SyncWindow, objectFormat test.ObjectFormat) map[string]interface{} {
+	if datum == nil {
+		return nil
+	}
+	object := map[string]interface{}{}
+	if datum.StartTime != nil {
+		object["startTime"] = test.NewObjectFromTime(*datum.StartTime, objectFormat)
+	}
+	if datum.EndTime != nil {
+		object["endTime"] = test.NewObjectFromTime(*datum.EndTime, objectFormat)
+	}
+	if datum.Requests != nil {
+		object["requests"] = NewArrayFromSyncRequestArray(datum.Requests, objectFormat)
+	}
+	if datum.TranslatedCount != nil {
+		object["translatedCount"] = test.NewObjectFromInt(*datum.TranslatedCount, objectFormat)
+	}
+	if datum.DeduplicatedCount != nil {
+		object["deduplicatedCount"] = test.NewObjectFromInt(*datum.DeduplicatedCount, objectFormat)
+	}
+	return object
+}
+
+func MatchSyncWindow(datum *dataSource.SyncWindow) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	return gomegaGstruct.PointTo(gomegaGstruct.MatchAllFields(gomegaGstruct.Fields{
+		"StartTime":         test.MatchTime(datum.StartTime),
+		"EndTime":           test.MatchTime(datum.EndTime),
+		"Requests":          gomega.Equal(datum.Requests),
+		"TranslatedCount":   gomega.Equal(datum.TranslatedCount),
+		"DeduplicatedCount": gomega.Equal(datum.DeduplicatedCount),
+	}))
+}
+
+func RandomSyncWindowArray(minimumLength int, maximumLength int) *dataSource.SyncWindowArray {
+	datum := make(dataSource.SyncWindowArray, test.RandomIntFromRange(minimumLength, maximumLength))
+	for index := range datum {
+		datum[index] = RandomSyncWindow()
+	}
+	return &datum
+}
+
+func CloneSyncWindowArray(datum *dataSource.SyncWindowArray) *dataSource.SyncWindowArray {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.SyncWindowArray{}
+	for _, window := range *datum {
+		clone = append(clone, CloneSyncWindow(window))
+	}
+	return &clone
+}
+
+func NewArrayFromSyncWindowArray(datum *dataSource.SyncWindowArray, objectFormat test.ObjectFormat) []interface{} {
+	if datum == nil {
+		return nil
+	}
+	array := []interface{}{}
+	for _, window := range *datum {
+		array = append(array, NewObjectFromSyncWindow(window, objectFormat))
+	}
+	return array
+}
+
+func MatchSyncWindowArray(datum *dataSource.SyncWindowArray) gomegaTypes.GomegaMatcher {
+	if datum == nil {
+		return gomega.BeNil()
+	}
+	matchers := []gomegaTypes.GomegaMatcher{}
+	for _, window := range *datum {
+		matchers = append(matchers, MatchSyncWindow(window))
+	}
+	return gomegaGstruct.PointTo(test.MatchArray(matchers))
+}
+
+func RandomSyncRequest() *dataSource.SyncRequest {
+	datum := dataSource.NewSyncRequest()
+	datum.Endpoint = pointer.FromString("/" + test.RandomStringFromRangeAndCharset(1, 16, test.CharsetAlphaNumeric))
+	datum.StatusCode = pointer.FromInt(test.RandomIntFromRange(200, 599))
+	datum.RecordCount = pointer.FromInt(test.RandomIntFromRange(0, 1000))
+	return datum
+}
+
+func CloneSyncRequest(datum *dataSource.SyncRequest) *dataSource.SyncRequest {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.NewSyncRequest()
+	clone.Endpoint = pointer.CloneString(datum.Endpoint)
+	clone.StatusCode = pointer.CloneInt(datum.StatusCode)
+	clone.RecordCount = pointer.CloneInt(datum.RecordCount)
+	return clone
+}
+
+func NewObjectFromSyncRequest(datum *dataSource.SyncRequest, objectFormat test.ObjectFormat) map[string]interface{} {
+	if datum == nil {
+		return nil
+	}
+	object := map[string]interface{}{}
+	if datum.Endpoint != nil {
+		object["endpoint"] = test.NewObjectFromString(*datum.Endpoint, objectFormat)
+	}
+	if datum.StatusCode != nil {
+		object["statusCode"] = test.NewObjectFromInt(*datum.StatusCode, objectFormat)
+	}
+	if datum.RecordCount != nil {
+		object["recordCount"] = test.NewObjectFromInt(*datum.RecordCount, objectFormat)
+	}
+	return object
+}
+
+func RandomSyncRequestArray(minimumLength int, maximumLength int) *dataSource.SyncRequestArray {
+	datum := make(dataSource.SyncRequestArray, test.RandomIntFromRange(minimumLength, maximumLength))
+	for index := range datum {
+		datum[index] = RandomSyncRequest()
+	}
+	return &datum
+}
+
+func CloneSyncRequestArray(datum *dataSource.SyncRequestArray) *dataSource.SyncRequestArray {
+	if datum == nil {
+		return nil
+	}
+	clone := dataSource.SyncRequestArray{}
+	for _, syncRequest := range *datum {
+		clone = append(clone, CloneSyncRequest(syncRequest))
+	}
+	return &clone
+}
+
+func NewArrayFromSyncRequestArray(datum *dataSource.SyncRequestArray, objectFormat test.ObjectFormat) []interface{} {
+	if datum == nil {
+		return nil
+	}
+	array := []interface{}{}
+	for _, syncRequest := range *datum {
+		array = append(array, NewObjectFromSyncRequest(syncRequest, objectFormat))
+	}
+	return array
+}
@@ -30,8 +30,11 @@ func (s *Store) EnsureIndexes() error {
 	}
 
 	err = summaryrepository.EnsureIndexes()
+	if err != nil {
+		return err
+	}
 
-	return err
+	return s.NewShareGrantRepository().EnsureIndexes()
 }
 
 func (s *Store) NewDataRepository() store.DataRepository {
@@ -45,3 +48,9 @@ func (s *Store) NewSummaryRepository() store.SummaryRepository {
 		s.Store.GetRepository("summary"),
 	}
 }
+
+func (s *Store) NewShareGrantRepository() store.ShareGrantRepository {
+	return &ShareGrantRepository{
+		s.Store.GetRepository("share_grants"),
+	}
+}
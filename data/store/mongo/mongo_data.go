@@ -862,26 +862,34 @@ func validateAndTranslateSelectors(selectors *data.Selectors) (bson.M, error) {
 
 	var selectorIDs []string
 	var selectorOriginIDs []string
+	var selectorRanges []bson.M
 	for _, selector := range *selectors {
 		if selector != nil {
 			if selector.ID != nil {
 				selectorIDs = append(selectorIDs, *selector.ID)
 			} else if selector.Origin != nil && selector.Origin.ID != nil {
 				selectorOriginIDs = append(selectorOriginIDs, *selector.Origin.ID)
+			} else if selector.Range != nil {
+				selectorRanges = append(selectorRanges, translateSelectorRange(selector.Range))
 			}
 		}
 	}
 
+	var selectorClauses []bson.M
+	if len(selectorIDs) > 0 {
+		selectorClauses = append(selectorClauses, bson.M{"id": bson.M{"$in": selectorIDs}})
+	}
+	if len(selectorOriginIDs) > 0 {
+		selectorClauses = append(selectorClauses, bson.M{"origin.id": bson.M{"$in": selectorOriginIDs}})
+	}
+	selectorClauses = append(selectorClauses, selectorRanges...)
+
+	// A range clause selects by type, so is never merged into the selector lest it replace the caller's type selector
 	selector := bson.M{}
-	if len(selectorIDs) > 0 && len(selectorOriginIDs) > 0 {
-		selector["$or"] = []bson.M{
-			{"id": bson.M{"$in": selectorIDs}},
-			{"origin.id": bson.M{"$in": selectorOriginIDs}},
-		}
-	} else if len(selectorIDs) > 0 {
-		selector["id"] = bson.M{"$in": selectorIDs}
-	} else if len(selectorOriginIDs) > 0 {
-		selector["origin.id"] = bson.M{"$in": selectorOriginIDs}
+	if len(selectorClauses) == 1 && len(selectorRanges) == 0 {
+		selector = selectorClauses[0]
+	} else if len(selectorClauses) > 0 {
+		selector["$or"] = selectorClauses
 	}
 
 	if len(selector) == 0 {
@@ -891,6 +899,17 @@ func validateAndTranslateSelectors(selectors *data.Selectors) (bson.M, error) {
 	return selector, nil
 }
 
+// translateSelectorRange matches time as either a date or, for older data, a string
+func translateSelectorRange(selectorRange *data.SelectorRange) bson.M {
+	return bson.M{
+		"type": bson.M{"$in": *selectorRange.Types},
+		"$or": []bson.M{
+			{"time": bson.M{"$gte": *selectorRange.StartTime, "$lt": *selectorRange.EndTime}},
+			{"time": bson.M{"$gte": selectorRange.StartTime.Format(time.RFC3339Nano), "$lt": selectorRange.EndTime.Format(time.RFC3339Nano)}},
+		},
+	}
+}
+
 func (d *DataRepository) GetCGMDataRange(ctx context.Context, id string, startTime time.Time, endTime time.Time) ([]*continuous.Continuous, error) {
 	var dataSetsOld []*continuous.Continuous
 	var dataSets []*continuous.Continuous
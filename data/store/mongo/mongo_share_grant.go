@@ -0,0 +1,225 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	dataShare "github.com/tidepool-org/platform/data/share"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+type ShareGrantRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (s *ShareGrantRepository) EnsureIndexes() error {
+	return s.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true).
+				SetName("ID"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "granteeUserId", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true).
+				SetName("UserIDGranteeUserID"),
+		},
+	})
+}
+
+func (s *ShareGrantRepository) ListUserGrants(ctx context.Context, userID string, pagination *page.Pagination) (dataShare.Grants, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "pagination": pagination})
+
+	grants := dataShare.Grants{}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"createdTime": -1})
+	cursor, err := s.Find(ctx, bson.M{"userId": userID}, opts)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("ListUserGrants")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list user share grants")
+	}
+
+	if err = cursor.All(ctx, &grants); err != nil {
+		return nil, errors.Wrap(err, "unable to decode user share grants")
+	}
+
+	if grants == nil {
+		grants = dataShare.Grants{}
+	}
+
+	return grants, nil
+}
+
+func (s *ShareGrantRepository) UpsertUserGrant(ctx context.Context, userID string, create *dataShare.GrantCreate) (*dataShare.Grant, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	grant, err := dataShare.NewGrant(userID, create)
+	if err != nil {
+		return nil, err
+	} else if err = structureValidator.New().Validate(grant); err != nil {
+		return nil, errors.Wrap(err, "share grant is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "granteeUserId": grant.GranteeUserID})
+
+	selector := bson.M{
+		"userId":        grant.UserID,
+		"granteeUserId": grant.GranteeUserID,
+	}
+	set := bson.M{}
+	unset := bson.M{}
+	if grant.Types != nil {
+		set["types"] = *grant.Types
+	} else {
+		unset["types"] = ""
+	}
+	if grant.StartTime != nil {
+		set["startTime"] = *grant.StartTime
+	} else {
+		unset["startTime"] = ""
+	}
+	if grant.EndTime != nil {
+		set["endTime"] = *grant.EndTime
+	} else {
+		unset["endTime"] = ""
+	}
+	update := bson.M{
+		"$setOnInsert": bson.M{"id": grant.ID, "createdTime": grant.CreatedTime},
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err = s.FindOneAndUpdate(ctx, selector, update, opts).Decode(grant)
+	logger.WithFields(log.Fields{"id": grant.ID, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UpsertUserGrant")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to upsert user share grant")
+	}
+
+	return grant, nil
+}
+
+func (s *ShareGrantRepository) GetUserGranteeGrant(ctx context.Context, userID string, granteeUserID string) (*dataShare.Grant, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if userID == "" {
+		return nil, errors.New("user id is missing")
+	}
+	if granteeUserID == "" {
+		return nil, errors.New("grantee user id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"userId": userID, "granteeUserId": granteeUserID})
+
+	grant, err := s.findOne(ctx, bson.M{"userId": userID, "granteeUserId": granteeUserID})
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetUserGranteeGrant")
+	return grant, err
+}
+
+func (s *ShareGrantRepository) GetGrant(ctx context.Context, id string) (*dataShare.Grant, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	grant, err := s.findOne(ctx, bson.M{"id": id})
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("GetGrant")
+	return grant, err
+}
+
+func (s *ShareGrantRepository) DeleteGrant(ctx context.Context, id string) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("context is missing")
+	}
+	if id == "" {
+		return false, errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	changeInfo, err := s.DeleteOne(ctx, bson.M{"id": id})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteGrant")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to delete share grant")
+	}
+
+	return changeInfo.DeletedCount > 0, nil
+}
+
+func (s *ShareGrantRepository) DeleteUserGrants(ctx context.Context, userID string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if userID == "" {
+		return errors.New("user id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("userId", userID)
+
+	selector := bson.M{
+		"$or": []bson.M{
+			{"userId": userID},
+			{"granteeUserId": userID},
+		},
+	}
+	changeInfo, err := s.DeleteMany(ctx, selector)
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteUserGrants")
+	if err != nil {
+		return errors.Wrap(err, "unable to delete user share grants")
+	}
+
+	return nil
+}
+
+func (s *ShareGrantRepository) findOne(ctx context.Context, selector bson.M) (*dataShare.Grant, error) {
+	grant := &dataShare.Grant{}
+	if err := s.FindOne(ctx, selector).Decode(grant); err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to get share grant")
+	}
+	return grant, nil
+}
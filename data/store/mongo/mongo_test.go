@@ -16,6 +16,7 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 
 	"github.com/tidepool-org/platform/data"
+	dataShare "github.com/tidepool-org/platform/data/share"
 	dataStore "github.com/tidepool-org/platform/data/store"
 	dataStoreMongo "github.com/tidepool-org/platform/data/store/mongo"
 	dataTest "github.com/tidepool-org/platform/data/test"
@@ -340,6 +341,51 @@ var _ = Describe("Mongo", func() {
 			})
 		})
 
+		Context("with a new share grant repository", func() {
+			var shareGrantRepository dataStore.ShareGrantRepository
+			var shareGrantCollection *mongo.Collection
+			var ctx context.Context
+
+			BeforeEach(func() {
+				shareGrantRepository = store.NewShareGrantRepository()
+				Expect(shareGrantRepository).ToNot(BeNil())
+				shareGrantCollection = store.GetCollection("share_grants")
+				ctx = log.NewContextWithLogger(context.Background(), logger)
+			})
+
+			AfterEach(func() {
+				shareGrantCollection.DeleteMany(context.Background(), bson.D{})
+			})
+
+			Context("DeleteUserGrants", func() {
+				var userID string
+				var otherUserID string
+
+				BeforeEach(func() {
+					userID = userTest.RandomID()
+					otherUserID = userTest.RandomID()
+					for _, grant := range [][]string{{userID, otherUserID}, {otherUserID, userID}, {otherUserID, userTest.RandomID()}} {
+						_, err := shareGrantRepository.UpsertUserGrant(ctx, grant[0], &dataShare.GrantCreate{GranteeUserID: pointer.FromString(grant[1])})
+						Expect(err).ToNot(HaveOccurred())
+					}
+				})
+
+				It("returns an error when the context is missing", func() {
+					Expect(shareGrantRepository.DeleteUserGrants(nil, userID)).To(MatchError("context is missing"))
+				})
+
+				It("returns an error when the user id is missing", func() {
+					Expect(shareGrantRepository.DeleteUserGrants(ctx, "")).To(MatchError("user id is missing"))
+				})
+
+				It("deletes the grants from and to the user", func() {
+					Expect(shareGrantRepository.DeleteUserGrants(ctx, userID)).To(Succeed())
+					Expect(shareGrantCollection.CountDocuments(context.Background(), bson.M{"$or": []bson.M{{"userId": userID}, {"granteeUserId": userID}}})).To(Equal(int64(0)))
+					Expect(shareGrantCollection.CountDocuments(context.Background(), bson.M{"userId": otherUserID})).To(Equal(int64(1)))
+				})
+			})
+		})
+
 		Context("with a new repository", func() {
 			BeforeEach(func() {
 				repository = store.NewDataRepository()
@@ -7,6 +7,7 @@ import (
 	"github.com/tidepool-org/platform/data/summary"
 
 	"github.com/tidepool-org/platform/data"
+	dataShare "github.com/tidepool-org/platform/data/share"
 	"github.com/tidepool-org/platform/data/types/blood/glucose/continuous"
 	"github.com/tidepool-org/platform/data/types/upload"
 	"github.com/tidepool-org/platform/page"
@@ -19,6 +20,7 @@ type Store interface {
 
 	NewDataRepository() DataRepository
 	NewSummaryRepository() SummaryRepository
+	NewShareGrantRepository() ShareGrantRepository
 }
 
 type DataRepository interface {
@@ -77,3 +79,9 @@ type SummaryRepository interface {
 	DistinctSummaryIDs(ctx context.Context) ([]string, error)
 	CreateSummaries(ctx context.Context, summaries []*summary.Summary) (int, error)
 }
+
+type ShareGrantRepository interface {
+	EnsureIndexes() error
+
+	dataShare.Accessor
+}
@@ -497,3 +497,51 @@ func (userSummary *Summary) Update(ctx context.Context, status *UserLastUpdated,
 
 	return nil
 }
+
+// Restrict removes the daily stats for days not wholly within the time range, inclusive of the start time and exclusive
+// of the end time, and limits the first and last data to the time range. The periods are calculated from the most recent
+// days regardless of the time range, so they are removed if the time range is specified. The last upload date is removed
+// if outside of the time range.
+func (userSummary *Summary) Restrict(startTime *time.Time, endTime *time.Time) {
+	if startTime == nil && endTime == nil {
+		return
+	}
+
+	dailyStats := make([]*Stats, 0, len(userSummary.DailyStats))
+	for _, stats := range userSummary.DailyStats {
+		if startTime != nil && stats.Date.Before(*startTime) {
+			continue
+		}
+		if endTime != nil && stats.Date.AddDate(0, 0, 1).After(*endTime) {
+			continue
+		}
+		dailyStats = append(dailyStats, stats)
+	}
+	userSummary.DailyStats = dailyStats
+	userSummary.Periods = make(map[string]*Period)
+
+	if startTime != nil {
+		if userSummary.FirstData != nil && userSummary.FirstData.Before(*startTime) {
+			userSummary.FirstData = pointer.CloneTime(startTime)
+		}
+		if userSummary.LastData != nil && userSummary.LastData.Before(*startTime) {
+			userSummary.FirstData = nil
+			userSummary.LastData = nil
+		}
+	}
+	if endTime != nil {
+		if userSummary.LastData != nil && !userSummary.LastData.Before(*endTime) {
+			userSummary.LastData = pointer.CloneTime(endTime)
+		}
+		if userSummary.FirstData != nil && !userSummary.FirstData.Before(*endTime) {
+			userSummary.FirstData = nil
+			userSummary.LastData = nil
+		}
+	}
+	if userSummary.LastUploadDate != nil {
+		if (startTime != nil && userSummary.LastUploadDate.Before(*startTime)) || (endTime != nil && !userSummary.LastUploadDate.Before(*endTime)) {
+			userSummary.LastUploadDate = nil
+		}
+	}
+	userSummary.TotalDays = pointer.FromInt(len(userSummary.DailyStats))
+}
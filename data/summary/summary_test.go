@@ -793,4 +793,50 @@ var _ = Describe("Summary", func() {
 			})
 		})
 	})
+
+	Context("Restrict", func() {
+		var userSummary *summary.Summary
+
+		BeforeEach(func() {
+			userSummary = summary.New(userID)
+			for day := 0; day < 5; day++ {
+				userSummary.DailyStats = append(userSummary.DailyStats, summary.NewStats(deviceID, datumTime.AddDate(0, 0, day)))
+			}
+			userSummary.Periods["14d"] = &summary.Period{}
+			userSummary.FirstData = pointer.FromTime(datumTime)
+			userSummary.LastData = pointer.FromTime(datumTime.AddDate(0, 0, 5).Add(-time.Minute))
+			userSummary.LastUploadDate = pointer.FromTime(datumTime.AddDate(0, 0, 5))
+			userSummary.TotalDays = pointer.FromInt(5)
+		})
+
+		It("does nothing if the time range is not specified", func() {
+			userSummary.Restrict(nil, nil)
+			Expect(userSummary.DailyStats).To(HaveLen(5))
+			Expect(userSummary.Periods).To(HaveLen(1))
+			Expect(*userSummary.TotalDays).To(Equal(5))
+		})
+
+		It("removes the daily stats and periods outside of the time range", func() {
+			startTime := datumTime.AddDate(0, 0, 1).Add(time.Hour)
+			endTime := datumTime.AddDate(0, 0, 4)
+			userSummary.Restrict(&startTime, &endTime)
+			Expect(userSummary.DailyStats).To(HaveLen(2))
+			Expect(userSummary.DailyStats[0].Date).To(Equal(datumTime.AddDate(0, 0, 2)))
+			Expect(userSummary.DailyStats[1].Date).To(Equal(datumTime.AddDate(0, 0, 3)))
+			Expect(userSummary.Periods).To(BeEmpty())
+			Expect(*userSummary.FirstData).To(Equal(startTime))
+			Expect(*userSummary.LastData).To(Equal(endTime))
+			Expect(userSummary.LastUploadDate).To(BeNil())
+			Expect(*userSummary.TotalDays).To(Equal(2))
+		})
+
+		It("removes the first and last data if the data is outside of the time range", func() {
+			startTime := datumTime.AddDate(0, 0, 10)
+			userSummary.Restrict(&startTime, nil)
+			Expect(userSummary.DailyStats).To(BeEmpty())
+			Expect(userSummary.FirstData).To(BeNil())
+			Expect(userSummary.LastData).To(BeNil())
+			Expect(*userSummary.TotalDays).To(Equal(0))
+		})
+	})
 })
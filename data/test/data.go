@@ -1,6 +1,8 @@
 package test
 
 import (
+	"time"
+
 	"github.com/tidepool-org/platform/data"
 	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/test"
@@ -33,6 +35,25 @@ func CloneSelectorOrigin(datum *data.SelectorOrigin) *data.SelectorOrigin {
 	return clone
 }
 
+func RandomSelectorRange() *data.SelectorRange {
+	datum := data.NewSelectorRange()
+	datum.Types = pointer.FromStringArray([]string{test.RandomStringFromArray([]string{"cbg", "food", "physicalActivity", "smbg"})})
+	datum.StartTime = pointer.FromTime(test.RandomTimeFromRange(test.RandomTimeMinimum(), time.Now().Add(-time.Hour)))
+	datum.EndTime = pointer.FromTime(test.RandomTimeFromRange(datum.StartTime.Add(time.Second), time.Now()))
+	return datum
+}
+
+func CloneSelectorRange(datum *data.SelectorRange) *data.SelectorRange {
+	if datum == nil {
+		return nil
+	}
+	clone := data.NewSelectorRange()
+	clone.Types = pointer.CloneStringArray(datum.Types)
+	clone.StartTime = pointer.CloneTime(datum.StartTime)
+	clone.EndTime = pointer.CloneTime(datum.EndTime)
+	return clone
+}
+
 func RandomSelector() *data.Selector {
 	datum := data.NewSelector()
 	if test.RandomBool() {
@@ -50,6 +71,7 @@ func CloneSelector(datum *data.Selector) *data.Selector {
 	clone := data.NewSelector()
 	clone.ID = pointer.CloneString(datum.ID)
 	clone.Origin = CloneSelectorOrigin(datum.Origin)
+	clone.Range = CloneSelectorRange(datum.Range)
 	return clone
 }
 
@@ -52,6 +52,10 @@ var types = []string{
 	dataTypesWater.Type,
 }
 
+func Types() []string {
+	return types
+}
+
 func NewDatum(parser structure.ObjectParser) data.Datum {
 	if !parser.Exists() {
 		return nil
@@ -0,0 +1,167 @@
+package dexcom
+
+import (
+	"strconv"
+
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	AlertNameFall          = "fall"
+	AlertNameFixedLow      = "fixedLow"
+	AlertNameHigh          = "high"
+	AlertNameLow           = "low"
+	AlertNameNoReadings    = "noReadings"
+	AlertNameOutOfRange    = "outOfRange"
+	AlertNameRise          = "rise"
+	AlertNameUnknown       = "unknown"
+	AlertNameUrgentLow     = "urgentLow"
+	AlertNameUrgentLowSoon = "urgentLowSoon"
+
+	AlertStateActiveAlarming = "activeAlarming"
+	AlertStateActiveSnoozed  = "activeSnoozed"
+	AlertStateInactive       = "inactive"
+	AlertStateUnknown        = "unknown"
+)
+
+func AlertNames() []string {
+	return []string{
+		AlertNameFall,
+		AlertNameFixedLow,
+		AlertNameHigh,
+		AlertNameLow,
+		AlertNameNoReadings,
+		AlertNameOutOfRange,
+		AlertNameRise,
+		AlertNameUnknown,
+		AlertNameUrgentLow,
+		AlertNameUrgentLowSoon,
+	}
+}
+
+func AlertStates() []string {
+	return []string{
+		AlertStateActiveAlarming,
+		AlertStateActiveSnoozed,
+		AlertStateInactive,
+		AlertStateUnknown,
+	}
+}
+
+// AlertsResponse is only available with the v3 API
+type AlertsResponse struct {
+	RecordType    *string `json:"recordType,omitempty"`
+	RecordVersion *string `json:"recordVersion,omitempty"`
+	UserID        *string `json:"userId,omitempty"`
+	Alerts        *Alerts `json:"records,omitempty"`
+}
+
+func ParseAlertsResponse(parser structure.ObjectParser) *AlertsResponse {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewAlertsResponse()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewAlertsResponse() *AlertsResponse {
+	return &AlertsResponse{}
+}
+
+func (a *AlertsResponse) Parse(parser structure.ObjectParser) {
+	a.RecordType = parser.String("recordType")
+	a.RecordVersion = parser.String("recordVersion")
+	a.UserID = parser.String("userId")
+	a.Alerts = ParseAlerts(parser.WithReferenceArrayParser("records"))
+}
+
+func (a *AlertsResponse) Validate(validator structure.Validator) {
+	if alertsValidator := validator.WithReference("records"); a.Alerts != nil {
+		a.Alerts.Validate(alertsValidator)
+	} else {
+		alertsValidator.ReportError(structureValidator.ErrorValueNotExists())
+	}
+}
+
+type Alerts []*Alert
+
+func ParseAlerts(parser structure.ArrayParser) *Alerts {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewAlerts()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewAlerts() *Alerts {
+	return &Alerts{}
+}
+
+func (a *Alerts) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*a = append(*a, ParseAlert(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (a *Alerts) Validate(validator structure.Validator) {
+	for index, alert := range *a {
+		if alertValidator := validator.WithReference(strconv.Itoa(index)); alert != nil {
+			alert.Validate(alertValidator)
+		} else {
+			alertValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+type Alert struct {
+	RecordID              *string `json:"recordId,omitempty"`
+	SystemTime            *Time   `json:"systemTime,omitempty"`
+	DisplayTime           *Time   `json:"displayTime,omitempty"`
+	AlertName             *string `json:"alertName,omitempty"`
+	AlertState            *string `json:"alertState,omitempty"`
+	TransmitterID         *string `json:"transmitterId,omitempty"`
+	TransmitterGeneration *string `json:"transmitterGeneration,omitempty"`
+	DisplayDevice         *string `json:"displayDevice,omitempty"`
+	DisplayApp            *string `json:"displayApp,omitempty"`
+}
+
+func ParseAlert(parser structure.ObjectParser) *Alert {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewAlert()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewAlert() *Alert {
+	return &Alert{}
+}
+
+func (a *Alert) Parse(parser structure.ObjectParser) {
+	a.RecordID = parser.String("recordId")
+	a.SystemTime = TimeFromRaw(parser.ForgivingTime("systemTime", TimeFormat))
+	a.DisplayTime = TimeFromRaw(parser.ForgivingTime("displayTime", TimeFormat))
+	a.AlertName = parser.String("alertName")
+	a.AlertState = parser.String("alertState")
+	a.TransmitterID = parser.String("transmitterId")
+	a.TransmitterGeneration = parser.String("transmitterGeneration")
+	a.DisplayDevice = parser.String("displayDevice")
+	a.DisplayApp = parser.String("displayApp")
+}
+
+func (a *Alert) Validate(validator structure.Validator) {
+	validator = validator.WithMeta(a)
+	validator.String("recordId", a.RecordID).Exists().NotEmpty()
+	validator.Time("systemTime", a.SystemTime.Raw()).Exists().NotZero().BeforeNow(SystemTimeNowThreshold)
+	validator.Time("displayTime", a.DisplayTime.Raw()).Exists().NotZero()
+	validator.String("alertName", a.AlertName).Exists().OneOf(AlertNames()...)
+	validator.String("alertState", a.AlertState).Exists().OneOf(AlertStates()...)
+	validator.String("transmitterId", a.TransmitterID).Using(TransmitterIDValidator)
+	validator.String("transmitterGeneration", a.TransmitterGeneration).OneOf(DeviceTransmitterGenerations()...)
+	validator.String("displayDevice", a.DisplayDevice).OneOf(DeviceDisplayDevices()...)
+	validator.String("displayApp", a.DisplayApp).NotEmpty()
+}
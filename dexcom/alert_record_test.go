@@ -0,0 +1,26 @@
+package dexcom_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/dexcom"
+)
+
+var _ = Describe("AlertRecord", func() {
+	It("AlertNameFixedLow is expected", func() {
+		Expect(dexcom.AlertNameFixedLow).To(Equal("fixedLow"))
+	})
+
+	It("AlertStateActiveAlarming is expected", func() {
+		Expect(dexcom.AlertStateActiveAlarming).To(Equal("activeAlarming"))
+	})
+
+	It("AlertNames returns expected", func() {
+		Expect(dexcom.AlertNames()).To(Equal([]string{"fall", "fixedLow", "high", "low", "noReadings", "outOfRange", "rise", "unknown", "urgentLow", "urgentLowSoon"}))
+	})
+
+	It("AlertStates returns expected", func() {
+		Expect(dexcom.AlertStates()).To(Equal([]string{"activeAlarming", "activeSnoozed", "inactive", "unknown"}))
+	})
+})
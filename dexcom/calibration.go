@@ -22,7 +22,10 @@ func CalibrationUnits() []string {
 }
 
 type CalibrationsResponse struct {
-	Calibrations *Calibrations `json:"calibrations,omitempty"`
+	RecordType    *string       `json:"recordType,omitempty"`
+	RecordVersion *string       `json:"recordVersion,omitempty"`
+	UserID        *string       `json:"userId,omitempty"`
+	Calibrations  *Calibrations `json:"calibrations,omitempty"`
 }
 
 func ParseCalibrationsResponse(parser structure.ObjectParser) *CalibrationsResponse {
@@ -39,17 +42,31 @@ func NewCalibrationsResponse() *CalibrationsResponse {
 }
 
 func (c *CalibrationsResponse) Parse(parser structure.ObjectParser) {
-	c.Calibrations = ParseCalibrations(parser.WithReferenceArrayParser("calibrations"))
+	c.RecordType = parser.String("recordType")
+	c.RecordVersion = parser.String("recordVersion")
+	c.UserID = parser.String("userId")
+	c.Calibrations = ParseCalibrations(parser.WithReferenceArrayParser(c.calibrationsReference()))
 }
 
 func (c *CalibrationsResponse) Validate(validator structure.Validator) {
-	if calibrationsValidator := validator.WithReference("calibrations"); c.Calibrations != nil {
+	if calibrationsValidator := validator.WithReference(c.calibrationsReference()); c.Calibrations != nil {
 		c.Calibrations.Validate(calibrationsValidator)
 	} else {
 		calibrationsValidator.ReportError(structureValidator.ErrorValueNotExists())
 	}
 }
 
+func (c *CalibrationsResponse) IsV3() bool {
+	return c.RecordVersion != nil && *c.RecordVersion == RecordVersionV3
+}
+
+func (c *CalibrationsResponse) calibrationsReference() string {
+	if c.IsV3() {
+		return "records"
+	}
+	return "calibrations"
+}
+
 type Calibrations []*Calibration
 
 func ParseCalibrations(parser structure.ArrayParser) *Calibrations {
@@ -82,11 +99,16 @@ func (c *Calibrations) Validate(validator structure.Validator) {
 }
 
 type Calibration struct {
-	SystemTime    *Time    `json:"systemTime,omitempty"`
-	DisplayTime   *Time    `json:"displayTime,omitempty"`
-	Unit          *string  `json:"unit,omitempty"`
-	Value         *float64 `json:"value,omitempty"`
-	TransmitterID *string  `json:"transmitterId,omitempty"`
+	RecordID              *string  `json:"recordId,omitempty"`
+	SystemTime            *Time    `json:"systemTime,omitempty"`
+	DisplayTime           *Time    `json:"displayTime,omitempty"`
+	Unit                  *string  `json:"unit,omitempty"`
+	Value                 *float64 `json:"value,omitempty"`
+	TransmitterID         *string  `json:"transmitterId,omitempty"`
+	TransmitterTicks      *int     `json:"transmitterTicks,omitempty"`
+	TransmitterGeneration *string  `json:"transmitterGeneration,omitempty"`
+	DisplayDevice         *string  `json:"displayDevice,omitempty"`
+	DisplayApp            *string  `json:"displayApp,omitempty"`
 }
 
 func ParseCalibration(parser structure.ObjectParser) *Calibration {
@@ -103,11 +125,16 @@ func NewCalibration() *Calibration {
 }
 
 func (c *Calibration) Parse(parser structure.ObjectParser) {
+	c.RecordID = parser.String("recordId")
 	c.SystemTime = TimeFromRaw(parser.Time("systemTime", TimeFormat))
 	c.DisplayTime = TimeFromRaw(parser.Time("displayTime", TimeFormat))
 	c.Unit = parser.String("unit")
 	c.Value = parser.Float64("value")
 	c.TransmitterID = parser.String("transmitterId")
+	c.TransmitterTicks = parser.Int("transmitterTicks")
+	c.TransmitterGeneration = parser.String("transmitterGeneration")
+	c.DisplayDevice = parser.String("displayDevice")
+	c.DisplayApp = parser.String("displayApp")
 }
 
 func (c *Calibration) Validate(validator structure.Validator) {
@@ -122,4 +149,8 @@ func (c *Calibration) Validate(validator structure.Validator) {
 		}
 	}
 	validator.String("transmitterId", c.TransmitterID).Using(TransmitterIDValidator)
+	validator.Int("transmitterTicks", c.TransmitterTicks).GreaterThanOrEqualTo(EGVTransmitterTickMinimum)
+	validator.String("transmitterGeneration", c.TransmitterGeneration).OneOf(DeviceTransmitterGenerations()...)
+	validator.String("displayDevice", c.DisplayDevice).OneOf(DeviceDisplayDevices()...)
+	validator.String("displayApp", c.DisplayApp).NotEmpty()
 }
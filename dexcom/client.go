@@ -8,7 +8,11 @@ import (
 )
 
 type Client interface {
+	APIVersion() string
+
+	GetAlerts(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*AlertsResponse, error)
 	GetCalibrations(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*CalibrationsResponse, error)
+	GetDataRange(ctx context.Context, tokenSource oauth.TokenSource) (*DataRangeResponse, error)
 	GetDevices(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*DevicesResponse, error)
 	GetEGVs(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*EGVsResponse, error)
 	GetEvents(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*EventsResponse, error)
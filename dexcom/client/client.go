@@ -6,21 +6,28 @@ import (
 
 	"golang.org/x/oauth2"
 
-	"github.com/tidepool-org/platform/client"
 	"github.com/tidepool-org/platform/dexcom"
+	dexcomProvider "github.com/tidepool-org/platform/dexcom/provider"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/log"
 	"github.com/tidepool-org/platform/oauth"
-	oauthClient "github.com/tidepool-org/platform/oauth/client"
+	providerClient "github.com/tidepool-org/platform/provider/client"
 	"github.com/tidepool-org/platform/request"
 )
 
 type Client struct {
-	client *oauthClient.Client
+	client     *providerClient.Client
+	apiVersion string
 }
 
-func New(cfg *client.Config, tknSrcSrc oauth.TokenSourceSource) (*Client, error) {
-	clnt, err := oauthClient.New(cfg, tknSrcSrc)
+func New(cfg *Config, tknSrcSrc oauth.TokenSourceSource) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+
+	clnt, err := providerClient.New(dexcomProvider.ProviderName, cfg.Config, tknSrcSrc)
 	if err != nil {
 		return nil, err
 	}
@@ -29,22 +36,53 @@ func New(cfg *client.Config, tknSrcSrc oauth.TokenSourceSource) (*Client, error)
 	oauth2.RegisterBrokenAuthHeaderProvider(cfg.Address)
 
 	return &Client{
-		client: clnt,
+		client:     clnt,
+		apiVersion: cfg.APIVersion,
 	}, nil
 }
 
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+func (c *Client) GetAlerts(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*dexcom.AlertsResponse, error) {
+	if c.apiVersion != dexcom.APIVersionV3 {
+		return nil, errors.New("alerts are not supported by api version")
+	}
+
+	alertsResponse := &dexcom.AlertsResponse{}
+	if err := c.sendDexcomRequest(ctx, &startTime, &endTime, "GET", c.constructURL("alerts"), alertsResponse, tokenSource); err != nil {
+		return nil, errors.Wrap(err, "unable to get alerts")
+	}
+
+	return alertsResponse, nil
+}
+
 func (c *Client) GetCalibrations(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*dexcom.CalibrationsResponse, error) {
 	calibrationsResponse := &dexcom.CalibrationsResponse{}
-	if err := c.sendDexcomRequest(ctx, startTime, endTime, "GET", c.client.ConstructURL("p", "v2", "users", "self", "calibrations"), calibrationsResponse, tokenSource); err != nil {
+	if err := c.sendDexcomRequest(ctx, &startTime, &endTime, "GET", c.constructURL("calibrations"), calibrationsResponse, tokenSource); err != nil {
 		return nil, errors.Wrap(err, "unable to get calibrations")
 	}
 
 	return calibrationsResponse, nil
 }
 
+func (c *Client) GetDataRange(ctx context.Context, tokenSource oauth.TokenSource) (*dexcom.DataRangeResponse, error) {
+	if c.apiVersion != dexcom.APIVersionV3 {
+		return nil, errors.New("data range is not supported by api version")
+	}
+
+	dataRangeResponse := &dexcom.DataRangeResponse{}
+	if err := c.sendDexcomRequest(ctx, nil, nil, "GET", c.constructURL("dataRange"), dataRangeResponse, tokenSource); err != nil {
+		return nil, errors.Wrap(err, "unable to get data range")
+	}
+
+	return dataRangeResponse, nil
+}
+
 func (c *Client) GetDevices(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*dexcom.DevicesResponse, error) {
 	devicesResponse := &dexcom.DevicesResponse{}
-	if err := c.sendDexcomRequest(ctx, startTime, endTime, "GET", c.client.ConstructURL("p", "v2", "users", "self", "devices"), devicesResponse, tokenSource); err != nil {
+	if err := c.sendDexcomRequest(ctx, &startTime, &endTime, "GET", c.constructURL("devices"), devicesResponse, tokenSource); err != nil {
 		return nil, errors.Wrap(err, "unable to get devices")
 	}
 
@@ -53,7 +91,7 @@ func (c *Client) GetDevices(ctx context.Context, startTime time.Time, endTime ti
 
 func (c *Client) GetEGVs(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*dexcom.EGVsResponse, error) {
 	egvsResponse := &dexcom.EGVsResponse{}
-	if err := c.sendDexcomRequest(ctx, startTime, endTime, "GET", c.client.ConstructURL("p", "v2", "users", "self", "egvs"), egvsResponse, tokenSource); err != nil {
+	if err := c.sendDexcomRequest(ctx, &startTime, &endTime, "GET", c.constructURL("egvs"), egvsResponse, tokenSource); err != nil {
 		return nil, errors.Wrap(err, "unable to get egvs")
 	}
 
@@ -62,20 +100,26 @@ func (c *Client) GetEGVs(ctx context.Context, startTime time.Time, endTime time.
 
 func (c *Client) GetEvents(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (*dexcom.EventsResponse, error) {
 	eventsResponse := &dexcom.EventsResponse{}
-	if err := c.sendDexcomRequest(ctx, startTime, endTime, "GET", c.client.ConstructURL("p", "v2", "users", "self", "events"), eventsResponse, tokenSource); err != nil {
+	if err := c.sendDexcomRequest(ctx, &startTime, &endTime, "GET", c.constructURL("events"), eventsResponse, tokenSource); err != nil {
 		return nil, errors.Wrap(err, "unable to get events")
 	}
 
 	return eventsResponse, nil
 }
 
-func (c *Client) sendDexcomRequest(ctx context.Context, startTime time.Time, endTime time.Time, method string, url string, responseBody interface{}, tokenSource oauth.TokenSource) error {
+func (c *Client) constructURL(resource string) string {
+	return c.client.ConstructURL("p", c.apiVersion, "users", "self", resource)
+}
+
+func (c *Client) sendDexcomRequest(ctx context.Context, startTime *time.Time, endTime *time.Time, method string, url string, responseBody interface{}, tokenSource oauth.TokenSource) error {
 	now := time.Now()
 
-	url = c.client.AppendURLQuery(url, map[string]string{
-		"startDate": startTime.UTC().Format(dexcom.TimeFormat),
-		"endDate":   endTime.UTC().Format(dexcom.TimeFormat),
-	})
+	if startTime != nil && endTime != nil {
+		url = c.client.AppendURLQuery(url, map[string]string{
+			"startDate": startTime.UTC().Format(dexcom.TimeFormat),
+			"endDate":   endTime.UTC().Format(dexcom.TimeFormat),
+		})
+	}
 
 	err := c.client.SendOAuthRequest(ctx, method, url, nil, nil, responseBody, tokenSource)
 	if oauth.IsAccessTokenError(err) {
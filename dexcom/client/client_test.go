@@ -11,7 +11,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/ghttp"
 
-	"github.com/tidepool-org/platform/client"
 	"github.com/tidepool-org/platform/dexcom"
 	dexcomClient "github.com/tidepool-org/platform/dexcom/client"
 	dexcomTest "github.com/tidepool-org/platform/dexcom/test"
@@ -34,12 +33,12 @@ func prepareEGVsResponse(egvsResponse *dexcom.EGVsResponse) *dexcom.EGVsResponse
 
 var _ = Describe("Client", func() {
 	var userAgent string
-	var config *client.Config
+	var config *dexcomClient.Config
 	var tokenSourceSource *oauthTest.TokenSourceSource
 
 	BeforeEach(func() {
 		userAgent = testHttp.NewUserAgent()
-		config = client.NewConfig()
+		config = dexcomClient.NewConfig()
 		config.UserAgent = userAgent
 		tokenSourceSource = oauthTest.NewTokenSourceSource()
 	})
@@ -66,6 +65,13 @@ var _ = Describe("Client", func() {
 			Expect(clnt).To(BeNil())
 		})
 
+		It("returns an error when api version is invalid", func() {
+			config.APIVersion = "v1"
+			clnt, err := dexcomClient.New(config, tokenSourceSource)
+			Expect(err).To(MatchError("config is invalid; api version is invalid"))
+			Expect(clnt).To(BeNil())
+		})
+
 		It("returns an error when token source source is missing", func() {
 			clnt, err := dexcomClient.New(config, nil)
 			Expect(err).To(MatchError("token source source is missing"))
@@ -75,6 +81,14 @@ var _ = Describe("Client", func() {
 		It("returns successfully", func() {
 			Expect(dexcomClient.New(config, tokenSourceSource)).ToNot(BeNil())
 		})
+
+		It("returns successfully with the api version", func() {
+			config.APIVersion = dexcom.APIVersionV3
+			clnt, err := dexcomClient.New(config, tokenSourceSource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clnt).ToNot(BeNil())
+			Expect(clnt.APIVersion()).To(Equal(dexcom.APIVersionV3))
+		})
 	})
 
 	Context("with started server and new client", func() {
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"github.com/tidepool-org/platform/config"
+	"github.com/tidepool-org/platform/dexcom"
+	"github.com/tidepool-org/platform/errors"
+	providerClient "github.com/tidepool-org/platform/provider/client"
+)
+
+type Config struct {
+	*providerClient.Config
+	APIVersion string
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Config:     providerClient.NewConfig(),
+		APIVersion: dexcom.APIVersionV2,
+	}
+}
+
+func (c *Config) Load(configReporter config.Reporter) error {
+	if err := c.Config.Load(configReporter); err != nil {
+		return err
+	}
+
+	c.APIVersion = configReporter.GetWithDefault("api_version", c.APIVersion)
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+
+	switch c.APIVersion {
+	case dexcom.APIVersionV2, dexcom.APIVersionV3:
+	default:
+		return errors.New("api version is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package dexcom
+
+import (
+	"time"
+
+	"github.com/tidepool-org/platform/structure"
+)
+
+// DataRangeResponse is only available with the v3 API
+type DataRangeResponse struct {
+	RecordType    *string    `json:"recordType,omitempty"`
+	RecordVersion *string    `json:"recordVersion,omitempty"`
+	UserID        *string    `json:"userId,omitempty"`
+	Calibrations  *DataRange `json:"calibrations,omitempty"`
+	EGVs          *DataRange `json:"egvs,omitempty"`
+	Events        *DataRange `json:"events,omitempty"`
+}
+
+func ParseDataRangeResponse(parser structure.ObjectParser) *DataRangeResponse {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewDataRangeResponse()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewDataRangeResponse() *DataRangeResponse {
+	return &DataRangeResponse{}
+}
+
+func (d *DataRangeResponse) Parse(parser structure.ObjectParser) {
+	d.RecordType = parser.String("recordType")
+	d.RecordVersion = parser.String("recordVersion")
+	d.UserID = parser.String("userId")
+	d.Calibrations = ParseDataRange(parser.WithReferenceObjectParser("calibrations"))
+	d.EGVs = ParseDataRange(parser.WithReferenceObjectParser("egvs"))
+	d.Events = ParseDataRange(parser.WithReferenceObjectParser("events"))
+}
+
+func (d *DataRangeResponse) Validate(validator structure.Validator) {
+	if d.Calibrations != nil {
+		d.Calibrations.Validate(validator.WithReference("calibrations"))
+	}
+	if d.EGVs != nil {
+		d.EGVs.Validate(validator.WithReference("egvs"))
+	}
+	if d.Events != nil {
+		d.Events.Validate(validator.WithReference("events"))
+	}
+}
+
+// StartTime returns the earliest system time across all data ranges, or nil if there is no data
+func (d *DataRangeResponse) StartTime() *time.Time {
+	var startTime *time.Time
+	for _, dataRange := range []*DataRange{d.Calibrations, d.EGVs, d.Events} {
+		if dataRange != nil && dataRange.Start != nil {
+			if systemTime := dataRange.Start.SystemTime.Raw(); systemTime != nil && (startTime == nil || systemTime.Before(*startTime)) {
+				startTime = systemTime
+			}
+		}
+	}
+	return startTime
+}
+
+// EndTime returns the latest system time across all data ranges, or nil if there is no data
+func (d *DataRangeResponse) EndTime() *time.Time {
+	var endTime *time.Time
+	for _, dataRange := range []*DataRange{d.Calibrations, d.EGVs, d.Events} {
+		if dataRange != nil && dataRange.End != nil {
+			if systemTime := dataRange.End.SystemTime.Raw(); systemTime != nil && (endTime == nil || systemTime.After(*endTime)) {
+				endTime = systemTime
+			}
+		}
+	}
+	return endTime
+}
+
+type DataRange struct {
+	Start *DataRangeMoment `json:"start,omitempty"`
+	End   *DataRangeMoment `json:"end,omitempty"`
+}
+
+func ParseDataRange(parser structure.ObjectParser) *DataRange {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewDataRange()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewDataRange() *DataRange {
+	return &DataRange{}
+}
+
+func (d *DataRange) Parse(parser structure.ObjectParser) {
+	d.Start = ParseDataRangeMoment(parser.WithReferenceObjectParser("start"))
+	d.End = ParseDataRangeMoment(parser.WithReferenceObjectParser("end"))
+}
+
+func (d *DataRange) Validate(validator structure.Validator) {
+	if d.Start != nil {
+		d.Start.Validate(validator.WithReference("start"))
+	}
+	if d.End != nil {
+		d.End.Validate(validator.WithReference("end"))
+	}
+}
+
+type DataRangeMoment struct {
+	SystemTime  *Time `json:"systemTime,omitempty"`
+	DisplayTime *Time `json:"displayTime,omitempty"`
+}
+
+func ParseDataRangeMoment(parser structure.ObjectParser) *DataRangeMoment {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewDataRangeMoment()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewDataRangeMoment() *DataRangeMoment {
+	return &DataRangeMoment{}
+}
+
+func (d *DataRangeMoment) Parse(parser structure.ObjectParser) {
+	d.SystemTime = TimeFromRaw(parser.ForgivingTime("systemTime", TimeFormat))
+	d.DisplayTime = TimeFromRaw(parser.ForgivingTime("displayTime", TimeFormat))
+}
+
+func (d *DataRangeMoment) Validate(validator structure.Validator) {
+	validator.Time("systemTime", d.SystemTime.Raw()).Exists().NotZero()
+	validator.Time("displayTime", d.DisplayTime.Raw()).Exists().NotZero()
+}
@@ -0,0 +1,43 @@
+package dexcom_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/dexcom"
+	"github.com/tidepool-org/platform/pointer"
+)
+
+var _ = Describe("DataRange", func() {
+	Context("DataRangeResponse", func() {
+		var now time.Time
+		var response *dexcom.DataRangeResponse
+
+		newDataRange := func(start time.Time, end time.Time) *dexcom.DataRange {
+			return &dexcom.DataRange{
+				Start: &dexcom.DataRangeMoment{SystemTime: dexcom.TimeFromRaw(pointer.FromTime(start)), DisplayTime: dexcom.TimeFromRaw(pointer.FromTime(start))},
+				End:   &dexcom.DataRangeMoment{SystemTime: dexcom.TimeFromRaw(pointer.FromTime(end)), DisplayTime: dexcom.TimeFromRaw(pointer.FromTime(end))},
+			}
+		}
+
+		BeforeEach(func() {
+			now = time.Now().Truncate(time.Second)
+			response = dexcom.NewDataRangeResponse()
+		})
+
+		It("returns nil start and end times if there is no data", func() {
+			Expect(response.StartTime()).To(BeNil())
+			Expect(response.EndTime()).To(BeNil())
+		})
+
+		It("returns the earliest start time and latest end time", func() {
+			response.Calibrations = newDataRange(now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+			response.EGVs = newDataRange(now.Add(-72*time.Hour), now.Add(-time.Hour))
+			response.Events = newDataRange(now.Add(-36*time.Hour), now.Add(-2*time.Hour))
+			Expect(response.StartTime()).To(Equal(pointer.FromTime(now.Add(-72 * time.Hour))))
+			Expect(response.EndTime()).To(Equal(pointer.FromTime(now.Add(-time.Hour))))
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/structure"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 )
@@ -23,6 +24,7 @@ const (
 	DeviceTransmitterGenerationG5    = "g5"
 	DeviceTransmitterGenerationG6    = "g6"
 	DeviceTransmitterGenerationG6Pro = "g6 pro"
+	DeviceTransmitterGenerationG7    = "g7"
 )
 
 func DeviceDisplayDevices() []string {
@@ -41,11 +43,15 @@ func DeviceTransmitterGenerations() []string {
 		DeviceTransmitterGenerationG5,
 		DeviceTransmitterGenerationG6,
 		DeviceTransmitterGenerationG6Pro,
+		DeviceTransmitterGenerationG7,
 	}
 }
 
 type DevicesResponse struct {
-	Devices *Devices `json:"devices,omitempty"`
+	RecordType    *string  `json:"recordType,omitempty"`
+	RecordVersion *string  `json:"recordVersion,omitempty"`
+	UserID        *string  `json:"userId,omitempty"`
+	Devices       *Devices `json:"devices,omitempty"`
 }
 
 func ParseDevicesResponse(parser structure.ObjectParser) *DevicesResponse {
@@ -62,11 +68,21 @@ func NewDevicesResponse() *DevicesResponse {
 }
 
 func (d *DevicesResponse) Parse(parser structure.ObjectParser) {
-	d.Devices = ParseDevices(parser.WithReferenceArrayParser("devices"))
+	d.RecordType = parser.String("recordType")
+	d.RecordVersion = parser.String("recordVersion")
+	d.UserID = parser.String("userId")
+	d.Devices = ParseDevices(parser.WithReferenceArrayParser(d.devicesReference()))
+	if d.IsV3() && d.Devices != nil {
+		for _, device := range *d.Devices {
+			if device != nil {
+				device.recordVersion = pointer.CloneString(d.RecordVersion)
+			}
+		}
+	}
 }
 
 func (d *DevicesResponse) Validate(validator structure.Validator) {
-	if devicesValidator := validator.WithReference("devices"); d.Devices != nil {
+	if devicesValidator := validator.WithReference(d.devicesReference()); d.Devices != nil {
 		d.Devices.Validate(devicesValidator)
 	} else {
 		devicesValidator.ReportError(structureValidator.ErrorValueNotExists())
@@ -75,10 +91,21 @@ func (d *DevicesResponse) Validate(validator structure.Validator) {
 
 func (d *DevicesResponse) Normalize(normalizer structure.Normalizer) {
 	if d.Devices != nil {
-		d.Devices.Normalize(normalizer.WithReference("devices"))
+		d.Devices.Normalize(normalizer.WithReference(d.devicesReference()))
 	}
 }
 
+func (d *DevicesResponse) IsV3() bool {
+	return d.RecordVersion != nil && *d.RecordVersion == RecordVersionV3
+}
+
+func (d *DevicesResponse) devicesReference() string {
+	if d.IsV3() {
+		return "records"
+	}
+	return "devices"
+}
+
 type Devices []*Device
 
 func ParseDevices(parser structure.ArrayParser) *Devices {
@@ -132,6 +159,9 @@ type Device struct {
 	Is24HourMode          *bool           `json:"is24HourMode,omitempty" yaml:"is24HourMode,omitempty"`
 	DisplayTimeOffset     *int            `json:"displayTimeOffset,omitempty" yaml:"displayTimeOffset,omitempty"`
 	SystemTimeOffset      *int            `json:"systemTimeOffset,omitempty" yaml:"systemTimeOffset,omitempty"`
+	DisplayApp            *string         `json:"displayApp,omitempty" yaml:"displayApp,omitempty"`
+
+	recordVersion *string
 }
 
 func ParseDevice(parser structure.ObjectParser) *Device {
@@ -149,7 +179,11 @@ func NewDevice() *Device {
 
 func (d *Device) Parse(parser structure.ObjectParser) {
 	d.LastUploadDate = TimeFromRaw(parser.Time("lastUploadDate", TimeFormat))
-	d.AlertScheduleList = ParseAlertSchedules(parser.WithReferenceArrayParser("alertScheduleList"))
+	if alertScheduleList := ParseAlertSchedules(parser.WithReferenceArrayParser("alertScheduleList")); alertScheduleList != nil {
+		d.AlertScheduleList = alertScheduleList
+	} else {
+		d.AlertScheduleList = ParseAlertSchedules(parser.WithReferenceArrayParser("alertSchedules"))
+	}
 	d.UDI = parser.String("udi")
 	d.SerialNumber = parser.String("serialNumber")
 	d.TransmitterID = parser.String("transmitterId")
@@ -163,6 +197,7 @@ func (d *Device) Parse(parser structure.ObjectParser) {
 	d.Is24HourMode = parser.Bool("is24HourMode")
 	d.DisplayTimeOffset = parser.Int("displayTimeOffset")
 	d.SystemTimeOffset = parser.Int("systemTimeOffset")
+	d.DisplayApp = parser.String("displayApp")
 }
 
 func (d *Device) Validate(validator structure.Validator) {
@@ -174,13 +209,22 @@ func (d *Device) Validate(validator structure.Validator) {
 		alertScheduleListValidator.ReportError(structureValidator.ErrorValueNotExists())
 	}
 	validator.String("udi", d.UDI).NotEmpty()
-	validator.String("serialNumber", d.SerialNumber).Exists().NotEmpty()
-	validator.String("transmitterId", d.TransmitterID).Using(TransmitterIDValidator)
+	if d.IsV3() {
+		validator.String("serialNumber", d.SerialNumber).NotEmpty()
+		validator.String("transmitterId", d.TransmitterID).Exists().Using(TransmitterIDValidator)
+		validator.String("softwareVersion", d.SoftwareVersion).NotEmpty()
+		validator.String("softwareNumber", d.SoftwareNumber).NotEmpty()
+		validator.String("language", d.Language).NotEmpty()
+	} else {
+		validator.String("serialNumber", d.SerialNumber).Exists().NotEmpty()
+		validator.String("transmitterId", d.TransmitterID).Using(TransmitterIDValidator)
+		validator.String("softwareVersion", d.SoftwareVersion).Exists().NotEmpty()
+		validator.String("softwareNumber", d.SoftwareNumber).Exists().NotEmpty()
+		validator.String("language", d.Language).Exists().NotEmpty()
+	}
 	validator.String("transmitterGeneration", d.TransmitterGeneration).OneOf(DeviceTransmitterGenerations()...)
 	validator.String("displayDevice", d.DisplayDevice).OneOf(DeviceDisplayDevices()...)
-	validator.String("softwareVersion", d.SoftwareVersion).Exists().NotEmpty()
-	validator.String("softwareNumber", d.SoftwareNumber).Exists().NotEmpty()
-	validator.String("language", d.Language).Exists().NotEmpty()
+	validator.String("displayApp", d.DisplayApp).NotEmpty()
 }
 
 func (d *Device) Normalize(normalizer structure.Normalizer) {
@@ -189,6 +233,18 @@ func (d *Device) Normalize(normalizer structure.Normalizer) {
 	}
 }
 
+func (d *Device) IsV3() bool {
+	return d.recordVersion != nil && *d.recordVersion == RecordVersionV3
+}
+
+// ID returns the serial number of the device, if available, otherwise the transmitter id (v3)
+func (d *Device) ID() *string {
+	if d.SerialNumber != nil {
+		return d.SerialNumber
+	}
+	return d.TransmitterID
+}
+
 func (d *Device) Hash() (string, error) {
 	bites, err := yaml.Marshal(d)
 	if err != nil {
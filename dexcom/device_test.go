@@ -44,11 +44,15 @@ var _ = Describe("Device", func() {
 		Expect(dexcom.DeviceTransmitterGenerationG6Pro).To(Equal("g6 pro"))
 	})
 
+	It("DeviceTransmitterGenerationG7 is expected", func() {
+		Expect(dexcom.DeviceTransmitterGenerationG7).To(Equal("g7"))
+	})
+
 	It("DeviceDisplayDevices returns expected", func() {
 		Expect(dexcom.DeviceDisplayDevices()).To(Equal([]string{"android", "iOS", "receiver", "shareReceiver", "touchscreenReceiver"}))
 	})
 
 	It("DeviceTransmitterGenerations returns expected", func() {
-		Expect(dexcom.DeviceTransmitterGenerations()).To(Equal([]string{"g4", "g5", "g6", "g6 pro"}))
+		Expect(dexcom.DeviceTransmitterGenerations()).To(Equal([]string{"g4", "g5", "g6", "g6 pro", "g7"}))
 	})
 })
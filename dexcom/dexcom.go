@@ -12,8 +12,20 @@ import (
 const (
 	TimeFormat             = "2006-01-02T15:04:05"
 	SystemTimeNowThreshold = 24 * time.Hour
+
+	APIVersionV2 = "v2"
+	APIVersionV3 = "v3"
+
+	RecordVersionV3 = "3.0"
 )
 
+func APIVersions() []string {
+	return []string{
+		APIVersionV2,
+		APIVersionV3,
+	}
+}
+
 func IsValidTransmitterID(value string) bool {
 	return ValidateTransmitterID(value) == nil
 }
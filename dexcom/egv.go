@@ -22,6 +22,7 @@ const (
 	EGVStatusOK               = "ok"
 	EGVStatusOutOfCalibration = "outOfCalibration"
 	EGVStatusSensorNoise      = "sensorNoise"
+	EGVStatusUnknown          = "unknown"
 
 	EGVTrendDoubleUp       = "doubleUp"
 	EGVTrendSingleUp       = "singleUp"
@@ -33,6 +34,7 @@ const (
 	EGVTrendNone           = "none"
 	EGVTrendNotComputable  = "notComputable"
 	EGVTrendRateOutOfRange = "rateOutOfRange"
+	EGVTrendUnknown        = "unknown"
 
 	EGVTransmitterTickMinimum = 0
 )
@@ -56,6 +58,7 @@ func EGVStatuses() []string {
 		EGVStatusOK,
 		EGVStatusOutOfCalibration,
 		EGVStatusSensorNoise,
+		EGVStatusUnknown,
 	}
 }
 
@@ -71,13 +74,17 @@ func EGVTrends() []string {
 		EGVTrendNone,
 		EGVTrendNotComputable,
 		EGVTrendRateOutOfRange,
+		EGVTrendUnknown,
 	}
 }
 
 type EGVsResponse struct {
-	RateUnit *string `json:"rateUnit,omitempty"`
-	Unit     *string `json:"unit,omitempty"`
-	EGVs     *EGVs   `json:"egvs,omitempty"`
+	RecordType    *string `json:"recordType,omitempty"`
+	RecordVersion *string `json:"recordVersion,omitempty"`
+	UserID        *string `json:"userId,omitempty"`
+	RateUnit      *string `json:"rateUnit,omitempty"`
+	Unit          *string `json:"unit,omitempty"`
+	EGVs          *EGVs   `json:"egvs,omitempty"`
 }
 
 func ParseEGVsResponse(parser structure.ObjectParser) *EGVsResponse {
@@ -94,21 +101,44 @@ func NewEGVsResponse() *EGVsResponse {
 }
 
 func (e *EGVsResponse) Parse(parser structure.ObjectParser) {
-	e.RateUnit = parser.String("rateUnit")
-	e.Unit = parser.String("unit")
-	e.EGVs = ParseEGVs(parser.WithReferenceArrayParser("egvs"), e.Unit)
+	e.RecordType = parser.String("recordType")
+	e.RecordVersion = parser.String("recordVersion")
+	e.UserID = parser.String("userId")
+	if e.IsV3() {
+		e.EGVs = ParseEGVs(parser.WithReferenceArrayParser("records"), nil)
+	} else {
+		e.RateUnit = parser.String("rateUnit")
+		e.Unit = parser.String("unit")
+		e.EGVs = ParseEGVs(parser.WithReferenceArrayParser("egvs"), e.Unit)
+	}
 }
 
 func (e *EGVsResponse) Validate(validator structure.Validator) {
-	validator.String("rateUnit", e.RateUnit).Exists().OneOf(EGVsResponseRateUnits()...)
-	validator.String("unit", e.Unit).Exists().OneOf(EGVsResponseUnits()...)
-	if egvsValidator := validator.WithReference("egvs"); e.EGVs != nil {
+	if e.IsV3() {
+		validator.String("unit", e.Unit).NotExists()
+		validator.String("rateUnit", e.RateUnit).NotExists()
+	} else {
+		validator.String("rateUnit", e.RateUnit).Exists().OneOf(EGVsResponseRateUnits()...)
+		validator.String("unit", e.Unit).Exists().OneOf(EGVsResponseUnits()...)
+	}
+	if egvsValidator := validator.WithReference(e.egvsReference()); e.EGVs != nil {
 		e.EGVs.Validate(egvsValidator)
 	} else {
 		egvsValidator.ReportError(structureValidator.ErrorValueNotExists())
 	}
 }
 
+func (e *EGVsResponse) IsV3() bool {
+	return e.RecordVersion != nil && *e.RecordVersion == RecordVersionV3
+}
+
+func (e *EGVsResponse) egvsReference() string {
+	if e.IsV3() {
+		return "records"
+	}
+	return "egvs"
+}
+
 type EGVs []*EGV
 
 func ParseEGVs(parser structure.ArrayParser, unit *string) *EGVs {
@@ -142,17 +172,22 @@ func (e *EGVs) Validate(validator structure.Validator) {
 }
 
 type EGV struct {
-	SystemTime       *Time    `json:"systemTime,omitempty"`
-	DisplayTime      *Time    `json:"displayTime,omitempty"`
-	Unit             *string  `json:"unit,omitempty"`
-	Value            *float64 `json:"value,omitempty"`
-	RealTimeValue    *float64 `json:"realtimeValue,omitempty"`
-	SmoothedValue    *float64 `json:"smoothedValue,omitempty"`
-	Status           *string  `json:"status,omitempty"`
-	Trend            *string  `json:"trend,omitempty"`
-	TrendRate        *float64 `json:"trendRate,omitempty"`
-	TransmitterID    *string  `json:"transmitterId,omitempty"`
-	TransmitterTicks *int     `json:"transmitterTicks,omitempty"`
+	RecordID              *string  `json:"recordId,omitempty"`
+	SystemTime            *Time    `json:"systemTime,omitempty"`
+	DisplayTime           *Time    `json:"displayTime,omitempty"`
+	Unit                  *string  `json:"unit,omitempty"`
+	RateUnit              *string  `json:"rateUnit,omitempty"`
+	Value                 *float64 `json:"value,omitempty"`
+	RealTimeValue         *float64 `json:"realtimeValue,omitempty"`
+	SmoothedValue         *float64 `json:"smoothedValue,omitempty"`
+	Status                *string  `json:"status,omitempty"`
+	Trend                 *string  `json:"trend,omitempty"`
+	TrendRate             *float64 `json:"trendRate,omitempty"`
+	TransmitterID         *string  `json:"transmitterId,omitempty"`
+	TransmitterTicks      *int     `json:"transmitterTicks,omitempty"`
+	TransmitterGeneration *string  `json:"transmitterGeneration,omitempty"`
+	DisplayDevice         *string  `json:"displayDevice,omitempty"`
+	DisplayApp            *string  `json:"displayApp,omitempty"`
 }
 
 func ParseEGV(parser structure.ObjectParser, unit *string) *EGV {
@@ -171,6 +206,11 @@ func NewEGV(unit *string) *EGV {
 }
 
 func (e *EGV) Parse(parser structure.ObjectParser) {
+	e.RecordID = parser.String("recordId")
+	if unit := parser.String("unit"); unit != nil {
+		e.Unit = unit
+	}
+	e.RateUnit = parser.String("rateUnit")
 	e.SystemTime = TimeFromRaw(parser.ForgivingTime("systemTime", TimeFormat))
 	e.DisplayTime = TimeFromRaw(parser.ForgivingTime("displayTime", TimeFormat))
 	e.Value = parser.Float64("value")
@@ -181,18 +221,30 @@ func (e *EGV) Parse(parser structure.ObjectParser) {
 	e.TrendRate = parser.Float64("trendRate")
 	e.TransmitterID = parser.String("transmitterId")
 	e.TransmitterTicks = parser.Int("transmitterTicks")
+	e.TransmitterGeneration = parser.String("transmitterGeneration")
+	e.DisplayDevice = parser.String("displayDevice")
+	e.DisplayApp = parser.String("displayApp")
 }
 
 func (e *EGV) Validate(validator structure.Validator) {
 	validator = validator.WithMeta(e)
 	validator.Time("systemTime", e.SystemTime.Raw()).Exists().NotZero().BeforeNow(SystemTimeNowThreshold)
 	validator.Time("displayTime", e.DisplayTime.Raw()).Exists().NotZero()
-	validator.String("unit", e.Unit).OneOf(EGVsResponseUnits()...)
+	if e.IsV3() {
+		validator.String("unit", e.Unit).Exists().OneOf(EGVsResponseUnits()...)
+		validator.String("rateUnit", e.RateUnit).OneOf(EGVsResponseRateUnits()...)
+	} else {
+		validator.String("unit", e.Unit).OneOf(EGVsResponseUnits()...)
+	}
 	if e.Unit != nil {
 		switch *e.Unit {
 		case EGVUnitMgdL:
 			validator.Float64("value", e.Value).Exists().InRange(EGVValueMgdLMinimum, EGVValueMgdLMaximum)
-			validator.Float64("realtimeValue", e.RealTimeValue).Exists().InRange(EGVValueMgdLMinimum, EGVValueMgdLMaximum)
+			if e.IsV3() {
+				validator.Float64("realtimeValue", e.RealTimeValue).InRange(EGVValueMgdLMinimum, EGVValueMgdLMaximum)
+			} else {
+				validator.Float64("realtimeValue", e.RealTimeValue).Exists().InRange(EGVValueMgdLMinimum, EGVValueMgdLMaximum)
+			}
 			validator.Float64("smoothedValue", e.SmoothedValue).InRange(EGVValueMgdLMinimum, EGVValueMgdLMaximum)
 		}
 	}
@@ -200,4 +252,12 @@ func (e *EGV) Validate(validator structure.Validator) {
 	validator.String("trend", e.Trend).OneOf(EGVTrends()...)
 	validator.String("transmitterId", e.TransmitterID).Using(TransmitterIDValidator)
 	validator.Int("transmitterTicks", e.TransmitterTicks).GreaterThanOrEqualTo(EGVTransmitterTickMinimum)
+	validator.String("transmitterGeneration", e.TransmitterGeneration).OneOf(DeviceTransmitterGenerations()...)
+	validator.String("displayDevice", e.DisplayDevice).OneOf(DeviceDisplayDevices()...)
+	validator.String("displayApp", e.DisplayApp).NotEmpty()
+}
+
+// IsV3 returns true if the record was returned by the v3 API, which always includes a record id
+func (e *EGV) IsV3() bool {
+	return e.RecordID != nil
 }
@@ -5,6 +5,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/tidepool-org/platform/dexcom"
+	"github.com/tidepool-org/platform/pointer"
+	structureParser "github.com/tidepool-org/platform/structure/parser"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
 )
 
 var _ = Describe("EGV", func() {
@@ -52,6 +55,10 @@ var _ = Describe("EGV", func() {
 		Expect(dexcom.EGVStatusSensorNoise).To(Equal("sensorNoise"))
 	})
 
+	It("EGVStatusUnknown is expected", func() {
+		Expect(dexcom.EGVStatusUnknown).To(Equal("unknown"))
+	})
+
 	It("EGVTrendDoubleUp is expected", func() {
 		Expect(dexcom.EGVTrendDoubleUp).To(Equal("doubleUp"))
 	})
@@ -92,6 +99,10 @@ var _ = Describe("EGV", func() {
 		Expect(dexcom.EGVTrendRateOutOfRange).To(Equal("rateOutOfRange"))
 	})
 
+	It("EGVTrendUnknown is expected", func() {
+		Expect(dexcom.EGVTrendUnknown).To(Equal("unknown"))
+	})
+
 	It("EGVTransmitterTickMinimum is expected", func() {
 		Expect(dexcom.EGVTransmitterTickMinimum).To(Equal(0))
 	})
@@ -105,10 +116,51 @@ var _ = Describe("EGV", func() {
 	})
 
 	It("EGVStatuses returns expected", func() {
-		Expect(dexcom.EGVStatuses()).To(Equal([]string{"high", "low", "ok", "outOfCalibration", "sensorNoise"}))
+		Expect(dexcom.EGVStatuses()).To(Equal([]string{"high", "low", "ok", "outOfCalibration", "sensorNoise", "unknown"}))
 	})
 
 	It("EGVTrends returns expected", func() {
-		Expect(dexcom.EGVTrends()).To(Equal([]string{"doubleUp", "singleUp", "fortyFiveUp", "flat", "fortyFiveDown", "singleDown", "doubleDown", "none", "notComputable", "rateOutOfRange"}))
+		Expect(dexcom.EGVTrends()).To(Equal([]string{"doubleUp", "singleUp", "fortyFiveUp", "flat", "fortyFiveDown", "singleDown", "doubleDown", "none", "notComputable", "rateOutOfRange", "unknown"}))
+	})
+
+	Context("EGVsResponse", func() {
+		It("parses and validates a v3 response", func() {
+			object := map[string]interface{}{
+				"recordType":    "egv",
+				"recordVersion": "3.0",
+				"userId":        "a1b2c3",
+				"records": []interface{}{
+					map[string]interface{}{
+						"recordId":              "d4e5f6",
+						"systemTime":            "2022-02-06T09:12:35",
+						"displayTime":           "2022-02-06T01:12:35",
+						"transmitterId":         "AB12CD",
+						"transmitterTicks":      5796,
+						"value":                 113.0,
+						"trend":                 "flat",
+						"trendRate":             0.5,
+						"unit":                  "mg/dL",
+						"rateUnit":              "mg/dL/min",
+						"displayDevice":         "iOS",
+						"transmitterGeneration": "g7",
+						"status":                "ok",
+					},
+				},
+			}
+			parser := structureParser.NewObject(&object)
+			response := dexcom.ParseEGVsResponse(parser)
+			Expect(parser.NotParsed()).To(Succeed())
+			Expect(response).ToNot(BeNil())
+			Expect(response.IsV3()).To(BeTrue())
+			Expect(response.EGVs).ToNot(BeNil())
+			Expect(*response.EGVs).To(HaveLen(1))
+			egv := (*response.EGVs)[0]
+			Expect(egv.IsV3()).To(BeTrue())
+			Expect(egv.RecordID).To(Equal(pointer.FromString("d4e5f6")))
+			Expect(egv.Unit).To(Equal(pointer.FromString(dexcom.EGVUnitMgdL)))
+			Expect(egv.RateUnit).To(Equal(pointer.FromString(dexcom.EGVUnitMgdLMinute)))
+			Expect(egv.TransmitterGeneration).To(Equal(pointer.FromString(dexcom.DeviceTransmitterGenerationG7)))
+			Expect(structureValidator.New().Validate(response)).To(Succeed())
+		})
 	})
 })
@@ -1,20 +1,30 @@
 package dexcom
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"strconv"
 
+	yaml "gopkg.in/yaml.v2"
+
 	dataTypesActivityPhysical "github.com/tidepool-org/platform/data/types/activity/physical"
 	dataTypesFood "github.com/tidepool-org/platform/data/types/food"
 	dataTypesInsulin "github.com/tidepool-org/platform/data/types/insulin"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/structure"
+	structureParser "github.com/tidepool-org/platform/structure/parser"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 )
 
 const (
-	EventTypeCarbs    = "carbs"
-	EventTypeExercise = "exercise"
-	EventTypeHealth   = "health"
-	EventTypeInsulin  = "insulin"
+	EventTypeBloodGlucose = "bloodGlucose"
+	EventTypeCarbs        = "carbs"
+	EventTypeExercise     = "exercise"
+	EventTypeHealth       = "health"
+	EventTypeInsulin      = "insulin"
+	EventTypeNotes        = "notes"
+	EventTypeUnknown      = "unknown"
 
 	EventUnitCarbsGrams         = "grams"
 	EventValueCarbsGramsMaximum = dataTypesFood.CarbohydrateNetGramsMaximum
@@ -46,10 +56,13 @@ const (
 
 func EventTypes() []string {
 	return []string{
+		EventTypeBloodGlucose,
 		EventTypeCarbs,
 		EventTypeExercise,
 		EventTypeHealth,
 		EventTypeInsulin,
+		EventTypeNotes,
+		EventTypeUnknown,
 	}
 }
 
@@ -87,7 +100,10 @@ func EventStatuses() []string {
 }
 
 type EventsResponse struct {
-	Events *Events `json:"events,omitempty"`
+	RecordType    *string `json:"recordType,omitempty"`
+	RecordVersion *string `json:"recordVersion,omitempty"`
+	UserID        *string `json:"userId,omitempty"`
+	Events        *Events `json:"events,omitempty"`
 }
 
 func ParseEventsResponse(parser structure.ObjectParser) *EventsResponse {
@@ -104,17 +120,31 @@ func NewEventsResponse() *EventsResponse {
 }
 
 func (e *EventsResponse) Parse(parser structure.ObjectParser) {
-	e.Events = ParseEvents(parser.WithReferenceArrayParser("events"))
+	e.RecordType = parser.String("recordType")
+	e.RecordVersion = parser.String("recordVersion")
+	e.UserID = parser.String("userId")
+	e.Events = ParseEvents(parser.WithReferenceArrayParser(e.eventsReference()))
 }
 
 func (e *EventsResponse) Validate(validator structure.Validator) {
-	if eventsValidator := validator.WithReference("events"); e.Events != nil {
+	if eventsValidator := validator.WithReference(e.eventsReference()); e.Events != nil {
 		e.Events.Validate(eventsValidator)
 	} else {
 		eventsValidator.ReportError(structureValidator.ErrorValueNotExists())
 	}
 }
 
+func (e *EventsResponse) IsV3() bool {
+	return e.RecordVersion != nil && *e.RecordVersion == RecordVersionV3
+}
+
+func (e *EventsResponse) eventsReference() string {
+	if e.IsV3() {
+		return "records"
+	}
+	return "events"
+}
+
 type Events []*Event
 
 func ParseEvents(parser structure.ArrayParser) *Events {
@@ -147,14 +177,19 @@ func (e *Events) Validate(validator structure.Validator) {
 }
 
 type Event struct {
-	SystemTime  *Time    `json:"systemTime,omitempty"`
-	DisplayTime *Time    `json:"displayTime,omitempty"`
-	Type        *string  `json:"eventType,omitempty"`
-	SubType     *string  `json:"eventSubType,omitempty"`
-	Unit        *string  `json:"unit,omitempty"`
-	Value       *float64 `json:"value,omitempty"`
-	ID          *string  `json:"eventId,omitempty"`
-	Status      *string  `json:"eventStatus,omitempty"`
+	RecordID              *string  `json:"recordId,omitempty"`
+	SystemTime            *Time    `json:"systemTime,omitempty"`
+	DisplayTime           *Time    `json:"displayTime,omitempty"`
+	Type                  *string  `json:"eventType,omitempty"`
+	SubType               *string  `json:"eventSubType,omitempty"`
+	Unit                  *string  `json:"unit,omitempty"`
+	Value                 *float64 `json:"value,omitempty"`
+	ID                    *string  `json:"eventId,omitempty"`
+	Status                *string  `json:"eventStatus,omitempty"`
+	TransmitterID         *string  `json:"transmitterId,omitempty"`
+	TransmitterGeneration *string  `json:"transmitterGeneration,omitempty"`
+	DisplayDevice         *string  `json:"displayDevice,omitempty"`
+	DisplayApp            *string  `json:"displayApp,omitempty"`
 }
 
 func ParseEvent(parser structure.ObjectParser) *Event {
@@ -171,14 +206,52 @@ func NewEvent() *Event {
 }
 
 func (e *Event) Parse(parser structure.ObjectParser) {
+	e.RecordID = parser.String("recordId")
 	e.SystemTime = TimeFromRaw(parser.Time("systemTime", TimeFormat))
 	e.DisplayTime = TimeFromRaw(parser.Time("displayTime", TimeFormat))
 	e.Type = parser.String("eventType")
 	e.SubType = parser.String("eventSubType")
 	e.Unit = parser.String("unit")
-	e.Value = parser.Float64("value")
+	if e.RecordID != nil {
+		e.Value = parseEventValueV3(parser)
+	} else {
+		e.Value = parser.Float64("value")
+	}
 	e.ID = parser.String("eventId")
 	e.Status = parser.String("eventStatus")
+	e.TransmitterID = parser.String("transmitterId")
+	e.TransmitterGeneration = parser.String("transmitterGeneration")
+	e.DisplayDevice = parser.String("displayDevice")
+	e.DisplayApp = parser.String("displayApp")
+
+	// NOTE: v3 events are identified by record id rather than event id
+	if e.ID == nil {
+		e.ID = pointer.CloneString(e.RecordID)
+	}
+}
+
+// Hash returns a hash of the event, which changes whenever the event is edited or deleted by the user
+func (e *Event) Hash() (string, error) {
+	bites, err := yaml.Marshal(e)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to generate hash")
+	}
+	md5Sum := md5.Sum(bites)
+	return hex.EncodeToString(md5Sum[:]), nil
+}
+
+// NOTE: v3 event values are strings
+func parseEventValueV3(parser structure.ObjectParser) *float64 {
+	value := parser.String("value")
+	if value == nil || *value == "" {
+		return nil
+	}
+	floatValue, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		parser.WithReferenceErrorReporter("value").ReportError(structureParser.ErrorTypeNotFloat64(*value))
+		return nil
+	}
+	return &floatValue
 }
 
 func (e *Event) Validate(validator structure.Validator) {
@@ -200,6 +273,10 @@ func (e *Event) Validate(validator structure.Validator) {
 	}
 	validator.String("eventId", e.ID).Exists().NotEmpty()
 	validator.String("eventStatus", e.Status).Exists().OneOf(EventStatuses()...)
+	validator.String("transmitterId", e.TransmitterID).Using(TransmitterIDValidator)
+	validator.String("transmitterGeneration", e.TransmitterGeneration).OneOf(DeviceTransmitterGenerations()...)
+	validator.String("displayDevice", e.DisplayDevice).OneOf(DeviceDisplayDevices()...)
+	validator.String("displayApp", e.DisplayApp).NotEmpty()
 }
 
 func (e *Event) validateCarbs(validator structure.Validator) {
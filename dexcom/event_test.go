@@ -12,6 +12,10 @@ import (
 )
 
 var _ = Describe("Event", func() {
+	It("EventTypeBloodGlucose is expected", func() {
+		Expect(dexcom.EventTypeBloodGlucose).To(Equal("bloodGlucose"))
+	})
+
 	It("EventTypeCarbs is expected", func() {
 		Expect(dexcom.EventTypeCarbs).To(Equal("carbs"))
 	})
@@ -28,6 +32,14 @@ var _ = Describe("Event", func() {
 		Expect(dexcom.EventTypeInsulin).To(Equal("insulin"))
 	})
 
+	It("EventTypeNotes is expected", func() {
+		Expect(dexcom.EventTypeNotes).To(Equal("notes"))
+	})
+
+	It("EventTypeUnknown is expected", func() {
+		Expect(dexcom.EventTypeUnknown).To(Equal("unknown"))
+	})
+
 	It("EventUnitCarbsGrams is expected", func() {
 		Expect(dexcom.EventUnitCarbsGrams).To(Equal("grams"))
 	})
@@ -117,7 +129,7 @@ var _ = Describe("Event", func() {
 	})
 
 	It("EventTypes returns expected", func() {
-		Expect(dexcom.EventTypes()).To(Equal([]string{"carbs", "exercise", "health", "insulin"}))
+		Expect(dexcom.EventTypes()).To(Equal([]string{"bloodGlucose", "carbs", "exercise", "health", "insulin", "notes", "unknown"}))
 	})
 
 	It("EventSubTypesExercise returns expected", func() {
@@ -150,4 +162,31 @@ var _ = Describe("Event", func() {
 			Expect(validator.Error()).ToNot(HaveOccurred())
 		})
 	})
+
+	Describe("Hash", func() {
+		It("returns the same hash for the same event", func() {
+			event := test.RandomEvent()
+			hash, err := event.Hash()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hash).ToNot(BeEmpty())
+			Expect(test.CloneEvent(event).Hash()).To(Equal(hash))
+		})
+
+		It("returns a different hash if the event is edited", func() {
+			event := test.RandomEvent()
+			hash, err := event.Hash()
+			Expect(err).ToNot(HaveOccurred())
+			event.Value = pointer.FromFloat64(pointer.ToFloat64(event.Value) + 1)
+			Expect(event.Hash()).ToNot(Equal(hash))
+		})
+
+		It("returns a different hash if the event is deleted", func() {
+			event := test.RandomEvent()
+			event.Status = pointer.FromString(dexcom.EventStatusCreated)
+			hash, err := event.Hash()
+			Expect(err).ToNot(HaveOccurred())
+			event.Status = pointer.FromString(dexcom.EventStatusDeleted)
+			Expect(event.Hash()).ToNot(Equal(hash))
+		})
+	})
 })
@@ -8,6 +8,7 @@ import (
 	dataTypes "github.com/tidepool-org/platform/data/types"
 	dataTypesActivityPhysical "github.com/tidepool-org/platform/data/types/activity/physical"
 	dataTypesBloodGlucoseContinuous "github.com/tidepool-org/platform/data/types/blood/glucose/continuous"
+	dataTypesDeviceAlarm "github.com/tidepool-org/platform/data/types/device/alarm"
 	dataTypesDeviceCalibration "github.com/tidepool-org/platform/data/types/device/calibration"
 	dataTypesFood "github.com/tidepool-org/platform/data/types/food"
 	dataTypesInsulin "github.com/tidepool-org/platform/data/types/insulin"
@@ -75,6 +76,41 @@ func translateTime(systemTime *dexcom.Time, displayTime *dexcom.Time, datum *dat
 	(*datum.Payload)["systemTime"] = systemTime.Raw()
 }
 
+func translateAlertToDatum(alert *dexcom.Alert) data.Datum {
+	datum := dataTypesDeviceAlarm.New()
+
+	// TODO: Refactor so we don't have to clear these here
+	datum.ID = nil
+	datum.GUID = nil
+
+	datum.AlarmType = pointer.FromString(dataTypesDeviceAlarm.AlarmTypeOther)
+	datum.Payload = metadata.NewMetadata()
+	if alert.AlertName != nil {
+		(*datum.Payload)["alertName"] = *alert.AlertName
+	}
+	if alert.AlertState != nil {
+		(*datum.Payload)["alertState"] = *alert.AlertState
+	}
+	if alert.TransmitterID != nil {
+		(*datum.Payload)["transmitterId"] = *alert.TransmitterID
+	}
+	if alert.TransmitterGeneration != nil {
+		(*datum.Payload)["transmitterGeneration"] = *alert.TransmitterGeneration
+	}
+	if alert.DisplayDevice != nil {
+		(*datum.Payload)["displayDevice"] = *alert.DisplayDevice
+	}
+	if alert.DisplayApp != nil {
+		(*datum.Payload)["displayApp"] = *alert.DisplayApp
+	}
+	if alert.RecordID != nil {
+		datum.Origin = &origin.Origin{ID: pointer.CloneString(alert.RecordID)}
+	}
+
+	translateTime(alert.SystemTime, alert.DisplayTime, &datum.Base)
+	return datum
+}
+
 func translateCalibrationToDatum(calibration *dexcom.Calibration) data.Datum {
 	datum := dataTypesDeviceCalibration.New()
 
@@ -88,6 +124,21 @@ func translateCalibrationToDatum(calibration *dexcom.Calibration) data.Datum {
 	if calibration.TransmitterID != nil {
 		(*datum.Payload)["transmitterId"] = *calibration.TransmitterID
 	}
+	if calibration.TransmitterTicks != nil {
+		(*datum.Payload)["transmitterTicks"] = *calibration.TransmitterTicks
+	}
+	if calibration.TransmitterGeneration != nil {
+		(*datum.Payload)["transmitterGeneration"] = *calibration.TransmitterGeneration
+	}
+	if calibration.DisplayDevice != nil {
+		(*datum.Payload)["displayDevice"] = *calibration.DisplayDevice
+	}
+	if calibration.DisplayApp != nil {
+		(*datum.Payload)["displayApp"] = *calibration.DisplayApp
+	}
+	if calibration.RecordID != nil {
+		datum.Origin = &origin.Origin{ID: pointer.CloneString(calibration.RecordID)}
+	}
 
 	translateTime(calibration.SystemTime, calibration.DisplayTime, &datum.Base)
 	return datum
@@ -186,6 +237,9 @@ func translateDeviceToDatum(device *dexcom.Device) data.Datum {
 	if device.SystemTimeOffset != nil {
 		(*datum.Payload)["systemTimeOffset"] = *device.SystemTimeOffset
 	}
+	if device.DisplayApp != nil {
+		(*datum.Payload)["displayApp"] = *device.DisplayApp
+	}
 
 	datum.Time = pointer.FromTime(device.LastUploadDate.Time)
 	return datum
@@ -370,6 +424,18 @@ func translateEGVToDatum(egv *dexcom.EGV, unit *string, rateUnit *string) data.D
 	if egv.TransmitterTicks != nil {
 		(*datum.Payload)["transmitterTicks"] = *egv.TransmitterTicks
 	}
+	if egv.TransmitterGeneration != nil {
+		(*datum.Payload)["transmitterGeneration"] = *egv.TransmitterGeneration
+	}
+	if egv.DisplayDevice != nil {
+		(*datum.Payload)["displayDevice"] = *egv.DisplayDevice
+	}
+	if egv.DisplayApp != nil {
+		(*datum.Payload)["displayApp"] = *egv.DisplayApp
+	}
+	if egv.RecordID != nil {
+		datum.Origin = &origin.Origin{ID: pointer.CloneString(egv.RecordID)}
+	}
 
 	switch *datum.Units {
 	case dexcom.EGVUnitMgdL:
@@ -394,6 +460,21 @@ func translateEGVToDatum(egv *dexcom.EGV, unit *string, rateUnit *string) data.D
 	return datum
 }
 
+// translateEventToDatum returns nil if the event type is not supported
+func translateEventToDatum(event *dexcom.Event) data.Datum {
+	switch *event.Type {
+	case dexcom.EventTypeCarbs:
+		return translateEventCarbsToDatum(event)
+	case dexcom.EventTypeExercise:
+		return translateEventExerciseToDatum(event)
+	case dexcom.EventTypeHealth:
+		return translateEventHealthToDatum(event)
+	case dexcom.EventTypeInsulin:
+		return translateEventInsulinToDatum(event)
+	}
+	return nil
+}
+
 func translateEventCarbsToDatum(event *dexcom.Event) data.Datum {
 	datum := dataTypesFood.New()
 
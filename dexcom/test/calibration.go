@@ -17,6 +17,9 @@ func CloneCalibrationsResponse(datum *dexcom.CalibrationsResponse) *dexcom.Calib
 		return nil
 	}
 	clone := dexcom.NewCalibrationsResponse()
+	clone.RecordType = pointer.CloneString(datum.RecordType)
+	clone.RecordVersion = pointer.CloneString(datum.RecordVersion)
+	clone.UserID = pointer.CloneString(datum.UserID)
 	clone.Calibrations = CloneCalibrations(datum.Calibrations)
 	return clone
 }
@@ -58,10 +61,15 @@ func CloneCalibration(datum *dexcom.Calibration) *dexcom.Calibration {
 		return nil
 	}
 	clone := dexcom.NewCalibration()
+	clone.RecordID = pointer.CloneString(datum.RecordID)
 	clone.SystemTime = CloneTime(datum.SystemTime)
 	clone.DisplayTime = CloneTime(datum.DisplayTime)
 	clone.Unit = pointer.CloneString(datum.Unit)
 	clone.Value = pointer.CloneFloat64(datum.Value)
 	clone.TransmitterID = pointer.CloneString(datum.TransmitterID)
+	clone.TransmitterTicks = pointer.CloneInt(datum.TransmitterTicks)
+	clone.TransmitterGeneration = pointer.CloneString(datum.TransmitterGeneration)
+	clone.DisplayDevice = pointer.CloneString(datum.DisplayDevice)
+	clone.DisplayApp = pointer.CloneString(datum.DisplayApp)
 	return clone
 }
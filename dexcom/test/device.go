@@ -19,6 +19,9 @@ func CloneDevicesResponse(datum *dexcom.DevicesResponse) *dexcom.DevicesResponse
 		return nil
 	}
 	clone := dexcom.NewDevicesResponse()
+	clone.RecordType = pointer.CloneString(datum.RecordType)
+	clone.RecordVersion = pointer.CloneString(datum.RecordVersion)
+	clone.UserID = pointer.CloneString(datum.UserID)
 	clone.Devices = CloneDevices(datum.Devices)
 	return clone
 }
@@ -82,6 +85,7 @@ func CloneDevice(datum *dexcom.Device) *dexcom.Device {
 	clone.Is24HourMode = pointer.CloneBool(datum.Is24HourMode)
 	clone.DisplayTimeOffset = pointer.CloneInt(datum.DisplayTimeOffset)
 	clone.SystemTimeOffset = pointer.CloneInt(datum.SystemTimeOffset)
+	clone.DisplayApp = pointer.CloneString(datum.DisplayApp)
 	return clone
 }
 
@@ -21,6 +21,9 @@ func CloneEGVsResponse(datum *dexcom.EGVsResponse) *dexcom.EGVsResponse {
 		return nil
 	}
 	clone := dexcom.NewEGVsResponse()
+	clone.RecordType = pointer.CloneString(datum.RecordType)
+	clone.RecordVersion = pointer.CloneString(datum.RecordVersion)
+	clone.UserID = pointer.CloneString(datum.UserID)
 	clone.RateUnit = pointer.CloneString(datum.RateUnit)
 	clone.Unit = pointer.CloneString(datum.Unit)
 	clone.EGVs = CloneEGVs(datum.EGVs)
@@ -69,9 +72,11 @@ func CloneEGV(datum *dexcom.EGV) *dexcom.EGV {
 		return nil
 	}
 	clone := dexcom.NewEGV(datum.Unit)
+	clone.RecordID = pointer.CloneString(datum.RecordID)
 	clone.SystemTime = CloneTime(datum.SystemTime)
 	clone.DisplayTime = CloneTime(datum.DisplayTime)
 	clone.Unit = pointer.CloneString(datum.Unit)
+	clone.RateUnit = pointer.CloneString(datum.RateUnit)
 	clone.Value = pointer.CloneFloat64(datum.Value)
 	clone.RealTimeValue = pointer.CloneFloat64(datum.RealTimeValue)
 	clone.SmoothedValue = pointer.CloneFloat64(datum.SmoothedValue)
@@ -80,5 +85,8 @@ func CloneEGV(datum *dexcom.EGV) *dexcom.EGV {
 	clone.TrendRate = pointer.CloneFloat64(datum.TrendRate)
 	clone.TransmitterID = pointer.CloneString(datum.TransmitterID)
 	clone.TransmitterTicks = pointer.CloneInt(datum.TransmitterTicks)
+	clone.TransmitterGeneration = pointer.CloneString(datum.TransmitterGeneration)
+	clone.DisplayDevice = pointer.CloneString(datum.DisplayDevice)
+	clone.DisplayApp = pointer.CloneString(datum.DisplayApp)
 	return clone
 }
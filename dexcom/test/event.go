@@ -17,6 +17,9 @@ func CloneEventsResponse(datum *dexcom.EventsResponse) *dexcom.EventsResponse {
 		return nil
 	}
 	clone := dexcom.NewEventsResponse()
+	clone.RecordType = pointer.CloneString(datum.RecordType)
+	clone.RecordVersion = pointer.CloneString(datum.RecordVersion)
+	clone.UserID = pointer.CloneString(datum.UserID)
 	clone.Events = CloneEvents(datum.Events)
 	return clone
 }
@@ -70,6 +73,7 @@ func CloneEvent(datum *dexcom.Event) *dexcom.Event {
 		return nil
 	}
 	clone := dexcom.NewEvent()
+	clone.RecordID = pointer.CloneString(datum.RecordID)
 	clone.SystemTime = CloneTime(datum.SystemTime)
 	clone.DisplayTime = CloneTime(datum.DisplayTime)
 	clone.Type = pointer.CloneString(datum.Type)
@@ -78,6 +82,10 @@ func CloneEvent(datum *dexcom.Event) *dexcom.Event {
 	clone.Value = pointer.CloneFloat64(datum.Value)
 	clone.ID = pointer.CloneString(datum.ID)
 	clone.Status = pointer.CloneString(datum.Status)
+	clone.TransmitterID = pointer.CloneString(datum.TransmitterID)
+	clone.TransmitterGeneration = pointer.CloneString(datum.TransmitterGeneration)
+	clone.DisplayDevice = pointer.CloneString(datum.DisplayDevice)
+	clone.DisplayApp = pointer.CloneString(datum.DisplayApp)
 	return clone
 }
 
@@ -22,6 +22,7 @@ export TIDEPOOL_USER_STORE_PASSWORD_SALT="ADihSEI7tOQQP9xfXMO9HfRpXKu1NpIJ"
 
 export TIDEPOOL_AUTH_CLIENT_ADDRESS="http://localhost:8009"
 export TIDEPOOL_BLOB_CLIENT_ADDRESS="http://localhost:8009"
+export TIDEPOOL_CLINIC_CLIENT_ADDRESS="http://localhost:8009"
 export TIDEPOOL_DATA_CLIENT_ADDRESS="http://localhost:8009"
 export TIDEPOOL_DATA_SOURCE_CLIENT_ADDRESS="http://localhost:8009"
 export TIDEPOOL_IMAGE_CLIENT_ADDRESS="http://localhost:8009"
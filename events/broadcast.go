@@ -0,0 +1,117 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	ev "github.com/tidepool-org/go-common/events"
+)
+
+// broadcastConsumer consumes every partition of the topic directly, without joining a consumer group, starting from the
+// newest offset. Since it never commits offsets, there is no consumer group state left behind in the cluster when a
+// replica stops or is replaced; partitions added after it starts are only picked up on the next restart.
+type broadcastConsumer struct {
+	config         *ev.CloudEventsConfig
+	createConsumer ev.ConsumerFactory
+	attempts       uint
+	delay          time.Duration
+	stop           chan struct{}
+	stopOnce       sync.Once
+}
+
+var _ ev.EventConsumer = &broadcastConsumer{}
+
+func newBroadcastConsumer(config *ev.CloudEventsConfig, createConsumer ev.ConsumerFactory) *broadcastConsumer {
+	return &broadcastConsumer{
+		config:         config,
+		createConsumer: createConsumer,
+		attempts:       ev.DefaultAttempts,
+		delay:          ev.DefaultDelay,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start consumes until Stop is called, retrying with the same attempts and delay as the consumer group if the brokers
+// cannot be reached
+func (b *broadcastConsumer) Start() error {
+	var err error
+	for attempt := uint(0); attempt < b.attempts; attempt++ {
+		if err = b.consume(); err == nil {
+			return ev.ErrConsumerStopped
+		}
+		log.Printf("Broadcast consumer exited. Reason: %v", err)
+
+		select {
+		case <-b.stop:
+			return ev.ErrConsumerStopped
+		case <-time.After(b.delay):
+		}
+	}
+	return err
+}
+
+func (b *broadcastConsumer) Stop() error {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+	})
+	return nil
+}
+
+func (b *broadcastConsumer) consume() error {
+	handler, err := b.createConsumer()
+	if err != nil {
+		return err
+	}
+	if err = handler.Initialize(b.config); err != nil {
+		return err
+	}
+
+	consumer, err := sarama.NewConsumer(b.config.KafkaBrokers, b.config.SaramaConfig)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	topic := b.config.GetPrefixedTopic()
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return err
+	}
+
+	var partitionConsumers []sarama.PartitionConsumer
+	defer func() {
+		for _, partitionConsumer := range partitionConsumers {
+			partitionConsumer.AsyncClose()
+		}
+	}()
+	for _, partition := range partitions {
+		partitionConsumer, err := consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return err
+		}
+		partitionConsumers = append(partitionConsumers, partitionConsumer)
+	}
+
+	wg := sync.WaitGroup{}
+	for _, partitionConsumer := range partitionConsumers {
+		wg.Add(1)
+		go func(partitionConsumer sarama.PartitionConsumer) {
+			defer wg.Done()
+			for message := range partitionConsumer.Messages() {
+				if err := handler.HandleKafkaMessage(message); err != nil {
+					log.Printf("failed to process kafka message: %v", err)
+				}
+			}
+		}(partitionConsumer)
+	}
+
+	<-b.stop
+	for _, partitionConsumer := range partitionConsumers {
+		partitionConsumer.AsyncClose()
+	}
+	partitionConsumers = nil
+	wg.Wait()
+	return nil
+}
@@ -11,8 +11,9 @@ type Runner interface {
 }
 
 type runner struct {
-	consumer *ev.FaultTolerantConsumerGroup
-	handlers []ev.EventHandler
+	consumer  ev.EventConsumer
+	handlers  []ev.EventHandler
+	broadcast bool
 }
 
 func NewRunner(handlers []ev.EventHandler) Runner {
@@ -21,14 +22,30 @@ func NewRunner(handlers []ev.EventHandler) Runner {
 	}
 }
 
+// NewBroadcastRunner returns a runner whose handlers receive every event on each replica, rather than one replica of the
+// service receiving each event. It consumes every partition directly, without a consumer group, and starts from the newest
+// event, so it is only suitable for handlers, such as cache invalidation, that do not need events published before it
+// started. No offsets are committed, so there is no per-replica consumer group to clean up when a replica goes away.
+func NewBroadcastRunner(handlers []ev.EventHandler) Runner {
+	return &runner{
+		handlers:  handlers,
+		broadcast: true,
+	}
+}
+
 func (r *runner) Initialize() error {
 	config := ev.NewConfig()
 	if err := config.LoadFromEnv(); err != nil {
 		return err
 	}
-	consumer, err := ev.NewFaultTolerantConsumerGroup(config, func() (ev.MessageConsumer, error) {
+	createConsumer := func() (ev.MessageConsumer, error) {
 		return ev.NewCloudEventsMessageHandler(r.handlers)
-	})
+	}
+	if r.broadcast {
+		r.consumer = newBroadcastConsumer(config, createConsumer)
+		return nil
+	}
+	consumer, err := ev.NewFaultTolerantConsumerGroup(config, createConsumer)
 	if err != nil {
 		return err
 	}
@@ -47,3 +64,18 @@ func (r *runner) Terminate() error {
 	}
 	return nil
 }
+
+// NewProducer returns a producer that sends events to the topic using the same environment configuration as the runner
+func NewProducer(topic string) (ev.EventProducer, error) {
+	config := ev.NewConfig()
+	if err := config.LoadFromEnv(); err != nil {
+		return nil, err
+	}
+
+	config.KafkaTopic = topic
+	if config.EventSource == "" {
+		config.EventSource = config.KafkaConsumerGroup
+	}
+
+	return ev.NewKafkaCloudEventsProducer(config)
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"context"
+
+	ev "github.com/tidepool-org/go-common/events"
+)
+
+type SendInput struct {
+	Context context.Context
+	Event   ev.Event
+}
+
+type EventProducer struct {
+	SendInvocations int
+	SendInputs      []SendInput
+	SendStub        func(ctx context.Context, event ev.Event) error
+	SendOutputs     []error
+	SendOutput      *error
+}
+
+func NewEventProducer() *EventProducer {
+	return &EventProducer{}
+}
+
+func (e *EventProducer) Send(ctx context.Context, event ev.Event) error {
+	e.SendInvocations++
+	e.SendInputs = append(e.SendInputs, SendInput{Context: ctx, Event: event})
+	if e.SendStub != nil {
+		return e.SendStub(ctx, event)
+	}
+	if len(e.SendOutputs) > 0 {
+		output := e.SendOutputs[0]
+		e.SendOutputs = e.SendOutputs[1:]
+		return output
+	}
+	if e.SendOutput != nil {
+		return *e.SendOutput
+	}
+	panic("Send has no output")
+}
+
+func (e *EventProducer) AssertOutputsEmpty() {
+	if len(e.SendOutputs) > 0 {
+		panic("SendOutputs is not empty")
+	}
+}
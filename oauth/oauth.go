@@ -22,9 +22,15 @@ type Provider interface {
 	provider.Provider
 	TokenSourceSource
 
-	CalculateStateForRestrictedToken(restrictedToken string) string // state = crypto of provider name, restrictedToken, secret
-	GetAuthorizationCodeURLWithState(state string) string
-	ExchangeAuthorizationCodeForToken(ctx context.Context, authorizationCode string) (*Token, error)
+	CalculateStateForRestrictedToken(restrictedToken string) string        // state = crypto of provider name, restrictedToken, secret
+	CalculateCodeVerifierForRestrictedToken(restrictedToken string) string // empty if PKCE not enabled
+	GetAuthorizationCodeURLWithState(state string, codeVerifier string) string
+	ExchangeAuthorizationCodeForToken(ctx context.Context, authorizationCode string, codeVerifier string) (*Token, error)
+}
+
+// TokenRevoker is implemented by providers that revoke tokens with the partner when a provider session is deleted
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, token *Token) error
 }
 
 type HTTPClientSource interface {
@@ -2,7 +2,15 @@ package provider
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"golang.org/x/oauth2"
 
@@ -15,9 +23,11 @@ import (
 const ProviderType = "oauth"
 
 type Provider struct {
-	name      string
-	config    *oauth2.Config
-	stateSalt string
+	name        string
+	config      *oauth2.Config
+	stateSalt   string
+	pkceEnabled bool
+	revokeURL   string
 }
 
 func NewProvider(name string, configReporter config.Reporter) (*Provider, error) {
@@ -56,10 +66,20 @@ func NewProvider(name string, configReporter config.Reporter) (*Provider, error)
 		return nil, errors.New("state salt is missing")
 	}
 
+	var pkceEnabled bool
+	if pkceEnabledString, err := configReporter.Get("pkce_enabled"); err == nil {
+		pkceEnabled, err = strconv.ParseBool(pkceEnabledString)
+		if err != nil {
+			return nil, errors.New("pkce enabled is invalid")
+		}
+	}
+
 	return &Provider{
-		name:      name,
-		config:    cfg,
-		stateSalt: stateSalt,
+		name:        name,
+		config:      cfg,
+		stateSalt:   stateSalt,
+		pkceEnabled: pkceEnabled,
+		revokeURL:   configReporter.GetWithDefault("revoke_url", ""),
 	}, nil
 }
 
@@ -96,12 +116,37 @@ func (p *Provider) CalculateStateForRestrictedToken(restrictedToken string) stri
 	return crypto.HexEncodedMD5Hash(fmt.Sprintf("%s:%s:%s:%s", p.Type(), p.Name(), restrictedToken, p.stateSalt))
 }
 
-func (p *Provider) GetAuthorizationCodeURLWithState(state string) string {
-	return p.config.AuthCodeURL(state)
+// CalculateCodeVerifierForRestrictedToken returns the PKCE code verifier, or an empty string if PKCE is not enabled.
+// Like the state, the code verifier is derived from the restricted token so it need not be stored between the
+// authorize and redirect requests. It is keyed with the state salt so it cannot be derived from the state.
+func (p *Provider) CalculateCodeVerifierForRestrictedToken(restrictedToken string) string {
+	if !p.pkceEnabled {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.stateSalt))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%s:code_verifier", p.Type(), p.Name(), restrictedToken)))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
-func (p *Provider) ExchangeAuthorizationCodeForToken(ctx context.Context, authorizationCode string) (*oauth.Token, error) {
-	token, err := p.config.Exchange(ctx, authorizationCode)
+func (p *Provider) GetAuthorizationCodeURLWithState(state string, codeVerifier string) string {
+	var options []oauth2.AuthCodeOption
+	if codeVerifier != "" {
+		options = append(options,
+			oauth2.SetAuthURLParam("code_challenge", CodeChallengeForCodeVerifier(codeVerifier)),
+			oauth2.SetAuthURLParam("code_challenge_method", CodeChallengeMethodS256),
+		)
+	}
+	return p.config.AuthCodeURL(state, options...)
+}
+
+func (p *Provider) ExchangeAuthorizationCodeForToken(ctx context.Context, authorizationCode string, codeVerifier string) (*oauth.Token, error) {
+	var options []oauth2.AuthCodeOption
+	if codeVerifier != "" {
+		options = append(options, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	}
+
+	token, err := p.config.Exchange(ctx, authorizationCode, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to exchange authorization code for token")
 	}
@@ -109,6 +154,65 @@ func (p *Provider) ExchangeAuthorizationCodeForToken(ctx context.Context, author
 	return oauth.NewTokenFromRawToken(token)
 }
 
+// RevokeToken revokes the refresh and access tokens with the partner per RFC 7009. If the revoke url is not configured,
+// then nothing is revoked.
+func (p *Provider) RevokeToken(ctx context.Context, token *oauth.Token) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if token == nil {
+		return errors.New("token is missing")
+	}
+
+	if p.revokeURL == "" {
+		return nil
+	}
+
+	if token.RefreshToken != "" {
+		if err := p.revokeToken(ctx, token.RefreshToken, "refresh_token"); err != nil {
+			return errors.Wrap(err, "unable to revoke refresh token")
+		}
+	}
+	if token.AccessToken != "" {
+		if err := p.revokeToken(ctx, token.AccessToken, "access_token"); err != nil {
+			return errors.Wrap(err, "unable to revoke access token")
+		}
+	}
+	return nil
+}
+
+func (p *Provider) revokeToken(ctx context.Context, token string, tokenTypeHint string) error {
+	values := url.Values{}
+	values.Set("token", token)
+	values.Set("token_type_hint", tokenTypeHint)
+
+	req, err := http.NewRequest(http.MethodPost, p.revokeURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return errors.Wrap(err, "unable to create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "unable to perform request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Newf("unexpected response status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+const CodeChallengeMethodS256 = "S256"
+
+func CodeChallengeForCodeVerifier(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 func SplitScopes(scopes string) []string {
 	return config.SplitTrimCompact(scopes)
 }
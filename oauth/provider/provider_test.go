@@ -0,0 +1,162 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	"github.com/tidepool-org/platform/oauth"
+	oauthProvider "github.com/tidepool-org/platform/oauth/provider"
+	"github.com/tidepool-org/platform/test"
+)
+
+var _ = Describe("Provider", func() {
+	var name string
+	var configReporter *configTest.Reporter
+
+	BeforeEach(func() {
+		name = test.RandomStringFromRangeAndCharset(4, 16, test.CharsetAlphaNumeric)
+		configReporter = configTest.NewReporter()
+		configReporter.Config["client_id"] = "client-id"
+		configReporter.Config["client_secret"] = "client-secret"
+		configReporter.Config["authorize_url"] = "https://partner.example.com/authorize"
+		configReporter.Config["token_url"] = "https://partner.example.com/token"
+		configReporter.Config["redirect_url"] = "https://tidepool.example.com/redirect"
+		configReporter.Config["state_salt"] = test.RandomStringFromRangeAndCharset(16, 32, test.CharsetAlphaNumeric)
+	})
+
+	Context("NewProvider", func() {
+		It("returns an error when pkce enabled is invalid", func() {
+			configReporter.Config["pkce_enabled"] = "invalid"
+			prvdr, err := oauthProvider.NewProvider(name, configReporter)
+			Expect(err).To(MatchError("pkce enabled is invalid"))
+			Expect(prvdr).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(oauthProvider.NewProvider(name, configReporter)).ToNot(BeNil())
+		})
+	})
+
+	Context("CodeChallengeForCodeVerifier", func() {
+		It("returns the S256 code challenge", func() {
+			// Example from RFC 7636 Appendix B
+			Expect(oauthProvider.CodeChallengeForCodeVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")).To(Equal("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"))
+		})
+	})
+
+	Context("with PKCE", func() {
+		It("returns an empty code verifier and omits the code challenge when not enabled", func() {
+			prvdr, err := oauthProvider.NewProvider(name, configReporter)
+			Expect(err).ToNot(HaveOccurred())
+			codeVerifier := prvdr.CalculateCodeVerifierForRestrictedToken("restricted-token")
+			Expect(codeVerifier).To(BeEmpty())
+			authorizationCodeURL, err := url.Parse(prvdr.GetAuthorizationCodeURLWithState("state", codeVerifier))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authorizationCodeURL.Query().Get("state")).To(Equal("state"))
+			Expect(authorizationCodeURL.Query()).ToNot(HaveKey("code_challenge"))
+			Expect(authorizationCodeURL.Query()).ToNot(HaveKey("code_challenge_method"))
+		})
+
+		It("returns a stable code verifier and includes the code challenge when enabled", func() {
+			configReporter.Config["pkce_enabled"] = "true"
+			prvdr, err := oauthProvider.NewProvider(name, configReporter)
+			Expect(err).ToNot(HaveOccurred())
+			codeVerifier := prvdr.CalculateCodeVerifierForRestrictedToken("restricted-token")
+			Expect(codeVerifier).To(HaveLen(64))
+			Expect(prvdr.CalculateCodeVerifierForRestrictedToken("restricted-token")).To(Equal(codeVerifier))
+			Expect(prvdr.CalculateCodeVerifierForRestrictedToken("other-restricted-token")).ToNot(Equal(codeVerifier))
+			Expect(codeVerifier).ToNot(Equal(prvdr.CalculateStateForRestrictedToken("restricted-token")))
+			authorizationCodeURL, err := url.Parse(prvdr.GetAuthorizationCodeURLWithState("state", codeVerifier))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authorizationCodeURL.Query().Get("code_challenge")).To(Equal(oauthProvider.CodeChallengeForCodeVerifier(codeVerifier)))
+			Expect(authorizationCodeURL.Query().Get("code_challenge_method")).To(Equal("S256"))
+		})
+
+		It("sends the code verifier when exchanging the authorization code", func() {
+			server := NewServer()
+			defer server.Close()
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("POST", "/token"),
+					VerifyForm(url.Values{"grant_type": []string{"authorization_code"}, "code": []string{"code"}, "code_verifier": []string{"code-verifier"}}),
+					RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{"access_token": "access-token", "token_type": "Bearer", "refresh_token": "refresh-token"}),
+				),
+			)
+			configReporter.Config["token_url"] = server.URL() + "/token"
+			configReporter.Config["pkce_enabled"] = "true"
+			prvdr, err := oauthProvider.NewProvider(name, configReporter)
+			Expect(err).ToNot(HaveOccurred())
+			token, err := prvdr.ExchangeAuthorizationCodeForToken(context.Background(), "code", "code-verifier")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("access-token"))
+			Expect(token.RefreshToken).To(Equal("refresh-token"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("RevokeToken", func() {
+		var server *Server
+		var token *oauth.Token
+
+		BeforeEach(func() {
+			server = NewServer()
+			token = &oauth.Token{AccessToken: "access-token", TokenType: "Bearer", RefreshToken: "refresh-token"}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("returns successfully without revoking when the revoke url is not configured", func() {
+			prvdr, err := oauthProvider.NewProvider(name, configReporter)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prvdr.RevokeToken(context.Background(), token)).To(Succeed())
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		Context("with revoke url", func() {
+			var prvdr *oauthProvider.Provider
+
+			BeforeEach(func() {
+				var err error
+				configReporter.Config["revoke_url"] = server.URL() + "/revoke"
+				prvdr, err = oauthProvider.NewProvider(name, configReporter)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns an error when the token is missing", func() {
+				Expect(prvdr.RevokeToken(context.Background(), nil)).To(MatchError("token is missing"))
+			})
+
+			It("returns an error when the partner responds with an error", func() {
+				server.AppendHandlers(RespondWith(http.StatusServiceUnavailable, nil))
+				Expect(prvdr.RevokeToken(context.Background(), token)).To(MatchError("unable to revoke refresh token; unexpected response status code 503"))
+			})
+
+			It("revokes the refresh and access tokens", func() {
+				server.AppendHandlers(
+					CombineHandlers(
+						VerifyRequest("POST", "/revoke"),
+						VerifyBasicAuth("client-id", "client-secret"),
+						VerifyForm(url.Values{"token": []string{"refresh-token"}, "token_type_hint": []string{"refresh_token"}}),
+						RespondWith(http.StatusOK, nil),
+					),
+					CombineHandlers(
+						VerifyRequest("POST", "/revoke"),
+						VerifyBasicAuth("client-id", "client-secret"),
+						VerifyForm(url.Values{"token": []string{"access-token"}, "token_type_hint": []string{"access_token"}}),
+						RespondWith(http.StatusOK, nil),
+					),
+				)
+				Expect(prvdr.RevokeToken(context.Background(), token)).To(Succeed())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+	})
+})
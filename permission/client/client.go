@@ -3,24 +3,70 @@ package client
 import (
 	"context"
 
+	"github.com/tidepool-org/platform/cache"
+	"github.com/tidepool-org/platform/config"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/permission"
 	"github.com/tidepool-org/platform/platform"
 	"github.com/tidepool-org/platform/request"
 )
 
+type Config struct {
+	*platform.Config
+	Cache *cache.Config
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Config: platform.NewConfig(),
+		Cache:  cache.NewConfig(),
+	}
+}
+
+func (c *Config) Load(configReporter config.Reporter) error {
+	if err := c.Config.Load(configReporter); err != nil {
+		return err
+	}
+	return c.Cache.Load(configReporter.WithScopes("cache"))
+}
+
+func (c *Config) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+	if c.Cache == nil {
+		return errors.New("cache is missing")
+	} else if err := c.Cache.Validate(); err != nil {
+		return errors.Wrap(err, "cache is invalid")
+	}
+	return nil
+}
+
 type Client struct {
 	client *platform.Client
+	cache  *cache.Cache
 }
 
-func New(config *platform.Config, authorizeAs platform.AuthorizeAs) (*Client, error) {
-	clnt, err := platform.NewClient(config, authorizeAs)
+func New(cfg *Config, authorizeAs platform.AuthorizeAs) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+
+	clnt, err := platform.NewClient(cfg.Config, authorizeAs)
+	if err != nil {
+		return nil, err
+	}
+
+	cch, err := cache.New(cfg.Cache)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
 		client: clnt,
+		cache:  cch,
 	}, nil
 }
 
@@ -35,14 +81,30 @@ func (c *Client) GetUserPermissions(ctx context.Context, requestUserID string, t
 		return nil, errors.New("target user id is missing")
 	}
 
+	key := permission.CacheKey(requestUserID, targetUserID)
+	if value, ok := c.cache.Get(key); ok {
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+		return value.(permission.Permissions), nil
+	}
+
 	url := c.client.ConstructURL("access", targetUserID, requestUserID)
 	result := permission.Permissions{}
 	if err := c.client.RequestData(ctx, "GET", url, nil, nil, &result); err != nil {
 		if request.IsErrorResourceNotFound(err) {
-			return nil, request.ErrorUnauthorized()
+			err = request.ErrorUnauthorized()
+			c.cache.SetError(key, err, requestUserID, targetUserID)
 		}
 		return nil, err
 	}
 
-	return permission.FixOwnerPermissions(result), nil
+	result = permission.FixOwnerPermissions(result)
+	c.cache.Set(key, result, requestUserID, targetUserID)
+	return result, nil
+}
+
+// InvalidateUser removes all cached permissions related to the user
+func (c *Client) InvalidateUser(userID string) {
+	c.cache.DeleteTagged(userID)
 }
@@ -23,11 +23,11 @@ import (
 )
 
 var _ = Describe("Client", func() {
-	var config *platform.Config
+	var config *permissionClient.Config
 	var authorizeAs platform.AuthorizeAs
 
 	BeforeEach(func() {
-		config = platform.NewConfig()
+		config = permissionClient.NewConfig()
 		config.UserAgent = testHttp.NewUserAgent()
 		authorizeAs = platform.AuthorizeAsService
 	})
@@ -51,6 +51,13 @@ var _ = Describe("Client", func() {
 			Expect(client).To(BeNil())
 		})
 
+		It("returns an error when the cache config is invalid", func() {
+			config.Cache.Size = -1
+			client, err := permissionClient.New(config, authorizeAs)
+			Expect(err).To(MatchError("config is invalid; cache is invalid; size is invalid"))
+			Expect(client).To(BeNil())
+		})
+
 		It("returns success", func() {
 			Expect(permissionClient.New(config, authorizeAs)).ToNot(BeNil())
 		})
@@ -166,6 +173,14 @@ var _ = Describe("Client", func() {
 						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
 						Expect(permissions).To(BeNil())
 					})
+
+					It("returns the cached unauthorized error without a second request", func() {
+						_, err := client.GetUserPermissions(ctx, requestUserID, targetUserID)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
+						permissions, err := client.GetUserPermissions(ctx, requestUserID, targetUserID)
+						errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
+						Expect(permissions).To(BeNil())
+					})
 				})
 
 				Context("with a successful response, but with no permissions", func() {
@@ -189,6 +204,14 @@ var _ = Describe("Client", func() {
 							permission.Read:  permission.Permission{},
 						}))
 					})
+
+					It("returns the cached permissions without a second request", func() {
+						Expect(client.GetUserPermissions(ctx, requestUserID, targetUserID)).ToNot(BeEmpty())
+						Expect(client.GetUserPermissions(ctx, requestUserID, targetUserID)).To(Equal(permission.Permissions{
+							permission.Write: permission.Permission{},
+							permission.Read:  permission.Permission{},
+						}))
+					})
 				})
 
 				Context("with a successful response with owner permissions that already includes upload permissions", func() {
@@ -233,6 +256,30 @@ var _ = Describe("Client", func() {
 					})
 				})
 			})
+
+			Context("with server responses before and after invalidating the user", func() {
+				BeforeEach(func() {
+					requestHandlers = append(requestHandlers,
+						VerifyRequest("GET", "/access/"+targetUserID+"/"+requestUserID),
+						RespondWith(http.StatusOK, `{"view": {}}`, responseHeaders),
+					)
+				})
+
+				It("requests the permissions again after invalidating the request user", func() {
+					server.AppendHandlers(CombineHandlers(
+						VerifyRequest("GET", "/access/"+targetUserID+"/"+requestUserID),
+						RespondWith(http.StatusNotFound, nil, responseHeaders),
+					))
+					Expect(client.GetUserPermissions(ctx, requestUserID, targetUserID)).To(HaveKey(permission.Read))
+					Expect(client.GetUserPermissions(ctx, requestUserID, targetUserID)).To(HaveKey(permission.Read))
+					Expect(server.ReceivedRequests()).To(HaveLen(1))
+					client.InvalidateUser(requestUserID)
+					permissions, err := client.GetUserPermissions(ctx, requestUserID, targetUserID)
+					errorsTest.ExpectEqual(err, request.ErrorUnauthorized())
+					Expect(permissions).To(BeNil())
+					Expect(server.ReceivedRequests()).To(HaveLen(2))
+				})
+			})
 		})
 	})
 })
@@ -16,6 +16,11 @@ type Client interface {
 	GetUserPermissions(ctx context.Context, requestUserID string, targetUserID string) (Permissions, error)
 }
 
+// CacheKey returns the key of the cached permissions the request user has for the target user
+func CacheKey(requestUserID string, targetUserID string) string {
+	return "permissions:" + requestUserID + ":" + targetUserID
+}
+
 func FixOwnerPermissions(permissions Permissions) Permissions {
 	if ownerPermission, ok := permissions[Owner]; ok {
 		if _, ok = permissions[Write]; !ok {
@@ -22,9 +22,10 @@ const (
 
 type Client struct {
 	*client.Client
-	authorizeAs   AuthorizeAs
-	serviceSecret string
-	httpClient    *http.Client
+	authorizeAs       AuthorizeAs
+	serviceSecret     string
+	clientCertificate bool
+	httpClient        *http.Client
 }
 
 func NewClient(cfg *Config, authorizeAs AuthorizeAs) (*Client, error) {
@@ -59,11 +60,21 @@ func NewClient(cfg *Config, authorizeAs AuthorizeAs) (*Client, error) {
 		Timeout: timeout,
 	}
 
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, err
+	} else if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient.Transport = transport
+	}
+
 	return &Client{
-		Client:        clnt,
-		authorizeAs:   authorizeAs,
-		serviceSecret: cfg.ServiceSecret,
-		httpClient:    httpClient,
+		Client:            clnt,
+		authorizeAs:       authorizeAs,
+		serviceSecret:     cfg.ServiceSecret,
+		clientCertificate: cfg.HasTLSCertificate(),
+		httpClient:        httpClient,
 	}, nil
 }
 
@@ -82,6 +93,8 @@ func (c *Client) Mutators(ctx context.Context) ([]request.RequestMutator, error)
 			authorizationMutator = NewServiceSecretHeaderMutator(c.serviceSecret)
 		} else if serverSessionToken := auth.ServerSessionTokenFromContext(ctx); serverSessionToken != "" {
 			authorizationMutator = NewSessionTokenHeaderMutator(serverSessionToken)
+		} else if c.clientCertificate {
+			return []request.RequestMutator{NewTraceMutator(ctx)}, nil // Authenticated by the client certificate
 		} else {
 			return nil, errors.New("service secret is missing")
 		}
@@ -90,7 +103,13 @@ func (c *Client) Mutators(ctx context.Context) ([]request.RequestMutator, error)
 		if details == nil {
 			return nil, errors.New("details is missing")
 		}
-		authorizationMutator = NewSessionTokenHeaderMutator(details.Token())
+		if details.Method() == request.MethodPersonalAccessToken {
+			authorizationMutator = NewPersonalAccessTokenHeaderMutator(details.Token())
+		} else if details.Method() == request.MethodOAuthAccessToken {
+			authorizationMutator = NewOAuthAccessTokenHeaderMutator(details.Token())
+		} else {
+			authorizationMutator = NewSessionTokenHeaderMutator(details.Token())
+		}
 	}
 	return []request.RequestMutator{authorizationMutator, NewTraceMutator(ctx)}, nil
 }
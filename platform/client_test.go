@@ -2,8 +2,19 @@ package platform_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -36,6 +47,8 @@ var _ = Describe("Client", func() {
 		var address string
 		var userAgent string
 		var serviceSecret string
+		var tlsCertificateFile string
+		var tlsKeyFile string
 		var ctx context.Context
 		var config *platform.Config
 
@@ -43,6 +56,8 @@ var _ = Describe("Client", func() {
 			address = testHttp.NewAddress()
 			userAgent = testHttp.NewUserAgent()
 			serviceSecret = authTest.NewServiceSecret()
+			tlsCertificateFile = ""
+			tlsKeyFile = ""
 			ctx = log.NewContextWithLogger(context.Background(), logTest.NewLogger())
 		})
 
@@ -53,6 +68,8 @@ var _ = Describe("Client", func() {
 			config.Address = address
 			config.UserAgent = userAgent
 			config.ServiceSecret = serviceSecret
+			config.TLSCertificateFile = tlsCertificateFile
+			config.TLSKeyFile = tlsKeyFile
 		})
 
 		Context("NewClient", func() {
@@ -137,6 +154,36 @@ var _ = Describe("Client", func() {
 						})
 					})
 
+					Context("with client certificate", func() {
+						var directory string
+
+						BeforeEach(func() {
+							var err error
+							directory, err = ioutil.TempDir("", "platform")
+							Expect(err).ToNot(HaveOccurred())
+							tlsCertificateFile, tlsKeyFile = writeCertificateAndKeyFiles(directory)
+						})
+
+						AfterEach(func() {
+							Expect(os.RemoveAll(directory)).To(Succeed())
+						})
+
+						It("returns only the trace mutator", func() {
+							mutators, err := clnt.Mutators(ctx)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(mutators).To(ConsistOf(
+								platform.NewTraceMutator(ctx),
+							))
+						})
+
+						It("presents the client certificate", func() {
+							transport, ok := clnt.HTTPClient().Transport.(*http.Transport)
+							Expect(ok).To(BeTrue())
+							Expect(transport.TLSClientConfig).ToNot(BeNil())
+							Expect(transport.TLSClientConfig.Certificates).To(HaveLen(1))
+						})
+					})
+
 					It("returns an error", func() {
 						mutators, err := clnt.Mutators(ctx)
 						Expect(err).To(MatchError("service secret is missing"))
@@ -358,6 +405,17 @@ var _ = Describe("Client", func() {
 						platform.NewTraceMutator(ctx),
 					))
 				})
+
+				It("returns the expected mutators with an oauth access token", func() {
+					oauthAccessToken := auth.NewOAuthSecret(auth.OAuthAccessTokenPrefix)
+					ctx = request.NewContextWithDetails(ctx, request.NewDetails(request.MethodOAuthAccessToken, test.RandomStringFromRangeAndCharset(10, 10, test.CharsetAlphaNumeric), oauthAccessToken))
+					mutators, err := clnt.Mutators(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mutators).To(ConsistOf(
+						platform.NewOAuthAccessTokenHeaderMutator(oauthAccessToken),
+						platform.NewTraceMutator(ctx),
+					))
+				})
 			})
 
 			Context("HTTPClient", func() {
@@ -530,3 +588,26 @@ var _ = Describe("Client", func() {
 		})
 	})
 })
+
+func writeCertificateAndKeyFiles(directory string) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "data"},
+		DNSNames:     []string{"data.tidepool.svc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	Expect(err).ToNot(HaveOccurred())
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	certificateFile := filepath.Join(directory, "client.crt")
+	Expect(ioutil.WriteFile(certificateFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes}), 0600)).To(Succeed())
+	keyFile := filepath.Join(directory, "client.key")
+	Expect(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}), 0600)).To(Succeed())
+	return certificateFile, keyFile
+}
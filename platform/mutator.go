@@ -27,6 +27,44 @@ func (s *SessionTokenHeaderMutator) MutateRequest(req *http.Request) error {
 	return s.HeaderMutator.MutateRequest(req)
 }
 
+type PersonalAccessTokenHeaderMutator struct {
+	*request.HeaderMutator
+}
+
+func NewPersonalAccessTokenHeaderMutator(personalAccessToken string) *PersonalAccessTokenHeaderMutator {
+	return &PersonalAccessTokenHeaderMutator{
+		HeaderMutator: request.NewHeaderMutator(auth.TidepoolPersonalAccessTokenHeaderKey, personalAccessToken),
+	}
+}
+
+func (p *PersonalAccessTokenHeaderMutator) MutateRequest(req *http.Request) error {
+	if p.HeaderMutator.Value == "" {
+		return errors.New("personal access token is missing")
+	}
+
+	return p.HeaderMutator.MutateRequest(req)
+}
+
+type OAuthAccessTokenHeaderMutator struct {
+	*request.HeaderMutator
+	OAuthAccessToken string
+}
+
+func NewOAuthAccessTokenHeaderMutator(oauthAccessToken string) *OAuthAccessTokenHeaderMutator {
+	return &OAuthAccessTokenHeaderMutator{
+		HeaderMutator:    request.NewHeaderMutator(auth.TidepoolAuthorizationHeaderKey, auth.OAuthTokenTypeBearer+" "+oauthAccessToken),
+		OAuthAccessToken: oauthAccessToken,
+	}
+}
+
+func (o *OAuthAccessTokenHeaderMutator) MutateRequest(req *http.Request) error {
+	if o.OAuthAccessToken == "" {
+		return errors.New("oauth access token is missing")
+	}
+
+	return o.HeaderMutator.MutateRequest(req)
+}
+
 type RestrictedTokenParameterMutator struct {
 	*request.ParameterMutator
 }
@@ -66,6 +66,86 @@ var _ = Describe("Mutator", func() {
 		})
 	})
 
+	Context("PersonalAccessTokenHeaderMutator", func() {
+		var personalAccessToken string
+
+		BeforeEach(func() {
+			personalAccessToken = auth.NewPersonalAccessTokenSecret()
+		})
+
+		Context("with new personal access token header mutator", func() {
+			var mutator *platform.PersonalAccessTokenHeaderMutator
+
+			BeforeEach(func() {
+				mutator = platform.NewPersonalAccessTokenHeaderMutator(personalAccessToken)
+				Expect(mutator).ToNot(BeNil())
+			})
+
+			It("remembers the personal access token header key", func() {
+				Expect(mutator.Key).To(Equal(auth.TidepoolPersonalAccessTokenHeaderKey))
+			})
+
+			Context("MutateRequest", func() {
+				var request *http.Request
+
+				BeforeEach(func() {
+					request = testHttp.NewRequest()
+				})
+
+				It("returns an error if the personal access token header value is missing", func() {
+					mutator.Value = ""
+					Expect(mutator.MutateRequest(request)).To(MatchError("personal access token is missing"))
+				})
+
+				It("adds the header", func() {
+					Expect(mutator.MutateRequest(request)).To(Succeed())
+					Expect(request.Header).To(HaveLen(1))
+					Expect(request.Header).To(HaveKeyWithValue(auth.TidepoolPersonalAccessTokenHeaderKey, []string{personalAccessToken}))
+				})
+			})
+		})
+	})
+
+	Context("OAuthAccessTokenHeaderMutator", func() {
+		var oauthAccessToken string
+
+		BeforeEach(func() {
+			oauthAccessToken = auth.NewOAuthSecret(auth.OAuthAccessTokenPrefix)
+		})
+
+		Context("with new oauth access token header mutator", func() {
+			var mutator *platform.OAuthAccessTokenHeaderMutator
+
+			BeforeEach(func() {
+				mutator = platform.NewOAuthAccessTokenHeaderMutator(oauthAccessToken)
+				Expect(mutator).ToNot(BeNil())
+			})
+
+			It("remembers the authorization header key", func() {
+				Expect(mutator.Key).To(Equal(auth.TidepoolAuthorizationHeaderKey))
+			})
+
+			Context("MutateRequest", func() {
+				var request *http.Request
+
+				BeforeEach(func() {
+					request = testHttp.NewRequest()
+				})
+
+				It("returns an error if the oauth access token is missing", func() {
+					mutator.OAuthAccessToken = ""
+					Expect(mutator.MutateRequest(request)).To(MatchError("oauth access token is missing"))
+				})
+
+				It("adds the header", func() {
+					Expect(mutator.MutateRequest(request)).To(Succeed())
+					Expect(request.Header).To(HaveLen(1))
+					Expect(request.Header).To(HaveKeyWithValue(auth.TidepoolAuthorizationHeaderKey, []string{"Bearer " + oauthAccessToken}))
+				})
+			})
+		})
+	})
+
 	Context("RestrictedTokenParameterMutator", func() {
 		var restrictedToken string
 
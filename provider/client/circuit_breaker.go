@@ -0,0 +1,110 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+)
+
+const (
+	CircuitBreakerStateClosed   = "closed"
+	CircuitBreakerStateHalfOpen = "halfOpen"
+	CircuitBreakerStateOpen     = "open"
+)
+
+// CircuitBreaker opens after the threshold of consecutive failures is reached and rejects all requests until the
+// timeout elapses. It is then half-open and allows requests; a single failure reopens it with double the previous
+// timeout, up to the maximum, while a single success closes it.
+type CircuitBreaker struct {
+	mutex          sync.Mutex
+	threshold      int
+	timeout        time.Duration
+	timeoutMaximum time.Duration
+	failures       int
+	openTimeout    time.Duration
+	openUntil      *time.Time
+}
+
+func NewCircuitBreaker(threshold int, timeout time.Duration, timeoutMaximum time.Duration) (*CircuitBreaker, error) {
+	if threshold < 1 {
+		return nil, errors.New("threshold is invalid")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("timeout is invalid")
+	}
+	if timeoutMaximum < timeout {
+		return nil, errors.New("timeout maximum is invalid")
+	}
+
+	return &CircuitBreaker{
+		threshold:      threshold,
+		timeout:        timeout,
+		timeoutMaximum: timeoutMaximum,
+	}, nil
+}
+
+func (c *CircuitBreaker) State() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.state(time.Now())
+}
+
+// Allow returns nil if a request is allowed, otherwise the time after which requests will be allowed
+func (c *CircuitBreaker) Allow() *time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state(time.Now()) != CircuitBreakerStateOpen {
+		return nil
+	}
+	openUntil := *c.openUntil
+	return &openUntil
+}
+
+func (c *CircuitBreaker) Success() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.failures = 0
+	c.openTimeout = 0
+	c.openUntil = nil
+}
+
+// Failure records a failure and returns the time after which requests will be allowed, if the failure opened the
+// circuit breaker
+func (c *CircuitBreaker) Failure() *time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	switch c.state(now) {
+	case CircuitBreakerStateOpen:
+		openUntil := *c.openUntil
+		return &openUntil
+	case CircuitBreakerStateHalfOpen:
+		c.openTimeout *= 2
+		if c.openTimeout > c.timeoutMaximum {
+			c.openTimeout = c.timeoutMaximum
+		}
+	default:
+		if c.failures++; c.failures < c.threshold {
+			return nil
+		}
+		c.openTimeout = c.timeout
+	}
+
+	openUntil := now.Add(c.openTimeout)
+	c.openUntil = &openUntil
+	return &openUntil
+}
+
+func (c *CircuitBreaker) state(now time.Time) string {
+	if c.openUntil == nil {
+		return CircuitBreakerStateClosed
+	} else if now.Before(*c.openUntil) {
+		return CircuitBreakerStateOpen
+	}
+	return CircuitBreakerStateHalfOpen
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	providerClient "github.com/tidepool-org/platform/provider/client"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	Context("NewCircuitBreaker", func() {
+		It("returns an error if the threshold is invalid", func() {
+			circuitBreaker, err := providerClient.NewCircuitBreaker(0, time.Minute, time.Hour)
+			Expect(err).To(MatchError("threshold is invalid"))
+			Expect(circuitBreaker).To(BeNil())
+		})
+
+		It("returns an error if the timeout is invalid", func() {
+			circuitBreaker, err := providerClient.NewCircuitBreaker(1, 0, time.Hour)
+			Expect(err).To(MatchError("timeout is invalid"))
+			Expect(circuitBreaker).To(BeNil())
+		})
+
+		It("returns an error if the timeout maximum is invalid", func() {
+			circuitBreaker, err := providerClient.NewCircuitBreaker(1, time.Minute, time.Second)
+			Expect(err).To(MatchError("timeout maximum is invalid"))
+			Expect(circuitBreaker).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(providerClient.NewCircuitBreaker(1, time.Minute, time.Hour)).ToNot(BeNil())
+		})
+	})
+
+	Context("with new circuit breaker", func() {
+		var circuitBreaker *providerClient.CircuitBreaker
+
+		BeforeEach(func() {
+			var err error
+			circuitBreaker, err = providerClient.NewCircuitBreaker(2, 20*time.Millisecond, 30*time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("is initially closed", func() {
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateClosed))
+			Expect(circuitBreaker.Allow()).To(BeNil())
+		})
+
+		It("remains closed while below the threshold", func() {
+			Expect(circuitBreaker.Failure()).To(BeNil())
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateClosed))
+		})
+
+		It("resets the failures on success", func() {
+			Expect(circuitBreaker.Failure()).To(BeNil())
+			circuitBreaker.Success()
+			Expect(circuitBreaker.Failure()).To(BeNil())
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateClosed))
+		})
+
+		It("opens at the threshold and then is half-open after the timeout", func() {
+			Expect(circuitBreaker.Failure()).To(BeNil())
+			openUntil := circuitBreaker.Failure()
+			Expect(openUntil).ToNot(BeNil())
+			Expect(*openUntil).To(BeTemporally("~", time.Now().Add(20*time.Millisecond), 10*time.Millisecond))
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateOpen))
+			Expect(circuitBreaker.Allow()).To(PointTo(Equal(*openUntil)))
+			Eventually(circuitBreaker.State).Should(Equal(providerClient.CircuitBreakerStateHalfOpen))
+			Expect(circuitBreaker.Allow()).To(BeNil())
+		})
+
+		It("reopens with a longer timeout up to the maximum on failure while half-open", func() {
+			circuitBreaker.Failure()
+			circuitBreaker.Failure()
+			Eventually(circuitBreaker.State).Should(Equal(providerClient.CircuitBreakerStateHalfOpen))
+			openUntil := circuitBreaker.Failure()
+			Expect(openUntil).ToNot(BeNil())
+			Expect(*openUntil).To(BeTemporally("~", time.Now().Add(30*time.Millisecond), 10*time.Millisecond))
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateOpen))
+		})
+
+		It("closes on success while half-open", func() {
+			circuitBreaker.Failure()
+			circuitBreaker.Failure()
+			Eventually(circuitBreaker.State).Should(Equal(providerClient.CircuitBreakerStateHalfOpen))
+			circuitBreaker.Success()
+			Expect(circuitBreaker.State()).To(Equal(providerClient.CircuitBreakerStateClosed))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/tidepool-org/platform/client"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/oauth"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/request"
+)
+
+var (
+	ClientRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tidepool_provider_client_requests_total",
+		Help: "The total number of partner requests sorted by provider and response status code",
+	}, []string{"provider", "status"})
+	ClientRateLimitWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tidepool_provider_client_rate_limit_wait_seconds",
+		Help:    "The duration partner requests waited for the rate limiter sorted by provider",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+	}, []string{"provider"})
+	ClientThrottledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tidepool_provider_client_throttled_total",
+		Help: "The total number of partner requests throttled by the partner sorted by provider",
+	}, []string{"provider"})
+	ClientRetryAfterSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tidepool_provider_client_retry_after_seconds",
+		Help:    "The duration the partner requested to wait before retrying sorted by provider",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+	}, []string{"provider"})
+	ClientCircuitBreakerOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tidepool_provider_client_circuit_breaker_open",
+		Help: "Whether the partner circuit breaker is open sorted by provider",
+	}, []string{"provider"})
+	ClientCircuitBreakerRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tidepool_provider_client_circuit_breaker_rejected_total",
+		Help: "The total number of partner requests rejected by an open circuit breaker sorted by provider",
+	}, []string{"provider"})
+)
+
+// Client sends OAuth requests to a partner. All requests are rate limited, a Retry-After response header pauses all
+// requests until the specified time, and consecutive server errors open a circuit breaker. Any error due to
+// throttling or an open circuit breaker includes the time after which requests may be retried (see RetryTime).
+type Client struct {
+	name              string
+	client            *client.Client
+	tokenSourceSource oauth.TokenSourceSource
+	retryAfterDefault time.Duration
+	limiter           *Limiter
+	circuitBreaker    *CircuitBreaker
+}
+
+func New(name string, cfg *Config, tokenSourceSource oauth.TokenSourceSource) (*Client, error) {
+	if name == "" {
+		return nil, errors.New("name is missing")
+	}
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+	if tokenSourceSource == nil {
+		return nil, errors.New("token source source is missing")
+	}
+
+	clnt, err := client.New(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter, err := NewLimiter(cfg.RateLimit, cfg.RateBurst)
+	if err != nil {
+		return nil, err
+	}
+
+	circuitBreaker, err := NewCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerTimeout, cfg.CircuitBreakerTimeoutMaximum)
+	if err != nil {
+		return nil, err
+	}
+
+	ClientCircuitBreakerOpen.WithLabelValues(name).Set(0)
+
+	return &Client{
+		name:              name,
+		client:            clnt,
+		tokenSourceSource: tokenSourceSource,
+		retryAfterDefault: cfg.RetryAfterDefault,
+		limiter:           limiter,
+		circuitBreaker:    circuitBreaker,
+	}, nil
+}
+
+func (c *Client) Name() string {
+	return c.name
+}
+
+func (c *Client) ConstructURL(paths ...string) string {
+	return c.client.ConstructURL(paths...)
+}
+
+func (c *Client) AppendURLQuery(urlString string, query map[string]string) string {
+	return c.client.AppendURLQuery(urlString, query)
+}
+
+func (c *Client) SendOAuthRequest(ctx context.Context, method string, url string, mutators []request.RequestMutator, requestBody interface{}, responseBody interface{}, httpClientSource oauth.HTTPClientSource) error {
+	if httpClientSource == nil {
+		return errors.New("http client source is missing")
+	}
+
+	httpClient, err := httpClientSource.HTTPClient(ctx, c.tokenSourceSource)
+	if err != nil {
+		return err
+	}
+
+	if retryTime := c.circuitBreaker.Allow(); retryTime != nil {
+		ClientCircuitBreakerRejectedTotal.WithLabelValues(c.name).Inc()
+		return NewRetryTimeError(ErrorCircuitBreakerOpen(), *retryTime)
+	}
+
+	now := time.Now()
+	if err = c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	ClientRateLimitWaitSeconds.WithLabelValues(c.name).Observe(time.Since(now).Seconds())
+
+	inspector := &responseInspector{}
+	err = c.client.RequestDataWithHTTPClient(ctx, method, url, mutators, requestBody, responseBody, []request.ResponseInspector{inspector}, httpClient)
+
+	if responseRecorder := ResponseRecorderFromContext(ctx); responseRecorder != nil {
+		responseRecorder.RecordResponse(method, url, inspector.StatusCode)
+	}
+
+	if inspector.StatusCode != 0 {
+		ClientRequestsTotal.WithLabelValues(c.name, strconv.Itoa(inspector.StatusCode)).Inc()
+	} else if err != nil {
+		ClientRequestsTotal.WithLabelValues(c.name, "error").Inc()
+	}
+
+	switch {
+	case inspector.StatusCode == http.StatusTooManyRequests:
+		c.circuitBreaker.Success()
+		ClientCircuitBreakerOpen.WithLabelValues(c.name).Set(0)
+		return c.throttled(ctx, err, inspector.RetryAfter)
+	case inspector.StatusCode >= http.StatusInternalServerError, inspector.StatusCode == 0 && err != nil && ctx != nil && ctx.Err() == nil:
+		if retryTime := c.circuitBreaker.Failure(); retryTime != nil {
+			ClientCircuitBreakerOpen.WithLabelValues(c.name).Set(1)
+			log.LoggerFromContext(ctx).WithField("retryTime", retryTime).Warn("Circuit breaker open")
+			return NewRetryTimeError(err, *retryTime)
+		} else if inspector.RetryAfter != nil {
+			return c.throttled(ctx, err, inspector.RetryAfter)
+		}
+	case inspector.StatusCode != 0:
+		c.circuitBreaker.Success()
+		ClientCircuitBreakerOpen.WithLabelValues(c.name).Set(0)
+	}
+
+	return err
+}
+
+func (c *Client) throttled(ctx context.Context, err error, retryAfter *time.Duration) error {
+	if retryAfter == nil {
+		retryAfter = pointer.FromDuration(c.retryAfterDefault)
+	}
+
+	retryTime := time.Now().Add(*retryAfter)
+	c.limiter.Pause(retryTime)
+
+	ClientThrottledTotal.WithLabelValues(c.name).Inc()
+	ClientRetryAfterSeconds.WithLabelValues(c.name).Observe(retryAfter.Seconds())
+	log.LoggerFromContext(ctx).WithField("retryTime", retryTime).Warn("Throttled by partner")
+
+	return NewRetryTimeError(err, retryTime)
+}
+
+func ErrorCircuitBreakerOpen() error {
+	return errors.New("circuit breaker is open")
+}
+
+// RetryTimeError is an error that includes the time after which the request may be retried
+type RetryTimeError struct {
+	err       error
+	retryTime time.Time
+}
+
+func NewRetryTimeError(err error, retryTime time.Time) error {
+	return &RetryTimeError{
+		err:       err,
+		retryTime: retryTime,
+	}
+}
+
+func (r *RetryTimeError) Error() string {
+	return r.err.Error()
+}
+
+func (r *RetryTimeError) Unwrap() error {
+	return r.err
+}
+
+func (r *RetryTimeError) RetryTime() time.Time {
+	return r.retryTime
+}
+
+// RetryTime returns the time after which the request may be retried, if the error includes one
+func RetryTime(err error) *time.Time {
+	if retryTimeErr, ok := errors.Cause(err).(*RetryTimeError); ok {
+		return pointer.FromTime(retryTimeErr.retryTime)
+	}
+	return nil
+}
+
+// ParseRetryAfter parses the Retry-After header value as either delay seconds or an HTTP date
+func ParseRetryAfter(value string, now time.Time) *time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 0); err == nil {
+		if seconds < 0 {
+			return nil
+		}
+		return pointer.FromDuration(time.Duration(seconds) * time.Second)
+	}
+
+	if retryTime, err := http.ParseTime(value); err == nil {
+		if retryAfter := retryTime.Sub(now); retryAfter > 0 {
+			return &retryAfter
+		}
+		return pointer.FromDuration(0)
+	}
+
+	return nil
+}
+
+type responseInspector struct {
+	StatusCode int
+	RetryAfter *time.Duration
+}
+
+func (r *responseInspector) InspectResponse(res *http.Response) error {
+	r.StatusCode = res.StatusCode
+	r.RetryAfter = ParseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	return nil
+}
@@ -0,0 +1,252 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/log"
+	logTest "github.com/tidepool-org/platform/log/test"
+	oauthTest "github.com/tidepool-org/platform/oauth/test"
+	providerClient "github.com/tidepool-org/platform/provider/client"
+	"github.com/tidepool-org/platform/request"
+	testHttp "github.com/tidepool-org/platform/test/http"
+)
+
+var _ = Describe("Client", func() {
+	var config *providerClient.Config
+	var tokenSourceSource *oauthTest.TokenSourceSource
+
+	BeforeEach(func() {
+		config = providerClient.NewConfig()
+		config.Address = testHttp.NewAddress()
+		config.UserAgent = testHttp.NewUserAgent()
+		tokenSourceSource = oauthTest.NewTokenSourceSource()
+	})
+
+	AfterEach(func() {
+		tokenSourceSource.AssertOutputsEmpty()
+	})
+
+	Context("New", func() {
+		It("returns an error when name is missing", func() {
+			clnt, err := providerClient.New("", config, tokenSourceSource)
+			Expect(err).To(MatchError("name is missing"))
+			Expect(clnt).To(BeNil())
+		})
+
+		It("returns an error when config is missing", func() {
+			clnt, err := providerClient.New("test", nil, tokenSourceSource)
+			Expect(err).To(MatchError("config is missing"))
+			Expect(clnt).To(BeNil())
+		})
+
+		It("returns an error when config is invalid", func() {
+			config.RateLimit = 0
+			clnt, err := providerClient.New("test", config, tokenSourceSource)
+			Expect(err).To(MatchError("config is invalid; rate limit is invalid"))
+			Expect(clnt).To(BeNil())
+		})
+
+		It("returns an error when token source source is missing", func() {
+			clnt, err := providerClient.New("test", config, nil)
+			Expect(err).To(MatchError("token source source is missing"))
+			Expect(clnt).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			clnt, err := providerClient.New("test", config, tokenSourceSource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clnt).ToNot(BeNil())
+			Expect(clnt.Name()).To(Equal("test"))
+		})
+	})
+
+	Context("with started server and new client", func() {
+		var server *Server
+		var ctx context.Context
+		var tokenSource *oauthTest.TokenSource
+		var clnt *providerClient.Client
+		var url string
+
+		BeforeEach(func() {
+			server = NewServer()
+			ctx = log.NewContextWithLogger(context.Background(), logTest.NewLogger())
+			tokenSource = oauthTest.NewTokenSource()
+			config.CircuitBreakerThreshold = 2
+		})
+
+		JustBeforeEach(func() {
+			config.Address = server.URL()
+			var err error
+			clnt, err = providerClient.New("test", config, tokenSourceSource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clnt).ToNot(BeNil())
+			url = clnt.ConstructURL("resource")
+		})
+
+		AfterEach(func() {
+			server.Close()
+			tokenSource.AssertOutputsEmpty()
+		})
+
+		sendOAuthRequest := func() error {
+			tokenSource.HTTPClientOutputs = append(tokenSource.HTTPClientOutputs, oauthTest.HTTPClientOutput{HTTPClient: http.DefaultClient})
+			return clnt.SendOAuthRequest(ctx, "GET", url, nil, nil, nil, tokenSource)
+		}
+
+		It("returns an error when http client source is missing", func() {
+			Expect(clnt.SendOAuthRequest(ctx, "GET", url, nil, nil, nil, nil)).To(MatchError("http client source is missing"))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("returns an error when http client source returns an error", func() {
+			responseErr := errorsTest.RandomError()
+			tokenSource.HTTPClientOutputs = []oauthTest.HTTPClientOutput{{HTTPClient: nil, Error: responseErr}}
+			Expect(clnt.SendOAuthRequest(ctx, "GET", url, nil, nil, nil, tokenSource)).To(Equal(responseErr))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("returns successfully", func() {
+			server.AppendHandlers(CombineHandlers(VerifyRequest("GET", "/resource"), RespondWith(http.StatusNoContent, nil)))
+			Expect(sendOAuthRequest()).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("records the response with the response recorder in the context", func() {
+			responseRecorder := &responseRecorder{}
+			ctx = providerClient.NewContextWithResponseRecorder(ctx, responseRecorder)
+			server.AppendHandlers(RespondWith(http.StatusNotFound, nil))
+			Expect(sendOAuthRequest()).ToNot(Succeed())
+			Expect(responseRecorder.responses).To(Equal([]recordedResponse{{method: "GET", url: url, statusCode: http.StatusNotFound}}))
+		})
+
+		It("returns an error without a retry time when the response is a client error", func() {
+			server.AppendHandlers(RespondWith(http.StatusNotFound, nil))
+			err := sendOAuthRequest()
+			Expect(err).To(MatchError("resource not found"))
+			Expect(providerClient.RetryTime(err)).To(BeNil())
+		})
+
+		Context("with too many requests", func() {
+			It("returns an error with the retry time from the retry after header in seconds", func() {
+				server.AppendHandlers(RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"120"}}))
+				err := sendOAuthRequest()
+				Expect(err).To(MatchError("too many requests"))
+				Expect(errors.Code(errors.Cause(err).(*providerClient.RetryTimeError).Unwrap())).To(Equal(request.ErrorCodeTooManyRequests))
+				retryTime := providerClient.RetryTime(errors.Wrap(err, "wrapped"))
+				Expect(retryTime).ToNot(BeNil())
+				Expect(*retryTime).To(BeTemporally("~", time.Now().Add(2*time.Minute), time.Second))
+			})
+
+			It("returns an error with the retry time from the retry after header as a date", func() {
+				retryAfter := time.Now().Add(time.Hour).UTC()
+				server.AppendHandlers(RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{retryAfter.Format(http.TimeFormat)}}))
+				retryTime := providerClient.RetryTime(sendOAuthRequest())
+				Expect(retryTime).ToNot(BeNil())
+				Expect(*retryTime).To(BeTemporally("~", retryAfter, 2*time.Second))
+			})
+
+			It("returns an error with the default retry time without a retry after header", func() {
+				server.AppendHandlers(RespondWith(http.StatusTooManyRequests, nil))
+				retryTime := providerClient.RetryTime(sendOAuthRequest())
+				Expect(retryTime).ToNot(BeNil())
+				Expect(*retryTime).To(BeTemporally("~", time.Now().Add(config.RetryAfterDefault), time.Second))
+			})
+
+			It("pauses subsequent requests until the retry time", func() {
+				server.AppendHandlers(
+					RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"1"}}),
+					RespondWith(http.StatusNoContent, nil),
+				)
+				now := time.Now()
+				Expect(sendOAuthRequest()).ToNot(Succeed())
+				Expect(sendOAuthRequest()).To(Succeed())
+				Expect(time.Since(now)).To(BeNumerically(">=", 900*time.Millisecond))
+			})
+		})
+
+		Context("with server errors", func() {
+			It("returns an error without a retry time while below the circuit breaker threshold", func() {
+				server.AppendHandlers(RespondWith(http.StatusInternalServerError, nil))
+				err := sendOAuthRequest()
+				Expect(err).To(HaveOccurred())
+				Expect(providerClient.RetryTime(err)).To(BeNil())
+			})
+
+			It("returns an error with the retry time from the retry after header while below the circuit breaker threshold", func() {
+				server.AppendHandlers(RespondWith(http.StatusServiceUnavailable, nil, http.Header{"Retry-After": []string{"30"}}))
+				retryTime := providerClient.RetryTime(sendOAuthRequest())
+				Expect(retryTime).ToNot(BeNil())
+				Expect(*retryTime).To(BeTemporally("~", time.Now().Add(30*time.Second), time.Second))
+			})
+
+			It("opens the circuit breaker at the threshold and rejects subsequent requests", func() {
+				server.AppendHandlers(RespondWith(http.StatusInternalServerError, nil), RespondWith(http.StatusBadGateway, nil))
+				Expect(sendOAuthRequest()).ToNot(Succeed())
+				err := sendOAuthRequest()
+				Expect(err).To(HaveOccurred())
+				retryTime := providerClient.RetryTime(err)
+				Expect(retryTime).ToNot(BeNil())
+				Expect(*retryTime).To(BeTemporally("~", time.Now().Add(config.CircuitBreakerTimeout), time.Second))
+				err = sendOAuthRequest()
+				Expect(err).To(MatchError("circuit breaker is open"))
+				Expect(providerClient.RetryTime(err)).To(Equal(retryTime))
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+	})
+
+	Context("ParseRetryAfter", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+		})
+
+		It("returns nil if the value is empty", func() {
+			Expect(providerClient.ParseRetryAfter("", now)).To(BeNil())
+		})
+
+		It("returns nil if the value is invalid", func() {
+			Expect(providerClient.ParseRetryAfter("invalid", now)).To(BeNil())
+		})
+
+		It("returns nil if the seconds are negative", func() {
+			Expect(providerClient.ParseRetryAfter("-1", now)).To(BeNil())
+		})
+
+		It("returns the duration from seconds", func() {
+			Expect(providerClient.ParseRetryAfter(" 90 ", now)).To(PointTo(Equal(90 * time.Second)))
+		})
+
+		It("returns the duration from a date", func() {
+			Expect(providerClient.ParseRetryAfter(now.Add(time.Hour).UTC().Format(http.TimeFormat), now)).To(PointTo(BeNumerically("~", time.Hour, time.Second)))
+		})
+
+		It("returns zero from a date in the past", func() {
+			Expect(providerClient.ParseRetryAfter(now.Add(-time.Hour).UTC().Format(http.TimeFormat), now)).To(PointTo(BeZero()))
+		})
+	})
+})
+
+type recordedResponse struct {
+	method     string
+	url        string
+	statusCode int
+}
+
+type responseRecorder struct {
+	responses []recordedResponse
+}
+
+func (r *responseRecorder) RecordResponse(method string, url string, statusCode int) {
+	r.responses = append(r.responses, recordedResponse{method: method, url: url, statusCode: statusCode})
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/client"
+	"github.com/tidepool-org/platform/config"
+	"github.com/tidepool-org/platform/errors"
+)
+
+type Config struct {
+	*client.Config
+	RateLimit                    float64 // Requests per second shared by all workers
+	RateBurst                    int
+	RetryAfterDefault            time.Duration
+	CircuitBreakerThreshold      int
+	CircuitBreakerTimeout        time.Duration
+	CircuitBreakerTimeoutMaximum time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Config:                       client.NewConfig(),
+		RateLimit:                    10,
+		RateBurst:                    20,
+		RetryAfterDefault:            time.Minute,
+		CircuitBreakerThreshold:      5,
+		CircuitBreakerTimeout:        time.Minute,
+		CircuitBreakerTimeoutMaximum: time.Hour,
+	}
+}
+
+func (c *Config) Load(configReporter config.Reporter) error {
+	if err := c.Config.Load(configReporter); err != nil {
+		return err
+	}
+
+	if rateLimitString, err := configReporter.Get("rate_limit"); err == nil {
+		var rateLimit float64
+		rateLimit, err = strconv.ParseFloat(rateLimitString, 64)
+		if err != nil {
+			return errors.New("rate limit is invalid")
+		}
+		c.RateLimit = rateLimit
+	}
+	if rateBurstString, err := configReporter.Get("rate_burst"); err == nil {
+		var rateBurst int64
+		rateBurst, err = strconv.ParseInt(rateBurstString, 10, 0)
+		if err != nil {
+			return errors.New("rate burst is invalid")
+		}
+		c.RateBurst = int(rateBurst)
+	}
+	if retryAfterDefaultString, err := configReporter.Get("retry_after_default"); err == nil {
+		var retryAfterDefault int64
+		retryAfterDefault, err = strconv.ParseInt(retryAfterDefaultString, 10, 0)
+		if err != nil {
+			return errors.New("retry after default is invalid")
+		}
+		c.RetryAfterDefault = time.Duration(retryAfterDefault) * time.Second
+	}
+	if circuitBreakerThresholdString, err := configReporter.Get("circuit_breaker_threshold"); err == nil {
+		var circuitBreakerThreshold int64
+		circuitBreakerThreshold, err = strconv.ParseInt(circuitBreakerThresholdString, 10, 0)
+		if err != nil {
+			return errors.New("circuit breaker threshold is invalid")
+		}
+		c.CircuitBreakerThreshold = int(circuitBreakerThreshold)
+	}
+	if circuitBreakerTimeoutString, err := configReporter.Get("circuit_breaker_timeout"); err == nil {
+		var circuitBreakerTimeout int64
+		circuitBreakerTimeout, err = strconv.ParseInt(circuitBreakerTimeoutString, 10, 0)
+		if err != nil {
+			return errors.New("circuit breaker timeout is invalid")
+		}
+		c.CircuitBreakerTimeout = time.Duration(circuitBreakerTimeout) * time.Second
+	}
+	if circuitBreakerTimeoutMaximumString, err := configReporter.Get("circuit_breaker_timeout_maximum"); err == nil {
+		var circuitBreakerTimeoutMaximum int64
+		circuitBreakerTimeoutMaximum, err = strconv.ParseInt(circuitBreakerTimeoutMaximumString, 10, 0)
+		if err != nil {
+			return errors.New("circuit breaker timeout maximum is invalid")
+		}
+		c.CircuitBreakerTimeoutMaximum = time.Duration(circuitBreakerTimeoutMaximum) * time.Second
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+
+	if c.RateLimit <= 0 {
+		return errors.New("rate limit is invalid")
+	}
+	if c.RateBurst < 1 {
+		return errors.New("rate burst is invalid")
+	}
+	if c.RetryAfterDefault <= 0 {
+		return errors.New("retry after default is invalid")
+	}
+	if c.CircuitBreakerThreshold < 1 {
+		return errors.New("circuit breaker threshold is invalid")
+	}
+	if c.CircuitBreakerTimeout <= 0 {
+		return errors.New("circuit breaker timeout is invalid")
+	}
+	if c.CircuitBreakerTimeoutMaximum < c.CircuitBreakerTimeout {
+		return errors.New("circuit breaker timeout maximum is invalid")
+	}
+
+	return nil
+}
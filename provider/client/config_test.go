@@ -0,0 +1,122 @@
+package client_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	providerClient "github.com/tidepool-org/platform/provider/client"
+	testHttp "github.com/tidepool-org/platform/test/http"
+)
+
+var _ = Describe("Config", func() {
+	var config *providerClient.Config
+
+	BeforeEach(func() {
+		config = providerClient.NewConfig()
+		Expect(config).ToNot(BeNil())
+	})
+
+	It("returns the default values", func() {
+		Expect(config.Config).ToNot(BeNil())
+		Expect(config.RateLimit).To(Equal(10.0))
+		Expect(config.RateBurst).To(Equal(20))
+		Expect(config.RetryAfterDefault).To(Equal(time.Minute))
+		Expect(config.CircuitBreakerThreshold).To(Equal(5))
+		Expect(config.CircuitBreakerTimeout).To(Equal(time.Minute))
+		Expect(config.CircuitBreakerTimeoutMaximum).To(Equal(time.Hour))
+	})
+
+	Context("Load", func() {
+		var configReporter *configTest.Reporter
+
+		BeforeEach(func() {
+			configReporter = configTest.NewReporter()
+		})
+
+		It("returns an error if the config reporter is missing", func() {
+			Expect(config.Load(nil)).To(MatchError("config reporter is missing"))
+		})
+
+		It("returns an error if the rate limit is invalid", func() {
+			configReporter.Config["rate_limit"] = "invalid"
+			Expect(config.Load(configReporter)).To(MatchError("rate limit is invalid"))
+		})
+
+		It("returns an error if the rate burst is invalid", func() {
+			configReporter.Config["rate_burst"] = "invalid"
+			Expect(config.Load(configReporter)).To(MatchError("rate burst is invalid"))
+		})
+
+		It("returns an error if the circuit breaker timeout is invalid", func() {
+			configReporter.Config["circuit_breaker_timeout"] = "invalid"
+			Expect(config.Load(configReporter)).To(MatchError("circuit breaker timeout is invalid"))
+		})
+
+		It("returns successfully and sets the values", func() {
+			configReporter.Config["address"] = "https://test.org"
+			configReporter.Config["rate_limit"] = "2.5"
+			configReporter.Config["rate_burst"] = "5"
+			configReporter.Config["retry_after_default"] = "30"
+			configReporter.Config["circuit_breaker_threshold"] = "3"
+			configReporter.Config["circuit_breaker_timeout"] = "120"
+			configReporter.Config["circuit_breaker_timeout_maximum"] = "1800"
+			Expect(config.Load(configReporter)).To(Succeed())
+			Expect(config.Address).To(Equal("https://test.org"))
+			Expect(config.RateLimit).To(Equal(2.5))
+			Expect(config.RateBurst).To(Equal(5))
+			Expect(config.RetryAfterDefault).To(Equal(30 * time.Second))
+			Expect(config.CircuitBreakerThreshold).To(Equal(3))
+			Expect(config.CircuitBreakerTimeout).To(Equal(2 * time.Minute))
+			Expect(config.CircuitBreakerTimeoutMaximum).To(Equal(30 * time.Minute))
+		})
+	})
+
+	Context("Validate", func() {
+		BeforeEach(func() {
+			config.Address = testHttp.NewAddress()
+			config.UserAgent = testHttp.NewUserAgent()
+		})
+
+		It("returns an error if the address is missing", func() {
+			config.Address = ""
+			Expect(config.Validate()).To(MatchError("address is missing"))
+		})
+
+		It("returns an error if the rate limit is invalid", func() {
+			config.RateLimit = 0
+			Expect(config.Validate()).To(MatchError("rate limit is invalid"))
+		})
+
+		It("returns an error if the rate burst is invalid", func() {
+			config.RateBurst = 0
+			Expect(config.Validate()).To(MatchError("rate burst is invalid"))
+		})
+
+		It("returns an error if the retry after default is invalid", func() {
+			config.RetryAfterDefault = 0
+			Expect(config.Validate()).To(MatchError("retry after default is invalid"))
+		})
+
+		It("returns an error if the circuit breaker threshold is invalid", func() {
+			config.CircuitBreakerThreshold = 0
+			Expect(config.Validate()).To(MatchError("circuit breaker threshold is invalid"))
+		})
+
+		It("returns an error if the circuit breaker timeout is invalid", func() {
+			config.CircuitBreakerTimeout = 0
+			Expect(config.Validate()).To(MatchError("circuit breaker timeout is invalid"))
+		})
+
+		It("returns an error if the circuit breaker timeout maximum is less than the timeout", func() {
+			config.CircuitBreakerTimeoutMaximum = config.CircuitBreakerTimeout - time.Second
+			Expect(config.Validate()).To(MatchError("circuit breaker timeout maximum is invalid"))
+		})
+
+		It("returns successfully", func() {
+			Expect(config.Validate()).To(Succeed())
+		})
+	})
+})
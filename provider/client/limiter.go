@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+)
+
+// Limiter is a token bucket rate limiter that may additionally be paused until a specific time, for example, when
+// the partner responds with a Retry-After header. A single limiter is shared by all workers of a provider.
+type Limiter struct {
+	mutex       sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	lastTime    time.Time
+	pausedUntil time.Time
+}
+
+func NewLimiter(rate float64, burst int) (*Limiter, error) {
+	if rate <= 0 {
+		return nil, errors.New("rate is invalid")
+	}
+	if burst < 1 {
+		return nil, errors.New("burst is invalid")
+	}
+
+	return &Limiter{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastTime: time.Now(),
+	}, nil
+}
+
+// Wait blocks until a token is available or the context is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// Pause prevents any token from being available until the specified time
+func (l *Limiter) Pause(until time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+func (l *Limiter) PausedUntil() *time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.pausedUntil.After(time.Now()) {
+		return nil
+	}
+	pausedUntil := l.pausedUntil
+	return &pausedUntil
+}
+
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.advance(now)
+	l.tokens--
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.pausedUntil.Sub(now); pause > delay {
+		delay = pause
+	}
+	return delay
+}
+
+func (l *Limiter) cancel() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.advance(time.Now())
+	if l.tokens++; l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+func (l *Limiter) advance(now time.Time) {
+	if elapsed := now.Sub(l.lastTime); elapsed > 0 {
+		if l.tokens += elapsed.Seconds() * l.rate; l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.lastTime = now
+	}
+}
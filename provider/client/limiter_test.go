@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	providerClient "github.com/tidepool-org/platform/provider/client"
+)
+
+var _ = Describe("Limiter", func() {
+	Context("NewLimiter", func() {
+		It("returns an error if the rate is invalid", func() {
+			limiter, err := providerClient.NewLimiter(0, 1)
+			Expect(err).To(MatchError("rate is invalid"))
+			Expect(limiter).To(BeNil())
+		})
+
+		It("returns an error if the burst is invalid", func() {
+			limiter, err := providerClient.NewLimiter(1, 0)
+			Expect(err).To(MatchError("burst is invalid"))
+			Expect(limiter).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(providerClient.NewLimiter(1, 1)).ToNot(BeNil())
+		})
+	})
+
+	Context("with new limiter", func() {
+		var limiter *providerClient.Limiter
+		var ctx context.Context
+
+		BeforeEach(func() {
+			var err error
+			limiter, err = providerClient.NewLimiter(20, 2)
+			Expect(err).ToNot(HaveOccurred())
+			ctx = context.Background()
+		})
+
+		Context("Wait", func() {
+			It("returns an error if the context is missing", func() {
+				Expect(limiter.Wait(nil)).To(MatchError("context is missing"))
+			})
+
+			It("does not wait while tokens are available", func() {
+				now := time.Now()
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(time.Since(now)).To(BeNumerically("<", 25*time.Millisecond))
+			})
+
+			It("waits for a token once the burst is exhausted", func() {
+				now := time.Now()
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(time.Since(now)).To(BeNumerically(">=", 40*time.Millisecond))
+			})
+
+			It("returns an error if the context is done while waiting", func() {
+				limiter.Pause(time.Now().Add(time.Minute))
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				defer cancel()
+				Expect(limiter.Wait(ctx)).To(MatchError(context.DeadlineExceeded))
+			})
+		})
+
+		Context("Pause", func() {
+			It("waits until the pause ends", func() {
+				pausedUntil := time.Now().Add(50 * time.Millisecond)
+				limiter.Pause(pausedUntil)
+				Expect(limiter.PausedUntil()).To(PointTo(Equal(pausedUntil)))
+				Expect(limiter.Wait(ctx)).To(Succeed())
+				Expect(time.Now()).To(BeTemporally(">=", pausedUntil))
+				Expect(limiter.PausedUntil()).To(BeNil())
+			})
+
+			It("does not shorten an existing pause", func() {
+				pausedUntil := time.Now().Add(time.Minute)
+				limiter.Pause(pausedUntil)
+				limiter.Pause(time.Now().Add(time.Second))
+				Expect(limiter.PausedUntil()).To(PointTo(Equal(pausedUntil)))
+			})
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package client
+
+import "context"
+
+// ResponseRecorder is notified of each partner request sent with a context that includes it. The status code is zero
+// if there was no response.
+type ResponseRecorder interface {
+	RecordResponse(method string, url string, statusCode int)
+}
+
+type contextKey string
+
+const responseRecorderContextKey contextKey = "responseRecorder"
+
+func NewContextWithResponseRecorder(ctx context.Context, responseRecorder ResponseRecorder) context.Context {
+	return context.WithValue(ctx, responseRecorderContextKey, responseRecorder)
+}
+
+func ResponseRecorderFromContext(ctx context.Context) ResponseRecorder {
+	if ctx != nil {
+		if responseRecorder, ok := ctx.Value(responseRecorderContextKey).(ResponseRecorder); ok {
+			return responseRecorder
+		}
+	}
+	return nil
+}
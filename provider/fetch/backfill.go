@@ -0,0 +1,115 @@
+package fetch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+const BackfillDurationMaximum = 90 * 24 * time.Hour
+
+// Backfill is a bounded window of historical data to fetch again, for example after the partner corrects their data.
+// The refetched data replaces any existing data with the same origin id or, if the data set does not use the origin
+// deduplicator, any existing data of the same type within the window.
+type Backfill struct {
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+func NewBackfill() *Backfill {
+	return &Backfill{}
+}
+
+func (b *Backfill) Parse(parser structure.ObjectParser) {
+	b.StartTime = parser.Time("startTime", time.RFC3339Nano)
+	b.EndTime = parser.Time("endTime", time.RFC3339Nano)
+}
+
+func (b *Backfill) Validate(validator structure.Validator) {
+	validator.Time("startTime", b.StartTime).Exists().NotZero()
+	endTimeValidator := validator.Time("endTime", b.EndTime).Exists().BeforeNow(time.Second)
+	if b.StartTime != nil {
+		endTimeValidator.After(*b.StartTime).Before(b.StartTime.Add(BackfillDurationMaximum))
+	}
+}
+
+// TaskType returns the fetch task type of the partner with the provider type and name
+func TaskType(providerType string, providerName string) string {
+	return fmt.Sprintf("org.tidepool.%s.%s.fetch", providerType, providerName)
+}
+
+func BackfillTaskName(taskType string, providerSessionID string, backfill *Backfill) string {
+	return fmt.Sprintf("%s:backfill:%d-%d", TaskName(taskType, providerSessionID), backfill.StartTime.Unix(), backfill.EndTime.Unix())
+}
+
+// NewBackfillTaskCreate returns the task create for a backfill. The task runs once with the same runner as the
+// fetch task of the data source.
+func NewBackfillTaskCreate(taskType string, providerSessionID string, dataSourceID string, backfill *Backfill) (*task.TaskCreate, error) {
+	if taskType == "" {
+		return nil, errors.New("task type is missing")
+	}
+	if providerSessionID == "" {
+		return nil, errors.New("provider session id is missing")
+	}
+	if dataSourceID == "" {
+		return nil, errors.New("data source id is missing")
+	}
+	if backfill == nil {
+		return nil, errors.New("backfill is missing")
+	} else if err := structureValidator.New().Validate(backfill); err != nil {
+		return nil, errors.Wrap(err, "backfill is invalid")
+	}
+
+	return &task.TaskCreate{
+		Name: pointer.FromString(BackfillTaskName(taskType, providerSessionID, backfill)),
+		Type: taskType,
+		Data: map[string]interface{}{
+			"providerSessionId": providerSessionID,
+			"dataSourceId":      dataSourceID,
+			"backfillStartTime": backfill.StartTime.Format(time.RFC3339Nano),
+			"backfillEndTime":   backfill.EndTime.Format(time.RFC3339Nano),
+		},
+	}, nil
+}
+
+// BackfillFromTaskData returns the backfill from the task data, or nil if the task is not a backfill task
+func BackfillFromTaskData(data map[string]interface{}) (*Backfill, error) {
+	if _, ok := data["backfillStartTime"]; !ok {
+		return nil, nil
+	}
+
+	startTime := parseTaskDataTime(data["backfillStartTime"])
+	if startTime == nil {
+		return nil, errors.New("backfill start time is invalid")
+	}
+	endTime := parseTaskDataTime(data["backfillEndTime"])
+	if endTime == nil {
+		return nil, errors.New("backfill end time is invalid")
+	}
+
+	backfill := &Backfill{
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	if backfill.EndTime.Before(*backfill.StartTime) {
+		return nil, errors.New("backfill end time is invalid")
+	}
+	return backfill, nil
+}
+
+func parseTaskDataTime(raw interface{}) *time.Time {
+	switch value := raw.(type) {
+	case string:
+		if tm, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return &tm
+		}
+	case time.Time:
+		return &value
+	}
+	return nil
+}
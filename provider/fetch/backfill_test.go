@@ -0,0 +1,143 @@
+package fetch_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/pointer"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/test"
+)
+
+var _ = Describe("Backfill", func() {
+	var backfill *providerFetch.Backfill
+
+	BeforeEach(func() {
+		endTime := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		backfill = providerFetch.NewBackfill()
+		backfill.StartTime = pointer.FromTime(endTime.Add(-7 * 24 * time.Hour))
+		backfill.EndTime = pointer.FromTime(endTime)
+	})
+
+	Context("Validate", func() {
+		It("returns successfully", func() {
+			Expect(structureValidator.New().Validate(backfill)).To(Succeed())
+		})
+
+		It("returns an error if the start time is missing", func() {
+			backfill.StartTime = nil
+			Expect(structureValidator.New().Validate(backfill)).To(HaveOccurred())
+		})
+
+		It("returns an error if the end time is missing", func() {
+			backfill.EndTime = nil
+			Expect(structureValidator.New().Validate(backfill)).To(HaveOccurred())
+		})
+
+		It("returns an error if the end time is not before now", func() {
+			backfill.EndTime = pointer.FromTime(time.Now().Add(time.Hour))
+			Expect(structureValidator.New().Validate(backfill)).To(HaveOccurred())
+		})
+
+		It("returns an error if the end time is before the start time", func() {
+			backfill.EndTime = pointer.FromTime(backfill.StartTime.Add(-time.Second))
+			Expect(structureValidator.New().Validate(backfill)).To(HaveOccurred())
+		})
+
+		It("returns an error if the window exceeds the maximum", func() {
+			backfill.StartTime = pointer.FromTime(backfill.EndTime.Add(-providerFetch.BackfillDurationMaximum - time.Second))
+			Expect(structureValidator.New().Validate(backfill)).To(HaveOccurred())
+		})
+	})
+
+	Context("TaskType", func() {
+		It("returns the fetch task type", func() {
+			Expect(providerFetch.TaskType("oauth", "dexcom")).To(Equal("org.tidepool.oauth.dexcom.fetch"))
+		})
+	})
+
+	Context("NewBackfillTaskCreate", func() {
+		var taskType string
+		var providerSessionID string
+		var dataSourceID string
+
+		BeforeEach(func() {
+			taskType = test.RandomStringFromRange(1, 32)
+			providerSessionID = test.RandomStringFromRange(1, 32)
+			dataSourceID = test.RandomStringFromRange(1, 32)
+		})
+
+		It("returns an error if the task type is missing", func() {
+			taskCreate, err := providerFetch.NewBackfillTaskCreate("", providerSessionID, dataSourceID, backfill)
+			Expect(err).To(MatchError("task type is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the provider session id is missing", func() {
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(taskType, "", dataSourceID, backfill)
+			Expect(err).To(MatchError("provider session id is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the data source id is missing", func() {
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(taskType, providerSessionID, "", backfill)
+			Expect(err).To(MatchError("data source id is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the backfill is missing", func() {
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(taskType, providerSessionID, dataSourceID, nil)
+			Expect(err).To(MatchError("backfill is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the backfill is invalid", func() {
+			backfill.EndTime = nil
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(taskType, providerSessionID, dataSourceID, backfill)
+			Expect(err).To(MatchError(HavePrefix("backfill is invalid")))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(taskType, providerSessionID, dataSourceID, backfill)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taskCreate).ToNot(BeNil())
+			Expect(taskCreate.Name).To(Equal(pointer.FromString(providerFetch.BackfillTaskName(taskType, providerSessionID, backfill))))
+			Expect(taskCreate.Type).To(Equal(taskType))
+			Expect(providerFetch.BackfillFromTaskData(taskCreate.Data)).To(Equal(backfill))
+			Expect(taskCreate.Data).To(HaveKeyWithValue("providerSessionId", providerSessionID))
+			Expect(taskCreate.Data).To(HaveKeyWithValue("dataSourceId", dataSourceID))
+		})
+	})
+
+	Context("BackfillFromTaskData", func() {
+		It("returns nil if the task is not a backfill task", func() {
+			Expect(providerFetch.BackfillFromTaskData(map[string]interface{}{"dataSourceId": "test"})).To(BeNil())
+		})
+
+		It("returns an error if the start time is invalid", func() {
+			result, err := providerFetch.BackfillFromTaskData(map[string]interface{}{"backfillStartTime": "invalid", "backfillEndTime": backfill.EndTime.Format(time.RFC3339Nano)})
+			Expect(err).To(MatchError("backfill start time is invalid"))
+			Expect(result).To(BeNil())
+		})
+
+		It("returns an error if the end time is missing", func() {
+			result, err := providerFetch.BackfillFromTaskData(map[string]interface{}{"backfillStartTime": backfill.StartTime.Format(time.RFC3339Nano)})
+			Expect(err).To(MatchError("backfill end time is invalid"))
+			Expect(result).To(BeNil())
+		})
+
+		It("returns an error if the end time is before the start time", func() {
+			result, err := providerFetch.BackfillFromTaskData(map[string]interface{}{"backfillStartTime": *backfill.EndTime, "backfillEndTime": *backfill.StartTime})
+			Expect(err).To(MatchError("backfill end time is invalid"))
+			Expect(result).To(BeNil())
+		})
+
+		It("returns successfully with times", func() {
+			Expect(providerFetch.BackfillFromTaskData(map[string]interface{}{"backfillStartTime": *backfill.StartTime, "backfillEndTime": *backfill.EndTime})).To(Equal(backfill))
+		})
+	})
+})
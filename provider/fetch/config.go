@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/config"
+	dataSource "github.com/tidepool-org/platform/data/source"
+	"github.com/tidepool-org/platform/errors"
+)
+
+// Config contains the recurring maintenance windows of a provider as a comma-separated list of daily windows, each
+// formatted as "HH:MM-HH:MM Location" (for example, "02:45-03:45 America/Los_Angeles"). A window may span midnight.
+// It also contains the data source health policy, that is, the number of consecutive authentication failures and the
+// duration without a successful import after which a data source requires the user to reconnect.
+type Config struct {
+	MaintenanceWindows                string
+	AuthenticationFailureCountMaximum int
+	FailureDurationMaximum            time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		AuthenticationFailureCountMaximum: dataSource.HealthAuthenticationFailureCountMaximumDefault,
+		FailureDurationMaximum:            dataSource.HealthFailureDurationMaximumDefault,
+	}
+}
+
+func (c *Config) Load(configReporter config.Reporter) error {
+	if configReporter == nil {
+		return errors.New("config reporter is missing")
+	}
+
+	c.MaintenanceWindows = configReporter.GetWithDefault("maintenance_windows", c.MaintenanceWindows)
+	if authenticationFailureCountMaximumString, err := configReporter.Get("authentication_failure_count_maximum"); err == nil {
+		var authenticationFailureCountMaximum int64
+		authenticationFailureCountMaximum, err = strconv.ParseInt(authenticationFailureCountMaximumString, 10, 0)
+		if err != nil {
+			return errors.New("authentication failure count maximum is invalid")
+		}
+		c.AuthenticationFailureCountMaximum = int(authenticationFailureCountMaximum)
+	}
+	if failureDurationMaximumString, err := configReporter.Get("failure_duration_maximum"); err == nil {
+		var failureDurationMaximum int64
+		failureDurationMaximum, err = strconv.ParseInt(failureDurationMaximumString, 10, 0)
+		if err != nil {
+			return errors.New("failure duration maximum is invalid")
+		}
+		c.FailureDurationMaximum = time.Duration(failureDurationMaximum) * time.Second
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if _, err := ParseRecurringWindows(c.MaintenanceWindows); err != nil {
+		return errors.Wrap(err, "maintenance windows is invalid")
+	}
+	if c.AuthenticationFailureCountMaximum < 1 {
+		return errors.New("authentication failure count maximum is invalid")
+	}
+	if c.FailureDurationMaximum <= 0 {
+		return errors.New("failure duration maximum is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package fetch_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
+)
+
+var _ = Describe("Config", func() {
+	var config *providerFetch.Config
+
+	BeforeEach(func() {
+		config = providerFetch.NewConfig()
+		Expect(config).ToNot(BeNil())
+	})
+
+	It("returns the default health policy", func() {
+		Expect(config.AuthenticationFailureCountMaximum).To(Equal(3))
+		Expect(config.FailureDurationMaximum).To(Equal(30 * 24 * time.Hour))
+	})
+
+	Context("Load", func() {
+		var configReporter *configTest.Reporter
+
+		BeforeEach(func() {
+			configReporter = configTest.NewReporter()
+		})
+
+		It("returns an error if the config reporter is missing", func() {
+			Expect(config.Load(nil)).To(MatchError("config reporter is missing"))
+		})
+
+		It("loads the maintenance windows", func() {
+			configReporter.Config["maintenance_windows"] = "01:00-02:00 UTC"
+			Expect(config.Load(configReporter)).To(Succeed())
+			Expect(config.MaintenanceWindows).To(Equal("01:00-02:00 UTC"))
+		})
+
+		It("returns an error if the authentication failure count maximum is invalid", func() {
+			configReporter.Config["authentication_failure_count_maximum"] = "invalid"
+			Expect(config.Load(configReporter)).To(MatchError("authentication failure count maximum is invalid"))
+		})
+
+		It("returns an error if the failure duration maximum is invalid", func() {
+			configReporter.Config["failure_duration_maximum"] = "invalid"
+			Expect(config.Load(configReporter)).To(MatchError("failure duration maximum is invalid"))
+		})
+
+		It("loads the health policy", func() {
+			configReporter.Config["authentication_failure_count_maximum"] = "5"
+			configReporter.Config["failure_duration_maximum"] = "86400"
+			Expect(config.Load(configReporter)).To(Succeed())
+			Expect(config.AuthenticationFailureCountMaximum).To(Equal(5))
+			Expect(config.FailureDurationMaximum).To(Equal(24 * time.Hour))
+		})
+	})
+
+	Context("Validate", func() {
+		It("returns an error if the maintenance windows are invalid", func() {
+			config.MaintenanceWindows = "invalid"
+			Expect(config.Validate()).To(MatchError(`maintenance windows is invalid; recurring window "invalid" is invalid`))
+		})
+
+		It("returns an error if the authentication failure count maximum is invalid", func() {
+			config.AuthenticationFailureCountMaximum = 0
+			Expect(config.Validate()).To(MatchError("authentication failure count maximum is invalid"))
+		})
+
+		It("returns an error if the failure duration maximum is invalid", func() {
+			config.FailureDurationMaximum = 0
+			Expect(config.Validate()).To(MatchError("failure duration maximum is invalid"))
+		})
+
+		It("validates successfully without maintenance windows", func() {
+			Expect(config.Validate()).To(Succeed())
+		})
+	})
+})
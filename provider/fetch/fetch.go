@@ -0,0 +1,150 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tidepool-org/platform/data"
+	dataDeduplicatorDeduplicator "github.com/tidepool-org/platform/data/deduplicator/deduplicator"
+	dataTypesUpload "github.com/tidepool-org/platform/data/types/upload"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/oauth"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/task"
+)
+
+const (
+	FetchRequirementNone    = "none"    // Always fetch data in the window
+	FetchRequirementDevices = "devices" // Only fetch data in the window if at least one device is found
+	FetchRequirementDataSet = "dataSet" // Only fetch data in the window if a data set already exists
+)
+
+const WindowDuration = 30 * 24 * time.Hour
+
+// Partner is implemented by each cloud partner to list devices, fetch records, and translate both to datums. Each
+// returned data datum must include the partner system time as the "systemTime" payload field, which is used to
+// track the earliest and latest data time of the data source.
+type Partner interface {
+	TaskType() string
+	InitialDataTime() time.Time
+	NewDataSetCreate() *data.DataSetCreate
+	FetchRequirement(startTime time.Time, endTime time.Time) string
+
+	ListDevices(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (Devices, error)
+	FetchData(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (data.Data, error)
+}
+
+// DataRangePartner is optionally implemented by a partner that can report the time range of all available data,
+// which allows windows without any data to be skipped rather than probed. If the returned data range is nil, then the
+// data range is not known and all windows are probed. If the returned data range start or end time is nil, then
+// there is no data available.
+type DataRangePartner interface {
+	DataRange(ctx context.Context, tokenSource oauth.TokenSource) (*DataRange, error)
+}
+
+type DataRange struct {
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// ReconciliationPartner is optionally implemented by a partner whose records can be edited or deleted by the user after
+// being imported. Each task run fetches the records in the reconciliation window preceding the latest data time and
+// replaces or deletes any previously imported data, by origin id, whose record has since changed.
+type ReconciliationPartner interface {
+	ReconciliationDuration() time.Duration
+	FetchRecords(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (Records, error)
+}
+
+// Record is a partner record that may be edited or deleted by the user. The origin id matches the origin id of any
+// datum previously imported from the record. If the hash is empty, then the record is never reconciled. If the record
+// is not deleted and the datum is nil, then the record is not imported.
+type Record struct {
+	OriginID string
+	Hash     string
+	Deleted  bool
+	Datum    data.Datum
+}
+
+type Records []*Record
+
+// Device is a partner device. If the hash is empty, then the device is never stored.
+type Device struct {
+	ID    string
+	Hash  string
+	Datum data.Datum
+}
+
+type Devices []*Device
+
+func TaskName(taskType string, providerSessionID string) string {
+	return fmt.Sprintf("%s:%s", taskType, providerSessionID)
+}
+
+func NewTaskCreate(taskType string, providerSessionID string, dataSourceID string) (*task.TaskCreate, error) {
+	if taskType == "" {
+		return nil, errors.New("task type is missing")
+	}
+	if providerSessionID == "" {
+		return nil, errors.New("provider session id is missing")
+	}
+	if dataSourceID == "" {
+		return nil, errors.New("data source id is missing")
+	}
+
+	return &task.TaskCreate{
+		Name: pointer.FromString(TaskName(taskType, providerSessionID)),
+		Type: taskType,
+		Data: map[string]interface{}{
+			"providerSessionId": providerSessionID,
+			"dataSourceId":      dataSourceID,
+		},
+	}, nil
+}
+
+func NewDataSetCreate(clientName string, clientVersion string, deviceManufacturers []string, deviceTags []string) *data.DataSetCreate {
+	dataSetCreate := data.NewDataSetCreate()
+	dataSetCreate.Client = &data.DataSetClient{
+		Name:    pointer.FromString(clientName),
+		Version: pointer.FromString(clientVersion),
+	}
+	dataSetCreate.DataSetType = pointer.FromString(data.DataSetTypeContinuous)
+	dataSetCreate.Deduplicator = data.NewDeduplicatorDescriptor()
+	dataSetCreate.Deduplicator.Name = pointer.FromString(dataDeduplicatorDeduplicator.DataSetDeleteOriginName)
+	dataSetCreate.DeviceManufacturers = pointer.FromStringArray(deviceManufacturers)
+	dataSetCreate.DeviceTags = pointer.FromStringArray(deviceTags)
+	dataSetCreate.TimeProcessing = pointer.FromString(dataTypesUpload.TimeProcessingNone)
+	return dataSetCreate
+}
+
+func PayloadSystemTime(datum data.Datum) *time.Time {
+	if payload := datum.GetPayload(); payload == nil {
+		return nil
+	} else if value := payload.Get("systemTime"); value == nil {
+		return nil
+	} else if systemTime, ok := value.(*time.Time); !ok {
+		return nil
+	} else {
+		return systemTime
+	}
+}
+
+type BySystemTime data.Data
+
+func (b BySystemTime) Len() int {
+	return len(b)
+}
+
+func (b BySystemTime) Less(left int, right int) bool {
+	if leftSystemTime := PayloadSystemTime(b[left]); leftSystemTime == nil {
+		return true
+	} else if rightSystemTime := PayloadSystemTime(b[right]); rightSystemTime == nil {
+		return false
+	} else {
+		return leftSystemTime.Before(*rightSystemTime)
+	}
+}
+
+func (b BySystemTime) Swap(left int, right int) {
+	b[left], b[right] = b[right], b[left]
+}
@@ -0,0 +1,112 @@
+package fetch_test
+
+import (
+	"sort"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/data"
+	dataDeduplicatorDeduplicator "github.com/tidepool-org/platform/data/deduplicator/deduplicator"
+	dataTypesBloodGlucoseContinuous "github.com/tidepool-org/platform/data/types/blood/glucose/continuous"
+	"github.com/tidepool-org/platform/metadata"
+	"github.com/tidepool-org/platform/pointer"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
+	"github.com/tidepool-org/platform/test"
+)
+
+func newDatumWithSystemTime(systemTime *time.Time) data.Datum {
+	datum := dataTypesBloodGlucoseContinuous.New()
+	if systemTime != nil {
+		datum.Payload = metadata.NewMetadata()
+		(*datum.Payload)["systemTime"] = systemTime
+	}
+	return datum
+}
+
+var _ = Describe("Fetch", func() {
+	var taskType string
+	var providerSessionID string
+	var dataSourceID string
+
+	BeforeEach(func() {
+		taskType = test.RandomStringFromRange(1, 32)
+		providerSessionID = test.RandomStringFromRange(1, 32)
+		dataSourceID = test.RandomStringFromRange(1, 32)
+	})
+
+	Context("TaskName", func() {
+		It("returns the task type and provider session id", func() {
+			Expect(providerFetch.TaskName(taskType, providerSessionID)).To(Equal(taskType + ":" + providerSessionID))
+		})
+	})
+
+	Context("NewTaskCreate", func() {
+		It("returns an error if the task type is missing", func() {
+			taskCreate, err := providerFetch.NewTaskCreate("", providerSessionID, dataSourceID)
+			Expect(err).To(MatchError("task type is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the provider session id is missing", func() {
+			taskCreate, err := providerFetch.NewTaskCreate(taskType, "", dataSourceID)
+			Expect(err).To(MatchError("provider session id is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns an error if the data source id is missing", func() {
+			taskCreate, err := providerFetch.NewTaskCreate(taskType, providerSessionID, "")
+			Expect(err).To(MatchError("data source id is missing"))
+			Expect(taskCreate).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			taskCreate, err := providerFetch.NewTaskCreate(taskType, providerSessionID, dataSourceID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taskCreate).ToNot(BeNil())
+			Expect(taskCreate.Name).To(Equal(pointer.FromString(providerFetch.TaskName(taskType, providerSessionID))))
+			Expect(taskCreate.Type).To(Equal(taskType))
+			Expect(taskCreate.Data).To(Equal(map[string]interface{}{
+				"providerSessionId": providerSessionID,
+				"dataSourceId":      dataSourceID,
+			}))
+		})
+	})
+
+	Context("NewDataSetCreate", func() {
+		It("returns a continuous data set create", func() {
+			dataSetCreate := providerFetch.NewDataSetCreate("org.tidepool.test", "1.0.0", []string{"Test"}, []string{data.DeviceTagCGM})
+			Expect(dataSetCreate).ToNot(BeNil())
+			Expect(dataSetCreate.Client).To(Equal(&data.DataSetClient{Name: pointer.FromString("org.tidepool.test"), Version: pointer.FromString("1.0.0")}))
+			Expect(dataSetCreate.DataSetType).To(Equal(pointer.FromString(data.DataSetTypeContinuous)))
+			Expect(dataSetCreate.Deduplicator.Name).To(Equal(pointer.FromString(dataDeduplicatorDeduplicator.DataSetDeleteOriginName)))
+			Expect(dataSetCreate.DeviceManufacturers).To(Equal(pointer.FromStringArray([]string{"Test"})))
+			Expect(dataSetCreate.DeviceTags).To(Equal(pointer.FromStringArray([]string{data.DeviceTagCGM})))
+			Expect(dataSetCreate.Time).To(BeNil())
+		})
+	})
+
+	Context("PayloadSystemTime", func() {
+		It("returns nil if the payload is missing", func() {
+			Expect(providerFetch.PayloadSystemTime(newDatumWithSystemTime(nil))).To(BeNil())
+		})
+
+		It("returns the system time", func() {
+			systemTime := pointer.FromTime(test.RandomTime())
+			Expect(providerFetch.PayloadSystemTime(newDatumWithSystemTime(systemTime))).To(Equal(systemTime))
+		})
+	})
+
+	Context("BySystemTime", func() {
+		It("sorts by system time with missing system times first", func() {
+			now := time.Now()
+			first := newDatumWithSystemTime(nil)
+			second := newDatumWithSystemTime(pointer.FromTime(now.Add(-time.Hour)))
+			third := newDatumWithSystemTime(pointer.FromTime(now))
+			datumArray := data.Data{third, second, first}
+			sort.Sort(providerFetch.BySystemTime(datumArray))
+			Expect(datumArray).To(Equal(data.Data{first, second, third}))
+		})
+	})
+})
@@ -0,0 +1,184 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/task"
+)
+
+const maintenanceWindowIterationsMaximum = 10
+
+// RecurringWindow is a daily window in the time zone of the location. The start and end are minutes since midnight.
+type RecurringWindow struct {
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+func ParseRecurringWindow(value string) (*RecurringWindow, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return nil, errors.Newf("recurring window %q is invalid", value)
+	}
+
+	times := strings.Split(fields[0], "-")
+	if len(times) != 2 {
+		return nil, errors.Newf("recurring window %q is invalid", value)
+	}
+
+	start, err := parseMinutes(times[0])
+	if err != nil {
+		return nil, errors.Newf("recurring window %q is invalid", value)
+	}
+	end, err := parseMinutes(times[1])
+	if err != nil || end == start {
+		return nil, errors.Newf("recurring window %q is invalid", value)
+	}
+
+	location, err := time.LoadLocation(fields[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "recurring window %q location is invalid", value)
+	}
+
+	return &RecurringWindow{
+		Start:    start,
+		End:      end,
+		Location: location,
+	}, nil
+}
+
+// EndTime returns the end time of the window that includes the time, or nil if the time is not within the window
+func (r *RecurringWindow) EndTime(tm time.Time) *time.Time {
+	local := tm.In(r.Location)
+	seconds := local.Hour()*60*60 + local.Minute()*60 + local.Second()
+	year, month, day := local.Date()
+
+	if r.Start < r.End {
+		if seconds >= r.Start*60 && seconds < r.End*60 {
+			return pointer.FromTime(time.Date(year, month, day, 0, r.End, 0, 0, r.Location))
+		}
+	} else if seconds >= r.Start*60 {
+		return pointer.FromTime(time.Date(year, month, day+1, 0, r.End, 0, 0, r.Location))
+	} else if seconds < r.End*60 {
+		return pointer.FromTime(time.Date(year, month, day, 0, r.End, 0, 0, r.Location))
+	}
+	return nil
+}
+
+func (r *RecurringWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d %s", r.Start/60, r.Start%60, r.End/60, r.End%60, r.Location)
+}
+
+type RecurringWindows []*RecurringWindow
+
+func ParseRecurringWindows(value string) (RecurringWindows, error) {
+	recurringWindows := RecurringWindows{}
+	for _, recurringWindowString := range strings.Split(value, ",") {
+		if recurringWindowString = strings.TrimSpace(recurringWindowString); recurringWindowString != "" {
+			recurringWindow, err := ParseRecurringWindow(recurringWindowString)
+			if err != nil {
+				return nil, err
+			}
+			recurringWindows = append(recurringWindows, recurringWindow)
+		}
+	}
+	return recurringWindows, nil
+}
+
+// Maintenance determines whether a provider is within a maintenance window, either one of the configured recurring
+// windows or a one-off window set through the task service API
+type Maintenance struct {
+	providerName              string
+	recurringWindows          RecurringWindows
+	maintenanceWindowAccessor task.MaintenanceWindowAccessor
+}
+
+func NewMaintenance(providerName string, cfg *Config, maintenanceWindowAccessor task.MaintenanceWindowAccessor) (*Maintenance, error) {
+	if providerName == "" {
+		return nil, errors.New("provider name is missing")
+	}
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+	if maintenanceWindowAccessor == nil {
+		return nil, errors.New("maintenance window accessor is missing")
+	}
+
+	recurringWindows, err := ParseRecurringWindows(cfg.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Maintenance{
+		providerName:              providerName,
+		recurringWindows:          recurringWindows,
+		maintenanceWindowAccessor: maintenanceWindowAccessor,
+	}, nil
+}
+
+func (m *Maintenance) ProviderName() string {
+	return m.providerName
+}
+
+func (m *Maintenance) RecurringWindows() RecurringWindows {
+	return m.recurringWindows
+}
+
+// EndTime returns the end time of the maintenance window that includes the time, or nil if the time is not within
+// any maintenance window. If the end time of one window falls within another window, then the end time of the later
+// window is returned.
+func (m *Maintenance) EndTime(ctx context.Context, tm time.Time) (*time.Time, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	filter := task.NewMaintenanceWindowFilter()
+	filter.Provider = pointer.FromString(m.providerName)
+	filter.Expired = pointer.FromBool(false)
+	maintenanceWindows, err := m.maintenanceWindowAccessor.ListMaintenanceWindows(ctx, filter, page.NewPagination())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list maintenance windows")
+	}
+
+	var endTime *time.Time
+	for iteration := 0; iteration < maintenanceWindowIterationsMaximum; iteration++ {
+		nextEndTime := m.endTime(tm, maintenanceWindows)
+		if nextEndTime == nil {
+			break
+		}
+		endTime = nextEndTime
+		tm = *nextEndTime
+	}
+	return endTime, nil
+}
+
+func (m *Maintenance) endTime(tm time.Time, maintenanceWindows task.MaintenanceWindows) *time.Time {
+	var endTime *time.Time
+	for _, recurringWindow := range m.recurringWindows {
+		if windowEndTime := recurringWindow.EndTime(tm); windowEndTime != nil && (endTime == nil || windowEndTime.After(*endTime)) {
+			endTime = windowEndTime
+		}
+	}
+	for _, maintenanceWindow := range maintenanceWindows {
+		if maintenanceWindow.Includes(tm) && (endTime == nil || maintenanceWindow.EndTime.After(*endTime)) {
+			endTime = pointer.FromTime(maintenanceWindow.EndTime)
+		}
+	}
+	return endTime
+}
+
+func parseMinutes(value string) (int, error) {
+	tm, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return tm.Hour()*60 + tm.Minute(), nil
+}
@@ -0,0 +1,173 @@
+package fetch_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/pointer"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
+	"github.com/tidepool-org/platform/task"
+	taskTest "github.com/tidepool-org/platform/task/test"
+)
+
+var _ = Describe("Maintenance", func() {
+	var location *time.Location
+
+	BeforeEach(func() {
+		var err error
+		location, err = time.LoadLocation("America/Los_Angeles")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("ParseRecurringWindows", func() {
+		It("returns an empty list if the value is empty", func() {
+			Expect(providerFetch.ParseRecurringWindows("")).To(BeEmpty())
+		})
+
+		It("returns an error if the times are invalid", func() {
+			_, err := providerFetch.ParseRecurringWindows("02:45-25:00 UTC")
+			Expect(err).To(MatchError(`recurring window "02:45-25:00 UTC" is invalid`))
+		})
+
+		It("returns an error if the start and end times are equal", func() {
+			_, err := providerFetch.ParseRecurringWindows("02:45-02:45 UTC")
+			Expect(err).To(MatchError(`recurring window "02:45-02:45 UTC" is invalid`))
+		})
+
+		It("returns an error if the location is invalid", func() {
+			_, err := providerFetch.ParseRecurringWindows("02:45-03:45 Invalid/Location")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns successfully with multiple windows", func() {
+			recurringWindows, err := providerFetch.ParseRecurringWindows("02:45-03:45 America/Los_Angeles, 23:30-00:15 UTC")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recurringWindows).To(HaveLen(2))
+			Expect(recurringWindows[0].String()).To(Equal("02:45-03:45 America/Los_Angeles"))
+			Expect(recurringWindows[1].String()).To(Equal("23:30-00:15 UTC"))
+		})
+	})
+
+	Context("RecurringWindow", func() {
+		Context("EndTime", func() {
+			It("returns the end time if the time is within the window", func() {
+				recurringWindow, err := providerFetch.ParseRecurringWindow("02:45-03:45 America/Los_Angeles")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 1, 2, 45, 0, 0, location))).To(PointTo(BeTemporally("==", time.Date(2020, 6, 1, 3, 45, 0, 0, location))))
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 1, 3, 44, 59, 0, location))).To(PointTo(BeTemporally("==", time.Date(2020, 6, 1, 3, 45, 0, 0, location))))
+			})
+
+			It("returns nil if the time is not within the window", func() {
+				recurringWindow, err := providerFetch.ParseRecurringWindow("02:45-03:45 America/Los_Angeles")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 1, 2, 44, 59, 0, location))).To(BeNil())
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 1, 3, 45, 0, 0, location))).To(BeNil())
+			})
+
+			It("returns the end time on the next day if the window spans midnight", func() {
+				recurringWindow, err := providerFetch.ParseRecurringWindow("23:30-00:15 UTC")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 1, 23, 45, 0, 0, time.UTC))).To(PointTo(BeTemporally("==", time.Date(2020, 6, 2, 0, 15, 0, 0, time.UTC))))
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 2, 0, 10, 0, 0, time.UTC))).To(PointTo(BeTemporally("==", time.Date(2020, 6, 2, 0, 15, 0, 0, time.UTC))))
+				Expect(recurringWindow.EndTime(time.Date(2020, 6, 2, 0, 15, 0, 0, time.UTC))).To(BeNil())
+			})
+		})
+	})
+
+	Context("NewMaintenance", func() {
+		var config *providerFetch.Config
+		var maintenanceWindowAccessor *taskTest.MaintenanceWindowAccessor
+
+		BeforeEach(func() {
+			config = providerFetch.NewConfig()
+			maintenanceWindowAccessor = taskTest.NewMaintenanceWindowAccessor()
+		})
+
+		It("returns an error if the provider name is missing", func() {
+			maintenance, err := providerFetch.NewMaintenance("", config, maintenanceWindowAccessor)
+			Expect(err).To(MatchError("provider name is missing"))
+			Expect(maintenance).To(BeNil())
+		})
+
+		It("returns an error if the config is missing", func() {
+			maintenance, err := providerFetch.NewMaintenance("test", nil, maintenanceWindowAccessor)
+			Expect(err).To(MatchError("config is missing"))
+			Expect(maintenance).To(BeNil())
+		})
+
+		It("returns an error if the maintenance window accessor is missing", func() {
+			maintenance, err := providerFetch.NewMaintenance("test", config, nil)
+			Expect(err).To(MatchError("maintenance window accessor is missing"))
+			Expect(maintenance).To(BeNil())
+		})
+
+		Context("with new maintenance", func() {
+			var ctx context.Context
+			var maintenance *providerFetch.Maintenance
+
+			BeforeEach(func() {
+				config.MaintenanceWindows = "02:45-03:45 America/Los_Angeles"
+				var err error
+				maintenance, err = providerFetch.NewMaintenance("test", config, maintenanceWindowAccessor)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(maintenance).ToNot(BeNil())
+				Expect(maintenance.ProviderName()).To(Equal("test"))
+				Expect(maintenance.RecurringWindows()).To(HaveLen(1))
+				ctx = context.Background()
+			})
+
+			AfterEach(func() {
+				maintenanceWindowAccessor.Expectations()
+			})
+
+			It("returns an error if the context is missing", func() {
+				endTime, err := maintenance.EndTime(nil, time.Now())
+				Expect(err).To(MatchError("context is missing"))
+				Expect(endTime).To(BeNil())
+			})
+
+			It("returns an error if the maintenance windows cannot be listed", func() {
+				responseErr := errorsTest.RandomError()
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{Error: responseErr}}
+				endTime, err := maintenance.EndTime(ctx, time.Now())
+				Expect(err).To(MatchError("unable to list maintenance windows; " + responseErr.Error()))
+				Expect(endTime).To(BeNil())
+			})
+
+			It("lists the unexpired maintenance windows for the provider", func() {
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{MaintenanceWindows: task.MaintenanceWindows{}}}
+				_, err := maintenance.EndTime(ctx, time.Now())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(maintenanceWindowAccessor.ListMaintenanceWindowsInputs).To(HaveLen(1))
+				Expect(maintenanceWindowAccessor.ListMaintenanceWindowsInputs[0].Filter).To(Equal(&task.MaintenanceWindowFilter{Provider: pointer.FromString("test"), Expired: pointer.FromBool(false)}))
+			})
+
+			It("returns nil if the time is not within any window", func() {
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{MaintenanceWindows: task.MaintenanceWindows{}}}
+				Expect(maintenance.EndTime(ctx, time.Date(2020, 6, 1, 12, 0, 0, 0, location))).To(BeNil())
+			})
+
+			It("returns the end of the recurring window", func() {
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{MaintenanceWindows: task.MaintenanceWindows{}}}
+				Expect(maintenance.EndTime(ctx, time.Date(2020, 6, 1, 3, 0, 0, 0, location))).To(PointTo(BeTemporally("==", time.Date(2020, 6, 1, 3, 45, 0, 0, location))))
+			})
+
+			It("returns the end of the one-off window", func() {
+				maintenanceWindow := &task.MaintenanceWindow{StartTime: time.Date(2020, 6, 1, 11, 0, 0, 0, location), EndTime: time.Date(2020, 6, 1, 13, 0, 0, 0, location)}
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{MaintenanceWindows: task.MaintenanceWindows{maintenanceWindow}}}
+				Expect(maintenance.EndTime(ctx, time.Date(2020, 6, 1, 12, 0, 0, 0, location))).To(PointTo(BeTemporally("==", maintenanceWindow.EndTime)))
+			})
+
+			It("returns the end of the last overlapping window", func() {
+				maintenanceWindow := &task.MaintenanceWindow{StartTime: time.Date(2020, 6, 1, 3, 30, 0, 0, location), EndTime: time.Date(2020, 6, 1, 5, 0, 0, 0, location)}
+				maintenanceWindowAccessor.ListMaintenanceWindowsOutputs = []taskTest.ListMaintenanceWindowsOutput{{MaintenanceWindows: task.MaintenanceWindows{maintenanceWindow}}}
+				Expect(maintenance.EndTime(ctx, time.Date(2020, 6, 1, 3, 0, 0, 0, location))).To(PointTo(BeTemporally("==", maintenanceWindow.EndTime)))
+			})
+		})
+	})
+})
@@ -0,0 +1,879 @@
+package fetch
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/data"
+	dataClient "github.com/tidepool-org/platform/data/client"
+	dataDeduplicatorDeduplicator "github.com/tidepool-org/platform/data/deduplicator/deduplicator"
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataTypes "github.com/tidepool-org/platform/data/types"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/oauth"
+	oauthToken "github.com/tidepool-org/platform/oauth/token"
+	"github.com/tidepool-org/platform/pointer"
+	providerClient "github.com/tidepool-org/platform/provider/client"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/task"
+	"github.com/tidepool-org/platform/version"
+)
+
+const (
+	AvailableAfterDurationMaximum = 75 * time.Minute
+	AvailableAfterDurationMinimum = 45 * time.Minute
+	DataSetSize                   = 2000
+	RetryJitterDurationMaximum    = time.Minute
+	TaskDurationMaximum           = 5 * time.Minute
+)
+
+type Runner struct {
+	logger           log.Logger
+	versionReporter  version.Reporter
+	authClient       auth.Client
+	dataClient       dataClient.Client
+	dataSourceClient dataSource.Client
+	partner          Partner
+	maintenance      *Maintenance
+	healthPolicy     *dataSource.HealthPolicy
+}
+
+func NewRunner(logger log.Logger, versionReporter version.Reporter, authClient auth.Client, dataClient dataClient.Client, dataSourceClient dataSource.Client, partner Partner, maintenance *Maintenance, healthPolicy *dataSource.HealthPolicy) (*Runner, error) {
+	if logger == nil {
+		return nil, errors.New("logger is missing")
+	}
+	if versionReporter == nil {
+		return nil, errors.New("version reporter is missing")
+	}
+	if authClient == nil {
+		return nil, errors.New("auth client is missing")
+	}
+	if dataClient == nil {
+		return nil, errors.New("data client is missing")
+	}
+	if dataSourceClient == nil {
+		return nil, errors.New("data source client is missing")
+	}
+	if partner == nil {
+		return nil, errors.New("partner is missing")
+	}
+	if maintenance == nil {
+		return nil, errors.New("maintenance is missing")
+	}
+	if healthPolicy == nil {
+		return nil, errors.New("health policy is missing")
+	}
+
+	return &Runner{
+		logger:           logger,
+		versionReporter:  versionReporter,
+		authClient:       authClient,
+		dataClient:       dataClient,
+		dataSourceClient: dataSourceClient,
+		partner:          partner,
+		maintenance:      maintenance,
+		healthPolicy:     healthPolicy,
+	}, nil
+}
+
+func (r *Runner) Logger() log.Logger {
+	return r.logger
+}
+
+func (r *Runner) VersionReporter() version.Reporter {
+	return r.versionReporter
+}
+
+func (r *Runner) AuthClient() auth.Client {
+	return r.authClient
+}
+
+func (r *Runner) DataClient() dataClient.Client {
+	return r.dataClient
+}
+
+func (r *Runner) DataSourceClient() dataSource.Client {
+	return r.dataSourceClient
+}
+
+func (r *Runner) Partner() Partner {
+	return r.partner
+}
+
+func (r *Runner) Maintenance() *Maintenance {
+	return r.maintenance
+}
+
+func (r *Runner) HealthPolicy() *dataSource.HealthPolicy {
+	return r.healthPolicy
+}
+
+func (r *Runner) CanRunTask(tsk *task.Task) bool {
+	return tsk != nil && tsk.Type == r.Partner().TaskType()
+}
+
+func (r *Runner) Run(ctx context.Context, tsk *task.Task) {
+	now := time.Now()
+
+	ctx = log.NewContextWithLogger(ctx, r.Logger())
+
+	if endTime, err := r.Maintenance().EndTime(ctx, now); err != nil {
+		r.Logger().WithError(err).Warn("Unable to determine maintenance window end time")
+	} else if endTime != nil {
+		r.Logger().WithField("endTime", endTime).Debug("Rescheduling task to end of maintenance window")
+		r.RepeatTaskAt(tsk, *endTime)
+		return
+	}
+
+	tsk.ClearError()
+
+	var retryTime *time.Time
+	if serverSessionToken, sErr := r.AuthClient().ServerSessionToken(); sErr != nil {
+		tsk.AppendError(errors.Wrap(sErr, "unable to get server session token"))
+	} else {
+		ctx = auth.NewContextWithServerSessionToken(ctx, serverSessionToken)
+
+		if taskRunner, tErr := NewTaskRunner(r, tsk); tErr != nil {
+			tsk.AppendError(errors.Wrap(tErr, "unable to create task runner"))
+		} else if tErr = taskRunner.Run(ctx); tErr != nil {
+			retryTime = providerClient.RetryTime(tErr)
+			tsk.AppendError(errors.Wrap(tErr, "unable to run task runner"))
+		}
+	}
+
+	if retryTime != nil {
+		r.RepeatTaskAt(tsk, *retryTime)
+	} else if !IsBackfillTask(tsk) {
+		r.RepeatTask(tsk)
+	}
+
+	if taskDuration := time.Since(now); taskDuration > TaskDurationMaximum {
+		r.Logger().WithField("taskDuration", taskDuration.Truncate(time.Millisecond).Seconds()).Warn("Task duration exceeds maximum")
+	}
+}
+
+func (r *Runner) RepeatTask(tsk *task.Task) {
+	if !tsk.IsFailed() {
+		tsk.RepeatAvailableAfter(AvailableAfterDurationMinimum + time.Duration(rand.Int63n(int64(AvailableAfterDurationMaximum-AvailableAfterDurationMinimum+1))))
+	}
+}
+
+// IsBackfillTask returns true if the task fetches a bounded window of historical data once, rather than repeatedly
+// fetching the latest data
+func IsBackfillTask(tsk *task.Task) bool {
+	_, ok := tsk.Data["backfillStartTime"]
+	return ok
+}
+
+// RepeatTaskAt repeats the task at the specified time, with jitter to spread out retries
+func (r *Runner) RepeatTaskAt(tsk *task.Task, retryTime time.Time) {
+	if !tsk.IsFailed() {
+		tsk.RepeatAvailableAt(retryTime.Add(time.Duration(rand.Int63n(int64(RetryJitterDurationMaximum + 1)))))
+	}
+}
+
+type TaskRunner struct {
+	*Runner
+	task             *task.Task
+	context          context.Context
+	providerSession  *auth.ProviderSession
+	backfill         *Backfill
+	dataSource       *dataSource.Source
+	tokenSource      oauth.TokenSource
+	deviceHashes     map[string]string
+	recordHashes     map[string]string
+	dataSet          *data.DataSet
+	dataSetPreloaded bool
+	syncRecorder     *syncRecorder
+}
+
+func NewTaskRunner(rnnr *Runner, tsk *task.Task) (*TaskRunner, error) {
+	if rnnr == nil {
+		return nil, errors.New("runner is missing")
+	}
+	if tsk == nil {
+		return nil, errors.New("task is missing")
+	}
+
+	return &TaskRunner{
+		Runner: rnnr,
+		task:   tsk,
+	}, nil
+}
+
+func (t *TaskRunner) Run(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+
+	if len(t.task.Data) == 0 {
+		t.task.SetFailed()
+		return errors.New("data is missing")
+	}
+
+	t.syncRecorder = newSyncRecorder(time.Now())
+	t.context = providerClient.NewContextWithResponseRecorder(ctx, t.syncRecorder)
+
+	if err := t.getProviderSession(); err != nil {
+		return err
+	}
+	if err := t.getBackfill(); err != nil {
+		return err
+	}
+	if err := t.getDataSource(); err != nil {
+		return err
+	}
+	if err := t.createTokenSource(); err != nil {
+		return err
+	}
+	if t.backfill != nil {
+		err := t.fetchBackfill()
+		if updateErr := t.updateDataSourceWithSync(err); updateErr != nil {
+			t.Logger().WithError(updateErr).Error("unable to update data source with sync")
+		}
+		return err
+	}
+	if err := t.getDeviceHashes(); err != nil {
+		return err
+	}
+	if err := t.getRecordHashes(); err != nil {
+		return err
+	}
+	err := t.fetchSinceLatestDataTime()
+	if err == nil {
+		err = t.reconcile()
+	}
+	if err != nil {
+		// Throttling and an open circuit breaker affect all data sources of the partner, so are not failures of this data source
+		if providerClient.RetryTime(err) == nil {
+			if updateErr := t.updateDataSourceWithFailure(err); updateErr != nil {
+				t.Logger().WithError(updateErr).Error("unable to update data source with failure")
+			}
+		} else if updateErr := t.updateDataSourceWithSync(err); updateErr != nil {
+			t.Logger().WithError(updateErr).Error("unable to update data source with sync")
+		}
+		return err
+	}
+	return t.updateDataSourceWithSuccess()
+}
+
+func (t *TaskRunner) getProviderSession() error {
+	providerSessionID, ok := t.task.Data["providerSessionId"].(string)
+	if !ok || providerSessionID == "" {
+		t.task.SetFailed()
+		return errors.New("provider session id is missing")
+	}
+
+	providerSession, err := t.AuthClient().GetProviderSession(t.context, providerSessionID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get provider session")
+	} else if providerSession == nil {
+		t.task.SetFailed()
+		return errors.Wrap(err, "provider session is missing")
+	}
+	t.providerSession = providerSession
+
+	return nil
+}
+
+func (t *TaskRunner) updateProviderSession() error {
+	refreshedToken, err := t.tokenSource.RefreshedToken()
+	if err != nil {
+		return errors.Wrap(err, "unable to get refreshed token")
+	} else if refreshedToken == nil {
+		return nil
+	}
+
+	updateProviderSession := auth.NewProviderSessionUpdate()
+	updateProviderSession.OAuthToken = refreshedToken
+	providerSession, err := t.AuthClient().UpdateProviderSession(t.context, t.providerSession.ID, updateProviderSession)
+	if err != nil {
+		return errors.Wrap(err, "unable to update provider session")
+	} else if providerSession == nil {
+		t.task.SetFailed()
+		return errors.Wrap(err, "provider session is missing")
+	}
+	t.providerSession = providerSession
+
+	return nil
+}
+
+func (t *TaskRunner) getBackfill() error {
+	backfill, err := BackfillFromTaskData(t.task.Data)
+	if err != nil {
+		t.task.SetFailed()
+		return err
+	}
+	t.backfill = backfill
+
+	return nil
+}
+
+func (t *TaskRunner) getDataSource() error {
+	dataSourceID, ok := t.task.Data["dataSourceId"].(string)
+	if !ok || dataSourceID == "" {
+		t.task.SetFailed()
+		return errors.New("data source id is missing")
+	}
+
+	source, err := t.DataSourceClient().Get(t.context, dataSourceID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get data source")
+	} else if source == nil {
+		t.task.SetFailed()
+		return errors.Wrap(err, "data source is missing")
+	}
+	t.dataSource = source
+
+	return nil
+}
+
+func (t *TaskRunner) updateDataSourceWithDataSet(dataSet *data.DataSet) error {
+	update := dataSource.NewUpdate()
+	update.DataSetIDs = pointer.FromStringArray(append(pointer.ToStringArray(t.dataSource.DataSetIDs), *dataSet.UploadID))
+	return t.updateDataSource(update)
+}
+
+func (t *TaskRunner) updateDataSourceWithDataTime(earliestDataTime *time.Time, latestDataTime *time.Time) error {
+	update := dataSource.NewUpdate()
+
+	if t.beforeEarliestDataTime(earliestDataTime) {
+		update.EarliestDataTime = earliestDataTime
+	}
+	if t.afterLatestDataTime(latestDataTime) {
+		update.LatestDataTime = latestDataTime
+	}
+
+	if update.EarliestDataTime == nil && update.LatestDataTime == nil {
+		return nil
+	}
+
+	update.LastImportTime = pointer.FromTime(time.Now())
+	return t.updateDataSource(update)
+}
+
+func (t *TaskRunner) updateDataSourceWithSync(err error) error {
+	update := dataSource.NewUpdate()
+	update.SyncHistory = dataSource.NewSyncHistory(t.dataSource, t.syncRecorder.Sync(err))
+	return t.updateDataSource(update)
+}
+
+func (t *TaskRunner) updateDataSourceWithSuccess() error {
+	update := t.HealthPolicy().SuccessUpdate(t.dataSource, time.Now())
+	update.SyncHistory = dataSource.NewSyncHistory(t.dataSource, t.syncRecorder.Sync(nil))
+	return t.updateDataSource(update)
+}
+
+func (t *TaskRunner) updateDataSourceWithFailure(err error) error {
+	update := t.HealthPolicy().FailureUpdate(t.dataSource, err, request.IsErrorUnauthenticated(errors.Cause(err)), time.Now())
+	update.SyncHistory = dataSource.NewSyncHistory(t.dataSource, t.syncRecorder.Sync(err))
+	if update.State == nil {
+		return t.updateDataSource(update)
+	}
+
+	t.task.SetFailed()
+	if updateErr := t.updateDataSource(update); updateErr != nil {
+		return updateErr
+	}
+
+	// A disconnected data source no longer has a provider session, so delete it along with its task
+	if *update.State == dataSource.StateDisconnected {
+		t.Logger().WithFields(log.Fields{"dataSourceId": t.dataSource.ID, "providerSessionId": t.providerSession.ID}).Warn("Disconnecting data source after persistent failures")
+		if deleteErr := t.AuthClient().DeleteProviderSession(t.context, t.providerSession.ID); deleteErr != nil {
+			return errors.Wrap(deleteErr, "unable to delete provider session")
+		}
+	}
+
+	return nil
+}
+
+func (t *TaskRunner) updateDataSource(update *dataSource.Update) error {
+	if update.IsEmpty() {
+		return nil
+	}
+
+	source, err := t.DataSourceClient().Update(t.context, *t.dataSource.ID, nil, update)
+	if err != nil {
+		return errors.Wrap(err, "unable to update data source")
+	} else if source == nil {
+		t.task.SetFailed()
+		return errors.Wrap(err, "data source is missing")
+	}
+
+	t.dataSource = source
+	return nil
+}
+
+func (t *TaskRunner) createTokenSource() error {
+	tokenSource, err := oauthToken.NewSourceWithToken(t.providerSession.OAuthToken)
+	if err != nil {
+		t.task.SetFailed()
+		return errors.Wrap(err, "unable to create token source")
+	}
+
+	t.tokenSource = tokenSource
+	return nil
+}
+
+func (t *TaskRunner) getDeviceHashes() error {
+	raw, rawOK := t.task.Data["deviceHashes"]
+	if !rawOK || raw == nil {
+		return nil
+	}
+	rawMap, rawMapOK := raw.(map[string]interface{})
+	if !rawMapOK || rawMap == nil {
+		t.task.SetFailed()
+		return errors.New("device hashes is invalid")
+	}
+	deviceHashes := map[string]string{}
+	for key, value := range rawMap {
+		if valueString, valueStringOK := value.(string); valueStringOK {
+			deviceHashes[key] = valueString
+		} else {
+			t.task.SetFailed()
+			return errors.New("device hash is invalid")
+		}
+	}
+
+	t.deviceHashes = deviceHashes
+	return nil
+}
+
+func (t *TaskRunner) updateDeviceHash(device *Device) bool {
+	if device.Hash == "" {
+		return false
+	}
+
+	if t.deviceHashes == nil {
+		t.deviceHashes = map[string]string{}
+	}
+
+	if t.deviceHashes[device.ID] != device.Hash {
+		t.deviceHashes[device.ID] = device.Hash
+		return true
+	}
+
+	return false
+}
+
+func (t *TaskRunner) getRecordHashes() error {
+	raw, rawOK := t.task.Data["recordHashes"]
+	if !rawOK || raw == nil {
+		return nil
+	}
+	rawMap, rawMapOK := raw.(map[string]interface{})
+	if !rawMapOK || rawMap == nil {
+		t.task.SetFailed()
+		return errors.New("record hashes is invalid")
+	}
+	recordHashes := map[string]string{}
+	for key, value := range rawMap {
+		if valueString, valueStringOK := value.(string); valueStringOK {
+			recordHashes[key] = valueString
+		} else {
+			t.task.SetFailed()
+			return errors.New("record hash is invalid")
+		}
+	}
+
+	t.recordHashes = recordHashes
+	return nil
+}
+
+func (t *TaskRunner) fetchSinceLatestDataTime() error {
+	startTime := t.Partner().InitialDataTime()
+	if t.dataSource.LatestDataTime != nil && startTime.Before(*t.dataSource.LatestDataTime) {
+		startTime = *t.dataSource.LatestDataTime
+	}
+
+	dataRange, err := t.fetchDataRange()
+	if err != nil {
+		return err
+	}
+
+	var endTime *time.Time
+	if dataRange != nil {
+		if dataRange.StartTime == nil || dataRange.EndTime == nil {
+			return nil
+		}
+		if startTime.Before(*dataRange.StartTime) {
+			startTime = *dataRange.StartTime
+		}
+		endTime = dataRange.EndTime
+	}
+
+	almostNow := time.Now().Add(-time.Minute)
+	for startTime.Before(almostNow) && (endTime == nil || !startTime.After(*endTime)) {
+		endTime := startTime.Add(WindowDuration)
+		if endTime.After(almostNow) {
+			endTime = almostNow
+		}
+
+		if err := t.fetch(startTime, endTime); err != nil {
+			return err
+		}
+
+		startTime = startTime.Add(WindowDuration)
+		almostNow = time.Now().Add(-time.Minute)
+	}
+	return nil
+}
+
+// fetchBackfill fetches all data in the backfill window into the existing data set. The origin deduplicator replaces
+// existing data rather than duplicating it. Any other deduplicator, such as that of data sets created before the origin
+// deduplicator was used, does not, so existing data of each fetched type is deleted from each window before the fetched
+// data is stored. Devices are not fetched again.
+func (t *TaskRunner) fetchBackfill() error {
+	if err := t.preloadDataSet(); err != nil {
+		return err
+	} else if t.dataSet == nil {
+		return nil
+	}
+
+	replaceByOrigin := t.dataSetDeduplicatorName() == dataDeduplicatorDeduplicator.DataSetDeleteOriginName
+	if !replaceByOrigin {
+		t.Logger().WithField("deduplicator", t.dataSetDeduplicatorName()).Info("Backfill replacing existing data by time range")
+	}
+
+	for startTime := *t.backfill.StartTime; startTime.Before(*t.backfill.EndTime); startTime = startTime.Add(WindowDuration) {
+		endTime := startTime.Add(WindowDuration)
+		if endTime.After(*t.backfill.EndTime) {
+			endTime = *t.backfill.EndTime
+		}
+
+		t.syncRecorder.StartWindow(startTime, endTime)
+		datumArray, err := t.fetchData(startTime, endTime)
+		if err != nil {
+			return err
+		}
+		if !replaceByOrigin && len(datumArray) > 0 {
+			if err = t.deleteDataRange(datumArray, startTime, endTime); err != nil {
+				return err
+			}
+		}
+		if err = t.storeDatumArray(datumArray); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDataRange deletes the existing data of the types of the datums within the time range
+func (t *TaskRunner) deleteDataRange(datumArray data.Data, startTime time.Time, endTime time.Time) error {
+	types := []string{}
+	for _, datum := range datumArray {
+		if meta, ok := datum.Meta().(*dataTypes.Meta); ok && meta.Type != "" && !stringArrayContains(types, meta.Type) {
+			types = append(types, meta.Type)
+		}
+	}
+
+	selectorRange := data.NewSelectorRange()
+	selectorRange.Types = pointer.FromStringArray(types)
+	selectorRange.StartTime = pointer.FromTime(startTime)
+	selectorRange.EndTime = pointer.FromTime(endTime)
+	selectors := data.Selectors{&data.Selector{Range: selectorRange}}
+	if err := t.DataClient().DeleteDataSetsData(t.context, *t.dataSet.UploadID, &selectors); err != nil {
+		return errors.Wrap(err, "unable to delete data set data")
+	}
+	return nil
+}
+
+func (t *TaskRunner) dataSetDeduplicatorName() string {
+	if t.dataSet == nil || t.dataSet.Deduplicator == nil {
+		return ""
+	}
+	return pointer.ToString(t.dataSet.Deduplicator.Name)
+}
+
+// reconcile fetches the records in the reconciliation window preceding the latest data time and compares each against
+// the hash of the record from the previous task run. A deleted record has any previously imported datum deleted, by
+// origin id. An edited record has any previously imported datum replaced, by the origin deduplicator or, if the data set
+// does not use the origin deduplicator, by deleting it by origin id before importing it again. A record first seen in
+// the window was already imported from the same fetch, so is not imported again. Only the hashes of the records
+// currently in the window are retained.
+func (t *TaskRunner) reconcile() error {
+	reconciliationPartner, ok := t.Partner().(ReconciliationPartner)
+	if !ok || t.dataSource.LatestDataTime == nil {
+		return nil
+	}
+
+	if err := t.preloadDataSet(); err != nil {
+		return err
+	} else if t.dataSet == nil {
+		t.Logger().WithField("dataSourceId", t.dataSource.ID).Warn("Skipping reconciliation without data set")
+		return nil
+	}
+
+	endTime := *t.dataSource.LatestDataTime
+	startTime := endTime.Add(-reconciliationPartner.ReconciliationDuration())
+
+	t.syncRecorder.StartWindow(startTime, endTime)
+	records, err := reconciliationPartner.FetchRecords(t.context, startTime, endTime, t.tokenSource)
+	if updateErr := t.updateProviderSession(); updateErr != nil {
+		return updateErr
+	}
+	if err != nil {
+		return err
+	}
+
+	replaceByOrigin := t.dataSetDeduplicatorName() == dataDeduplicatorDeduplicator.DataSetDeleteOriginName
+
+	datumArray := data.Data{}
+	selectors := data.Selectors{}
+	recordHashes := map[string]string{}
+	for _, record := range records {
+		if record.Hash == "" {
+			continue
+		}
+		recordHashes[record.OriginID] = record.Hash
+
+		if previousHash, previousHashOK := t.recordHashes[record.OriginID]; previousHashOK && previousHash == record.Hash {
+			continue
+		} else if record.Deleted {
+			selectors = append(selectors, &data.Selector{Origin: &data.SelectorOrigin{ID: pointer.FromString(record.OriginID)}})
+		} else if previousHashOK && record.Datum != nil {
+			if !replaceByOrigin {
+				selectors = append(selectors, &data.Selector{Origin: &data.SelectorOrigin{ID: pointer.FromString(record.OriginID)}})
+			}
+			datumArray = append(datumArray, record.Datum)
+		}
+	}
+
+	sort.Sort(BySystemTime(datumArray))
+
+	t.syncRecorder.RecordDatumCounts(len(datumArray), len(datumArray))
+
+	// Delete before storing, so that an edited datum deleted by origin id is not deleted after it is stored again
+	if len(selectors) > 0 {
+		if err = t.DataClient().DeleteDataSetsData(t.context, *t.dataSet.UploadID, &selectors); err != nil {
+			return errors.Wrap(err, "unable to delete data set data")
+		}
+	}
+
+	if err = t.storeDatumArray(datumArray); err != nil {
+		return err
+	}
+
+	t.recordHashes = recordHashes
+	t.task.Data["recordHashes"] = t.recordHashes
+	return nil
+}
+
+func (t *TaskRunner) fetchDataRange() (*DataRange, error) {
+	dataRangePartner, ok := t.Partner().(DataRangePartner)
+	if !ok {
+		return nil, nil
+	}
+
+	dataRange, err := dataRangePartner.DataRange(t.context, t.tokenSource)
+	if updateErr := t.updateProviderSession(); updateErr != nil {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dataRange, nil
+}
+
+func (t *TaskRunner) fetch(startTime time.Time, endTime time.Time) error {
+	t.syncRecorder.StartWindow(startTime, endTime)
+
+	devices, devicesDatumArray, err := t.fetchDevices(startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	switch t.Partner().FetchRequirement(startTime, endTime) {
+	case FetchRequirementDevices:
+		if len(devices) == 0 {
+			return nil
+		}
+	case FetchRequirementDataSet:
+		if err = t.preloadDataSet(); err != nil {
+			return err
+		} else if t.dataSet == nil {
+			return nil
+		}
+	}
+
+	datumArray, err := t.fetchData(startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	if len(datumArray) == 0 && len(devicesDatumArray) == 0 {
+		return nil
+	}
+
+	if err = t.prepareDataSet(); err != nil {
+		return err
+	}
+
+	if err = t.storeDatumArray(datumArray); err != nil {
+		return err
+	}
+
+	if err = t.storeDevicesDatumArray(devicesDatumArray); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TaskRunner) fetchDevices(startTime time.Time, endTime time.Time) (Devices, data.Data, error) {
+	devices, err := t.Partner().ListDevices(t.context, startTime, endTime, t.tokenSource)
+	if updateErr := t.updateProviderSession(); updateErr != nil {
+		return nil, nil, updateErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var devicesDatumArray data.Data
+	for _, device := range devices {
+		if t.updateDeviceHash(device) {
+			devicesDatumArray = append(devicesDatumArray, device.Datum)
+		}
+	}
+
+	return devices, devicesDatumArray, nil
+}
+
+func (t *TaskRunner) fetchData(startTime time.Time, endTime time.Time) (data.Data, error) {
+	fetchDatumArray, err := t.Partner().FetchData(t.context, startTime, endTime, t.tokenSource)
+	if updateErr := t.updateProviderSession(); updateErr != nil {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	datumArray := data.Data{}
+	for _, datum := range fetchDatumArray {
+		if t.backfill != nil || t.afterLatestDataTime(PayloadSystemTime(datum)) {
+			datumArray = append(datumArray, datum)
+		}
+	}
+
+	sort.Sort(BySystemTime(datumArray))
+
+	t.syncRecorder.RecordDatumCounts(len(fetchDatumArray), len(datumArray))
+
+	return datumArray, nil
+}
+
+func (t *TaskRunner) preloadDataSet() error {
+	if t.dataSet != nil || t.dataSetPreloaded {
+		return nil
+	}
+
+	dataSet, err := t.findDataSet()
+	if err != nil {
+		return err
+	}
+
+	t.dataSet = dataSet
+	t.dataSetPreloaded = true
+	return nil
+}
+
+func (t *TaskRunner) prepareDataSet() error {
+	if err := t.preloadDataSet(); err != nil {
+		return err
+	}
+
+	if t.dataSet != nil {
+		return nil
+	}
+
+	dataSet, err := t.createDataSet()
+	if err != nil {
+		return err
+	}
+	t.dataSet = dataSet
+	return nil
+}
+
+func (t *TaskRunner) findDataSet() (*data.DataSet, error) {
+	if t.dataSource.DataSetIDs != nil {
+		for index := len(*t.dataSource.DataSetIDs) - 1; index >= 0; index-- {
+			if dataSet, err := t.DataClient().GetDataSet(t.context, (*t.dataSource.DataSetIDs)[index]); err != nil {
+				return nil, errors.Wrap(err, "unable to get data set")
+			} else if dataSet != nil {
+				return dataSet, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (t *TaskRunner) createDataSet() (*data.DataSet, error) {
+	dataSetCreate := t.Partner().NewDataSetCreate()
+	dataSetCreate.Time = pointer.FromTime(time.Now())
+
+	dataSet, err := t.DataClient().CreateUserDataSet(t.context, t.providerSession.UserID, dataSetCreate)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create data set")
+	}
+	if err = t.updateDataSourceWithDataSet(dataSet); err != nil {
+		return nil, err
+	}
+
+	return dataSet, nil
+}
+
+func (t *TaskRunner) storeDatumArray(datumArray data.Data) error {
+	length := len(datumArray)
+	for startIndex := 0; startIndex < length; startIndex += DataSetSize {
+		endIndex := startIndex + DataSetSize
+		if endIndex > length {
+			endIndex = length
+		}
+
+		if err := t.DataClient().CreateDataSetsData(t.context, *t.dataSet.UploadID, datumArray[startIndex:endIndex]); err != nil {
+			return errors.Wrap(err, "unable to create data set data")
+		}
+
+		earliestDataTime := PayloadSystemTime(datumArray[0])
+		latestDataTime := PayloadSystemTime(datumArray[endIndex-1])
+		if err := t.updateDataSourceWithDataTime(earliestDataTime, latestDataTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TaskRunner) storeDevicesDatumArray(devicesDatumArray data.Data) error {
+	if len(devicesDatumArray) > 0 {
+		if err := t.DataClient().CreateDataSetsData(t.context, *t.dataSet.UploadID, devicesDatumArray); err != nil {
+			return errors.Wrap(err, "unable to create data set data")
+		}
+
+		t.task.Data["deviceHashes"] = t.deviceHashes
+	}
+
+	return nil
+}
+
+func (t *TaskRunner) beforeEarliestDataTime(earliestDataTime *time.Time) bool {
+	return earliestDataTime != nil && (t.dataSource.EarliestDataTime == nil || earliestDataTime.Before(*t.dataSource.EarliestDataTime))
+}
+
+func (t *TaskRunner) afterLatestDataTime(latestDataTime *time.Time) bool {
+	return latestDataTime != nil && (t.dataSource.LatestDataTime == nil || latestDataTime.After(*t.dataSource.LatestDataTime))
+}
+
+func stringArrayContains(array []string, value string) bool {
+	for _, element := range array {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
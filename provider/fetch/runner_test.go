@@ -0,0 +1,294 @@
+package fetch_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+
+	"github.com/tidepool-org/platform/auth"
+	authTest "github.com/tidepool-org/platform/auth/test"
+	"github.com/tidepool-org/platform/data"
+	dataClient "github.com/tidepool-org/platform/data/client"
+	dataDeduplicatorDeduplicator "github.com/tidepool-org/platform/data/deduplicator/deduplicator"
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
+	dataTest "github.com/tidepool-org/platform/data/test"
+	dataTypesFood "github.com/tidepool-org/platform/data/types/food"
+	logNull "github.com/tidepool-org/platform/log/null"
+	"github.com/tidepool-org/platform/metadata"
+	"github.com/tidepool-org/platform/oauth"
+	oauthTest "github.com/tidepool-org/platform/oauth/test"
+	"github.com/tidepool-org/platform/pointer"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/task"
+	taskTest "github.com/tidepool-org/platform/task/test"
+	"github.com/tidepool-org/platform/test"
+	userTest "github.com/tidepool-org/platform/user/test"
+	versionTest "github.com/tidepool-org/platform/version/test"
+)
+
+type fakePartner struct {
+	tokenSourceSource *oauthTest.TokenSourceSource
+	datumArray        data.Data
+	fetchDataInputs   [][2]time.Time
+}
+
+func (p *fakePartner) TaskType() string {
+	return "org.tidepool.oauth.test.fetch"
+}
+
+func (p *fakePartner) InitialDataTime() time.Time {
+	return time.Now().Add(-365 * 24 * time.Hour)
+}
+
+func (p *fakePartner) NewDataSetCreate() *data.DataSetCreate {
+	return providerFetch.NewDataSetCreate("org.tidepool.oauth.test", "1.0.0", nil, nil)
+}
+
+func (p *fakePartner) FetchRequirement(startTime time.Time, endTime time.Time) string {
+	return providerFetch.FetchRequirementNone
+}
+
+func (p *fakePartner) ListDevices(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (providerFetch.Devices, error) {
+	if _, err := tokenSource.HTTPClient(ctx, p.tokenSourceSource); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (p *fakePartner) FetchData(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (data.Data, error) {
+	if _, err := tokenSource.HTTPClient(ctx, p.tokenSourceSource); err != nil {
+		return nil, err
+	}
+	p.fetchDataInputs = append(p.fetchDataInputs, [2]time.Time{startTime, endTime})
+
+	datumArray := data.Data{}
+	for _, datum := range p.datumArray {
+		if systemTime := providerFetch.PayloadSystemTime(datum); systemTime != nil && !systemTime.Before(startTime) && systemTime.Before(endTime) {
+			datumArray = append(datumArray, datum)
+		}
+	}
+	return datumArray, nil
+}
+
+type fakeReconciliationPartner struct {
+	*fakePartner
+	records providerFetch.Records
+}
+
+func (p *fakeReconciliationPartner) ReconciliationDuration() time.Duration {
+	return 7 * 24 * time.Hour
+}
+
+func (p *fakeReconciliationPartner) FetchRecords(ctx context.Context, startTime time.Time, endTime time.Time, tokenSource oauth.TokenSource) (providerFetch.Records, error) {
+	if _, err := tokenSource.HTTPClient(ctx, p.tokenSourceSource); err != nil {
+		return nil, err
+	}
+	return p.records, nil
+}
+
+// fakeDataClient records the data set data created and deleted; any other method panics
+type fakeDataClient struct {
+	dataClient.Client
+	dataSet                  *data.DataSet
+	createDataSetsDataInputs []data.Data
+	deleteDataSetsDataInputs []data.Selectors
+	createUserDataSetInvoked bool
+}
+
+func (d *fakeDataClient) GetDataSet(ctx context.Context, id string) (*data.DataSet, error) {
+	if d.dataSet != nil && *d.dataSet.UploadID == id {
+		return d.dataSet, nil
+	}
+	return nil, nil
+}
+
+func (d *fakeDataClient) CreateUserDataSet(ctx context.Context, userID string, create *data.DataSetCreate) (*data.DataSet, error) {
+	d.createUserDataSetInvoked = true
+	return nil, request.ErrorUnauthorized()
+}
+
+func (d *fakeDataClient) CreateDataSetsData(ctx context.Context, dataSetID string, datumArray []data.Datum) error {
+	d.createDataSetsDataInputs = append(d.createDataSetsDataInputs, datumArray)
+	return nil
+}
+
+func (d *fakeDataClient) DeleteDataSetsData(ctx context.Context, dataSetID string, selectors *data.Selectors) error {
+	d.deleteDataSetsDataInputs = append(d.deleteDataSetsDataInputs, *selectors)
+	return nil
+}
+
+// newFood returns a datum without an origin id, like those imported before the origin deduplicator was used
+func newFood(systemTime time.Time) data.Datum {
+	datum := dataTypesFood.New()
+	datum.Time = pointer.FromTime(systemTime)
+	datum.Payload = metadata.NewMetadata()
+	datum.Payload.Set("systemTime", pointer.FromTime(systemTime))
+	return datum
+}
+
+var _ = Describe("Runner", func() {
+	var authClient *authTest.Client
+	var dataClnt *fakeDataClient
+	var dataSourceClient *dataSourceTest.Client
+	var prtnr *fakePartner
+	var maintenance *providerFetch.Maintenance
+	var healthPolicy *dataSource.HealthPolicy
+	var rnnr *providerFetch.Runner
+	var providerSession *auth.ProviderSession
+	var source *dataSource.Source
+	var dataSet *data.DataSet
+
+	BeforeEach(func() {
+		token := &oauth.Token{AccessToken: test.RandomString(), TokenType: "Bearer", ExpirationTime: time.Now().Add(time.Hour).Truncate(time.Second)}
+		providerSession = &auth.ProviderSession{ID: authTest.RandomProviderSessionID(), UserID: userTest.RandomID(), OAuthToken: token}
+		authClient = authTest.NewClient()
+		authClient.GetProviderSessionOutputs = []authTest.GetProviderSessionOutput{{ProviderSession: providerSession, Error: nil}}
+
+		dataSet = &data.DataSet{}
+		dataSet.UploadID = pointer.FromString(dataTest.RandomID())
+		dataSet.Deduplicator = data.NewDeduplicatorDescriptor()
+		dataSet.Deduplicator.Name = pointer.FromString(dataDeduplicatorDeduplicator.NoneName)
+		dataClnt = &fakeDataClient{dataSet: dataSet}
+
+		source = &dataSource.Source{ID: pointer.FromString(dataSourceTest.RandomID()), DataSetIDs: pointer.FromStringArray([]string{*dataSet.UploadID})}
+		dataSourceClient = dataSourceTest.NewClient()
+		dataSourceClient.GetOutputs = []dataSourceTest.GetOutput{{Source: source, Error: nil}}
+		dataSourceClient.UpdateStub = func(ctx context.Context, id string, condition *request.Condition, update *dataSource.Update) (*dataSource.Source, error) {
+			return source, nil
+		}
+
+		tokenSourceSource := oauthTest.NewTokenSourceSource()
+		tokenSourceSource.TokenSourceOutput = &oauthTest.TokenSourceOutput{TokenSource: oauth2.StaticTokenSource(token.RawToken()), Error: nil}
+		prtnr = &fakePartner{tokenSourceSource: tokenSourceSource}
+
+		var err error
+		config := providerFetch.NewConfig()
+		maintenance, err = providerFetch.NewMaintenance("test", config, taskTest.NewMaintenanceWindowAccessor())
+		Expect(err).ToNot(HaveOccurred())
+		healthPolicy, err = dataSource.NewHealthPolicy(config.AuthenticationFailureCountMaximum, config.FailureDurationMaximum)
+		Expect(err).ToNot(HaveOccurred())
+		rnnr, err = providerFetch.NewRunner(logNull.NewLogger(), versionTest.NewReporter(), authClient, dataClnt, dataSourceClient, prtnr, maintenance, healthPolicy)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("with backfill task", func() {
+		var backfill *providerFetch.Backfill
+		var tsk *task.Task
+
+		BeforeEach(func() {
+			endTime := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+			backfill = providerFetch.NewBackfill()
+			backfill.StartTime = pointer.FromTime(endTime.Add(-45 * 24 * time.Hour))
+			backfill.EndTime = pointer.FromTime(endTime)
+			taskCreate, err := providerFetch.NewBackfillTaskCreate(prtnr.TaskType(), providerSession.ID, *source.ID, backfill)
+			Expect(err).ToNot(HaveOccurred())
+			tsk, err = task.NewTask(taskCreate)
+			Expect(err).ToNot(HaveOccurred())
+
+			prtnr.datumArray = data.Data{
+				newFood(backfill.StartTime.Add(time.Hour)),
+				newFood(backfill.StartTime.Add(40 * 24 * time.Hour)),
+			}
+		})
+
+		run := func() error {
+			taskRunner, err := providerFetch.NewTaskRunner(rnnr, tsk)
+			Expect(err).ToNot(HaveOccurred())
+			return taskRunner.Run(context.Background())
+		}
+
+		It("replaces the existing data of each window of an existing data set without the origin deduplicator", func() {
+			Expect(run()).To(Succeed())
+			Expect(tsk.IsFailed()).To(BeFalse())
+			Expect(dataClnt.createUserDataSetInvoked).To(BeFalse())
+
+			windowEndTime := backfill.StartTime.Add(providerFetch.WindowDuration)
+			Expect(prtnr.fetchDataInputs).To(Equal([][2]time.Time{{*backfill.StartTime, windowEndTime}, {windowEndTime, *backfill.EndTime}}))
+			Expect(dataClnt.deleteDataSetsDataInputs).To(Equal([]data.Selectors{
+				{{Range: &data.SelectorRange{Types: pointer.FromStringArray([]string{"food"}), StartTime: pointer.FromTime(*backfill.StartTime), EndTime: pointer.FromTime(windowEndTime)}}},
+				{{Range: &data.SelectorRange{Types: pointer.FromStringArray([]string{"food"}), StartTime: pointer.FromTime(windowEndTime), EndTime: pointer.FromTime(*backfill.EndTime)}}},
+			}))
+			Expect(dataClnt.createDataSetsDataInputs).To(Equal([]data.Data{{prtnr.datumArray[0]}, {prtnr.datumArray[1]}}))
+		})
+
+		It("does not delete existing data of a data set with the origin deduplicator", func() {
+			dataSet.Deduplicator.Name = pointer.FromString(dataDeduplicatorDeduplicator.DataSetDeleteOriginName)
+			Expect(run()).To(Succeed())
+			Expect(dataClnt.deleteDataSetsDataInputs).To(BeEmpty())
+			Expect(dataClnt.createDataSetsDataInputs).To(Equal([]data.Data{{prtnr.datumArray[0]}, {prtnr.datumArray[1]}}))
+		})
+
+		It("does not delete existing data of a window without data", func() {
+			prtnr.datumArray = prtnr.datumArray[1:]
+			Expect(run()).To(Succeed())
+			Expect(dataClnt.deleteDataSetsDataInputs).To(HaveLen(1))
+			Expect(dataClnt.createDataSetsDataInputs).To(Equal([]data.Data{{prtnr.datumArray[0]}}))
+		})
+	})
+
+	Context("with reconciliation", func() {
+		var reconciliationPartner *fakeReconciliationPartner
+		var tsk *task.Task
+		var editedDatum data.Datum
+
+		BeforeEach(func() {
+			source.LatestDataTime = pointer.FromTime(time.Now().Add(-time.Hour))
+			editedDatum = newFood(source.LatestDataTime.Add(-time.Hour))
+			reconciliationPartner = &fakeReconciliationPartner{
+				fakePartner: prtnr,
+				records: providerFetch.Records{
+					{OriginID: "unchanged", Hash: "unchanged", Datum: newFood(source.LatestDataTime.Add(-3 * time.Hour))},
+					{OriginID: "deleted", Hash: "deleted-2", Deleted: true},
+					{OriginID: "edited", Hash: "edited-2", Datum: editedDatum},
+				},
+			}
+			var err error
+			rnnr, err = providerFetch.NewRunner(logNull.NewLogger(), versionTest.NewReporter(), authClient, dataClnt, dataSourceClient, reconciliationPartner, maintenance, healthPolicy)
+			Expect(err).ToNot(HaveOccurred())
+
+			taskCreate, err := providerFetch.NewTaskCreate(prtnr.TaskType(), providerSession.ID, *source.ID)
+			Expect(err).ToNot(HaveOccurred())
+			tsk, err = task.NewTask(taskCreate)
+			Expect(err).ToNot(HaveOccurred())
+			tsk.Data["recordHashes"] = map[string]interface{}{"unchanged": "unchanged", "deleted": "deleted-1", "edited": "edited-1"}
+		})
+
+		run := func() error {
+			taskRunner, err := providerFetch.NewTaskRunner(rnnr, tsk)
+			Expect(err).ToNot(HaveOccurred())
+			return taskRunner.Run(context.Background())
+		}
+
+		It("deletes deleted records and replaces edited records of an existing data set without the origin deduplicator", func() {
+			Expect(run()).To(Succeed())
+			Expect(dataClnt.deleteDataSetsDataInputs).To(Equal([]data.Selectors{{
+				{Origin: &data.SelectorOrigin{ID: pointer.FromString("deleted")}},
+				{Origin: &data.SelectorOrigin{ID: pointer.FromString("edited")}},
+			}}))
+			Expect(dataClnt.createDataSetsDataInputs).To(Equal([]data.Data{{editedDatum}}))
+			Expect(tsk.Data["recordHashes"]).To(Equal(map[string]string{"unchanged": "unchanged", "deleted": "deleted-2", "edited": "edited-2"}))
+		})
+
+		It("deletes deleted records and relies upon the deduplicator to replace edited records of a data set with the origin deduplicator", func() {
+			dataSet.Deduplicator.Name = pointer.FromString(dataDeduplicatorDeduplicator.DataSetDeleteOriginName)
+			Expect(run()).To(Succeed())
+			Expect(dataClnt.deleteDataSetsDataInputs).To(Equal([]data.Selectors{{
+				{Origin: &data.SelectorOrigin{ID: pointer.FromString("deleted")}},
+			}}))
+			Expect(dataClnt.createDataSetsDataInputs).To(Equal([]data.Data{{editedDatum}}))
+		})
+
+		It("skips reconciliation without a data set", func() {
+			source.DataSetIDs = nil
+			Expect(run()).To(Succeed())
+			Expect(dataClnt.deleteDataSetsDataInputs).To(BeEmpty())
+			Expect(dataClnt.createDataSetsDataInputs).To(BeEmpty())
+			Expect(tsk.Data["recordHashes"]).To(Equal(map[string]interface{}{"unchanged": "unchanged", "deleted": "deleted-1", "edited": "edited-1"}))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package fetch
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	dataSource "github.com/tidepool-org/platform/data/source"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+	providerClient "github.com/tidepool-org/platform/provider/client"
+)
+
+// RecordRecordCount records the number of records returned by the most recent partner request. A partner should call it
+// after each successful request with the context passed to it by the task runner.
+func RecordRecordCount(ctx context.Context, recordCount int) {
+	if recorder, ok := providerClient.ResponseRecorderFromContext(ctx).(*syncRecorder); ok {
+		recorder.RecordRecordCount(recordCount)
+	}
+}
+
+// syncRecorder records the sync of a single task run, added to the sync history of the data source when the run
+// completes. Partner requests sent outside of a window, such as for the data range, are not recorded.
+type syncRecorder struct {
+	sync   *dataSource.Sync
+	window *dataSource.SyncWindow
+}
+
+func newSyncRecorder(now time.Time) *syncRecorder {
+	sync := dataSource.NewSync()
+	sync.Time = pointer.FromTime(now)
+	return &syncRecorder{
+		sync: sync,
+	}
+}
+
+func (s *syncRecorder) StartWindow(startTime time.Time, endTime time.Time) {
+	s.window = s.sync.AddWindow(startTime, endTime)
+}
+
+func (s *syncRecorder) RecordResponse(method string, urlString string, statusCode int) {
+	if s.window == nil {
+		return
+	}
+
+	endpoint := urlString
+	if parsedURL, err := url.Parse(urlString); err == nil {
+		endpoint = parsedURL.Path
+	}
+	s.window.AddRequest(endpoint, statusCode)
+}
+
+func (s *syncRecorder) RecordRecordCount(recordCount int) {
+	if s.window == nil || s.window.Requests == nil || len(*s.window.Requests) == 0 {
+		return
+	}
+
+	syncRequest := (*s.window.Requests)[len(*s.window.Requests)-1]
+	syncRequest.RecordCount = pointer.FromInt(recordCount)
+}
+
+func (s *syncRecorder) RecordDatumCounts(translatedCount int, deduplicatedCount int) {
+	if s.window == nil {
+		return
+	}
+
+	s.window.TranslatedCount = pointer.FromInt(pointer.ToInt(s.window.TranslatedCount) + translatedCount)
+	s.window.DeduplicatedCount = pointer.FromInt(pointer.ToInt(s.window.DeduplicatedCount) + deduplicatedCount)
+}
+
+func (s *syncRecorder) Sync(err error) *dataSource.Sync {
+	if err != nil {
+		s.sync.Error = errors.NewSerializable(err)
+	}
+	return s.sync
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+)
+
+const MemoryBackendPruneInterval = time.Minute
+
+// MemoryBackend counts requests for a single replica only
+type MemoryBackend struct {
+	mutex     sync.Mutex
+	counters  map[string]*memoryCounter
+	pruneTime time.Time
+}
+
+type memoryCounter struct {
+	count          int
+	expirationTime time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		counters:  map[string]*memoryCounter{},
+		pruneTime: time.Now().Add(MemoryBackendPruneInterval),
+	}
+}
+
+func (m *MemoryBackend) Increment(ctx context.Context, key string, expirationTime time.Time) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+	if key == "" {
+		return 0, errors.New("key is missing")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.After(m.pruneTime) {
+		m.prune(now)
+	}
+
+	counter, ok := m.counters[key]
+	if !ok || !now.Before(counter.expirationTime) {
+		counter = &memoryCounter{expirationTime: expirationTime}
+		m.counters[key] = counter
+	}
+
+	counter.count++
+	return counter.count, nil
+}
+
+func (m *MemoryBackend) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.counters)
+}
+
+func (m *MemoryBackend) prune(now time.Time) {
+	for key, counter := range m.counters {
+		if !now.Before(counter.expirationTime) {
+			delete(m.counters, key)
+		}
+	}
+	m.pruneTime = now.Add(MemoryBackendPruneInterval)
+}
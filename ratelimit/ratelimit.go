@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidepool-org/platform/config"
+	"github.com/tidepool-org/platform/errors"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendMongo  = "mongo"
+
+	MethodAny = "*"
+)
+
+// Backend counts requests per key until the expiration time
+type Backend interface {
+	Increment(ctx context.Context, key string, expirationTime time.Time) (int, error)
+}
+
+// Budget allows the limit of requests within each fixed window
+type Budget struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (b *Budget) Validate() error {
+	if b.Limit < 1 {
+		return errors.New("limit is invalid")
+	}
+	if b.Window < time.Second {
+		return errors.New("window is invalid")
+	}
+	return nil
+}
+
+// Route applies a separate budget to requests with the method and path prefix
+type Route struct {
+	Method     string
+	PathPrefix string
+	Budget     Budget
+}
+
+func (r *Route) Validate() error {
+	if r.Method == "" {
+		return errors.New("method is missing")
+	}
+	if !strings.HasPrefix(r.PathPrefix, "/") {
+		return errors.New("path prefix is invalid")
+	}
+	if err := r.Budget.Validate(); err != nil {
+		return errors.Wrap(err, "budget is invalid")
+	}
+	return nil
+}
+
+func (r *Route) Matches(method string, path string) bool {
+	return (r.Method == MethodAny || r.Method == method) && strings.HasPrefix(path, r.PathPrefix)
+}
+
+func (r *Route) Name() string {
+	return r.Method + " " + r.PathPrefix
+}
+
+type Config struct {
+	Enabled bool
+	Backend string
+	Default Budget
+	Routes  []Route
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Backend: BackendMemory,
+		Default: Budget{
+			Limit:  600,
+			Window: time.Minute,
+		},
+	}
+}
+
+// Load reads the routes as a semicolon separated list of "<method> <path prefix> <limit> <window seconds>", for
+// example "POST /v1/datasets/ 120 60; * /dataservices/ 300 60"
+func (c *Config) Load(configReporter config.Reporter) error {
+	if configReporter == nil {
+		return errors.New("config reporter is missing")
+	}
+
+	if enabledString, err := configReporter.Get("enabled"); err == nil {
+		var enabled bool
+		enabled, err = strconv.ParseBool(enabledString)
+		if err != nil {
+			return errors.New("enabled is invalid")
+		}
+		c.Enabled = enabled
+	}
+	c.Backend = configReporter.GetWithDefault("backend", c.Backend)
+	if limitString, err := configReporter.Get("limit"); err == nil {
+		var limit int64
+		limit, err = strconv.ParseInt(limitString, 10, 0)
+		if err != nil {
+			return errors.New("limit is invalid")
+		}
+		c.Default.Limit = int(limit)
+	}
+	if windowString, err := configReporter.Get("window"); err == nil {
+		var window int64
+		window, err = strconv.ParseInt(windowString, 10, 0)
+		if err != nil {
+			return errors.New("window is invalid")
+		}
+		c.Default.Window = time.Duration(window) * time.Second
+	}
+	if routesString, err := configReporter.Get("routes"); err == nil {
+		routes, err := ParseRoutes(routesString)
+		if err != nil {
+			return err
+		}
+		c.Routes = routes
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case BackendMemory, BackendMongo:
+	default:
+		return errors.New("backend is invalid")
+	}
+	if err := c.Default.Validate(); err != nil {
+		return errors.Wrap(err, "default is invalid")
+	}
+	for index, route := range c.Routes {
+		if err := route.Validate(); err != nil {
+			return errors.Wrapf(err, "route %d is invalid", index)
+		}
+	}
+	return nil
+}
+
+func ParseRoutes(value string) ([]Route, error) {
+	var routes []Route
+	for _, routeString := range strings.Split(value, ";") {
+		fields := strings.Fields(routeString)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) != 4 {
+			return nil, errors.Newf("route %q is invalid", strings.TrimSpace(routeString))
+		}
+
+		limit, err := strconv.ParseInt(fields[2], 10, 0)
+		if err != nil {
+			return nil, errors.Newf("route %q is invalid", strings.TrimSpace(routeString))
+		}
+		window, err := strconv.ParseInt(fields[3], 10, 0)
+		if err != nil {
+			return nil, errors.Newf("route %q is invalid", strings.TrimSpace(routeString))
+		}
+
+		routes = append(routes, Route{
+			Method:     strings.ToUpper(fields[0]),
+			PathPrefix: fields[1],
+			Budget: Budget{
+				Limit:  int(limit),
+				Window: time.Duration(window) * time.Second,
+			},
+		})
+	}
+	return routes, nil
+}
+
+// Limiter counts requests in fixed windows, using the budget of the first matching route, if any, otherwise the
+// default budget. Each route is counted separately.
+type Limiter struct {
+	config  *Config
+	backend Backend
+}
+
+func NewLimiter(cfg *Config, backend Backend) (*Limiter, error) {
+	if cfg == nil {
+		return nil, errors.New("config is missing")
+	} else if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "config is invalid")
+	}
+	if backend == nil {
+		return nil, errors.New("backend is missing")
+	}
+
+	return &Limiter{
+		config:  cfg,
+		backend: backend,
+	}, nil
+}
+
+// Allow returns zero if the request is allowed, otherwise the duration after which requests will be allowed
+func (l *Limiter) Allow(ctx context.Context, key string, method string, path string) (time.Duration, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+	if key == "" {
+		return 0, errors.New("key is missing")
+	}
+
+	name := "default"
+	budget := l.config.Default
+	for _, route := range l.config.Routes {
+		if route.Matches(method, path) {
+			name = route.Name()
+			budget = route.Budget
+			break
+		}
+	}
+
+	now := time.Now()
+	windowTime := now.Truncate(budget.Window)
+	expirationTime := windowTime.Add(budget.Window)
+
+	count, err := l.backend.Increment(ctx, strings.Join([]string{key, name, strconv.FormatInt(windowTime.Unix(), 10)}, "|"), expirationTime)
+	if err != nil {
+		return 0, err
+	} else if count <= budget.Limit {
+		return 0, nil
+	}
+
+	return expirationTime.Sub(now), nil
+}
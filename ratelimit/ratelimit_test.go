@@ -0,0 +1,250 @@
+package ratelimit_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/ratelimit"
+)
+
+type backend struct {
+	keys            []string
+	expirationTimes []time.Time
+	count           int
+	err             error
+}
+
+func (b *backend) Increment(ctx context.Context, key string, expirationTime time.Time) (int, error) {
+	b.keys = append(b.keys, key)
+	b.expirationTimes = append(b.expirationTimes, expirationTime)
+	return b.count, b.err
+}
+
+var _ = Describe("Ratelimit", func() {
+	Context("Config", func() {
+		var cfg *ratelimit.Config
+
+		BeforeEach(func() {
+			cfg = ratelimit.NewConfig()
+		})
+
+		It("returns default values", func() {
+			Expect(cfg.Enabled).To(BeFalse())
+			Expect(cfg.Backend).To(Equal("memory"))
+			Expect(cfg.Default).To(Equal(ratelimit.Budget{Limit: 600, Window: time.Minute}))
+			Expect(cfg.Routes).To(BeEmpty())
+		})
+
+		Context("Load", func() {
+			var configReporter *configTest.Reporter
+
+			BeforeEach(func() {
+				configReporter = configTest.NewReporter()
+			})
+
+			It("returns an error if the config reporter is missing", func() {
+				errorsTest.ExpectEqual(cfg.Load(nil), errors.New("config reporter is missing"))
+			})
+
+			It("returns an error if enabled is invalid", func() {
+				configReporter.Config["enabled"] = "invalid"
+				errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("enabled is invalid"))
+			})
+
+			It("returns an error if the limit is invalid", func() {
+				configReporter.Config["limit"] = "invalid"
+				errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("limit is invalid"))
+			})
+
+			It("returns an error if the window is invalid", func() {
+				configReporter.Config["window"] = "invalid"
+				errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New("window is invalid"))
+			})
+
+			It("returns an error if the routes are invalid", func() {
+				configReporter.Config["routes"] = "POST /v1/datasets/ 120"
+				errorsTest.ExpectEqual(cfg.Load(configReporter), errors.New(`route "POST /v1/datasets/ 120" is invalid`))
+			})
+
+			It("loads the values", func() {
+				configReporter.Config["enabled"] = "true"
+				configReporter.Config["backend"] = "mongo"
+				configReporter.Config["limit"] = "100"
+				configReporter.Config["window"] = "10"
+				configReporter.Config["routes"] = "post /v1/datasets/ 120 60; * /dataservices/ 300 30;"
+				Expect(cfg.Load(configReporter)).To(Succeed())
+				Expect(cfg.Enabled).To(BeTrue())
+				Expect(cfg.Backend).To(Equal("mongo"))
+				Expect(cfg.Default).To(Equal(ratelimit.Budget{Limit: 100, Window: 10 * time.Second}))
+				Expect(cfg.Routes).To(Equal([]ratelimit.Route{
+					{Method: "POST", PathPrefix: "/v1/datasets/", Budget: ratelimit.Budget{Limit: 120, Window: time.Minute}},
+					{Method: "*", PathPrefix: "/dataservices/", Budget: ratelimit.Budget{Limit: 300, Window: 30 * time.Second}},
+				}))
+				Expect(cfg.Validate()).To(Succeed())
+			})
+		})
+
+		Context("Validate", func() {
+			It("returns an error if the backend is invalid", func() {
+				cfg.Backend = "invalid"
+				errorsTest.ExpectEqual(cfg.Validate(), errors.New("backend is invalid"))
+			})
+
+			It("returns an error if the default limit is invalid", func() {
+				cfg.Default.Limit = 0
+				Expect(cfg.Validate()).To(MatchError("default is invalid; limit is invalid"))
+			})
+
+			It("returns an error if the default window is invalid", func() {
+				cfg.Default.Window = time.Millisecond
+				Expect(cfg.Validate()).To(MatchError("default is invalid; window is invalid"))
+			})
+
+			It("returns an error if a route method is missing", func() {
+				cfg.Routes = []ratelimit.Route{{PathPrefix: "/v1/", Budget: cfg.Default}}
+				Expect(cfg.Validate()).To(MatchError("route 0 is invalid; method is missing"))
+			})
+
+			It("returns an error if a route path prefix is invalid", func() {
+				cfg.Routes = []ratelimit.Route{{Method: "GET", PathPrefix: "v1/", Budget: cfg.Default}}
+				Expect(cfg.Validate()).To(MatchError("route 0 is invalid; path prefix is invalid"))
+			})
+
+			It("returns an error if a route budget is invalid", func() {
+				cfg.Routes = []ratelimit.Route{{Method: "GET", PathPrefix: "/v1/"}}
+				Expect(cfg.Validate()).To(MatchError("route 0 is invalid; budget is invalid; limit is invalid"))
+			})
+		})
+	})
+
+	Context("Limiter", func() {
+		var cfg *ratelimit.Config
+		var bcknd *backend
+		var ctx context.Context
+
+		BeforeEach(func() {
+			cfg = ratelimit.NewConfig()
+			cfg.Default = ratelimit.Budget{Limit: 2, Window: time.Minute}
+			cfg.Routes = []ratelimit.Route{{Method: "POST", PathPrefix: "/v1/datasets/", Budget: ratelimit.Budget{Limit: 1, Window: time.Hour}}}
+			bcknd = &backend{count: 1}
+			ctx = context.Background()
+		})
+
+		Context("NewLimiter", func() {
+			It("returns an error if the config is missing", func() {
+				limiter, err := ratelimit.NewLimiter(nil, bcknd)
+				errorsTest.ExpectEqual(err, errors.New("config is missing"))
+				Expect(limiter).To(BeNil())
+			})
+
+			It("returns an error if the config is invalid", func() {
+				cfg.Backend = "invalid"
+				limiter, err := ratelimit.NewLimiter(cfg, bcknd)
+				Expect(err).To(MatchError("config is invalid; backend is invalid"))
+				Expect(limiter).To(BeNil())
+			})
+
+			It("returns an error if the backend is missing", func() {
+				limiter, err := ratelimit.NewLimiter(cfg, nil)
+				errorsTest.ExpectEqual(err, errors.New("backend is missing"))
+				Expect(limiter).To(BeNil())
+			})
+		})
+
+		Context("Allow", func() {
+			var limiter *ratelimit.Limiter
+
+			BeforeEach(func() {
+				var err error
+				limiter, err = ratelimit.NewLimiter(cfg, bcknd)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(limiter).ToNot(BeNil())
+			})
+
+			It("returns an error if the context is missing", func() {
+				_, err := limiter.Allow(nil, "user:1", "GET", "/v1/users")
+				errorsTest.ExpectEqual(err, errors.New("context is missing"))
+			})
+
+			It("returns an error if the key is missing", func() {
+				_, err := limiter.Allow(ctx, "", "GET", "/v1/users")
+				errorsTest.ExpectEqual(err, errors.New("key is missing"))
+			})
+
+			It("returns an error if the backend returns an error", func() {
+				bcknd.err = errors.New("test error")
+				_, err := limiter.Allow(ctx, "user:1", "GET", "/v1/users")
+				errorsTest.ExpectEqual(err, errors.New("test error"))
+			})
+
+			It("allows requests within the default budget", func() {
+				bcknd.count = 2
+				Expect(limiter.Allow(ctx, "user:1", "GET", "/v1/users")).To(BeZero())
+				Expect(bcknd.keys).To(ConsistOf(HavePrefix("user:1|default|")))
+				Expect(bcknd.expirationTimes[0]).To(BeTemporally("~", time.Now().Truncate(time.Minute).Add(time.Minute)))
+			})
+
+			It("rejects requests exceeding the default budget until the end of the window", func() {
+				bcknd.count = 3
+				retryAfter, err := limiter.Allow(ctx, "user:1", "GET", "/v1/users")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(retryAfter).To(BeNumerically(">", 0))
+				Expect(retryAfter).To(BeNumerically("<=", time.Minute))
+			})
+
+			It("uses the budget of a matching route", func() {
+				bcknd.count = 2
+				retryAfter, err := limiter.Allow(ctx, "user:1", "POST", "/v1/datasets/1234/data")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(retryAfter).To(BeNumerically(">", time.Minute))
+				Expect(bcknd.keys).To(ConsistOf(HavePrefix("user:1|POST /v1/datasets/|")))
+			})
+
+			It("uses the default budget if the route method does not match", func() {
+				Expect(limiter.Allow(ctx, "user:1", "GET", "/v1/datasets/1234")).To(BeZero())
+				Expect(bcknd.keys).To(ConsistOf(HavePrefix("user:1|default|")))
+			})
+		})
+	})
+
+	Context("MemoryBackend", func() {
+		var bcknd *ratelimit.MemoryBackend
+		var ctx context.Context
+		var expirationTime time.Time
+
+		BeforeEach(func() {
+			bcknd = ratelimit.NewMemoryBackend()
+			ctx = context.Background()
+			expirationTime = time.Now().Add(time.Minute)
+		})
+
+		It("returns an error if the context is missing", func() {
+			_, err := bcknd.Increment(nil, "key", expirationTime)
+			errorsTest.ExpectEqual(err, errors.New("context is missing"))
+		})
+
+		It("returns an error if the key is missing", func() {
+			_, err := bcknd.Increment(ctx, "", expirationTime)
+			errorsTest.ExpectEqual(err, errors.New("key is missing"))
+		})
+
+		It("counts each key separately", func() {
+			Expect(bcknd.Increment(ctx, "one", expirationTime)).To(Equal(1))
+			Expect(bcknd.Increment(ctx, "one", expirationTime)).To(Equal(2))
+			Expect(bcknd.Increment(ctx, "two", expirationTime)).To(Equal(1))
+			Expect(bcknd.Len()).To(Equal(2))
+		})
+
+		It("restarts the count after the expiration time", func() {
+			Expect(bcknd.Increment(ctx, "one", time.Now().Add(-time.Second))).To(Equal(1))
+			Expect(bcknd.Increment(ctx, "one", expirationTime)).To(Equal(1))
+			Expect(bcknd.Increment(ctx, "one", expirationTime)).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/ratelimit"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+)
+
+type Store struct {
+	*storeStructuredMongo.Store
+}
+
+func NewStore(config *storeStructuredMongo.Config) (*Store, error) {
+	store, err := storeStructuredMongo.NewStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		Store: store,
+	}, nil
+}
+
+func (s *Store) EnsureIndexes() error {
+	repository := s.newRepository()
+	return repository.EnsureIndexes()
+}
+
+func (s *Store) NewBackend() ratelimit.Backend {
+	return s.newRepository()
+}
+
+func (s *Store) newRepository() *CounterRepository {
+	return &CounterRepository{
+		s.Store.GetRepository("rate_limit_counters"),
+	}
+}
+
+// CounterRepository counts requests shared by all replicas. Expired counters are removed by the TTL index.
+type CounterRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (c *CounterRepository) EnsureIndexes() error {
+	return c.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "expirationTime", Value: 1}},
+			Options: options.Index().
+				SetExpireAfterSeconds(0).
+				SetBackground(true),
+		},
+	})
+}
+
+func (c *CounterRepository) Increment(ctx context.Context, key string, expirationTime time.Time) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+	if key == "" {
+		return 0, errors.New("key is missing")
+	}
+
+	count, err := c.increment(ctx, key, expirationTime)
+	if mongo.IsDuplicateKeyError(err) { // Concurrent upsert of the same key, so the counter now exists
+		count, err = c.increment(ctx, key, expirationTime)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to increment rate limit counter")
+	}
+
+	return count, nil
+}
+
+func (c *CounterRepository) increment(ctx context.Context, key string, expirationTime time.Time) (int, error) {
+	var result struct {
+		Count int `bson:"count"`
+	}
+
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"expirationTime": expirationTime},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := c.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
@@ -0,0 +1,131 @@
+package mongo_test
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/tidepool-org/platform/errors"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	"github.com/tidepool-org/platform/ratelimit"
+	rateLimitStoreMongo "github.com/tidepool-org/platform/ratelimit/store/mongo"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	storeStructuredMongoTest "github.com/tidepool-org/platform/store/structured/mongo/test"
+	"github.com/tidepool-org/platform/test"
+)
+
+var _ = Describe("Mongo", func() {
+	var config *storeStructuredMongo.Config
+	var store *rateLimitStoreMongo.Store
+
+	BeforeEach(func() {
+		config = storeStructuredMongoTest.NewConfig()
+	})
+
+	AfterEach(func() {
+		if store != nil {
+			store.Terminate(context.Background())
+		}
+	})
+
+	Context("NewStore", func() {
+		It("returns an error when unsuccessful", func() {
+			var err error
+			store, err = rateLimitStoreMongo.NewStore(nil)
+			errorsTest.ExpectEqual(err, errors.New("database config is empty"))
+			Expect(store).To(BeNil())
+		})
+
+		It("returns a new store and no error when successful", func() {
+			var err error
+			store, err = rateLimitStoreMongo.NewStore(config)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(store).ToNot(BeNil())
+		})
+	})
+
+	Context("with a new store", func() {
+		var collection *mongo.Collection
+
+		BeforeEach(func() {
+			var err error
+			store, err = rateLimitStoreMongo.NewStore(config)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(store).ToNot(BeNil())
+			collection = store.GetCollection("rate_limit_counters")
+		})
+
+		Context("EnsureIndexes", func() {
+			It("returns successfully", func() {
+				Expect(store.EnsureIndexes()).To(Succeed())
+				cursor, err := collection.Indexes().List(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cursor).ToNot(BeNil())
+				var indexes []storeStructuredMongoTest.MongoIndex
+				err = cursor.All(context.Background(), &indexes)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(indexes).To(ConsistOf(
+					MatchFields(IgnoreExtras, Fields{
+						"Key": Equal(storeStructuredMongoTest.MakeKeySlice("_id")),
+					}),
+					MatchFields(IgnoreExtras, Fields{
+						"Key":        Equal(storeStructuredMongoTest.MakeKeySlice("key")),
+						"Background": Equal(true),
+						"Unique":     Equal(true),
+					}),
+					MatchFields(IgnoreExtras, Fields{
+						"Key":        Equal(storeStructuredMongoTest.MakeKeySlice("expirationTime")),
+						"Background": Equal(true),
+					}),
+				))
+			})
+		})
+
+		Context("NewBackend", func() {
+			var backend ratelimit.Backend
+			var ctx context.Context
+			var expirationTime time.Time
+
+			BeforeEach(func() {
+				Expect(store.EnsureIndexes()).To(Succeed())
+				backend = store.NewBackend()
+				Expect(backend).ToNot(BeNil())
+				ctx = context.Background()
+				expirationTime = time.Now().Add(time.Minute).Truncate(time.Millisecond)
+			})
+
+			It("returns an error if the context is missing", func() {
+				_, err := backend.Increment(nil, test.RandomString(), expirationTime)
+				errorsTest.ExpectEqual(err, errors.New("context is missing"))
+			})
+
+			It("returns an error if the key is missing", func() {
+				_, err := backend.Increment(ctx, "", expirationTime)
+				errorsTest.ExpectEqual(err, errors.New("key is missing"))
+			})
+
+			It("counts each key separately and stores the expiration time", func() {
+				key := test.RandomString()
+				otherKey := test.RandomString()
+				Expect(backend.Increment(ctx, key, expirationTime)).To(Equal(1))
+				Expect(backend.Increment(ctx, key, expirationTime.Add(time.Hour))).To(Equal(2))
+				Expect(backend.Increment(ctx, otherKey, expirationTime)).To(Equal(1))
+
+				var result struct {
+					Count          int       `bson:"count"`
+					ExpirationTime time.Time `bson:"expirationTime"`
+				}
+				Expect(collection.FindOne(ctx, bson.M{"key": key}).Decode(&result)).To(Succeed())
+				Expect(result.Count).To(Equal(2))
+				Expect(result.ExpirationTime).To(BeTemporally("==", expirationTime))
+			})
+		})
+	})
+})
@@ -47,10 +47,13 @@ func IsStatusCodeClientError(statusCode int) bool {
 type Method string
 
 const (
-	MethodServiceSecret   Method = "service secret"
-	MethodAccessToken     Method = "access token"
-	MethodSessionToken    Method = "session token"
-	MethodRestrictedToken Method = "restricted token"
+	MethodServiceSecret       Method = "service secret"
+	MethodAccessToken         Method = "access token"
+	MethodSessionToken        Method = "session token"
+	MethodRestrictedToken     Method = "restricted token"
+	MethodPersonalAccessToken Method = "personal access token"
+	MethodOAuthAccessToken    Method = "oauth access token"
+	MethodClientCertificate   Method = "client certificate"
 )
 
 type Details interface {
@@ -59,6 +62,7 @@ type Details interface {
 	IsService() bool
 	IsUser() bool
 	UserID() string
+	ServiceName() string
 
 	HasToken() bool
 	Token() string
@@ -72,10 +76,19 @@ func NewDetails(method Method, userID string, token string) Details {
 	}
 }
 
+// NewServiceDetails returns the details of a service identified by name, such as by its client certificate
+func NewServiceDetails(method Method, serviceName string) Details {
+	return &details{
+		method:      method,
+		serviceName: serviceName,
+	}
+}
+
 type details struct {
-	method Method
-	userID string
-	token  string
+	method      Method
+	userID      string
+	serviceName string
+	token       string
 }
 
 func (d *details) Method() Method {
@@ -83,7 +96,7 @@ func (d *details) Method() Method {
 }
 
 func (d *details) IsService() bool {
-	return d.method == MethodServiceSecret || (d.method == MethodSessionToken && d.userID == "")
+	return d.method == MethodServiceSecret || d.method == MethodClientCertificate || (d.method == MethodSessionToken && d.userID == "")
 }
 
 func (d *details) IsUser() bool {
@@ -94,8 +107,12 @@ func (d *details) UserID() string {
 	return d.userID
 }
 
+func (d *details) ServiceName() string {
+	return d.serviceName
+}
+
 func (d *details) HasToken() bool {
-	return d.method != MethodServiceSecret
+	return d.method != MethodServiceSecret && d.method != MethodClientCertificate
 }
 
 func (d *details) Token() string {
@@ -177,6 +194,22 @@ func TraceSessionFromContext(ctx context.Context) string {
 	return ""
 }
 
+const routeContextKey contextKey = "route"
+
+// NewContextWithRoute returns a context with the request method and path, as recorded in audit events
+func NewContextWithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeContextKey, route)
+}
+
+func RouteFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if route, ok := ctx.Value(routeContextKey).(string); ok {
+			return route
+		}
+	}
+	return ""
+}
+
 const contextErrorContextKey contextKey = "context-error"
 
 type ContextError struct {
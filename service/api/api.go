@@ -6,8 +6,11 @@ import (
 	"github.com/ant0ine/go-json-rest/rest"
 
 	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/ratelimit"
+	rateLimitStoreMongo "github.com/tidepool-org/platform/ratelimit/store/mongo"
 	"github.com/tidepool-org/platform/service"
 	"github.com/tidepool-org/platform/service/middleware"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
 )
 
 type API struct {
@@ -67,6 +70,10 @@ func (a *API) InitializeMiddleware() error {
 	if err != nil {
 		return err
 	}
+	rateLimitMiddleware, err := a.newRateLimitMiddleware()
+	if err != nil {
+		return err
+	}
 
 	statusMiddleware := &rest.StatusMiddleware{}
 	timerMiddleware := &rest.TimerMiddleware{}
@@ -83,8 +90,11 @@ func (a *API) InitializeMiddleware() error {
 		recorderMiddleware,
 		recoverMiddleware,
 		authMiddleware,
-		gzipMiddleware,
 	}
+	if rateLimitMiddleware != nil {
+		middlewareStack = append(middlewareStack, rateLimitMiddleware)
+	}
+	middlewareStack = append(middlewareStack, gzipMiddleware)
 
 	a.api.Use(middlewareStack...)
 
@@ -109,3 +119,38 @@ func (a *API) InitializeRouters(routers ...service.Router) error {
 
 	return nil
 }
+
+func (a *API) newRateLimitMiddleware() (*middleware.RateLimit, error) {
+	cfg := ratelimit.NewConfig()
+	if err := cfg.Load(a.ConfigReporter().WithScopes("rate_limit")); err != nil {
+		return nil, errors.Wrap(err, "unable to load rate limit config")
+	} else if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var backend ratelimit.Backend
+	switch cfg.Backend {
+	case ratelimit.BackendMongo:
+		storeConfig := storeStructuredMongo.NewConfig()
+		if err := storeConfig.Load(); err != nil {
+			return nil, errors.Wrap(err, "unable to load rate limit store config")
+		}
+		store, err := rateLimitStoreMongo.NewStore(storeConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create rate limit store")
+		}
+		if err = store.EnsureIndexes(); err != nil {
+			return nil, errors.Wrap(err, "unable to ensure rate limit store indexes")
+		}
+		backend = store.NewBackend()
+	default:
+		backend = ratelimit.NewMemoryBackend()
+	}
+
+	limiter, err := ratelimit.NewLimiter(cfg, backend)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create rate limiter")
+	}
+
+	return middleware.NewRateLimit(limiter)
+}
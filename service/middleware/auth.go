@@ -91,7 +91,40 @@ func (a *Auth) authenticate(req *rest.Request) (request.Details, error) {
 		return details, err
 	}
 
-	return a.authenticateRestrictedToken(req)
+	details, err = a.authenticatePersonalAccessToken(req)
+	if err != nil || details != nil {
+		return details, err
+	}
+
+	details, err = a.authenticateRestrictedToken(req)
+	if err != nil || details != nil {
+		return details, err
+	}
+
+	return a.authenticateClientCertificate(req)
+}
+
+// authenticateClientCertificate returns the service details added by the server for a verified client certificate.
+// Any other credentials take precedence so that a service may act on behalf of a user. Since it is only reached if
+// those credentials did not authenticate, any credential present is invalid and must not fall back to the service.
+func (a *Auth) authenticateClientCertificate(req *rest.Request) (request.Details, error) {
+	if details := request.DetailsFromContext(req.Context()); details != nil && details.Method() == request.MethodClientCertificate {
+		if hasCredential(req) {
+			return nil, request.ErrorUnauthenticated()
+		}
+		return details, nil
+	}
+	return nil, nil
+}
+
+func hasCredential(req *rest.Request) bool {
+	for _, key := range []string{auth.TidepoolServiceSecretHeaderKey, auth.TidepoolAuthorizationHeaderKey, auth.TidepoolSessionTokenHeaderKey, auth.TidepoolPersonalAccessTokenHeaderKey} {
+		if _, found := req.Header[key]; found {
+			return true
+		}
+	}
+	_, found := req.URL.Query()[auth.TidepoolRestrictedTokenParameterKey]
+	return found
 }
 
 func (a *Auth) authenticateServiceSecret(req *rest.Request) (request.Details, error) {
@@ -122,6 +155,10 @@ func (a *Auth) authenticateAccessToken(req *rest.Request) (request.Details, erro
 		return nil, request.ErrorUnauthorized()
 	}
 
+	if auth.IsOAuthAccessToken(parts[1]) {
+		return a.authenticateOAuthAccessToken(req, parts[1])
+	}
+
 	details, err := a.authClient.ValidateSessionToken(req.Context(), parts[1])
 	if err != nil {
 		return nil, nil
@@ -130,6 +167,15 @@ func (a *Auth) authenticateAccessToken(req *rest.Request) (request.Details, erro
 	return request.NewDetails(request.MethodAccessToken, details.UserID(), details.Token()), nil
 }
 
+func (a *Auth) authenticateOAuthAccessToken(req *rest.Request, token string) (request.Details, error) {
+	introspection, err := a.authClient.IntrospectOAuthToken(req.Context(), token)
+	if err != nil || introspection == nil || !introspection.Authenticates(req.Request) {
+		return nil, nil
+	}
+
+	return request.NewDetails(request.MethodOAuthAccessToken, *introspection.UserID, token), nil
+}
+
 func (a *Auth) authenticateSessionToken(req *rest.Request) (request.Details, error) {
 	values, found := req.Header[auth.TidepoolSessionTokenHeaderKey]
 	if !found {
@@ -146,6 +192,24 @@ func (a *Auth) authenticateSessionToken(req *rest.Request) (request.Details, err
 	return details, nil
 }
 
+func (a *Auth) authenticatePersonalAccessToken(req *rest.Request) (request.Details, error) {
+	values, found := req.Header[auth.TidepoolPersonalAccessTokenHeaderKey]
+	if !found {
+		return nil, nil
+	} else if len(values) != 1 || !auth.IsPersonalAccessTokenSecret(values[0]) {
+		return nil, request.ErrorUnauthorized()
+	}
+
+	personalAccessToken, err := a.authClient.AuthenticatePersonalAccessToken(req.Context(), values[0])
+	if err != nil {
+		return nil, err
+	} else if personalAccessToken == nil || !personalAccessToken.Authenticates(req.Request) {
+		return nil, nil
+	}
+
+	return request.NewDetails(request.MethodPersonalAccessToken, personalAccessToken.UserID, values[0]), nil
+}
+
 func (a *Auth) authenticateRestrictedToken(req *rest.Request) (request.Details, error) {
 	values, found := req.URL.Query()[auth.TidepoolRestrictedTokenParameterKey]
 	if !found {
@@ -159,5 +223,13 @@ func (a *Auth) authenticateRestrictedToken(req *rest.Request) (request.Details,
 		return nil, nil
 	}
 
+	// A token with a maximum use count is only authenticated if a use remains when it is counted
+	if restrictedToken.MaximumUseCount != nil {
+		restrictedToken, err = a.authClient.UseRestrictedToken(req.Context(), restrictedToken.ID)
+		if err != nil || restrictedToken == nil {
+			return nil, nil
+		}
+	}
+
 	return request.NewDetails(request.MethodRestrictedToken, restrictedToken.UserID, restrictedToken.ID), nil
 }
@@ -14,6 +14,7 @@ import (
 	errorsTest "github.com/tidepool-org/platform/errors/test"
 	"github.com/tidepool-org/platform/log"
 	logNull "github.com/tidepool-org/platform/log/null"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/service"
 	"github.com/tidepool-org/platform/service/middleware"
@@ -322,6 +323,148 @@ var _ = Describe("Auth", func() {
 					})
 				})
 
+				Context("with personal access token", func() {
+					var personalAccessToken string
+
+					BeforeEach(func() {
+						personalAccessToken = auth.NewPersonalAccessTokenSecret()
+						req.Header.Add("X-Tidepool-Personal-Access-Token", personalAccessToken)
+					})
+
+					It("returns unauthorized if multiple values", func() {
+						res.HeaderOutput = &http.Header{}
+						res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+						req.Header.Add("X-Tidepool-Personal-Access-Token", personalAccessToken)
+						middlewareFunc(res, req)
+						Expect(res.WriteHeaderInputs).To(Equal([]int{403}))
+					})
+
+					It("returns unauthorized if not a personal access token", func() {
+						res.HeaderOutput = &http.Header{}
+						res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+						req.Header.Set("X-Tidepool-Personal-Access-Token", authTest.NewSessionToken())
+						middlewareFunc(res, req)
+						Expect(res.WriteHeaderInputs).To(Equal([]int{403}))
+					})
+
+					It("returns successfully", func() {
+						userID := serviceTest.NewUserID()
+						personalAccessTokenObject := &auth.PersonalAccessToken{
+							ID:     auth.NewPersonalAccessTokenID(),
+							UserID: userID,
+							Scopes: &auth.RestrictedTokenScopes{{Path: pointer.FromString(req.URL.Path)}},
+						}
+						authClient.AuthenticatePersonalAccessTokenOutputs = []authTest.AuthenticatePersonalAccessTokenOutput{{PersonalAccessToken: personalAccessTokenObject, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).ToNot(BeNil())
+							Expect(details.Method()).To(Equal(request.MethodPersonalAccessToken))
+							Expect(details.IsUser()).To(BeTrue())
+							Expect(details.UserID()).To(Equal(userID))
+							Expect(details.Token()).To(Equal(personalAccessToken))
+							Expect(service.GetRequestAuthDetails(req)).To(Equal(details))
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.AuthenticatePersonalAccessTokenInputs).To(HaveLen(1))
+						Expect(authClient.AuthenticatePersonalAccessTokenInputs[0].Token).To(Equal(personalAccessToken))
+					})
+
+					It("returns successfully with no details if personal access token is missing", func() {
+						authClient.AuthenticatePersonalAccessTokenOutputs = []authTest.AuthenticatePersonalAccessTokenOutput{{PersonalAccessToken: nil, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Expect(request.DetailsFromContext(req.Context())).To(BeNil())
+							Expect(service.GetRequestAuthDetails(req)).To(BeNil())
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.AuthenticatePersonalAccessTokenInputs).To(HaveLen(1))
+					})
+
+					It("returns internal server error if authenticating the personal access token fails", func() {
+						res.HeaderOutput = &http.Header{}
+						res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+						authClient.AuthenticatePersonalAccessTokenOutputs = []authTest.AuthenticatePersonalAccessTokenOutput{{PersonalAccessToken: nil, Error: errorsTest.RandomError()}}
+						middlewareFunc(res, req)
+						Expect(res.WriteHeaderInputs).To(Equal([]int{500}))
+						Expect(authClient.AuthenticatePersonalAccessTokenInputs).To(HaveLen(1))
+					})
+
+					It("returns successfully with no details if personal access token scopes do not authenticate request", func() {
+						personalAccessTokenObject := &auth.PersonalAccessToken{
+							ID:     auth.NewPersonalAccessTokenID(),
+							UserID: serviceTest.NewUserID(),
+							Scopes: &auth.RestrictedTokenScopes{{Path: pointer.FromString("/v1/other")}},
+						}
+						authClient.AuthenticatePersonalAccessTokenOutputs = []authTest.AuthenticatePersonalAccessTokenOutput{{PersonalAccessToken: personalAccessTokenObject, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Expect(request.DetailsFromContext(req.Context())).To(BeNil())
+							Expect(service.GetRequestAuthDetails(req)).To(BeNil())
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.AuthenticatePersonalAccessTokenInputs).To(HaveLen(1))
+					})
+				})
+
+				Context("with oauth access token", func() {
+					var oauthAccessToken string
+					var userID string
+
+					BeforeEach(func() {
+						oauthAccessToken = auth.NewOAuthSecret(auth.OAuthAccessTokenPrefix)
+						userID = serviceTest.NewUserID()
+						req.Method = http.MethodGet
+						req.URL.Path = "/data/" + userID
+						req.URL.RawPath = ""
+						req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", oauthAccessToken))
+					})
+
+					It("returns successfully", func() {
+						authClient.IntrospectOAuthTokenOutputs = []authTest.IntrospectOAuthTokenOutput{{OAuthTokenIntrospection: &auth.OAuthTokenIntrospection{
+							Active:         true,
+							Scope:          pointer.FromString(auth.OAuthScopeDataRead),
+							UserID:         pointer.FromString(userID),
+							ExpirationTime: pointer.FromInt64(time.Now().Add(time.Hour).Unix()),
+						}, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).ToNot(BeNil())
+							Expect(details.Method()).To(Equal(request.MethodOAuthAccessToken))
+							Expect(details.IsUser()).To(BeTrue())
+							Expect(details.UserID()).To(Equal(userID))
+							Expect(details.Token()).To(Equal(oauthAccessToken))
+							Expect(service.GetRequestAuthDetails(req)).To(Equal(details))
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.IntrospectOAuthTokenInputs).To(HaveLen(1))
+						Expect(authClient.IntrospectOAuthTokenInputs[0].Token).To(Equal(oauthAccessToken))
+						Expect(authClient.ValidateSessionTokenInputs).To(BeEmpty())
+					})
+
+					It("returns successfully with no details if oauth access token is not active", func() {
+						authClient.IntrospectOAuthTokenOutputs = []authTest.IntrospectOAuthTokenOutput{{OAuthTokenIntrospection: &auth.OAuthTokenIntrospection{}, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Expect(request.DetailsFromContext(req.Context())).To(BeNil())
+							Expect(service.GetRequestAuthDetails(req)).To(BeNil())
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.IntrospectOAuthTokenInputs).To(HaveLen(1))
+					})
+
+					It("returns successfully with no details if oauth access token scope does not authenticate request", func() {
+						authClient.IntrospectOAuthTokenOutputs = []authTest.IntrospectOAuthTokenOutput{{OAuthTokenIntrospection: &auth.OAuthTokenIntrospection{
+							Active:         true,
+							Scope:          pointer.FromString(auth.OAuthScopeDataWrite),
+							UserID:         pointer.FromString(userID),
+							ExpirationTime: pointer.FromInt64(time.Now().Add(time.Hour).Unix()),
+						}, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Expect(request.DetailsFromContext(req.Context())).To(BeNil())
+							Expect(service.GetRequestAuthDetails(req)).To(BeNil())
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.IntrospectOAuthTokenInputs).To(HaveLen(1))
+					})
+				})
+
 				Context("with restricted token", func() {
 					var restrictedToken string
 
@@ -368,6 +511,52 @@ var _ = Describe("Auth", func() {
 						Expect(authClient.GetRestrictedTokenInputs[0].ID).To(Equal(restrictedToken))
 					})
 
+					It("returns successfully after using restricted token with maximum use count", func() {
+						userID := serviceTest.NewUserID()
+						restrictedTokenObject := &auth.RestrictedToken{
+							ID:              restrictedToken,
+							UserID:          userID,
+							ExpirationTime:  time.Now().Add(time.Hour),
+							MaximumUseCount: pointer.FromInt(2),
+							UseCount:        1,
+						}
+						usedRestrictedTokenObject := *restrictedTokenObject
+						usedRestrictedTokenObject.UseCount = 2
+						authClient.GetRestrictedTokenOutputs = []authTest.GetRestrictedTokenOutput{{RestrictedToken: restrictedTokenObject, Error: nil}}
+						authClient.UseRestrictedTokenOutputs = []authTest.UseRestrictedTokenOutput{{RestrictedToken: &usedRestrictedTokenObject, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).ToNot(BeNil())
+							Expect(details.Method()).To(Equal(request.MethodRestrictedToken))
+							Expect(details.UserID()).To(Equal(userID))
+							Expect(details.Token()).To(Equal(restrictedToken))
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.UseRestrictedTokenInputs).To(HaveLen(1))
+						Expect(authClient.UseRestrictedTokenInputs[0].ID).To(Equal(restrictedToken))
+					})
+
+					It("returns successfully with no details if restricted token has no uses remaining", func() {
+						userID := serviceTest.NewUserID()
+						restrictedTokenObject := &auth.RestrictedToken{
+							ID:              restrictedToken,
+							UserID:          userID,
+							ExpirationTime:  time.Now().Add(time.Hour),
+							MaximumUseCount: pointer.FromInt(2),
+							UseCount:        1,
+						}
+						authClient.GetRestrictedTokenOutputs = []authTest.GetRestrictedTokenOutput{{RestrictedToken: restrictedTokenObject, Error: nil}}
+						authClient.UseRestrictedTokenOutputs = []authTest.UseRestrictedTokenOutput{{RestrictedToken: nil, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).To(BeNil())
+							Expect(service.GetRequestAuthDetails(req)).To(BeNil())
+						}
+						middlewareFunc(res, req)
+						Expect(authClient.UseRestrictedTokenInputs).To(HaveLen(1))
+						Expect(authClient.UseRestrictedTokenInputs[0].ID).To(Equal(restrictedToken))
+					})
+
 					It("returns successfully with no details if restricted token is not valid", func() {
 						authClient.GetRestrictedTokenOutputs = []authTest.GetRestrictedTokenOutput{{RestrictedToken: nil, Error: errorsTest.RandomError()}}
 						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
@@ -416,6 +605,68 @@ var _ = Describe("Auth", func() {
 						Expect(authClient.GetRestrictedTokenInputs[0].ID).To(Equal(restrictedToken))
 					})
 				})
+
+				Context("with client certificate", func() {
+					var clientCertificateDetails request.Details
+
+					BeforeEach(func() {
+						clientCertificateDetails = request.NewServiceDetails(request.MethodClientCertificate, "data")
+						req.Request = req.WithContext(request.NewContextWithDetails(req.Context(), clientCertificateDetails))
+					})
+
+					It("returns successfully", func() {
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).To(Equal(clientCertificateDetails))
+							Expect(details.IsService()).To(BeTrue())
+							Expect(details.HasToken()).To(BeFalse())
+							Expect(details.ServiceName()).To(Equal("data"))
+							Expect(service.GetRequestAuthDetails(req)).To(Equal(details))
+							Expect(service.GetRequestLogger(req)).To(Equal(lgr))
+						}
+						middlewareFunc(res, req)
+					})
+
+					It("returns successfully with session token details if a session token is also present", func() {
+						sessionToken := authTest.NewSessionToken()
+						userID := serviceTest.NewUserID()
+						req.Header.Add("X-Tidepool-Session-Token", sessionToken)
+						authClient.ValidateSessionTokenOutputs = []authTest.ValidateSessionTokenOutput{{Details: request.NewDetails(request.MethodSessionToken, userID, sessionToken), Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							details := request.DetailsFromContext(req.Context())
+							Expect(details).ToNot(BeNil())
+							Expect(details.Method()).To(Equal(request.MethodSessionToken))
+							Expect(details.UserID()).To(Equal(userID))
+						}
+						middlewareFunc(res, req)
+					})
+
+					It("responds with unauthenticated error if an invalid session token is also present", func() {
+						req.Header.Add("X-Tidepool-Session-Token", authTest.NewSessionToken())
+						authClient.ValidateSessionTokenOutputs = []authTest.ValidateSessionTokenOutput{{Details: nil, Error: errorsTest.RandomError()}}
+						res.HeaderOutput = &http.Header{}
+						res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Fail("handler function must not be invoked")
+						}
+						middlewareFunc(res, req)
+						Expect(res.WriteHeaderInputs).To(Equal([]int{401}))
+					})
+
+					It("responds with unauthenticated error if an invalid restricted token is also present", func() {
+						query := req.URL.Query()
+						query.Add("restricted_token", authTest.NewRestrictedToken())
+						req.URL.RawQuery = query.Encode()
+						authClient.GetRestrictedTokenOutputs = []authTest.GetRestrictedTokenOutput{{RestrictedToken: nil, Error: errorsTest.RandomError()}}
+						res.HeaderOutput = &http.Header{}
+						res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+						handlerFunc = func(res rest.ResponseWriter, req *rest.Request) {
+							Fail("handler function must not be invoked")
+						}
+						middlewareFunc(res, req)
+						Expect(res.WriteHeaderInputs).To(Equal([]int{401}))
+					})
+				})
 			})
 		})
 	})
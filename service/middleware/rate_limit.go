@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ant0ine/go-json-rest/rest"
+
+	"github.com/tidepool-org/platform/crypto"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/ratelimit"
+	"github.com/tidepool-org/platform/request"
+)
+
+// RateLimit must follow the auth middleware so that authenticated requests are limited by user or restricted token
+// rather than by client IP. Services are not limited.
+type RateLimit struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimit(limiter *ratelimit.Limiter) (*RateLimit, error) {
+	if limiter == nil {
+		return nil, errors.New("limiter is missing")
+	}
+
+	return &RateLimit{
+		limiter: limiter,
+	}, nil
+}
+
+func (r *RateLimit) MiddlewareFunc(handler rest.HandlerFunc) rest.HandlerFunc {
+	return func(res rest.ResponseWriter, req *rest.Request) {
+		if handler != nil && res != nil && req != nil {
+			if key := rateLimitKey(req); key != "" {
+				retryAfter, err := r.limiter.Allow(req.Context(), key, req.Method, req.URL.Path)
+				if err != nil {
+					log.LoggerFromContext(req.Context()).WithError(err).Warn("Unable to rate limit request; allowing")
+				} else if retryAfter > 0 {
+					retryAfterSeconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+					request.MustNewResponder(res, req).Error(http.StatusTooManyRequests, request.ErrorTooManyRequests(), request.NewHeaderMutator("Retry-After", retryAfterSeconds))
+					return
+				}
+			}
+
+			handler(res, req)
+		}
+	}
+}
+
+func rateLimitKey(req *rest.Request) string {
+	if details := request.DetailsFromContext(req.Context()); details != nil {
+		if details.IsService() {
+			return ""
+		} else if details.Method() == request.MethodRestrictedToken {
+			return "restrictedToken:" + crypto.HexEncodedSHA256Hash(details.Token())
+		}
+		return "user:" + details.UserID()
+	}
+	if clientIP := rateLimitClientIP(req); clientIP != "" {
+		return "ip:" + clientIP
+	}
+	return ""
+}
+
+// rateLimitClientIP uses the last forwarded address, which is added by the nearest proxy and cannot be spoofed by
+// the client, otherwise the remote address
+func rateLimitClientIP(req *rest.Request) string {
+	if forwardedFor := req.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		addresses := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+		if address := strings.TrimSpace(addresses[len(addresses)-1]); address != "" {
+			return address
+		}
+	}
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ant0ine/go-json-rest/rest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authTest "github.com/tidepool-org/platform/auth/test"
+	"github.com/tidepool-org/platform/log"
+	logNull "github.com/tidepool-org/platform/log/null"
+	"github.com/tidepool-org/platform/ratelimit"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/middleware"
+	serviceTest "github.com/tidepool-org/platform/service/test"
+	testRest "github.com/tidepool-org/platform/test/rest"
+)
+
+var _ = Describe("RateLimit", func() {
+	var limiter *ratelimit.Limiter
+
+	BeforeEach(func() {
+		cfg := ratelimit.NewConfig()
+		cfg.Default = ratelimit.Budget{Limit: 1, Window: time.Hour}
+		var err error
+		limiter, err = ratelimit.NewLimiter(cfg, ratelimit.NewMemoryBackend())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limiter).ToNot(BeNil())
+	})
+
+	Context("NewRateLimit", func() {
+		It("returns an error if limiter is missing", func() {
+			rateLimitMiddleware, err := middleware.NewRateLimit(nil)
+			Expect(err).To(MatchError("limiter is missing"))
+			Expect(rateLimitMiddleware).To(BeNil())
+		})
+
+		It("returns successfully", func() {
+			Expect(middleware.NewRateLimit(limiter)).ToNot(BeNil())
+		})
+	})
+
+	Context("with rate limit middleware, response, request, and middleware func", func() {
+		var handlerInvocations int
+		var middlewareFunc rest.HandlerFunc
+		var res *testRest.ResponseWriter
+		var req *rest.Request
+
+		BeforeEach(func() {
+			rateLimitMiddleware, err := middleware.NewRateLimit(limiter)
+			Expect(err).ToNot(HaveOccurred())
+			handlerInvocations = 0
+			middlewareFunc = rateLimitMiddleware.MiddlewareFunc(func(res rest.ResponseWriter, req *rest.Request) {
+				handlerInvocations++
+			})
+			res = testRest.NewResponseWriter()
+			req = newRateLimitRequest("192.0.2.1:1234", nil)
+		})
+
+		AfterEach(func() {
+			res.AssertOutputsEmpty()
+		})
+
+		expectTooManyRequests := func(req *rest.Request) {
+			res.HeaderOutput = &http.Header{}
+			res.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+			middlewareFunc(res, req)
+			Expect(res.WriteHeaderInputs).To(Equal([]int{429}))
+			Expect(res.HeaderOutput.Get("Retry-After")).To(MatchRegexp("^[0-9]+$"))
+			Expect(res.WriteInputs).To(HaveLen(1))
+			Expect(string(res.WriteInputs[0])).To(ContainSubstring(`"code":"too-many-requests"`))
+		}
+
+		It("does nothing if response is nil", func() {
+			middlewareFunc(nil, req)
+			Expect(handlerInvocations).To(Equal(0))
+		})
+
+		It("does nothing if request is nil", func() {
+			middlewareFunc(res, nil)
+			Expect(handlerInvocations).To(Equal(0))
+		})
+
+		It("limits requests by client IP", func() {
+			middlewareFunc(res, req)
+			Expect(handlerInvocations).To(Equal(1))
+			expectTooManyRequests(newRateLimitRequest("192.0.2.1:5678", nil))
+			Expect(handlerInvocations).To(Equal(1))
+			middlewareFunc(res, newRateLimitRequest("192.0.2.2:1234", nil))
+			Expect(handlerInvocations).To(Equal(2))
+		})
+
+		It("limits requests by the last forwarded for address", func() {
+			middlewareFunc(res, newRateLimitRequest("10.0.0.1:1234", nil, "203.0.113.1, 192.0.2.1"))
+			Expect(handlerInvocations).To(Equal(1))
+			expectTooManyRequests(newRateLimitRequest("10.0.0.2:1234", nil, "198.51.100.1, 192.0.2.1"))
+			Expect(handlerInvocations).To(Equal(1))
+		})
+
+		It("limits requests by authenticated user regardless of client IP", func() {
+			userID := serviceTest.NewUserID()
+			middlewareFunc(res, newRateLimitRequest("192.0.2.1:1234", request.NewDetails(request.MethodSessionToken, userID, authTest.NewSessionToken())))
+			Expect(handlerInvocations).To(Equal(1))
+			expectTooManyRequests(newRateLimitRequest("192.0.2.2:1234", request.NewDetails(request.MethodAccessToken, userID, authTest.NewSessionToken())))
+			Expect(handlerInvocations).To(Equal(1))
+			middlewareFunc(res, newRateLimitRequest("192.0.2.1:1234", nil))
+			Expect(handlerInvocations).To(Equal(2))
+		})
+
+		It("limits requests by restricted token separately from the user", func() {
+			userID := serviceTest.NewUserID()
+			restrictedToken := authTest.NewRestrictedToken()
+			middlewareFunc(res, newRateLimitRequest("192.0.2.1:1234", request.NewDetails(request.MethodRestrictedToken, userID, restrictedToken)))
+			Expect(handlerInvocations).To(Equal(1))
+			middlewareFunc(res, newRateLimitRequest("192.0.2.1:1234", request.NewDetails(request.MethodSessionToken, userID, authTest.NewSessionToken())))
+			Expect(handlerInvocations).To(Equal(2))
+			expectTooManyRequests(newRateLimitRequest("192.0.2.1:1234", request.NewDetails(request.MethodRestrictedToken, userID, restrictedToken)))
+			Expect(handlerInvocations).To(Equal(2))
+		})
+
+		It("does not limit services", func() {
+			for index := 0; index < 3; index++ {
+				middlewareFunc(res, newRateLimitRequest("192.0.2.1:1234", request.NewDetails(request.MethodServiceSecret, "", authTest.NewServiceSecret())))
+			}
+			Expect(handlerInvocations).To(Equal(3))
+		})
+	})
+})
+
+func newRateLimitRequest(remoteAddr string, details request.Details, forwardedFor ...string) *rest.Request {
+	req := testRest.NewRequest()
+	req.RemoteAddr = remoteAddr
+	for _, value := range forwardedFor {
+		req.Header.Add("X-Forwarded-For", value)
+	}
+	ctx := log.NewContextWithLogger(req.Context(), logNull.NewLogger())
+	if details != nil {
+		ctx = request.NewContextWithDetails(ctx, details)
+	}
+	req.Request = req.WithContext(ctx)
+	return req
+}
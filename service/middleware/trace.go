@@ -33,6 +33,10 @@ func (t *Trace) MiddlewareFunc(handler rest.HandlerFunc) rest.HandlerFunc {
 
 			trace := map[string]interface{}{}
 
+			if req.URL != nil {
+				req.Request = req.WithContext(request.NewContextWithRoute(req.Context(), req.Method+" "+req.URL.Path))
+			}
+
 			// DEPRECATED
 			oldTraceRequest := service.GetRequestTraceRequest(req)
 			defer service.SetRequestTraceRequest(req, oldTraceRequest)
@@ -183,6 +183,14 @@ var _ = Describe("Trace", func() {
 			Expect(res.Header()["X-Tidepool-Trace-Request"]).To(Equal([]string{traceRequest[0:64]}))
 		})
 
+		It("adds route", func() {
+			hndlr = func(res rest.ResponseWriter, req *rest.Request) {
+				Expect(request.RouteFromContext(req.Context())).To(Equal(req.Method + " " + req.URL.Path))
+			}
+			traceMiddleware.MiddlewareFunc(hndlr)(res, req)
+			Expect(request.RouteFromContext(req.Context())).To(BeEmpty())
+		})
+
 		It("does not add trace session if not specified", func() {
 			req.Request.Header.Del("X-Tidepool-Trace-Session")
 			hndlr = func(res rest.ResponseWriter, req *rest.Request) {
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/tidepool-org/platform/request"
+)
+
+// NewClientCertificateHandler adds service details to the request context if the request presented a verified client
+// certificate with an identity mapped to a service name. Otherwise, the request is passed through unchanged and must
+// authenticate by other means.
+func NewClientCertificateHandler(handler http.Handler, identities map[string]string) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+			if serviceName := ClientCertificateServiceName(req.TLS.VerifiedChains[0][0], identities); serviceName != "" {
+				req = req.WithContext(request.NewContextWithDetails(req.Context(), request.NewServiceDetails(request.MethodClientCertificate, serviceName)))
+			}
+		}
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// ClientCertificateServiceName returns the service name mapped to the first URI, then DNS, subject alternative name of
+// the certificate, or an empty string if none are mapped
+func ClientCertificateServiceName(certificate *x509.Certificate, identities map[string]string) string {
+	if certificate == nil {
+		return ""
+	}
+	for _, uri := range certificate.URIs {
+		if serviceName, ok := identities[uri.String()]; ok {
+			return serviceName
+		}
+	}
+	for _, dnsName := range certificate.DNSNames {
+		if serviceName, ok := identities[dnsName]; ok {
+			return serviceName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package server_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/service/server"
+)
+
+var _ = Describe("ClientCertificate", func() {
+	var identities map[string]string
+	var certificate *x509.Certificate
+
+	BeforeEach(func() {
+		identities = map[string]string{
+			"spiffe://tidepool.org/data": "data",
+			"blob.tidepool.svc":          "blob",
+		}
+		certificate = &x509.Certificate{}
+	})
+
+	Context("ClientCertificateServiceName", func() {
+		It("returns empty if the certificate is missing", func() {
+			Expect(server.ClientCertificateServiceName(nil, identities)).To(BeEmpty())
+		})
+
+		It("returns empty if the certificate does not have a mapped identity", func() {
+			certificate.URIs = []*url.URL{{Scheme: "spiffe", Host: "tidepool.org", Path: "/unknown"}}
+			certificate.DNSNames = []string{"unknown.tidepool.svc"}
+			Expect(server.ClientCertificateServiceName(certificate, identities)).To(BeEmpty())
+		})
+
+		It("returns the service name mapped to the uri", func() {
+			certificate.URIs = []*url.URL{{Scheme: "spiffe", Host: "tidepool.org", Path: "/data"}}
+			certificate.DNSNames = []string{"blob.tidepool.svc"}
+			Expect(server.ClientCertificateServiceName(certificate, identities)).To(Equal("data"))
+		})
+
+		It("returns the service name mapped to the dns name", func() {
+			certificate.DNSNames = []string{"unknown.tidepool.svc", "blob.tidepool.svc"}
+			Expect(server.ClientCertificateServiceName(certificate, identities)).To(Equal("blob"))
+		})
+	})
+
+	Context("NewClientCertificateHandler", func() {
+		var details request.Details
+		var handler http.Handler
+		var req *http.Request
+
+		BeforeEach(func() {
+			details = nil
+			handler = server.NewClientCertificateHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				details = request.DetailsFromContext(req.Context())
+			}), identities)
+			req = httptest.NewRequest(http.MethodGet, "https://data.tidepool.svc/v1/users", nil)
+			certificate.URIs = []*url.URL{{Scheme: "spiffe", Host: "tidepool.org", Path: "/data"}}
+		})
+
+		It("does not add details if the request is not over tls", func() {
+			req.TLS = nil
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(details).To(BeNil())
+		})
+
+		It("does not add details if the client certificate is not verified", func() {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(details).To(BeNil())
+		})
+
+		It("does not add details if the client certificate identity is not mapped", func() {
+			certificate.URIs = nil
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(details).To(BeNil())
+		})
+
+		It("adds service details if the client certificate is verified and the identity is mapped", func() {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(details).ToNot(BeNil())
+			Expect(details.Method()).To(Equal(request.MethodClientCertificate))
+			Expect(details.IsService()).To(BeTrue())
+			Expect(details.ServiceName()).To(Equal("data"))
+			Expect(details.HasToken()).To(BeFalse())
+		})
+	})
+})
@@ -3,18 +3,24 @@ package server
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tidepool-org/platform/config"
 	"github.com/tidepool-org/platform/errors"
 )
 
+// Config specifies the server. If the TLS client CA file is specified, then client certificates, if presented, are
+// verified against it and the identities (URI or DNS subject alternative names) of verified client certificates are
+// mapped to service names.
 type Config struct {
-	Address            string
-	TLS                bool
-	TLSCertificateFile string
-	TLSKeyFile         string
-	Timeout            time.Duration
+	Address             string
+	TLS                 bool
+	TLSCertificateFile  string
+	TLSKeyFile          string
+	TLSClientCAFile     string
+	TLSClientIdentities map[string]string
+	Timeout             time.Duration
 }
 
 func NewConfig() *Config {
@@ -40,6 +46,18 @@ func (c *Config) Load(configReporter config.Reporter) error {
 	}
 	c.TLSCertificateFile = configReporter.GetWithDefault("tls_certificate_file", "")
 	c.TLSKeyFile = configReporter.GetWithDefault("tls_key_file", "")
+	c.TLSClientCAFile = configReporter.GetWithDefault("tls_client_ca_file", "")
+	if tlsClientIdentitiesString := configReporter.GetWithDefault("tls_client_identities", ""); tlsClientIdentitiesString != "" {
+		tlsClientIdentities := map[string]string{}
+		for _, tlsClientIdentityString := range strings.Split(tlsClientIdentitiesString, ",") {
+			parts := strings.SplitN(strings.TrimSpace(tlsClientIdentityString), "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return errors.New("tls client identities is invalid")
+			}
+			tlsClientIdentities[parts[0]] = parts[1]
+		}
+		c.TLSClientIdentities = tlsClientIdentities
+	}
 	if timeoutString, err := configReporter.Get("timeout"); err == nil {
 		var timeout int64
 		timeout, err = strconv.ParseInt(timeoutString, 10, 0)
@@ -78,6 +96,21 @@ func (c *Config) Validate() error {
 			return errors.New("tls key file is a directory")
 		}
 	}
+	if c.TLSClientCAFile != "" {
+		if !c.TLS {
+			return errors.New("tls client ca file requires tls")
+		} else if fileInfo, err := os.Stat(c.TLSClientCAFile); err != nil {
+			if !os.IsNotExist(err) {
+				return errors.Wrap(err, "unable to stat tls client ca file")
+			}
+			return errors.New("tls client ca file does not exist")
+		} else if fileInfo.IsDir() {
+			return errors.New("tls client ca file is a directory")
+		}
+		if len(c.TLSClientIdentities) == 0 {
+			return errors.New("tls client identities is missing")
+		}
+	}
 	if c.Timeout <= 0 {
 		return errors.New("timeout is invalid")
 	}
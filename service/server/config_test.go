@@ -19,6 +19,8 @@ var _ = Describe("Config", func() {
 			Expect(config.TLS).To(BeTrue())
 			Expect(config.TLSCertificateFile).To(BeEmpty())
 			Expect(config.TLSKeyFile).To(BeEmpty())
+			Expect(config.TLSClientCAFile).To(BeEmpty())
+			Expect(config.TLSClientIdentities).To(BeEmpty())
 			Expect(config.Timeout).To(Equal(60 * time.Second))
 		})
 	})
@@ -40,6 +42,8 @@ var _ = Describe("Config", func() {
 				configReporter.Config["tls"] = "false"
 				configReporter.Config["tls_certificate_file"] = "my-certificate-file"
 				configReporter.Config["tls_key_file"] = "my-key-file"
+				configReporter.Config["tls_client_ca_file"] = "my-client-ca-file"
+				configReporter.Config["tls_client_identities"] = "spiffe://tidepool.org/data=data, blob.tidepool.svc=blob"
 				configReporter.Config["timeout"] = "120"
 			})
 
@@ -77,6 +81,11 @@ var _ = Describe("Config", func() {
 				Expect(config.TLSKeyFile).To(BeEmpty())
 			})
 
+			It("returns an error if the tls client identities are not identity and service name pairs", func() {
+				configReporter.Config["tls_client_identities"] = "spiffe://tidepool.org/data"
+				Expect(config.Load(configReporter)).To(MatchError("tls client identities is invalid"))
+			})
+
 			It("uses default timeout if not set", func() {
 				delete(configReporter.Config, "timeout")
 				Expect(config.Load(configReporter)).To(Succeed())
@@ -95,6 +104,8 @@ var _ = Describe("Config", func() {
 				Expect(config.TLS).To(BeFalse())
 				Expect(config.TLSCertificateFile).To(Equal("my-certificate-file"))
 				Expect(config.TLSKeyFile).To(Equal("my-key-file"))
+				Expect(config.TLSClientCAFile).To(Equal("my-client-ca-file"))
+				Expect(config.TLSClientIdentities).To(Equal(map[string]string{"spiffe://tidepool.org/data": "data", "blob.tidepool.svc": "blob"}))
 				Expect(config.Timeout).To(Equal(120 * time.Second))
 			})
 		})
@@ -148,6 +159,37 @@ var _ = Describe("Config", func() {
 					Expect(config.Validate()).To(MatchError("tls key file is a directory"))
 				})
 
+				Context("with tls client ca file", func() {
+					BeforeEach(func() {
+						config.TLSClientCAFile = "config_test.go"
+						config.TLSClientIdentities = map[string]string{"spiffe://tidepool.org/data": "data"}
+					})
+
+					It("returns success", func() {
+						Expect(config.Validate()).To(Succeed())
+					})
+
+					It("returns an error if TLS is not specified", func() {
+						config.TLS = false
+						Expect(config.Validate()).To(MatchError("tls client ca file requires tls"))
+					})
+
+					It("returns an error if the client ca file does not exist", func() {
+						config.TLSClientCAFile = "does_not_exist"
+						Expect(config.Validate()).To(MatchError("tls client ca file does not exist"))
+					})
+
+					It("returns an error if the client ca file is a directory", func() {
+						config.TLSClientCAFile = "."
+						Expect(config.Validate()).To(MatchError("tls client ca file is a directory"))
+					})
+
+					It("returns an error if the client identities are missing", func() {
+						config.TLSClientIdentities = nil
+						Expect(config.Validate()).To(MatchError("tls client identities is missing"))
+					})
+				})
+
 				It("returns an error if the timeout is invalid", func() {
 					config.Timeout = 0
 					Expect(config.Validate()).To(MatchError("timeout is invalid"))
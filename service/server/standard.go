@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/tidepool-org/platform/errors"
@@ -34,6 +37,20 @@ func NewStandard(cfg *Config, lgr log.Logger, api service.API) (*Standard, error
 	server := &http.Server{
 		Addr: cfg.Address,
 	}
+	if cfg.TLSClientCAFile != "" {
+		tlsClientCAs, err := ioutil.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read tls client ca file")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(tlsClientCAs) {
+			return nil, errors.New("tls client ca file is invalid")
+		}
+		server.TLSConfig = &tls.Config{
+			ClientAuth: tls.VerifyClientCertIfGiven,
+			ClientCAs:  clientCAs,
+		}
+	}
 
 	return &Standard{
 		logger: lgr,
@@ -45,6 +62,9 @@ func NewStandard(cfg *Config, lgr log.Logger, api service.API) (*Standard, error
 
 func (s *Standard) Serve() error {
 	s.server.Handler = s.api.Handler()
+	if s.config.TLSClientCAFile != "" {
+		s.server.Handler = NewClientCertificateHandler(s.server.Handler, s.config.TLSClientIdentities)
+	}
 
 	var err error
 	if s.config.TLS {
@@ -70,6 +70,17 @@ var _ = Describe("Standard", func() {
 			Expect(err).To(MatchError("config is invalid; address is missing"))
 			Expect(standard).To(BeNil())
 		})
+
+		It("returns an error if the tls client ca file does not contain certificates", func() {
+			cfg.TLS = true
+			cfg.TLSCertificateFile = "standard_test.go"
+			cfg.TLSKeyFile = "standard_test.go"
+			cfg.TLSClientCAFile = "standard_test.go"
+			cfg.TLSClientIdentities = map[string]string{"spiffe://tidepool.org/data": "data"}
+			standard, err := server.NewStandard(cfg, lgr, api)
+			Expect(err).To(MatchError("tls client ca file is invalid"))
+			Expect(standard).To(BeNil())
+		})
 	})
 
 	// NOTE: Unable to test Serve() function as it actually starts a server (and asks for permission to do on the Mac)
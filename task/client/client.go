@@ -126,3 +126,159 @@ func (c *Client) DeleteTask(ctx context.Context, id string) error {
 	url := c.client.ConstructURL("v1", "tasks", id)
 	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
 }
+
+func (c *Client) GetTaskWorkflow(ctx context.Context, id string) (*task.TaskWorkflow, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "tasks", id, "workflow")
+	workflow := &task.TaskWorkflow{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, nil, nil, workflow); err != nil {
+		if request.IsErrorResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+func (c *Client) ListDeadLetters(ctx context.Context, filter *task.DeadLetterFilter, pagination *page.Pagination) (task.Tasks, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewDeadLetterFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "dead_letters")
+	tsks := task.Tasks{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, []request.RequestMutator{filter, pagination}, nil, &tsks); err != nil {
+		return nil, err
+	}
+
+	return tsks, nil
+}
+
+func (c *Client) SummarizeDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (task.DeadLetterSummaries, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewDeadLetterFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "dead_letters", "summary")
+	summaries := task.DeadLetterSummaries{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, []request.RequestMutator{filter}, nil, &summaries); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func (c *Client) RequeueDeadLetters(ctx context.Context, requeue *task.DeadLetterRequeue) (*task.DeadLetterResult, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if requeue == nil {
+		return nil, errors.New("requeue is missing")
+	} else if err := structureValidator.New().Validate(requeue); err != nil {
+		return nil, errors.Wrap(err, "requeue is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "dead_letters", "requeue")
+	result := &task.DeadLetterResult{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, requeue, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) DeleteDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (*task.DeadLetterResult, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		return nil, errors.New("filter is missing")
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "dead_letters")
+	result := &task.DeadLetterResult{}
+	if err := c.client.RequestData(ctx, http.MethodDelete, url, []request.RequestMutator{filter}, nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) ListMaintenanceWindows(ctx context.Context, filter *task.MaintenanceWindowFilter, pagination *page.Pagination) (task.MaintenanceWindows, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewMaintenanceWindowFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "maintenance_windows")
+	maintenanceWindows := task.MaintenanceWindows{}
+	if err := c.client.RequestData(ctx, http.MethodGet, url, []request.RequestMutator{filter, pagination}, nil, &maintenanceWindows); err != nil {
+		return nil, err
+	}
+
+	return maintenanceWindows, nil
+}
+
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, create *task.MaintenanceWindowCreate) (*task.MaintenanceWindow, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	url := c.client.ConstructURL("v1", "maintenance_windows")
+	maintenanceWindow := &task.MaintenanceWindow{}
+	if err := c.client.RequestData(ctx, http.MethodPost, url, nil, create, maintenanceWindow); err != nil {
+		return nil, err
+	}
+
+	return maintenanceWindow, nil
+}
+
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if id == "" {
+		return errors.New("id is missing")
+	}
+
+	url := c.client.ConstructURL("v1", "maintenance_windows", id)
+	return c.client.RequestData(ctx, http.MethodDelete, url, nil, nil, nil)
+}
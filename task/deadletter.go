@@ -0,0 +1,106 @@
+package task
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+)
+
+const (
+	DeadLetterRequeueIDsLengthMaximum      = 1000
+	DeadLetterRequeueSpreadDurationMaximum = 24 * 60 * 60
+)
+
+type DeadLetterAccessor interface {
+	ListDeadLetters(ctx context.Context, filter *DeadLetterFilter, pagination *page.Pagination) (Tasks, error)
+	SummarizeDeadLetters(ctx context.Context, filter *DeadLetterFilter) (DeadLetterSummaries, error)
+	RequeueDeadLetters(ctx context.Context, requeue *DeadLetterRequeue) (*DeadLetterResult, error)
+	DeleteDeadLetters(ctx context.Context, filter *DeadLetterFilter) (*DeadLetterResult, error)
+}
+
+type DeadLetterFilter struct {
+	Type *string `json:"type,omitempty"`
+	Code *string `json:"code,omitempty"`
+}
+
+func NewDeadLetterFilter() *DeadLetterFilter {
+	return &DeadLetterFilter{}
+}
+
+func (d *DeadLetterFilter) Parse(parser structure.ObjectParser) {
+	d.Type = parser.String("type")
+	d.Code = parser.String("code")
+}
+
+func (d *DeadLetterFilter) Validate(validator structure.Validator) {
+	validator.String("type", d.Type).NotEmpty()
+	validator.String("code", d.Code).NotEmpty()
+}
+
+func (d *DeadLetterFilter) MutateRequest(req *http.Request) error {
+	parameters := map[string]string{}
+	if d.Type != nil {
+		parameters["type"] = *d.Type
+	}
+	if d.Code != nil {
+		parameters["code"] = *d.Code
+	}
+	return request.NewParametersMutator(parameters).MutateRequest(req)
+}
+
+type DeadLetterSummary struct {
+	Type               string    `json:"type" bson:"type"`
+	Code               string    `json:"code,omitempty" bson:"code,omitempty"`
+	Count              int       `json:"count" bson:"count"`
+	EarliestFailedTime time.Time `json:"earliestFailedTime" bson:"earliestFailedTime"`
+	LatestFailedTime   time.Time `json:"latestFailedTime" bson:"latestFailedTime"`
+}
+
+type DeadLetterSummaries []*DeadLetterSummary
+
+// DeadLetterRequeue requeues failed tasks matching the type and code, or the explicit task ids. The available
+// time of each requeued task is randomly spread across the spread duration (in seconds) to avoid overwhelming
+// downstream partners when requeuing many tasks at once.
+type DeadLetterRequeue struct {
+	Type           *string   `json:"type,omitempty"`
+	Code           *string   `json:"code,omitempty"`
+	IDs            *[]string `json:"ids,omitempty"`
+	SpreadDuration *int      `json:"spreadDuration,omitempty"`
+}
+
+func NewDeadLetterRequeue() *DeadLetterRequeue {
+	return &DeadLetterRequeue{}
+}
+
+func (d *DeadLetterRequeue) Parse(parser structure.ObjectParser) {
+	d.Type = parser.String("type")
+	d.Code = parser.String("code")
+	d.IDs = parser.StringArray("ids")
+	d.SpreadDuration = parser.Int("spreadDuration")
+}
+
+func (d *DeadLetterRequeue) Validate(validator structure.Validator) {
+	if d.IDs != nil {
+		validator.String("type", d.Type).NotExists()
+		validator.String("code", d.Code).NotExists()
+		validator.StringArray("ids", d.IDs).NotEmpty().LengthLessThanOrEqualTo(DeadLetterRequeueIDsLengthMaximum).EachUsing(IDValidator)
+	} else {
+		validator.String("type", d.Type).Exists().NotEmpty()
+		validator.String("code", d.Code).NotEmpty()
+	}
+	validator.Int("spreadDuration", d.SpreadDuration).InRange(0, DeadLetterRequeueSpreadDurationMaximum)
+}
+
+type DeadLetterResult struct {
+	Count int `json:"count"`
+}
+
+func NewDeadLetterResult(count int) *DeadLetterResult {
+	return &DeadLetterResult{
+		Count: count,
+	}
+}
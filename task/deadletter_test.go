@@ -0,0 +1,44 @@
+package task_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+var _ = Describe("DeadLetter", func() {
+	Context("DeadLetterRequeue", func() {
+		It("validates successfully with type and code", func() {
+			requeue := &task.DeadLetterRequeue{Type: pointer.FromString("test"), Code: pointer.FromString("code"), SpreadDuration: pointer.FromInt(3600)}
+			Expect(structureValidator.New().Validate(requeue)).To(Succeed())
+		})
+
+		It("validates successfully with ids", func() {
+			requeue := &task.DeadLetterRequeue{IDs: pointer.FromStringArray([]string{task.NewID()})}
+			Expect(structureValidator.New().Validate(requeue)).To(Succeed())
+		})
+
+		It("returns an error when neither type nor ids are specified", func() {
+			requeue := &task.DeadLetterRequeue{Code: pointer.FromString("code")}
+			Expect(structureValidator.New().Validate(requeue)).To(HaveOccurred())
+		})
+
+		It("returns an error when both type and ids are specified", func() {
+			requeue := &task.DeadLetterRequeue{Type: pointer.FromString("test"), IDs: pointer.FromStringArray([]string{task.NewID()})}
+			Expect(structureValidator.New().Validate(requeue)).To(HaveOccurred())
+		})
+
+		It("returns an error when an id is invalid", func() {
+			requeue := &task.DeadLetterRequeue{IDs: pointer.FromStringArray([]string{"invalid"})}
+			Expect(structureValidator.New().Validate(requeue)).To(HaveOccurred())
+		})
+
+		It("returns an error when the spread duration is out of range", func() {
+			requeue := &task.DeadLetterRequeue{Type: pointer.FromString("test"), SpreadDuration: pointer.FromInt(task.DeadLetterRequeueSpreadDurationMaximum + 1)}
+			Expect(structureValidator.New().Validate(requeue)).To(HaveOccurred())
+		})
+	})
+})
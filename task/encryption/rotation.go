@@ -0,0 +1,88 @@
+package encryption
+
+import (
+	"context"
+	"time"
+
+	"github.com/tidepool-org/platform/auth"
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/pointer"
+	"github.com/tidepool-org/platform/task"
+)
+
+const (
+	RotationType                   = "org.tidepool.encryption.provider_session.rotation"
+	RotationAvailableAfterDuration = 24 * time.Hour
+	RotationTaskDurationMaximum    = 30 * time.Minute
+)
+
+func RotationTaskName() string {
+	return RotationType
+}
+
+func NewDefaultRotationTaskCreate() *task.TaskCreate {
+	availableTime := time.Now().UTC()
+	expirationTime := availableTime.AddDate(1000, 0, 0)
+
+	return &task.TaskCreate{
+		Name:           pointer.FromString(RotationTaskName()),
+		Type:           RotationType,
+		Priority:       5,
+		AvailableTime:  pointer.FromTime(availableTime),
+		ExpirationTime: pointer.FromTime(expirationTime),
+	}
+}
+
+// RotationRunner periodically requests the auth service to encrypt all provider session OAuth tokens with the current
+// master key, which encrypts any tokens still stored in plaintext and retires previous master keys.
+type RotationRunner struct {
+	logger     log.Logger
+	authClient auth.Client
+}
+
+func NewRotationRunner(logger log.Logger, authClient auth.Client) (*RotationRunner, error) {
+	if logger == nil {
+		return nil, errors.New("logger is missing")
+	}
+	if authClient == nil {
+		return nil, errors.New("auth client is missing")
+	}
+
+	return &RotationRunner{
+		logger:     logger,
+		authClient: authClient,
+	}, nil
+}
+
+func (r *RotationRunner) CanRunTask(tsk *task.Task) bool {
+	return tsk != nil && tsk.Type == RotationType
+}
+
+func (r *RotationRunner) Run(ctx context.Context, tsk *task.Task) {
+	now := time.Now()
+
+	ctx = log.NewContextWithLogger(ctx, r.logger)
+
+	tsk.ClearError()
+
+	if serverSessionToken, err := r.authClient.ServerSessionToken(); err != nil {
+		tsk.AppendError(errors.Wrap(err, "unable to get server session token"))
+	} else {
+		ctx = auth.NewContextWithServerSessionToken(ctx, serverSessionToken)
+
+		if count, err := r.authClient.RotateProviderSessionEncryption(ctx); err != nil {
+			tsk.AppendError(errors.Wrap(err, "unable to rotate provider session encryption"))
+		} else {
+			tsk.AppendLog(log.InfoLevel, "Rotated provider session encryption", log.Fields{"count": count})
+		}
+	}
+
+	if !tsk.IsFailed() {
+		tsk.RepeatAvailableAfter(RotationAvailableAfterDuration)
+	}
+
+	if taskDuration := time.Since(now); taskDuration > RotationTaskDurationMaximum {
+		r.logger.WithField("taskDuration", taskDuration.Truncate(time.Millisecond).Seconds()).Warn("Task duration exceeds maximum")
+	}
+}
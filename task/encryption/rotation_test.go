@@ -0,0 +1,92 @@
+package encryption_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	authTest "github.com/tidepool-org/platform/auth/test"
+	errorsTest "github.com/tidepool-org/platform/errors/test"
+	logTest "github.com/tidepool-org/platform/log/test"
+	"github.com/tidepool-org/platform/task"
+	"github.com/tidepool-org/platform/task/encryption"
+)
+
+var _ = Describe("Rotation", func() {
+	Context("NewDefaultRotationTaskCreate", func() {
+		It("returns the rotation task create", func() {
+			create := encryption.NewDefaultRotationTaskCreate()
+			Expect(create).ToNot(BeNil())
+			Expect(*create.Name).To(Equal(encryption.RotationTaskName()))
+			Expect(create.Type).To(Equal(encryption.RotationType))
+			Expect(*create.AvailableTime).To(BeTemporally("~", time.Now(), time.Second))
+		})
+	})
+
+	Context("NewRotationRunner", func() {
+		It("returns an error if the logger is missing", func() {
+			runner, err := encryption.NewRotationRunner(nil, authTest.NewClient())
+			Expect(err).To(MatchError("logger is missing"))
+			Expect(runner).To(BeNil())
+		})
+
+		It("returns an error if the auth client is missing", func() {
+			runner, err := encryption.NewRotationRunner(logTest.NewLogger(), nil)
+			Expect(err).To(MatchError("auth client is missing"))
+			Expect(runner).To(BeNil())
+		})
+	})
+
+	Context("with new rotation runner", func() {
+		var authClient *authTest.Client
+		var runner *encryption.RotationRunner
+		var tsk *task.Task
+
+		BeforeEach(func() {
+			var err error
+			authClient = authTest.NewClient()
+			runner, err = encryption.NewRotationRunner(logTest.NewLogger(), authClient)
+			Expect(err).ToNot(HaveOccurred())
+			tsk, err = task.NewTask(encryption.NewDefaultRotationTaskCreate())
+			Expect(err).ToNot(HaveOccurred())
+			tsk.State = task.TaskStateRunning
+		})
+
+		AfterEach(func() {
+			authClient.AssertOutputsEmpty()
+		})
+
+		It("can run only the rotation task", func() {
+			Expect(runner.CanRunTask(tsk)).To(BeTrue())
+			tsk.Type = "other"
+			Expect(runner.CanRunTask(tsk)).To(BeFalse())
+		})
+
+		It("records the error and repeats if the server session token is not available", func() {
+			authClient.ServerSessionTokenOutputs = []authTest.ServerSessionTokenOutput{{Error: errorsTest.RandomError()}}
+			runner.Run(context.Background(), tsk)
+			Expect(tsk.HasError()).To(BeTrue())
+			Expect(tsk.State).To(Equal(task.TaskStatePending))
+		})
+
+		It("records the error and repeats if the rotation fails", func() {
+			authClient.ServerSessionTokenOutputs = []authTest.ServerSessionTokenOutput{{Token: "token"}}
+			authClient.RotateProviderSessionEncryptionOutputs = []authTest.RotateProviderSessionEncryptionOutput{{Error: errorsTest.RandomError()}}
+			runner.Run(context.Background(), tsk)
+			Expect(tsk.HasError()).To(BeTrue())
+			Expect(tsk.State).To(Equal(task.TaskStatePending))
+		})
+
+		It("rotates and repeats after a day", func() {
+			authClient.ServerSessionTokenOutputs = []authTest.ServerSessionTokenOutput{{Token: "token"}}
+			authClient.RotateProviderSessionEncryptionOutputs = []authTest.RotateProviderSessionEncryptionOutput{{Count: 2}}
+			runner.Run(context.Background(), tsk)
+			Expect(tsk.HasError()).To(BeFalse())
+			Expect(tsk.State).To(Equal(task.TaskStatePending))
+			Expect(*tsk.AvailableTime).To(BeTemporally("~", time.Now().Add(encryption.RotationAvailableAfterDuration), time.Second))
+			Expect(authClient.RotateProviderSessionEncryptionInvocations).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package task
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	MaintenanceWindowDurationMaximum     = 7 * 24 * time.Hour
+	MaintenanceWindowReasonLengthMaximum = 1000
+)
+
+// MaintenanceWindowAccessor manages one-off maintenance windows, typically set by operations during a partner
+// outage. Any provider task that runs during a maintenance window for that provider is rescheduled to the end of the
+// window.
+type MaintenanceWindowAccessor interface {
+	ListMaintenanceWindows(ctx context.Context, filter *MaintenanceWindowFilter, pagination *page.Pagination) (MaintenanceWindows, error)
+	CreateMaintenanceWindow(ctx context.Context, create *MaintenanceWindowCreate) (*MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, id string) error
+}
+
+// MaintenanceWindowFilter filters by provider and, if expired is specified, by whether the window has already ended
+type MaintenanceWindowFilter struct {
+	Provider *string `json:"provider,omitempty"`
+	Expired  *bool   `json:"expired,omitempty"`
+}
+
+func NewMaintenanceWindowFilter() *MaintenanceWindowFilter {
+	return &MaintenanceWindowFilter{}
+}
+
+func (m *MaintenanceWindowFilter) Parse(parser structure.ObjectParser) {
+	m.Provider = parser.String("provider")
+	m.Expired = parser.Bool("expired")
+}
+
+func (m *MaintenanceWindowFilter) Validate(validator structure.Validator) {
+	validator.String("provider", m.Provider).NotEmpty()
+}
+
+func (m *MaintenanceWindowFilter) MutateRequest(req *http.Request) error {
+	parameters := map[string]string{}
+	if m.Provider != nil {
+		parameters["provider"] = *m.Provider
+	}
+	if m.Expired != nil {
+		parameters["expired"] = strconv.FormatBool(*m.Expired)
+	}
+	return request.NewParametersMutator(parameters).MutateRequest(req)
+}
+
+type MaintenanceWindowCreate struct {
+	Provider  *string    `json:"provider,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	Reason    *string    `json:"reason,omitempty"`
+}
+
+func NewMaintenanceWindowCreate() *MaintenanceWindowCreate {
+	return &MaintenanceWindowCreate{}
+}
+
+func (m *MaintenanceWindowCreate) Parse(parser structure.ObjectParser) {
+	m.Provider = parser.String("provider")
+	m.StartTime = parser.Time("startTime", time.RFC3339Nano)
+	m.EndTime = parser.Time("endTime", time.RFC3339Nano)
+	m.Reason = parser.String("reason")
+}
+
+func (m *MaintenanceWindowCreate) Validate(validator structure.Validator) {
+	validator.String("provider", m.Provider).Exists().NotEmpty()
+	validator.Time("startTime", m.StartTime).Exists().NotZero()
+	endTimeValidator := validator.Time("endTime", m.EndTime).Exists().AfterNow(time.Second)
+	if m.StartTime != nil {
+		endTimeValidator.After(*m.StartTime).Before(m.StartTime.Add(MaintenanceWindowDurationMaximum))
+	}
+	validator.String("reason", m.Reason).NotEmpty().LengthLessThanOrEqualTo(MaintenanceWindowReasonLengthMaximum)
+}
+
+type MaintenanceWindow struct {
+	ID          string    `json:"id" bson:"id"`
+	Provider    string    `json:"provider" bson:"provider"`
+	StartTime   time.Time `json:"startTime" bson:"startTime"`
+	EndTime     time.Time `json:"endTime" bson:"endTime"`
+	Reason      *string   `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedTime time.Time `json:"createdTime" bson:"createdTime"`
+}
+
+func NewMaintenanceWindow(create *MaintenanceWindowCreate) (*MaintenanceWindow, error) {
+	if create == nil {
+		return nil, errors.New("create is missing")
+	} else if err := structureValidator.New().Validate(create); err != nil {
+		return nil, errors.Wrap(err, "create is invalid")
+	}
+
+	return &MaintenanceWindow{
+		ID:          NewID(),
+		Provider:    *create.Provider,
+		StartTime:   *create.StartTime,
+		EndTime:     *create.EndTime,
+		Reason:      create.Reason,
+		CreatedTime: time.Now(),
+	}, nil
+}
+
+func (m *MaintenanceWindow) Parse(parser structure.ObjectParser) {
+	if ptr := parser.String("id"); ptr != nil {
+		m.ID = *ptr
+	}
+	if ptr := parser.String("provider"); ptr != nil {
+		m.Provider = *ptr
+	}
+	if ptr := parser.Time("startTime", time.RFC3339Nano); ptr != nil {
+		m.StartTime = *ptr
+	}
+	if ptr := parser.Time("endTime", time.RFC3339Nano); ptr != nil {
+		m.EndTime = *ptr
+	}
+	m.Reason = parser.String("reason")
+	if ptr := parser.Time("createdTime", time.RFC3339Nano); ptr != nil {
+		m.CreatedTime = *ptr
+	}
+}
+
+func (m *MaintenanceWindow) Validate(validator structure.Validator) {
+	validator.String("id", &m.ID).Using(IDValidator)
+	validator.String("provider", &m.Provider).NotEmpty()
+	validator.Time("startTime", &m.StartTime).NotZero()
+	validator.Time("endTime", &m.EndTime).After(m.StartTime)
+	validator.String("reason", m.Reason).NotEmpty().LengthLessThanOrEqualTo(MaintenanceWindowReasonLengthMaximum)
+	validator.Time("createdTime", &m.CreatedTime).NotZero().BeforeNow(time.Second)
+}
+
+// Includes returns true if the time is within the window, including the start time and excluding the end time
+func (m *MaintenanceWindow) Includes(tm time.Time) bool {
+	return !tm.Before(m.StartTime) && tm.Before(m.EndTime)
+}
+
+type MaintenanceWindows []*MaintenanceWindow
@@ -0,0 +1,94 @@
+package task_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+var _ = Describe("Maintenance", func() {
+	Context("MaintenanceWindowCreate", func() {
+		var create *task.MaintenanceWindowCreate
+
+		BeforeEach(func() {
+			create = &task.MaintenanceWindowCreate{
+				Provider:  pointer.FromString("dexcom"),
+				StartTime: pointer.FromTime(time.Now().Add(-time.Hour)),
+				EndTime:   pointer.FromTime(time.Now().Add(time.Hour)),
+				Reason:    pointer.FromString("partner outage"),
+			}
+		})
+
+		It("validates successfully", func() {
+			Expect(structureValidator.New().Validate(create)).To(Succeed())
+		})
+
+		It("returns an error when the provider is missing", func() {
+			create.Provider = nil
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("returns an error when the end time is before the start time", func() {
+			create.StartTime = pointer.FromTime(time.Now().Add(2 * time.Hour))
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("returns an error when the end time is in the past", func() {
+			create.EndTime = pointer.FromTime(time.Now().Add(-time.Minute))
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		It("returns an error when the duration exceeds the maximum", func() {
+			create.EndTime = pointer.FromTime(create.StartTime.Add(task.MaintenanceWindowDurationMaximum + time.Second))
+			Expect(structureValidator.New().Validate(create)).To(HaveOccurred())
+		})
+
+		Context("NewMaintenanceWindow", func() {
+			It("returns an error when create is missing", func() {
+				maintenanceWindow, err := task.NewMaintenanceWindow(nil)
+				Expect(err).To(MatchError("create is missing"))
+				Expect(maintenanceWindow).To(BeNil())
+			})
+
+			It("returns successfully", func() {
+				maintenanceWindow, err := task.NewMaintenanceWindow(create)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(maintenanceWindow).ToNot(BeNil())
+				Expect(task.IsValidID(maintenanceWindow.ID)).To(BeTrue())
+				Expect(maintenanceWindow.Provider).To(Equal(*create.Provider))
+				Expect(maintenanceWindow.StartTime).To(Equal(*create.StartTime))
+				Expect(maintenanceWindow.EndTime).To(Equal(*create.EndTime))
+				Expect(maintenanceWindow.Reason).To(Equal(create.Reason))
+				Expect(structureValidator.New().Validate(maintenanceWindow)).To(Succeed())
+			})
+		})
+	})
+
+	Context("MaintenanceWindow", func() {
+		Context("Includes", func() {
+			var maintenanceWindow *task.MaintenanceWindow
+
+			BeforeEach(func() {
+				now := time.Now()
+				maintenanceWindow = &task.MaintenanceWindow{StartTime: now, EndTime: now.Add(time.Hour)}
+			})
+
+			It("returns true for the start time", func() {
+				Expect(maintenanceWindow.Includes(maintenanceWindow.StartTime)).To(BeTrue())
+			})
+
+			It("returns false for the end time", func() {
+				Expect(maintenanceWindow.Includes(maintenanceWindow.EndTime)).To(BeFalse())
+			})
+
+			It("returns false before the start time", func() {
+				Expect(maintenanceWindow.Includes(maintenanceWindow.StartTime.Add(-time.Second))).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,199 @@
+package task
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/structure"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+)
+
+const (
+	ProgressPercentMaximum     = 100.0
+	ProgressPercentMinimum     = 0.0
+	ProgressPhaseLengthMaximum = 100
+
+	LogEntriesLengthMaximum      = 50
+	LogEntryMessageLengthMaximum = 1000
+)
+
+type Progress struct {
+	Percent     *float64       `json:"percent,omitempty" bson:"percent,omitempty"`
+	Phase       *string        `json:"phase,omitempty" bson:"phase,omitempty"`
+	Counters    map[string]int `json:"counters,omitempty" bson:"counters,omitempty"`
+	UpdatedTime *time.Time     `json:"updatedTime,omitempty" bson:"updatedTime,omitempty"`
+}
+
+func ParseProgress(parser structure.ObjectParser) *Progress {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewProgress()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewProgress() *Progress {
+	return &Progress{}
+}
+
+func (p *Progress) Parse(parser structure.ObjectParser) {
+	p.Percent = parser.Float64("percent")
+	p.Phase = parser.String("phase")
+	if countersParser := parser.WithReferenceObjectParser("counters"); countersParser.Exists() {
+		p.Counters = map[string]int{}
+		for _, reference := range countersParser.References() {
+			if ptr := countersParser.Int(reference); ptr != nil {
+				p.Counters[reference] = *ptr
+			}
+		}
+	}
+	p.UpdatedTime = parser.Time("updatedTime", time.RFC3339Nano)
+}
+
+func (p *Progress) Validate(validator structure.Validator) {
+	validator.Float64("percent", p.Percent).InRange(ProgressPercentMinimum, ProgressPercentMaximum)
+	validator.String("phase", p.Phase).NotEmpty().LengthLessThanOrEqualTo(ProgressPhaseLengthMaximum)
+	if p.Counters != nil {
+		countersValidator := validator.WithReference("counters")
+		for key, value := range p.Counters {
+			countersValidator.Int(key, &value).GreaterThanOrEqualTo(0)
+		}
+	}
+	validator.Time("updatedTime", p.UpdatedTime).NotZero().BeforeNow(time.Second)
+}
+
+type LogEntry struct {
+	Time    time.Time              `json:"time,omitempty" bson:"time,omitempty"`
+	Level   log.Level              `json:"level,omitempty" bson:"level,omitempty"`
+	Message string                 `json:"message,omitempty" bson:"message,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty" bson:"fields,omitempty"`
+}
+
+func ParseLogEntry(parser structure.ObjectParser) *LogEntry {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewLogEntry()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewLogEntry() *LogEntry {
+	return &LogEntry{}
+}
+
+func (l *LogEntry) Parse(parser structure.ObjectParser) {
+	if ptr := parser.Time("time", time.RFC3339Nano); ptr != nil {
+		l.Time = *ptr
+	}
+	if ptr := parser.String("level"); ptr != nil {
+		l.Level = log.Level(*ptr)
+	}
+	if ptr := parser.String("message"); ptr != nil {
+		l.Message = *ptr
+	}
+	if ptr := parser.Object("fields"); ptr != nil {
+		l.Fields = *ptr
+	}
+}
+
+func (l *LogEntry) Validate(validator structure.Validator) {
+	validator.Time("time", &l.Time).NotZero()
+	level := string(l.Level)
+	validator.String("level", &level).OneOf(LogEntryLevels()...)
+	validator.String("message", &l.Message).NotEmpty().LengthLessThanOrEqualTo(LogEntryMessageLengthMaximum)
+}
+
+func LogEntryLevels() []string {
+	return []string{
+		string(log.DebugLevel),
+		string(log.InfoLevel),
+		string(log.WarnLevel),
+		string(log.ErrorLevel),
+	}
+}
+
+type LogEntries []*LogEntry
+
+func ParseLogEntries(parser structure.ArrayParser) *LogEntries {
+	if !parser.Exists() {
+		return nil
+	}
+	datum := NewLogEntries()
+	parser.Parse(datum)
+	return datum
+}
+
+func NewLogEntries() *LogEntries {
+	return &LogEntries{}
+}
+
+func (l *LogEntries) Parse(parser structure.ArrayParser) {
+	for _, reference := range parser.References() {
+		*l = append(*l, ParseLogEntry(parser.WithReferenceObjectParser(reference)))
+	}
+}
+
+func (l *LogEntries) Validate(validator structure.Validator) {
+	if length := len(*l); length > LogEntriesLengthMaximum {
+		validator.ReportError(structureValidator.ErrorLengthNotLessThanOrEqualTo(length, LogEntriesLengthMaximum))
+	}
+	for index, logEntry := range *l {
+		if logEntryValidator := validator.WithReference(strconv.Itoa(index)); logEntry != nil {
+			logEntry.Validate(logEntryValidator)
+		} else {
+			logEntryValidator.ReportError(structureValidator.ErrorValueNotExists())
+		}
+	}
+}
+
+// Append adds the log entry and discards the oldest entries beyond the maximum length
+func (l *LogEntries) Append(logEntry *LogEntry) {
+	if logEntry != nil {
+		*l = append(*l, logEntry)
+		if length := len(*l); length > LogEntriesLengthMaximum {
+			*l = (*l)[length-LogEntriesLengthMaximum:]
+		}
+	}
+}
+
+type ProgressReporter interface {
+	ReportProgress(ctx context.Context, tsk *Task) error
+}
+
+type contextKey string
+
+const progressReporterContextKey contextKey = "progressReporter"
+
+func NewContextWithProgressReporter(ctx context.Context, progressReporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterContextKey, progressReporter)
+}
+
+func ProgressReporterFromContext(ctx context.Context) ProgressReporter {
+	if ctx != nil {
+		if progressReporter, ok := ctx.Value(progressReporterContextKey).(ProgressReporter); ok {
+			return progressReporter
+		}
+	}
+	return nil
+}
+
+// ReportProgress persists the current progress and log entries of the task using the progress reporter
+// from the context, if any. Runners should call this periodically during long running tasks.
+func ReportProgress(ctx context.Context, tsk *Task) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if tsk == nil {
+		return errors.New("task is missing")
+	}
+
+	if progressReporter := ProgressReporterFromContext(ctx); progressReporter != nil {
+		return progressReporter.ReportProgress(ctx, tsk)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package task_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+type progressReporter struct {
+	tasks []*task.Task
+	err   error
+}
+
+func (p *progressReporter) ReportProgress(ctx context.Context, tsk *task.Task) error {
+	p.tasks = append(p.tasks, tsk)
+	return p.err
+}
+
+var _ = Describe("Progress", func() {
+	Context("Progress", func() {
+		It("validates successfully", func() {
+			tsk := &task.Task{}
+			tsk.SetProgress(pointer.FromFloat64(50), pointer.FromString("fetch"), map[string]int{"data": 10})
+			Expect(structureValidator.New().Validate(tsk.Progress)).To(Succeed())
+			Expect(tsk.Progress.UpdatedTime).ToNot(BeNil())
+		})
+
+		It("returns an error if the percent is out of range", func() {
+			progress := &task.Progress{Percent: pointer.FromFloat64(100.1)}
+			Expect(structureValidator.New().Validate(progress)).To(HaveOccurred())
+		})
+
+		It("returns an error if a counter is negative", func() {
+			progress := &task.Progress{Counters: map[string]int{"data": -1}}
+			Expect(structureValidator.New().Validate(progress)).To(HaveOccurred())
+		})
+	})
+
+	Context("LogEntries", func() {
+		It("appends log entries", func() {
+			tsk := &task.Task{}
+			tsk.AppendLog(log.InfoLevel, "first", nil)
+			tsk.AppendLog(log.WarnLevel, "second", log.Fields{"count": 1})
+			Expect(tsk.Log).To(HaveLen(2))
+			Expect(tsk.Log[0].Message).To(Equal("first"))
+			Expect(tsk.Log[1].Level).To(Equal(log.WarnLevel))
+			Expect(structureValidator.New().Validate(&tsk.Log)).To(Succeed())
+		})
+
+		It("discards the oldest log entries beyond the maximum length", func() {
+			tsk := &task.Task{}
+			for index := 0; index <= task.LogEntriesLengthMaximum; index++ {
+				tsk.AppendLog(log.InfoLevel, "message", log.Fields{"index": index})
+			}
+			Expect(tsk.Log).To(HaveLen(task.LogEntriesLengthMaximum))
+			Expect(tsk.Log[0].Fields["index"]).To(Equal(1))
+		})
+	})
+
+	Context("ReportProgress", func() {
+		It("returns an error if the context is missing", func() {
+			Expect(task.ReportProgress(nil, &task.Task{})).To(MatchError("context is missing"))
+		})
+
+		It("returns an error if the task is missing", func() {
+			Expect(task.ReportProgress(context.Background(), nil)).To(MatchError("task is missing"))
+		})
+
+		It("returns successfully if the context does not have a progress reporter", func() {
+			Expect(task.ReportProgress(context.Background(), &task.Task{})).To(Succeed())
+		})
+
+		It("reports progress using the progress reporter from the context", func() {
+			reporter := &progressReporter{}
+			tsk := &task.Task{}
+			Expect(task.ReportProgress(task.NewContextWithProgressReporter(context.Background(), reporter), tsk)).To(Succeed())
+			Expect(reporter.tasks).To(ConsistOf(tsk))
+		})
+
+		It("returns the error from the progress reporter", func() {
+			reporter := &progressReporter{err: errors.New("test error")}
+			Expect(task.ReportProgress(task.NewContextWithProgressReporter(context.Background(), reporter), &task.Task{})).To(MatchError("test error"))
+		})
+	})
+})
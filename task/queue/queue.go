@@ -8,7 +8,8 @@ import (
 	"sync"
 	"time"
 
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/tidepool-org/platform/config"
 	"github.com/tidepool-org/platform/errors"
@@ -18,15 +19,54 @@ import (
 	"github.com/tidepool-org/platform/task/store"
 )
 
+var (
+	QueuePendingTasks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tidepool_task_queue_pending_tasks",
+		Help: "The number of pending tasks available for dispatch sorted by type",
+	}, []string{"type"})
+	QueueDispatchLatencySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tidepool_task_queue_dispatch_latency_seconds",
+		Help:    "The duration from when a task is available until it is running sorted by type",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+	}, []string{"type"})
+	QueueRunDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tidepool_task_queue_run_duration_seconds",
+		Help:    "The duration of task runs sorted by type",
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 10),
+	}, []string{"type"})
+	QueueFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tidepool_task_queue_failures_total",
+		Help: "The total number of failed task runs sorted by type and error code",
+	}, []string{"type", "code"})
+	QueueWorkersAvailable = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "tidepool_task_queue_workers_available",
+		Help: "The number of workers available to run tasks",
+	})
+)
+
 type Config struct {
-	Workers int
-	Delay   time.Duration
+	Workers            int
+	Delay              time.Duration
+	SweepInterval      time.Duration
+	ReportInterval     time.Duration
+	CompletedRetention time.Duration
+	FailedRetention    time.Duration
+	ExpiredRetention   time.Duration
+	CanceledRetention  time.Duration
 }
 
+// NewConfig returns the default config. Failed tasks are retained indefinitely by default so they remain
+// available as dead letters. A retention of zero disables deletion of tasks in the associated state.
 func NewConfig() *Config {
 	return &Config{
-		Workers: 1,
-		Delay:   60 * time.Second,
+		Workers:            1,
+		Delay:              60 * time.Second,
+		SweepInterval:      time.Hour,
+		ReportInterval:     30 * time.Second,
+		CompletedRetention: 7 * 24 * time.Hour,
+		FailedRetention:    0,
+		ExpiredRetention:   7 * 24 * time.Hour,
+		CanceledRetention:  7 * 24 * time.Hour,
 	}
 }
 
@@ -51,6 +91,54 @@ func (c *Config) Load(configReporter config.Reporter) error {
 		}
 		c.Delay = time.Duration(delay) * time.Second
 	}
+	if sweepIntervalString, err := configReporter.Get("sweep_interval"); err == nil {
+		var sweepInterval int64
+		sweepInterval, err = strconv.ParseInt(sweepIntervalString, 10, 0)
+		if err != nil {
+			return errors.New("sweep interval is invalid")
+		}
+		c.SweepInterval = time.Duration(sweepInterval) * time.Second
+	}
+	if reportIntervalString, err := configReporter.Get("report_interval"); err == nil {
+		var reportInterval int64
+		reportInterval, err = strconv.ParseInt(reportIntervalString, 10, 0)
+		if err != nil {
+			return errors.New("report interval is invalid")
+		}
+		c.ReportInterval = time.Duration(reportInterval) * time.Second
+	}
+	if completedRetentionString, err := configReporter.Get("completed_retention"); err == nil {
+		var completedRetention int64
+		completedRetention, err = strconv.ParseInt(completedRetentionString, 10, 0)
+		if err != nil {
+			return errors.New("completed retention is invalid")
+		}
+		c.CompletedRetention = time.Duration(completedRetention) * time.Second
+	}
+	if failedRetentionString, err := configReporter.Get("failed_retention"); err == nil {
+		var failedRetention int64
+		failedRetention, err = strconv.ParseInt(failedRetentionString, 10, 0)
+		if err != nil {
+			return errors.New("failed retention is invalid")
+		}
+		c.FailedRetention = time.Duration(failedRetention) * time.Second
+	}
+	if expiredRetentionString, err := configReporter.Get("expired_retention"); err == nil {
+		var expiredRetention int64
+		expiredRetention, err = strconv.ParseInt(expiredRetentionString, 10, 0)
+		if err != nil {
+			return errors.New("expired retention is invalid")
+		}
+		c.ExpiredRetention = time.Duration(expiredRetention) * time.Second
+	}
+	if canceledRetentionString, err := configReporter.Get("canceled_retention"); err == nil {
+		var canceledRetention int64
+		canceledRetention, err = strconv.ParseInt(canceledRetentionString, 10, 0)
+		if err != nil {
+			return errors.New("canceled retention is invalid")
+		}
+		c.CanceledRetention = time.Duration(canceledRetention) * time.Second
+	}
 
 	return nil
 }
@@ -62,6 +150,24 @@ func (c *Config) Validate() error {
 	if c.Delay < 0 {
 		return errors.New("delay is invalid")
 	}
+	if c.SweepInterval <= 0 {
+		return errors.New("sweep interval is invalid")
+	}
+	if c.ReportInterval <= 0 {
+		return errors.New("report interval is invalid")
+	}
+	if c.CompletedRetention < 0 {
+		return errors.New("completed retention is invalid")
+	}
+	if c.FailedRetention < 0 {
+		return errors.New("failed retention is invalid")
+	}
+	if c.ExpiredRetention < 0 {
+		return errors.New("expired retention is invalid")
+	}
+	if c.CanceledRetention < 0 {
+		return errors.New("canceled retention is invalid")
+	}
 
 	return nil
 }
@@ -77,6 +183,10 @@ type Queue struct {
 	store             store.Store
 	workers           int
 	delay             time.Duration
+	sweepInterval     time.Duration
+	reportInterval    time.Duration
+	pendingTypes      map[string]bool
+	retentions        map[string]time.Duration
 	runners           []Runner
 	cancelFunc        context.CancelFunc
 	waitGroup         sync.WaitGroup
@@ -85,7 +195,7 @@ type Queue struct {
 	completionChannel chan *task.Task
 	timer             *time.Timer
 	taskRepository    store.TaskRepository
-	iterator          *mongo.Cursor
+	iterator          store.TaskIterator
 }
 
 func New(cfg *Config, lgr log.Logger, str store.Store) (*Queue, error) {
@@ -107,10 +217,19 @@ func New(cfg *Config, lgr log.Logger, str store.Store) (*Queue, error) {
 	delay := cfg.Delay
 
 	return &Queue{
-		logger:            lgr,
-		store:             str,
-		workers:           workers,
-		delay:             delay,
+		logger:         lgr,
+		store:          str,
+		workers:        workers,
+		delay:          delay,
+		sweepInterval:  cfg.SweepInterval,
+		reportInterval: cfg.ReportInterval,
+		pendingTypes:   map[string]bool{},
+		retentions: map[string]time.Duration{
+			task.TaskStateCompleted: cfg.CompletedRetention,
+			task.TaskStateFailed:    cfg.FailedRetention,
+			task.TaskStateExpired:   cfg.ExpiredRetention,
+			task.TaskStateCanceled:  cfg.CanceledRetention,
+		},
 		runners:           []Runner{},
 		dispatchChannel:   make(chan *task.Task, workers),
 		completionChannel: make(chan *task.Task, workers),
@@ -133,6 +252,8 @@ func (q *Queue) Start() {
 
 		q.startWorkers(ctx)
 		q.startManager(ctx)
+		q.startSweeper(ctx)
+		q.startReporter(ctx)
 	}
 }
 
@@ -149,6 +270,7 @@ func (q *Queue) startWorkers(ctx context.Context) {
 	for q.workersAvailable = 0; q.workersAvailable < q.workers; q.workersAvailable++ {
 		q.startWorker(ctx)
 	}
+	QueueWorkersAvailable.Set(float64(q.workersAvailable))
 }
 
 func (q *Queue) startWorker(ctx context.Context) {
@@ -171,6 +293,8 @@ func (q *Queue) startWorker(ctx context.Context) {
 func (q *Queue) runTask(ctx context.Context, tsk *task.Task) {
 	logger := q.logger.WithField("taskId", tsk.ID)
 
+	ctx = task.NewContextWithProgressReporter(ctx, q)
+
 	defer func() {
 		if err := recover(); err != nil {
 			logger.WithFields(log.Fields{"error": err, "stack": string(debug.Stack())}).Error("Unhandled panic")
@@ -189,6 +313,14 @@ func (q *Queue) runTask(ctx context.Context, tsk *task.Task) {
 	tsk.AppendError(errors.New("runner not found for task"))
 }
 
+func (q *Queue) ReportProgress(ctx context.Context, tsk *task.Task) error {
+	if tsk == nil {
+		return errors.New("task is missing")
+	}
+
+	return q.store.NewTaskRepository().UpdateProgress(ctx, tsk)
+}
+
 func (q *Queue) startManager(ctx context.Context) {
 	q.waitGroup.Add(1)
 	go func() {
@@ -236,6 +368,11 @@ func (q *Queue) dispatchTasks(ctx context.Context) time.Duration {
 func (q *Queue) dispatchTask(ctx context.Context, tsk *task.Task) {
 	logger := q.logger.WithField("taskId", tsk.ID)
 
+	if tsk.IsExpiredAt(time.Now()) {
+		logger.Debug("Skipping dispatch of expired task")
+		return
+	}
+
 	repository := q.store.NewTaskRepository()
 
 	tsk.State = task.TaskStateRunning
@@ -253,7 +390,14 @@ func (q *Queue) dispatchTask(ctx context.Context, tsk *task.Task) {
 		return
 	}
 
+	availableTime := tsk.CreatedTime
+	if tsk.AvailableTime != nil && tsk.AvailableTime.After(availableTime) {
+		availableTime = *tsk.AvailableTime
+	}
+	QueueDispatchLatencySeconds.WithLabelValues(tsk.Type).Observe(tsk.RunTime.Sub(availableTime).Seconds())
+
 	q.workersAvailable--
+	QueueWorkersAvailable.Set(float64(q.workersAvailable))
 	q.dispatchChannel <- tsk
 }
 
@@ -261,17 +405,25 @@ func (q *Queue) completeTask(ctx context.Context, tsk *task.Task) {
 	logger := q.logger.WithField("taskId", tsk.ID)
 
 	q.workersAvailable++
+	QueueWorkersAvailable.Set(float64(q.workersAvailable))
 
 	repository := q.store.NewTaskRepository()
 
 	if tsk.RunTime != nil {
 		tsk.Duration = pointer.FromFloat64(time.Since(*tsk.RunTime).Truncate(time.Millisecond).Seconds())
+		QueueRunDurationSeconds.WithLabelValues(tsk.Type).Observe(*tsk.Duration)
 	}
 	q.computeState(tsk)
 
+	if tsk.IsFailed() {
+		QueueFailuresTotal.WithLabelValues(tsk.Type, errorCode(tsk)).Inc()
+	}
+
 	_, err := repository.UpdateFromState(ctx, tsk, task.TaskStateRunning)
 	if err != nil {
 		logger.WithError(err).Error("Failure to update state during complete task")
+	} else if err = repository.UpdateDependents(ctx, tsk); err != nil {
+		logger.WithError(err).Error("Failure to update dependents during complete task")
 	}
 
 	if tsk.HasError() {
@@ -279,12 +431,56 @@ func (q *Queue) completeTask(ctx context.Context, tsk *task.Task) {
 	}
 }
 
+// startReporter periodically reports the pending tasks gauge on its own goroutine, so that counting pending tasks
+// does not delay dispatch
+func (q *Queue) startReporter(ctx context.Context) {
+	q.waitGroup.Add(1)
+	go func() {
+		defer q.waitGroup.Done()
+
+		ticker := time.NewTicker(q.reportInterval)
+		defer ticker.Stop()
+
+		q.reportPending(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.reportPending(ctx)
+			}
+		}
+	}()
+}
+
+func (q *Queue) reportPending(ctx context.Context) {
+	counts, err := q.store.NewTaskRepository().CountPending(ctx)
+	if err != nil {
+		q.logger.WithError(err).Warn("Failure to count pending tasks")
+		return
+	}
+
+	pendingTypes := map[string]bool{}
+	for typ, count := range counts {
+		QueuePendingTasks.WithLabelValues(typ).Set(float64(count))
+		pendingTypes[typ] = true
+	}
+	for typ := range q.pendingTypes {
+		if !pendingTypes[typ] {
+			QueuePendingTasks.DeleteLabelValues(typ)
+		}
+	}
+	q.pendingTypes = pendingTypes
+}
+
 func (q *Queue) computeState(tsk *task.Task) {
 	switch tsk.State {
 	case task.TaskStatePending:
 		if tsk.AvailableTime == nil || time.Now().After(*tsk.AvailableTime) {
 			tsk.AppendError(errors.New("pending task requires future available time"))
 			tsk.State = task.TaskStateFailed
+		} else if tsk.IsExpiredAt(*tsk.AvailableTime) {
+			tsk.State = task.TaskStateExpired
 		}
 	case task.TaskStateRunning:
 		if tsk.HasError() {
@@ -292,13 +488,53 @@ func (q *Queue) computeState(tsk *task.Task) {
 		} else {
 			tsk.State = task.TaskStateCompleted
 		}
-	case task.TaskStateFailed, task.TaskStateCompleted:
+	case task.TaskStateFailed, task.TaskStateCompleted, task.TaskStateExpired, task.TaskStateWaiting, task.TaskStateCanceled:
 	default:
 		tsk.AppendError(errors.New("unknown state"))
 		tsk.State = task.TaskStateFailed
 	}
 }
 
+func (q *Queue) startSweeper(ctx context.Context) {
+	q.waitGroup.Add(1)
+	go func() {
+		defer q.waitGroup.Done()
+
+		ticker := time.NewTicker(q.sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.sweep(ctx)
+			}
+		}
+	}()
+}
+
+func (q *Queue) sweep(ctx context.Context) {
+	repository := q.store.NewTaskRepository()
+
+	if count, err := repository.ExpireTasks(ctx); err != nil {
+		q.logger.WithError(err).Error("Failure to expire tasks")
+	} else if count > 0 {
+		q.logger.WithField("count", count).Info("Expired tasks")
+	}
+
+	now := time.Now()
+	for _, state := range []string{task.TaskStateCompleted, task.TaskStateFailed, task.TaskStateExpired, task.TaskStateCanceled} {
+		if retention := q.retentions[state]; retention > 0 {
+			if count, err := repository.DeleteTasksBefore(ctx, state, now.Add(-retention)); err != nil {
+				q.logger.WithError(err).WithField("state", state).Error("Failure to delete tasks beyond retention")
+			} else if count > 0 {
+				q.logger.WithFields(log.Fields{"state": state, "count": count}).Info("Deleted tasks beyond retention")
+			}
+		}
+	}
+}
+
 func (q *Queue) startTimer(delay time.Duration) {
 	if delay > 0 {
 		if q.timer == nil {
@@ -317,7 +553,7 @@ func (q *Queue) stopTimer() {
 	}
 }
 
-func (q *Queue) startPendingIterator(ctx context.Context) *mongo.Cursor {
+func (q *Queue) startPendingIterator(ctx context.Context) store.TaskIterator {
 	if q.taskRepository == nil {
 		q.taskRepository = q.store.NewTaskRepository()
 	}
@@ -336,3 +572,12 @@ func (q *Queue) stopPendingIterator() {
 		q.taskRepository = nil
 	}
 }
+
+func errorCode(tsk *task.Task) string {
+	if tsk.HasError() {
+		if code := errors.Code(tsk.Error.Error); code != "" {
+			return code
+		}
+	}
+	return "unknown"
+}
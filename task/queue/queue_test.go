@@ -1,8 +1,387 @@
 package queue_test
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	configTest "github.com/tidepool-org/platform/config/test"
+	"github.com/tidepool-org/platform/errors"
+	logTest "github.com/tidepool-org/platform/log/test"
+	"github.com/tidepool-org/platform/task"
+	"github.com/tidepool-org/platform/task/queue"
+	"github.com/tidepool-org/platform/task/store"
+	"github.com/tidepool-org/platform/test"
 )
 
 var _ = Describe("Queue", func() {
+	Context("Config", func() {
+		var config *queue.Config
+
+		BeforeEach(func() {
+			config = queue.NewConfig()
+			Expect(config).ToNot(BeNil())
+		})
+
+		It("returns the default values", func() {
+			Expect(config.Workers).To(Equal(1))
+			Expect(config.Delay).To(Equal(60 * time.Second))
+			Expect(config.SweepInterval).To(Equal(time.Hour))
+			Expect(config.ReportInterval).To(Equal(30 * time.Second))
+			Expect(config.CompletedRetention).To(Equal(7 * 24 * time.Hour))
+			Expect(config.FailedRetention).To(BeZero())
+			Expect(config.ExpiredRetention).To(Equal(7 * 24 * time.Hour))
+			Expect(config.CanceledRetention).To(Equal(7 * 24 * time.Hour))
+		})
+
+		Context("Load", func() {
+			var configReporter *configTest.Reporter
+
+			BeforeEach(func() {
+				configReporter = configTest.NewReporter()
+			})
+
+			It("returns an error if the config reporter is missing", func() {
+				Expect(config.Load(nil)).To(MatchError("config reporter is missing"))
+			})
+
+			It("returns an error if the sweep interval is invalid", func() {
+				configReporter.Config["sweep_interval"] = "invalid"
+				Expect(config.Load(configReporter)).To(MatchError("sweep interval is invalid"))
+			})
+
+			It("returns an error if the report interval is invalid", func() {
+				configReporter.Config["report_interval"] = "invalid"
+				Expect(config.Load(configReporter)).To(MatchError("report interval is invalid"))
+			})
+
+			It("returns an error if the failed retention is invalid", func() {
+				configReporter.Config["failed_retention"] = "invalid"
+				Expect(config.Load(configReporter)).To(MatchError("failed retention is invalid"))
+			})
+
+			It("returns an error if the canceled retention is invalid", func() {
+				configReporter.Config["canceled_retention"] = "invalid"
+				Expect(config.Load(configReporter)).To(MatchError("canceled retention is invalid"))
+			})
+
+			It("returns successfully and sets the values", func() {
+				configReporter.Config["sweep_interval"] = "600"
+				configReporter.Config["report_interval"] = "15"
+				configReporter.Config["completed_retention"] = "3600"
+				configReporter.Config["failed_retention"] = "7200"
+				configReporter.Config["expired_retention"] = "0"
+				configReporter.Config["canceled_retention"] = "1800"
+				Expect(config.Load(configReporter)).To(Succeed())
+				Expect(config.SweepInterval).To(Equal(10 * time.Minute))
+				Expect(config.ReportInterval).To(Equal(15 * time.Second))
+				Expect(config.CompletedRetention).To(Equal(time.Hour))
+				Expect(config.FailedRetention).To(Equal(2 * time.Hour))
+				Expect(config.ExpiredRetention).To(BeZero())
+				Expect(config.CanceledRetention).To(Equal(30 * time.Minute))
+			})
+		})
+
+		Context("Validate", func() {
+			It("returns successfully", func() {
+				Expect(config.Validate()).To(Succeed())
+			})
+
+			It("returns an error if the sweep interval is not positive", func() {
+				config.SweepInterval = 0
+				Expect(config.Validate()).To(MatchError("sweep interval is invalid"))
+			})
+
+			It("returns an error if the report interval is not positive", func() {
+				config.ReportInterval = 0
+				Expect(config.Validate()).To(MatchError("report interval is invalid"))
+			})
+
+			It("returns an error if the completed retention is negative", func() {
+				config.CompletedRetention = -time.Second
+				Expect(config.Validate()).To(MatchError("completed retention is invalid"))
+			})
+
+			It("returns an error if the canceled retention is negative", func() {
+				config.CanceledRetention = -time.Second
+				Expect(config.Validate()).To(MatchError("canceled retention is invalid"))
+			})
+		})
+	})
+
+	Context("with a started queue", func() {
+		var typ string
+		var repository *taskRepository
+		var runner *taskRunner
+		var q *queue.Queue
+
+		BeforeEach(func() {
+			typ = test.RandomStringFromRangeAndCharset(4, 16, test.CharsetAlphaNumeric)
+			repository = &taskRepository{}
+			runner = &taskRunner{}
+			config := queue.NewConfig()
+			config.Delay = time.Second
+			config.ReportInterval = 50 * time.Millisecond
+			var err error
+			q, err = queue.New(config, logTest.NewLogger(), &taskStore{taskRepository: repository})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.RegisterRunner(runner)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			q.Start()
+		})
+
+		AfterEach(func() {
+			q.Stop()
+		})
+
+		Context("with pending tasks", func() {
+			var startedChannel chan *task.Task
+			var releaseChannel chan bool
+
+			BeforeEach(func() {
+				startedChannel = make(chan *task.Task, 2)
+				releaseChannel = make(chan bool)
+				runner.startedChannel = startedChannel
+				runner.releaseChannel = releaseChannel
+				repository.pending = task.Tasks{
+					{ID: task.NewID(), Type: typ, State: task.TaskStatePending, CreatedTime: time.Now().Add(-time.Minute)},
+					{ID: task.NewID(), Type: typ, State: task.TaskStatePending, CreatedTime: time.Now().Add(-time.Minute)},
+				}
+			})
+
+			It("reports the pending tasks gauge periodically and observes the dispatch latency and run duration", func() {
+				Eventually(func() float64 {
+					count, _ := pendingTasks(typ)
+					return count
+				}, 500*time.Millisecond).Should(Equal(2.0))
+
+				Eventually(startedChannel, 2*time.Second).Should(Receive())
+				Eventually(func() float64 {
+					count, _ := pendingTasks(typ)
+					return count
+				}, 500*time.Millisecond).Should(Equal(1.0))
+				Expect(testutil.ToFloat64(queue.QueueWorkersAvailable)).To(Equal(0.0))
+
+				releaseChannel <- true
+				Eventually(startedChannel, 500*time.Millisecond).Should(Receive())
+				Eventually(func() bool {
+					_, found := pendingTasks(typ)
+					return found
+				}, 500*time.Millisecond).Should(BeFalse())
+
+				releaseChannel <- true
+				Eventually(repository.Completed, 500*time.Millisecond).Should(HaveLen(2))
+				q.Stop()
+
+				dispatchLatencyCount, dispatchLatencySum := histogramSample(queue.QueueDispatchLatencySeconds, typ)
+				Expect(dispatchLatencyCount).To(Equal(uint64(2)))
+				Expect(dispatchLatencySum).To(BeNumerically(">=", 2*time.Minute.Seconds()))
+				runDurationCount, _ := histogramSample(queue.QueueRunDurationSeconds, typ)
+				Expect(runDurationCount).To(Equal(uint64(2)))
+				Expect(testutil.ToFloat64(queue.QueueWorkersAvailable)).To(Equal(1.0))
+				Expect(testutil.ToFloat64(queue.QueueFailuresTotal.WithLabelValues(typ, "unknown"))).To(BeZero())
+			})
+
+			It("counts failures by error code", func() {
+				runner.err = errors.Prepared("test-failure", "test failure", "test failure detail")
+				close(releaseChannel)
+				Eventually(repository.Completed, 3*time.Second).Should(HaveLen(2))
+				q.Stop()
+
+				Expect(testutil.ToFloat64(queue.QueueFailuresTotal.WithLabelValues(typ, "test-failure"))).To(Equal(2.0))
+				Expect(testutil.ToFloat64(queue.QueueFailuresTotal.WithLabelValues(typ, "unknown"))).To(BeZero())
+				for _, tsk := range repository.Completed() {
+					Expect(tsk.State).To(Equal(task.TaskStateFailed))
+				}
+			})
+		})
+
+		Context("with a pending task whose runner changes its state", func() {
+			BeforeEach(func() {
+				runner.startedChannel = make(chan *task.Task, 1)
+				releaseChannel := make(chan bool)
+				close(releaseChannel)
+				runner.releaseChannel = releaseChannel
+				repository.pending = task.Tasks{
+					{ID: task.NewID(), Type: typ, State: task.TaskStatePending, CreatedTime: time.Now()},
+				}
+			})
+
+			Context("when waiting", func() {
+				BeforeEach(func() {
+					runner.state = task.TaskStateWaiting
+				})
+
+				It("retains the waiting state without an error", func() {
+					Eventually(repository.Completed, 3*time.Second).Should(HaveLen(1))
+					q.Stop()
+
+					completed := repository.Completed()[0]
+					Expect(completed.State).To(Equal(task.TaskStateWaiting))
+					Expect(completed.HasError()).To(BeFalse())
+				})
+			})
+
+			Context("when canceled", func() {
+				BeforeEach(func() {
+					runner.state = task.TaskStateCanceled
+				})
+
+				It("retains the canceled state without an error", func() {
+					Eventually(repository.Completed, 3*time.Second).Should(HaveLen(1))
+					q.Stop()
+
+					completed := repository.Completed()[0]
+					Expect(completed.State).To(Equal(task.TaskStateCanceled))
+					Expect(completed.HasError()).To(BeFalse())
+				})
+			})
+		})
+	})
 })
+
+func pendingTasks(typ string) (float64, bool) {
+	registry := prometheus.NewRegistry()
+	Expect(registry.Register(queue.QueuePendingTasks)).To(Succeed())
+	families, err := registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "type" && label.GetValue() == typ {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func histogramSample(histogramVec *prometheus.HistogramVec, typ string) (uint64, float64) {
+	registry := prometheus.NewRegistry()
+	Expect(registry.Register(histogramVec)).To(Succeed())
+	families, err := registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "type" && label.GetValue() == typ {
+					return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+type taskStore struct {
+	store.Store
+	taskRepository *taskRepository
+}
+
+func (t *taskStore) NewTaskRepository() store.TaskRepository {
+	return t.taskRepository
+}
+
+type taskRepository struct {
+	store.TaskRepository
+	mutex     sync.Mutex
+	pending   task.Tasks
+	completed task.Tasks
+}
+
+func (t *taskRepository) IteratePending(ctx context.Context) (store.TaskIterator, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return &taskIterator{tasks: append(task.Tasks{}, t.pending...)}, nil
+}
+
+func (t *taskRepository) CountPending(ctx context.Context) (map[string]int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	counts := map[string]int{}
+	for _, tsk := range t.pending {
+		counts[tsk.Type]++
+	}
+	return counts, nil
+}
+
+func (t *taskRepository) UpdateFromState(ctx context.Context, tsk *task.Task, state string) (*task.Task, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	switch state {
+	case task.TaskStatePending:
+		for index, pending := range t.pending {
+			if pending.ID == tsk.ID {
+				t.pending = append(t.pending[:index], t.pending[index+1:]...)
+				return tsk, nil
+			}
+		}
+		return nil, task.AlreadyClaimedTask
+	case task.TaskStateRunning:
+		t.completed = append(t.completed, tsk)
+	}
+	return tsk, nil
+}
+
+func (t *taskRepository) UpdateDependents(ctx context.Context, tsk *task.Task) error {
+	return nil
+}
+
+func (t *taskRepository) Completed() task.Tasks {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append(task.Tasks{}, t.completed...)
+}
+
+type taskIterator struct {
+	tasks task.Tasks
+	index int
+}
+
+func (t *taskIterator) Next(ctx context.Context) bool {
+	if t.index >= len(t.tasks) {
+		return false
+	}
+	t.index++
+	return true
+}
+
+func (t *taskIterator) Decode(val interface{}) error {
+	*val.(*task.Task) = *t.tasks[t.index-1]
+	return nil
+}
+
+type taskRunner struct {
+	startedChannel chan *task.Task
+	releaseChannel chan bool
+	err            error
+	state          string
+}
+
+func (t *taskRunner) CanRunTask(tsk *task.Task) bool {
+	return true
+}
+
+func (t *taskRunner) Run(ctx context.Context, tsk *task.Task) {
+	t.startedChannel <- tsk
+	select {
+	case <-ctx.Done():
+		return
+	case <-t.releaseChannel:
+	}
+	if t.err != nil {
+		tsk.AppendError(t.err)
+	}
+	if t.state != "" {
+		tsk.State = t.state
+	}
+}
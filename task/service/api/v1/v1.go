@@ -2,11 +2,15 @@ package v1
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ant0ine/go-json-rest/rest"
 
+	dataSource "github.com/tidepool-org/platform/data/source"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/pointer"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
 	"github.com/tidepool-org/platform/request"
 	"github.com/tidepool-org/platform/service/api"
 	"github.com/tidepool-org/platform/task"
@@ -37,6 +41,16 @@ func (r *Router) Routes() []*rest.Route {
 		rest.Get("/v1/tasks/:id", api.RequireServer(r.GetTask)),
 		rest.Put("/v1/tasks/:id", api.RequireServer(r.UpdateTask)),
 		rest.Delete("/v1/tasks/:id", api.RequireServer(r.DeleteTask)),
+		rest.Get("/v1/tasks/:id/workflow", api.RequireServer(r.GetTaskWorkflow)),
+		rest.Get("/v1/dead_letters", api.RequireServer(r.ListDeadLetters)),
+		rest.Get("/v1/dead_letters/summary", api.RequireServer(r.SummarizeDeadLetters)),
+		rest.Post("/v1/dead_letters/requeue", api.RequireServer(r.RequeueDeadLetters)),
+		rest.Delete("/v1/dead_letters", api.RequireServer(r.DeleteDeadLetters)),
+		rest.Get("/v1/maintenance_windows", api.RequireServer(r.ListMaintenanceWindows)),
+		rest.Post("/v1/maintenance_windows", api.RequireServer(r.CreateMaintenanceWindow)),
+		rest.Delete("/v1/maintenance_windows/:id", api.RequireServer(r.DeleteMaintenanceWindow)),
+		rest.Post("/v1/data_sources/:id/sync", api.RequireServer(r.SyncDataSource)),
+		rest.Post("/v1/data_sources/:id/backfill", api.RequireServer(r.BackfillDataSource)),
 		rest.Get("/v1/metrics", r.PrometheusMetrics),
 	}
 }
@@ -149,3 +163,244 @@ func (r *Router) DeleteTask(res rest.ResponseWriter, req *rest.Request) {
 
 	responder.Empty(http.StatusOK)
 }
+
+func (r *Router) GetTaskWorkflow(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	workflow, err := r.TaskClient().GetTaskWorkflow(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if workflow == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return
+	}
+
+	responder.Data(http.StatusOK, workflow)
+}
+
+func (r *Router) ListDeadLetters(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	filter := task.NewDeadLetterFilter()
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, filter, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	tsks, err := r.TaskClient().ListDeadLetters(req.Context(), filter, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, tsks)
+}
+
+func (r *Router) SummarizeDeadLetters(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	filter := task.NewDeadLetterFilter()
+	if err := request.DecodeRequestQuery(req.Request, filter); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	summaries, err := r.TaskClient().SummarizeDeadLetters(req.Context(), filter)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, summaries)
+}
+
+func (r *Router) RequeueDeadLetters(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	requeue := task.NewDeadLetterRequeue()
+	if err := request.DecodeRequestBody(req.Request, requeue); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.TaskClient().RequeueDeadLetters(req.Context(), requeue)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, result)
+}
+
+func (r *Router) DeleteDeadLetters(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	filter := task.NewDeadLetterFilter()
+	if err := request.DecodeRequestQuery(req.Request, filter); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	} else if filter.Type == nil {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("type"))
+		return
+	}
+
+	result, err := r.TaskClient().DeleteDeadLetters(req.Context(), filter)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, result)
+}
+
+func (r *Router) ListMaintenanceWindows(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	filter := task.NewMaintenanceWindowFilter()
+	pagination := page.NewPagination()
+	if err := request.DecodeRequestQuery(req.Request, filter, pagination); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	maintenanceWindows, err := r.TaskClient().ListMaintenanceWindows(req.Context(), filter, pagination)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusOK, maintenanceWindows)
+}
+
+func (r *Router) CreateMaintenanceWindow(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	create := task.NewMaintenanceWindowCreate()
+	if err := request.DecodeRequestBody(req.Request, create); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	maintenanceWindow, err := r.TaskClient().CreateMaintenanceWindow(req.Context(), create)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, maintenanceWindow)
+}
+
+func (r *Router) DeleteMaintenanceWindow(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return
+	}
+
+	if err := r.TaskClient().DeleteMaintenanceWindow(req.Context(), id); err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Empty(http.StatusOK)
+}
+
+// SyncDataSource makes the fetch task of a connected data source available immediately, rather than waiting for the
+// next scheduled fetch. The fetch task must be pending, otherwise the queue would never dispatch it.
+func (r *Router) SyncDataSource(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	source, ok := r.connectedDataSource(responder, req)
+	if !ok {
+		return
+	}
+
+	filter := task.NewTaskFilter()
+	filter.Name = pointer.FromString(providerFetch.TaskName(providerFetch.TaskType(*source.ProviderType, *source.ProviderName), *source.ProviderSessionID))
+	tsks, err := r.TaskClient().ListTasks(req.Context(), filter, page.NewPagination())
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if len(tsks) == 0 {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(*filter.Name))
+		return
+	} else if tsks[0].State != task.TaskStatePending {
+		responder.Error(http.StatusConflict, errors.Newf("data source fetch task is %s, not pending", tsks[0].State))
+		return
+	}
+
+	update := task.NewTaskUpdate()
+	update.AvailableTime = pointer.FromTime(time.Now())
+	tsk, err := r.TaskClient().UpdateTask(req.Context(), tsks[0].ID, update)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	} else if tsk == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(tsks[0].ID))
+		return
+	}
+
+	responder.Data(http.StatusOK, tsk)
+}
+
+// BackfillDataSource creates a task to fetch a bounded window of historical data again for a connected data source
+func (r *Router) BackfillDataSource(res rest.ResponseWriter, req *rest.Request) {
+	responder := request.MustNewResponder(res, req)
+
+	backfill := providerFetch.NewBackfill()
+	if err := request.DecodeRequestBody(req.Request, backfill); err != nil {
+		responder.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	source, ok := r.connectedDataSource(responder, req)
+	if !ok {
+		return
+	}
+
+	create, err := providerFetch.NewBackfillTaskCreate(providerFetch.TaskType(*source.ProviderType, *source.ProviderName), *source.ProviderSessionID, *source.ID, backfill)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	tsk, err := r.TaskClient().CreateTask(req.Context(), create)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	responder.Data(http.StatusCreated, tsk)
+}
+
+func (r *Router) connectedDataSource(responder *request.Responder, req *rest.Request) (*dataSource.Source, bool) {
+	id := req.PathParam("id")
+	if id == "" {
+		responder.Error(http.StatusBadRequest, request.ErrorParameterMissing("id"))
+		return nil, false
+	}
+
+	source, err := r.DataSourceClient().Get(req.Context(), id)
+	if err != nil {
+		responder.Error(http.StatusInternalServerError, err)
+		return nil, false
+	} else if source == nil {
+		responder.Error(http.StatusNotFound, request.ErrorResourceNotFoundWithID(id))
+		return nil, false
+	} else if source.ProviderType == nil || source.ProviderName == nil || source.ProviderSessionID == nil || pointer.ToString(source.State) != dataSource.StateConnected {
+		responder.Error(http.StatusConflict, errors.New("data source is not connected"))
+		return nil, false
+	}
+
+	return source, true
+}
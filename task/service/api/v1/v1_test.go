@@ -3,12 +3,25 @@ package v1_test
 import (
 	"net/http"
 
+	"github.com/ant0ine/go-json-rest/rest"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
+	authTest "github.com/tidepool-org/platform/auth/test"
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
+	"github.com/tidepool-org/platform/log"
+	logNull "github.com/tidepool-org/platform/log/null"
+	"github.com/tidepool-org/platform/pointer"
+	platformRequest "github.com/tidepool-org/platform/request"
+	"github.com/tidepool-org/platform/task"
 	taskServiceApiV1 "github.com/tidepool-org/platform/task/service/api/v1"
 	taskServiceTest "github.com/tidepool-org/platform/task/service/test"
+	taskTest "github.com/tidepool-org/platform/task/test"
+	"github.com/tidepool-org/platform/test"
+	testRest "github.com/tidepool-org/platform/test/rest"
 )
 
 var _ = Describe("V1", func() {
@@ -50,9 +63,74 @@ var _ = Describe("V1", func() {
 					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/tasks/:id")})),
 					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodPut), "PathExp": Equal("/v1/tasks/:id")})),
 					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodDelete), "PathExp": Equal("/v1/tasks/:id")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/tasks/:id/workflow")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/dead_letters")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/dead_letters/summary")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodPost), "PathExp": Equal("/v1/dead_letters/requeue")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodDelete), "PathExp": Equal("/v1/dead_letters")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/maintenance_windows")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodPost), "PathExp": Equal("/v1/maintenance_windows")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodDelete), "PathExp": Equal("/v1/maintenance_windows/:id")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodPost), "PathExp": Equal("/v1/data_sources/:id/sync")})),
+					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodPost), "PathExp": Equal("/v1/data_sources/:id/backfill")})),
 					PointTo(MatchFields(IgnoreExtras, Fields{"HttpMethod": Equal(http.MethodGet), "PathExp": Equal("/v1/metrics")})),
 				))
 			})
 		})
+
+		Context("SyncDataSource", func() {
+			var response *testRest.ResponseWriter
+			var request *rest.Request
+			var fetchTask *task.Task
+
+			BeforeEach(func() {
+				source := &dataSource.Source{
+					ID:                pointer.FromString(dataSourceTest.RandomID()),
+					ProviderType:      pointer.FromString("oauth"),
+					ProviderName:      pointer.FromString("dexcom"),
+					ProviderSessionID: pointer.FromString(test.RandomStringFromRangeAndCharset(32, 32, test.CharsetHexidecimalLowercase)),
+					State:             pointer.FromString(dataSource.StateConnected),
+				}
+				fetchTask = &task.Task{ID: task.NewID(), Type: "org.tidepool.oauth.dexcom.fetch", State: task.TaskStatePending}
+				service.DataSourceClientImpl.GetOutputs = []dataSourceTest.GetOutput{{Source: source}}
+				service.TaskClientImpl.ListTasksOutputs = []taskTest.ListTasksOutput{{Tasks: task.Tasks{fetchTask}}}
+				response = testRest.NewResponseWriter()
+				response.HeaderOutput = &http.Header{}
+				response.WriteOutputs = []testRest.WriteOutput{{BytesWritten: 0, Error: nil}}
+				request = testRest.NewRequest()
+				ctx := log.NewContextWithLogger(request.Context(), logNull.NewLogger())
+				ctx = platformRequest.NewContextWithDetails(ctx, platformRequest.NewDetails(platformRequest.MethodServiceSecret, "", authTest.NewServiceSecret()))
+				request.Request = request.WithContext(ctx)
+				request.PathParams["id"] = *source.ID
+			})
+
+			AfterEach(func() {
+				service.TaskClientImpl.Expectations()
+				response.AssertOutputsEmpty()
+			})
+
+			It("makes the pending fetch task available immediately", func() {
+				service.TaskClientImpl.UpdateTaskOutputs = []taskTest.UpdateTaskOutput{{Task: fetchTask}}
+				router.SyncDataSource(response, request)
+				Expect(response.WriteHeaderInputs).To(Equal([]int{http.StatusOK}))
+				Expect(service.TaskClientImpl.UpdateTaskInputs).To(HaveLen(1))
+				Expect(service.TaskClientImpl.UpdateTaskInputs[0].ID).To(Equal(fetchTask.ID))
+				Expect(service.TaskClientImpl.UpdateTaskInputs[0].Update.AvailableTime).ToNot(BeNil())
+			})
+
+			DescribeTable("returns a conflict without updating the fetch task when it is not pending",
+				func(state string) {
+					fetchTask.State = state
+					router.SyncDataSource(response, request)
+					Expect(response.WriteHeaderInputs).To(Equal([]int{http.StatusConflict}))
+					Expect(response.WriteInputs).To(HaveLen(1))
+					Expect(string(response.WriteInputs[0])).To(ContainSubstring("data source fetch task is " + state + ", not pending"))
+					Expect(service.TaskClientImpl.UpdateTaskInvocations).To(BeZero())
+				},
+				Entry("running", task.TaskStateRunning),
+				Entry("failed", task.TaskStateFailed),
+				Entry("expired", task.TaskStateExpired),
+			)
+		})
 	})
 })
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 
+	dataSource "github.com/tidepool-org/platform/data/source"
 	"github.com/tidepool-org/platform/service"
 	"github.com/tidepool-org/platform/task"
 	"github.com/tidepool-org/platform/task/store"
@@ -13,6 +14,7 @@ type Service interface {
 
 	TaskStore() store.Store
 	TaskClient() task.Client
+	DataSourceClient() dataSource.Client
 
 	Status(context.Context) *Status
 }
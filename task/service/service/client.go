@@ -47,3 +47,43 @@ func (c *Client) DeleteTask(ctx context.Context, id string) error {
 	repository := c.taskStore.NewTaskRepository()
 	return repository.DeleteTask(ctx, id)
 }
+
+func (c *Client) GetTaskWorkflow(ctx context.Context, id string) (*task.TaskWorkflow, error) {
+	repository := c.taskStore.NewTaskRepository()
+	return repository.GetTaskWorkflow(ctx, id)
+}
+
+func (c *Client) ListDeadLetters(ctx context.Context, filter *task.DeadLetterFilter, pagination *page.Pagination) (task.Tasks, error) {
+	repository := c.taskStore.NewTaskRepository()
+	return repository.ListDeadLetters(ctx, filter, pagination)
+}
+
+func (c *Client) SummarizeDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (task.DeadLetterSummaries, error) {
+	repository := c.taskStore.NewTaskRepository()
+	return repository.SummarizeDeadLetters(ctx, filter)
+}
+
+func (c *Client) RequeueDeadLetters(ctx context.Context, requeue *task.DeadLetterRequeue) (*task.DeadLetterResult, error) {
+	repository := c.taskStore.NewTaskRepository()
+	return repository.RequeueDeadLetters(ctx, requeue)
+}
+
+func (c *Client) DeleteDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (*task.DeadLetterResult, error) {
+	repository := c.taskStore.NewTaskRepository()
+	return repository.DeleteDeadLetters(ctx, filter)
+}
+
+func (c *Client) ListMaintenanceWindows(ctx context.Context, filter *task.MaintenanceWindowFilter, pagination *page.Pagination) (task.MaintenanceWindows, error) {
+	repository := c.taskStore.NewMaintenanceWindowRepository()
+	return repository.ListMaintenanceWindows(ctx, filter, pagination)
+}
+
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, create *task.MaintenanceWindowCreate) (*task.MaintenanceWindow, error) {
+	repository := c.taskStore.NewMaintenanceWindowRepository()
+	return repository.CreateMaintenanceWindow(ctx, create)
+}
+
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	repository := c.taskStore.NewMaintenanceWindowRepository()
+	return repository.DeleteMaintenanceWindow(ctx, id)
+}
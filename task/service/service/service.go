@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/tidepool-org/platform/application"
-	"github.com/tidepool-org/platform/client"
 	dataClient "github.com/tidepool-org/platform/data/client"
 	dataSource "github.com/tidepool-org/platform/data/source"
 	dataSourceClient "github.com/tidepool-org/platform/data/source/client"
@@ -14,9 +13,11 @@ import (
 	dexcomProvider "github.com/tidepool-org/platform/dexcom/provider"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/platform"
+	providerFetch "github.com/tidepool-org/platform/provider/fetch"
 	serviceService "github.com/tidepool-org/platform/service/service"
 	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
 	"github.com/tidepool-org/platform/task"
+	"github.com/tidepool-org/platform/task/encryption"
 	"github.com/tidepool-org/platform/task/queue"
 	"github.com/tidepool-org/platform/task/service"
 	"github.com/tidepool-org/platform/task/service/api"
@@ -88,6 +89,10 @@ func (s *Service) TaskClient() task.Client {
 	return s.taskClient
 }
 
+func (s *Service) DataSourceClient() dataSource.Client {
+	return s.dataSourceClient
+}
+
 func (s *Service) Status(ctx context.Context) *service.Status {
 	return &service.Status{
 		Version: s.VersionReporter().Long(),
@@ -127,6 +132,11 @@ func (s *Service) initializeTaskStore() error {
 		return errors.Wrap(err, "unable to ensure task store contains summary backfill task")
 	}
 
+	err = s.taskStore.EnsureEncryptionRotationTask()
+	if err != nil {
+		return errors.Wrap(err, "unable to ensure task store contains encryption rotation task")
+	}
+
 	return nil
 }
 
@@ -222,7 +232,7 @@ func (s *Service) initializeDexcomClient() error {
 	} else {
 		s.Logger().Debug("Loading dexcom client config")
 
-		cfg := client.NewConfig()
+		cfg := dexcomClient.NewConfig()
 		cfg.UserAgent = s.UserAgent()
 		if err = cfg.Load(s.ConfigReporter().WithScopes("dexcom", "client")); err != nil {
 			return errors.Wrap(err, "unable to load dexcom client config")
@@ -265,9 +275,31 @@ func (s *Service) initializeTaskQueue() error {
 	s.taskQueue = taskQueue
 
 	if s.dexcomClient != nil {
+		s.Logger().Debug("Loading dexcom fetch config")
+
+		fetchCfg := providerFetch.NewConfig()
+		fetchCfg.MaintenanceWindows = dexcomFetch.DefaultMaintenanceWindows
+		if err = fetchCfg.Load(s.ConfigReporter().WithScopes("dexcom", "fetch")); err != nil {
+			return errors.Wrap(err, "unable to load dexcom fetch config")
+		}
+
+		s.Logger().Debug("Creating dexcom maintenance")
+
+		maintenance, maintenanceErr := providerFetch.NewMaintenance(dexcomProvider.ProviderName, fetchCfg, s.TaskClient())
+		if maintenanceErr != nil {
+			return errors.Wrap(maintenanceErr, "unable to create dexcom maintenance")
+		}
+
+		s.Logger().Debug("Creating dexcom health policy")
+
+		healthPolicy, healthPolicyErr := dataSource.NewHealthPolicy(fetchCfg.AuthenticationFailureCountMaximum, fetchCfg.FailureDurationMaximum)
+		if healthPolicyErr != nil {
+			return errors.Wrap(healthPolicyErr, "unable to create dexcom health policy")
+		}
+
 		s.Logger().Debug("Creating dexcom fetch runner")
 
-		rnnr, rnnrErr := dexcomFetch.NewRunner(s.Logger(), s.VersionReporter(), s.AuthClient(), s.dataClient, s.dataSourceClient, s.dexcomClient)
+		rnnr, rnnrErr := dexcomFetch.NewRunner(s.Logger(), s.VersionReporter(), s.AuthClient(), s.dataClient, s.dataSourceClient, s.dexcomClient, maintenance, healthPolicy)
 		if rnnrErr != nil {
 			return errors.Wrap(rnnrErr, "unable to create dexcom fetch runner")
 		}
@@ -293,6 +325,15 @@ func (s *Service) initializeTaskQueue() error {
 
 	taskQueue.RegisterRunner(summaryBackfillRnnr)
 
+	s.Logger().Debug("Creating encryption rotation runner")
+
+	encryptionRotationRnnr, encryptionRotationRnnrErr := encryption.NewRotationRunner(s.Logger(), s.AuthClient())
+	if encryptionRotationRnnrErr != nil {
+		return errors.Wrap(encryptionRotationRnnrErr, "unable to create encryption rotation runner")
+	}
+
+	taskQueue.RegisterRunner(encryptionRotationRnnr)
+
 	s.Logger().Debug("Starting task queue")
 
 	s.taskQueue.Start()
@@ -3,6 +3,8 @@ package test
 import (
 	"context"
 
+	dataSource "github.com/tidepool-org/platform/data/source"
+	dataSourceTest "github.com/tidepool-org/platform/data/source/test"
 	serviceTest "github.com/tidepool-org/platform/service/test"
 	"github.com/tidepool-org/platform/task"
 	taskService "github.com/tidepool-org/platform/task/service"
@@ -13,19 +15,22 @@ import (
 
 type Service struct {
 	*serviceTest.Service
-	TaskStoreInvocations  int
-	TaskStoreImpl         *taskStoreTest.Store
-	TaskClientInvocations int
-	TaskClientImpl        *taskTest.Client
-	StatusInvocations     int
-	StatusOutputs         []*taskService.Status
+	TaskStoreInvocations        int
+	TaskStoreImpl               *taskStoreTest.Store
+	TaskClientInvocations       int
+	TaskClientImpl              *taskTest.Client
+	DataSourceClientInvocations int
+	DataSourceClientImpl        *dataSourceTest.Client
+	StatusInvocations           int
+	StatusOutputs               []*taskService.Status
 }
 
 func NewService() *Service {
 	return &Service{
-		Service:        serviceTest.NewService(),
-		TaskStoreImpl:  taskStoreTest.NewStore(),
-		TaskClientImpl: taskTest.NewClient(),
+		Service:              serviceTest.NewService(),
+		TaskStoreImpl:        taskStoreTest.NewStore(),
+		TaskClientImpl:       taskTest.NewClient(),
+		DataSourceClientImpl: dataSourceTest.NewClient(),
 	}
 }
 
@@ -41,6 +46,12 @@ func (s *Service) TaskClient() task.Client {
 	return s.TaskClientImpl
 }
 
+func (s *Service) DataSourceClient() dataSource.Client {
+	s.DataSourceClientInvocations++
+
+	return s.DataSourceClientImpl
+}
+
 func (s *Service) Status(ctx context.Context) *taskService.Status {
 	s.StatusInvocations++
 
@@ -0,0 +1,130 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/page"
+	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+type MaintenanceWindowRepository struct {
+	*storeStructuredMongo.Repository
+}
+
+func (m *MaintenanceWindowRepository) EnsureIndexes() error {
+	return m.CreateAllIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "provider", Value: 1}, {Key: "endTime", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+	})
+}
+
+func (m *MaintenanceWindowRepository) ListMaintenanceWindows(ctx context.Context, filter *task.MaintenanceWindowFilter, pagination *page.Pagination) (task.MaintenanceWindows, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewMaintenanceWindowFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"filter": filter, "pagination": pagination})
+
+	maintenanceWindows := task.MaintenanceWindows{}
+	selector := bson.M{}
+	if filter.Provider != nil {
+		selector["provider"] = *filter.Provider
+	}
+	if filter.Expired != nil {
+		if *filter.Expired {
+			selector["endTime"] = bson.M{"$lte": now}
+		} else {
+			selector["endTime"] = bson.M{"$gt": now}
+		}
+	}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"startTime": -1})
+	cursor, err := m.Find(ctx, selector, opts)
+	logger.WithFields(log.Fields{"duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("ListMaintenanceWindows")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list maintenance windows")
+	}
+
+	if err = cursor.All(ctx, &maintenanceWindows); err != nil {
+		return nil, errors.Wrap(err, "unable to decode maintenance windows")
+	}
+
+	if maintenanceWindows == nil {
+		maintenanceWindows = task.MaintenanceWindows{}
+	}
+
+	return maintenanceWindows, nil
+}
+
+func (m *MaintenanceWindowRepository) CreateMaintenanceWindow(ctx context.Context, create *task.MaintenanceWindowCreate) (*task.MaintenanceWindow, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	maintenanceWindow, err := task.NewMaintenanceWindow(create)
+	if err != nil {
+		return nil, err
+	} else if err = structureValidator.New().Validate(maintenanceWindow); err != nil {
+		return nil, errors.Wrap(err, "maintenance window is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"create": create})
+
+	_, err = m.InsertOne(ctx, maintenanceWindow)
+	logger.WithFields(log.Fields{"id": maintenanceWindow.ID, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("CreateMaintenanceWindow")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create maintenance window")
+	}
+
+	return maintenanceWindow, nil
+}
+
+func (m *MaintenanceWindowRepository) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if id == "" {
+		return errors.New("id is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", id)
+
+	changeInfo, err := m.DeleteMany(ctx, bson.M{"id": id})
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteMaintenanceWindow")
+	if err != nil {
+		return errors.Wrap(err, "unable to delete maintenance window")
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,6 +19,7 @@ import (
 	storeStructuredMongo "github.com/tidepool-org/platform/store/structured/mongo"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 	"github.com/tidepool-org/platform/task"
+	"github.com/tidepool-org/platform/task/encryption"
 	"github.com/tidepool-org/platform/task/store"
 	"github.com/tidepool-org/platform/task/summary"
 )
@@ -54,9 +56,23 @@ func (s *Store) TaskRepository() *TaskRepository {
 	}
 }
 
+func (s *Store) NewMaintenanceWindowRepository() store.MaintenanceWindowRepository {
+	return s.MaintenanceWindowRepository()
+}
+
+func (s *Store) MaintenanceWindowRepository() *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{
+		s.Store.GetRepository("maintenanceWindows"),
+	}
+}
+
 func (s *Store) EnsureIndexes() error {
 	repository := s.TaskRepository()
-	return repository.EnsureIndexes()
+	if err := repository.EnsureIndexes(); err != nil {
+		return err
+	}
+	maintenanceWindowRepository := s.MaintenanceWindowRepository()
+	return maintenanceWindowRepository.EnsureIndexes()
 }
 
 func (s *Store) EnsureSummaryUpdateTask() error {
@@ -69,6 +85,11 @@ func (s *Store) EnsureSummaryBackfillTask() error {
 	return repository.EnsureSummaryBackfillTask()
 }
 
+func (s *Store) EnsureEncryptionRotationTask() error {
+	repository := s.TaskRepository()
+	return repository.EnsureEncryptionRotationTask()
+}
+
 type TaskRepository struct {
 	*storeStructuredMongo.Repository
 }
@@ -108,6 +129,17 @@ func (t *TaskRepository) EnsureIndexes() error {
 			Options: options.Index().
 				SetBackground(true),
 		},
+		{
+			Keys: bson.D{{Key: "state", Value: 1}, {Key: "modifiedTime", Value: 1}},
+			Options: options.Index().
+				SetBackground(true),
+		},
+		{
+			Keys: bson.D{{Key: "dependencies", Value: 1}},
+			Options: options.Index().
+				SetSparse(true).
+				SetBackground(true),
+		},
 	})
 }
 
@@ -145,6 +177,40 @@ func (t *TaskRepository) EnsureSummaryUpdateTask() error {
 	return summaryTask.Err()
 }
 
+func (t *TaskRepository) EnsureEncryptionRotationTask() error {
+	create := encryption.NewDefaultRotationTaskCreate()
+
+	tsk, err := task.NewTask(create)
+	if err != nil {
+		return err
+	} else if err = structureValidator.New().Validate(tsk); err != nil {
+		return errors.Wrap(err, "task is invalid")
+	}
+
+	upsert := true
+	after := options.After
+	opts := options.FindOneAndUpdateOptions{
+		ReturnDocument: &after,
+		Upsert:         &upsert,
+	}
+
+	rotationTask := t.FindOneAndUpdate(context.Background(),
+		bson.M{"name": tsk.Name},
+		bson.M{"$setOnInsert": tsk},
+		&opts,
+	)
+
+	if rotationTask.Err() != nil {
+		if rotationTask.Err() != mongo.ErrNoDocuments {
+			return errors.Wrap(rotationTask.Err(), "unable to create encryption rotation task")
+		}
+	}
+
+	TasksStateTotal.WithLabelValues(task.TaskStatePending, create.Type).Inc()
+
+	return rotationTask.Err()
+}
+
 func (t *TaskRepository) EnsureSummaryBackfillTask() error {
 	create := summary.NewDefaultBackfillTaskCreate()
 
@@ -208,6 +274,9 @@ func (t *TaskRepository) ListTasks(ctx context.Context, filter *task.TaskFilter,
 	if filter.State != nil {
 		selector["state"] = *filter.State
 	}
+	if filter.Phase != nil {
+		selector["progress.phase"] = *filter.Phase
+	}
 	opts := storeStructuredMongo.FindWithPagination(pagination).
 		SetSort(bson.M{"createdTime": -1})
 	cursor, err := t.Find(ctx, selector, opts)
@@ -242,13 +311,30 @@ func (t *TaskRepository) CreateTask(ctx context.Context, create *task.TaskCreate
 	now := time.Now()
 	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"create": create})
 
+	if tsk.HasDependencies() {
+		var count int64
+		count, err = t.CountDocuments(ctx, bson.M{"id": bson.M{"$in": tsk.Dependencies}})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to count dependencies")
+		} else if count != int64(len(uniqueStrings(tsk.Dependencies))) {
+			return nil, errors.New("dependencies not found")
+		}
+	}
+
 	_, err = t.InsertOne(ctx, tsk)
 	logger.WithFields(log.Fields{"id": tsk.ID, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("CreateTask")
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create task")
 	}
 
-	TasksStateTotal.WithLabelValues(task.TaskStatePending, create.Type).Inc()
+	TasksStateTotal.WithLabelValues(tsk.State, create.Type).Inc()
+
+	if tsk.IsWaiting() {
+		if err = t.resolveDependencies(ctx, tsk); err != nil {
+			return nil, err
+		}
+	}
+
 	return tsk, nil
 }
 
@@ -333,7 +419,51 @@ func (t *TaskRepository) DeleteTask(ctx context.Context, id string) error {
 		return errors.Wrap(err, "unable to delete task")
 	}
 
-	return nil
+	return t.updateDependents(ctx, id)
+}
+
+func (t *TaskRepository) GetTaskWorkflow(ctx context.Context, id string) (*task.TaskWorkflow, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if id == "" {
+		return nil, errors.New("id is missing")
+	}
+
+	tsk, err := t.GetTask(ctx, id)
+	if err != nil || tsk == nil {
+		return nil, err
+	}
+
+	return t.getTaskWorkflow(ctx, tsk, 1)
+}
+
+func (t *TaskRepository) getTaskWorkflow(ctx context.Context, tsk *task.Task, depth int) (*task.TaskWorkflow, error) {
+	workflow := &task.TaskWorkflow{Task: tsk}
+	if depth >= task.TaskWorkflowDepthMaximum {
+		return workflow, nil
+	}
+
+	opts := options.Find().SetSort(bson.M{"createdTime": 1})
+	cursor, err := t.Find(ctx, bson.M{"dependencies": tsk.ID}, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to find dependent tasks")
+	}
+
+	dependents := task.Tasks{}
+	if err = cursor.All(ctx, &dependents); err != nil {
+		return nil, errors.Wrap(err, "unable to decode dependent tasks")
+	}
+
+	for _, dependent := range dependents {
+		dependentWorkflow, err := t.getTaskWorkflow(ctx, dependent, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		workflow.Dependents = append(workflow.Dependents, dependentWorkflow)
+	}
+
+	return workflow, nil
 }
 
 // TODO: Consider using an "update only specific fields" approach, as above
@@ -368,10 +498,454 @@ func (t *TaskRepository) UpdateFromState(ctx context.Context, tsk *task.Task, st
 	return tsk, nil
 }
 
-func (t *TaskRepository) IteratePending(ctx context.Context) (*mongo.Cursor, error) {
+func (t *TaskRepository) UpdateDependents(ctx context.Context, tsk *task.Task) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if tsk == nil {
+		return errors.New("task is missing")
+	}
+
+	if !tsk.IsCompleted() && !tsk.IsRepeating() && !tsk.IsFailed() && !tsk.IsCanceled() && !tsk.IsExpired() {
+		return nil
+	}
+
+	return t.updateDependents(ctx, tsk.ID)
+}
+
+func (t *TaskRepository) updateDependents(ctx context.Context, id string) error {
+	selector := bson.M{
+		"dependencies": id,
+		"state":        task.TaskStateWaiting,
+	}
+	cursor, err := t.Find(ctx, selector)
+	if err != nil {
+		return errors.Wrap(err, "unable to find dependent tasks")
+	}
+
+	dependents := task.Tasks{}
+	if err = cursor.All(ctx, &dependents); err != nil {
+		return errors.Wrap(err, "unable to decode dependent tasks")
+	}
+
+	for _, dependent := range dependents {
+		if err = t.resolveDependencies(ctx, dependent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TaskRepository) resolveDependencies(ctx context.Context, tsk *task.Task) error {
+	cursor, err := t.Find(ctx, bson.M{"id": bson.M{"$in": tsk.Dependencies}})
+	if err != nil {
+		return errors.Wrap(err, "unable to find dependencies")
+	}
+
+	dependencies := task.Tasks{}
+	if err = cursor.All(ctx, &dependencies); err != nil {
+		return errors.Wrap(err, "unable to decode dependencies")
+	}
+
+	state := task.ResolveDependencies(tsk, dependencies)
+	if state == "" || state == task.TaskStateWaiting {
+		return nil
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"id": tsk.ID, "state": state})
+
+	selector := bson.M{
+		"id":    tsk.ID,
+		"state": task.TaskStateWaiting,
+	}
+	set := bson.M{
+		"state":        state,
+		"modifiedTime": now.Truncate(time.Millisecond),
+	}
+	result, err := t.UpdateOne(ctx, selector, t.ConstructUpdate(set, bson.M{}))
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("ResolveDependencies")
+	if err != nil {
+		return errors.Wrap(err, "unable to resolve dependencies")
+	} else if result.ModifiedCount != 1 {
+		return nil
+	}
+
+	tsk.State = state
+	TasksStateTotal.WithLabelValues(tsk.State, tsk.Type).Inc()
+
+	if tsk.IsCanceled() {
+		return t.UpdateDependents(ctx, tsk)
+	}
+	return nil
+}
+
+func (t *TaskRepository) UpdateProgress(ctx context.Context, tsk *task.Task) error {
+	if ctx == nil {
+		return errors.New("context is missing")
+	}
+	if tsk == nil {
+		return errors.New("task is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("id", tsk.ID)
+
+	tsk.ModifiedTime = pointer.FromTime(now.Truncate(time.Millisecond))
+
+	selector := bson.M{
+		"id":    tsk.ID,
+		"state": task.TaskStateRunning,
+	}
+	set := bson.M{
+		"modifiedTime": tsk.ModifiedTime,
+	}
+	unset := bson.M{}
+	if tsk.Progress != nil {
+		set["progress"] = tsk.Progress
+	} else {
+		unset["progress"] = true
+	}
+	if tsk.Log != nil {
+		set["log"] = tsk.Log
+	} else {
+		unset["log"] = true
+	}
+	changeInfo, err := t.UpdateOne(ctx, selector, t.ConstructUpdate(set, unset))
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("UpdateProgress")
+	if err != nil {
+		return errors.Wrap(err, "unable to update progress")
+	}
+
+	return nil
+}
+
+func (t *TaskRepository) IteratePending(ctx context.Context) (store.TaskIterator, error) {
+	opts := options.Find().SetSort(bson.M{"priority": -1})
+	cursor, err := t.Find(ctx, pendingSelector(time.Now()), opts)
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (t *TaskRepository) CountPending(ctx context.Context) (map[string]int, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx)
+
+	pipeline := []bson.M{
+		{"$match": pendingSelector(now)},
+		{"$group": bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}},
+	}
+	cursor, err := t.Aggregate(ctx, pipeline)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("CountPending")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to count pending tasks")
+	}
+
+	var results []struct {
+		Type  string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, errors.Wrap(err, "unable to decode pending task counts")
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Type] = result.Count
+	}
+	return counts, nil
+}
+
+func (t *TaskRepository) ListDeadLetters(ctx context.Context, filter *task.DeadLetterFilter, pagination *page.Pagination) (task.Tasks, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewDeadLetterFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+	if pagination == nil {
+		pagination = page.NewPagination()
+	} else if err := structureValidator.New().Validate(pagination); err != nil {
+		return nil, errors.Wrap(err, "pagination is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"filter": filter, "pagination": pagination})
+
+	tasks := task.Tasks{}
+	opts := storeStructuredMongo.FindWithPagination(pagination).
+		SetSort(bson.M{"modifiedTime": -1})
+	cursor, err := t.Find(ctx, deadLetterSelector(filter.Type, filter.Code), opts)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("ListDeadLetters")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list dead letters")
+	}
+
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, errors.Wrap(err, "unable to decode dead letters")
+	}
+
+	if tasks == nil {
+		tasks = task.Tasks{}
+	}
+
+	return tasks, nil
+}
+
+func (t *TaskRepository) SummarizeDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (task.DeadLetterSummaries, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		filter = task.NewDeadLetterFilter()
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	}
+
 	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("filter", filter)
+
+	// The code of a task with multiple errors is the code of the first error
+	code := bson.M{
+		"$cond": bson.A{
+			bson.M{"$isArray": "$error"},
+			bson.M{"$arrayElemAt": bson.A{"$error.code", 0}},
+			"$error.code",
+		},
+	}
+	pipeline := []bson.M{
+		{"$match": deadLetterSelector(filter.Type, filter.Code)},
+		{"$group": bson.M{
+			"_id":                bson.M{"type": "$type", "code": code},
+			"count":              bson.M{"$sum": 1},
+			"earliestFailedTime": bson.M{"$min": "$modifiedTime"},
+			"latestFailedTime":   bson.M{"$max": "$modifiedTime"},
+		}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id.type", Value: 1}}},
+	}
+	cursor, err := t.Aggregate(ctx, pipeline)
+	logger.WithField("duration", time.Since(now)/time.Microsecond).WithError(err).Debug("SummarizeDeadLetters")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to summarize dead letters")
+	}
+
+	var results []struct {
+		ID struct {
+			Type string `bson:"type"`
+			Code string `bson:"code"`
+		} `bson:"_id"`
+		Count              int       `bson:"count"`
+		EarliestFailedTime time.Time `bson:"earliestFailedTime"`
+		LatestFailedTime   time.Time `bson:"latestFailedTime"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, errors.Wrap(err, "unable to decode dead letter summaries")
+	}
+
+	summaries := task.DeadLetterSummaries{}
+	for _, result := range results {
+		summaries = append(summaries, &task.DeadLetterSummary{
+			Type:               result.ID.Type,
+			Code:               result.ID.Code,
+			Count:              result.Count,
+			EarliestFailedTime: result.EarliestFailedTime,
+			LatestFailedTime:   result.LatestFailedTime,
+		})
+	}
+	return summaries, nil
+}
+
+func (t *TaskRepository) RequeueDeadLetters(ctx context.Context, requeue *task.DeadLetterRequeue) (*task.DeadLetterResult, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if requeue == nil {
+		return nil, errors.New("requeue is missing")
+	} else if err := structureValidator.New().Validate(requeue); err != nil {
+		return nil, errors.Wrap(err, "requeue is invalid")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("requeue", requeue)
+
+	selector := deadLetterSelector(requeue.Type, requeue.Code)
+	if requeue.IDs != nil {
+		selector["id"] = bson.M{"$in": *requeue.IDs}
+	}
+	opts := options.Find().SetProjection(bson.M{"id": 1, "type": 1})
+	cursor, err := t.Find(ctx, selector, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to find dead letters")
+	}
+
+	var tasks task.Tasks
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, errors.Wrap(err, "unable to decode dead letters")
+	}
+
+	var spreadDuration time.Duration
+	if requeue.SpreadDuration != nil {
+		spreadDuration = time.Duration(*requeue.SpreadDuration) * time.Second
+	}
+
+	count := 0
+	for _, tsk := range tasks {
+		availableTime := now
+		if spreadDuration > 0 {
+			availableTime = availableTime.Add(time.Duration(rand.Int63n(int64(spreadDuration))))
+		}
+
+		set := bson.M{
+			"state":         task.TaskStatePending,
+			"availableTime": availableTime,
+			"modifiedTime":  now.Truncate(time.Millisecond),
+		}
+		unset := bson.M{
+			"error":    true,
+			"runTime":  true,
+			"duration": true,
+			"progress": true,
+			"log":      true,
+		}
+		result, err := t.UpdateOne(ctx, bson.M{"id": tsk.ID, "state": task.TaskStateFailed}, t.ConstructUpdate(set, unset))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to requeue dead letter")
+		} else if result.ModifiedCount == 1 {
+			TasksStateTotal.WithLabelValues(task.TaskStatePending, tsk.Type).Inc()
+			count++
+		}
+	}
+
+	logger.WithFields(log.Fields{"count": count, "duration": time.Since(now) / time.Microsecond}).Debug("RequeueDeadLetters")
+
+	return task.NewDeadLetterResult(count), nil
+}
+
+func (t *TaskRepository) DeleteDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (*task.DeadLetterResult, error) {
+	if ctx == nil {
+		return nil, errors.New("context is missing")
+	}
+	if filter == nil {
+		return nil, errors.New("filter is missing")
+	} else if err := structureValidator.New().Validate(filter); err != nil {
+		return nil, errors.Wrap(err, "filter is invalid")
+	} else if filter.Type == nil {
+		return nil, errors.New("filter type is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithField("filter", filter)
+
+	changeInfo, err := t.DeleteMany(ctx, deadLetterSelector(filter.Type, filter.Code))
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteDeadLetters")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to delete dead letters")
+	}
+
+	return task.NewDeadLetterResult(int(changeInfo.DeletedCount)), nil
+}
+
+func (t *TaskRepository) ExpireTasks(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx)
 
 	selector := bson.M{
+		"state":          bson.M{"$in": []string{task.TaskStateWaiting, task.TaskStatePending}},
+		"expirationTime": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetProjection(bson.M{"id": 1, "type": 1, "state": 1})
+	cursor, err := t.Find(ctx, selector, opts)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to find expired tasks")
+	}
+
+	var tasks task.Tasks
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return 0, errors.Wrap(err, "unable to decode expired tasks")
+	}
+
+	count := 0
+	for _, tsk := range tasks {
+		set := bson.M{
+			"state":        task.TaskStateExpired,
+			"modifiedTime": now.Truncate(time.Millisecond),
+		}
+		result, err := t.UpdateOne(ctx, bson.M{"id": tsk.ID, "state": tsk.State}, t.ConstructUpdate(set, bson.M{}))
+		if err != nil {
+			return count, errors.Wrap(err, "unable to expire task")
+		} else if result.ModifiedCount != 1 {
+			continue
+		}
+
+		count++
+		tsk.SetExpired()
+		TasksStateTotal.WithLabelValues(tsk.State, tsk.Type).Inc()
+
+		if err = t.UpdateDependents(ctx, tsk); err != nil {
+			return count, err
+		}
+	}
+
+	logger.WithFields(log.Fields{"count": count, "duration": time.Since(now) / time.Microsecond}).Debug("ExpireTasks")
+
+	return count, nil
+}
+
+func (t *TaskRepository) DeleteTasksBefore(ctx context.Context, state string, before time.Time) (int, error) {
+	if ctx == nil {
+		return 0, errors.New("context is missing")
+	}
+	if state != task.TaskStateCompleted && state != task.TaskStateFailed && state != task.TaskStateExpired && state != task.TaskStateCanceled {
+		return 0, errors.New("state is invalid")
+	}
+	if before.IsZero() {
+		return 0, errors.New("before is missing")
+	}
+
+	now := time.Now()
+	logger := log.LoggerFromContext(ctx).WithFields(log.Fields{"state": state, "before": before})
+
+	selector := bson.M{
+		"state":        state,
+		"modifiedTime": bson.M{"$lt": before},
+	}
+	changeInfo, err := t.DeleteMany(ctx, selector)
+	logger.WithFields(log.Fields{"changeInfo": changeInfo, "duration": time.Since(now) / time.Microsecond}).WithError(err).Debug("DeleteTasksBefore")
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to delete tasks")
+	}
+
+	return int(changeInfo.DeletedCount), nil
+}
+
+func deadLetterSelector(typ *string, code *string) bson.M {
+	selector := bson.M{
+		"state": task.TaskStateFailed,
+	}
+	if typ != nil {
+		selector["type"] = *typ
+	}
+	if code != nil {
+		selector["error.code"] = *code
+	}
+	return selector
+}
+
+func pendingSelector(now time.Time) bson.M {
+	return bson.M{
 		"state": task.TaskStatePending,
 		"$and": []bson.M{
 			{
@@ -404,7 +978,16 @@ func (t *TaskRepository) IteratePending(ctx context.Context) (*mongo.Cursor, err
 			},
 		},
 	}
+}
 
-	opts := options.Find().SetSort(bson.M{"priority": -1})
-	return t.Find(ctx, selector, opts)
+func uniqueStrings(values []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
 }
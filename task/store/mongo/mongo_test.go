@@ -112,6 +112,15 @@ var _ = Describe("Mongo", func() {
 						"Key":        Equal(storeStructuredMongoTest.MakeKeySlice("state")),
 						"Background": Equal(true),
 					}),
+					MatchFields(IgnoreExtras, Fields{
+						"Key":        Equal(storeStructuredMongoTest.MakeKeySlice("state", "modifiedTime")),
+						"Background": Equal(true),
+					}),
+					MatchFields(IgnoreExtras, Fields{
+						"Key":        Equal(storeStructuredMongoTest.MakeKeySlice("dependencies")),
+						"Background": Equal(true),
+						"Sparse":     Equal(true),
+					}),
 				))
 			})
 		})
@@ -279,5 +288,53 @@ var _ = Describe("Mongo", func() {
 				})
 			})
 		})
+
+		Context("with a new maintenance window repository", func() {
+			var ctx context.Context
+			var maintenanceWindowRepository taskStore.MaintenanceWindowRepository
+
+			BeforeEach(func() {
+				maintenanceWindowRepository = str.NewMaintenanceWindowRepository()
+				Expect(maintenanceWindowRepository).ToNot(BeNil())
+				ctx = log.NewContextWithLogger(context.Background(), logger)
+			})
+
+			It("creates, lists, and deletes maintenance windows", func() {
+				now := time.Now()
+				expired, err := maintenanceWindowRepository.CreateMaintenanceWindow(ctx, &task.MaintenanceWindowCreate{
+					Provider:  pointer.FromString("test"),
+					StartTime: pointer.FromTime(now.Add(-time.Hour)),
+					EndTime:   pointer.FromTime(now.Add(time.Second)),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				active, err := maintenanceWindowRepository.CreateMaintenanceWindow(ctx, &task.MaintenanceWindowCreate{
+					Provider:  pointer.FromString("test"),
+					StartTime: pointer.FromTime(now.Add(-time.Minute)),
+					EndTime:   pointer.FromTime(now.Add(time.Hour)),
+					Reason:    pointer.FromString("partner outage"),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				_, err = maintenanceWindowRepository.CreateMaintenanceWindow(ctx, &task.MaintenanceWindowCreate{
+					Provider:  pointer.FromString("other"),
+					StartTime: pointer.FromTime(now),
+					EndTime:   pointer.FromTime(now.Add(time.Hour)),
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = str.GetCollection("maintenanceWindows").UpdateOne(ctx, bson.M{"id": expired.ID}, bson.M{"$set": bson.M{"endTime": now.Add(-time.Minute)}})
+				Expect(err).ToNot(HaveOccurred())
+
+				maintenanceWindows, err := maintenanceWindowRepository.ListMaintenanceWindows(ctx, &task.MaintenanceWindowFilter{Provider: pointer.FromString("test"), Expired: pointer.FromBool(false)}, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(maintenanceWindows).To(HaveLen(1))
+				Expect(maintenanceWindows[0].ID).To(Equal(active.ID))
+
+				Expect(maintenanceWindowRepository.DeleteMaintenanceWindow(ctx, active.ID)).To(Succeed())
+				maintenanceWindows, err = maintenanceWindowRepository.ListMaintenanceWindows(ctx, &task.MaintenanceWindowFilter{Provider: pointer.FromString("test")}, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(maintenanceWindows).To(HaveLen(1))
+				Expect(maintenanceWindows[0].ID).To(Equal(expired.ID))
+			})
+		})
 	})
 })
@@ -2,19 +2,35 @@ package store
 
 import (
 	"context"
-
-	"go.mongodb.org/mongo-driver/mongo"
+	"time"
 
 	"github.com/tidepool-org/platform/task"
 )
 
 type Store interface {
 	NewTaskRepository() TaskRepository
+	NewMaintenanceWindowRepository() MaintenanceWindowRepository
 }
 
 type TaskRepository interface {
 	task.TaskAccessor
+	task.DeadLetterAccessor
 
 	UpdateFromState(ctx context.Context, tsk *task.Task, state string) (*task.Task, error)
-	IteratePending(ctx context.Context) (*mongo.Cursor, error)
+	UpdateProgress(ctx context.Context, tsk *task.Task) error
+	UpdateDependents(ctx context.Context, tsk *task.Task) error
+	IteratePending(ctx context.Context) (TaskIterator, error)
+	CountPending(ctx context.Context) (map[string]int, error)
+	ExpireTasks(ctx context.Context) (int, error)
+	DeleteTasksBefore(ctx context.Context, state string, before time.Time) (int, error)
+}
+
+// TaskIterator iterates over tasks in order, decoding the current task on each iteration
+type TaskIterator interface {
+	Next(ctx context.Context) bool
+	Decode(val interface{}) error
+}
+
+type MaintenanceWindowRepository interface {
+	task.MaintenanceWindowAccessor
 }
@@ -5,8 +5,10 @@ import (
 )
 
 type Store struct {
-	NewTaskRepositoryInvocations int
-	NewTaskRepositoryOutputs     []store.TaskRepository
+	NewTaskRepositoryInvocations              int
+	NewTaskRepositoryOutputs                  []store.TaskRepository
+	NewMaintenanceWindowRepositoryInvocations int
+	NewMaintenanceWindowRepositoryOutputs     []store.MaintenanceWindowRepository
 }
 
 func NewStore() *Store {
@@ -25,6 +27,18 @@ func (s *Store) NewTaskRepository() store.TaskRepository {
 	return output
 }
 
+func (s *Store) NewMaintenanceWindowRepository() store.MaintenanceWindowRepository {
+	s.NewMaintenanceWindowRepositoryInvocations++
+
+	if len(s.NewMaintenanceWindowRepositoryOutputs) == 0 {
+		panic("Unexpected invocation of NewMaintenanceWindowRepository on Store")
+	}
+
+	output := s.NewMaintenanceWindowRepositoryOutputs[0]
+	s.NewMaintenanceWindowRepositoryOutputs = s.NewMaintenanceWindowRepositoryOutputs[1:]
+	return output
+}
+
 func (s *Store) UnusedOutputsCount() int {
-	return len(s.NewTaskRepositoryOutputs)
+	return len(s.NewTaskRepositoryOutputs) + len(s.NewMaintenanceWindowRepositoryOutputs)
 }
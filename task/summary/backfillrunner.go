@@ -10,6 +10,7 @@ import (
 	dataClient "github.com/tidepool-org/platform/data/client"
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/log"
+	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/structure"
 	structureValidator "github.com/tidepool-org/platform/structure/validator"
 	"github.com/tidepool-org/platform/task"
@@ -119,12 +120,21 @@ func (t *BackfillTaskRunner) Run(ctx context.Context) error {
 
 	t.logger.Debug("Starting User Summary Creation")
 
+	t.task.SetProgress(pointer.FromFloat64(0), pointer.FromString("backfill"), nil)
+	if err := task.ReportProgress(t.context, t.task); err != nil {
+		t.logger.WithError(err).Warn("Unable to report progress")
+	}
+
 	count, err := t.dataClient.BackfillSummaries(t.context)
 	if err != nil {
+		t.task.AppendLog(log.ErrorLevel, "Unable to backfill summaries", log.Fields{"error": err.Error()})
 		return err
 	}
 
 	t.logger.Info(fmt.Sprintf("Backfilled %d summaries", count))
 
+	t.task.SetProgress(pointer.FromFloat64(task.ProgressPercentMaximum), pointer.FromString("backfill"), map[string]int{"summaries": count})
+	t.task.AppendLog(log.InfoLevel, "Backfilled summaries", log.Fields{"count": count})
+
 	return nil
 }
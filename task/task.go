@@ -8,6 +8,7 @@ import (
 
 	"github.com/tidepool-org/platform/errors"
 	"github.com/tidepool-org/platform/id"
+	"github.com/tidepool-org/platform/log"
 	"github.com/tidepool-org/platform/page"
 	"github.com/tidepool-org/platform/pointer"
 	"github.com/tidepool-org/platform/request"
@@ -17,6 +18,8 @@ import (
 
 type Client interface {
 	TaskAccessor
+	DeadLetterAccessor
+	MaintenanceWindowAccessor
 }
 
 type TaskAccessor interface {
@@ -25,21 +28,40 @@ type TaskAccessor interface {
 	GetTask(ctx context.Context, id string) (*Task, error)
 	UpdateTask(ctx context.Context, id string, update *TaskUpdate) (*Task, error)
 	DeleteTask(ctx context.Context, id string) error
+	GetTaskWorkflow(ctx context.Context, id string) (*TaskWorkflow, error)
 }
 
 const (
+	TaskStateWaiting   = "waiting"
 	TaskStatePending   = "pending"
 	TaskStateRunning   = "running"
 	TaskStateFailed    = "failed"
 	TaskStateCompleted = "completed"
+	TaskStateCanceled  = "canceled"
+	TaskStateExpired   = "expired"
+
+	TaskDependenciesLengthMaximum = 100
+
+	TaskDependencyFailurePolicyCancel   = "cancel"
+	TaskDependencyFailurePolicyContinue = "continue"
 )
 
 func TaskStates() []string {
 	return []string{
+		TaskStateWaiting,
 		TaskStatePending,
 		TaskStateRunning,
 		TaskStateFailed,
 		TaskStateCompleted,
+		TaskStateCanceled,
+		TaskStateExpired,
+	}
+}
+
+func TaskDependencyFailurePolicies() []string {
+	return []string{
+		TaskDependencyFailurePolicyCancel,
+		TaskDependencyFailurePolicyContinue,
 	}
 }
 
@@ -47,6 +69,7 @@ type TaskFilter struct {
 	Name  *string `json:"name,omitempty"`
 	Type  *string `json:"type,omitempty"`
 	State *string `json:"state,omitempty"`
+	Phase *string `json:"phase,omitempty"`
 }
 
 func NewTaskFilter() *TaskFilter {
@@ -57,12 +80,14 @@ func (t *TaskFilter) Parse(parser structure.ObjectParser) {
 	t.Name = parser.String("name")
 	t.Type = parser.String("type")
 	t.State = parser.String("state")
+	t.Phase = parser.String("phase")
 }
 
 func (t *TaskFilter) Validate(validator structure.Validator) {
 	validator.String("name", t.Name).NotEmpty()
 	validator.String("type", t.Type).NotEmpty()
 	validator.String("state", t.State).OneOf(TaskStates()...)
+	validator.String("phase", t.Phase).NotEmpty()
 }
 
 func (t *TaskFilter) MutateRequest(req *http.Request) error {
@@ -76,16 +101,21 @@ func (t *TaskFilter) MutateRequest(req *http.Request) error {
 	if t.State != nil {
 		parameters["state"] = *t.State
 	}
+	if t.Phase != nil {
+		parameters["phase"] = *t.Phase
+	}
 	return request.NewParametersMutator(parameters).MutateRequest(req)
 }
 
 type TaskCreate struct {
-	Name           *string                `json:"name,omitempty"`
-	Type           string                 `json:"type,omitempty"`
-	Priority       int                    `json:"priority,omitempty"`
-	Data           map[string]interface{} `json:"data,omitempty"`
-	AvailableTime  *time.Time             `json:"availableTime,omitempty"`
-	ExpirationTime *time.Time             `json:"expirationTime,omitempty"`
+	Name                    *string                `json:"name,omitempty"`
+	Type                    string                 `json:"type,omitempty"`
+	Priority                int                    `json:"priority,omitempty"`
+	Data                    map[string]interface{} `json:"data,omitempty"`
+	AvailableTime           *time.Time             `json:"availableTime,omitempty"`
+	ExpirationTime          *time.Time             `json:"expirationTime,omitempty"`
+	Dependencies            *[]string              `json:"dependencies,omitempty"`
+	DependencyFailurePolicy *string                `json:"dependencyFailurePolicy,omitempty"`
 }
 
 func NewTaskCreate() *TaskCreate {
@@ -105,6 +135,8 @@ func (t *TaskCreate) Parse(parser structure.ObjectParser) {
 	}
 	t.AvailableTime = parser.Time("availableTime", time.RFC3339Nano)
 	t.ExpirationTime = parser.Time("expirationTime", time.RFC3339Nano)
+	t.Dependencies = parser.StringArray("dependencies")
+	t.DependencyFailurePolicy = parser.String("dependencyFailurePolicy")
 }
 
 func (t *TaskCreate) Validate(validator structure.Validator) {
@@ -115,6 +147,13 @@ func (t *TaskCreate) Validate(validator structure.Validator) {
 	if t.AvailableTime != nil {
 		expirationTimeValidator.After(*t.AvailableTime)
 	}
+	validator.StringArray("dependencies", t.Dependencies).NotEmpty().LengthLessThanOrEqualTo(TaskDependenciesLengthMaximum).EachUsing(IDValidator)
+	dependencyFailurePolicyValidator := validator.String("dependencyFailurePolicy", t.DependencyFailurePolicy)
+	if t.Dependencies != nil {
+		dependencyFailurePolicyValidator.OneOf(TaskDependencyFailurePolicies()...)
+	} else {
+		dependencyFailurePolicyValidator.NotExists()
+	}
 }
 
 type TaskUpdate struct {
@@ -175,19 +214,23 @@ func ErrorValueStringAsIDNotValid(value string) error {
 var idExpression = regexp.MustCompile("^[0-9a-f]{32}$")
 
 type Task struct {
-	ID             string                 `json:"id,omitempty" bson:"id,omitempty"`
-	Name           *string                `json:"name,omitempty" bson:"name,omitempty"`
-	Type           string                 `json:"type,omitempty" bson:"type,omitempty"`
-	Priority       int                    `json:"priority,omitempty" bson:"priority,omitempty"`
-	Data           map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"`
-	AvailableTime  *time.Time             `json:"availableTime,omitempty" bson:"availableTime,omitempty"`
-	ExpirationTime *time.Time             `json:"expirationTime,omitempty" bson:"expirationTime,omitempty"`
-	State          string                 `json:"state,omitempty" bson:"state,omitempty"`
-	Error          *errors.Serializable   `json:"error,omitempty" bson:"error,omitempty"`
-	RunTime        *time.Time             `json:"runTime,omitempty" bson:"runTime,omitempty"`
-	Duration       *float64               `json:"duration,omitempty" bson:"duration,omitempty"`
-	CreatedTime    time.Time              `json:"createdTime,omitempty" bson:"createdTime,omitempty"`
-	ModifiedTime   *time.Time             `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
+	ID                      string                 `json:"id,omitempty" bson:"id,omitempty"`
+	Name                    *string                `json:"name,omitempty" bson:"name,omitempty"`
+	Type                    string                 `json:"type,omitempty" bson:"type,omitempty"`
+	Priority                int                    `json:"priority,omitempty" bson:"priority,omitempty"`
+	Data                    map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"`
+	AvailableTime           *time.Time             `json:"availableTime,omitempty" bson:"availableTime,omitempty"`
+	ExpirationTime          *time.Time             `json:"expirationTime,omitempty" bson:"expirationTime,omitempty"`
+	State                   string                 `json:"state,omitempty" bson:"state,omitempty"`
+	Error                   *errors.Serializable   `json:"error,omitempty" bson:"error,omitempty"`
+	RunTime                 *time.Time             `json:"runTime,omitempty" bson:"runTime,omitempty"`
+	Duration                *float64               `json:"duration,omitempty" bson:"duration,omitempty"`
+	Progress                *Progress              `json:"progress,omitempty" bson:"progress,omitempty"`
+	Log                     LogEntries             `json:"log,omitempty" bson:"log,omitempty"`
+	Dependencies            []string               `json:"dependencies,omitempty" bson:"dependencies,omitempty"`
+	DependencyFailurePolicy *string                `json:"dependencyFailurePolicy,omitempty" bson:"dependencyFailurePolicy,omitempty"`
+	CreatedTime             time.Time              `json:"createdTime,omitempty" bson:"createdTime,omitempty"`
+	ModifiedTime            *time.Time             `json:"modifiedTime,omitempty" bson:"modifiedTime,omitempty"`
 }
 
 func NewTask(create *TaskCreate) (*Task, error) {
@@ -197,7 +240,7 @@ func NewTask(create *TaskCreate) (*Task, error) {
 		return nil, errors.Wrap(err, "create is invalid")
 	}
 
-	return &Task{
+	tsk := &Task{
 		ID:             NewID(),
 		Name:           create.Name,
 		Type:           create.Type,
@@ -207,7 +250,16 @@ func NewTask(create *TaskCreate) (*Task, error) {
 		ExpirationTime: create.ExpirationTime,
 		State:          TaskStatePending,
 		CreatedTime:    time.Now(),
-	}, nil
+	}
+	if create.Dependencies != nil {
+		tsk.Dependencies = *create.Dependencies
+		tsk.DependencyFailurePolicy = create.DependencyFailurePolicy
+		if tsk.DependencyFailurePolicy == nil {
+			tsk.DependencyFailurePolicy = pointer.FromString(TaskDependencyFailurePolicyCancel)
+		}
+		tsk.State = TaskStateWaiting
+	}
+	return tsk, nil
 }
 
 func (t *Task) Parse(parser structure.ObjectParser) {
@@ -235,6 +287,14 @@ func (t *Task) Parse(parser structure.ObjectParser) {
 	}
 	t.RunTime = parser.Time("runTime", time.RFC3339Nano)
 	t.Duration = parser.Float64("duration")
+	t.Progress = ParseProgress(parser.WithReferenceObjectParser("progress"))
+	if ptr := ParseLogEntries(parser.WithReferenceArrayParser("log")); ptr != nil {
+		t.Log = *ptr
+	}
+	if ptr := parser.StringArray("dependencies"); ptr != nil {
+		t.Dependencies = *ptr
+	}
+	t.DependencyFailurePolicy = parser.String("dependencyFailurePolicy")
 	if ptr := parser.Time("createdTime", time.RFC3339Nano); ptr != nil {
 		t.CreatedTime = *ptr
 	}
@@ -256,6 +316,18 @@ func (t *Task) Validate(validator structure.Validator) {
 	}
 	validator.Time("runTime", t.RunTime).After(t.CreatedTime).BeforeNow(time.Second)
 	validator.Float64("duration", t.Duration).GreaterThanOrEqualTo(0)
+	if t.Progress != nil {
+		t.Progress.Validate(validator.WithReference("progress"))
+	}
+	if t.Log != nil {
+		t.Log.Validate(validator.WithReference("log"))
+	}
+	if t.Dependencies != nil {
+		validator.StringArray("dependencies", &t.Dependencies).NotEmpty().LengthLessThanOrEqualTo(TaskDependenciesLengthMaximum).EachUsing(IDValidator)
+		validator.String("dependencyFailurePolicy", t.DependencyFailurePolicy).Exists().OneOf(TaskDependencyFailurePolicies()...)
+	} else {
+		validator.String("dependencyFailurePolicy", t.DependencyFailurePolicy).NotExists()
+	}
 	validator.Time("createdTime", &t.CreatedTime).NotZero().BeforeNow(time.Second)
 	validator.Time("modifiedTime", t.ModifiedTime).After(t.CreatedTime).BeforeNow(time.Second)
 }
@@ -290,6 +362,44 @@ func (t *Task) SetFailed() {
 	t.State = TaskStateFailed
 }
 
+func (t *Task) IsWaiting() bool {
+	return t.State == TaskStateWaiting
+}
+
+func (t *Task) IsCanceled() bool {
+	return t.State == TaskStateCanceled
+}
+
+func (t *Task) SetCanceled() {
+	t.State = TaskStateCanceled
+}
+
+func (t *Task) IsExpired() bool {
+	return t.State == TaskStateExpired
+}
+
+func (t *Task) SetExpired() {
+	t.State = TaskStateExpired
+}
+
+func (t *Task) IsExpiredAt(now time.Time) bool {
+	return t.ExpirationTime != nil && !now.Before(*t.ExpirationTime)
+}
+
+func (t *Task) HasDependencies() bool {
+	return len(t.Dependencies) > 0
+}
+
+func (t *Task) CancelOnDependencyFailure() bool {
+	return t.DependencyFailurePolicy == nil || *t.DependencyFailurePolicy != TaskDependencyFailurePolicyContinue
+}
+
+// IsRepeating returns true if the task ran without error and is pending to run again. A recurring task never
+// completes, so each successful run resolves its dependents instead.
+func (t *Task) IsRepeating() bool {
+	return t.State == TaskStatePending && t.RunTime != nil && !t.HasError()
+}
+
 func (t *Task) IsCompleted() bool {
 	return t.State == TaskStateCompleted
 }
@@ -315,6 +425,24 @@ func (t *Task) ClearError() {
 	t.Error = nil
 }
 
+func (t *Task) SetProgress(percent *float64, phase *string, counters map[string]int) {
+	t.Progress = &Progress{
+		Percent:     percent,
+		Phase:       phase,
+		Counters:    counters,
+		UpdatedTime: pointer.FromTime(time.Now()),
+	}
+}
+
+func (t *Task) AppendLog(level log.Level, message string, fields log.Fields) {
+	t.Log.Append(&LogEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  fields,
+	})
+}
+
 type Tasks []*Task
 
 func (t Tasks) Sanitize(details request.Details) error {
@@ -2,14 +2,20 @@ package test
 
 type Client struct {
 	*TaskAccessor
+	*DeadLetterAccessor
+	*MaintenanceWindowAccessor
 }
 
 func NewClient() *Client {
 	return &Client{
-		TaskAccessor: NewTaskAccessor(),
+		TaskAccessor:              NewTaskAccessor(),
+		DeadLetterAccessor:        NewDeadLetterAccessor(),
+		MaintenanceWindowAccessor: NewMaintenanceWindowAccessor(),
 	}
 }
 
 func (c *Client) Expectations() {
 	c.TaskAccessor.Expectations()
+	c.DeadLetterAccessor.Expectations()
+	c.MaintenanceWindowAccessor.Expectations()
 }
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/task"
+)
+
+type ListDeadLettersInput struct {
+	Context    context.Context
+	Filter     *task.DeadLetterFilter
+	Pagination *page.Pagination
+}
+
+type ListDeadLettersOutput struct {
+	Tasks task.Tasks
+	Error error
+}
+
+type SummarizeDeadLettersInput struct {
+	Context context.Context
+	Filter  *task.DeadLetterFilter
+}
+
+type SummarizeDeadLettersOutput struct {
+	Summaries task.DeadLetterSummaries
+	Error     error
+}
+
+type RequeueDeadLettersInput struct {
+	Context context.Context
+	Requeue *task.DeadLetterRequeue
+}
+
+type DeleteDeadLettersInput struct {
+	Context context.Context
+	Filter  *task.DeadLetterFilter
+}
+
+type DeadLetterResultOutput struct {
+	Result *task.DeadLetterResult
+	Error  error
+}
+
+type DeadLetterAccessor struct {
+	ListDeadLettersInvocations      int
+	ListDeadLettersInputs           []ListDeadLettersInput
+	ListDeadLettersOutputs          []ListDeadLettersOutput
+	SummarizeDeadLettersInvocations int
+	SummarizeDeadLettersInputs      []SummarizeDeadLettersInput
+	SummarizeDeadLettersOutputs     []SummarizeDeadLettersOutput
+	RequeueDeadLettersInvocations   int
+	RequeueDeadLettersInputs        []RequeueDeadLettersInput
+	RequeueDeadLettersOutputs       []DeadLetterResultOutput
+	DeleteDeadLettersInvocations    int
+	DeleteDeadLettersInputs         []DeleteDeadLettersInput
+	DeleteDeadLettersOutputs        []DeadLetterResultOutput
+}
+
+func NewDeadLetterAccessor() *DeadLetterAccessor {
+	return &DeadLetterAccessor{}
+}
+
+func (d *DeadLetterAccessor) ListDeadLetters(ctx context.Context, filter *task.DeadLetterFilter, pagination *page.Pagination) (task.Tasks, error) {
+	d.ListDeadLettersInvocations++
+
+	d.ListDeadLettersInputs = append(d.ListDeadLettersInputs, ListDeadLettersInput{Context: ctx, Filter: filter, Pagination: pagination})
+
+	gomega.Expect(d.ListDeadLettersOutputs).ToNot(gomega.BeEmpty())
+
+	output := d.ListDeadLettersOutputs[0]
+	d.ListDeadLettersOutputs = d.ListDeadLettersOutputs[1:]
+	return output.Tasks, output.Error
+}
+
+func (d *DeadLetterAccessor) SummarizeDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (task.DeadLetterSummaries, error) {
+	d.SummarizeDeadLettersInvocations++
+
+	d.SummarizeDeadLettersInputs = append(d.SummarizeDeadLettersInputs, SummarizeDeadLettersInput{Context: ctx, Filter: filter})
+
+	gomega.Expect(d.SummarizeDeadLettersOutputs).ToNot(gomega.BeEmpty())
+
+	output := d.SummarizeDeadLettersOutputs[0]
+	d.SummarizeDeadLettersOutputs = d.SummarizeDeadLettersOutputs[1:]
+	return output.Summaries, output.Error
+}
+
+func (d *DeadLetterAccessor) RequeueDeadLetters(ctx context.Context, requeue *task.DeadLetterRequeue) (*task.DeadLetterResult, error) {
+	d.RequeueDeadLettersInvocations++
+
+	d.RequeueDeadLettersInputs = append(d.RequeueDeadLettersInputs, RequeueDeadLettersInput{Context: ctx, Requeue: requeue})
+
+	gomega.Expect(d.RequeueDeadLettersOutputs).ToNot(gomega.BeEmpty())
+
+	output := d.RequeueDeadLettersOutputs[0]
+	d.RequeueDeadLettersOutputs = d.RequeueDeadLettersOutputs[1:]
+	return output.Result, output.Error
+}
+
+func (d *DeadLetterAccessor) DeleteDeadLetters(ctx context.Context, filter *task.DeadLetterFilter) (*task.DeadLetterResult, error) {
+	d.DeleteDeadLettersInvocations++
+
+	d.DeleteDeadLettersInputs = append(d.DeleteDeadLettersInputs, DeleteDeadLettersInput{Context: ctx, Filter: filter})
+
+	gomega.Expect(d.DeleteDeadLettersOutputs).ToNot(gomega.BeEmpty())
+
+	output := d.DeleteDeadLettersOutputs[0]
+	d.DeleteDeadLettersOutputs = d.DeleteDeadLettersOutputs[1:]
+	return output.Result, output.Error
+}
+
+func (d *DeadLetterAccessor) Expectations() {
+	gomega.Expect(d.ListDeadLettersOutputs).To(gomega.BeEmpty())
+	gomega.Expect(d.SummarizeDeadLettersOutputs).To(gomega.BeEmpty())
+	gomega.Expect(d.RequeueDeadLettersOutputs).To(gomega.BeEmpty())
+	gomega.Expect(d.DeleteDeadLettersOutputs).To(gomega.BeEmpty())
+}
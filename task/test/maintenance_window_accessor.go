@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+
+	"github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/page"
+	"github.com/tidepool-org/platform/task"
+)
+
+type ListMaintenanceWindowsInput struct {
+	Context    context.Context
+	Filter     *task.MaintenanceWindowFilter
+	Pagination *page.Pagination
+}
+
+type ListMaintenanceWindowsOutput struct {
+	MaintenanceWindows task.MaintenanceWindows
+	Error              error
+}
+
+type CreateMaintenanceWindowInput struct {
+	Context context.Context
+	Create  *task.MaintenanceWindowCreate
+}
+
+type CreateMaintenanceWindowOutput struct {
+	MaintenanceWindow *task.MaintenanceWindow
+	Error             error
+}
+
+type DeleteMaintenanceWindowInput struct {
+	Context context.Context
+	ID      string
+}
+
+type MaintenanceWindowAccessor struct {
+	ListMaintenanceWindowsInvocations  int
+	ListMaintenanceWindowsInputs       []ListMaintenanceWindowsInput
+	ListMaintenanceWindowsOutputs      []ListMaintenanceWindowsOutput
+	CreateMaintenanceWindowInvocations int
+	CreateMaintenanceWindowInputs      []CreateMaintenanceWindowInput
+	CreateMaintenanceWindowOutputs     []CreateMaintenanceWindowOutput
+	DeleteMaintenanceWindowInvocations int
+	DeleteMaintenanceWindowInputs      []DeleteMaintenanceWindowInput
+	DeleteMaintenanceWindowOutputs     []error
+}
+
+func NewMaintenanceWindowAccessor() *MaintenanceWindowAccessor {
+	return &MaintenanceWindowAccessor{}
+}
+
+func (m *MaintenanceWindowAccessor) ListMaintenanceWindows(ctx context.Context, filter *task.MaintenanceWindowFilter, pagination *page.Pagination) (task.MaintenanceWindows, error) {
+	m.ListMaintenanceWindowsInvocations++
+
+	m.ListMaintenanceWindowsInputs = append(m.ListMaintenanceWindowsInputs, ListMaintenanceWindowsInput{Context: ctx, Filter: filter, Pagination: pagination})
+
+	gomega.Expect(m.ListMaintenanceWindowsOutputs).ToNot(gomega.BeEmpty())
+
+	output := m.ListMaintenanceWindowsOutputs[0]
+	m.ListMaintenanceWindowsOutputs = m.ListMaintenanceWindowsOutputs[1:]
+	return output.MaintenanceWindows, output.Error
+}
+
+func (m *MaintenanceWindowAccessor) CreateMaintenanceWindow(ctx context.Context, create *task.MaintenanceWindowCreate) (*task.MaintenanceWindow, error) {
+	m.CreateMaintenanceWindowInvocations++
+
+	m.CreateMaintenanceWindowInputs = append(m.CreateMaintenanceWindowInputs, CreateMaintenanceWindowInput{Context: ctx, Create: create})
+
+	gomega.Expect(m.CreateMaintenanceWindowOutputs).ToNot(gomega.BeEmpty())
+
+	output := m.CreateMaintenanceWindowOutputs[0]
+	m.CreateMaintenanceWindowOutputs = m.CreateMaintenanceWindowOutputs[1:]
+	return output.MaintenanceWindow, output.Error
+}
+
+func (m *MaintenanceWindowAccessor) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	m.DeleteMaintenanceWindowInvocations++
+
+	m.DeleteMaintenanceWindowInputs = append(m.DeleteMaintenanceWindowInputs, DeleteMaintenanceWindowInput{Context: ctx, ID: id})
+
+	gomega.Expect(m.DeleteMaintenanceWindowOutputs).ToNot(gomega.BeEmpty())
+
+	output := m.DeleteMaintenanceWindowOutputs[0]
+	m.DeleteMaintenanceWindowOutputs = m.DeleteMaintenanceWindowOutputs[1:]
+	return output
+}
+
+func (m *MaintenanceWindowAccessor) Expectations() {
+	gomega.Expect(m.ListMaintenanceWindowsOutputs).To(gomega.BeEmpty())
+	gomega.Expect(m.CreateMaintenanceWindowOutputs).To(gomega.BeEmpty())
+	gomega.Expect(m.DeleteMaintenanceWindowOutputs).To(gomega.BeEmpty())
+}
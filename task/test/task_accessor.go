@@ -56,22 +56,35 @@ type DeleteTaskInput struct {
 	ID      string
 }
 
+type GetTaskWorkflowInput struct {
+	Context context.Context
+	ID      string
+}
+
+type GetTaskWorkflowOutput struct {
+	Workflow *task.TaskWorkflow
+	Error    error
+}
+
 type TaskAccessor struct {
-	ListTasksInvocations  int
-	ListTasksInputs       []ListTasksInput
-	ListTasksOutputs      []ListTasksOutput
-	CreateTaskInvocations int
-	CreateTaskInputs      []CreateTaskInput
-	CreateTaskOutputs     []CreateTaskOutput
-	GetTaskInvocations    int
-	GetTaskInputs         []GetTaskInput
-	GetTaskOutputs        []GetTaskOutput
-	UpdateTaskInvocations int
-	UpdateTaskInputs      []UpdateTaskInput
-	UpdateTaskOutputs     []UpdateTaskOutput
-	DeleteTaskInvocations int
-	DeleteTaskInputs      []DeleteTaskInput
-	DeleteTaskOutputs     []error
+	ListTasksInvocations       int
+	ListTasksInputs            []ListTasksInput
+	ListTasksOutputs           []ListTasksOutput
+	CreateTaskInvocations      int
+	CreateTaskInputs           []CreateTaskInput
+	CreateTaskOutputs          []CreateTaskOutput
+	GetTaskInvocations         int
+	GetTaskInputs              []GetTaskInput
+	GetTaskOutputs             []GetTaskOutput
+	UpdateTaskInvocations      int
+	UpdateTaskInputs           []UpdateTaskInput
+	UpdateTaskOutputs          []UpdateTaskOutput
+	DeleteTaskInvocations      int
+	DeleteTaskInputs           []DeleteTaskInput
+	DeleteTaskOutputs          []error
+	GetTaskWorkflowInvocations int
+	GetTaskWorkflowInputs      []GetTaskWorkflowInput
+	GetTaskWorkflowOutputs     []GetTaskWorkflowOutput
 }
 
 func NewTaskAccessor() *TaskAccessor {
@@ -138,9 +151,22 @@ func (t *TaskAccessor) DeleteTask(ctx context.Context, id string) error {
 	return output
 }
 
+func (t *TaskAccessor) GetTaskWorkflow(ctx context.Context, id string) (*task.TaskWorkflow, error) {
+	t.GetTaskWorkflowInvocations++
+
+	t.GetTaskWorkflowInputs = append(t.GetTaskWorkflowInputs, GetTaskWorkflowInput{Context: ctx, ID: id})
+
+	gomega.Expect(t.GetTaskWorkflowOutputs).ToNot(gomega.BeEmpty())
+
+	output := t.GetTaskWorkflowOutputs[0]
+	t.GetTaskWorkflowOutputs = t.GetTaskWorkflowOutputs[1:]
+	return output.Workflow, output.Error
+}
+
 func (t *TaskAccessor) Expectations() {
 	gomega.Expect(t.ListTasksOutputs).To(gomega.BeEmpty())
 	gomega.Expect(t.CreateTaskOutputs).To(gomega.BeEmpty())
 	gomega.Expect(t.GetTaskOutputs).To(gomega.BeEmpty())
 	gomega.Expect(t.UpdateTaskOutputs).To(gomega.BeEmpty())
+	gomega.Expect(t.GetTaskWorkflowOutputs).To(gomega.BeEmpty())
 }
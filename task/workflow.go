@@ -0,0 +1,44 @@
+package task
+
+const TaskWorkflowDepthMaximum = 10
+
+type TaskWorkflow struct {
+	Task       *Task           `json:"task,omitempty"`
+	Dependents []*TaskWorkflow `json:"dependents,omitempty"`
+}
+
+// ResolveDependencies returns the state a waiting task should transition to based upon the
+// current states of its dependencies. A dependency that no longer exists is considered failed. A recurring dependency
+// is considered completed once it has run successfully after the waiting task was created.
+func ResolveDependencies(tsk *Task, dependencies Tasks) string {
+	if tsk == nil || !tsk.IsWaiting() {
+		return ""
+	}
+
+	dependenciesByID := map[string]*Task{}
+	for _, dependency := range dependencies {
+		if dependency != nil {
+			dependenciesByID[dependency.ID] = dependency
+		}
+	}
+
+	resolved := true
+	for _, id := range tsk.Dependencies {
+		dependency, ok := dependenciesByID[id]
+		switch {
+		case !ok, dependency.IsFailed(), dependency.IsCanceled(), dependency.IsExpired():
+			if tsk.CancelOnDependencyFailure() {
+				return TaskStateCanceled
+			}
+		case dependency.IsCompleted():
+		case dependency.IsRepeating() && dependency.RunTime.After(tsk.CreatedTime):
+		default:
+			resolved = false
+		}
+	}
+
+	if resolved {
+		return TaskStatePending
+	}
+	return TaskStateWaiting
+}
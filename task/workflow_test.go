@@ -0,0 +1,118 @@
+package task_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidepool-org/platform/errors"
+	"github.com/tidepool-org/platform/pointer"
+	structureValidator "github.com/tidepool-org/platform/structure/validator"
+	"github.com/tidepool-org/platform/task"
+)
+
+var _ = Describe("Workflow", func() {
+	Context("NewTask", func() {
+		It("returns a waiting task with the default failure policy when the create has dependencies", func() {
+			tsk, err := task.NewTask(&task.TaskCreate{Type: "test", Dependencies: pointer.FromStringArray([]string{task.NewID()})})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tsk.State).To(Equal(task.TaskStateWaiting))
+			Expect(tsk.DependencyFailurePolicy).To(Equal(pointer.FromString(task.TaskDependencyFailurePolicyCancel)))
+			Expect(structureValidator.New().Validate(tsk)).To(Succeed())
+		})
+
+		It("returns a pending task when the create does not have dependencies", func() {
+			tsk, err := task.NewTask(&task.TaskCreate{Type: "test"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tsk.State).To(Equal(task.TaskStatePending))
+			Expect(tsk.DependencyFailurePolicy).To(BeNil())
+		})
+
+		It("returns an error when a dependency is not a valid id", func() {
+			_, err := task.NewTask(&task.TaskCreate{Type: "test", Dependencies: pointer.FromStringArray([]string{"invalid"})})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the failure policy is specified without dependencies", func() {
+			_, err := task.NewTask(&task.TaskCreate{Type: "test", DependencyFailurePolicy: pointer.FromString(task.TaskDependencyFailurePolicyContinue)})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ResolveDependencies", func() {
+		var first *task.Task
+		var second *task.Task
+
+		BeforeEach(func() {
+			first = &task.Task{ID: task.NewID(), State: task.TaskStateCompleted}
+			second = &task.Task{ID: task.NewID(), State: task.TaskStateCompleted}
+		})
+
+		DescribeTable("returns the expected state when",
+			func(policy string, firstState string, secondState string, includeSecond bool, expectedState string) {
+				tsk := &task.Task{
+					ID:                      task.NewID(),
+					State:                   task.TaskStateWaiting,
+					Dependencies:            []string{first.ID, second.ID},
+					DependencyFailurePolicy: pointer.FromString(policy),
+				}
+				first.State = firstState
+				second.State = secondState
+				dependencies := task.Tasks{first}
+				if includeSecond {
+					dependencies = append(dependencies, second)
+				}
+				Expect(task.ResolveDependencies(tsk, dependencies)).To(Equal(expectedState))
+			},
+			Entry("all dependencies are completed", task.TaskDependencyFailurePolicyCancel, task.TaskStateCompleted, task.TaskStateCompleted, true, task.TaskStatePending),
+			Entry("a dependency is running", task.TaskDependencyFailurePolicyCancel, task.TaskStateCompleted, task.TaskStateRunning, true, task.TaskStateWaiting),
+			Entry("a dependency failed with cancel policy", task.TaskDependencyFailurePolicyCancel, task.TaskStateFailed, task.TaskStateRunning, true, task.TaskStateCanceled),
+			Entry("a dependency was canceled with cancel policy", task.TaskDependencyFailurePolicyCancel, task.TaskStateCanceled, task.TaskStateCompleted, true, task.TaskStateCanceled),
+			Entry("a dependency is missing with cancel policy", task.TaskDependencyFailurePolicyCancel, task.TaskStateCompleted, task.TaskStateCompleted, false, task.TaskStateCanceled),
+			Entry("a dependency failed with continue policy", task.TaskDependencyFailurePolicyContinue, task.TaskStateFailed, task.TaskStateCompleted, true, task.TaskStatePending),
+			Entry("a dependency failed with continue policy while another is pending", task.TaskDependencyFailurePolicyContinue, task.TaskStateFailed, task.TaskStatePending, true, task.TaskStateWaiting),
+		)
+
+		Context("with a recurring dependency", func() {
+			var tsk *task.Task
+
+			BeforeEach(func() {
+				tsk = &task.Task{
+					ID:                      task.NewID(),
+					State:                   task.TaskStateWaiting,
+					Dependencies:            []string{first.ID, second.ID},
+					DependencyFailurePolicy: pointer.FromString(task.TaskDependencyFailurePolicyCancel),
+					CreatedTime:             time.Now().Add(-time.Hour),
+				}
+				second.State = task.TaskStatePending
+				second.AvailableTime = pointer.FromTime(time.Now().Add(time.Hour))
+			})
+
+			It("returns waiting when the dependency has not yet run", func() {
+				Expect(task.ResolveDependencies(tsk, task.Tasks{first, second})).To(Equal(task.TaskStateWaiting))
+			})
+
+			It("returns waiting when the dependency last ran before the task was created", func() {
+				second.RunTime = pointer.FromTime(tsk.CreatedTime.Add(-time.Minute))
+				Expect(task.ResolveDependencies(tsk, task.Tasks{first, second})).To(Equal(task.TaskStateWaiting))
+			})
+
+			It("returns waiting when the dependency last ran with an error", func() {
+				second.RunTime = pointer.FromTime(tsk.CreatedTime.Add(time.Minute))
+				second.AppendError(errors.New("test error"))
+				Expect(task.ResolveDependencies(tsk, task.Tasks{first, second})).To(Equal(task.TaskStateWaiting))
+			})
+
+			It("returns pending when the dependency ran successfully after the task was created", func() {
+				second.RunTime = pointer.FromTime(tsk.CreatedTime.Add(time.Minute))
+				Expect(task.ResolveDependencies(tsk, task.Tasks{first, second})).To(Equal(task.TaskStatePending))
+			})
+		})
+
+		It("returns empty when the task is not waiting", func() {
+			Expect(task.ResolveDependencies(&task.Task{State: task.TaskStatePending}, nil)).To(BeEmpty())
+		})
+	})
+})
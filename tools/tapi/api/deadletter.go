@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type (
+	DeadLetterFilter struct {
+		Type *string
+		Code *string
+	}
+
+	DeadLetterRequeue struct {
+		Type           *string   `json:"type,omitempty"`
+		Code           *string   `json:"code,omitempty"`
+		IDs            *[]string `json:"ids,omitempty"`
+		SpreadDuration *int      `json:"spreadDuration,omitempty"`
+	}
+)
+
+func (a *API) ListDeadLetters(filter *DeadLetterFilter, pagination *Pagination) ([]interface{}, error) {
+	queryMap := deadLetterQueryMap(filter)
+	if pagination != nil {
+		if pagination.Page != nil {
+			queryMap["page"] = strconv.Itoa(*pagination.Page)
+		}
+		if pagination.Size != nil {
+			queryMap["size"] = strconv.Itoa(*pagination.Size)
+		}
+	}
+
+	return a.asArray(a.request("GET", a.addQuery(a.joinPaths("v1", "dead_letters"), queryMap),
+		requestFuncs{a.addSessionToken()},
+		responseFuncs{a.expectStatusCode(http.StatusOK)}))
+}
+
+func (a *API) SummarizeDeadLetters(filter *DeadLetterFilter) ([]interface{}, error) {
+	return a.asArray(a.request("GET", a.addQuery(a.joinPaths("v1", "dead_letters", "summary"), deadLetterQueryMap(filter)),
+		requestFuncs{a.addSessionToken()},
+		responseFuncs{a.expectStatusCode(http.StatusOK)}))
+}
+
+func (a *API) RequeueDeadLetters(requeue *DeadLetterRequeue) (map[string]interface{}, error) {
+	if requeue == nil {
+		return nil, errors.New("Requeue is missing")
+	}
+	if requeue.Type == nil && requeue.IDs == nil {
+		return nil, errors.New("Type or task ids must be specified")
+	}
+
+	return a.asStringMap(a.request("POST", a.joinPaths("v1", "dead_letters", "requeue"),
+		requestFuncs{a.addSessionToken(), a.addObjectBody(requeue)},
+		responseFuncs{a.expectStatusCode(http.StatusOK)}))
+}
+
+func (a *API) DeleteDeadLetters(filter *DeadLetterFilter) (map[string]interface{}, error) {
+	if filter == nil || filter.Type == nil {
+		return nil, errors.New("Type is missing")
+	}
+
+	return a.asStringMap(a.request("DELETE", a.addQuery(a.joinPaths("v1", "dead_letters"), deadLetterQueryMap(filter)),
+		requestFuncs{a.addSessionToken()},
+		responseFuncs{a.expectStatusCode(http.StatusOK)}))
+}
+
+func deadLetterQueryMap(filter *DeadLetterFilter) map[string]string {
+	queryMap := map[string]string{}
+	if filter != nil {
+		if filter.Type != nil {
+			queryMap["type"] = *filter.Type
+		}
+		if filter.Code != nil {
+			queryMap["code"] = *filter.Code
+		}
+	}
+	return queryMap
+}
@@ -38,6 +38,7 @@ func InitializeApplication() (*cli.App, error) {
 		AuthCommands(),
 		UserCommands(),
 		DataSetCommands(),
+		DeadLetterCommands(),
 		VersionCommands(versionReporter),
 	))
 	return app, nil
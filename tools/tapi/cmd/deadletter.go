@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"github.com/urfave/cli"
+
+	"github.com/tidepool-org/platform/tools/tapi/api"
+)
+
+const (
+	TaskTypeFlag       = "type"
+	ErrorCodeFlag      = "code"
+	TaskIDFlag         = "task-id"
+	SpreadDurationFlag = "spread"
+)
+
+func DeadLetterCommands() cli.Commands {
+	return cli.Commands{
+		{
+			Name:  "dead-letter",
+			Usage: "failed task management",
+			Subcommands: []cli.Command{
+				{
+					Name:  "list",
+					Usage: "list failed tasks",
+					Flags: CommandFlags(
+						cli.StringFlag{
+							Name:  TaskTypeFlag,
+							Usage: "list failed tasks of the specified task `TYPE`",
+						},
+						cli.StringFlag{
+							Name:  ErrorCodeFlag,
+							Usage: "list failed tasks with the specified error `CODE`",
+						},
+						cli.IntFlag{
+							Name:  PageFlag,
+							Usage: "pagination `PAGE`",
+						},
+						cli.IntFlag{
+							Name:  SizeFlag,
+							Usage: "pagination `SIZE`",
+						},
+					),
+					Before: ensureNoArgs,
+					Action: deadLetterList,
+				},
+				{
+					Name:  "summary",
+					Usage: "summarize failed tasks grouped by task type and error code",
+					Flags: CommandFlags(
+						cli.StringFlag{
+							Name:  TaskTypeFlag,
+							Usage: "summarize failed tasks of the specified task `TYPE`",
+						},
+						cli.StringFlag{
+							Name:  ErrorCodeFlag,
+							Usage: "summarize failed tasks with the specified error `CODE`",
+						},
+					),
+					Before: ensureNoArgs,
+					Action: deadLetterSummary,
+				},
+				{
+					Name:  "requeue",
+					Usage: "requeue failed tasks by task type and error code, or by task id",
+					Flags: CommandFlags(
+						cli.StringFlag{
+							Name:  TaskTypeFlag,
+							Usage: "requeue failed tasks of the specified task `TYPE`",
+						},
+						cli.StringFlag{
+							Name:  ErrorCodeFlag,
+							Usage: "requeue failed tasks with the specified error `CODE`",
+						},
+						cli.StringSliceFlag{
+							Name:  TaskIDFlag,
+							Usage: "requeue the failed task with the specified `TASKID` (may be repeated)",
+						},
+						cli.IntFlag{
+							Name:  SpreadDurationFlag,
+							Usage: "randomly spread the requeued tasks over `SECONDS`",
+						},
+					),
+					Before: ensureNoArgs,
+					Action: deadLetterRequeue,
+				},
+				{
+					Name:  "delete",
+					Usage: "delete failed tasks by task type and error code",
+					Flags: CommandFlags(
+						cli.StringFlag{
+							Name:  TaskTypeFlag,
+							Usage: "delete failed tasks of the specified task `TYPE`",
+						},
+						cli.StringFlag{
+							Name:  ErrorCodeFlag,
+							Usage: "delete failed tasks with the specified error `CODE`",
+						},
+					),
+					Before: ensureNoArgs,
+					Action: deadLetterDelete,
+				},
+			},
+		},
+	}
+}
+
+func deadLetterList(c *cli.Context) error {
+	var pagination *api.Pagination
+
+	if c.IsSet(PageFlag) {
+		if pagination == nil {
+			pagination = &api.Pagination{}
+		}
+		page := c.Int(PageFlag)
+		pagination.Page = &page
+	}
+	if c.IsSet(SizeFlag) {
+		if pagination == nil {
+			pagination = &api.Pagination{}
+		}
+		size := c.Int(SizeFlag)
+		pagination.Size = &size
+	}
+
+	tasks, err := API(c).ListDeadLetters(deadLetterFilter(c), pagination)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err = reportMessageWithJSON(c, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deadLetterSummary(c *cli.Context) error {
+	summaries, err := API(c).SummarizeDeadLetters(deadLetterFilter(c))
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		if err = reportMessageWithJSON(c, summary); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deadLetterRequeue(c *cli.Context) error {
+	requeue := &api.DeadLetterRequeue{}
+
+	if c.IsSet(TaskTypeFlag) {
+		typ := c.String(TaskTypeFlag)
+		requeue.Type = &typ
+	}
+	if c.IsSet(ErrorCodeFlag) {
+		code := c.String(ErrorCodeFlag)
+		requeue.Code = &code
+	}
+	if c.IsSet(TaskIDFlag) {
+		ids := c.StringSlice(TaskIDFlag)
+		requeue.IDs = &ids
+	}
+	if c.IsSet(SpreadDurationFlag) {
+		spreadDuration := c.Int(SpreadDurationFlag)
+		requeue.SpreadDuration = &spreadDuration
+	}
+
+	result, err := API(c).RequeueDeadLetters(requeue)
+	if err != nil {
+		return err
+	}
+
+	return reportMessageWithJSON(c, result)
+}
+
+func deadLetterDelete(c *cli.Context) error {
+	result, err := API(c).DeleteDeadLetters(deadLetterFilter(c))
+	if err != nil {
+		return err
+	}
+
+	return reportMessageWithJSON(c, result)
+}
+
+func deadLetterFilter(c *cli.Context) *api.DeadLetterFilter {
+	filter := &api.DeadLetterFilter{}
+	if c.IsSet(TaskTypeFlag) {
+		typ := c.String(TaskTypeFlag)
+		filter.Type = &typ
+	}
+	if c.IsSet(ErrorCodeFlag) {
+		code := c.String(ErrorCodeFlag)
+		filter.Code = &code
+	}
+	return filter
+}